@@ -543,11 +543,34 @@ func (hs *HTTPServer) registerRoutes() {
 			// POST influx line protocol.
 			liveRoute.Post("/push/:streamId", hs.LivePushGateway.Handle)
 
+			// POST Prometheus remote write and OTLP/HTTP metrics.
+			liveRoute.Post("/push/:streamId/remote-write", hs.LivePushGateway.HandlePrometheusRemoteWrite)
+			liveRoute.Post("/push/:streamId/otlp/v1/metrics", hs.LivePushGateway.HandleOTLPMetrics)
+
+			// POST to a channel processed by the Live pipeline.
+			liveRoute.Post("/pipeline/push/*", reqOrgAdmin, hs.LivePushGateway.HandlePipelinePush)
+
 			// List available streams and fields
 			liveRoute.Get("/list", routing.Wrap(hs.Live.HandleListHTTP))
 
 			// Some channels may have info
 			liveRoute.Get("/info/*", routing.Wrap(hs.Live.HandleInfoHTTP))
+
+			if hs.Cfg.LivePipelineEnabled {
+				// Try out channel rules on data without saving them.
+				liveRoute.Post("/pipeline-convert-test", reqOrgAdmin, routing.Wrap(hs.Live.HandlePipelineConvertTestHTTP))
+				liveRoute.Get("/pipeline-entities", reqOrgAdmin, routing.Wrap(hs.Live.HandlePipelineEntitiesListHTTP))
+
+				// Manage the channel rules and write configs of the Live pipeline.
+				liveRoute.Get("/channel-rules", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesListHTTP))
+				liveRoute.Post("/channel-rules", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesPostHTTP))
+				liveRoute.Put("/channel-rules", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesPutHTTP))
+				liveRoute.Delete("/channel-rules", reqOrgAdmin, routing.Wrap(hs.Live.HandleChannelRulesDeleteHTTP))
+				liveRoute.Get("/write-configs", reqOrgAdmin, routing.Wrap(hs.Live.HandleWriteConfigsListHTTP))
+				liveRoute.Post("/write-configs", reqOrgAdmin, routing.Wrap(hs.Live.HandleWriteConfigsPostHTTP))
+				liveRoute.Put("/write-configs", reqOrgAdmin, routing.Wrap(hs.Live.HandleWriteConfigsPutHTTP))
+				liveRoute.Delete("/write-configs", reqOrgAdmin, routing.Wrap(hs.Live.HandleWriteConfigsDeleteHTTP))
+			}
 		}, requestmeta.SetSLOGroup(requestmeta.SLOGroupNone))
 
 		// short urls
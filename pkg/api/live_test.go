@@ -0,0 +1,98 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAPI_LivePipelineRoutes(t *testing.T) {
+	type testCase struct {
+		desc            string
+		pipelineEnabled bool
+		role            org.RoleType
+		method          string
+		url             string
+		expectedCode    int
+	}
+	tests := []testCase{
+		{
+			desc:            "should list pipeline entities for org admins",
+			pipelineEnabled: true,
+			role:            org.RoleAdmin,
+			method:          http.MethodGet,
+			url:             "/api/live/pipeline-entities",
+			expectedCode:    http.StatusOK,
+		},
+		{
+			desc:            "should forbid listing channel rules to editors",
+			pipelineEnabled: true,
+			role:            org.RoleEditor,
+			method:          http.MethodGet,
+			url:             "/api/live/channel-rules",
+			expectedCode:    http.StatusForbidden,
+		},
+		{
+			desc:            "should forbid updating channel rules to editors",
+			pipelineEnabled: true,
+			role:            org.RoleEditor,
+			method:          http.MethodPut,
+			url:             "/api/live/channel-rules",
+			expectedCode:    http.StatusForbidden,
+		},
+		{
+			desc:            "should forbid creating write configs to editors",
+			pipelineEnabled: true,
+			role:            org.RoleEditor,
+			method:          http.MethodPost,
+			url:             "/api/live/write-configs",
+			expectedCode:    http.StatusForbidden,
+		},
+		{
+			desc:            "should forbid the convert test to viewers",
+			pipelineEnabled: true,
+			role:            org.RoleViewer,
+			method:          http.MethodPost,
+			url:             "/api/live/pipeline-convert-test",
+			expectedCode:    http.StatusForbidden,
+		},
+		{
+			desc:         "should not register channel rules when the pipeline is disabled",
+			role:         org.RoleAdmin,
+			method:       http.MethodGet,
+			url:          "/api/live/channel-rules",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			desc:         "should not register write configs when the pipeline is disabled",
+			role:         org.RoleAdmin,
+			method:       http.MethodDelete,
+			url:          "/api/live/write-configs",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.Cfg = setting.NewCfg()
+				hs.Cfg.LivePipelineEnabled = tt.pipelineEnabled
+				hs.Live = &live.GrafanaLive{}
+			})
+
+			req := server.NewRequest(tt.method, tt.url, nil)
+			res, err := server.Send(webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: tt.role}))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
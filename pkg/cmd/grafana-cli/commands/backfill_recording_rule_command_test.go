@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/commandstest"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestBackfillRecordingRuleCommand(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(2 * time.Hour)
+	next := from.Add(time.Hour)
+
+	newServer := func(t *testing.T, requests *[]apimodels.BackfillConfig, handle func(cfg apimodels.BackfillConfig) (int, any)) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, backfillPath, r.URL.Path)
+			require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			var cfg apimodels.BackfillConfig
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&cfg))
+			*requests = append(*requests, cfg)
+
+			status, body := handle(cfg)
+			w.WriteHeader(status)
+			require.NoError(t, json.NewEncoder(w).Encode(body))
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	flags := func(url string) map[string]string {
+		return map[string]string{
+			"url":                url,
+			"token":              "token",
+			"rule-uid":           "rule",
+			"from":               from.Format(time.RFC3339),
+			"to":                 to.Format(time.RFC3339),
+			"chunk-size":         "60",
+			"chunks-per-request": "1",
+			"timeout":            "10",
+		}
+	}
+
+	t.Run("should send requests until the range is backfilled", func(t *testing.T) {
+		var requests []apimodels.BackfillConfig
+		srv := newServer(t, &requests, func(cfg apimodels.BackfillConfig) (int, any) {
+			if cfg.From.Equal(from) {
+				return http.StatusOK, apimodels.BackfillResult{From: from, To: next.Add(-time.Minute), Evaluations: 60, Samples: 60, Next: &next}
+			}
+			return http.StatusOK, apimodels.BackfillResult{From: next, To: to.Add(-time.Minute), Evaluations: 60, Samples: 60}
+		})
+
+		c, err := commandstest.NewCliContext(flags(srv.URL))
+		require.NoError(t, err)
+		require.NoError(t, backfillRecordingRuleCommand(c))
+
+		require.Len(t, requests, 2)
+		require.Equal(t, from, requests[0].From.UTC())
+		require.Equal(t, next, requests[1].From.UTC())
+		for _, r := range requests {
+			require.Equal(t, "rule", r.RuleUID)
+			require.Equal(t, to, r.To.UTC())
+			require.Equal(t, 60, r.ChunkSize)
+			require.Equal(t, 1, r.MaxChunks)
+		}
+	})
+
+	t.Run("should return the time from which to resume if a request fails", func(t *testing.T) {
+		var requests []apimodels.BackfillConfig
+		srv := newServer(t, &requests, func(cfg apimodels.BackfillConfig) (int, any) {
+			if cfg.From.Equal(from) {
+				return http.StatusOK, apimodels.BackfillResult{From: from, To: next.Add(-time.Minute), Evaluations: 60, Samples: 60, Next: &next}
+			}
+			return http.StatusInternalServerError, map[string]string{"message": "remote write failed"}
+		})
+
+		c, err := commandstest.NewCliContext(flags(srv.URL))
+		require.NoError(t, err)
+		err = backfillRecordingRuleCommand(c)
+		require.ErrorContains(t, err, "--from "+next.Format(time.RFC3339))
+		require.Len(t, requests, 2)
+	})
+}
@@ -186,6 +186,55 @@ var adminCommands = []*cli.Command{
 	},
 }
 
+var alertingCommands = []*cli.Command{
+	{
+		Name:   "backfill-recording-rule",
+		Usage:  "Evaluates a recording rule over a past time range and writes the samples with their original timestamps",
+		Action: backfillRecordingRuleCommand,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "url",
+				Usage: "URL of the Grafana server",
+				Value: "http://localhost:3000",
+			},
+			&cli.StringFlag{
+				Name:    "token",
+				Usage:   "Service account token used to authenticate to the Grafana server",
+				EnvVars: []string{"GRAFANA_TOKEN"},
+			},
+			&cli.StringFlag{
+				Name:     "rule-uid",
+				Usage:    "UID of the recording rule to backfill",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "from",
+				Usage:    "Start of the range to backfill, in RFC3339 format",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "to",
+				Usage: "End of the range to backfill, in RFC3339 format. Defaults to now",
+			},
+			&cli.IntFlag{
+				Name:  "chunk-size",
+				Usage: "Number of evaluations whose samples are written together",
+				Value: 60,
+			},
+			&cli.IntFlag{
+				Name:  "chunks-per-request",
+				Usage: "Number of chunks backfilled by each request to the Grafana server",
+				Value: 10,
+			},
+			&cli.IntFlag{
+				Name:  "timeout",
+				Usage: "Timeout of each request to the Grafana server, in seconds",
+				Value: 300,
+			},
+		},
+	},
+}
+
 var Commands = []*cli.Command{
 	{
 		Name:        "plugins",
@@ -197,4 +246,9 @@ var Commands = []*cli.Command{
 		Usage:       "Grafana admin commands",
 		Subcommands: adminCommands,
 	},
+	{
+		Name:        "alerting",
+		Usage:       "Grafana Alerting commands",
+		Subcommands: alertingCommands,
+	},
 }
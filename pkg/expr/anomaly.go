@@ -0,0 +1,110 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/metrics"
+	"github.com/grafana/grafana/pkg/expr/ml"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// AnomalyCommand is an expression command that detects anomalies in time series locally,
+// without calling the Machine Learning API. For every input series it returns a score series and an alert series.
+type AnomalyCommand struct {
+	VarToScore string
+	Detector   ml.AnomalyDetector
+	refID      string
+}
+
+// NewAnomalyCommand creates a new AnomalyCommand.
+func NewAnomalyCommand(refID, varToScore string, detector ml.AnomalyDetector) (*AnomalyCommand, error) {
+	if err := detector.Validate(); err != nil {
+		return nil, err
+	}
+	return &AnomalyCommand{
+		VarToScore: varToScore,
+		Detector:   detector,
+		refID:      refID,
+	}, nil
+}
+
+// UnmarshalAnomalyCommand creates an AnomalyCommand from Grafana's frontend query.
+func UnmarshalAnomalyCommand(rn *rawNode) (*AnomalyCommand, error) {
+	q := AnomalyQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the anomaly command: %w", err)
+	}
+	varToScore, err := getReferenceVar(q.Expression, rn.RefID)
+	if err != nil {
+		return nil, err
+	}
+	detector, err := q.detector()
+	if err != nil {
+		return nil, err
+	}
+	return NewAnomalyCommand(rn.RefID, varToScore, detector)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (ga *AnomalyCommand) NeedsVars() []string {
+	return []string{ga.VarToScore}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (ga *AnomalyCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer, _ *metrics.ExprMetrics) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteAnomaly")
+	defer span.End()
+	span.SetAttributes(attribute.String("scoring", string(ga.Detector.Scoring)), attribute.String("season", ga.Detector.Season.String()))
+
+	newRes := mathexp.Results{}
+	for _, val := range vars[ga.VarToScore].Values {
+		if val == nil {
+			continue
+		}
+		switch v := val.(type) {
+		case mathexp.Series:
+			score, alert, err := ga.Detector.DetectAnomalies(ga.refID, v)
+			if err != nil {
+				return newRes, fmt.Errorf("failed to detect anomalies in series %s: %w", v.GetLabels().String(), err)
+			}
+			newRes.Values = append(newRes.Values, score, alert)
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+			return newRes, nil
+		default:
+			return newRes, fmt.Errorf("can only detect anomalies in type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func (ga *AnomalyCommand) Type() string {
+	return TypeAnomaly.String()
+}
+
+func (q AnomalyQuery) detector() (ml.AnomalyDetector, error) {
+	detector := ml.AnomalyDetector{
+		Scoring:   q.Scoring,
+		Threshold: ml.DefaultAnomalyThreshold,
+	}
+	if q.Season != "" {
+		season, err := gtime.ParseDuration(q.Season)
+		if err != nil {
+			return detector, fmt.Errorf(`failed to parse anomaly "season" duration field %q: %w`, q.Season, err)
+		}
+		detector.Season = season
+	}
+	if q.Threshold != nil {
+		detector.Threshold = *q.Threshold
+	}
+	return detector, nil
+}
@@ -0,0 +1,109 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/ml"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestUnmarshalAnomalyCommand(t *testing.T) {
+	var tests = []struct {
+		name          string
+		query         string
+		expectedError string
+		expected      ml.AnomalyDetector
+	}{
+		{
+			name:     "defaults threshold",
+			query:    `{ "type": "anomaly", "expression": "$A", "scoring": "zscore" }`,
+			expected: ml.AnomalyDetector{Scoring: ml.AnomalyScoringZScore, Threshold: ml.DefaultAnomalyThreshold},
+		},
+		{
+			name:     "parses season and threshold",
+			query:    `{ "type": "anomaly", "expression": "$A", "scoring": "mad", "season": "1d", "threshold": 5 }`,
+			expected: ml.AnomalyDetector{Scoring: ml.AnomalyScoringMAD, Season: 24 * time.Hour, Threshold: 5},
+		},
+		{
+			name:          "error when season is invalid",
+			query:         `{ "type": "anomaly", "expression": "$A", "scoring": "mad", "season": "daily" }`,
+			expectedError: `failed to parse anomaly "season" duration field`,
+		},
+		{
+			name:          "error when scoring is missing",
+			query:         `{ "type": "anomaly", "expression": "$A" }`,
+			expectedError: "unsupported scoring method",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]any)
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+
+			cmd, err := UnmarshalAnomalyCommand(&rawNode{
+				RefID:    "B",
+				Query:    qmap,
+				QueryRaw: []byte(test.query),
+			})
+
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, cmd.Detector)
+			require.Equal(t, []string{"A"}, cmd.NeedsVars())
+		})
+	}
+}
+
+func TestAnomalyCommandExecute(t *testing.T) {
+	cmd, err := NewAnomalyCommand("B", "A", ml.AnomalyDetector{Scoring: ml.AnomalyScoringMAD, Threshold: ml.DefaultAnomalyThreshold})
+	require.NoError(t, err)
+
+	t.Run("should return score and alert series", func(t *testing.T) {
+		s := mathexp.NewSeries("A", data.Labels{"host": "a"}, 5)
+		for i, v := range []float64{1, 2, 3, 4, 100} {
+			s.SetPoint(i, time.Unix(int64(i), 0), util.Pointer(v))
+		}
+		vars := mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{s}}}
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest(), nil)
+		require.NoError(t, err)
+		require.Len(t, res.Values, 2)
+		require.Equal(t, data.Labels{"host": "a", ml.AnomalyLabel: ml.AnomalyScoreSeries}, res.Values[0].GetLabels())
+		require.Equal(t, data.Labels{"host": "a", ml.AnomalyLabel: ml.AnomalyAlertSeries}, res.Values[1].GetLabels())
+		alert := res.Values[1].(mathexp.Series)
+		require.Equal(t, util.Pointer(1.0), alert.GetValue(4))
+		require.Equal(t, util.Pointer(0.0), alert.GetValue(0))
+	})
+
+	t.Run("should pass no data through", func(t *testing.T) {
+		vars := mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{mathexp.NoData{}.New()}}}
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest(), nil)
+		require.NoError(t, err)
+		require.True(t, res.IsNoData())
+	})
+
+	t.Run("should skip nil values", func(t *testing.T) {
+		vars := mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{nil}}}
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest(), nil)
+		require.NoError(t, err)
+		require.Empty(t, res.Values)
+	})
+
+	t.Run("should fail on numbers", func(t *testing.T) {
+		vars := mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{mathexp.NewNumber("A", nil)}}}
+		_, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest(), nil)
+		require.ErrorContains(t, err, "can only detect anomalies in type series")
+	})
+}
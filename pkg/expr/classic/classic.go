@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -47,6 +49,9 @@ type condition struct {
 	// min and max functions.
 	Reducer reducer
 
+	// ReducerParams contains the arguments of parameterised reducers such as percentile.
+	ReducerParams mathexp.ReducerParams
+
 	// Evaluator evaluates the reduced time series, instant metric, or result of another expression
 	// against an evaluator. An example of an evaluator is checking if it exceeds a threshold,
 	// falls within a range, or does not contain a value.
@@ -183,7 +188,7 @@ func (cmd *ConditionsCmd) executeCond(_ context.Context, _ time.Time, cond condi
 			number = v
 		case mathexp.Series:
 			name = v.GetName()
-			number = cond.Reducer.Reduce(v)
+			number = cond.Reducer.Reduce(v, cond.ReducerParams)
 		default:
 			return false, false, nil, fmt.Errorf("can only reduce type series, got type %v", v.Type())
 		}
@@ -292,7 +297,60 @@ type ConditionQueryJSON struct {
 
 type ConditionReducerJSON struct {
 	Type string `json:"type"`
-	// Params []any `json:"params"` (Unused)
+	// Params are the arguments of parameterised reducers.
+	// The percentile reducer expects the percentile (0-100) as its first param.
+	Params ConditionReducerParams `json:"params,omitempty"`
+}
+
+// ConditionReducerParams are the params of a reducer. Reducers of legacy alerts had no params
+// and their stored params can be strings, so numeric strings are converted to numbers and
+// other values are kept as NaN rather than failing to unmarshal the condition.
+type ConditionReducerParams []float64
+
+func (p *ConditionReducerParams) UnmarshalJSON(b []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*p = nil
+		return nil
+	}
+	params := make(ConditionReducerParams, 0, len(raw))
+	for _, r := range raw {
+		var v any
+		if err := json.Unmarshal(r, &v); err != nil {
+			return err
+		}
+		param := math.NaN()
+		switch v := v.(type) {
+		case float64:
+			param = v
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				param = f
+			}
+		}
+		params = append(params, param)
+	}
+	*p = params
+	return nil
+}
+
+// MarshalJSON writes params which are not numbers as null, as JSON has no NaN.
+func (p ConditionReducerParams) MarshalJSON() ([]byte, error) {
+	if p == nil {
+		return []byte("null"), nil
+	}
+	raw := make([]any, 0, len(p))
+	for _, v := range p {
+		if math.IsNaN(v) {
+			raw = append(raw, nil)
+			continue
+		}
+		raw = append(raw, v)
+	}
+	return json.Marshal(raw)
 }
 
 func NewConditionCmd(refID string, ccj []ConditionJSON) (*ConditionsCmd, error) {
@@ -322,6 +380,15 @@ func NewConditionCmd(refID string, ccj []ConditionJSON) (*ConditionsCmd, error)
 		if !cond.Reducer.ValidReduceFunc() {
 			return nil, fmt.Errorf("invalid reducer '%v' in condition %v", cond.Reducer, i+1)
 		}
+		if cond.Reducer == reducer(mathexp.ReducerPercentile) {
+			if len(cj.Reducer.Params) == 0 || math.IsNaN(cj.Reducer.Params[0]) {
+				return nil, fmt.Errorf("reducer '%v' in condition %v requires the percentile as its first param", cond.Reducer, i+1)
+			}
+			cond.ReducerParams.Percentile = &cj.Reducer.Params[0]
+			if _, err := mathexp.GetSeriesReduceFunc(mathexp.ReducerPercentile, cond.ReducerParams); err != nil {
+				return nil, fmt.Errorf("invalid reducer '%v' in condition %v: %w", cond.Reducer, i+1, err)
+			}
+		}
 
 		cond.Evaluator, err = newAlertEvaluator(cj.Evaluator)
 		if err != nil {
@@ -3,6 +3,7 @@ package classic
 import (
 	"context"
 	"encoding/json"
+	"math"
 	"testing"
 	"time"
 
@@ -732,6 +733,117 @@ func TestUnmarshalConditionsCmd(t *testing.T) {
 			},
 			needsVars: []string{"A"},
 		},
+		{
+			name: "percentile condition",
+			rawJSON: `{
+				"conditions": [
+				  {
+					"evaluator": {
+					  "params": [
+						100
+					  ],
+					  "type": "gt"
+					},
+					"operator": {
+					  "type": "and"
+					},
+					"query": {
+					  "params": [
+						"A"
+					  ]
+					},
+					"reducer": {
+					  "params": [
+						99
+					  ],
+					  "type": "percentile"
+					},
+					"type": "query"
+				  }
+				]
+			}`,
+			expectedCommand: &ConditionsCmd{
+				Conditions: []condition{
+					{
+						InputRefID:    "A",
+						Reducer:       reducer("percentile"),
+						ReducerParams: mathexp.ReducerParams{Percentile: util.Pointer(99.0)},
+						Operator:      "and",
+						Evaluator:     &thresholdEvaluator{Type: "gt", Threshold: 100},
+					},
+				},
+			},
+			needsVars: []string{"A"},
+		},
+		{
+			name: "legacy condition with string reducer params",
+			rawJSON: `{
+				"conditions": [
+				  {
+					"evaluator": {
+					  "params": [
+						3
+					  ],
+					  "type": "lt"
+					},
+					"operator": {
+					  "type": "and"
+					},
+					"query": {
+					  "params": [
+						"A",
+						"5m",
+						"now"
+					  ]
+					},
+					"reducer": {
+					  "params": [
+						"5m"
+					  ],
+					  "type": "last"
+					},
+					"type": "query"
+				  }
+				]
+			}`,
+			expectedCommand: &ConditionsCmd{
+				Conditions: []condition{
+					{
+						InputRefID: "A",
+						Reducer:    reducer("last"),
+						Operator:   "and",
+						Evaluator:  &thresholdEvaluator{Type: "lt", Threshold: 3},
+					},
+				},
+			},
+			needsVars: []string{"A"},
+		},
+		{
+			name: "percentile condition with mixed reducer params",
+			rawJSON: `{
+				"conditions": [
+				  {
+					"evaluator": { "params": [100], "type": "gt" },
+					"operator": { "type": "and" },
+					"query": { "params": ["A"] },
+					"reducer": { "params": ["95", 1, null], "type": "percentile" },
+					"type": "query"
+				  }
+				]
+			}`,
+			expectedCommand: &ConditionsCmd{
+				Conditions: []condition{
+					{
+						InputRefID:    "A",
+						Reducer:       reducer("percentile"),
+						ReducerParams: mathexp.ReducerParams{Percentile: util.Pointer(95.0)},
+						Operator:      "and",
+						Evaluator:     &thresholdEvaluator{Type: "gt", Threshold: 100},
+					},
+				},
+			},
+			needsVars: []string{"A"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -748,3 +860,37 @@ func TestUnmarshalConditionsCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestUnmarshalConditionsCmdPercentile(t *testing.T) {
+	for _, params := range []string{`[]`, `[101]`, `["high"]`} {
+		rawJSON := `{
+			"conditions": [
+			  {
+				"evaluator": { "params": [1], "type": "gt" },
+				"operator": { "type": "and" },
+				"query": { "params": ["A"] },
+				"reducer": { "params": ` + params + `, "type": "percentile" }
+			  }
+			]
+		}`
+		var rq map[string]any
+		require.NoError(t, json.Unmarshal([]byte(rawJSON), &rq))
+
+		_, err := UnmarshalConditionsCmd(rq, "")
+		require.Error(t, err, "params %s", params)
+	}
+}
+
+func TestConditionReducerParamsJSON(t *testing.T) {
+	var params ConditionReducerParams
+	require.NoError(t, json.Unmarshal([]byte(`[" 50 ", 2.5, "5m", true]`), &params))
+	require.Len(t, params, 4)
+	require.Equal(t, 50.0, params[0])
+	require.Equal(t, 2.5, params[1])
+	require.True(t, math.IsNaN(params[2]))
+	require.True(t, math.IsNaN(params[3]))
+
+	b, err := json.Marshal(params)
+	require.NoError(t, err)
+	require.JSONEq(t, `[50, 2.5, null, null]`, string(b))
+}
@@ -15,12 +15,14 @@ func (cr reducer) ValidReduceFunc() bool {
 		return true
 	case "diff", "diff_abs", "percent_diff", "percent_diff_abs", "count_non_null":
 		return true
+	case "first", "stddev", "range", "delta", "rate", "percentile":
+		return true
 	}
 	return false
 }
 
 //nolint:gocyclo
-func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
+func (cr reducer) Reduce(series mathexp.Series, params mathexp.ReducerParams) mathexp.Number {
 	num := mathexp.NewNumber("", nil)
 
 	if series.GetLabels() != nil {
@@ -144,6 +146,8 @@ func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
 		if value > 0 {
 			allNull = false
 		}
+	case "first", "stddev", "range", "delta", "rate", "percentile":
+		allNull, value = reduceNonNull(series, mathexp.ReducerID(cr), params)
 	}
 
 	if allNull {
@@ -182,6 +186,20 @@ func calculateDiff(ff mathexp.Float64Field, allNull bool, value float64, fn func
 	return allNull, value
 }
 
+// reduceNonNull drops null and NaN values from the series and reduces what is left
+// with the reducer of the same name used by server-side Reduce expressions.
+func reduceNonNull(series mathexp.Series, rFunc mathexp.ReducerID, params mathexp.ReducerParams) (bool, float64) {
+	num, err := series.Reduce("", rFunc, params, mathexp.DropNonNumber{})
+	if err != nil {
+		return true, 0
+	}
+	f := num.GetFloat64Value()
+	if f == nil {
+		return true, 0
+	}
+	return false, *f
+}
+
 func nilOrNaN(f *float64) bool {
 	return f == nil || math.IsNaN(*f)
 }
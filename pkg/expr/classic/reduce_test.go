@@ -115,17 +115,63 @@ func TestReducer(t *testing.T) {
 			inputSeries:    newSeries(nil, nil),
 			expectedNumber: newNumber(nil),
 		},
+		{
+			name:           "first skips null values",
+			reducer:        reducer("first"),
+			inputSeries:    newSeries(nil, util.Pointer(math.NaN()), util.Pointer(3.0), util.Pointer(4.0)),
+			expectedNumber: newNumber(util.Pointer(3.0)),
+		},
+		{
+			name:           "stddev",
+			reducer:        reducer("stddev"),
+			inputSeries:    newSeries(util.Pointer(2.0), nil, util.Pointer(4.0), util.Pointer(4.0), util.Pointer(4.0), util.Pointer(5.0), util.Pointer(5.0), util.Pointer(7.0), util.Pointer(9.0)),
+			expectedNumber: newNumber(util.Pointer(2.0)),
+		},
+		{
+			name:           "range",
+			reducer:        reducer("range"),
+			inputSeries:    newSeries(util.Pointer(3.0), nil, util.Pointer(-1.0), util.Pointer(4.0)),
+			expectedNumber: newNumber(util.Pointer(5.0)),
+		},
+		{
+			name:           "delta",
+			reducer:        reducer("delta"),
+			inputSeries:    newSeries(nil, util.Pointer(3.0), util.Pointer(-1.0), util.Pointer(4.0), nil),
+			expectedNumber: newNumber(util.Pointer(1.0)),
+		},
+		{
+			name:           "rate",
+			reducer:        reducer("rate"),
+			inputSeries:    newSeries(util.Pointer(1.0), util.Pointer(2.0), util.Pointer(3.0), util.Pointer(4.0)),
+			expectedNumber: newNumber(util.Pointer(1.0)),
+		},
+		{
+			name:           "rate with no values",
+			reducer:        reducer("rate"),
+			inputSeries:    newSeries(nil, nil),
+			expectedNumber: newNumber(nil),
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			require.Equal(t, true, tt.reducer.ValidReduceFunc())
-			num := tt.reducer.Reduce(tt.inputSeries)
+			num := tt.reducer.Reduce(tt.inputSeries, mathexp.ReducerParams{})
 			require.Equal(t, tt.expectedNumber, num)
 		})
 	}
 }
 
+func TestPercentileReducer(t *testing.T) {
+	series := newSeries(util.Pointer(4.0), nil, util.Pointer(1.0), util.Pointer(3.0), util.Pointer(2.0))
+
+	num := reducer("percentile").Reduce(series, mathexp.ReducerParams{Percentile: util.Pointer(50.0)})
+	require.Equal(t, newNumber(util.Pointer(2.5)), num)
+
+	num = reducer("percentile").Reduce(series, mathexp.ReducerParams{Percentile: util.Pointer(100.0)})
+	require.Equal(t, newNumber(util.Pointer(4.0)), num)
+}
+
 func TestDiffReducer(t *testing.T) {
 	var tests = []struct {
 		name           string
@@ -190,7 +236,7 @@ func TestDiffReducer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			num := reducer("diff").Reduce(tt.inputSeries)
+			num := reducer("diff").Reduce(tt.inputSeries, mathexp.ReducerParams{})
 			require.Equal(t, tt.expectedNumber, num)
 		})
 	}
@@ -260,7 +306,7 @@ func TestDiffAbsReducer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			num := reducer("diff_abs").Reduce(tt.inputSeries)
+			num := reducer("diff_abs").Reduce(tt.inputSeries, mathexp.ReducerParams{})
 			require.Equal(t, tt.expectedNumber, num)
 		})
 	}
@@ -330,7 +376,7 @@ func TestPercentDiffReducer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			num := reducer("percent_diff").Reduce(tt.inputSeries)
+			num := reducer("percent_diff").Reduce(tt.inputSeries, mathexp.ReducerParams{})
 			require.Equal(t, tt.expectedNumber, num)
 		})
 	}
@@ -400,7 +446,7 @@ func TestPercentDiffAbsReducer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			num := reducer("percent_diff_abs").Reduce(tt.inputSeries)
+			num := reducer("percent_diff_abs").Reduce(tt.inputSeries, mathexp.ReducerParams{})
 			require.Equal(t, tt.expectedNumber, num)
 		})
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -75,7 +76,11 @@ func (gm *MathCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Va
 	_, span := tracer.Start(ctx, "SSE.ExecuteMath")
 	span.SetAttributes(attribute.String("expression", gm.RawExpression))
 	defer span.End()
-	return gm.Expression.Execute(gm.refID, vars, tracer)
+	res, drops, err := gm.Expression.ExecuteWithDrops(gm.refID, vars, tracer)
+	for _, d := range drops {
+		recordDropped(ctx, d.Labels, fmt.Sprintf("no matching value for %s in union %s", d.Input, d.Union))
+	}
+	return res, err
 }
 
 func (gm *MathCommand) Type() string {
@@ -85,20 +90,22 @@ func (gm *MathCommand) Type() string {
 // ReduceCommand is an expression command for reduction of a timeseries such as a min, mean, or max.
 type ReduceCommand struct {
 	Reducer      mathexp.ReducerID
+	Params       mathexp.ReducerParams
 	VarToReduce  string
 	refID        string
 	seriesMapper mathexp.ReduceMapper
 }
 
 // NewReduceCommand creates a new ReduceCMD.
-func NewReduceCommand(refID string, reducer mathexp.ReducerID, varToReduce string, mapper mathexp.ReduceMapper) (*ReduceCommand, error) {
-	_, err := mathexp.GetReduceFunc(reducer)
+func NewReduceCommand(refID string, reducer mathexp.ReducerID, params mathexp.ReducerParams, varToReduce string, mapper mathexp.ReduceMapper) (*ReduceCommand, error) {
+	_, err := mathexp.GetSeriesReduceFunc(reducer, params)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ReduceCommand{
 		Reducer:      reducer,
+		Params:       params,
 		VarToReduce:  varToReduce,
 		refID:        refID,
 		seriesMapper: mapper,
@@ -127,6 +134,15 @@ func UnmarshalReduceCommand(rn *rawNode) (*ReduceCommand, error) {
 	}
 	redFunc := mathexp.ReducerID(strings.ToLower(redString))
 
+	params := mathexp.ReducerParams{}
+	if rawPercentile, ok := rn.Query["percentile"]; ok && rawPercentile != nil {
+		percentile, ok := rawPercentile.(float64)
+		if !ok {
+			return nil, fmt.Errorf("expected percentile to be a number, got %T", rawPercentile)
+		}
+		params.Percentile = &percentile
+	}
+
 	var mapper mathexp.ReduceMapper = nil
 	settings, ok := rn.Query["settings"]
 	if ok {
@@ -156,7 +172,7 @@ func UnmarshalReduceCommand(rn *rawNode) (*ReduceCommand, error) {
 			return nil, fmt.Errorf("field settings must be an object, got %T for refId %v", s, rn.RefID)
 		}
 	}
-	return NewReduceCommand(rn.RefID, redFunc, varToReduce, mapper)
+	return NewReduceCommand(rn.RefID, redFunc, params, varToReduce, mapper)
 }
 
 // NeedsVars returns the variable names (refIds) that are dependencies
@@ -177,7 +193,7 @@ func (gr *ReduceCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.
 	for i, val := range vars[gr.VarToReduce].Values {
 		switch v := val.(type) {
 		case mathexp.Series:
-			num, err := v.Reduce(gr.refID, gr.Reducer, gr.seriesMapper)
+			num, err := v.Reduce(gr.refID, gr.Reducer, gr.Params, gr.seriesMapper)
 			if err != nil {
 				return newRes, err
 			}
@@ -187,6 +203,7 @@ func (gr *ReduceCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.
 			if gr.seriesMapper != nil {
 				value = gr.seriesMapper.MapInput(value)
 				if value == nil { // same logic as in mapSeries
+					recordDropped(ctx, v.GetLabels(), "dropped by the reduce mode because it is not a number")
 					continue
 				}
 			}
@@ -218,22 +235,27 @@ type ResampleCommand struct {
 	VarToResample string
 	Downsampler   mathexp.ReducerID
 	Upsampler     mathexp.Upsampler
+	MaxGap        int
 	TimeRange     TimeRange
 	refID         string
 }
 
 // NewResampleCommand creates a new ResampleCMD.
-func NewResampleCommand(refID, rawWindow, varToResample string, downsampler mathexp.ReducerID, upsampler mathexp.Upsampler, tr TimeRange) (*ResampleCommand, error) {
+func NewResampleCommand(refID, rawWindow, varToResample string, downsampler mathexp.ReducerID, upsampler mathexp.Upsampler, maxGap int, tr TimeRange) (*ResampleCommand, error) {
 	// TODO: validate reducer here, before execution
 	window, err := gtime.ParseDuration(rawWindow)
 	if err != nil {
 		return nil, fmt.Errorf(`failed to parse resample "window" duration field %q: %w`, window, err)
 	}
+	if maxGap < 0 {
+		return nil, fmt.Errorf("resample max gap must not be negative, got %d", maxGap)
+	}
 	return &ResampleCommand{
 		Window:        window,
 		VarToResample: varToResample,
 		Downsampler:   downsampler,
 		Upsampler:     upsampler,
+		MaxGap:        maxGap,
 		TimeRange:     tr,
 		refID:         refID,
 	}, nil
@@ -282,10 +304,20 @@ func UnmarshalResampleCommand(rn *rawNode) (*ResampleCommand, error) {
 		return nil, fmt.Errorf("expected resample downsampler to be a string, got type %T", upsampler)
 	}
 
+	maxGap := 0
+	if rawMaxGap, ok := rn.Query["maxGap"]; ok && rawMaxGap != nil {
+		f, ok := rawMaxGap.(float64)
+		if !ok || f != math.Trunc(f) {
+			return nil, fmt.Errorf("expected resample max gap to be an integer, got %v", rawMaxGap)
+		}
+		maxGap = int(f)
+	}
+
 	return NewResampleCommand(rn.RefID, window,
 		varToResample,
 		mathexp.ReducerID(downsampler),
 		mathexp.Upsampler(upsampler),
+		maxGap,
 		rn.TimeRange)
 }
 
@@ -308,7 +340,7 @@ func (gr *ResampleCommand) Execute(ctx context.Context, now time.Time, vars math
 		}
 		switch v := val.(type) {
 		case mathexp.Series:
-			num, err := v.Resample(gr.refID, gr.Window, gr.Downsampler, gr.Upsampler, timeRange.From, timeRange.To)
+			num, err := v.Resample(gr.refID, gr.Window, gr.Downsampler, gr.Upsampler, gr.MaxGap, timeRange.From, timeRange.To)
 			if err != nil {
 				return newRes, err
 			}
@@ -345,6 +377,12 @@ const (
 	TypeThreshold
 	// TypeSQL is the CMDType for running SQL expressions
 	TypeSQL
+	// TypeJoin is the CMDType for joining two results by label matching
+	TypeJoin
+	// TypeAnomaly is the CMDType for detecting anomalies in time series
+	TypeAnomaly
+	// TypeForecast is the CMDType for forecasting time series
+	TypeForecast
 )
 
 func (gt CommandType) String() string {
@@ -361,6 +399,12 @@ func (gt CommandType) String() string {
 		return "threshold"
 	case TypeSQL:
 		return "sql"
+	case TypeJoin:
+		return "join"
+	case TypeAnomaly:
+		return "anomaly"
+	case TypeForecast:
+		return "forecast"
 	default:
 		return "unknown"
 	}
@@ -381,6 +425,12 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeThreshold, nil
 	case "sql":
 		return TypeSQL, nil
+	case "join":
+		return TypeJoin, nil
+	case "anomaly":
+		return TypeAnomaly, nil
+	case "forecast":
+		return TypeForecast, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"slices"
 	"testing"
 	"time"
 
@@ -99,11 +100,113 @@ func Test_UnmarshalReduceCommand_Settings(t *testing.T) {
 	}
 }
 
+func Test_UnmarshalReduceCommand_Percentile(t *testing.T) {
+	var tests = []struct {
+		name               string
+		query              string
+		isError            bool
+		expectedPercentile *float64
+	}{
+		{
+			name:               "percentile is parsed",
+			query:              `{ "expression" : "$A", "reducer": "percentile", "percentile": 99 }`,
+			expectedPercentile: util.Pointer(99.0),
+		},
+		{
+			name:    "error when percentile is missing",
+			query:   `{ "expression" : "$A", "reducer": "percentile" }`,
+			isError: true,
+		},
+		{
+			name:    "error when percentile is out of range",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "percentile": 101 }`,
+			isError: true,
+		},
+		{
+			name:    "error when percentile is not a number",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "percentile": "90" }`,
+			isError: true,
+		},
+		{
+			name:  "percentile is ignored by other reducers",
+			query: `{ "expression" : "$A", "reducer": "stddev" }`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]any)
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+
+			cmd, err := UnmarshalReduceCommand(&rawNode{
+				RefID: "A",
+				Query: qmap,
+			})
+
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expectedPercentile, cmd.Params.Percentile)
+		})
+	}
+}
+
+func Test_UnmarshalResampleCommand_MaxGap(t *testing.T) {
+	var tests = []struct {
+		name           string
+		query          string
+		isError        bool
+		expectedMaxGap int
+	}{
+		{
+			name:           "max gap is parsed",
+			query:          `{ "expression" : "$A", "window": "1m", "downsampler": "mean", "upsampler": "linear", "maxGap": 3 }`,
+			expectedMaxGap: 3,
+		},
+		{
+			name:  "max gap defaults to zero",
+			query: `{ "expression" : "$A", "window": "1m", "downsampler": "mean", "upsampler": "nearest" }`,
+		},
+		{
+			name:    "error when max gap is negative",
+			query:   `{ "expression" : "$A", "window": "1m", "downsampler": "mean", "upsampler": "pad", "maxGap": -1 }`,
+			isError: true,
+		},
+		{
+			name:    "error when max gap is not an integer",
+			query:   `{ "expression" : "$A", "window": "1m", "downsampler": "mean", "upsampler": "pad", "maxGap": 1.5 }`,
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]any)
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+
+			cmd, err := UnmarshalResampleCommand(&rawNode{
+				RefID:     "A",
+				Query:     qmap,
+				TimeRange: RelativeTimeRange{},
+			})
+
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expectedMaxGap, cmd.MaxGap)
+		})
+	}
+}
+
 func TestReduceExecute(t *testing.T) {
 	varToReduce := util.GenerateShortUID()
 
 	t.Run("when mapper is nil", func(t *testing.T) {
-		cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), varToReduce, nil)
+		cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), mathexp.ReducerParams{}, varToReduce, nil)
 		require.NoError(t, err)
 
 		t.Run("should noop if Number", func(t *testing.T) {
@@ -161,7 +264,7 @@ func TestReduceExecute(t *testing.T) {
 		}
 
 		t.Run("drop all non numbers if mapper is DropNonNumber", func(t *testing.T) {
-			cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), varToReduce, &mathexp.DropNonNumber{})
+			cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), mathexp.ReducerParams{}, varToReduce, &mathexp.DropNonNumber{})
 			require.NoError(t, err)
 			execute, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest(), nil)
 			require.NoError(t, err)
@@ -169,7 +272,7 @@ func TestReduceExecute(t *testing.T) {
 		})
 
 		t.Run("replace all non numbers if mapper is ReplaceNonNumberWithValue", func(t *testing.T) {
-			cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), varToReduce, &mathexp.ReplaceNonNumberWithValue{Value: 1})
+			cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), mathexp.ReducerParams{}, varToReduce, &mathexp.ReplaceNonNumberWithValue{Value: 1})
 			require.NoError(t, err)
 			execute, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest(), nil)
 			require.NoError(t, err)
@@ -192,7 +295,7 @@ func TestReduceExecute(t *testing.T) {
 				Values: noData,
 			},
 		}
-		cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), varToReduce, nil)
+		cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), mathexp.ReducerParams{}, varToReduce, nil)
 		require.NoError(t, err)
 		results, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest(), nil)
 		require.NoError(t, err)
@@ -211,7 +314,9 @@ func TestReduceExecute(t *testing.T) {
 }
 
 func randomReduceFunc() mathexp.ReducerID {
-	res := mathexp.GetSupportedReduceFuncs()
+	res := slices.DeleteFunc(mathexp.GetSupportedReduceFuncs(), func(r mathexp.ReducerID) bool {
+		return r == mathexp.ReducerPercentile // requires parameters
+	})
 	return res[rand.Intn(len(res))]
 }
 
@@ -221,7 +326,7 @@ func TestResampleCommand_Execute(t *testing.T) {
 		From: -10 * time.Second,
 		To:   0,
 	}
-	cmd, err := NewResampleCommand(util.GenerateShortUID(), "1s", varToReduce, "sum", "pad", tr)
+	cmd, err := NewResampleCommand(util.GenerateShortUID(), "1s", varToReduce, "sum", "pad", 0, tr)
 	require.NoError(t, err)
 
 	var tests = []struct {
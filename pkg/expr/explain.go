@@ -0,0 +1,171 @@
+package expr
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+const (
+	// maxExplainSamples is the maximum number of output values sampled per node.
+	maxExplainSamples = 5
+	// maxExplainSampleValues is the maximum number of points sampled per series.
+	maxExplainSampleValues = 5
+	// maxExplainDropped is the maximum number of dropped series listed per node.
+	maxExplainDropped = 100
+)
+
+// PipelineExplanation is the trace of the execution of a pipeline, with the nodes in the order they were executed.
+type PipelineExplanation struct {
+	Nodes []*NodeExplanation `json:"nodes"`
+
+	// current is the node being executed, which the values dropped by commands are reported to.
+	current *NodeExplanation
+}
+
+// NodeExplanation describes the execution of a node of the pipeline.
+type NodeExplanation struct {
+	RefID string `json:"refId"`
+	// NodeType is the type of the node, such as "Datasource" or "Expression".
+	NodeType string `json:"nodeType"`
+	// Command is the type of the expression command, such as "math" or "reduce". It is empty for other nodes.
+	Command string `json:"command,omitempty"`
+	// Inputs are the refIds of the nodes this node depends on.
+	Inputs []string `json:"inputs,omitempty"`
+	// Duration is the time it took to execute the node. Data source nodes that are queried together
+	// have the duration of the whole group.
+	Duration time.Duration `json:"duration"`
+	// InputSeries is the number of values, NoData excluded, of the inputs of the node.
+	InputSeries int `json:"inputSeries"`
+	// OutputSeries is the number of values, NoData excluded, returned by the node.
+	OutputSeries int `json:"outputSeries"`
+	// Dropped are the input values that did not make it to the output, at most maxExplainDropped.
+	Dropped []DroppedSeries `json:"dropped,omitempty"`
+	// DroppedCount is the total number of dropped values, including those that are not listed.
+	DroppedCount int `json:"droppedCount,omitempty"`
+	// Samples are the first few output values of the node.
+	Samples []ValueSample `json:"samples,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// DroppedSeries is an input value that was dropped by a node.
+type DroppedSeries struct {
+	Labels data.Labels `json:"labels,omitempty"`
+	Reason string      `json:"reason"`
+}
+
+// ValueSample is a sample of an output value of a node.
+// Values are formatted as strings, so that NaN and Inf can be represented, and nulls are "null".
+type ValueSample struct {
+	Labels data.Labels `json:"labels,omitempty"`
+	// Type is the type of the value, such as "seriesSet" or "numberSet".
+	Type string `json:"type"`
+	// Length is the number of points of a series.
+	Length int `json:"length,omitempty"`
+	// Values is the value of a number or a scalar, or the last points of a series.
+	Values []string `json:"values,omitempty"`
+}
+
+type explanationKey struct{}
+
+// newNode appends the explanation of a node to the pipeline explanation, with the inputs read from vars.
+func (e *PipelineExplanation) newNode(node Node, vars mathexp.Vars) *NodeExplanation {
+	n := &NodeExplanation{
+		RefID:    node.RefID(),
+		NodeType: node.NodeType().String(),
+		Inputs:   node.NeedsVars(),
+	}
+	if cmdNode, ok := node.(*CMDNode); ok {
+		n.Command = cmdNode.CMDType.String()
+	}
+	for _, input := range n.Inputs {
+		res, ok := vars[input]
+		if !ok {
+			continue
+		}
+		n.InputSeries += countSeries(res)
+		if res.Error == nil && res.IsNoData() {
+			n.addDropped(nil, "input "+input+" has no data")
+		}
+	}
+	e.Nodes = append(e.Nodes, n)
+	e.current = n
+	return n
+}
+
+// finish records the duration and the result of the execution of the node.
+func (n *NodeExplanation) finish(res mathexp.Results, duration time.Duration) {
+	n.Duration = duration
+	if res.Error != nil {
+		n.Error = res.Error.Error()
+	}
+	n.OutputSeries = countSeries(res)
+	for _, val := range res.Values {
+		if len(n.Samples) == maxExplainSamples {
+			break
+		}
+		if val == nil || val.Type() == parse.TypeNoData {
+			continue
+		}
+		n.Samples = append(n.Samples, sampleValue(val))
+	}
+}
+
+func (n *NodeExplanation) addDropped(labels data.Labels, reason string) {
+	n.DroppedCount++
+	if len(n.Dropped) < maxExplainDropped {
+		n.Dropped = append(n.Dropped, DroppedSeries{Labels: labels, Reason: reason})
+	}
+}
+
+// withExplanation returns a context that commands use to report the values they drop to the node being executed.
+func withExplanation(ctx context.Context, e *PipelineExplanation) context.Context {
+	return context.WithValue(ctx, explanationKey{}, e)
+}
+
+// recordDropped reports a value dropped by a command when the pipeline is executed in explain mode,
+// and does nothing otherwise.
+func recordDropped(ctx context.Context, labels data.Labels, reason string) {
+	if e, ok := ctx.Value(explanationKey{}).(*PipelineExplanation); ok && e.current != nil {
+		e.current.addDropped(labels, reason)
+	}
+}
+
+func countSeries(res mathexp.Results) int {
+	count := 0
+	for _, val := range res.Values {
+		if val != nil && val.Type() != parse.TypeNoData {
+			count++
+		}
+	}
+	return count
+}
+
+func sampleValue(val mathexp.Value) ValueSample {
+	sample := ValueSample{Labels: val.GetLabels(), Type: val.Type().String()}
+	switch v := val.(type) {
+	case mathexp.Series:
+		sample.Length = v.Len()
+		for i := max(0, v.Len()-maxExplainSampleValues); i < v.Len(); i++ {
+			_, value := v.GetPoint(i)
+			sample.Values = append(sample.Values, formatSampleValue(value))
+		}
+	case mathexp.Number:
+		sample.Values = []string{formatSampleValue(v.GetFloat64Value())}
+	case mathexp.Scalar:
+		sample.Values = []string{formatSampleValue(v.GetFloat64Value())}
+	}
+	return sample
+}
+
+func formatSampleValue(value *float64) string {
+	if value == nil {
+		return "null"
+	}
+	return strconv.FormatFloat(*value, 'g', -1, 64)
+}
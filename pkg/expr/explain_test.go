@@ -0,0 +1,111 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+func TestExecutePipelineExplain(t *testing.T) {
+	series := func(refID string, labels data.Labels, value float64) *data.Frame {
+		f := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+			data.NewField("value", labels, []*float64{fp(value)}),
+		)
+		f.RefID = refID
+		return f
+	}
+	dsQuery := func(refID string) Query {
+		return Query{
+			RefID: refID,
+			DataSource: &datasources.DataSource{
+				OrgID: 1,
+				UID:   "test",
+				Type:  "test",
+			},
+			JSON: json.RawMessage(`{ "datasource": { "uid": "1" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+		}
+	}
+	exprQuery := func(refID, model string) Query {
+		return Query{
+			RefID:      refID,
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(model),
+		}
+	}
+
+	resp := map[string]backend.DataResponse{
+		"A": {Frames: data.Frames{series("A", data.Labels{"host": "a"}, 1), series("A", data.Labels{"host": "b"}, 2)}},
+		"B": {Frames: data.Frames{series("B", data.Labels{"host": "a"}, 10)}},
+		"E": {Frames: data.Frames{}},
+	}
+	queries := []Query{
+		dsQuery("A"),
+		dsQuery("B"),
+		exprQuery("C", `{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$A + $B" }`),
+		dsQuery("E"),
+		exprQuery("F", `{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$E * 2" }`),
+	}
+
+	s, req := newMockQueryService(resp, queries)
+	pl, err := s.BuildPipeline(req)
+	require.NoError(t, err)
+
+	t.Run("does not explain by default", func(t *testing.T) {
+		_, err := s.ExecutePipeline(context.Background(), time.Now(), pl)
+		require.NoError(t, err)
+	})
+
+	explanation := &PipelineExplanation{}
+	res, err := s.ExecutePipeline(context.Background(), time.Now(), pl, WithExplain(explanation))
+	require.NoError(t, err)
+	require.Len(t, res.Responses, 5)
+	require.Len(t, explanation.Nodes, 5)
+
+	nodes := make(map[string]*NodeExplanation, len(explanation.Nodes))
+	for _, n := range explanation.Nodes {
+		nodes[n.RefID] = n
+	}
+
+	t.Run("data source node", func(t *testing.T) {
+		a := nodes["A"]
+		require.Equal(t, TypeDatasourceNode.String(), a.NodeType)
+		require.Empty(t, a.Command)
+		require.Equal(t, 0, a.InputSeries)
+		require.Equal(t, 2, a.OutputSeries)
+		require.Len(t, a.Samples, 2)
+		require.Empty(t, a.Error)
+	})
+
+	t.Run("union mismatch", func(t *testing.T) {
+		c := nodes["C"]
+		require.Equal(t, TypeCMDNode.String(), c.NodeType)
+		require.Equal(t, TypeMath.String(), c.Command)
+		require.ElementsMatch(t, []string{"A", "B"}, c.Inputs)
+		require.Equal(t, 3, c.InputSeries)
+		require.Equal(t, 1, c.OutputSeries)
+		require.Equal(t, 1, c.DroppedCount)
+		require.Equal(t, []DroppedSeries{{Labels: data.Labels{"host": "b"}, Reason: "no matching value for $A in union $A + $B"}}, c.Dropped)
+		require.Equal(t, []ValueSample{{Labels: data.Labels{"host": "a"}, Type: "seriesSet", Length: 1, Values: []string{"11"}}}, c.Samples)
+	})
+
+	t.Run("no data", func(t *testing.T) {
+		f := nodes["F"]
+		require.Equal(t, 0, f.InputSeries)
+		require.Equal(t, 0, f.OutputSeries)
+		require.Equal(t, []DroppedSeries{{Reason: "input E has no data"}}, f.Dropped)
+		require.Empty(t, f.Samples)
+	})
+}
+
+func TestFormatSampleValue(t *testing.T) {
+	require.Equal(t, "null", formatSampleValue(nil))
+	require.Equal(t, "1.5", formatSampleValue(fp(1.5)))
+}
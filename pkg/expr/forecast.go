@@ -0,0 +1,123 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/metrics"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// ForecastCommand is an expression command that fits a model over each time series
+// and predicts its value, or the time until it reaches a threshold.
+type ForecastCommand struct {
+	VarToForecast string
+	Params        mathexp.ForecastParams
+	refID         string
+}
+
+// NewForecastCommand creates a new ForecastCommand.
+func NewForecastCommand(refID, varToForecast string, params mathexp.ForecastParams) (*ForecastCommand, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return &ForecastCommand{
+		VarToForecast: varToForecast,
+		Params:        params,
+		refID:         refID,
+	}, nil
+}
+
+// UnmarshalForecastCommand creates a ForecastCommand from Grafana's frontend query.
+func UnmarshalForecastCommand(rn *rawNode) (*ForecastCommand, error) {
+	q := ForecastQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the forecast command: %w", err)
+	}
+	varToForecast, err := getReferenceVar(q.Expression, rn.RefID)
+	if err != nil {
+		return nil, err
+	}
+	params, err := q.params()
+	if err != nil {
+		return nil, err
+	}
+	return NewForecastCommand(rn.RefID, varToForecast, params)
+}
+
+func (q ForecastQuery) params() (mathexp.ForecastParams, error) {
+	params := mathexp.ForecastParams{
+		Model:       q.Model,
+		Output:      q.Output,
+		Threshold:   q.Threshold,
+		Direction:   q.Direction,
+		Smoothing:   mathexp.DefaultForecastSmoothing,
+		Trend:       mathexp.DefaultForecastTrend,
+		Seasonality: mathexp.DefaultForecastSeasonality,
+	}
+	if q.Horizon != "" {
+		horizon, err := gtime.ParseDuration(q.Horizon)
+		if err != nil {
+			return params, fmt.Errorf(`failed to parse forecast "horizon" duration field %q: %w`, q.Horizon, err)
+		}
+		params.Horizon = horizon
+	}
+	if q.Smoothing != nil {
+		params.Smoothing = *q.Smoothing
+	}
+	if q.Trend != nil {
+		params.Trend = *q.Trend
+	}
+	if q.SeasonLength != nil {
+		params.SeasonLength = *q.SeasonLength
+	}
+	if q.Seasonality != nil {
+		params.Seasonality = *q.Seasonality
+	}
+	return params, nil
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (gf *ForecastCommand) NeedsVars() []string {
+	return []string{gf.VarToForecast}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (gf *ForecastCommand) Execute(ctx context.Context, now time.Time, vars mathexp.Vars, tracer tracing.Tracer, _ *metrics.ExprMetrics) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteForecast")
+	defer span.End()
+	span.SetAttributes(attribute.String("model", string(gf.Params.Model)), attribute.String("output", string(gf.Params.Output)))
+
+	newRes := mathexp.Results{}
+	for _, val := range vars[gf.VarToForecast].Values {
+		if val == nil {
+			continue
+		}
+		switch v := val.(type) {
+		case mathexp.Series:
+			num, err := v.Forecast(gf.refID, now, gf.Params)
+			if err != nil {
+				return newRes, err
+			}
+			newRes.Values = append(newRes.Values, num)
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+			return newRes, nil
+		default:
+			return newRes, fmt.Errorf("can only forecast type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func (gf *ForecastCommand) Type() string {
+	return TypeForecast.String()
+}
@@ -0,0 +1,150 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func Test_UnmarshalForecastCommand(t *testing.T) {
+	var tests = []struct {
+		name           string
+		query          string
+		expectedError  string
+		expectedParams mathexp.ForecastParams
+	}{
+		{
+			name:  "linear value at horizon",
+			query: `{ "expression" : "$A", "model": "linear", "output": "value", "horizon": "1d" }`,
+			expectedParams: mathexp.ForecastParams{
+				Model:       mathexp.ForecastModelLinear,
+				Output:      mathexp.ForecastOutputValue,
+				Horizon:     24 * time.Hour,
+				Smoothing:   mathexp.DefaultForecastSmoothing,
+				Trend:       mathexp.DefaultForecastTrend,
+				Seasonality: mathexp.DefaultForecastSeasonality,
+			},
+		},
+		{
+			name:  "holt time to threshold",
+			query: `{ "expression" : "$A", "model": "holt", "output": "time_to_threshold", "threshold": 10, "direction": "below", "smoothing": 0.3, "trend": 0.1 }`,
+			expectedParams: mathexp.ForecastParams{
+				Model:       mathexp.ForecastModelHolt,
+				Output:      mathexp.ForecastOutputTimeToThreshold,
+				Threshold:   util.Pointer(10.0),
+				Direction:   mathexp.ForecastDirectionBelow,
+				Smoothing:   0.3,
+				Trend:       0.1,
+				Seasonality: mathexp.DefaultForecastSeasonality,
+			},
+		},
+		{
+			name:  "holt-winters value at horizon",
+			query: `{ "expression" : "$A", "model": "holt", "output": "value", "horizon": "1h", "seasonLength": 24, "seasonality": 0.2 }`,
+			expectedParams: mathexp.ForecastParams{
+				Model:        mathexp.ForecastModelHolt,
+				Output:       mathexp.ForecastOutputValue,
+				Horizon:      time.Hour,
+				Smoothing:    mathexp.DefaultForecastSmoothing,
+				Trend:        mathexp.DefaultForecastTrend,
+				SeasonLength: 24,
+				Seasonality:  0.2,
+			},
+		},
+		{
+			name:          "error when horizon is invalid",
+			query:         `{ "expression" : "$A", "model": "linear", "output": "value", "horizon": "tomorrow" }`,
+			expectedError: `failed to parse forecast "horizon" duration field`,
+		},
+		{
+			name:          "error when threshold is missing",
+			query:         `{ "expression" : "$A", "model": "linear", "output": "time_to_threshold" }`,
+			expectedError: "requires a threshold",
+		},
+		{
+			name:          "error when direction is invalid",
+			query:         `{ "expression" : "$A", "model": "linear", "output": "time_to_threshold", "threshold": 10, "direction": "up" }`,
+			expectedError: "forecast direction up not implemented",
+		},
+		{
+			name:          "error when linear model has a season length",
+			query:         `{ "expression" : "$A", "model": "linear", "output": "value", "seasonLength": 24 }`,
+			expectedError: "forecast model linear has no seasonality",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]any)
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+
+			cmd, err := UnmarshalForecastCommand(&rawNode{
+				RefID:    "B",
+				Query:    qmap,
+				QueryRaw: []byte(test.query),
+			})
+
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expectedParams, cmd.Params)
+			require.Equal(t, []string{"A"}, cmd.NeedsVars())
+		})
+	}
+}
+
+func TestForecastCommand_Execute(t *testing.T) {
+	varToForecast := util.GenerateShortUID()
+	cmd, err := NewForecastCommand(util.GenerateShortUID(), varToForecast, mathexp.ForecastParams{
+		Model:   mathexp.ForecastModelLinear,
+		Output:  mathexp.ForecastOutputValue,
+		Horizon: time.Hour,
+	})
+	require.NoError(t, err)
+
+	var tests = []struct {
+		name         string
+		vals         mathexp.Value
+		isError      bool
+		expectedType parse.ReturnType
+	}{
+		{
+			name:         "should forecast when input Series",
+			vals:         mathexp.NewSeries(varToForecast, nil, 10),
+			expectedType: parse.TypeNumberSet,
+		},
+		{
+			name:         "should return NoData when input NoData",
+			vals:         mathexp.NoData{},
+			expectedType: parse.TypeNoData,
+		}, {
+			name:    "should return error when input Number",
+			vals:    mathexp.NewNumber("test", nil),
+			isError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+				varToForecast: mathexp.Results{Values: mathexp.Values{test.vals}},
+			}, tracing.InitializeTracerForTest(), nil)
+			if test.isError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, result.Values, 1)
+				require.Equal(t, test.expectedType, result.Values[0].Type())
+			}
+		})
+	}
+}
@@ -58,13 +58,65 @@ type ExecutableNode interface {
 // DataPipeline is an ordered set of nodes returned from DPGraph processing.
 type DataPipeline []Node
 
+// dryRun executes the nodes of the pipeline that do not depend on a SQL expression,
+// then explains the SQL expressions against their inputs instead of running them.
+func (dp *DataPipeline) dryRun(c context.Context, now time.Time, s *Service) (map[string]SQLDryRun, error) {
+	skipped := map[string]bool{}
+	sqlNodes := []*CMDNode{}
+	runnable := DataPipeline{}
+	for _, node := range *dp {
+		if cmdNode, ok := node.(*CMDNode); ok && cmdNode.CMDType == TypeSQL {
+			sqlNodes = append(sqlNodes, cmdNode)
+			skipped[node.RefID()] = true
+			continue
+		}
+		if slices.ContainsFunc(node.NeedsVars(), func(v string) bool { return skipped[v] }) {
+			skipped[node.RefID()] = true
+			continue
+		}
+		runnable = append(runnable, node)
+	}
+
+	vars, err := runnable.execute(c, now, s, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]SQLDryRun, len(sqlNodes))
+	for _, node := range sqlNodes {
+		cmd, ok := node.Command.(*SQLCommand)
+		if !ok {
+			return nil, makeUnexpectedNodeTypeError(node.RefID(), node.NodeType().String())
+		}
+		var depErr error
+		for _, neededVar := range node.NeedsVars() {
+			if r, ok := vars[neededVar]; ok && r.Error != nil {
+				depErr = MakeDependencyError(node.RefID(), neededVar)
+				break
+			}
+		}
+		if depErr != nil {
+			res[node.RefID()] = SQLDryRun{Error: depErr}
+			continue
+		}
+		explanation, err := cmd.Explain(c, vars, s.tracer)
+		res[node.RefID()] = SQLDryRun{Explanation: explanation, Error: err}
+	}
+	return res, nil
+}
+
 // execute runs all the command/datasource requests in the pipeline return a
-// map of the refId of the of each command
-func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (mathexp.Vars, error) {
+// map of the refId of the of each command. If explanation is not nil, the execution
+// of every node is traced into it.
+func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service, explanation *PipelineExplanation) (mathexp.Vars, error) {
 	vars := make(mathexp.Vars)
+	if explanation != nil {
+		c = withExplanation(c, explanation)
+	}
 
 	groupByDSFlag := s.features.IsEnabled(c, featuremgmt.FlagSseGroupByDatasource)
 	// Execute datasource nodes first, and grouped by datasource.
+	var groupDuration time.Duration
 	if groupByDSFlag {
 		dsNodes := []*DSNode{}
 		for _, node := range *dp {
@@ -74,11 +126,16 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			dsNodes = append(dsNodes, node.(*DSNode))
 		}
 
+		start := time.Now()
 		executeDSNodesGrouped(c, now, vars, s, dsNodes)
+		groupDuration = time.Since(start)
 	}
 
 	for _, node := range *dp {
 		if groupByDSFlag && node.NodeType() == TypeDatasourceNode {
+			if explanation != nil {
+				explanation.newNode(node, vars).finish(vars[node.RefID()], groupDuration)
+			}
 			continue // already executed via executeDSNodesGrouped
 		}
 
@@ -92,6 +149,9 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 					}
 					vars[node.RefID()] = errResult
 					hasDepError = true
+					if explanation != nil {
+						explanation.newNode(node, vars).finish(errResult, 0)
+					}
 					break
 				}
 			}
@@ -113,10 +173,19 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			return vars, makeUnexpectedNodeTypeError(node.RefID(), node.NodeType().String())
 		}
 
+		var nodeExplanation *NodeExplanation
+		if explanation != nil {
+			nodeExplanation = explanation.newNode(node, vars)
+		}
+
+		start := time.Now()
 		res, err := execNode.Execute(c, now, vars, s)
 		if err != nil {
 			res.Error = err
 		}
+		if nodeExplanation != nil {
+			nodeExplanation.finish(res, time.Since(start))
+		}
 
 		vars[node.RefID()] = res
 	}
@@ -287,7 +356,7 @@ func (s *Service) buildGraph(req *Request) (*simple.DirectedGraph, error) {
 		case TypeDatasourceNode:
 			node, err = s.buildDSNode(dp, rn, req)
 		case TypeCMDNode:
-			node, err = buildCMDNode(rn, s.features, s.cfg)
+			node, err = buildCMDNode(rn, s.features, s.cfg, s.sqlMacros)
 		case TypeMLNode:
 			if s.features.IsEnabledGlobally(featuremgmt.FlagMlExpressions) {
 				node, err = s.buildMLNode(dp, rn, req)
@@ -0,0 +1,77 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/metrics"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// JoinCommand is an expression command that pairs the results of two variables
+// by explicit label matching and applies a binary operator to each pair.
+type JoinCommand struct {
+	LeftVar  string
+	RightVar string
+	Spec     mathexp.JoinSpec
+	refID    string
+}
+
+// NewJoinCommand creates a new JoinCommand.
+func NewJoinCommand(refID, leftVar, rightVar string, spec mathexp.JoinSpec) (*JoinCommand, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &JoinCommand{
+		LeftVar:  leftVar,
+		RightVar: rightVar,
+		Spec:     spec,
+		refID:    refID,
+	}, nil
+}
+
+// UnmarshalJoinCommand creates a JoinCommand from Grafana's frontend query.
+func UnmarshalJoinCommand(rn *rawNode) (*JoinCommand, error) {
+	q := JoinQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the join command: %w", err)
+	}
+	leftVar, err := getReferenceVar(q.Left, rn.RefID)
+	if err != nil {
+		return nil, err
+	}
+	rightVar, err := getReferenceVar(q.Right, rn.RefID)
+	if err != nil {
+		return nil, err
+	}
+	return NewJoinCommand(rn.RefID, leftVar, rightVar, q.spec())
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (gj *JoinCommand) NeedsVars() []string {
+	return []string{gj.LeftVar, gj.RightVar}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (gj *JoinCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer, _ *metrics.ExprMetrics) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteJoin")
+	defer span.End()
+	span.SetAttributes(attribute.String("operator", gj.Spec.Operator), attribute.String("cardinality", string(gj.Spec.Cardinality)))
+
+	res, err := mathexp.Join(gj.refID, vars[gj.LeftVar], vars[gj.RightVar], gj.Spec)
+	if err != nil {
+		return res, fmt.Errorf("failed to join '%s' and '%s': %w", gj.LeftVar, gj.RightVar, err)
+	}
+	return res, nil
+}
+
+func (gj *JoinCommand) Type() string {
+	return TypeJoin.String()
+}
@@ -0,0 +1,106 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestUnmarshalJoinCommand(t *testing.T) {
+	var tests = []struct {
+		name          string
+		query         string
+		expectedError string
+		expected      *JoinCommand
+	}{
+		{
+			name:  "join on labels",
+			query: `{ "type": "join", "left": "$A", "right": "$B", "operator": "/", "on": ["host"] }`,
+			expected: &JoinCommand{
+				LeftVar:  "A",
+				RightVar: "B",
+				Spec:     mathexp.JoinSpec{Operator: "/", On: []string{"host"}},
+				refID:    "C",
+			},
+		},
+		{
+			name:  "group left join with included labels",
+			query: `{ "type": "join", "left": "A", "right": "B", "operator": "*", "ignoring": ["pod"], "cardinality": "group_left", "include": ["region"] }`,
+			expected: &JoinCommand{
+				LeftVar:  "A",
+				RightVar: "B",
+				Spec:     mathexp.JoinSpec{Operator: "*", Ignoring: []string{"pod"}, Cardinality: mathexp.JoinGroupLeft, Include: []string{"region"}},
+				refID:    "C",
+			},
+		},
+		{
+			name:          "error when right is missing",
+			query:         `{ "type": "join", "left": "$A", "operator": "/" }`,
+			expectedError: "no variable specified to reference for refId C",
+		},
+		{
+			name:          "error when cardinality is unknown",
+			query:         `{ "type": "join", "left": "$A", "right": "$B", "operator": "/", "cardinality": "many_to_many" }`,
+			expectedError: "unsupported join cardinality 'many_to_many'",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]any)
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+
+			cmd, err := UnmarshalJoinCommand(&rawNode{
+				RefID:    "C",
+				Query:    qmap,
+				QueryRaw: []byte(test.query),
+			})
+
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, cmd)
+			require.Equal(t, []string{test.expected.LeftVar, test.expected.RightVar}, cmd.NeedsVars())
+		})
+	}
+}
+
+func TestJoinCommandExecute(t *testing.T) {
+	cmd, err := NewJoinCommand("C", "A", "B", mathexp.JoinSpec{Operator: "/", On: []string{"host"}})
+	require.NoError(t, err)
+
+	newNumber := func(refID string, labels data.Labels, f float64) mathexp.Number {
+		n := mathexp.NewNumber(refID, labels)
+		n.SetValue(util.Pointer(f))
+		return n
+	}
+
+	t.Run("should divide matched values", func(t *testing.T) {
+		vars := mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{newNumber("A", data.Labels{"host": "a", "code": "500"}, 5)}},
+			"B": mathexp.Results{Values: mathexp.Values{newNumber("B", data.Labels{"host": "a"}, 10)}},
+		}
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest(), nil)
+		require.NoError(t, err)
+		require.Equal(t, mathexp.Values{newNumber("C", data.Labels{"host": "a"}, 0.5)}, res.Values)
+	})
+
+	t.Run("should explain unmatched values", func(t *testing.T) {
+		vars := mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{newNumber("A", data.Labels{"host": "a"}, 5)}},
+			"B": mathexp.Results{Values: mathexp.Values{newNumber("B", data.Labels{"host": "b"}, 10)}},
+		}
+		_, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest(), nil)
+		require.ErrorContains(t, err, "failed to join 'A' and 'B': join has unmatched values: left {host=a}, right {host=b}")
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"reflect"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -61,6 +62,28 @@ func (e *Expr) Execute(refID string, vars Vars, tracer tracing.Tracer) (r Result
 	return e.executeState(s)
 }
 
+// Drop is a value that was dropped from the union of a binary operation because it did not match any value of the other side.
+type Drop struct {
+	// Union is the binary operation, such as "$A + $B".
+	Union string
+	// Input is the side of the union the value comes from, such as "$A".
+	Input  string
+	Labels data.Labels
+}
+
+// ExecuteWithDrops is like Execute, and also returns the values that were dropped from unions.
+func (e *Expr) ExecuteWithDrops(refID string, vars Vars, tracer tracing.Tracer) (Results, []Drop, error) {
+	s := &State{
+		Expr:  e,
+		Vars:  vars,
+		RefID: refID,
+
+		tracer: tracer,
+	}
+	r, err := e.executeState(s)
+	return r, s.drops(), err
+}
+
 func (e *Expr) executeState(s *State) (r Results, err error) {
 	defer errRecover(&err, s)
 	r, err = s.walk(e.Root)
@@ -318,82 +341,90 @@ func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	}
 	unions := e.union(ar, br, node)
 	for _, uni := range unions {
-		var value Value
-		switch at := uni.A.(type) {
+		value, err := e.biValues(uni.Labels, node.OpStr, uni.A, uni.B)
+		if err != nil {
+			return res, err
+		}
+		res.Values = append(res.Values, value)
+	}
+	return res, nil
+}
+
+// biValues performs the binary operation op between the values a and b,
+// and sets the given labels on the result.
+func (e *State) biValues(labels data.Labels, op string, a, b Value) (Value, error) {
+	var value Value
+	var err error
+	switch at := a.(type) {
+	case Scalar:
+		aFloat := at.GetFloat64Value()
+		switch bt := b.(type) {
+		// Scalar op Scalar
 		case Scalar:
-			aFloat := at.GetFloat64Value()
-			switch bt := uni.B.(type) {
-			// Scalar op Scalar
-			case Scalar:
-				bFloat := bt.GetFloat64Value()
-				if aFloat == nil || bFloat == nil {
-					value = NewScalar(e.RefID, nil)
-					break
-				}
-				f := math.NaN()
-				if aFloat != nil && bFloat != nil {
-					f, err = binaryOp(node.OpStr, *aFloat, *bFloat)
-					if err != nil {
-						return res, err
-					}
+			bFloat := bt.GetFloat64Value()
+			if aFloat == nil || bFloat == nil {
+				value = NewScalar(e.RefID, nil)
+				break
+			}
+			f := math.NaN()
+			if aFloat != nil && bFloat != nil {
+				f, err = binaryOp(op, *aFloat, *bFloat)
+				if err != nil {
+					return value, err
 				}
-				value = NewScalar(e.RefID, &f)
-			// Scalar op Scalar
-			case Number:
-				value, err = e.biScalarNumber(uni.Labels, node.OpStr, bt, aFloat, false)
-			// Scalar op Series
-			case Series:
-				value, err = e.biSeriesNumber(uni.Labels, node.OpStr, bt, aFloat, false)
-			case NoData:
-				value = uni.B
-			default:
-				return res, fmt.Errorf("not implemented: binary %v on %T and %T", node.OpStr, uni.A, uni.B)
 			}
+			value = NewScalar(e.RefID, &f)
+		// Scalar op Scalar
+		case Number:
+			value, err = e.biScalarNumber(labels, op, bt, aFloat, false)
+		// Scalar op Series
 		case Series:
-			switch bt := uni.B.(type) {
-			// Series Op Scalar
-			case Scalar:
-				bFloat := bt.GetFloat64Value()
-				value, err = e.biSeriesNumber(uni.Labels, node.OpStr, at, bFloat, true)
-			// case Series Op Number
-			case Number:
-				bFloat := bt.GetFloat64Value()
-				value, err = e.biSeriesNumber(uni.Labels, node.OpStr, at, bFloat, true)
-			// case Series op Series
-			case Series:
-				value, err = e.biSeriesSeries(uni.Labels, node.OpStr, at, bt)
-			case NoData:
-				value = uni.B
-			default:
-				return res, fmt.Errorf("not implemented: binary %v on %T and %T", node.OpStr, uni.A, uni.B)
-			}
+			value, err = e.biSeriesNumber(labels, op, bt, aFloat, false)
+		case NoData:
+			value = b
+		default:
+			return value, fmt.Errorf("not implemented: binary %v on %T and %T", op, a, b)
+		}
+	case Series:
+		switch bt := b.(type) {
+		// Series Op Scalar
+		case Scalar:
+			bFloat := bt.GetFloat64Value()
+			value, err = e.biSeriesNumber(labels, op, at, bFloat, true)
+		// case Series Op Number
 		case Number:
-			aFloat := at.GetFloat64Value()
-			switch bt := uni.B.(type) {
-			case Scalar:
-				bFloat := bt.GetFloat64Value()
-				value, err = e.biScalarNumber(uni.Labels, node.OpStr, at, bFloat, true)
-			case Number:
-				bFloat := bt.GetFloat64Value()
-				value, err = e.biScalarNumber(uni.Labels, node.OpStr, at, bFloat, true)
-			case Series:
-				value, err = e.biSeriesNumber(uni.Labels, node.OpStr, bt, aFloat, false)
-			case NoData:
-				value = uni.B
-			default:
-				return res, fmt.Errorf("not implemented: binary %v on %T and %T", node.OpStr, uni.A, uni.B)
-			}
+			bFloat := bt.GetFloat64Value()
+			value, err = e.biSeriesNumber(labels, op, at, bFloat, true)
+		// case Series op Series
+		case Series:
+			value, err = e.biSeriesSeries(labels, op, at, bt)
 		case NoData:
-			value = uni.A
+			value = b
 		default:
-			return res, fmt.Errorf("not implemented: binary %v on %T and %T", node.OpStr, uni.A, uni.B)
+			return value, fmt.Errorf("not implemented: binary %v on %T and %T", op, a, b)
 		}
-		if err != nil {
-			return res, err
+	case Number:
+		aFloat := at.GetFloat64Value()
+		switch bt := b.(type) {
+		case Scalar:
+			bFloat := bt.GetFloat64Value()
+			value, err = e.biScalarNumber(labels, op, at, bFloat, true)
+		case Number:
+			bFloat := bt.GetFloat64Value()
+			value, err = e.biScalarNumber(labels, op, at, bFloat, true)
+		case Series:
+			value, err = e.biSeriesNumber(labels, op, bt, aFloat, false)
+		case NoData:
+			value = b
+		default:
+			return value, fmt.Errorf("not implemented: binary %v on %T and %T", op, a, b)
 		}
-		res.Values = append(res.Values, value)
+	case NoData:
+		value = a
+	default:
+		return value, fmt.Errorf("not implemented: binary %v on %T and %T", op, a, b)
 	}
-	return res, nil
+	return value, err
 }
 
 // binaryOp performs a binary operations (e.g. A+B or A>B) on two
@@ -569,6 +600,8 @@ func (e *State) walkFunc(node *parse.FuncNode) (Results, error) {
 			v = e.Vars[t.Name]
 		case *parse.ScalarNode:
 			v = NewScalarResults(e.RefID, &t.Float64)
+		case *parse.DurationNode:
+			v = t.Duration
 		case *parse.FuncNode:
 			v, err = e.walkFunc(t)
 		case *parse.UnaryNode:
@@ -599,6 +632,29 @@ func (e *State) walkFunc(node *parse.FuncNode) (Results, error) {
 	return res, nil
 }
 
+// drops returns the values dropped from unions, sorted by union and input.
+func (e *State) drops() []Drop {
+	var drops []Drop
+	unions := make([]string, 0, len(e.Drops))
+	for u := range e.Drops {
+		unions = append(unions, u)
+	}
+	sort.Strings(unions)
+	for _, u := range unions {
+		inputs := make([]string, 0, len(e.Drops[u]))
+		for input := range e.Drops[u] {
+			inputs = append(inputs, input)
+		}
+		sort.Strings(inputs)
+		for _, input := range inputs {
+			for _, labels := range e.Drops[u][input] {
+				drops = append(drops, Drop{Union: u, Input: input, Labels: labels})
+			}
+		}
+	}
+	return drops
+}
+
 func (e *State) addDropNotices(r *Results) {
 	nT := strings.Builder{}
 
@@ -0,0 +1,315 @@
+package mathexp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// The forecasting model
+// +enum
+type ForecastModel string
+
+const (
+	// Least squares linear regression
+	ForecastModelLinear ForecastModel = "linear"
+
+	// Holt's exponential smoothing of the level and the trend, and of the seasonality when a season length is set (Holt-Winters)
+	ForecastModelHolt ForecastModel = "holt"
+)
+
+// The value returned by a forecast
+// +enum
+type ForecastOutput string
+
+const (
+	// The predicted value at now + horizon
+	ForecastOutputValue ForecastOutput = "value"
+
+	// The number of seconds from now until the predicted value reaches the threshold
+	ForecastOutputTimeToThreshold ForecastOutput = "time_to_threshold"
+)
+
+// The side of the threshold that the predicted value reaches
+// +enum
+type ForecastDirection string
+
+const (
+	// The threshold is reached when the value is greater than or equal to it
+	ForecastDirectionAbove ForecastDirection = "above"
+
+	// The threshold is reached when the value is less than or equal to it
+	ForecastDirectionBelow ForecastDirection = "below"
+)
+
+const (
+	// DefaultForecastSmoothing is the smoothing factor of the level used by the Holt model if none is configured.
+	DefaultForecastSmoothing = 0.5
+	// DefaultForecastTrend is the smoothing factor of the trend used by the Holt model if none is configured.
+	DefaultForecastTrend = 0.5
+	// DefaultForecastSeasonality is the smoothing factor of the seasonality used by the Holt model if none is configured.
+	DefaultForecastSeasonality = 0.5
+)
+
+// ForecastParams configures Series.Forecast.
+type ForecastParams struct {
+	Model  ForecastModel
+	Output ForecastOutput
+	// Horizon is the duration after now at which the value is predicted. Only used by ForecastOutputValue.
+	Horizon time.Duration
+	// Threshold is the value whose crossing time is estimated. Only used by ForecastOutputTimeToThreshold.
+	Threshold *float64
+	// Direction is the side of the threshold that is reached. Only used by ForecastOutputTimeToThreshold, defaults to ForecastDirectionAbove.
+	Direction ForecastDirection
+	// Smoothing and Trend are the smoothing factors of the Holt model, between 0 and 1 (exclusive).
+	Smoothing float64
+	Trend     float64
+	// SeasonLength is the number of values in a season. If set, the Holt model also smooths the seasonality
+	// with the factor Seasonality, between 0 and 1 (exclusive). The Series must have at least two seasons of values.
+	SeasonLength int
+	Seasonality  float64
+}
+
+// Validate returns an error if the parameters cannot be used to forecast.
+func (p ForecastParams) Validate() error {
+	switch p.Model {
+	case ForecastModelLinear:
+	case ForecastModelHolt:
+		if p.Smoothing <= 0 || p.Smoothing >= 1 {
+			return fmt.Errorf("smoothing factor must be between 0 and 1, got %v", p.Smoothing)
+		}
+		if p.Trend <= 0 || p.Trend >= 1 {
+			return fmt.Errorf("trend factor must be between 0 and 1, got %v", p.Trend)
+		}
+		if p.SeasonLength < 0 {
+			return fmt.Errorf("season length must not be negative, got %d", p.SeasonLength)
+		}
+		if p.SeasonLength > 0 && (p.Seasonality <= 0 || p.Seasonality >= 1) {
+			return fmt.Errorf("seasonality factor must be between 0 and 1, got %v", p.Seasonality)
+		}
+	default:
+		return fmt.Errorf("forecast model %v not implemented", p.Model)
+	}
+	if p.Model != ForecastModelHolt && p.SeasonLength != 0 {
+		return fmt.Errorf("forecast model %v has no seasonality", p.Model)
+	}
+	switch p.Output {
+	case ForecastOutputValue:
+		if p.Horizon < 0 {
+			return fmt.Errorf("forecast horizon must not be negative, got %s", p.Horizon)
+		}
+	case ForecastOutputTimeToThreshold:
+		if p.Threshold == nil {
+			return errors.New("forecast output time_to_threshold requires a threshold")
+		}
+		switch p.Direction {
+		case "", ForecastDirectionAbove, ForecastDirectionBelow:
+		default:
+			return fmt.Errorf("forecast direction %v not implemented", p.Direction)
+		}
+	default:
+		return fmt.Errorf("forecast output %v not implemented", p.Output)
+	}
+	return nil
+}
+
+// Forecast fits the model of the parameters over the Series and turns it into a Number,
+// which is either the predicted value at now+horizon or the number of seconds until the threshold is reached.
+// Null and NaN values are ignored. The result is NaN if the Series has less than two values.
+// It is an error to use a season length if the Series has less than two seasons of values.
+// The time to threshold is 0 if the value at now already reached the threshold, and NaN if the predicted value never does.
+func (s Series) Forecast(refID string, now time.Time, params ForecastParams) (Number, error) {
+	var l data.Labels
+	if s.GetLabels() != nil {
+		l = s.GetLabels().Copy()
+	}
+	number := NewNumber(refID, l)
+	if err := params.Validate(); err != nil {
+		return number, err
+	}
+
+	times := make([]float64, 0, s.Len())
+	values := make([]float64, 0, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, v := s.GetPoint(i)
+		if v == nil || math.IsNaN(*v) {
+			continue
+		}
+		// seconds relative to now keep the intercept of the model at now.
+		times = append(times, t.Sub(now).Seconds())
+		values = append(values, *v)
+	}
+
+	nan := math.NaN()
+	if len(values) < 2 {
+		number.SetValue(&nan)
+		return number, nil
+	}
+
+	if params.Model == ForecastModelHolt && params.SeasonLength > 0 {
+		if len(values) < 2*params.SeasonLength {
+			return number, fmt.Errorf("forecast with a season length of %d requires at least %d values, got %d", params.SeasonLength, 2*params.SeasonLength, len(values))
+		}
+		f := holtWinters(times, values, params)
+		number.SetValue(&f)
+		return number, nil
+	}
+
+	var atNow, slope float64
+	switch params.Model {
+	case ForecastModelHolt:
+		atNow, slope = holt(times, values, params.Smoothing, params.Trend)
+	default:
+		atNow, slope = linearRegression(times, values)
+	}
+
+	var f float64
+	switch params.Output {
+	case ForecastOutputTimeToThreshold:
+		f = timeToThreshold(atNow, slope, *params.Threshold, params.Direction)
+	default:
+		f = atNow + slope*params.Horizon.Seconds()
+	}
+	number.SetValue(&f)
+	return number, nil
+}
+
+// linearRegression returns the intercept at 0 and the slope of the least squares line fitting the points.
+func linearRegression(times, values []float64) (float64, float64) {
+	n := float64(len(times))
+	var sumT, sumV, sumTV, sumTT float64
+	for i := range times {
+		sumT += times[i]
+		sumV += values[i]
+		sumTV += times[i] * values[i]
+		sumTT += times[i] * times[i]
+	}
+	cov := sumTV - sumT*sumV/n
+	variance := sumTT - sumT*sumT/n
+	if variance == 0 {
+		return math.NaN(), math.NaN()
+	}
+	slope := cov / variance
+	intercept := sumV/n - slope*sumT/n
+	return intercept, slope
+}
+
+// timeToThreshold returns the number of seconds until the line of intercept atNow and slope reaches the threshold
+// from the given direction. It is 0 if the threshold is already reached, and NaN if the line moves away from it.
+func timeToThreshold(atNow, slope, threshold float64, direction ForecastDirection) float64 {
+	if math.IsNaN(atNow) || math.IsNaN(slope) {
+		return math.NaN()
+	}
+	if direction == ForecastDirectionBelow {
+		if atNow <= threshold {
+			return 0
+		}
+		if slope >= 0 {
+			return math.NaN()
+		}
+	} else {
+		if atNow >= threshold {
+			return 0
+		}
+		if slope <= 0 {
+			return math.NaN()
+		}
+	}
+	return (threshold - atNow) / slope
+}
+
+// holt smooths the points, in order, with the smoothing factors sf and tf of the level and the trend.
+// It returns the level projected at 0 and the trend per second.
+func holt(times, values []float64, sf, tf float64) (float64, float64) {
+	level := values[0]
+	trend := values[1] - values[0]
+	for i := 1; i < len(values); i++ {
+		prev := level
+		level = sf*values[i] + (1-sf)*(level+trend)
+		trend = tf*(level-prev) + (1-tf)*trend
+	}
+	last := len(times) - 1
+	interval := (times[last] - times[0]) / float64(last)
+	if interval <= 0 {
+		return math.NaN(), math.NaN()
+	}
+	perSecond := trend / interval
+	return level - perSecond*times[last], perSecond
+}
+
+// holtWinters smooths the points, in order, with Holt-Winters' additive triple exponential smoothing of the level,
+// the trend and the seasonality, and returns the output of the parameters. Steps are the average interval between
+// points, and the seasonal component of a step is the one of the phase of the season it falls in.
+func holtWinters(times, values []float64, params ForecastParams) float64 {
+	m := params.SeasonLength
+	last := len(times) - 1
+	interval := (times[last] - times[0]) / float64(last)
+	if interval <= 0 {
+		return math.NaN()
+	}
+
+	// The averages of the first two seasons initialize the trend, and the first season, without the trend,
+	// the seasonal components and the level at its last point.
+	var first, second float64
+	for i := 0; i < m; i++ {
+		first += values[i]
+		second += values[m+i]
+	}
+	trend := (second - first) / float64(m*m)
+	center := float64(m-1) / 2
+	season := make([]float64, m)
+	for i := 0; i < m; i++ {
+		season[i] = values[i] - (first/float64(m) + (float64(i)-center)*trend)
+	}
+	level := first/float64(m) + center*trend
+	for i := m; i < len(values); i++ {
+		prev := level
+		level = params.Smoothing*(values[i]-season[i%m]) + (1-params.Smoothing)*(level+trend)
+		trend = params.Trend*(level-prev) + (1-params.Trend)*trend
+		season[i%m] = params.Seasonality*(values[i]-level) + (1-params.Seasonality)*season[i%m]
+	}
+
+	// predict returns the value k steps after the last point.
+	predict := func(k float64) float64 {
+		phase := ((last+int(math.Round(k)))%m + m) % m
+		return level + k*trend + season[phase]
+	}
+	// the number of steps from the last point to now.
+	toNow := -times[last] / interval
+	if params.Output != ForecastOutputTimeToThreshold {
+		return predict(toNow + params.Horizon.Seconds()/interval)
+	}
+
+	// Values below the threshold are negated so that the threshold is always reached from below.
+	sign := 1.0
+	if params.Direction == ForecastDirectionBelow {
+		sign = -1
+	}
+	threshold := sign * *params.Threshold
+	if sign*predict(toNow) >= threshold {
+		return 0
+	}
+	// For each phase of the season, find the first step after now in that phase at which the threshold is reached.
+	start := math.Ceil(toNow)
+	steps := math.Inf(1)
+	for j := 0; j < m; j++ {
+		k := start + float64(j)
+		v := sign * predict(k)
+		if v < threshold {
+			t := sign * trend
+			if t <= 0 {
+				continue
+			}
+			// the value of the phase increases by t*m every season.
+			k += math.Ceil((threshold-v)/(t*float64(m))) * float64(m)
+		}
+		steps = math.Min(steps, k)
+	}
+	if math.IsInf(steps, 1) {
+		return math.NaN()
+	}
+	return (steps - toNow) * interval
+}
@@ -0,0 +1,190 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesForecast(t *testing.T) {
+	now := time.Unix(3600, 0)
+	increasing := makeSeries("A", data.Labels{"mountpoint": "/"}, tp{
+		now.Add(-3 * time.Minute), float64Pointer(10),
+	}, tp{
+		now.Add(-2 * time.Minute), float64Pointer(20),
+	}, tp{
+		now.Add(-1 * time.Minute), nil,
+	}, tp{
+		now, float64Pointer(40),
+	})
+	regular := makeSeries("A", data.Labels{"mountpoint": "/"}, tp{
+		now.Add(-3 * time.Minute), float64Pointer(10),
+	}, tp{
+		now.Add(-2 * time.Minute), float64Pointer(20),
+	}, tp{
+		now.Add(-1 * time.Minute), float64Pointer(30),
+	}, tp{
+		now, float64Pointer(40),
+	})
+
+	// a flat series with a season of 4 values, from 7 minutes ago to now.
+	seasonal := makeSeasonalSeries(now, 8, func(i int) float64 { return 10 + []float64{0, 10, 0, -10}[i%4] })
+	// the same season on top of a trend of 2 per minute, from 11 minutes ago to now.
+	seasonalTrend := makeSeasonalSeries(now, 12, func(i int) float64 { return float64(2*i) + []float64{0, 10, 0, -10}[i%4] })
+	holtWinters := ForecastParams{Model: ForecastModelHolt, Smoothing: 0.5, Trend: 0.5, SeasonLength: 4, Seasonality: 0.5}
+	withOutput := func(p ForecastParams, output ForecastOutput, horizon time.Duration, threshold *float64, direction ForecastDirection) ForecastParams {
+		p.Output, p.Horizon, p.Threshold, p.Direction = output, horizon, threshold, direction
+		return p
+	}
+
+	var tests = []struct {
+		name     string
+		series   Series
+		params   ForecastParams
+		expected float64
+	}{
+		{
+			name:     "linear value at horizon",
+			series:   increasing,
+			params:   ForecastParams{Model: ForecastModelLinear, Output: ForecastOutputValue, Horizon: time.Hour},
+			expected: 640,
+		},
+		{
+			name:     "linear value at now",
+			series:   increasing,
+			params:   ForecastParams{Model: ForecastModelLinear, Output: ForecastOutputValue},
+			expected: 40,
+		},
+		{
+			name:     "linear time to threshold",
+			series:   increasing,
+			params:   ForecastParams{Model: ForecastModelLinear, Output: ForecastOutputTimeToThreshold, Threshold: float64Pointer(100)},
+			expected: 360,
+		},
+		{
+			name:     "linear time to threshold that is never reached",
+			series:   increasing,
+			params:   ForecastParams{Model: ForecastModelLinear, Output: ForecastOutputTimeToThreshold, Threshold: float64Pointer(0), Direction: ForecastDirectionBelow},
+			expected: math.NaN(),
+		},
+		{
+			name:     "linear time to threshold that is already reached",
+			series:   increasing,
+			params:   ForecastParams{Model: ForecastModelLinear, Output: ForecastOutputTimeToThreshold, Threshold: float64Pointer(20)},
+			expected: 0,
+		},
+		{
+			name:     "linear time to threshold below that is already reached",
+			series:   increasing,
+			params:   ForecastParams{Model: ForecastModelLinear, Output: ForecastOutputTimeToThreshold, Threshold: float64Pointer(100), Direction: ForecastDirectionBelow},
+			expected: 0,
+		},
+		{
+			name:     "linear time to threshold of a flat series",
+			series:   makeSeries("A", data.Labels{"mountpoint": "/"}, tp{now.Add(-time.Minute), float64Pointer(10)}, tp{now, float64Pointer(10)}),
+			params:   ForecastParams{Model: ForecastModelLinear, Output: ForecastOutputTimeToThreshold, Threshold: float64Pointer(100)},
+			expected: math.NaN(),
+		},
+		{
+			name:     "holt value at horizon",
+			series:   regular,
+			params:   ForecastParams{Model: ForecastModelHolt, Output: ForecastOutputValue, Horizon: time.Hour, Smoothing: 0.5, Trend: 0.5},
+			expected: 640,
+		},
+		{
+			name:     "holt time to threshold",
+			series:   regular,
+			params:   ForecastParams{Model: ForecastModelHolt, Output: ForecastOutputTimeToThreshold, Threshold: float64Pointer(100), Smoothing: 0.3, Trend: 0.1},
+			expected: 360,
+		},
+		{
+			name:     "holt-winters value at now",
+			series:   seasonal,
+			params:   withOutput(holtWinters, ForecastOutputValue, 0, nil, ""),
+			expected: 0,
+		},
+		{
+			name:     "holt-winters value at the next phase of the season",
+			series:   seasonal,
+			params:   withOutput(holtWinters, ForecastOutputValue, 2*time.Minute, nil, ""),
+			expected: 20,
+		},
+		{
+			name:     "holt-winters value at horizon with a trend",
+			series:   seasonalTrend,
+			params:   withOutput(holtWinters, ForecastOutputValue, time.Hour, nil, ""),
+			expected: 132,
+		},
+		{
+			name:     "holt-winters time to threshold reached by the season",
+			series:   seasonal,
+			params:   withOutput(holtWinters, ForecastOutputTimeToThreshold, 0, float64Pointer(15), ""),
+			expected: 120,
+		},
+		{
+			name:     "holt-winters time to threshold reached by the trend and the season",
+			series:   seasonalTrend,
+			params:   withOutput(holtWinters, ForecastOutputTimeToThreshold, 0, float64Pointer(100), ""),
+			expected: 2040,
+		},
+		{
+			name:     "holt-winters time to threshold that is never reached",
+			series:   seasonalTrend,
+			params:   withOutput(holtWinters, ForecastOutputTimeToThreshold, 0, float64Pointer(-20), ForecastDirectionBelow),
+			expected: math.NaN(),
+		},
+		{
+			name:     "not enough points",
+			series:   makeSeries("A", nil, tp{now, float64Pointer(1)}),
+			params:   ForecastParams{Model: ForecastModelLinear, Output: ForecastOutputValue, Horizon: time.Hour},
+			expected: math.NaN(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			number, err := tt.series.Forecast("B", now, tt.params)
+			require.NoError(t, err)
+			require.Equal(t, tt.series.GetLabels(), number.GetLabels())
+			f := number.GetFloat64Value()
+			require.NotNil(t, f)
+			if math.IsNaN(tt.expected) {
+				require.True(t, math.IsNaN(*f), "expected NaN but got %v", *f)
+				return
+			}
+			require.InDelta(t, tt.expected, *f, 1e-9)
+		})
+	}
+}
+
+func TestSeriesForecastSeasonLength(t *testing.T) {
+	now := time.Unix(3600, 0)
+	series := makeSeasonalSeries(now, 7, func(i int) float64 { return float64(i % 4) })
+	params := ForecastParams{Model: ForecastModelHolt, Output: ForecastOutputValue, Smoothing: 0.5, Trend: 0.5, SeasonLength: 4, Seasonality: 0.5}
+	_, err := series.Forecast("B", now, params)
+	require.ErrorContains(t, err, "requires at least 8 values, got 7")
+}
+
+// makeSeasonalSeries returns a series of n values, one per minute until now.
+func makeSeasonalSeries(now time.Time, n int, value func(i int) float64) Series {
+	points := make([]tp, 0, n)
+	for i := 0; i < n; i++ {
+		points = append(points, tp{now.Add(-time.Duration(n-1-i) * time.Minute), float64Pointer(value(i))})
+	}
+	return makeSeries("A", data.Labels{"mountpoint": "/"}, points...)
+}
+
+func TestForecastParamsValidate(t *testing.T) {
+	require.NoError(t, ForecastParams{Model: ForecastModelLinear, Output: ForecastOutputValue}.Validate())
+	require.ErrorContains(t, ForecastParams{Model: "arima", Output: ForecastOutputValue}.Validate(), "forecast model arima not implemented")
+	require.ErrorContains(t, ForecastParams{Model: ForecastModelLinear, Output: "slope"}.Validate(), "forecast output slope not implemented")
+	require.ErrorContains(t, ForecastParams{Model: ForecastModelLinear, Output: ForecastOutputTimeToThreshold}.Validate(), "requires a threshold")
+	require.ErrorContains(t, ForecastParams{Model: ForecastModelLinear, Output: ForecastOutputTimeToThreshold, Threshold: float64Pointer(1), Direction: "sideways"}.Validate(), "forecast direction sideways not implemented")
+	require.ErrorContains(t, ForecastParams{Model: ForecastModelHolt, Output: ForecastOutputValue, Smoothing: 1, Trend: 0.5}.Validate(), "smoothing factor")
+	require.ErrorContains(t, ForecastParams{Model: ForecastModelHolt, Output: ForecastOutputValue, Smoothing: 0.5}.Validate(), "trend factor")
+	require.ErrorContains(t, ForecastParams{Model: ForecastModelHolt, Output: ForecastOutputValue, Smoothing: 0.5, Trend: 0.5, SeasonLength: -1}.Validate(), "season length must not be negative")
+	require.ErrorContains(t, ForecastParams{Model: ForecastModelHolt, Output: ForecastOutputValue, Smoothing: 0.5, Trend: 0.5, SeasonLength: 4}.Validate(), "seasonality factor")
+	require.ErrorContains(t, ForecastParams{Model: ForecastModelLinear, Output: ForecastOutputValue, SeasonLength: 4}.Validate(), "forecast model linear has no seasonality")
+}
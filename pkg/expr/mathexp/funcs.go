@@ -1,7 +1,9 @@
 package mathexp
 
 import (
+	"fmt"
 	"math"
+	"time"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
 )
@@ -68,6 +70,41 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"clamp": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeScalar, parse.TypeScalar},
+		VariantReturn: true,
+		F:             clamp,
+	},
+	"rate": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      rate,
+	},
+	"delta": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      delta,
+	},
+	"cumsum": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      cumsum,
+	},
+	"timestamp": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      timestamp,
+	},
+	"shift": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeDuration},
+		Return: parse.TypeSeriesSet,
+		F:      shift,
+	},
+	"moving_avg": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeDuration},
+		Return: parse.TypeSeriesSet,
+		F:      movingAvg,
+	},
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
@@ -307,3 +344,187 @@ func floor(e *State, varSet Results) (Results, error) {
 	}
 	return newRes, nil
 }
+
+// clamp limits the value for each result in NumberSet, SeriesSet, or Scalar to the range [min, max].
+func clamp(e *State, varSet Results, minRes Results, maxRes Results) (Results, error) {
+	newRes := Results{}
+	minV, err := scalarArg("clamp", "min", minRes)
+	if err != nil {
+		return newRes, err
+	}
+	maxV, err := scalarArg("clamp", "max", maxRes)
+	if err != nil {
+		return newRes, err
+	}
+	if minV > maxV {
+		return newRes, fmt.Errorf("clamp: min (%v) must not be greater than max (%v)", minV, maxV)
+	}
+	for _, res := range varSet.Values {
+		newVal, err := perFloat(e, res, func(f float64) float64 {
+			return math.Max(minV, math.Min(maxV, f))
+		})
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// rate returns the per-second rate of increase between consecutive points of each series in SeriesSet.
+// The series is treated as a counter: a value lower than the previous one is considered a reset.
+// The first point of each series is dropped since it has no previous point.
+func rate(e *State, varSet Results) (Results, error) {
+	return perSeries("rate", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), 0)
+		for i := 1; i < s.Len(); i++ {
+			prevT, prevF := s.GetPoint(i - 1)
+			t, f := s.GetPoint(i)
+			if prevF == nil || f == nil {
+				newSeries.AppendPoint(t, nil)
+				continue
+			}
+			increase := *f - *prevF
+			if *f < *prevF {
+				increase = *f
+			}
+			nF := math.NaN()
+			if elapsed := t.Sub(prevT).Seconds(); elapsed > 0 {
+				nF = increase / elapsed
+			}
+			newSeries.AppendPoint(t, &nF)
+		}
+		return newSeries
+	})
+}
+
+// delta returns the difference between consecutive points of each series in SeriesSet.
+// The first point of each series is dropped since it has no previous point.
+func delta(e *State, varSet Results) (Results, error) {
+	return perSeries("delta", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), 0)
+		for i := 1; i < s.Len(); i++ {
+			prevF := s.GetValue(i - 1)
+			t, f := s.GetPoint(i)
+			if prevF == nil || f == nil {
+				newSeries.AppendPoint(t, nil)
+				continue
+			}
+			nF := *f - *prevF
+			newSeries.AppendPoint(t, &nF)
+		}
+		return newSeries
+	})
+}
+
+// cumsum returns the cumulative sum of each series in SeriesSet. Null points are kept as null
+// and do not contribute to the sum.
+func cumsum(e *State, varSet Results) (Results, error) {
+	return perSeries("cumsum", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		var sum float64
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			if f == nil {
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			sum += *f
+			nF := sum
+			newSeries.SetPoint(i, t, &nF)
+		}
+		return newSeries
+	})
+}
+
+// timestamp returns the time of each point, in seconds since the Unix epoch, for each series in SeriesSet.
+func timestamp(e *State, varSet Results) (Results, error) {
+	return perSeries("timestamp", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		for i := 0; i < s.Len(); i++ {
+			t := s.GetTime(i)
+			nF := float64(t.UnixNano()) / float64(time.Second)
+			newSeries.SetPoint(i, t, &nF)
+		}
+		return newSeries
+	})
+}
+
+// shift moves each point of each series in SeriesSet forward in time by d, so that
+// for example $A - shift($A, 1w) compares each point with the point a week before.
+func shift(e *State, varSet Results, d time.Duration) (Results, error) {
+	return perSeries("shift", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			newSeries.SetPoint(i, t.Add(d), f)
+		}
+		return newSeries
+	})
+}
+
+// movingAvg returns, for each point of each series in SeriesSet, the mean of the points
+// in the window (t-d, t]. Null and NaN points are ignored. If the window has no values NaN is returned.
+func movingAvg(e *State, varSet Results, d time.Duration) (Results, error) {
+	if d <= 0 {
+		return Results{}, fmt.Errorf("moving_avg: window must be greater than zero, got %v", d)
+	}
+	return perSeries("moving_avg", varSet, func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		var sum float64
+		var count int
+		start := 0
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			if f != nil && !math.IsNaN(*f) {
+				sum += *f
+				count++
+			}
+			for ; start < i && !s.GetTime(start).After(t.Add(-d)); start++ {
+				if old := s.GetValue(start); old != nil && !math.IsNaN(*old) {
+					sum -= *old
+					count--
+				}
+			}
+			nF := math.NaN()
+			if count > 0 {
+				nF = sum / float64(count)
+			}
+			newSeries.SetPoint(i, t, &nF)
+		}
+		return newSeries
+	})
+}
+
+// perSeries passes each Series in varSet to seriesF. NoData is returned as is, and any other
+// type is an error since these functions need the time of each point.
+func perSeries(name string, varSet Results, seriesF func(s Series) Series) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		switch v := res.(type) {
+		case Series:
+			newRes.Values = append(newRes.Values, seriesF(v))
+		case NoData:
+			newRes.Values = append(newRes.Values, NewNoData())
+		default:
+			return newRes, fmt.Errorf("%s: expected %v, got %v", name, parse.TypeSeriesSet, res.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// scalarArg returns the value of a scalar argument of the function name.
+func scalarArg(name, arg string, res Results) (float64, error) {
+	if len(res.Values) != 1 {
+		return 0, fmt.Errorf("%s: expected a single scalar for %s", name, arg)
+	}
+	s, ok := res.Values[0].(Scalar)
+	if !ok {
+		return 0, fmt.Errorf("%s: expected %v for %s, got %v", name, parse.TypeScalar, arg, res.Values[0].Type())
+	}
+	f := s.GetFloat64Value()
+	if f == nil || math.IsNaN(*f) {
+		return 0, fmt.Errorf("%s: %s must be a number", name, arg)
+	}
+	return *f, nil
+}
@@ -140,3 +140,194 @@ func TestIsNumberFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestSeriesFuncs(t *testing.T) {
+	counter := Vars{
+		"A": resultValuesNoErr(
+			makeSeries("", nil,
+				tp{time.Unix(0, 0), float64Pointer(10)},
+				tp{time.Unix(10, 0), float64Pointer(30)},
+				tp{time.Unix(20, 0), nil},
+				tp{time.Unix(30, 0), float64Pointer(50)},
+				tp{time.Unix(40, 0), float64Pointer(20)}),
+		),
+	}
+
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "rate handles nulls and counter resets",
+			expr:      "rate($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(10, 0), float64Pointer(2)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), nil},
+					tp{time.Unix(40, 0), float64Pointer(2)}),
+			),
+		},
+		{
+			name:      "delta",
+			expr:      "delta($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), nil},
+					tp{time.Unix(40, 0), float64Pointer(-30)}),
+			),
+		},
+		{
+			name:      "cumsum keeps nulls",
+			expr:      "cumsum($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(10)},
+					tp{time.Unix(10, 0), float64Pointer(40)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(90)},
+					tp{time.Unix(40, 0), float64Pointer(110)}),
+			),
+		},
+		{
+			name:      "timestamp",
+			expr:      "timestamp($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(0)},
+					tp{time.Unix(10, 0), float64Pointer(10)},
+					tp{time.Unix(20, 0), float64Pointer(20)},
+					tp{time.Unix(30, 0), float64Pointer(30)},
+					tp{time.Unix(40, 0), float64Pointer(40)}),
+			),
+		},
+		{
+			name:      "shift compared with itself",
+			expr:      "$A - shift($A, 10s)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), nil},
+					tp{time.Unix(40, 0), float64Pointer(-30)}),
+			),
+		},
+		{
+			name:      "moving_avg ignores nulls",
+			expr:      "moving_avg($A, 20s)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(10)},
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), float64Pointer(30)},
+					tp{time.Unix(30, 0), float64Pointer(50)},
+					tp{time.Unix(40, 0), float64Pointer(35)}),
+			),
+		},
+		{
+			name: "clamp on series",
+			expr: "clamp($A, 15, 40)",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), float64Pointer(10)},
+						tp{time.Unix(10, 0), float64Pointer(30)},
+						tp{time.Unix(20, 0), float64Pointer(50)}),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(15)},
+					tp{time.Unix(10, 0), float64Pointer(30)},
+					tp{time.Unix(20, 0), float64Pointer(40)}),
+			),
+		},
+		{
+			name: "clamp on number",
+			expr: "clamp($A, -1, 1)",
+			vars: Vars{
+				"A": resultValuesNoErr(makeNumber("", nil, float64Pointer(-7))),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   resultValuesNoErr(makeNumber("", nil, float64Pointer(-1))),
+		},
+		{
+			name:      "clamp with min greater than max should error",
+			expr:      "clamp($A, 2, 1)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name: "rate on number should error",
+			expr: "rate($A)",
+			vars: Vars{
+				"A": resultValuesNoErr(makeNumber("", nil, float64Pointer(-7))),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:      "moving_avg with an empty window should error",
+			expr:      "moving_avg($A, 0s)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:     "shift without duration should error",
+			expr:     "shift($A, 10)",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "duration outside of a function should error",
+			expr:     "$A + 1h",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "invalid duration should error",
+			expr:     "shift($A, 1x)",
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e != nil {
+				res, err := e.Execute("", tt.vars, tracing.InitializeTracerForTest())
+				tt.execErrIs(t, err)
+				if err == nil {
+					require.Equal(t, tt.results, res)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package mathexp
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// The cardinality of a join
+// +enum
+type JoinCardinality string
+
+const (
+	// Each value on one side matches at most one value on the other side
+	JoinOneToOne JoinCardinality = "one_to_one"
+
+	// Many values on the left side can match one value on the right side
+	JoinGroupLeft JoinCardinality = "group_left"
+
+	// Many values on the right side can match one value on the left side
+	JoinGroupRight JoinCardinality = "group_right"
+)
+
+// JoinSpec describes how the values of two results are paired and combined by Join.
+type JoinSpec struct {
+	// Operator is the binary operator applied to each pair of values, e.g. "/".
+	Operator string
+	// On is the list of labels used to match values. Cannot be used together with Ignoring.
+	On []string
+	// Ignoring is the list of labels that are not used to match values. Cannot be used together with On.
+	Ignoring []string
+	// Cardinality of the join. Defaults to JoinOneToOne.
+	Cardinality JoinCardinality
+	// Include is the list of labels copied from the "one" side to the result of a group_left or group_right join.
+	Include []string
+}
+
+// Validate returns an error if the specification cannot be used to join results.
+func (j JoinSpec) Validate() error {
+	if _, err := binaryOp(j.Operator, 1, 1); err != nil {
+		return fmt.Errorf("unsupported join operator '%s'", j.Operator)
+	}
+	if len(j.On) > 0 && len(j.Ignoring) > 0 {
+		return errors.New("join labels can be specified either with 'on' or with 'ignoring', not both")
+	}
+	switch j.Cardinality {
+	case "", JoinOneToOne:
+		if len(j.Include) > 0 {
+			return errors.New("included labels can only be used with group_left or group_right joins")
+		}
+	case JoinGroupLeft, JoinGroupRight:
+	default:
+		return fmt.Errorf("unsupported join cardinality '%s'", j.Cardinality)
+	}
+	return nil
+}
+
+// matchingKey returns the labels used to pair a value with the values of the other side.
+func (j JoinSpec) matchingKey(l data.Labels) string {
+	key := data.Labels{}
+	for k, v := range l {
+		if len(j.On) > 0 && !slices.Contains(j.On, k) {
+			continue
+		}
+		if slices.Contains(j.Ignoring, k) {
+			continue
+		}
+		key[k] = v
+	}
+	return key.String()
+}
+
+// resultLabels returns the labels of the value produced by joining the values with the labels many and one.
+// For one-to-one joins, many is the left side.
+func (j JoinSpec) resultLabels(many, one data.Labels) data.Labels {
+	result := data.Labels{}
+	if j.Cardinality == JoinGroupLeft || j.Cardinality == JoinGroupRight {
+		for k, v := range many {
+			result[k] = v
+		}
+		for _, k := range j.Include {
+			if v, ok := one[k]; ok {
+				result[k] = v
+			} else {
+				delete(result, k)
+			}
+		}
+		return result
+	}
+	for k, v := range many {
+		if len(j.On) > 0 && !slices.Contains(j.On, k) {
+			continue
+		}
+		if slices.Contains(j.Ignoring, k) {
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// Join pairs the values of the left and right results by their labels, as described by the spec,
+// and applies the operator of the spec to each pair.
+// Unlike the implicit union of Math expressions, every value must be matched: Join returns an error
+// that lists the labels of the unmatched values of both sides, or of the values matched more than once
+// when the cardinality does not allow it.
+func Join(refID string, left, right Results, spec JoinSpec) (Results, error) {
+	res := Results{Values: Values{}}
+	if err := spec.Validate(); err != nil {
+		return res, err
+	}
+	if left.IsNoData() || right.IsNoData() {
+		res.Values = append(res.Values, NewNoData())
+		return res, nil
+	}
+
+	leftGroups, leftKeys := groupByMatchingKey(left, spec)
+	rightGroups, rightKeys := groupByMatchingKey(right, spec)
+
+	var unmatched []string
+	for _, key := range leftKeys {
+		if _, ok := rightGroups[key]; !ok {
+			unmatched = append(unmatched, fmt.Sprintf("left %s", labelsString(leftGroups[key])))
+		}
+	}
+	for _, key := range rightKeys {
+		if _, ok := leftGroups[key]; !ok {
+			unmatched = append(unmatched, fmt.Sprintf("right %s", labelsString(rightGroups[key])))
+		}
+	}
+	if len(unmatched) > 0 {
+		return res, fmt.Errorf("join has unmatched values: %s", strings.Join(unmatched, ", "))
+	}
+
+	e := &State{RefID: refID}
+	for _, key := range leftKeys {
+		lValues, rValues := leftGroups[key], rightGroups[key]
+		if len(lValues) > 1 && spec.Cardinality != JoinGroupLeft {
+			return res, fmt.Errorf("join has multiple left values for the same match {%s}: %s; use group_left if this is expected", key, labelsString(lValues))
+		}
+		if len(rValues) > 1 && spec.Cardinality != JoinGroupRight {
+			return res, fmt.Errorf("join has multiple right values for the same match {%s}: %s; use group_right if this is expected", key, labelsString(rValues))
+		}
+		for _, l := range lValues {
+			for _, r := range rValues {
+				var labels data.Labels
+				if spec.Cardinality == JoinGroupRight {
+					labels = spec.resultLabels(r.GetLabels(), l.GetLabels())
+				} else {
+					labels = spec.resultLabels(l.GetLabels(), r.GetLabels())
+				}
+				value, err := e.biValues(labels, spec.Operator, l, r)
+				if err != nil {
+					return res, err
+				}
+				res.Values = append(res.Values, value)
+			}
+		}
+	}
+	return res, nil
+}
+
+// groupByMatchingKey groups the values of the results by their matching key,
+// and returns the keys in the order they are first seen.
+func groupByMatchingKey(r Results, spec JoinSpec) (map[string][]Value, []string) {
+	groups := make(map[string][]Value, len(r.Values))
+	keys := make([]string, 0, len(r.Values))
+	for _, v := range r.Values {
+		key := spec.matchingKey(v.GetLabels())
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], v)
+	}
+	return groups, keys
+}
+
+func labelsString(values []Value) string {
+	s := make([]string, 0, len(values))
+	for _, v := range values {
+		s = append(s, "{"+v.GetLabels().String()+"}")
+	}
+	return strings.Join(s, " ")
+}
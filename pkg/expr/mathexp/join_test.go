@@ -0,0 +1,139 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoin(t *testing.T) {
+	var tests = []struct {
+		name        string
+		left        Results
+		right       Results
+		spec        JoinSpec
+		errContains string
+		results     Results
+	}{
+		{
+			name: "one to one join on labels",
+			left: resultValuesNoErr(
+				makeNumber("A", data.Labels{"host": "a", "job": "errors"}, float64Pointer(2)),
+				makeNumber("A", data.Labels{"host": "b", "job": "errors"}, float64Pointer(3)),
+			),
+			right: resultValuesNoErr(
+				makeNumber("B", data.Labels{"host": "b", "job": "requests"}, float64Pointer(30)),
+				makeNumber("B", data.Labels{"host": "a", "job": "requests"}, float64Pointer(10)),
+			),
+			spec: JoinSpec{Operator: "/", On: []string{"host"}},
+			results: resultValuesNoErr(
+				makeNumber("C", data.Labels{"host": "a"}, float64Pointer(0.2)),
+				makeNumber("C", data.Labels{"host": "b"}, float64Pointer(0.1)),
+			),
+		},
+		{
+			name: "one to one join ignoring labels",
+			left: resultValuesNoErr(
+				makeSeries("A", data.Labels{"host": "a", "job": "errors"}, tp{time.Unix(5, 0), float64Pointer(2)}, tp{time.Unix(10, 0), float64Pointer(4)}),
+			),
+			right: resultValuesNoErr(
+				makeSeries("B", data.Labels{"host": "a", "job": "requests"}, tp{time.Unix(5, 0), float64Pointer(1)}, tp{time.Unix(10, 0), float64Pointer(8)}),
+			),
+			spec: JoinSpec{Operator: "*", Ignoring: []string{"job"}},
+			results: resultValuesNoErr(
+				makeSeries("C", data.Labels{"host": "a"}, tp{time.Unix(5, 0), float64Pointer(2)}, tp{time.Unix(10, 0), float64Pointer(32)}),
+			),
+		},
+		{
+			name: "group left join keeps the labels of the left side and includes labels of the right side",
+			left: resultValuesNoErr(
+				makeNumber("A", data.Labels{"cluster": "eu", "pod": "a"}, float64Pointer(1)),
+				makeNumber("A", data.Labels{"cluster": "eu", "pod": "b"}, float64Pointer(3)),
+			),
+			right: resultValuesNoErr(
+				makeNumber("B", data.Labels{"cluster": "eu", "region": "west"}, float64Pointer(4)),
+			),
+			spec: JoinSpec{Operator: "/", On: []string{"cluster"}, Cardinality: JoinGroupLeft, Include: []string{"region"}},
+			results: resultValuesNoErr(
+				makeNumber("C", data.Labels{"cluster": "eu", "pod": "a", "region": "west"}, float64Pointer(0.25)),
+				makeNumber("C", data.Labels{"cluster": "eu", "pod": "b", "region": "west"}, float64Pointer(0.75)),
+			),
+		},
+		{
+			name: "group right join keeps the labels of the right side",
+			left: resultValuesNoErr(
+				makeNumber("A", data.Labels{"cluster": "eu"}, float64Pointer(10)),
+			),
+			right: resultValuesNoErr(
+				makeNumber("B", data.Labels{"cluster": "eu", "pod": "a"}, float64Pointer(1)),
+				makeNumber("B", data.Labels{"cluster": "eu", "pod": "b"}, float64Pointer(2)),
+			),
+			spec: JoinSpec{Operator: "-", On: []string{"cluster"}, Cardinality: JoinGroupRight},
+			results: resultValuesNoErr(
+				makeNumber("C", data.Labels{"cluster": "eu", "pod": "a"}, float64Pointer(9)),
+				makeNumber("C", data.Labels{"cluster": "eu", "pod": "b"}, float64Pointer(8)),
+			),
+		},
+		{
+			name:  "no data on one side results in no data",
+			left:  resultValuesNoErr(NewNoData()),
+			right: resultValuesNoErr(makeNumber("B", data.Labels{"host": "a"}, float64Pointer(1))),
+			spec:  JoinSpec{Operator: "+"},
+			results: resultValuesNoErr(
+				NewNoData(),
+			),
+		},
+		{
+			name: "error lists unmatched values of both sides",
+			left: resultValuesNoErr(
+				makeNumber("A", data.Labels{"host": "a"}, float64Pointer(1)),
+				makeNumber("A", data.Labels{"host": "b"}, float64Pointer(1)),
+			),
+			right: resultValuesNoErr(
+				makeNumber("B", data.Labels{"host": "a"}, float64Pointer(1)),
+				makeNumber("B", data.Labels{"host": "c"}, float64Pointer(1)),
+			),
+			spec:        JoinSpec{Operator: "+", On: []string{"host"}},
+			errContains: "join has unmatched values: left {host=b}, right {host=c}",
+		},
+		{
+			name: "error when one to one join matches many values",
+			left: resultValuesNoErr(
+				makeNumber("A", data.Labels{"cluster": "eu", "pod": "a"}, float64Pointer(1)),
+				makeNumber("A", data.Labels{"cluster": "eu", "pod": "b"}, float64Pointer(1)),
+			),
+			right: resultValuesNoErr(
+				makeNumber("B", data.Labels{"cluster": "eu"}, float64Pointer(1)),
+			),
+			spec:        JoinSpec{Operator: "+", On: []string{"cluster"}},
+			errContains: "use group_left",
+		},
+		{
+			name:        "error when both on and ignoring are set",
+			left:        resultValuesNoErr(makeNumber("A", nil, float64Pointer(1))),
+			right:       resultValuesNoErr(makeNumber("B", nil, float64Pointer(1))),
+			spec:        JoinSpec{Operator: "+", On: []string{"a"}, Ignoring: []string{"b"}},
+			errContains: "either with 'on' or with 'ignoring'",
+		},
+		{
+			name:        "error when the operator is not supported",
+			left:        resultValuesNoErr(makeNumber("A", nil, float64Pointer(1))),
+			right:       resultValuesNoErr(makeNumber("B", nil, float64Pointer(1))),
+			spec:        JoinSpec{Operator: "abs"},
+			errContains: "unsupported join operator",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Join("C", tt.left, tt.right, tt.spec)
+			if tt.errContains != "" {
+				require.ErrorContains(t, err, tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.results.Values, res.Values)
+		})
+	}
+}
@@ -56,8 +56,9 @@ const (
 	itemRightParen
 	itemString
 	itemFunc
-	itemVar // e.g. $A
-	itemPow // '**'
+	itemVar      // e.g. $A
+	itemPow      // '**'
+	itemDuration // e.g. 5m
 )
 
 const eof = -1
@@ -90,7 +91,6 @@ func (l *lexer) next() rune {
 }
 
 // peek returns but does not consume the next rune in the input.
-// nolint:unused
 func (l *lexer) peek() rune {
 	r := l.next()
 	l.backup()
@@ -236,10 +236,28 @@ func lexNumber(l *lexer) stateFn {
 	if !l.scanNumber() {
 		return l.errorf("bad number syntax: %q", l.input[l.start:l.pos])
 	}
+	if unicode.IsLetter(l.peek()) {
+		return lexDuration
+	}
 	l.emit(itemNumber)
 	return lexItem
 }
 
+// lexDuration scans the unit of a duration such as 5m, 1h30m or 1w. The leading
+// number has already been scanned by lexNumber.
+func lexDuration(l *lexer) stateFn {
+	for {
+		switch r := l.next(); {
+		case unicode.IsLetter(r) || isNumber(r):
+			// absorb
+		default:
+			l.backup()
+			l.emit(itemDuration)
+			return lexItem
+		}
+	}
+}
+
 func (l *lexer) scanNumber() bool {
 	// Is it hex?
 	digits := "0123456789"
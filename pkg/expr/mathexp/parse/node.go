@@ -9,6 +9,9 @@ package parse
 import (
 	"fmt"
 	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 )
 
 // A Node is an element in the parse tree. The interface is trivial.
@@ -62,6 +65,8 @@ const (
 	NodeNumber
 	// NodeVar is variable: $A
 	NodeVar
+	// NodeDuration is a duration constant: 5m
+	NodeDuration
 )
 
 // String returns the string representation of the NodeType
@@ -79,6 +84,8 @@ func (t NodeType) String() string {
 		return "NodeNumber"
 	case NodeVar:
 		return "NodeVar"
+	case NodeDuration:
+		return "NodeDuration"
 	default:
 		return "NodeUnknown"
 	}
@@ -261,6 +268,42 @@ func (n *ScalarNode) Return() ReturnType {
 	return TypeScalar
 }
 
+// DurationNode holds a duration constant such as 5m or 1w.
+type DurationNode struct {
+	NodeType
+	Pos
+	Duration time.Duration // The parsed duration.
+	Text     string        // The original textual representation from the input.
+}
+
+func newDuration(pos Pos, text string) (*DurationNode, error) {
+	d, err := gtime.ParseDuration(text)
+	if err != nil {
+		return nil, fmt.Errorf("illegal duration syntax: %q", text)
+	}
+	return &DurationNode{NodeType: NodeDuration, Pos: pos, Duration: d, Text: text}, nil
+}
+
+// String returns the string representation of the DurationNode so it fulfills the Node interface.
+func (n *DurationNode) String() string {
+	return n.Text
+}
+
+// StringAST returns the string representation of abstract syntax tree of the DurationNode so it fulfills the Node interface.
+func (n *DurationNode) StringAST() string {
+	return n.String()
+}
+
+// Check performs parse time checking on the DurationNode so it fulfills the Node interface.
+func (n *DurationNode) Check(*Tree) error {
+	return nil
+}
+
+// Return returns the result type of the DurationNode so it fulfills the Node interface.
+func (n *DurationNode) Return() ReturnType {
+	return TypeDuration
+}
+
 // StringNode holds a string constant. The value has been "unquoted".
 type StringNode struct {
 	NodeType
@@ -318,6 +361,14 @@ func (b *BinaryNode) StringAST() string {
 
 // Check performs parse time checking on the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) Check(t *Tree) error {
+	for _, arg := range b.Args {
+		if rt := arg.Return(); rt == TypeDuration {
+			return fmt.Errorf(`parse: type error in %s, %s can only be used as a function argument`, b, rt)
+		}
+		if err := arg.Check(t); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -380,7 +431,7 @@ func Walk(n Node, f func(Node)) {
 		for _, a := range n.Args {
 			Walk(a, f)
 		}
-	case *ScalarNode, *StringNode:
+	case *ScalarNode, *StringNode, *DurationNode:
 		// Ignore since these node types have no sub nodes.
 	case *UnaryNode:
 		Walk(n.Arg, f)
@@ -407,6 +458,8 @@ const (
 	TypeNoData
 	// TypeTableData is a tabular data response.
 	TypeTableData
+	// TypeDuration is a duration constant, only valid as a function argument.
+	TypeDuration
 )
 
 // String returns a string representation of the ReturnType.
@@ -426,6 +479,8 @@ func (f ReturnType) String() string {
 		return "noData"
 	case TypeTableData:
 		return "tableData"
+	case TypeDuration:
+		return "duration"
 	default:
 		return "unknown"
 	}
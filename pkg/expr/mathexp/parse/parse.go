@@ -177,7 +177,7 @@ E -> F {( "**" ) F}
 F -> v | "(" O ")" | "!" O | "-" O
 v -> number | func(..) | queryVar
 Func -> name "(" param {"," param} ")"
-param -> number | "string" | queryVar
+param -> number | duration | "string" | queryVar
 */
 
 // expr:
@@ -263,7 +263,7 @@ func (t *Tree) E() Node {
 // F is v | "(" O ")" | "!" O | "-" O in the grammar.
 func (t *Tree) F() Node {
 	switch token := t.peek(); token.typ {
-	case itemNumber, itemFunc, itemVar:
+	case itemNumber, itemDuration, itemFunc, itemVar:
 		return t.v()
 	case itemNot, itemMinus:
 		return newUnary(t.next(), t.F())
@@ -278,7 +278,7 @@ func (t *Tree) F() Node {
 	return nil
 }
 
-// V is number | func(..) | queryVar in the grammar.
+// V is number | duration | func(..) | queryVar in the grammar.
 func (t *Tree) v() Node {
 	switch token := t.next(); token.typ {
 	case itemNumber:
@@ -287,6 +287,12 @@ func (t *Tree) v() Node {
 			t.error(err)
 		}
 		return n
+	case itemDuration:
+		n, err := newDuration(token.pos, token.val)
+		if err != nil {
+			t.error(err)
+		}
+		return n
 	case itemFunc:
 		t.backup()
 		return t.Func()
@@ -1,6 +1,7 @@
 package mathexp
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"sort"
@@ -22,13 +23,32 @@ const (
 	ReducerCount  ReducerID = "count"
 	ReducerLast   ReducerID = "last"
 	ReducerMedian ReducerID = "median"
+
+	ReducerFirst      ReducerID = "first"
+	ReducerStdDev     ReducerID = "stddev"
+	ReducerRange      ReducerID = "range"
+	ReducerDelta      ReducerID = "delta"
+	ReducerRate       ReducerID = "rate"
+	ReducerPercentile ReducerID = "percentile"
 )
 
 // GetSupportedReduceFuncs returns collection of supported function names
 func GetSupportedReduceFuncs() []ReducerID {
-	return []ReducerID{ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast, ReducerMedian}
+	return []ReducerID{
+		ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast, ReducerMedian,
+		ReducerFirst, ReducerStdDev, ReducerRange, ReducerDelta, ReducerRate, ReducerPercentile,
+	}
 }
 
+// ReducerParams contains the arguments of parameterised reducers.
+type ReducerParams struct {
+	// Percentile is the percentile (0-100) computed by the percentile reducer.
+	Percentile *float64
+}
+
+// SeriesReducerFunc is a reduction function that needs the timestamps of the series as well as its values.
+type SeriesReducerFunc = func(s Series) *float64
+
 func Sum(fv *Float64Field) *float64 {
 	var sum float64
 	for i := 0; i < fv.Len(); i++ {
@@ -126,6 +146,122 @@ func Median(fv *Float64Field) *float64 {
 	}
 }
 
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+// StdDev returns the population standard deviation of the values.
+func StdDev(fv *Float64Field) *float64 {
+	if fv.Len() == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	mean := Avg(fv)
+	if math.IsNaN(*mean) {
+		return mean
+	}
+	var sum float64
+	for i := 0; i < fv.Len(); i++ {
+		d := *fv.GetValue(i) - *mean
+		sum += d * d
+	}
+	f := math.Sqrt(sum / float64(fv.Len()))
+	return &f
+}
+
+// Range returns the difference between the largest and the smallest value.
+func Range(fv *Float64Field) *float64 {
+	minV, maxV := Min(fv), Max(fv)
+	if math.IsNaN(*minV) || math.IsNaN(*maxV) {
+		return minV
+	}
+	f := *maxV - *minV
+	return &f
+}
+
+// Delta returns the difference between the last and the first value.
+func Delta(fv *Float64Field) *float64 {
+	if fv.Len() == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	first, last := fv.GetValue(0), fv.GetValue(fv.Len()-1)
+	if first == nil || last == nil || math.IsNaN(*first) || math.IsNaN(*last) {
+		nan := math.NaN()
+		return &nan
+	}
+	f := *last - *first
+	return &f
+}
+
+// Percentile returns a reduction function that computes the p-th percentile of the values,
+// interpolating linearly between the closest ranks.
+func Percentile(p float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		values := make([]float64, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			v := fv.GetValue(i)
+			if v == nil || math.IsNaN(*v) {
+				nan := math.NaN()
+				return &nan
+			}
+			values = append(values, *v)
+		}
+
+		if len(values) == 0 {
+			nan := math.NaN()
+			return &nan
+		}
+
+		sort.Float64s(values)
+		rank := p / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		f := values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+		return &f
+	}
+}
+
+// Rate returns the per-second increase of the series between its first and last point.
+// The series is treated as a counter: a value lower than the previous one is considered a reset.
+func Rate(s Series) *float64 {
+	if s.Len() < 2 {
+		nan := math.NaN()
+		return &nan
+	}
+	var increase float64
+	var prev float64
+	for i := 0; i < s.Len(); i++ {
+		v := s.GetValue(i)
+		if v == nil || math.IsNaN(*v) {
+			nan := math.NaN()
+			return &nan
+		}
+		if i > 0 {
+			if *v < prev {
+				increase += *v
+			} else {
+				increase += *v - prev
+			}
+		}
+		prev = *v
+	}
+	elapsed := s.GetTime(s.Len() - 1).Sub(s.GetTime(0)).Seconds()
+	if elapsed <= 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	f := increase / elapsed
+	return &f
+}
+
+// GetReduceFunc returns the reduction function for reducers that only need the values of the series.
+// Parameterised reducers, such as percentile, and time-aware reducers, such as rate, are returned by GetSeriesReduceFunc.
 func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 	switch rFunc {
 	case ReducerSum:
@@ -142,15 +278,53 @@ func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 		return Last, nil
 	case ReducerMedian:
 		return Median, nil
+	case ReducerFirst:
+		return First, nil
+	case ReducerStdDev:
+		return StdDev, nil
+	case ReducerRange:
+		return Range, nil
+	case ReducerDelta:
+		return Delta, nil
 	default:
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
 }
 
+// GetSeriesReduceFunc returns the reduction function for any supported reducer.
+// It returns an error if the reducer is not supported or if the parameters it requires are missing or invalid.
+func GetSeriesReduceFunc(rFunc ReducerID, params ReducerParams) (SeriesReducerFunc, error) {
+	var reduceFunc ReducerFunc
+	switch rFunc {
+	case ReducerRate:
+		return Rate, nil
+	case ReducerPercentile:
+		if params.Percentile == nil {
+			return nil, errors.New("reduction percentile requires the percentile parameter")
+		}
+		p := *params.Percentile
+		if math.IsNaN(p) || p < 0 || p > 100 {
+			return nil, fmt.Errorf("reduction percentile requires a percentile between 0 and 100, got %v", p)
+		}
+		reduceFunc = Percentile(p)
+	default:
+		var err error
+		reduceFunc, err = GetReduceFunc(rFunc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return func(s Series) *float64 {
+		fVec := s.Frame.Fields[seriesTypeValIdx]
+		floatField := Float64Field(*fVec)
+		return reduceFunc(&floatField)
+	}, nil
+}
+
 // Reduce turns the Series into a Number based on the given reduction function
 // if ReduceMapper is defined it applies it to the provided series and performs reduction of the resulting series.
 // Otherwise, the reduction operation is done against the original series.
-func (s Series) Reduce(refID string, rFunc ReducerID, mapper ReduceMapper) (Number, error) {
+func (s Series) Reduce(refID string, rFunc ReducerID, params ReducerParams, mapper ReduceMapper) (Number, error) {
 	var l data.Labels
 	if s.GetLabels() != nil {
 		l = s.GetLabels().Copy()
@@ -161,13 +335,11 @@ func (s Series) Reduce(refID string, rFunc ReducerID, mapper ReduceMapper) (Numb
 	if mapper != nil {
 		series = mapSeries(s, mapper)
 	}
-	fVec := series.Frame.Fields[seriesTypeValIdx]
-	floatField := Float64Field(*fVec)
-	reduceFunc, err := GetReduceFunc(rFunc)
+	reduceFunc, err := GetSeriesReduceFunc(rFunc, params)
 	if err != nil {
 		return number, fmt.Errorf("invalid expression '%s': %w", refID, err)
 	}
-	f = reduceFunc(&floatField)
+	f = reduceFunc(series)
 	if f != nil && mapper != nil {
 		f = mapper.MapOutput(f)
 	}
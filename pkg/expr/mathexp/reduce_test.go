@@ -28,10 +28,33 @@ var seriesEmpty = Vars{
 	),
 }
 
+var counterSeries = Vars{
+	"A": resultValuesNoErr(
+		makeSeries("temp", nil, tp{
+			time.Unix(0, 0), float64Pointer(10),
+		}, tp{
+			time.Unix(10, 0), float64Pointer(20),
+		}, tp{
+			time.Unix(20, 0), float64Pointer(5),
+		}, tp{
+			time.Unix(30, 0), float64Pointer(20),
+		}),
+	),
+}
+
+var seriesSinglePoint = Vars{
+	"A": resultValuesNoErr(
+		makeSeries("temp", nil, tp{
+			time.Unix(5, 0), float64Pointer(2),
+		}),
+	),
+}
+
 func TestSeriesReduce(t *testing.T) {
 	var tests = []struct {
 		name        string
 		red         ReducerID
+		params      ReducerParams
 		vars        Vars
 		varToReduce string
 		errIs       require.ErrorAssertionFunc
@@ -274,6 +297,116 @@ func TestSeriesReduce(t *testing.T) {
 			resultsIs:   require.Equal,
 			results:     resultValuesNoErr(makeNumber("", nil, nil)),
 		},
+		{
+			name:        "first series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2))),
+		},
+		{
+			name:        "first empty series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "stddev series",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(0.5))),
+		},
+		{
+			name:        "stddev series with a nil value",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "range series",
+			red:         "range",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
+		},
+		{
+			name:        "delta series",
+			red:         "delta",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(-1))),
+		},
+		{
+			name:        "delta empty series",
+			red:         "delta",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "rate series",
+			red:         "rate",
+			varToReduce: "A",
+			vars:        counterSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			// 10 -> 20 -> reset to 5 -> 20: increase of 30 over 30s
+			results: resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
+		},
+		{
+			name:        "rate series with a single point",
+			red:         "rate",
+			varToReduce: "A",
+			vars:        seriesSinglePoint,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "percentile series",
+			red:         "percentile",
+			params:      ReducerParams{Percentile: float64Pointer(25)},
+			varToReduce: "A",
+			vars:        counterSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(8.75))),
+		},
+		{
+			name:        "percentile 50 is the median",
+			red:         "percentile",
+			params:      ReducerParams{Percentile: float64Pointer(50)},
+			varToReduce: "A",
+			vars:        counterSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(15))),
+		},
+		{
+			name:        "percentile without parameter will error",
+			red:         "percentile",
+			varToReduce: "A",
+			vars:        counterSeries,
+			errIs:       require.Error,
+			resultsIs:   require.Equal,
+		},
 	}
 
 	for _, tt := range tests {
@@ -281,7 +414,7 @@ func TestSeriesReduce(t *testing.T) {
 			results := Results{}
 			seriesSet := tt.vars[tt.varToReduce]
 			for _, series := range seriesSet.Values {
-				ns, err := series.Value().(*Series).Reduce("", tt.red, nil)
+				ns, err := series.Value().(*Series).Reduce("", tt.red, tt.params, nil)
 				tt.errIs(t, err)
 				if err != nil {
 					return
@@ -401,7 +534,7 @@ func TestSeriesReduceDropNN(t *testing.T) {
 			results := Results{}
 			seriesSet := tt.vars[tt.varToReduce]
 			for _, series := range seriesSet.Values {
-				ns, err := series.Value().(*Series).Reduce("", tt.red, DropNonNumber{})
+				ns, err := series.Value().(*Series).Reduce("", tt.red, ReducerParams{}, DropNonNumber{})
 				require.NoError(t, err)
 				results.Values = append(results.Values, ns)
 			}
@@ -523,7 +656,7 @@ func TestSeriesReduceReplaceNN(t *testing.T) {
 			results := Results{}
 			seriesSet := tt.vars[tt.varToReduce]
 			for _, series := range seriesSet.Values {
-				ns, err := series.Value().(*Series).Reduce("", tt.red, ReplaceNonNumberWithValue{Value: replaceWith})
+				ns, err := series.Value().(*Series).Reduce("", tt.red, ReducerParams{}, ReplaceNonNumberWithValue{Value: replaceWith})
 				require.NoError(t, err)
 				results.Values = append(results.Values, ns)
 			}
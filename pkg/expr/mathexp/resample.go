@@ -2,6 +2,7 @@ package mathexp
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -20,10 +21,17 @@ const (
 
 	// Do not fill values (nill)
 	UpsamplerFillNA Upsampler = "fillna"
+
+	// Interpolate linearly between the last seen value and the next one
+	UpsamplerLinear Upsampler = "linear"
+
+	// Use the value of the closest point in time
+	UpsamplerNearest Upsampler = "nearest"
 )
 
 // Resample turns the Series into a Number based on the given reduction function
-func (s Series) Resample(refID string, interval time.Duration, downsampler ReducerID, upsampler Upsampler, from, to time.Time) (Series, error) {
+// If maxGap is greater than zero, gaps longer than maxGap intervals are not upsampled and are filled with NaN.
+func (s Series) Resample(refID string, interval time.Duration, downsampler ReducerID, upsampler Upsampler, maxGap int, from, to time.Time) (Series, error) {
 	newSeriesLength := int(float64(to.Sub(from).Nanoseconds()) / float64(interval.Nanoseconds()))
 	if newSeriesLength <= 0 {
 		return s, fmt.Errorf("the series cannot be sampled further; the time range is shorter than the interval")
@@ -31,6 +39,9 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 	resampled := NewSeries(refID, s.GetLabels(), newSeriesLength+1)
 	bookmark := 0
 	var lastSeen *float64
+	var lastSeenTime time.Time
+	hasLastSeen := false
+	lastFilledIdx := -1
 	idx := 0
 	t := from
 	for !t.After(to) && idx <= newSeriesLength {
@@ -44,10 +55,19 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 			bookmark++
 			sIdx++
 			lastSeen = v
+			lastSeenTime = st
+			hasLastSeen = true
 			vals = append(vals, v)
 		}
 		var value *float64
 		if len(vals) == 0 { // upsampling
+			if maxGap > 0 && gapLength(s, sIdx, from, interval, lastFilledIdx, newSeriesLength) > maxGap {
+				nan := math.NaN()
+				resampled.SetPoint(idx, t, &nan)
+				t = t.Add(interval)
+				idx++
+				continue
+			}
 			switch upsampler {
 			case UpsamplerPad:
 				if lastSeen != nil {
@@ -63,12 +83,33 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 				}
 			case UpsamplerFillNA:
 				value = nil
+			case UpsamplerLinear:
+				if hasLastSeen && sIdx != s.Len() {
+					nextTime, next := s.GetPoint(sIdx)
+					value = interpolate(t, lastSeenTime, lastSeen, nextTime, next)
+				}
+			case UpsamplerNearest:
+				switch {
+				case sIdx == s.Len():
+					value = lastSeen
+				case !hasLastSeen:
+					_, value = s.GetPoint(sIdx)
+				default:
+					nextTime, next := s.GetPoint(sIdx)
+					if nextTime.Sub(t) < t.Sub(lastSeenTime) {
+						value = next
+					} else {
+						value = lastSeen
+					}
+				}
 			default:
 				return s, fmt.Errorf("upsampling %v not implemented", upsampler)
 			}
 		} else if len(vals) == 1 {
 			value = vals[0]
+			lastFilledIdx = idx
 		} else { // downsampling
+			lastFilledIdx = idx
 			fVec := data.NewField("", s.GetLabels(), vals)
 			ff := Float64Field(*fVec)
 			var tmp *float64
@@ -94,3 +135,29 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 	}
 	return resampled, nil
 }
+
+// gapLength returns the number of consecutive intervals without points around the interval being upsampled,
+// given the index of the last interval that had points and the index of the next point of the series.
+func gapLength(s Series, nextIdx int, from time.Time, interval time.Duration, lastFilledIdx, newSeriesLength int) int {
+	nextFilledIdx := newSeriesLength + 1
+	if nextIdx != s.Len() {
+		nextFilledIdx = 0
+		if d := s.GetTime(nextIdx).Sub(from); d > 0 {
+			nextFilledIdx = int((d + interval - 1) / interval)
+		}
+	}
+	return nextFilledIdx - lastFilledIdx - 1
+}
+
+// interpolate returns the value at t on the line between the points (t0, v0) and (t1, v1).
+func interpolate(t, t0 time.Time, v0 *float64, t1 time.Time, v1 *float64) *float64 {
+	if v0 == nil || v1 == nil {
+		return nil
+	}
+	span := t1.Sub(t0)
+	if span <= 0 {
+		return v0
+	}
+	f := *v0 + (*v1-*v0)*float64(t.Sub(t0))/float64(span)
+	return &f
+}
@@ -1,11 +1,13 @@
 package mathexp
 
 import (
+	"math"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
-	"github.com/stretchr/testify/assert"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
 )
 
@@ -15,6 +17,7 @@ func TestResampleSeries(t *testing.T) {
 		interval         time.Duration
 		downsampler      ReducerID
 		upsampler        Upsampler
+		maxGap           int
 		timeRange        backend.TimeRange
 		seriesToResample Series
 		series           Series
@@ -277,15 +280,181 @@ func TestResampleSeries(t *testing.T) {
 				time.Unix(9, 0), float64Pointer(0),
 			}),
 		},
+		{
+			name:        "resample series: upsampling (mean / linear)",
+			interval:    time.Second * 5,
+			downsampler: "mean",
+			upsampler:   "linear",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(45, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(0),
+			}, tp{
+				time.Unix(10, 0), float64Pointer(10),
+			}, tp{
+				time.Unix(40, 0), float64Pointer(40),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(0),
+			}, tp{
+				time.Unix(5, 0), float64Pointer(5),
+			}, tp{
+				time.Unix(10, 0), float64Pointer(10),
+			}, tp{
+				time.Unix(15, 0), float64Pointer(15),
+			}, tp{
+				time.Unix(20, 0), float64Pointer(20),
+			}, tp{
+				time.Unix(25, 0), float64Pointer(25),
+			}, tp{
+				time.Unix(30, 0), float64Pointer(30),
+			}, tp{
+				time.Unix(35, 0), float64Pointer(35),
+			}, tp{
+				time.Unix(40, 0), float64Pointer(40),
+			}, tp{
+				time.Unix(45, 0), nil,
+			}),
+		},
+		{
+			name:        "resample series: upsampling (mean / linear) with max gap",
+			interval:    time.Second * 5,
+			downsampler: "mean",
+			upsampler:   "linear",
+			maxGap:      2,
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(45, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(0),
+			}, tp{
+				time.Unix(10, 0), float64Pointer(10),
+			}, tp{
+				time.Unix(40, 0), float64Pointer(40),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(0),
+			}, tp{
+				time.Unix(5, 0), float64Pointer(5),
+			}, tp{
+				time.Unix(10, 0), float64Pointer(10),
+			}, tp{
+				time.Unix(15, 0), float64Pointer(math.NaN()),
+			}, tp{
+				time.Unix(20, 0), float64Pointer(math.NaN()),
+			}, tp{
+				time.Unix(25, 0), float64Pointer(math.NaN()),
+			}, tp{
+				time.Unix(30, 0), float64Pointer(math.NaN()),
+			}, tp{
+				time.Unix(35, 0), float64Pointer(math.NaN()),
+			}, tp{
+				time.Unix(40, 0), float64Pointer(40),
+			}, tp{
+				time.Unix(45, 0), nil,
+			}),
+		},
+		{
+			name:        "resample series: upsampling (mean / pad) with max gap",
+			interval:    time.Second * 5,
+			downsampler: "mean",
+			upsampler:   "pad",
+			maxGap:      2,
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(45, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(0),
+			}, tp{
+				time.Unix(10, 0), float64Pointer(10),
+			}, tp{
+				time.Unix(40, 0), float64Pointer(40),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(0),
+			}, tp{
+				time.Unix(5, 0), float64Pointer(0),
+			}, tp{
+				time.Unix(10, 0), float64Pointer(10),
+			}, tp{
+				time.Unix(15, 0), float64Pointer(math.NaN()),
+			}, tp{
+				time.Unix(20, 0), float64Pointer(math.NaN()),
+			}, tp{
+				time.Unix(25, 0), float64Pointer(math.NaN()),
+			}, tp{
+				time.Unix(30, 0), float64Pointer(math.NaN()),
+			}, tp{
+				time.Unix(35, 0), float64Pointer(math.NaN()),
+			}, tp{
+				time.Unix(40, 0), float64Pointer(40),
+			}, tp{
+				time.Unix(45, 0), float64Pointer(40),
+			}),
+		},
+		{
+			name:        "resample series: upsampling (mean / nearest)",
+			interval:    time.Second * 3,
+			downsampler: "mean",
+			upsampler:   "nearest",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(15, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(1),
+			}, tp{
+				time.Unix(9, 0), float64Pointer(2),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(1),
+			}, tp{
+				time.Unix(3, 0), float64Pointer(1),
+			}, tp{
+				time.Unix(6, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(9, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(12, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(15, 0), float64Pointer(2),
+			}),
+		},
+		{
+			name:        "resample series: reducer that is not a downsampler",
+			interval:    time.Second * 5,
+			downsampler: ReducerStdDev,
+			upsampler:   "pad",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(10, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(1, 0), float64Pointer(1),
+			}, tp{
+				time.Unix(2, 0), float64Pointer(3),
+			}),
+		},
 	}
+	opt := cmp.Comparer(func(x, y float64) bool {
+		return (math.IsNaN(x) && math.IsNaN(y)) || x == y
+	})
+	options := append([]cmp.Option{opt}, data.FrameTestCompareOptions()...)
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			series, err := tt.seriesToResample.Resample("", tt.interval, tt.downsampler, tt.upsampler, tt.timeRange.From, tt.timeRange.To)
+			series, err := tt.seriesToResample.Resample("", tt.interval, tt.downsampler, tt.upsampler, tt.maxGap, tt.timeRange.From, tt.timeRange.To)
 			if tt.series.Frame == nil {
 				require.Error(t, err)
 			} else {
 				require.NoError(t, err)
-				assert.Equal(t, tt.series, series)
+				if diff := cmp.Diff(tt.series, series, options...); diff != "" {
+					t.Errorf("Result mismatch (-want +got):\n%s", diff)
+				}
 			}
 		})
 	}
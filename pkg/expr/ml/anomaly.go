@@ -0,0 +1,312 @@
+package ml
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// The scoring method of outliers
+// +enum
+type AnomalyScoring string
+
+const (
+	// Number of standard deviations from the mean of the residuals
+	AnomalyScoringZScore AnomalyScoring = "zscore"
+
+	// Number of median absolute deviations from the median of the residuals
+	AnomalyScoringMAD AnomalyScoring = "mad"
+)
+
+const (
+	// AnomalyLabel is the label that distinguishes the score series from the alert series returned by DetectAnomalies.
+	AnomalyLabel = "anomaly"
+
+	AnomalyScoreSeries = "score"
+	AnomalyAlertSeries = "alert"
+
+	// DefaultAnomalyThreshold is the score above which a point is considered anomalous if no threshold is configured.
+	DefaultAnomalyThreshold = 3.0
+
+	// madScale makes the median absolute deviation a consistent estimator of the standard deviation of normally distributed data.
+	madScale = 1.4826
+	// meanADScale makes the mean absolute deviation a consistent estimator of the standard deviation of normally distributed data.
+	meanADScale = 1.2533
+)
+
+// AnomalyDetector scores the points of a series locally, without calling the Machine Learning API.
+// The series is decomposed into trend, seasonal and residual components, and the residuals are scored with Scoring.
+type AnomalyDetector struct {
+	// Season is the length of the seasonal pattern, e.g. 24h. If zero, the series is not decomposed and the values are scored as is.
+	Season time.Duration
+	// Scoring is the method used to score the residuals.
+	Scoring AnomalyScoring
+	// Threshold is the absolute score above which a point is anomalous.
+	Threshold float64
+}
+
+// Validate returns an error if the detector is not configured correctly.
+func (d AnomalyDetector) Validate() error {
+	switch d.Scoring {
+	case AnomalyScoringZScore, AnomalyScoringMAD:
+	default:
+		return fmt.Errorf("unsupported scoring method '%s', should be one of [%s, %s]", d.Scoring, AnomalyScoringZScore, AnomalyScoringMAD)
+	}
+	if d.Season < 0 {
+		return fmt.Errorf("season must not be negative, got %s", d.Season)
+	}
+	if d.Threshold <= 0 || math.IsNaN(d.Threshold) {
+		return fmt.Errorf("threshold must be greater than 0, got %v", d.Threshold)
+	}
+	return nil
+}
+
+// DetectAnomalies scores each point of the series and returns the score series and the alert series.
+// The alert series is 1 where the absolute score exceeds the threshold, and 0 otherwise.
+// Both series have the labels of the input series plus the label AnomalyLabel. Null and NaN values are ignored
+// when computing the decomposition and remain null in both series.
+// The series is expected to be sorted by time and regularly spaced. Resample it first if it is not.
+func (d AnomalyDetector) DetectAnomalies(refID string, s mathexp.Series) (mathexp.Series, mathexp.Series, error) {
+	values := make([]float64, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		v := s.GetValue(i)
+		if v == nil {
+			values[i] = math.NaN()
+			continue
+		}
+		values[i] = *v
+	}
+
+	residuals := values
+	if d.Season > 0 {
+		period, err := seasonPeriod(s, d.Season)
+		if err != nil {
+			return mathexp.Series{}, mathexp.Series{}, err
+		}
+		residuals = decompose(values, period)
+	}
+
+	scores := d.score(residuals)
+
+	scoreSeries := mathexp.NewSeries(refID, anomalyLabels(s.GetLabels(), AnomalyScoreSeries), s.Len())
+	alertSeries := mathexp.NewSeries(refID, anomalyLabels(s.GetLabels(), AnomalyAlertSeries), s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t := s.GetTime(i)
+		if math.IsNaN(scores[i]) {
+			scoreSeries.SetPoint(i, t, nil)
+			alertSeries.SetPoint(i, t, nil)
+			continue
+		}
+		score := scores[i]
+		alert := 0.0
+		if math.Abs(score) > d.Threshold {
+			alert = 1
+		}
+		scoreSeries.SetPoint(i, t, &score)
+		alertSeries.SetPoint(i, t, &alert)
+	}
+	return scoreSeries, alertSeries, nil
+}
+
+// score returns the score of each value, or NaN if the value is NaN.
+func (d AnomalyDetector) score(values []float64) []float64 {
+	valid := make([]float64, 0, len(values))
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			valid = append(valid, v)
+		}
+	}
+
+	var center, scale float64
+	switch d.Scoring {
+	case AnomalyScoringMAD:
+		center = median(valid)
+		deviations := make([]float64, 0, len(valid))
+		for _, v := range valid {
+			deviations = append(deviations, math.Abs(v-center))
+		}
+		scale = madScale * median(deviations)
+		if scale == 0 {
+			// More than half of the values are equal to the median, e.g. a flat series with a spike.
+			// Fall back to the mean absolute deviation so that the values that differ get a finite score.
+			var sum float64
+			for _, d := range deviations {
+				sum += d
+			}
+			if len(deviations) > 0 {
+				scale = meanADScale * sum / float64(len(deviations))
+			}
+		}
+	default:
+		center, scale = meanStdDev(valid)
+	}
+
+	scores := make([]float64, len(values))
+	for i, v := range values {
+		switch {
+		case math.IsNaN(v):
+			scores[i] = math.NaN()
+		case scale == 0:
+			// All the values are equal to the center.
+			scores[i] = 0
+		default:
+			scores[i] = (v - center) / scale
+		}
+	}
+	return scores
+}
+
+// seasonPeriod returns the number of points in a season, based on the median interval between the points of the series.
+func seasonPeriod(s mathexp.Series, season time.Duration) (int, error) {
+	if s.Len() < 2 {
+		return 0, errors.New("at least two points are required to detect the interval of the series")
+	}
+	intervals := make([]float64, 0, s.Len()-1)
+	for i := 1; i < s.Len(); i++ {
+		intervals = append(intervals, float64(s.GetTime(i).Sub(s.GetTime(i-1))))
+	}
+	interval := time.Duration(median(intervals))
+	if interval <= 0 {
+		return 0, errors.New("the points of the series must be sorted by time and have distinct timestamps")
+	}
+	period := int(math.Round(float64(season) / float64(interval)))
+	if period < 2 {
+		return 0, fmt.Errorf("season %s must be at least twice the interval of the series %s", season, interval)
+	}
+	if 2*period > s.Len() {
+		return 0, fmt.Errorf("at least two seasons of data are required, got %d points for a season of %d points", s.Len(), period)
+	}
+	return period, nil
+}
+
+// decompose performs a classical additive decomposition of the values, and returns the residuals
+// after removing the trend (a centered moving average over a season) and the seasonal component.
+func decompose(values []float64, period int) []float64 {
+	trend := movingAverage(values, period)
+
+	// The seasonal component is the average of the detrended values at each position of the season, centered on zero.
+	sums := make([]float64, period)
+	counts := make([]int, period)
+	for i, v := range values {
+		if math.IsNaN(v) || math.IsNaN(trend[i]) {
+			continue
+		}
+		sums[i%period] += v - trend[i]
+		counts[i%period]++
+	}
+	seasonal := make([]float64, period)
+	var total float64
+	var n int
+	for p := range seasonal {
+		if counts[p] > 0 {
+			seasonal[p] = sums[p] / float64(counts[p])
+			total += seasonal[p]
+			n++
+		}
+	}
+	if n > 0 {
+		for p := range seasonal {
+			seasonal[p] -= total / float64(n)
+		}
+	}
+
+	residuals := make([]float64, len(values))
+	for i, v := range values {
+		residuals[i] = v - trend[i] - seasonal[i%period]
+	}
+	return residuals
+}
+
+// movingAverage returns the centered moving average of the values over a window of period points, ignoring NaN values.
+// An even period uses a window of period+1 points with half weights at both ends.
+// Where the window does not fit, the closest computed average is used.
+func movingAverage(values []float64, period int) []float64 {
+	half := period / 2
+	avg := make([]float64, len(values))
+	first, last := -1, -1
+	for i := range values {
+		avg[i] = math.NaN()
+		if i < half || i+half >= len(values) {
+			continue
+		}
+		var sum, weight float64
+		for j := i - half; j <= i+half; j++ {
+			if math.IsNaN(values[j]) {
+				continue
+			}
+			w := 1.0
+			if period%2 == 0 && (j == i-half || j == i+half) {
+				w = 0.5
+			}
+			sum += w * values[j]
+			weight += w
+		}
+		if weight == 0 {
+			continue
+		}
+		avg[i] = sum / weight
+		if first == -1 {
+			first = i
+		}
+		last = i
+	}
+	if first == -1 {
+		return avg
+	}
+	for i := range avg {
+		switch {
+		case i < first:
+			avg[i] = avg[first]
+		case i > last:
+			avg[i] = avg[last]
+		case math.IsNaN(avg[i]):
+			avg[i] = avg[i-1]
+		}
+	}
+	return avg
+}
+
+func anomalyLabels(labels data.Labels, series string) data.Labels {
+	l := data.Labels{}
+	if labels != nil {
+		l = labels.Copy()
+	}
+	l[AnomalyLabel] = series
+	return l
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func meanStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return math.NaN(), math.NaN()
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	var sq float64
+	for _, v := range values {
+		sq += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sq / float64(len(values)))
+}
@@ -0,0 +1,127 @@
+package ml
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// seasonalSeries returns a series with a season of 4 points, 1 minute apart, repeated 4 times.
+// The value at index 10 is 10 instead of 30, which is a common value overall but not at this position of the season.
+func seasonalSeries() mathexp.Series {
+	values := []float64{10, 20, 30, 20, 10, 20, 30, 20, 10, 20, 10, 20, 10, 20, 30, 20}
+	s := mathexp.NewSeries("A", data.Labels{"host": "a"}, len(values))
+	for i, v := range values {
+		s.SetPoint(i, time.Unix(int64(i*60), 0), util.Pointer(v))
+	}
+	return s
+}
+
+func alertIndexes(t *testing.T, alert mathexp.Series) []int {
+	t.Helper()
+	idx := []int{}
+	for i := 0; i < alert.Len(); i++ {
+		v := alert.GetValue(i)
+		require.NotNil(t, v)
+		if *v == 1 {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+func TestAnomalyDetector(t *testing.T) {
+	t.Run("should detect seasonal anomalies", func(t *testing.T) {
+		for _, scoring := range []AnomalyScoring{AnomalyScoringZScore, AnomalyScoringMAD} {
+			t.Run(string(scoring), func(t *testing.T) {
+				d := AnomalyDetector{Season: 4 * time.Minute, Scoring: scoring, Threshold: DefaultAnomalyThreshold}
+				score, alert, err := d.DetectAnomalies("B", seasonalSeries())
+				require.NoError(t, err)
+				require.Equal(t, data.Labels{"host": "a", AnomalyLabel: AnomalyScoreSeries}, score.GetLabels())
+				require.Equal(t, data.Labels{"host": "a", AnomalyLabel: AnomalyAlertSeries}, alert.GetLabels())
+				require.Equal(t, 16, score.Len())
+				require.Equal(t, []int{10}, alertIndexes(t, alert))
+				require.Less(t, *score.GetValue(10), -DefaultAnomalyThreshold)
+			})
+		}
+	})
+
+	t.Run("should not detect seasonal anomalies without season", func(t *testing.T) {
+		d := AnomalyDetector{Scoring: AnomalyScoringMAD, Threshold: DefaultAnomalyThreshold}
+		_, alert, err := d.DetectAnomalies("B", seasonalSeries())
+		require.NoError(t, err)
+		require.Empty(t, alertIndexes(t, alert))
+	})
+
+	t.Run("should score mad without season", func(t *testing.T) {
+		s := mathexp.NewSeries("A", nil, 5)
+		for i, v := range []float64{1, 2, 3, 4, 100} {
+			s.SetPoint(i, time.Unix(int64(i), 0), util.Pointer(v))
+		}
+		d := AnomalyDetector{Scoring: AnomalyScoringMAD, Threshold: DefaultAnomalyThreshold}
+		score, alert, err := d.DetectAnomalies("B", s)
+		require.NoError(t, err)
+		require.InDelta(t, 0, *score.GetValue(2), 1e-9)
+		require.InDelta(t, 97/madScale, *score.GetValue(4), 1e-9)
+		require.Equal(t, []int{4}, alertIndexes(t, alert))
+	})
+
+	t.Run("should score mad of a flat series with an outlier", func(t *testing.T) {
+		values := []float64{5, 5, 5, 5, 5, 5, 5, 5, 5, 50}
+		s := mathexp.NewSeries("A", nil, len(values))
+		for i, v := range values {
+			s.SetPoint(i, time.Unix(int64(i), 0), util.Pointer(v))
+		}
+		d := AnomalyDetector{Scoring: AnomalyScoringMAD, Threshold: DefaultAnomalyThreshold}
+		score, alert, err := d.DetectAnomalies("B", s)
+		require.NoError(t, err)
+		require.InDelta(t, 0, *score.GetValue(0), 1e-9)
+		require.InDelta(t, 45/(meanADScale*4.5), *score.GetValue(9), 1e-9)
+		require.Equal(t, []int{9}, alertIndexes(t, alert))
+	})
+
+	t.Run("should score a constant series as 0", func(t *testing.T) {
+		s := mathexp.NewSeries("A", nil, 4)
+		for i := 0; i < 4; i++ {
+			s.SetPoint(i, time.Unix(int64(i), 0), util.Pointer(5.0))
+		}
+		for _, scoring := range []AnomalyScoring{AnomalyScoringZScore, AnomalyScoringMAD} {
+			d := AnomalyDetector{Scoring: scoring, Threshold: DefaultAnomalyThreshold}
+			score, alert, err := d.DetectAnomalies("B", s)
+			require.NoError(t, err)
+			for i := 0; i < score.Len(); i++ {
+				require.InDelta(t, 0, *score.GetValue(i), 1e-9)
+			}
+			require.Empty(t, alertIndexes(t, alert))
+		}
+	})
+
+	t.Run("should keep null values", func(t *testing.T) {
+		s := seasonalSeries()
+		s.SetPoint(3, s.GetTime(3), nil)
+		d := AnomalyDetector{Season: 4 * time.Minute, Scoring: AnomalyScoringZScore, Threshold: DefaultAnomalyThreshold}
+		score, alert, err := d.DetectAnomalies("B", s)
+		require.NoError(t, err)
+		require.Nil(t, score.GetValue(3))
+		require.Nil(t, alert.GetValue(3))
+		require.NotNil(t, score.GetValue(10))
+	})
+
+	t.Run("should fail if there is less than two seasons of data", func(t *testing.T) {
+		d := AnomalyDetector{Season: 10 * time.Minute, Scoring: AnomalyScoringZScore, Threshold: DefaultAnomalyThreshold}
+		_, _, err := d.DetectAnomalies("B", seasonalSeries())
+		require.ErrorContains(t, err, "at least two seasons of data are required")
+	})
+}
+
+func TestAnomalyDetectorValidate(t *testing.T) {
+	require.NoError(t, AnomalyDetector{Scoring: AnomalyScoringMAD, Threshold: 2}.Validate())
+	require.ErrorContains(t, AnomalyDetector{Scoring: "iqr", Threshold: 2}.Validate(), "unsupported scoring method 'iqr'")
+	require.ErrorContains(t, AnomalyDetector{Scoring: AnomalyScoringMAD, Threshold: 0}.Validate(), "threshold must be greater than 0")
+	require.ErrorContains(t, AnomalyDetector{Scoring: AnomalyScoringMAD, Threshold: 2, Season: -time.Hour}.Validate(), "season must not be negative")
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/expr/classic"
 	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/ml"
 )
 
 // Supported expression types
@@ -29,6 +30,15 @@ const (
 
 	// SQL query
 	QueryTypeSQL QueryType = "sql"
+
+	// Join two query results by label matching
+	QueryTypeJoin QueryType = "join"
+
+	// Detect anomalies in query results
+	QueryTypeAnomaly QueryType = "anomaly"
+
+	// Forecast query results
+	QueryTypeForecast QueryType = "forecast"
 )
 
 type MathQuery struct {
@@ -43,6 +53,9 @@ type ReduceQuery struct {
 	// The reducer
 	Reducer mathexp.ReducerID `json:"reducer"`
 
+	// The percentile (0-100) to compute, required when reducer is percentile
+	Percentile *float64 `json:"percentile,omitempty" jsonschema:"minimum=0,maximum=100,example=90,example=99"`
+
 	// Reducer Options
 	Settings *ReduceSettings `json:"settings,omitempty"`
 }
@@ -60,6 +73,9 @@ type ResampleQuery struct {
 
 	// The upsample function
 	Upsampler mathexp.Upsampler `json:"upsampler"`
+
+	// The maximum number of consecutive empty intervals to upsample, longer gaps are filled with NaN
+	MaxGap int `json:"maxGap,omitempty" jsonschema:"minimum=0,example=3"`
 }
 
 type ThresholdQuery struct {
@@ -80,6 +96,85 @@ type SQLExpression struct {
 	Format     string `json:"format"`
 }
 
+type JoinQuery struct {
+	// Reference to the left query result
+	Left string `json:"left" jsonschema:"minLength=1,example=$A"`
+
+	// Reference to the right query result
+	Right string `json:"right" jsonschema:"minLength=1,example=$B"`
+
+	// The binary operator applied to each pair of matched values
+	Operator string `json:"operator" jsonschema:"minLength=1,example=/,example=-"`
+
+	// Only match values on these labels
+	On []string `json:"on,omitempty"`
+
+	// Match values on all labels except these ones
+	Ignoring []string `json:"ignoring,omitempty"`
+
+	// The cardinality of the join, defaults to one_to_one
+	Cardinality mathexp.JoinCardinality `json:"cardinality,omitempty"`
+
+	// Labels copied from the "one" side of a group_left or group_right join
+	Include []string `json:"include,omitempty"`
+}
+
+func (q JoinQuery) spec() mathexp.JoinSpec {
+	return mathexp.JoinSpec{
+		Operator:    q.Operator,
+		On:          q.On,
+		Ignoring:    q.Ignoring,
+		Cardinality: q.Cardinality,
+		Include:     q.Include,
+	}
+}
+
+type AnomalyQuery struct {
+	// Reference to single query result
+	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
+
+	// The length of the seasonal pattern, empty if the series has no seasonality
+	Season string `json:"season,omitempty" jsonschema:"example=1d,example=1h"`
+
+	// The scoring method of outliers
+	Scoring ml.AnomalyScoring `json:"scoring"`
+
+	// The absolute score above which a point is anomalous, defaults to 3
+	Threshold *float64 `json:"threshold,omitempty" jsonschema:"example=3"`
+}
+
+type ForecastQuery struct {
+	// Reference to single query result
+	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
+
+	// The forecasting model
+	Model mathexp.ForecastModel `json:"model"`
+
+	// The value returned by the forecast
+	Output mathexp.ForecastOutput `json:"output"`
+
+	// The time duration after now at which the value is predicted
+	Horizon string `json:"horizon,omitempty" jsonschema:"example=1d,example=4h"`
+
+	// The value to reach, required when output is time_to_threshold
+	Threshold *float64 `json:"threshold,omitempty" jsonschema:"example=100"`
+
+	// The side of the threshold that is reached, defaults to above
+	Direction mathexp.ForecastDirection `json:"direction,omitempty"`
+
+	// The smoothing factor of the level of the holt model, defaults to 0.5
+	Smoothing *float64 `json:"smoothing,omitempty" jsonschema:"minimum=0,maximum=1,example=0.5"`
+
+	// The smoothing factor of the trend of the holt model, defaults to 0.5
+	Trend *float64 `json:"trend,omitempty" jsonschema:"minimum=0,maximum=1,example=0.5"`
+
+	// The number of values in a season, enables the seasonality of the holt model
+	SeasonLength *int `json:"seasonLength,omitempty" jsonschema:"minimum=0,example=24"`
+
+	// The smoothing factor of the seasonality of the holt model, defaults to 0.5
+	Seasonality *float64 `json:"seasonality,omitempty" jsonschema:"minimum=0,maximum=1,example=0.5"`
+}
+
 //-------------------------------
 // Non-query commands
 //-------------------------------
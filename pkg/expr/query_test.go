@@ -11,6 +11,8 @@ import (
 
 	"github.com/grafana/grafana/pkg/expr/classic"
 	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/ml"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 func TestQueryTypeDefinitions(t *testing.T) {
@@ -27,6 +29,10 @@ func TestQueryTypeDefinitions(t *testing.T) {
 				reflect.TypeOf(ReduceModeDrop),       // pick an example value (not the root)
 				reflect.TypeOf(ThresholdIsAbove),
 				reflect.TypeOf(classic.ConditionOperatorAnd),
+				reflect.TypeOf(mathexp.JoinOneToOne),
+				reflect.TypeOf(ml.AnomalyScoringZScore),
+				reflect.TypeOf(mathexp.ForecastModelLinear),
+				reflect.TypeOf(mathexp.ForecastOutputValue),
 			},
 		})
 	require.NoError(t, err)
@@ -161,6 +167,50 @@ func TestQueryTypeDefinitions(t *testing.T) {
 				},
 			},
 		},
+		schemabuilder.QueryTypeInfo{
+			Discriminators: data.NewDiscriminators("type", QueryTypeJoin),
+			GoType:         reflect.TypeOf(&JoinQuery{}),
+			Examples: []data.QueryExample{
+				{
+					Name: "ratio of errors to requests by host",
+					SaveModel: data.AsUnstructured(JoinQuery{
+						Left:     "$A",
+						Right:    "$B",
+						Operator: "/",
+						On:       []string{"host"},
+					}),
+				},
+			},
+		},
+		schemabuilder.QueryTypeInfo{
+			Discriminators: data.NewDiscriminators("type", QueryTypeAnomaly),
+			GoType:         reflect.TypeOf(&AnomalyQuery{}),
+			Examples: []data.QueryExample{
+				{
+					Name: "daily seasonal anomalies of A",
+					SaveModel: data.AsUnstructured(AnomalyQuery{
+						Expression: "$A",
+						Season:     "1d",
+						Scoring:    ml.AnomalyScoringMAD,
+					}),
+				},
+			},
+		},
+		schemabuilder.QueryTypeInfo{
+			Discriminators: data.NewDiscriminators("type", QueryTypeForecast),
+			GoType:         reflect.TypeOf(&ForecastQuery{}),
+			Examples: []data.QueryExample{
+				{
+					Name: "time until the disk is full",
+					SaveModel: data.AsUnstructured(ForecastQuery{
+						Expression: "$A",
+						Model:      mathexp.ForecastModelLinear,
+						Output:     mathexp.ForecastOutputTimeToThreshold,
+						Threshold:  util.Pointer(100.0),
+					}),
+				},
+			},
+		},
 	)
 
 	require.NoError(t, err)
@@ -10,6 +10,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/expr/classic"
 	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/ml"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 )
 
@@ -90,7 +91,7 @@ func (h *ExpressionQueryReader) ReadQuery(
 		if err == nil {
 			eq.Properties = q
 			eq.Command, err = NewReduceCommand(common.RefID,
-				q.Reducer, referenceVar, mapper)
+				q.Reducer, mathexp.ReducerParams{Percentile: q.Percentile}, referenceVar, mapper)
 		}
 
 	case QueryTypeResample:
@@ -110,6 +111,7 @@ func (h *ExpressionQueryReader) ReadQuery(
 				referenceVar,
 				q.Downsampler,
 				q.Upsampler,
+				q.MaxGap,
 				AbsoluteTimeRange{
 					From: tr.GetFromAsTimeUTC(),
 					To:   tr.GetToAsTimeUTC(),
@@ -178,6 +180,51 @@ func (h *ExpressionQueryReader) ReadQuery(
 			}
 		}
 
+	case QueryTypeJoin:
+		q := &JoinQuery{}
+		err = iter.ReadVal(q)
+		leftVar, rightVar := "", ""
+		if err == nil {
+			leftVar, err = getReferenceVar(q.Left, common.RefID)
+		}
+		if err == nil {
+			rightVar, err = getReferenceVar(q.Right, common.RefID)
+		}
+		if err == nil {
+			eq.Properties = q
+			eq.Command, err = NewJoinCommand(common.RefID, leftVar, rightVar, q.spec())
+		}
+
+	case QueryTypeAnomaly:
+		q := &AnomalyQuery{}
+		err = iter.ReadVal(q)
+		if err == nil {
+			referenceVar, err = getReferenceVar(q.Expression, common.RefID)
+		}
+		var detector ml.AnomalyDetector
+		if err == nil {
+			detector, err = q.detector()
+		}
+		if err == nil {
+			eq.Properties = q
+			eq.Command, err = NewAnomalyCommand(common.RefID, referenceVar, detector)
+		}
+
+	case QueryTypeForecast:
+		q := &ForecastQuery{}
+		err = iter.ReadVal(q)
+		if err == nil {
+			referenceVar, err = getReferenceVar(q.Expression, common.RefID)
+		}
+		var params mathexp.ForecastParams
+		if err == nil {
+			params, err = q.params()
+		}
+		if err == nil {
+			eq.Properties = q
+			eq.Command, err = NewForecastCommand(common.RefID, referenceVar, params)
+		}
+
 	default:
 		err = fmt.Errorf("unknown query type (%s)", common.QueryType)
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/expr/metrics"
+	"github.com/grafana/grafana/pkg/expr/sql"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -63,6 +64,7 @@ type Service struct {
 	pCtxProvider pluginContextProvider
 	features     featuremgmt.FeatureToggles
 	converter    *ResultConverter
+	sqlMacros    *sql.MacroRegistry
 
 	pluginsClient backend.CallResourceHandler
 
@@ -77,7 +79,16 @@ type pluginContextProvider interface {
 }
 
 func ProvideService(cfg *setting.Cfg, pluginClient plugins.Client, pCtxProvider *plugincontext.Provider,
-	features featuremgmt.FeatureToggles, registerer prometheus.Registerer, tracer tracing.Tracer, builder mtdsclient.MTDatasourceClientBuilder) *Service {
+	features featuremgmt.FeatureToggles, registerer prometheus.Registerer, tracer tracing.Tracer, builder mtdsclient.MTDatasourceClientBuilder) (*Service, error) {
+	var sqlMacros *sql.MacroRegistry
+	if cfg != nil {
+		var err error
+		// The macros are checked once at startup, so an invalid definition is reported before any query runs.
+		sqlMacros, err = sql.NewMacroRegistry(cfg.SQLExpressionMacros)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SQL expression macros: %w", err)
+		}
+	}
 	return &Service{
 		cfg:           cfg,
 		dataService:   pluginClient,
@@ -91,7 +102,16 @@ func ProvideService(cfg *setting.Cfg, pluginClient plugins.Client, pCtxProvider
 			Tracer:   tracer,
 		},
 		mtDatasourceClientBuilder: builder,
-	}
+		sqlMacros:                 sqlMacros,
+	}, nil
+}
+
+// WithMTDatasourceClientBuilder returns a copy of the service which queries data sources with the given builder,
+// for services that are shared by requests with their own data source clients.
+func (s *Service) WithMTDatasourceClientBuilder(builder mtdsclient.MTDatasourceClientBuilder) *Service {
+	c := *s
+	c.mtDatasourceClientBuilder = builder
+	return &c
 }
 
 func (s *Service) isDisabled() bool {
@@ -106,12 +126,32 @@ func (s *Service) BuildPipeline(req *Request) (DataPipeline, error) {
 	return s.buildPipeline(req)
 }
 
+// PipelineOption configures the execution of a pipeline by ExecutePipeline.
+type PipelineOption func(*PipelineOptions)
+
+// PipelineOptions are the options of the execution of a pipeline.
+type PipelineOptions struct {
+	// Explanation, if not nil, enables the explain mode: the execution of every node is traced into it.
+	Explanation *PipelineExplanation
+}
+
+// WithExplain enables the explain mode of ExecutePipeline, which traces the execution of every node into the explanation.
+func WithExplain(explanation *PipelineExplanation) PipelineOption {
+	return func(o *PipelineOptions) {
+		o.Explanation = explanation
+	}
+}
+
 // ExecutePipeline executes an expression pipeline and returns all the results.
-func (s *Service) ExecutePipeline(ctx context.Context, now time.Time, pipeline DataPipeline) (*backend.QueryDataResponse, error) {
+func (s *Service) ExecutePipeline(ctx context.Context, now time.Time, pipeline DataPipeline, opts ...PipelineOption) (*backend.QueryDataResponse, error) {
 	ctx, span := s.tracer.Start(ctx, "SSE.ExecutePipeline")
 	defer span.End()
+	options := PipelineOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
 	res := backend.NewQueryDataResponse()
-	vars, err := pipeline.execute(ctx, now, s)
+	vars, err := pipeline.execute(ctx, now, s, options.Explanation)
 	if err != nil {
 		return nil, err
 	}
@@ -124,6 +164,21 @@ func (s *Service) ExecutePipeline(ctx context.Context, now time.Time, pipeline D
 	return res, nil
 }
 
+// SQLDryRun is the result of the dry run of a SQL expression.
+type SQLDryRun struct {
+	Explanation *sql.Explanation
+	Error       error
+}
+
+// DryRunPipeline runs the queries and expressions that the SQL expressions of the pipeline depend on,
+// and explains the SQL expressions instead of running them, so their cost can be checked before saving.
+// It returns the result of the dry run of each SQL expression by refID.
+func (s *Service) DryRunPipeline(ctx context.Context, now time.Time, pipeline DataPipeline) (map[string]SQLDryRun, error) {
+	ctx, span := s.tracer.Start(ctx, "SSE.DryRunPipeline")
+	defer span.End()
+	return pipeline.dryRun(ctx, now, s)
+}
+
 // Create a datasources.DataSource struct from NodeType. Returns error if kind is TypeDatasourceNode or unknown one.
 func DataSourceModelFromNodeType(kind NodeType) (*datasources.DataSource, error) {
 	switch kind {
@@ -10,6 +10,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/expr/sql"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/stretchr/testify/require"
@@ -109,4 +110,30 @@ func TestSQLService(t *testing.T) {
 		require.Error(t, rsp.Responses["B"].Error, "should return sql error on parsing")
 		require.ErrorContains(t, rsp.Responses["B"].Error, "limit expression expected to be numeric")
 	})
+
+	t.Run("dry run explains the query without running it", func(t *testing.T) {
+		s, req := newMockQueryService(resp, newABSQLQueries("SELECT time, value FROM A"))
+		s.features = featuremgmt.WithFeatures(featuremgmt.FlagSqlExpressions)
+		pl, err := s.BuildPipeline(req)
+		require.NoError(t, err)
+
+		res, err := s.DryRunPipeline(context.Background(), time.Now(), pl)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.NoError(t, res["B"].Error)
+		require.NotEmpty(t, res["B"].Explanation.Plan)
+		require.Equal(t, []sql.ExplainInput{{Table: "A", Rows: 1, Columns: 2}}, res["B"].Explanation.Inputs)
+		require.Equal(t, int64(2), res["B"].Explanation.EstimatedOutputCells)
+	})
+
+	t.Run("dry run points to the offending clause", func(t *testing.T) {
+		s, req := newMockQueryService(resp, newABSQLQueries("SELECT value FROM A WHERE TRUNCATE(value, 1) > 0"))
+		s.features = featuremgmt.WithFeatures(featuremgmt.FlagSqlExpressions)
+		pl, err := s.BuildPipeline(req)
+		require.NoError(t, err)
+
+		res, err := s.DryRunPipeline(context.Background(), time.Now(), pl)
+		require.NoError(t, err)
+		require.ErrorContains(t, res["B"].Error, "in WHERE clause at line 1, column 27")
+	})
 }
@@ -213,6 +213,25 @@ func TestSQLExpressionCellLimitFromConfig(t *testing.T) {
 	}
 }
 
+func TestProvideServiceSQLExpressionMacros(t *testing.T) {
+	newService := func(macros map[string]string) (*Service, error) {
+		cfg := setting.NewCfg()
+		cfg.SQLExpressionMacros = macros
+		return ProvideService(cfg, nil, nil, featuremgmt.WithFeatures(), nil, tracing.InitializeTracerForTest(), mtdsclient.NewNullMTDatasourceClientBuilder())
+	}
+
+	t.Run("should parse the macros once", func(t *testing.T) {
+		s, err := newService(map[string]string{"ratio(num, den)": "num / NULLIF(den, 0)"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"ratio"}, s.sqlMacros.Names())
+	})
+
+	t.Run("should fail on invalid macro definitions", func(t *testing.T) {
+		_, err := newService(map[string]string{"abs(v)": "v"})
+		require.ErrorContains(t, err, "it shadows an allowed function")
+	})
+}
+
 func fp(f float64) *float64 {
 	return &f
 }
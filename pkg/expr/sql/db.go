@@ -6,6 +6,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"regexp"
 	"time"
 
 	sqle "github.com/dolthub/go-mysql-server"
@@ -22,11 +24,24 @@ type DB struct{}
 // GoMySQLServerError represents an error from the underlying Go MySQL Server
 type GoMySQLServerError struct {
 	Err error
+
+	// Line and Column are the 1-based position of the offending token in the query, or 0 if it is unknown.
+	Line   int
+	Column int
+	// Clause is the SQL clause the offending token belongs to, such as WHERE, if any.
+	Clause string
 }
 
 // Error implements the error interface
 func (e *GoMySQLServerError) Error() string {
-	return fmt.Sprintf("error in go-mysql-server: %v", e.Err)
+	switch {
+	case e.Line == 0:
+		return fmt.Sprintf("error in go-mysql-server: %v", e.Err)
+	case e.Clause == "":
+		return fmt.Sprintf("error in go-mysql-server: %v (at line %d, column %d)", e.Err, e.Line, e.Column)
+	default:
+		return fmt.Sprintf("error in go-mysql-server: %v (in %s clause at line %d, column %d)", e.Err, e.Clause, e.Line, e.Column)
+	}
 }
 
 // Unwrap provides the original error for errors.Is/As
@@ -34,20 +49,25 @@ func (e *GoMySQLServerError) Unwrap() error {
 	return e.Err
 }
 
-// WrapGoMySQLServerError wraps errors from Go MySQL Server with additional context
-func WrapGoMySQLServerError(err error) error {
+// WrapGoMySQLServerError wraps errors from Go MySQL Server with additional context,
+// including the position and the clause of the offending token in the query when it can be found.
+func WrapGoMySQLServerError(err error, query string) error {
 	// Don't wrap nil errors
 	if err == nil {
 		return nil
 	}
 
-	// Check if it's a function not found error or other specific GMS errors
-	if isFunctionNotFoundError(err) {
-		return &GoMySQLServerError{Err: err}
+	// Check if it's a function, column or table not found error
+	if !isFunctionNotFoundError(err) && !isNotFoundError(err) {
+		// Return original error if it's not one we want to wrap
+		return err
 	}
 
-	// Return original error if it's not one we want to wrap
-	return err
+	wrapped := &GoMySQLServerError{Err: err}
+	if token := offendingToken(err); token != "" {
+		wrapped.Line, wrapped.Column, wrapped.Clause = locateToken(query, token)
+	}
+	return wrapped
 }
 
 // isFunctionNotFoundError checks if the error is related to a function not being found
@@ -55,6 +75,28 @@ func isFunctionNotFoundError(err error) bool {
 	return mysql.ErrFunctionNotFound.Is(err)
 }
 
+// isNotFoundError checks if the error is related to a column or a table not being found
+func isNotFoundError(err error) bool {
+	return mysql.ErrColumnNotFound.Is(err) || mysql.ErrTableNotFound.Is(err)
+}
+
+var (
+	quotedToken        = regexp.MustCompile(`['"]([^'"]+)['"]`)
+	tableNotFoundToken = regexp.MustCompile(`table not found: (\S+)`)
+)
+
+// offendingToken extracts the name of the function, column or table that was not found from the error message.
+func offendingToken(err error) string {
+	msg := err.Error()
+	if m := quotedToken.FindStringSubmatch(msg); m != nil {
+		return m[1]
+	}
+	if m := tableNotFoundToken.FindStringSubmatch(msg); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
 type QueryOption func(*QueryOptions)
 
 type QueryOptions struct {
@@ -100,24 +142,7 @@ func (db *DB) QueryFrames(ctx context.Context, tracer tracing.Tracer, name strin
 	_, span := tracer.Start(ctx, "SSE.ExecuteGMSQuery")
 	defer span.End()
 
-	pro := NewFramesDBProvider(frames)
-	session := mysql.NewBaseSession()
-
-	// Create a new context with the session and tracer
-	mCtx := mysql.NewContext(ctx, mysql.WithSession(session), mysql.WithTracer(tracer))
-
-	// Select the database in the context
-	mCtx.SetCurrentDatabase(dbName)
-
-	// Empty dir does not disable secure_file_priv
-	//ctx.SetSessionVariable(ctx, "secure_file_priv", "")
-
-	// TODO: Check if it's wise to reuse the existing provider, rather than creating a new one
-	a := analyzer.NewDefault(pro)
-
-	engine := sqle.New(a, &sqle.Config{
-		IsReadOnly: true,
-	})
+	engine, mCtx := newEngine(ctx, tracer, frames)
 
 	contextErr := func(err error) error {
 		switch {
@@ -136,7 +161,7 @@ func (db *DB) QueryFrames(ctx context.Context, tracer tracing.Tracer, name strin
 		if ctx.Err() != nil {
 			return nil, contextErr(ctx.Err())
 		}
-		return nil, WrapGoMySQLServerError(err)
+		return nil, WrapGoMySQLServerError(err, query)
 	}
 
 	// Convert the iterator into a Grafana data.Frame
@@ -153,3 +178,97 @@ func (db *DB) QueryFrames(ctx context.Context, tracer tracing.Tracer, name strin
 
 	return f, nil
 }
+
+// ExplainFrames plans the sql query against a database created from frames without running it.
+// It returns the query plan, the input tables with their sizes, and an estimate of the output size.
+// The query goes through the same checks and limits as QueryFrames.
+func (db *DB) ExplainFrames(ctx context.Context, tracer tracing.Tracer, name string, query string, frames []*data.Frame, opts ...QueryOption) (*Explanation, error) {
+	if _, err := AllowQuery(query); err != nil {
+		return nil, err
+	}
+
+	QueryOptions := &QueryOptions{}
+	for _, opt := range opts {
+		opt(QueryOptions)
+	}
+
+	if QueryOptions.Timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, QueryOptions.Timeout)
+		defer cancel()
+	}
+	_, span := tracer.Start(ctx, "SSE.ExplainGMSQuery")
+	defer span.End()
+
+	engine, mCtx := newEngine(ctx, tracer, frames)
+
+	wrapErr := func(err error) error {
+		if ctx.Err() != nil {
+			return fmt.Errorf("SQL expression for refId %v could not be explained: %w", name, ctx.Err())
+		}
+		return WrapGoMySQLServerError(err, query)
+	}
+
+	// Planning and iterator construction only, the iterator is closed without reading any row.
+	schema, iter, _, err := engine.Query(mCtx, query)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+	if err := iter.Close(mCtx); err != nil {
+		return nil, wrapErr(err)
+	}
+
+	plan, err := explainPlan(mCtx, engine, query)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+
+	return newExplanation(query, plan, frames, len(schema), QueryOptions.MaxOutputCells), nil
+}
+
+// newEngine creates a read-only engine over a database created from frames,
+// and a context in which that database is selected.
+func newEngine(ctx context.Context, tracer tracing.Tracer, frames []*data.Frame) (*sqle.Engine, *mysql.Context) {
+	pro := NewFramesDBProvider(frames)
+	session := mysql.NewBaseSession()
+
+	// Create a new context with the session and tracer
+	mCtx := mysql.NewContext(ctx, mysql.WithSession(session), mysql.WithTracer(tracer))
+
+	// Select the database in the context
+	mCtx.SetCurrentDatabase(dbName)
+
+	// Empty dir does not disable secure_file_priv
+	//ctx.SetSessionVariable(ctx, "secure_file_priv", "")
+
+	// TODO: Check if it's wise to reuse the existing provider, rather than creating a new one
+	a := analyzer.NewDefault(pro)
+
+	engine := sqle.New(a, &sqle.Config{
+		IsReadOnly: true,
+	})
+	return engine, mCtx
+}
+
+// explainPlan returns the lines of the plan of the query as printed by EXPLAIN.
+func explainPlan(mCtx *mysql.Context, engine *sqle.Engine, query string) ([]string, error) {
+	_, iter, _, err := engine.Query(mCtx, "EXPLAIN "+query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = iter.Close(mCtx) }()
+
+	plan := []string{}
+	for {
+		row, err := iter.Next(mCtx)
+		if errors.Is(err, io.EOF) {
+			return plan, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range row {
+			plan = append(plan, fmt.Sprint(v))
+		}
+	}
+}
@@ -207,6 +207,52 @@ func TestErrorsFromGoMySQLServerAreFlagged(t *testing.T) {
 	_, err := db.QueryFrames(context.Background(), &testTracer{}, "sqlExpressionRefId", query, nil)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "error in go-mysql-server")
+	require.Contains(t, err.Error(), "in SELECT clause at line 1, column 8")
+
+	var gmsErr *GoMySQLServerError
+	require.ErrorAs(t, err, &gmsErr)
+	require.Equal(t, 1, gmsErr.Line)
+	require.Equal(t, 8, gmsErr.Column)
+}
+
+func TestExplainFrames(t *testing.T) {
+	db := DB{}
+	frames := []*data.Frame{
+		data.NewFrame(
+			"",
+			data.NewField("host", nil, []string{"a", "b", "c"}),
+			data.NewField("value", nil, []float64{1, 2, 3}),
+		).SetRefID("A"),
+	}
+
+	t.Run("should explain the query without running it", func(t *testing.T) {
+		e, err := db.ExplainFrames(context.Background(), &testTracer{}, "B", `SELECT host, value * 2 AS doubled FROM A`, frames)
+		require.NoError(t, err)
+		require.NotEmpty(t, e.Plan)
+		require.Equal(t, []ExplainInput{{Table: "A", Rows: 3, Columns: 2}}, e.Inputs)
+		require.Equal(t, 2, e.OutputColumns)
+		require.Equal(t, int64(3), e.EstimatedOutputRows)
+		require.Equal(t, int64(6), e.EstimatedOutputCells)
+		require.Empty(t, e.Warnings)
+	})
+
+	t.Run("should cap the estimate with the limit", func(t *testing.T) {
+		e, err := db.ExplainFrames(context.Background(), &testTracer{}, "B", `SELECT host FROM A LIMIT 1`, frames)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), e.EstimatedOutputCells)
+	})
+
+	t.Run("should warn when the output cell limit would be exceeded", func(t *testing.T) {
+		e, err := db.ExplainFrames(context.Background(), &testTracer{}, "B", `SELECT * FROM A`, frames, WithMaxOutputCells(4))
+		require.NoError(t, err)
+		require.Len(t, e.Warnings, 1)
+		require.Contains(t, e.Warnings[0], "estimated output of 6 cells exceeds the limit of 4 cells")
+	})
+
+	t.Run("should go through the allow list", func(t *testing.T) {
+		_, err := db.ExplainFrames(context.Background(), &testTracer{}, "B", `SELECT SLEEP(1) FROM A`, frames)
+		require.ErrorContains(t, err, "blocked function")
+	})
 }
 
 func TestFrameToSQLAndBack_JSONRoundtrip(t *testing.T) {
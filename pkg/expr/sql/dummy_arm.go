@@ -19,6 +19,11 @@ func (db *DB) QueryFrames(_ context.Context, _ tracing.Tracer, _, _ string, _ []
 	return nil, fmt.Errorf("sql expressions not supported in arm")
 }
 
+// Stub out the ExplainFrames method for ARM builds
+func (db *DB) ExplainFrames(_ context.Context, _ tracing.Tracer, _, _ string, _ []*data.Frame, _ ...QueryOption) (*Explanation, error) {
+	return nil, fmt.Errorf("sql expressions not supported in arm")
+}
+
 func WithTimeout(_ time.Duration) QueryOption {
 	return func(_ *QueryOptions) {
 		// no-op
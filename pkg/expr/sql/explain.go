@@ -0,0 +1,131 @@
+package sql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// Explanation is the result of a dry run of a SQL expression.
+type Explanation struct {
+	// Plan is the query plan as printed by EXPLAIN, one line per node.
+	Plan []string `json:"plan"`
+	// Inputs are the tables that the query can read, one per input frame.
+	Inputs []ExplainInput `json:"inputs"`
+	// OutputColumns is the number of columns of the result.
+	OutputColumns int `json:"outputColumns"`
+	// EstimatedOutputRows assumes at most one output row per row of the largest input, capped by a top level LIMIT.
+	EstimatedOutputRows int64 `json:"estimatedOutputRows"`
+	// EstimatedOutputCells is EstimatedOutputRows × OutputColumns.
+	EstimatedOutputCells int64 `json:"estimatedOutputCells"`
+	// Warnings are the problems the query is expected to run into, such as exceeding the output cell limit.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ExplainInput describes an input table of a SQL expression.
+type ExplainInput struct {
+	Table   string `json:"table"`
+	Rows    int    `json:"rows"`
+	Columns int    `json:"columns"`
+}
+
+func newExplanation(query string, plan []string, frames []*data.Frame, outputColumns int, maxOutputCells int64) *Explanation {
+	e := &Explanation{
+		Plan:          plan,
+		Inputs:        make([]ExplainInput, 0, len(frames)),
+		OutputColumns: outputColumns,
+	}
+
+	maxRows := int64(1)
+	if len(frames) > 0 {
+		maxRows = 0
+	}
+	for _, f := range frames {
+		rows, _ := f.RowLen()
+		e.Inputs = append(e.Inputs, ExplainInput{Table: f.RefID, Rows: rows, Columns: len(f.Fields)})
+		maxRows = max(maxRows, int64(rows))
+	}
+
+	e.EstimatedOutputRows = maxRows
+	if limit, ok := topLevelLimit(query); ok {
+		e.EstimatedOutputRows = min(e.EstimatedOutputRows, limit)
+	}
+	e.EstimatedOutputCells = e.EstimatedOutputRows * int64(outputColumns)
+
+	// limit of 0 or less means no limit (following convention)
+	if maxOutputCells > 0 && e.EstimatedOutputCells > maxOutputCells {
+		e.Warnings = append(e.Warnings, fmt.Sprintf("estimated output of %d cells exceeds the limit of %d cells, the result will be truncated", e.EstimatedOutputCells, maxOutputCells))
+	}
+	return e
+}
+
+// topLevelLimit returns the row count of the LIMIT clause of the outermost SELECT, if it is a literal.
+func topLevelLimit(query string) (int64, bool) {
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		return 0, false
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok || sel.Limit == nil {
+		return 0, false
+	}
+	val, ok := sel.Limit.Rowcount.(*sqlparser.SQLVal)
+	if !ok || val.Type != sqlparser.IntVal {
+		return 0, false
+	}
+	limit, err := strconv.ParseInt(string(val.Val), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return limit, true
+}
+
+var clauseKeywords = map[string]string{
+	"SELECT": "SELECT",
+	"FROM":   "FROM",
+	"JOIN":   "JOIN",
+	"ON":     "ON",
+	"WHERE":  "WHERE",
+	"GROUP":  "GROUP BY",
+	"HAVING": "HAVING",
+	"WINDOW": "WINDOW",
+	"ORDER":  "ORDER BY",
+	"LIMIT":  "LIMIT",
+}
+
+// locateToken returns the 1-based line and column of the first occurrence of the identifier token in the query,
+// outside of strings and comments, and the clause it belongs to. The line is 0 if the token is not found.
+// Qualified tokens, such as "A.value", are matched on their last part.
+func locateToken(query, token string) (line, column int, clause string) {
+	if i := strings.LastIndexByte(token, '.'); i >= 0 {
+		token = token[i+1:]
+	}
+	for i := 0; i < len(query); {
+		if j := skipLiteral(query, i); j > i {
+			i = j
+			continue
+		}
+		if !isIdentChar(query[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(query) && isIdentChar(query[j]) {
+			j++
+		}
+		word := query[i:j]
+		if strings.EqualFold(word, token) {
+			line = 1 + strings.Count(query[:i], "\n")
+			column = i - strings.LastIndexByte(query[:i], '\n')
+			return line, column, clause
+		}
+		if c, ok := clauseKeywords[strings.ToUpper(word)]; ok {
+			clause = c
+		}
+		i = j
+	}
+	return 0, 0, ""
+}
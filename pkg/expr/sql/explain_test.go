@@ -0,0 +1,76 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocateToken(t *testing.T) {
+	testCases := []struct {
+		name   string
+		query  string
+		token  string
+		line   int
+		column int
+		clause string
+	}{
+		{
+			name:   "token in the select clause",
+			query:  `SELECT nope(value) FROM A`,
+			token:  "nope",
+			line:   1,
+			column: 8,
+			clause: "SELECT",
+		},
+		{
+			name:   "token in the where clause on another line",
+			query:  "SELECT value\nFROM A\nWHERE  missing > 1",
+			token:  "missing",
+			line:   3,
+			column: 8,
+			clause: "WHERE",
+		},
+		{
+			name:   "qualified token",
+			query:  `SELECT * FROM A JOIN B ON A.time = B.missing`,
+			token:  "B.missing",
+			line:   1,
+			column: 38,
+			clause: "ON",
+		},
+		{
+			name:   "ignores strings and comments",
+			query:  "SELECT 'missing' -- missing\nFROM A GROUP BY missing",
+			token:  "missing",
+			line:   2,
+			column: 17,
+			clause: "GROUP BY",
+		},
+		{
+			name:  "token not found",
+			query: `SELECT value FROM A`,
+			token: "missing",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			line, column, clause := locateToken(tc.query, tc.token)
+			require.Equal(t, tc.line, line)
+			require.Equal(t, tc.column, column)
+			require.Equal(t, tc.clause, clause)
+		})
+	}
+}
+
+func TestTopLevelLimit(t *testing.T) {
+	limit, ok := topLevelLimit(`SELECT * FROM A LIMIT 10`)
+	require.True(t, ok)
+	require.Equal(t, int64(10), limit)
+
+	_, ok = topLevelLimit(`SELECT * FROM (SELECT * FROM A LIMIT 10) t`)
+	require.False(t, ok)
+
+	_, ok = topLevelLimit(`SELECT * FROM A`)
+	require.False(t, ok)
+}
@@ -0,0 +1,300 @@
+package sql
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+)
+
+// maxMacroDepth is the maximum number of times macros are expanded in a query,
+// which bounds the expansion of macros that call themselves or each other.
+const maxMacroDepth = 10
+
+var macroIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// MacroRegistry holds the scalar macros defined by an administrator, for example
+//
+//	ratio(num, den) = num / NULLIF(den, 0)
+//
+// Macros are expanded in the text of the query before it is parsed, so the expanded query
+// goes through AllowQuery and the query limits like any other query.
+type MacroRegistry struct {
+	macros map[string]macro
+}
+
+type macro struct {
+	name   string
+	params []string
+	body   string
+}
+
+// NewMacroRegistry parses the macro definitions, which map a signature such as "ratio(num, den)"
+// to the SQL expression it expands to. It returns an error if a signature is invalid, if a macro
+// shadows an allowed function, or if the expansion of a macro is not an allowed query.
+func NewMacroRegistry(definitions map[string]string) (*MacroRegistry, error) {
+	r := &MacroRegistry{macros: make(map[string]macro, len(definitions))}
+	for signature, body := range definitions {
+		m, err := parseMacroSignature(signature)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(body) == "" {
+			return nil, fmt.Errorf("invalid SQL macro %s: empty body", m.name)
+		}
+		if allowedFunction(&sqlparser.FuncExpr{Name: sqlparser.NewColIdent(m.name)}) {
+			return nil, fmt.Errorf("invalid SQL macro %s: it shadows an allowed function", m.name)
+		}
+		key := strings.ToLower(m.name)
+		if _, ok := r.macros[key]; ok {
+			return nil, fmt.Errorf("invalid SQL macro %s: defined more than once", m.name)
+		}
+		m.body = body
+		r.macros[key] = m
+	}
+
+	for _, name := range r.Names() {
+		m := r.macros[name]
+		args := make([]string, len(m.params))
+		for i := range args {
+			args[i] = "NULL"
+		}
+		q, err := r.Expand(fmt.Sprintf("SELECT %s(%s)", m.name, strings.Join(args, ", ")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid SQL macro %s: %w", m.name, err)
+		}
+		if _, err := AllowQuery(q); err != nil {
+			return nil, fmt.Errorf("invalid SQL macro %s: %w", m.name, err)
+		}
+	}
+	return r, nil
+}
+
+// Names returns the sorted names of the registered macros.
+func (r *MacroRegistry) Names() []string {
+	if r == nil {
+		return nil
+	}
+	names := make([]string, 0, len(r.macros))
+	for name := range r.macros {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Expand replaces the calls to macros in the query with their bodies. Names are matched case-insensitively,
+// and string literals, quoted identifiers and comments are left untouched. A nil registry returns the query as is.
+func (r *MacroRegistry) Expand(query string) (string, error) {
+	if r == nil || len(r.macros) == 0 {
+		return query, nil
+	}
+	for i := 0; i < maxMacroDepth; i++ {
+		expanded, changed, err := r.expandOnce(query)
+		if err != nil {
+			return "", err
+		}
+		if !changed {
+			return expanded, nil
+		}
+		query = expanded
+	}
+	return "", fmt.Errorf("macros are nested more than %d levels deep, they might be recursive", maxMacroDepth)
+}
+
+func (r *MacroRegistry) expandOnce(query string) (string, bool, error) {
+	var sb strings.Builder
+	changed := false
+	for i := 0; i < len(query); {
+		if j := skipLiteral(query, i); j > i {
+			sb.WriteString(query[i:j])
+			i = j
+			continue
+		}
+		if !isIdentChar(query[i]) {
+			sb.WriteByte(query[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(query) && isIdentChar(query[j]) {
+			j++
+		}
+		word := query[i:j]
+		m, ok := r.macros[strings.ToLower(word)]
+		open := skipSpaces(query, j)
+		if !ok || isDigit(query[i]) || (i > 0 && query[i-1] == '.') || open >= len(query) || query[open] != '(' {
+			sb.WriteString(word)
+			i = j
+			continue
+		}
+		args, end, err := splitArgs(query, open)
+		if err != nil {
+			return "", false, fmt.Errorf("macro %s: %w", m.name, err)
+		}
+		if len(args) != len(m.params) {
+			return "", false, fmt.Errorf("macro %s expects %d arguments, got %d", m.name, len(m.params), len(args))
+		}
+		sb.WriteString("(")
+		sb.WriteString(m.substitute(args))
+		sb.WriteString(")")
+		i = end
+		changed = true
+	}
+	return sb.String(), changed, nil
+}
+
+// substitute returns the body of the macro with the parameters replaced by the arguments.
+func (m macro) substitute(args []string) string {
+	if len(m.params) == 0 {
+		return m.body
+	}
+	values := make(map[string]string, len(m.params))
+	for i, p := range m.params {
+		values[strings.ToLower(p)] = args[i]
+	}
+	var sb strings.Builder
+	for i := 0; i < len(m.body); {
+		if j := skipLiteral(m.body, i); j > i {
+			sb.WriteString(m.body[i:j])
+			i = j
+			continue
+		}
+		if !isIdentChar(m.body[i]) {
+			sb.WriteByte(m.body[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(m.body) && isIdentChar(m.body[j]) {
+			j++
+		}
+		word := m.body[i:j]
+		arg, ok := values[strings.ToLower(word)]
+		next := skipSpaces(m.body, j)
+		if !ok || isDigit(m.body[i]) || (i > 0 && m.body[i-1] == '.') || (next < len(m.body) && m.body[next] == '(') {
+			sb.WriteString(word)
+		} else {
+			sb.WriteString("(" + arg + ")")
+		}
+		i = j
+	}
+	return sb.String()
+}
+
+// parseMacroSignature parses "name(param, ...)". The parentheses are optional for macros without parameters.
+func parseMacroSignature(signature string) (macro, error) {
+	signature = strings.TrimSpace(signature)
+	m := macro{name: signature}
+	if open := strings.IndexByte(signature, '('); open >= 0 {
+		if !strings.HasSuffix(signature, ")") {
+			return m, fmt.Errorf("invalid SQL macro signature %q: missing closing parenthesis", signature)
+		}
+		m.name = strings.TrimSpace(signature[:open])
+		if params := strings.TrimSpace(signature[open+1 : len(signature)-1]); params != "" {
+			for _, p := range strings.Split(params, ",") {
+				m.params = append(m.params, strings.TrimSpace(p))
+			}
+		}
+	}
+	if !macroIdentifier.MatchString(m.name) {
+		return m, fmt.Errorf("invalid SQL macro signature %q: invalid name %q", signature, m.name)
+	}
+	seen := make(map[string]bool, len(m.params))
+	for _, p := range m.params {
+		if !macroIdentifier.MatchString(p) {
+			return m, fmt.Errorf("invalid SQL macro signature %q: invalid parameter %q", signature, p)
+		}
+		if seen[strings.ToLower(p)] {
+			return m, fmt.Errorf("invalid SQL macro signature %q: duplicate parameter %q", signature, p)
+		}
+		seen[strings.ToLower(p)] = true
+	}
+	return m, nil
+}
+
+// splitArgs splits the comma separated arguments of the call whose opening parenthesis is at open.
+// It returns the trimmed arguments and the index after the closing parenthesis.
+func splitArgs(s string, open int) ([]string, int, error) {
+	var args []string
+	depth := 0
+	start := open + 1
+	for i := open + 1; i < len(s); {
+		if j := skipLiteral(s, i); j > i {
+			i = j
+			continue
+		}
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(s[start:i]))
+				if len(args) == 1 && args[0] == "" {
+					args = nil
+				}
+				return args, i + 1, nil
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+		i++
+	}
+	return nil, 0, fmt.Errorf("missing closing parenthesis")
+}
+
+// skipLiteral returns the index after the quoted string, quoted identifier or comment starting at i,
+// or i if there is none.
+func skipLiteral(s string, i int) int {
+	switch {
+	case s[i] == '\'' || s[i] == '"' || s[i] == '`':
+		q := s[i]
+		for j := i + 1; j < len(s); j++ {
+			switch s[j] {
+			case '\\':
+				if q != '`' {
+					j++
+				}
+			case q:
+				if j+1 < len(s) && s[j+1] == q {
+					j++
+					continue
+				}
+				return j + 1
+			}
+		}
+		return len(s)
+	case s[i] == '#' || strings.HasPrefix(s[i:], "--"):
+		if j := strings.IndexByte(s[i:], '\n'); j >= 0 {
+			return i + j + 1
+		}
+		return len(s)
+	case strings.HasPrefix(s[i:], "/*"):
+		if j := strings.Index(s[i+2:], "*/"); j >= 0 {
+			return i + 2 + j + 2
+		}
+		return len(s)
+	}
+	return i
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t' || s[i] == '\n' || s[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,131 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacroRegistry(t *testing.T) {
+	r, err := NewMacroRegistry(map[string]string{
+		"ratio(num, den)":   "num / NULLIF(den, 0)",
+		"percent(num, den)": "ratio(num, den) * 100",
+		"bytes_to_gb(b)":    "b / POW(1024, 3)",
+		"one":               "1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"bytes_to_gb", "one", "percent", "ratio"}, r.Names())
+
+	testCases := []struct {
+		name     string
+		q        string
+		expected string
+		err      string
+	}{
+		{
+			name:     "expands a macro",
+			q:        `SELECT ratio(errors, requests) AS rate FROM A`,
+			expected: `SELECT ((errors) / NULLIF((requests), 0)) AS rate FROM A`,
+		},
+		{
+			name:     "expands nested macros and nested arguments",
+			q:        `SELECT PERCENT(SUM(errors), ratio(a, b)) FROM A`,
+			expected: `SELECT ((((SUM(errors))) / NULLIF(((((a) / NULLIF((b), 0)))), 0)) * 100) FROM A`,
+		},
+		{
+			name:     "expands a macro without parameters",
+			q:        `SELECT one() + 1`,
+			expected: `SELECT (1) + 1`,
+		},
+		{
+			name:     "leaves strings, quoted identifiers, comments and columns alone",
+			q:        "SELECT 'ratio(a, b)', `ratio(a, b)`, ratio, t.ratio(a) -- ratio(a, b)\nFROM A",
+			expected: "SELECT 'ratio(a, b)', `ratio(a, b)`, ratio, t.ratio(a) -- ratio(a, b)\nFROM A",
+		},
+		{
+			name:     "does not split arguments on commas in strings",
+			q:        `SELECT bytes_to_gb(CONCAT('a,', b))`,
+			expected: `SELECT ((CONCAT('a,', b)) / POW(1024, 3))`,
+		},
+		{
+			name: "fails on the wrong number of arguments",
+			q:    `SELECT ratio(a) FROM A`,
+			err:  "macro ratio expects 2 arguments, got 1",
+		},
+		{
+			name: "fails on unbalanced parentheses",
+			q:    `SELECT ratio(a, (b) FROM A`,
+			err:  "missing closing parenthesis",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := r.Expand(tc.q)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, q)
+		})
+	}
+
+	t.Run("expanded queries still go through the allow list", func(t *testing.T) {
+		q, err := r.Expand(`SELECT percent(errors, requests) FROM A`)
+		require.NoError(t, err)
+		_, err = AllowQuery(q)
+		require.NoError(t, err)
+	})
+}
+
+func TestNewMacroRegistry(t *testing.T) {
+	t.Run("a nil registry does not change the query", func(t *testing.T) {
+		var r *MacroRegistry
+		q, err := r.Expand(`SELECT ratio(a, b)`)
+		require.NoError(t, err)
+		require.Equal(t, `SELECT ratio(a, b)`, q)
+	})
+
+	testCases := []struct {
+		name        string
+		definitions map[string]string
+		err         string
+	}{
+		{
+			name:        "invalid name",
+			definitions: map[string]string{"1ratio(a)": "a"},
+			err:         `invalid name "1ratio"`,
+		},
+		{
+			name:        "duplicate parameter",
+			definitions: map[string]string{"ratio(a, A)": "a"},
+			err:         `duplicate parameter "A"`,
+		},
+		{
+			name:        "shadows an allowed function",
+			definitions: map[string]string{"abs(a)": "a"},
+			err:         "it shadows an allowed function",
+		},
+		{
+			name:        "body uses a blocked function",
+			definitions: map[string]string{"nap()": "sleep(1)"},
+			err:         "blocked function sleep",
+		},
+		{
+			name:        "recursive macros",
+			definitions: map[string]string{"ping(a)": "pong(a)", "pong(a)": "ping(a)"},
+			err:         "they might be recursive",
+		},
+		{
+			name:        "empty body",
+			definitions: map[string]string{"nothing()": " "},
+			err:         "empty body",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewMacroRegistry(tc.definitions)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -165,6 +165,9 @@ func allowedNode(node sqlparser.SQLNode) (b bool) {
 	case *sqlparser.Where:
 		return
 
+	case sqlparser.Window, *sqlparser.WindowDef:
+		return
+
 	default:
 		return false
 	}
@@ -190,7 +193,9 @@ func allowedFunction(f *sqlparser.FuncExpr) (b bool) {
 		return
 	case "row_number", "rank", "dense_rank", "lead", "lag":
 		return
-	case "first_value", "last_value":
+	case "first_value", "last_value", "nth_value":
+		return
+	case "ntile", "percent_rank", "cume_dist":
 		return
 
 	// Mathematical functions
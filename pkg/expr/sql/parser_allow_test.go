@@ -87,6 +87,21 @@ func TestAllowQuery(t *testing.T) {
 			q:    example_window_functions,
 			err:  nil,
 		},
+		{
+			name: "partitioned window functions",
+			q:    example_partitioned_window_functions,
+			err:  nil,
+		},
+		{
+			name: "named window",
+			q:    `SELECT host, SUM(value) OVER w AS running_total FROM A WINDOW w AS (PARTITION BY host ORDER BY time)`,
+			err:  nil,
+		},
+		{
+			name: "recursive cte",
+			q:    `WITH RECURSIVE n AS (SELECT 1 AS i UNION ALL SELECT i + 1 FROM n WHERE i < 5) SELECT i FROM n`,
+			err:  nil,
+		},
 		{
 			name: "json table",
 			q:    "SELECT * FROM mockGitHubIssuesDSResponse, JSON_TABLE(labels, '$[*]' COLUMNS(val VARCHAR(255) PATH '$')) AS jt WHERE CAST(jt.val AS CHAR) LIKE 'type%'",
@@ -355,3 +370,19 @@ SELECT
   FIRST_VALUE(val) OVER (ORDER BY val) as first_val,
   LAST_VALUE(val) OVER (ORDER BY val ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING) as last_val
 FROM dummy_data;`
+
+var example_partitioned_window_functions = `
+WITH per_host AS (
+  SELECT host, time, value FROM A
+)
+SELECT
+  host,
+  time,
+  SUM(value) OVER (PARTITION BY host ORDER BY time) as running_total,
+  value - LAG(value, 1, 0) OVER (PARTITION BY host ORDER BY time) as delta,
+  LEAD(value) OVER (PARTITION BY host ORDER BY time) as next_value,
+  NTILE(4) OVER (ORDER BY value) as quartile,
+  PERCENT_RANK() OVER (PARTITION BY host ORDER BY value) as pct_rank,
+  CUME_DIST() OVER (PARTITION BY host ORDER BY value) as cume_dist,
+  NTH_VALUE(value, 2) OVER (PARTITION BY host ORDER BY time) as second_value
+FROM per_host;`
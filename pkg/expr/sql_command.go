@@ -76,7 +76,7 @@ func NewSQLCommand(refID, format, rawSQL string, intputLimit, outputLimit int64,
 }
 
 // UnmarshalSQLCommand creates a SQLCommand from Grafana's frontend query.
-func UnmarshalSQLCommand(rn *rawNode, cfg *setting.Cfg) (*SQLCommand, error) {
+func UnmarshalSQLCommand(rn *rawNode, cfg *setting.Cfg, macros *sql.MacroRegistry) (*SQLCommand, error) {
 	if rn.TimeRange == nil {
 		logger.Error("time range must be specified for refID", "refID", rn.RefID)
 		return nil, fmt.Errorf("time range must be specified for refID %s", rn.RefID)
@@ -93,6 +93,11 @@ func UnmarshalSQLCommand(rn *rawNode, cfg *setting.Cfg) (*SQLCommand, error) {
 		return nil, fmt.Errorf("expected sql expression to be type string, but got type %T", expressionRaw)
 	}
 
+	expression, err := macros.Expand(expression)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand macros in the sql expression for refID %s: %w", rn.RefID, err)
+	}
+
 	formatRaw := rn.Query["format"]
 	format, _ := formatRaw.(string)
 
@@ -127,16 +132,7 @@ func (gr *SQLCommand) Execute(ctx context.Context, now time.Time, vars mathexp.V
 		metrics.SqlCommandCellCount.WithLabelValues(statusLabel).Observe(float64(tc))
 	}()
 
-	allFrames := []*data.Frame{}
-	for _, ref := range gr.varsToQuery {
-		results, ok := vars[ref]
-		if !ok {
-			logger.Warn("no results found for", "ref", ref)
-			continue
-		}
-		frames := results.Values.AsDataFrames(ref)
-		allFrames = append(allFrames, frames...)
-	}
+	allFrames := gr.inputFrames(vars)
 
 	tc = totalCells(allFrames)
 
@@ -190,6 +186,47 @@ func (gr *SQLCommand) Execute(ctx context.Context, now time.Time, vars mathexp.V
 	return rsp, nil
 }
 
+// Explain plans the query against the results of its inputs without running it.
+// The input cell limit is reported as a warning rather than an error, so the whole explanation can be returned.
+func (gr *SQLCommand) Explain(ctx context.Context, vars mathexp.Vars, tracer tracing.Tracer) (*sql.Explanation, error) {
+	ctx, span := tracer.Start(ctx, "SSE.ExplainSQL")
+	defer span.End()
+
+	allFrames := gr.inputFrames(vars)
+
+	db := sql.DB{}
+	explanation, err := db.ExplainFrames(ctx, tracer, gr.refID, gr.query, allFrames, sql.WithMaxOutputCells(gr.outputLimit), sql.WithTimeout(gr.timeout))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ref := range gr.varsToQuery {
+		if _, ok := vars[ref]; !ok {
+			explanation.Warnings = append(explanation.Warnings, fmt.Sprintf("no results found for %s", ref))
+		}
+	}
+	// limit of 0 or less means no limit (following convention)
+	if tc := totalCells(allFrames); gr.inputLimit > 0 && tc > gr.inputLimit {
+		explanation.Warnings = append(explanation.Warnings, fmt.Sprintf("total cell count across all input tables exceeds limit of %d. Total cells: %d", gr.inputLimit, tc))
+	}
+	return explanation, nil
+}
+
+// inputFrames returns the frames of the results of the tables used by the query.
+func (gr *SQLCommand) inputFrames(vars mathexp.Vars) []*data.Frame {
+	allFrames := []*data.Frame{}
+	for _, ref := range gr.varsToQuery {
+		results, ok := vars[ref]
+		if !ok {
+			logger.Warn("no results found for", "ref", ref)
+			continue
+		}
+		frames := results.Values.AsDataFrames(ref)
+		allFrames = append(allFrames, frames...)
+	}
+	return allFrames
+}
+
 func (gr *SQLCommand) Type() string {
 	return TypeSQL.String()
 }
@@ -11,6 +11,8 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 	"github.com/grafana/grafana/pkg/expr/metrics"
+	"github.com/grafana/grafana/pkg/expr/sql"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/trace"
@@ -36,6 +38,37 @@ func TestNewCommand(t *testing.T) {
 	}
 }
 
+func TestUnmarshalSQLCommandMacros(t *testing.T) {
+	cfg := setting.NewCfg()
+	macros, err := sql.NewMacroRegistry(map[string]string{"ratio(num, den)": "num / NULLIF(den, 0)"})
+	require.NoError(t, err)
+	rn := func(expression string) *rawNode {
+		return &rawNode{
+			RefID:     "C",
+			Query:     map[string]any{"expression": expression},
+			TimeRange: RelativeTimeRange{},
+		}
+	}
+
+	t.Run("should expand macros before listing the tables", func(t *testing.T) {
+		cmd, err := UnmarshalSQLCommand(rn("SELECT ratio(A.errors, B.requests) AS rate FROM A JOIN B ON A.time = B.time"), cfg, macros)
+		require.NoError(t, err)
+		require.Equal(t, "SELECT ((A.errors) / NULLIF((B.requests), 0)) AS rate FROM A JOIN B ON A.time = B.time", cmd.query)
+		require.ElementsMatch(t, []string{"A", "B"}, cmd.NeedsVars())
+	})
+
+	t.Run("should fail on invalid macro calls", func(t *testing.T) {
+		_, err := UnmarshalSQLCommand(rn("SELECT ratio(errors) FROM A"), cfg, macros)
+		require.ErrorContains(t, err, "macro ratio expects 2 arguments, got 1")
+	})
+
+	t.Run("should not expand anything without macros", func(t *testing.T) {
+		cmd, err := UnmarshalSQLCommand(rn("SELECT * FROM A"), cfg, nil)
+		require.NoError(t, err)
+		require.Equal(t, "SELECT * FROM A", cmd.query)
+	})
+}
+
 // Helper function for creating test data
 func createFrameWithRowsAndCols(rows int, cols int) *data.Frame {
 	frame := data.NewFrame("dummy")
@@ -140,11 +140,14 @@ func UnmarshalThresholdCommand(rn *rawNode) (Command, error) {
 	}
 	referenceVar := cmdConfig.Expression
 
-	// we only support one condition for now, we might want to turn this in to "OR" expressions later
-	if len(cmdConfig.Conditions) != 1 {
-		return nil, fmt.Errorf("threshold expression requires exactly one condition")
+	// more than one condition is only supported as levels of severity, we might want to turn this in to "OR" expressions later
+	if len(cmdConfig.Conditions) == 0 {
+		return nil, fmt.Errorf("threshold expression requires at least one condition")
 	}
 	firstCondition := cmdConfig.Conditions[0]
+	if len(cmdConfig.Conditions) > 1 || firstCondition.Severity != "" {
+		return unmarshalMultiLevelThresholdCommand(rn, referenceVar, cmdConfig.Conditions)
+	}
 
 	threshold, err := NewThresholdCommand(rn.RefID, referenceVar, firstCondition.Evaluator.Type, firstCondition.Evaluator.Params)
 	if err != nil {
@@ -240,6 +243,9 @@ type ThresholdConditionJSON struct {
 	Evaluator        ConditionEvalJSON  `json:"evaluator"`
 	UnloadEvaluator  *ConditionEvalJSON `json:"unloadEvaluator,omitempty"`
 	LoadedDimensions *data.Frame        `json:"loadedDimensions,omitempty"`
+	// The severity of the level of the condition, such as warning or critical.
+	// Required when there is more than one condition. Conditions are ordered from the lowest to the highest severity.
+	Severity string `json:"severity,omitempty"`
 }
 
 // IsHysteresisExpression returns true if the raw model describes a hysteresis command:
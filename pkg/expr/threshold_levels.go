@@ -0,0 +1,241 @@
+package expr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/metrics"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// ThresholdSeverityLabel is the label that MultiLevelThresholdCommand adds to the numbers that breach a level.
+// Its value is the severity of the highest breached level. The alerting engine removes it from the result labels
+// and reports it as the severity of the result.
+const ThresholdSeverityLabel = "__severity__"
+
+// ThresholdLevel is a level of a MultiLevelThresholdCommand.
+type ThresholdLevel struct {
+	Severity string
+	// Threshold is the condition that breaches the level.
+	Threshold ThresholdCommand
+	// Unloading, if not nil, is the inverted condition that is used instead of Threshold
+	// for the dimensions that breached the level or a higher level during the previous evaluation.
+	Unloading *ThresholdCommand
+	// LoadedDimensions are the fingerprints of the dimensions that breached the level or a higher level during the previous evaluation.
+	LoadedDimensions Fingerprints
+}
+
+// MultiLevelThresholdCommand evaluates several levels of thresholds, ordered from the lowest to the highest severity,
+// each with an optional hysteresis. The result is, for each value, the 1-based index of the highest level that is breached,
+// or 0 if no level is breached.
+type MultiLevelThresholdCommand struct {
+	RefID        string
+	ReferenceVar string
+	Levels       []ThresholdLevel
+}
+
+// NewMultiLevelThresholdCommand creates a new MultiLevelThresholdCommand.
+func NewMultiLevelThresholdCommand(refID, referenceVar string, levels []ThresholdLevel) (*MultiLevelThresholdCommand, error) {
+	if len(levels) == 0 {
+		return nil, errors.New("multi-level threshold requires at least one level")
+	}
+	seen := make(map[string]struct{}, len(levels))
+	for i, l := range levels {
+		if l.Severity == "" {
+			return nil, fmt.Errorf("level %d of the threshold has no severity", i)
+		}
+		if _, ok := seen[l.Severity]; ok {
+			return nil, fmt.Errorf("severity %s is used by more than one level of the threshold", l.Severity)
+		}
+		seen[l.Severity] = struct{}{}
+	}
+	return &MultiLevelThresholdCommand{
+		RefID:        refID,
+		ReferenceVar: referenceVar,
+		Levels:       levels,
+	}, nil
+}
+
+func unmarshalMultiLevelThresholdCommand(rn *rawNode, referenceVar string, conditions []ThresholdConditionJSON) (*MultiLevelThresholdCommand, error) {
+	levels := make([]ThresholdLevel, 0, len(conditions))
+	for i, c := range conditions {
+		threshold, err := NewThresholdCommand(rn.RefID, referenceVar, c.Evaluator.Type, c.Evaluator.Params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition of level %d: %w", i, err)
+		}
+		level := ThresholdLevel{Severity: c.Severity, Threshold: *threshold}
+		if c.UnloadEvaluator != nil {
+			level.Unloading, err = NewThresholdCommand(rn.RefID, referenceVar, c.UnloadEvaluator.Type, c.UnloadEvaluator.Params)
+			if err != nil {
+				return nil, fmt.Errorf("invalid unloadCondition of level %d: %w", i, err)
+			}
+			level.Unloading.Invert = true
+			if c.LoadedDimensions != nil {
+				level.LoadedDimensions, err = FingerprintsFromFrame(c.LoadedDimensions)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse loaded dimensions of level %d: %w", i, err)
+				}
+			}
+		}
+		levels = append(levels, level)
+	}
+	return NewMultiLevelThresholdCommand(rn.RefID, referenceVar, levels)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (m *MultiLevelThresholdCommand) NeedsVars() []string {
+	return []string{m.ReferenceVar}
+}
+
+func (m *MultiLevelThresholdCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer, _ *metrics.ExprMetrics) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteMultiLevelThreshold")
+	span.SetAttributes(attribute.Int("levels", len(m.Levels)))
+	defer span.End()
+
+	refVarResult := vars[m.ReferenceVar]
+	newRes := mathexp.Results{Values: make(mathexp.Values, 0, len(refVarResult.Values))}
+	for _, val := range refVarResult.Values {
+		switch v := val.(type) {
+		case mathexp.Series:
+			fp := v.GetLabels().Fingerprint()
+			s := mathexp.NewSeries(m.RefID, v.GetLabels(), v.Len())
+			for i := 0; i < v.Len(); i++ {
+				t, value := v.GetPoint(i)
+				level := m.level(fp, value)
+				s.SetPoint(i, t, levelValue(value, level))
+			}
+			newRes.Values = append(newRes.Values, s)
+		case mathexp.Number:
+			value := v.GetFloat64Value()
+			level := m.level(v.GetLabels().Fingerprint(), value)
+			labels := v.GetLabels()
+			if level > 0 {
+				labels = labels.Copy()
+				if labels == nil {
+					labels = data.Labels{}
+				}
+				labels[ThresholdSeverityLabel] = m.Levels[level-1].Severity
+			}
+			copyV := mathexp.NewNumber(m.RefID, labels)
+			copyV.SetValue(levelValue(value, level))
+			newRes.Values = append(newRes.Values, copyV)
+		case mathexp.Scalar:
+			value := v.GetFloat64Value()
+			copyV := mathexp.NewScalar(m.RefID, levelValue(value, m.level(v.GetLabels().Fingerprint(), value)))
+			newRes.Values = append(newRes.Values, copyV)
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, mathexp.NewNoData())
+		default:
+			return newRes, fmt.Errorf("unsupported format of the input data, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// level returns the 1-based index of the highest level breached by the value of the dimension, or 0 if none is breached.
+func (m *MultiLevelThresholdCommand) level(fp data.Fingerprint, value *float64) int {
+	if value == nil {
+		return 0
+	}
+	breached := 0
+	for i, l := range m.Levels {
+		threshold := l.Threshold
+		if _, loaded := l.LoadedDimensions[fp]; loaded && l.Unloading != nil {
+			threshold = *l.Unloading
+		}
+		result := threshold.predicate.Eval(*value)
+		if threshold.Invert {
+			result = !result
+		}
+		if result {
+			breached = i + 1
+		}
+	}
+	return breached
+}
+
+func levelValue(value *float64, level int) *float64 {
+	if value == nil {
+		return nil
+	}
+	return util.Pointer(float64(level))
+}
+
+func (m *MultiLevelThresholdCommand) Type() string {
+	return TypeThreshold.String()
+}
+
+// IsMultiLevelThresholdExpression returns true if the raw model describes a multi-level threshold command:
+// - field 'type' has value "threshold",
+// - field 'conditions' is array of objects, each of which has a field 'severity'.
+func IsMultiLevelThresholdExpression(query map[string]any) bool {
+	conditions, err := getConditionsForMultiLevelThresholdCommand(query)
+	return err == nil && conditions != nil
+}
+
+// SetLoadedSeveritiesToMultiLevelThresholdCommand mutates the input map and sets field "loadedDimensions" of every condition
+// that has an unload evaluator with the data frame created from the fingerprints whose severity is of that level or higher.
+// Severities that do not match any level are ignored.
+func SetLoadedSeveritiesToMultiLevelThresholdCommand(query map[string]any, severities map[data.Fingerprint]string) error {
+	conditions, err := getConditionsForMultiLevelThresholdCommand(query)
+	if err != nil {
+		return err
+	}
+	if conditions == nil {
+		return errors.New("not a multi-level threshold command")
+	}
+	levels := make(map[string]int, len(conditions))
+	for i, c := range conditions {
+		levels[c["severity"].(string)] = i
+	}
+	for i, c := range conditions {
+		if _, ok := c["unloadEvaluator"]; !ok {
+			continue
+		}
+		loaded := Fingerprints{}
+		for fp, severity := range severities {
+			if level, ok := levels[severity]; ok && level >= i {
+				loaded[fp] = struct{}{}
+			}
+		}
+		c["loadedDimensions"] = FingerprintsToFrame(loaded)
+	}
+	return nil
+}
+
+func getConditionsForMultiLevelThresholdCommand(query map[string]any) ([]map[string]any, error) {
+	t, err := GetExpressionCommandType(query)
+	if err != nil {
+		return nil, err
+	}
+	if t != TypeThreshold {
+		return nil, errors.New("not a threshold command")
+	}
+	arr, ok := query["conditions"].([]any)
+	if !ok {
+		return nil, errors.New("invalid threshold command: field \"conditions\" expected to be an array of objects")
+	}
+	conditions := make([]map[string]any, 0, len(arr))
+	for _, item := range arr {
+		condition, ok := item.(map[string]any)
+		if !ok {
+			return nil, errors.New("invalid threshold command: elements of field \"conditions\" expected to be objects")
+		}
+		if severity, ok := condition["severity"].(string); !ok || severity == "" {
+			return nil, nil
+		}
+		conditions = append(conditions, condition)
+	}
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+	return conditions, nil
+}
@@ -0,0 +1,192 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+const multiLevelThresholdQuery = `{
+	"expression": "A",
+	"type": "threshold",
+	"conditions": [
+		{
+			"severity": "warning",
+			"evaluator": { "type": "gt", "params": [50] },
+			"unloadEvaluator": { "type": "lt", "params": [40] }
+		},
+		{
+			"severity": "critical",
+			"evaluator": { "type": "gt", "params": [90] },
+			"unloadEvaluator": { "type": "lt", "params": [80] }
+		}
+	]
+}`
+
+func TestUnmarshalMultiLevelThresholdCommand(t *testing.T) {
+	t.Run("unmarshal levels", func(t *testing.T) {
+		cmd, err := UnmarshalThresholdCommand(&rawNode{RefID: "B", QueryRaw: []byte(multiLevelThresholdQuery)})
+		require.NoError(t, err)
+		require.IsType(t, &MultiLevelThresholdCommand{}, cmd)
+		m := cmd.(*MultiLevelThresholdCommand)
+		require.Equal(t, []string{"A"}, m.NeedsVars())
+		require.Len(t, m.Levels, 2)
+		require.Equal(t, "warning", m.Levels[0].Severity)
+		require.Equal(t, greaterThanPredicate{50.0}, m.Levels[0].Threshold.predicate)
+		require.Equal(t, lessThanPredicate{40.0}, m.Levels[0].Unloading.predicate)
+		require.True(t, m.Levels[0].Unloading.Invert)
+		require.Equal(t, "critical", m.Levels[1].Severity)
+	})
+
+	t.Run("unmarshal a single level with a severity", func(t *testing.T) {
+		cmd, err := UnmarshalThresholdCommand(&rawNode{RefID: "B", QueryRaw: []byte(`{
+			"expression": "A",
+			"conditions": [{ "severity": "critical", "evaluator": { "type": "gt", "params": [90] } }]
+		}`)})
+		require.NoError(t, err)
+		require.IsType(t, &MultiLevelThresholdCommand{}, cmd)
+	})
+
+	t.Run("error if a level has no severity", func(t *testing.T) {
+		_, err := UnmarshalThresholdCommand(&rawNode{RefID: "B", QueryRaw: []byte(`{
+			"expression": "A",
+			"conditions": [
+				{ "severity": "warning", "evaluator": { "type": "gt", "params": [50] } },
+				{ "evaluator": { "type": "gt", "params": [90] } }
+			]
+		}`)})
+		require.ErrorContains(t, err, "level 1 of the threshold has no severity")
+	})
+
+	t.Run("error if severities are not unique", func(t *testing.T) {
+		_, err := UnmarshalThresholdCommand(&rawNode{RefID: "B", QueryRaw: []byte(`{
+			"expression": "A",
+			"conditions": [
+				{ "severity": "warning", "evaluator": { "type": "gt", "params": [50] } },
+				{ "severity": "warning", "evaluator": { "type": "gt", "params": [90] } }
+			]
+		}`)})
+		require.ErrorContains(t, err, "severity warning is used by more than one level")
+	})
+
+	t.Run("error if a level is invalid", func(t *testing.T) {
+		_, err := UnmarshalThresholdCommand(&rawNode{RefID: "B", QueryRaw: []byte(`{
+			"expression": "A",
+			"conditions": [
+				{ "severity": "warning", "evaluator": { "type": "gt", "params": [50] } },
+				{ "severity": "critical", "evaluator": { "type": "foo", "params": [90] } }
+			]
+		}`)})
+		require.ErrorContains(t, err, "invalid condition of level 1")
+	})
+}
+
+func TestMultiLevelThresholdExecute(t *testing.T) {
+	number := func(label string, value float64, severity string) mathexp.Number {
+		labels := data.Labels{"label": label}
+		if severity != "" {
+			labels[ThresholdSeverityLabel] = severity
+		}
+		n := mathexp.NewNumber("B", labels)
+		n.SetValue(&value)
+		return n
+	}
+	fingerprint := func(label string) data.Fingerprint {
+		return data.Labels{"label": label}.Fingerprint()
+	}
+
+	cmd, err := UnmarshalThresholdCommand(&rawNode{RefID: "B", QueryRaw: []byte(multiLevelThresholdQuery)})
+	require.NoError(t, err)
+	m := cmd.(*MultiLevelThresholdCommand)
+	// "loaded" was warning, "escalated" was critical during the previous evaluation.
+	m.Levels[0].LoadedDimensions = Fingerprints{fingerprint("loaded"): {}, fingerprint("escalated"): {}}
+	m.Levels[1].LoadedDimensions = Fingerprints{fingerprint("escalated"): {}}
+
+	vars := mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{
+		number("normal", 45, ""),
+		number("warning", 60, ""),
+		number("critical", 95, ""),
+		number("loaded", 45, ""),
+		number("escalated", 85, ""),
+	}}}
+	result, err := m.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest(), nil)
+	require.NoError(t, err)
+	require.Equal(t, mathexp.Values{
+		number("normal", 0, ""),
+		number("warning", 1, "warning"),
+		number("critical", 2, "critical"),
+		number("loaded", 1, "warning"),
+		number("escalated", 2, "critical"),
+	}, result.Values)
+
+	t.Run("return NoData when no data", func(t *testing.T) {
+		result, err := m.Execute(context.Background(), time.Now(), mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}}, tracing.InitializeTracerForTest(), nil)
+		require.NoError(t, err)
+		require.True(t, result.IsNoData())
+	})
+}
+
+func TestIsMultiLevelThresholdExpression(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{
+			name:     "false if it is not threshold type",
+			input:    `{ "type": "reduce" }`,
+			expected: false,
+		},
+		{
+			name:     "false if conditions have no severity",
+			input:    `{ "type": "threshold", "conditions": [{}, {}] }`,
+			expected: false,
+		},
+		{
+			name:     "false if a condition has no severity",
+			input:    `{ "type": "threshold", "conditions": [{ "severity": "warning" }, {}] }`,
+			expected: false,
+		},
+		{
+			name:     "true if all conditions have a severity",
+			input:    `{ "type": "threshold", "conditions": [{ "severity": "warning" }, { "severity": "critical" }] }`,
+			expected: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			query := map[string]any{}
+			require.NoError(t, json.Unmarshal([]byte(tc.input), &query))
+			require.Equal(t, tc.expected, IsMultiLevelThresholdExpression(query))
+		})
+	}
+}
+
+func TestSetLoadedSeveritiesToMultiLevelThresholdCommand(t *testing.T) {
+	t.Run("error if not a multi-level threshold", func(t *testing.T) {
+		query := map[string]any{}
+		require.NoError(t, json.Unmarshal([]byte(`{ "type": "threshold", "conditions": [{}] }`), &query))
+		require.Error(t, SetLoadedSeveritiesToMultiLevelThresholdCommand(query, nil))
+	})
+
+	t.Run("loads the dimensions of each level and the levels above", func(t *testing.T) {
+		query := map[string]any{}
+		require.NoError(t, json.Unmarshal([]byte(multiLevelThresholdQuery), &query))
+		require.NoError(t, SetLoadedSeveritiesToMultiLevelThresholdCommand(query, map[data.Fingerprint]string{1: "warning", 2: "critical", 3: "unknown"}))
+		raw, err := json.Marshal(query)
+		require.NoError(t, err)
+
+		cmd, err := UnmarshalThresholdCommand(&rawNode{RefID: "B", QueryRaw: raw})
+		require.NoError(t, err)
+		m := cmd.(*MultiLevelThresholdCommand)
+		require.Equal(t, Fingerprints{1: {}, 2: {}}, m.Levels[0].LoadedDimensions)
+		require.Equal(t, Fingerprints{2: {}}, m.Levels[1].LoadedDimensions)
+	})
+}
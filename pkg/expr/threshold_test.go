@@ -191,7 +191,7 @@ func TestUnmarshalThresholdCommand(t *testing.T) {
 				"conditions": []
 			}`,
 			shouldError:   true,
-			expectedError: "threshold expression requires exactly one condition",
+			expectedError: "threshold expression requires at least one condition",
 		},
 		{
 			description: "unmarshal with unsupported threshold function",
@@ -27,6 +27,7 @@ type InstanceConfigurationSettings struct {
 	SQLExpressionCellLimit       int64
 	SQLExpressionOutputCellLimit int64
 	SQLExpressionTimeout         time.Duration
+	SQLExpressionMacros          map[string]string
 	ExpressionsEnabled           bool
 }
 
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"slices"
 	"strconv"
+	"sync"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/experimental/apis/data/v0alpha1"
@@ -26,6 +27,8 @@ import (
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	query "github.com/grafana/grafana/pkg/apis/query/v0alpha1"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/registry/apis/query/clientapi"
 	ds_service "github.com/grafana/grafana/pkg/services/datasources/service"
 	service "github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/web"
@@ -241,22 +244,14 @@ func handleQuery(ctx context.Context, raw query.QueryDataRequest, b QueryAPIBuil
 		b.log,
 	)
 
-	exprService := expr.ProvideService(
-		&setting.Cfg{
-			ExpressionsEnabled:           instanceConfig.ExpressionsEnabled,
-			SQLExpressionCellLimit:       instanceConfig.SQLExpressionCellLimit,
-			SQLExpressionOutputCellLimit: instanceConfig.SQLExpressionOutputCellLimit,
-			SQLExpressionTimeout:         instanceConfig.SQLExpressionTimeout,
-		},
-		nil,
-		nil,
-		instanceConfig.FeatureToggles,
-		nil,
-		b.tracer,
-		mtDsClientBuilder,
-	)
+	exprService, err := b.exprService.get(instanceConfig, b.tracer)
+	if err != nil {
+		b.log.Error("failed to create the expression service", "err", err)
+		responder.Error(err)
+		return nil, err
+	}
 
-	qdr, err := service.QueryData(ctx, b.log, cache, exprService, mReq, mtDsClientBuilder, headers)
+	qdr, err := service.QueryData(ctx, b.log, cache, exprService.WithMTDatasourceClientBuilder(mtDsClientBuilder), mReq, mtDsClientBuilder, headers)
 
 	if err != nil {
 		return qdr, err
@@ -265,6 +260,35 @@ func handleQuery(ctx context.Context, raw query.QueryDataRequest, b QueryAPIBuil
 	return qdr, nil
 }
 
+// exprServiceHolder builds the expression service once, from the instance configuration of the first query,
+// so that the SQL expression macros are not parsed again for every query.
+type exprServiceHolder struct {
+	once    sync.Once
+	service *expr.Service
+	err     error
+}
+
+func (h *exprServiceHolder) get(instanceConfig clientapi.InstanceConfigurationSettings, tracer tracing.Tracer) (*expr.Service, error) {
+	h.once.Do(func() {
+		h.service, h.err = expr.ProvideService(
+			&setting.Cfg{
+				ExpressionsEnabled:           instanceConfig.ExpressionsEnabled,
+				SQLExpressionCellLimit:       instanceConfig.SQLExpressionCellLimit,
+				SQLExpressionOutputCellLimit: instanceConfig.SQLExpressionOutputCellLimit,
+				SQLExpressionTimeout:         instanceConfig.SQLExpressionTimeout,
+				SQLExpressionMacros:          instanceConfig.SQLExpressionMacros,
+			},
+			nil,
+			nil,
+			instanceConfig.FeatureToggles,
+			nil,
+			tracer,
+			nil,
+		)
+	})
+	return h.service, h.err
+}
+
 type responderWrapper struct {
 	wrapped    rest.Responder
 	onObjectFn func(statusCode *int, obj runtime.Object)
@@ -165,6 +165,7 @@ func TestQueryAPI(t *testing.T) {
 				tracer:                 tracing.InitializeTracerForTest(),
 				log:                    log.New("test"),
 				legacyDatasourceLookup: &mockLegacyDataSourceLookup{},
+				exprService:            &exprServiceHolder{},
 			}
 
 			req := httptest.NewRequest(http.MethodPost, "/some-path", bytes.NewReader([]byte(tc.queryJSON)))
@@ -297,6 +298,17 @@ func (m *mockLegacyDataSourceLookup) GetDataSourceFromDeprecatedFields(ctx conte
 	}, nil
 }
 
+func TestExprServiceHolder(t *testing.T) {
+	holder := &exprServiceHolder{}
+	config := clientapi.InstanceConfigurationSettings{ExpressionsEnabled: true}
+
+	first, err := holder.get(config, tracing.InitializeTracerForTest())
+	require.NoError(t, err)
+	second, err := holder.get(config, tracing.InitializeTracerForTest())
+	require.NoError(t, err)
+	require.Same(t, first, second)
+}
+
 func TestMergeHeaders(t *testing.T) {
 	tests := []struct {
 		name     string
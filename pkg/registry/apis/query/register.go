@@ -49,6 +49,7 @@ type QueryAPIBuilder struct {
 	converter              *expr.ResultConverter
 	queryTypes             *query.QueryTypeDefinitionList
 	legacyDatasourceLookup service.LegacyDataSourceLookup
+	exprService            *exprServiceHolder
 }
 
 func NewQueryAPIBuilder(
@@ -90,6 +91,7 @@ func NewQueryAPIBuilder(
 			Tracer:   tracer,
 		},
 		legacyDatasourceLookup: legacyDatasourceLookup,
+		exprService:            &exprServiceHolder{},
 	}, nil
 }
 
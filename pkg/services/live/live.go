@@ -24,6 +24,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana-plugin-sdk-go/live"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -179,6 +180,29 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 
 	g.ManagedStreamRunner = managedStreamRunner
 
+	g.pipelineStorage = &pipeline.SQLStorage{
+		SQLStore:       sqlStore,
+		SecretsService: secretsService,
+		OnChange:       g.notifyPipelineChange,
+	}
+	if cfg.LivePipelineEnabled {
+		builder := &pipeline.StorageRuleBuilder{
+			Node:                 node,
+			ManagedStream:        g.ManagedStreamRunner,
+			FrameStorage:         pipeline.NewFrameStorage(),
+			ProcessorStorage:     pipeline.NewProcessorStorage(),
+			Storage:              g.pipelineStorage,
+			ChannelHandlerGetter: g,
+			SecretsService:       secretsService,
+		}
+		g.pipelineRules = pipeline.NewCacheSegmentedTree(builder)
+		g.Pipeline, err = pipeline.New(g.pipelineRules)
+		if err != nil {
+			return nil, err
+		}
+	}
+	node.OnNotification(g.handleNodeNotification)
+
 	g.contextGetter = liveplugin.NewContextGetter(g.PluginContextProvider, g.DataSourceCache)
 	pipelinedChannelLocalPublisher := liveplugin.NewChannelLocalPublisher(node, g.Pipeline)
 	numLocalSubscribersGetter := liveplugin.NewNumLocalSubscribersGetter(node)
@@ -503,6 +527,7 @@ type GrafanaLive struct {
 	ManagedStreamRunner *managedstream.Runner
 	Pipeline            *pipeline.Pipeline
 	pipelineStorage     pipeline.Storage
+	pipelineRules       *pipeline.CacheSegmentedTree
 
 	contextGetter    *liveplugin.ContextGetter
 	runStreamManager *runstream.Manager
@@ -512,6 +537,35 @@ type GrafanaLive struct {
 	usageStats        usageStats
 }
 
+// pipelineChangeNotificationOp is the operation of node notifications sent when the pipeline
+// channel rules or write configs of an organization change. The data is the organization ID.
+const pipelineChangeNotificationOp = "pipeline_change"
+
+// notifyPipelineChange makes all Grafana instances rebuild the pipeline rules of an organization.
+func (g *GrafanaLive) notifyPipelineChange(orgID int64) {
+	// Rebuild the rules of this instance right away, the notification reaches other
+	// instances over the HA engine.
+	if g.pipelineRules != nil {
+		g.pipelineRules.Invalidate(orgID)
+	}
+	err := g.node.Notify(pipelineChangeNotificationOp, []byte(strconv.FormatInt(orgID, 10)), "")
+	if err != nil {
+		logger.Error("Error notifying about pipeline change", "error", err, "orgId", orgID)
+	}
+}
+
+func (g *GrafanaLive) handleNodeNotification(e centrifuge.NotificationEvent) {
+	if e.Op != pipelineChangeNotificationOp || g.pipelineRules == nil {
+		return
+	}
+	orgID, err := strconv.ParseInt(string(e.Data), 10, 64)
+	if err != nil {
+		logger.Error("Error parsing pipeline change notification", "error", err, "node", e.FromNodeID)
+		return
+	}
+	g.pipelineRules.Invalidate(orgID)
+}
+
 // DashboardActivityChannel is a service to advertise dashboard activity
 type DashboardActivityChannel interface {
 	// Called when a dashboard is saved -- this includes the error so we can support a
@@ -1209,6 +1263,8 @@ type ConvertDryRunRequest struct {
 
 type ConvertDryRunResponse struct {
 	ChannelFrames []*pipeline.ChannelFrame `json:"channelFrames"`
+	// ProcessedChannelFrames are the frames after applying the frame processors of their channel rules.
+	ProcessedChannelFrames []*pipeline.ChannelFrame `json:"processedChannelFrames"`
 }
 
 type DryRunRuleStorage struct {
@@ -1269,6 +1325,7 @@ func (g *GrafanaLive) HandlePipelineConvertTestHTTP(c *contextmodel.ReqContext)
 		Node:                 g.node,
 		ManagedStream:        g.ManagedStreamRunner,
 		FrameStorage:         pipeline.NewFrameStorage(),
+		ProcessorStorage:     pipeline.NewProcessorStorage(),
 		Storage:              storage,
 		ChannelHandlerGetter: g,
 	}
@@ -1291,8 +1348,35 @@ func (g *GrafanaLive) HandlePipelineConvertTestHTTP(c *contextmodel.ReqContext)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Error converting data", err)
 	}
+	processedChannelFrames := make([]*pipeline.ChannelFrame, 0, len(channelFrames))
+	for _, channelFrame := range channelFrames {
+		channel := req.Channel
+		frameRule := rule
+		if channelFrame.Channel != "" {
+			channel = channelFrame.Channel
+			frameRule, ok, err = channelRuleGetter.Get(c.GetOrgID(), channel)
+			if err != nil {
+				return response.Error(http.StatusInternalServerError, "Error getting channel rule", err)
+			}
+			if !ok {
+				processedChannelFrames = append(processedChannelFrames, channelFrame)
+				continue
+			}
+		}
+		// Processors may change the fields of the frame, keep the converted one as it is.
+		frameCopy := *channelFrame.Frame
+		frameCopy.Fields = append([]*data.Field(nil), frameCopy.Fields...)
+		frame, err := pipe.ApplyFrameProcessors(c.Req.Context(), *frameRule, c.GetOrgID(), channel, &frameCopy)
+		if err != nil {
+			return response.Error(http.StatusBadRequest, "Error processing frame", err)
+		}
+		if frame != nil {
+			processedChannelFrames = append(processedChannelFrames, &pipeline.ChannelFrame{Channel: channelFrame.Channel, Frame: frame})
+		}
+	}
 	return response.JSON(http.StatusOK, ConvertDryRunResponse{
-		ChannelFrames: channelFrames,
+		ChannelFrames:          channelFrames,
+		ProcessedChannelFrames: processedChannelFrames,
 	})
 }
 
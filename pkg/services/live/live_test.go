@@ -2,8 +2,11 @@ package live
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,11 +21,14 @@ import (
 	"github.com/grafana/grafana/pkg/infra/usagestats"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
 	"github.com/grafana/grafana/pkg/services/annotations/annotationstest"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/live/livecontext"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tests/testsuite"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 func TestMain(m *testing.M) {
@@ -331,6 +337,38 @@ func Test_handleOnSubscribe_IDTokenExpiration(t *testing.T) {
 	})
 }
 
+func TestHandlePipelineConvertTestHTTP(t *testing.T) {
+	g := &GrafanaLive{}
+	body := `{
+		"channelRules": [{
+			"pattern": "stream/test/xxx",
+			"settings": {
+				"converter": {"type": "jsonAuto"},
+				"frameProcessors": [{"type": "keepFields", "keepFields": {"fieldNames": ["value1"]}}]
+			}
+		}],
+		"channel": "stream/test/xxx",
+		"data": "{\"value1\": 1, \"value2\": 2}"
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/api/live/pipeline-convert-test", strings.NewReader(body))
+	c := &contextmodel.ReqContext{
+		Context:      &web.Context{Req: req},
+		SignedInUser: &user.SignedInUser{OrgID: 1},
+	}
+
+	resp := g.HandlePipelineConvertTestHTTP(c)
+	require.Equal(t, http.StatusOK, resp.Status())
+
+	var result ConvertDryRunResponse
+	require.NoError(t, json.Unmarshal(resp.Body(), &result))
+	require.Len(t, result.ChannelFrames, 1)
+	require.Len(t, result.ChannelFrames[0].Frame.Fields, 3)
+	// The processed frames are returned next to the converted frames, which are not modified.
+	require.Len(t, result.ProcessedChannelFrames, 1)
+	require.Len(t, result.ProcessedChannelFrames[0].Frame.Fields, 1)
+	require.Equal(t, "value1", result.ProcessedChannelFrames[0].Frame.Fields[0].Name)
+}
+
 func setupLiveService(cfg *setting.Cfg, t *testing.T) (*GrafanaLive, error) {
 	if cfg == nil {
 		cfg = setting.NewCfg()
@@ -3,6 +3,7 @@ package managedstream
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
@@ -11,8 +12,34 @@ import (
 type FrameCache interface {
 	// GetActiveChannels returns active managed stream channels with JSON schema.
 	GetActiveChannels(orgID int64) (map[string]json.RawMessage, error)
-	// GetFrame returns full JSON frame for a channel in org.
+	// GetFrame returns full JSON frame for a channel in org. When the channel retains
+	// several frames, it returns a single frame with the rows of all retained frames.
 	GetFrame(ctx context.Context, orgID int64, channel string) (json.RawMessage, bool, error)
-	// Update updates frame cache and returns true if schema changed.
-	Update(ctx context.Context, orgID int64, channel string, frameJson data.FrameJSONCache) (bool, error)
+	// Update updates frame cache and returns true if schema changed. Recent frames
+	// of the channel are kept according to the retention.
+	Update(ctx context.Context, orgID int64, channel string, frameJson data.FrameJSONCache, retention Retention) (bool, error)
+}
+
+// DefaultRetentionMaxFrames is the number of frames kept for a channel that only
+// limits the age of its frames.
+const DefaultRetentionMaxFrames = 1000
+
+// Retention configures how many recent frames of a channel are kept to be sent
+// to new subscribers. The zero value keeps only the last frame.
+type Retention struct {
+	// MaxFrames is the maximum number of frames kept.
+	MaxFrames int
+	// MaxAge is the maximum age of the frames kept.
+	MaxAge time.Duration
+}
+
+func (r Retention) enabled() bool {
+	return r.MaxFrames > 1 || r.MaxAge > 0
+}
+
+func (r Retention) maxFrames() int {
+	if r.MaxFrames > 0 {
+		return r.MaxFrames
+	}
+	return DefaultRetentionMaxFrames
 }
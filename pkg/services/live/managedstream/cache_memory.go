@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
@@ -12,16 +13,31 @@ import (
 
 // MemoryFrameCache ...
 type MemoryFrameCache struct {
-	mu     sync.RWMutex
-	frames map[int64]map[string]data.FrameJSONCache
-	log    log.Logger
+	mu      sync.RWMutex
+	frames  map[int64]map[string]data.FrameJSONCache
+	buffers map[int64]map[string]*frameBuffer
+	log     log.Logger
+	now     func() time.Time
+}
+
+// frameBuffer keeps the recent frames of a channel, oldest first.
+type frameBuffer struct {
+	retention Retention
+	frames    []bufferedFrame
+}
+
+type bufferedFrame struct {
+	time  time.Time
+	frame json.RawMessage
 }
 
 // NewMemoryFrameCache ...
 func NewMemoryFrameCache() *MemoryFrameCache {
 	return &MemoryFrameCache{
-		frames: map[int64]map[string]data.FrameJSONCache{},
-		log:    log.New("live.memoryframecache"),
+		frames:  map[int64]map[string]data.FrameJSONCache{},
+		buffers: map[int64]map[string]*frameBuffer{},
+		log:     log.New("live.memoryframecache"),
+		now:     time.Now,
 	}
 }
 
@@ -44,6 +60,25 @@ func (c *MemoryFrameCache) GetFrame(ctx context.Context, orgID int64, channel st
 	defer c.mu.RUnlock()
 	cachedFrame, ok := c.frames[orgID][channel]
 	raw := cachedFrame.Bytes(data.IncludeAll)
+	if buffer, exists := c.buffers[orgID][channel]; exists {
+		var minTime time.Time
+		if buffer.retention.MaxAge > 0 {
+			minTime = c.now().Add(-buffer.retention.MaxAge)
+		}
+		frames := make([]json.RawMessage, 0, len(buffer.frames))
+		for _, f := range buffer.frames {
+			if !f.time.Before(minTime) {
+				frames = append(frames, f.frame)
+			}
+		}
+		if len(frames) > 0 {
+			merged, err := mergeFrames(frames)
+			if err != nil {
+				return nil, false, err
+			}
+			raw = merged
+		}
+	}
 	c.log.Debug("Cache get",
 		"orgId", orgID,
 		"channel", channel,
@@ -52,7 +87,7 @@ func (c *MemoryFrameCache) GetFrame(ctx context.Context, orgID int64, channel st
 	return raw, ok, nil
 }
 
-func (c *MemoryFrameCache) Update(ctx context.Context, orgID int64, channel string, jsonFrame data.FrameJSONCache) (bool, error) {
+func (c *MemoryFrameCache) Update(ctx context.Context, orgID int64, channel string, jsonFrame data.FrameJSONCache, retention Retention) (bool, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if _, ok := c.frames[orgID]; !ok {
@@ -61,6 +96,7 @@ func (c *MemoryFrameCache) Update(ctx context.Context, orgID int64, channel stri
 	cachedJsonFrame, exists := c.frames[orgID][channel]
 	schemaUpdated := !exists || !cachedJsonFrame.SameSchema(&jsonFrame)
 	c.frames[orgID][channel] = jsonFrame
+	c.updateBuffer(orgID, channel, jsonFrame, retention, schemaUpdated)
 	c.log.Debug("Cache update",
 		"orgId", orgID,
 		"channel", channel,
@@ -68,3 +104,35 @@ func (c *MemoryFrameCache) Update(ctx context.Context, orgID int64, channel stri
 	)
 	return schemaUpdated, nil
 }
+
+func (c *MemoryFrameCache) updateBuffer(orgID int64, channel string, jsonFrame data.FrameJSONCache, retention Retention, schemaUpdated bool) {
+	if !retention.enabled() {
+		delete(c.buffers[orgID], channel)
+		return
+	}
+	if _, ok := c.buffers[orgID]; !ok {
+		c.buffers[orgID] = map[string]*frameBuffer{}
+	}
+	buffer, ok := c.buffers[orgID][channel]
+	if !ok || schemaUpdated {
+		// Frames with a different schema cannot be merged with the new ones.
+		buffer = &frameBuffer{}
+		c.buffers[orgID][channel] = buffer
+	}
+	buffer.retention = retention
+	now := c.now()
+	buffer.frames = append(buffer.frames, bufferedFrame{time: now, frame: jsonFrame.Bytes(data.IncludeAll)})
+
+	start := 0
+	if len(buffer.frames) > retention.maxFrames() {
+		start = len(buffer.frames) - retention.maxFrames()
+	}
+	if retention.MaxAge > 0 {
+		minTime := now.Add(-retention.MaxAge)
+		for start < len(buffer.frames)-1 && buffer.frames[start].time.Before(minTime) {
+			start++
+		}
+	}
+	// The dropped frames are released when append reallocates the slice.
+	buffer.frames = buffer.frames[start:]
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
@@ -15,7 +16,7 @@ func testFrameCache(t *testing.T, c FrameCache) {
 	frameJsonCache, err := data.FrameToJSONCache(frame)
 	require.NoError(t, err)
 
-	updated, err := c.Update(context.Background(), 1, "test", frameJsonCache)
+	updated, err := c.Update(context.Background(), 1, "test", frameJsonCache, Retention{})
 	require.NoError(t, err)
 	require.True(t, updated)
 
@@ -27,7 +28,7 @@ func testFrameCache(t *testing.T, c FrameCache) {
 	require.NotZero(t, schema)
 
 	// Make sure the same frame does not update schema.
-	updated, err = c.Update(context.Background(), 1, "test", frameJsonCache)
+	updated, err = c.Update(context.Background(), 1, "test", frameJsonCache, Retention{})
 	require.NoError(t, err)
 	require.False(t, updated)
 
@@ -37,12 +38,12 @@ func testFrameCache(t *testing.T, c FrameCache) {
 	require.NoError(t, err)
 
 	// Make sure schema updated.
-	updated, err = c.Update(context.Background(), 1, "test", frameJsonCache)
+	updated, err = c.Update(context.Background(), 1, "test", frameJsonCache, Retention{})
 	require.NoError(t, err)
 	require.True(t, updated)
 
 	// Add the same with another orgID and make sure schema updated.
-	updated, err = c.Update(context.Background(), 2, "test", frameJsonCache)
+	updated, err = c.Update(context.Background(), 2, "test", frameJsonCache, Retention{})
 	require.NoError(t, err)
 	require.True(t, updated)
 
@@ -62,8 +63,83 @@ func testFrameCache(t *testing.T, c FrameCache) {
 	require.NotEqual(t, string(channels["test"]), string(schema))
 }
 
+func testFrameCacheRetention(t *testing.T, c FrameCache) {
+	push := func(channel string, values []float64, retention Retention) {
+		frame := data.NewFrame("cpu", data.NewField("value", nil, values))
+		frameJsonCache, err := data.FrameToJSONCache(frame)
+		require.NoError(t, err)
+		_, err = c.Update(context.Background(), 1, channel, frameJsonCache, retention)
+		require.NoError(t, err)
+	}
+	getValues := func(channel string) []float64 {
+		frameJSON, ok, err := c.GetFrame(context.Background(), 1, channel)
+		require.NoError(t, err)
+		require.True(t, ok)
+		var f data.Frame
+		require.NoError(t, json.Unmarshal(frameJSON, &f))
+		values := make([]float64, 0, f.Rows())
+		for i := 0; i < f.Rows(); i++ {
+			values = append(values, f.Fields[0].At(i).(float64))
+		}
+		return values
+	}
+
+	// Only the last frame is kept by default.
+	push("default", []float64{1}, Retention{})
+	push("default", []float64{2}, Retention{})
+	require.Equal(t, []float64{2}, getValues("default"))
+
+	// Frames are merged up to the maximum number of frames.
+	retention := Retention{MaxFrames: 3}
+	for i := 1; i <= 5; i++ {
+		push("buffered", []float64{float64(i), float64(i) + 0.5}, retention)
+	}
+	require.Equal(t, []float64{3, 3.5, 4, 4.5, 5, 5.5}, getValues("buffered"))
+
+	// Frames with another schema are not merged with the new ones.
+	frame := data.NewFrame("cpu", data.NewField("other", nil, []float64{10}))
+	frameJsonCache, err := data.FrameToJSONCache(frame)
+	require.NoError(t, err)
+	_, err = c.Update(context.Background(), 1, "buffered", frameJsonCache, retention)
+	require.NoError(t, err)
+	push("buffered", []float64{6}, retention)
+	require.Equal(t, []float64{6}, getValues("buffered"))
+	push("buffered", []float64{7}, retention)
+	require.Equal(t, []float64{6, 7}, getValues("buffered"))
+
+	// Disabling the retention drops the buffered frames.
+	push("buffered", []float64{8}, Retention{})
+	require.Equal(t, []float64{8}, getValues("buffered"))
+}
+
 func TestMemoryFrameCache(t *testing.T) {
 	c := NewMemoryFrameCache()
 	require.NotNil(t, c)
 	testFrameCache(t, c)
 }
+
+func TestMemoryFrameCacheRetention(t *testing.T) {
+	testFrameCacheRetention(t, NewMemoryFrameCache())
+
+	t.Run("should drop frames older than the maximum age", func(t *testing.T) {
+		c := NewMemoryFrameCache()
+		now := time.Now()
+		c.now = func() time.Time { return now }
+		retention := Retention{MaxAge: time.Minute}
+		for i := 1; i <= 3; i++ {
+			frameJsonCache, err := data.FrameToJSONCache(data.NewFrame("cpu", data.NewField("value", nil, []float64{float64(i)})))
+			require.NoError(t, err)
+			_, err = c.Update(context.Background(), 1, "test", frameJsonCache, retention)
+			require.NoError(t, err)
+			now = now.Add(40 * time.Second)
+		}
+
+		frameJSON, ok, err := c.GetFrame(context.Background(), 1, "test")
+		require.NoError(t, err)
+		require.True(t, ok)
+		var f data.Frame
+		require.NoError(t, json.Unmarshal(frameJSON, &f))
+		require.Equal(t, 1, f.Rows())
+		require.Equal(t, float64(3), f.Fields[0].At(0))
+	})
+}
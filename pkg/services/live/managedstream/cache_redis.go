@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -45,23 +48,56 @@ func (c *RedisFrameCache) GetActiveChannels(orgID int64) (map[string]json.RawMes
 }
 
 func (c *RedisFrameCache) GetFrame(ctx context.Context, orgID int64, channel string) (json.RawMessage, bool, error) {
-	key := c.getCacheKey(orgchannel.PrependOrgID(orgID, channel))
-	cmd := c.redisClient.HGetAll(ctx, key)
-	result, err := cmd.Result()
+	channelID := orgchannel.PrependOrgID(orgID, channel)
+	pipe := c.redisClient.Pipeline()
+	defer func() { _ = pipe.Close() }()
+	frameCmd := pipe.HGetAll(ctx, c.getCacheKey(channelID))
+	bufferCmd := pipe.ZRangeWithScores(ctx, c.getBufferKey(channelID), 0, -1)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, false, err
+	}
+	result, err := frameCmd.Result()
 	if err != nil {
 		return nil, false, err
 	}
 	if len(result) == 0 {
 		return nil, false, nil
 	}
-	return json.RawMessage(result["frame"]), true, nil
+	buffered, err := bufferCmd.Result()
+	if err != nil {
+		return nil, false, err
+	}
+	var minScore float64
+	if maxAge, err := strconv.ParseInt(result["replay_max_age"], 10, 64); err == nil && maxAge > 0 {
+		minScore = float64(time.Now().Add(-time.Duration(maxAge) * time.Millisecond).UnixMilli())
+	}
+	frames := make([]json.RawMessage, 0, len(buffered))
+	for _, z := range buffered {
+		member, ok := z.Member.(string)
+		if !ok || z.Score < minScore {
+			continue
+		}
+		// Members are prefixed with the time of the update, so that identical frames
+		// are all kept and frames with the same score are sorted by time.
+		if _, frame, found := strings.Cut(member, ":"); found {
+			frames = append(frames, json.RawMessage(frame))
+		}
+	}
+	if len(frames) == 0 {
+		return json.RawMessage(result["frame"]), true, nil
+	}
+	merged, err := mergeFrames(frames)
+	if err != nil {
+		return nil, false, err
+	}
+	return merged, true, nil
 }
 
 const (
 	frameCacheTTL = 7 * 24 * time.Hour
 )
 
-func (c *RedisFrameCache) Update(ctx context.Context, orgID int64, channel string, jsonFrame data.FrameJSONCache) (bool, error) {
+func (c *RedisFrameCache) Update(ctx context.Context, orgID int64, channel string, jsonFrame data.FrameJSONCache, retention Retention) (bool, error) {
 	c.mu.Lock()
 	if _, ok := c.frames[orgID]; !ok {
 		c.frames[orgID] = map[string]data.FrameJSONCache{}
@@ -71,17 +107,37 @@ func (c *RedisFrameCache) Update(ctx context.Context, orgID int64, channel strin
 
 	stringSchema := string(jsonFrame.Bytes(data.IncludeSchemaOnly))
 
-	key := c.getCacheKey(orgchannel.PrependOrgID(orgID, channel))
+	channelID := orgchannel.PrependOrgID(orgID, channel)
+	key := c.getCacheKey(channelID)
+	bufferKey := c.getBufferKey(channelID)
+	frame := string(jsonFrame.Bytes(data.IncludeAll))
 
 	pipe := c.redisClient.TxPipeline()
 	defer func() { _ = pipe.Close() }()
 
 	pipe.HGetAll(ctx, key)
 	pipe.HMSet(ctx, key, map[string]string{
-		"schema": stringSchema,
-		"frame":  string(jsonFrame.Bytes(data.IncludeAll)),
+		"schema":         stringSchema,
+		"frame":          frame,
+		"replay_max_age": strconv.FormatInt(retention.MaxAge.Milliseconds(), 10),
 	})
 	pipe.Expire(ctx, key, frameCacheTTL)
+	if retention.enabled() {
+		now := time.Now()
+		pipe.ZAdd(ctx, bufferKey, &redis.Z{
+			Score:  float64(now.UnixMilli()),
+			Member: fmt.Sprintf("%020d:%s", now.UnixNano(), frame),
+		})
+		pipe.ZRemRangeByRank(ctx, bufferKey, 0, int64(-retention.maxFrames()-1))
+		ttl := frameCacheTTL
+		if retention.MaxAge > 0 {
+			pipe.ZRemRangeByScore(ctx, bufferKey, "-inf", "("+strconv.FormatInt(now.Add(-retention.MaxAge).UnixMilli(), 10))
+			ttl = retention.MaxAge
+		}
+		pipe.Expire(ctx, bufferKey, ttl)
+	} else {
+		pipe.Del(ctx, bufferKey)
+	}
 
 	replies, err := pipe.Exec(ctx)
 	if err != nil {
@@ -112,3 +168,7 @@ func (c *RedisFrameCache) Update(ctx context.Context, orgID int64, channel strin
 func (c *RedisFrameCache) getCacheKey(channelID string) string {
 	return c.keyPrefix + ".managed_stream." + channelID
 }
+
+func (c *RedisFrameCache) getBufferKey(channelID string) string {
+	return c.keyPrefix + ".managed_stream_buffer." + channelID
+}
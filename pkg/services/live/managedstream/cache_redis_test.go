@@ -39,6 +39,7 @@ func TestIntegrationRedisCacheStorage(t *testing.T) {
 	c := NewRedisFrameCache(redisClient, prefix)
 	require.NotNil(t, c)
 	testFrameCache(t, c)
+	testFrameCacheRetention(t, c)
 
 	keys, err := redisClient.Keys(redisClient.Context(), "*").Result()
 	if err != nil {
@@ -0,0 +1,49 @@
+package managedstream
+
+import (
+	"encoding/json"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// mergeFrames returns a frame with the rows of the frames, oldest first. Frames
+// with a schema different from the last frame and the frames before them are
+// skipped, since their rows cannot be appended to the last frame.
+func mergeFrames(frames []json.RawMessage) (json.RawMessage, error) {
+	if len(frames) == 1 {
+		return frames[0], nil
+	}
+	decoded := make([]*data.Frame, 0, len(frames))
+	for _, f := range frames {
+		var frame data.Frame
+		if err := json.Unmarshal(f, &frame); err != nil {
+			return nil, err
+		}
+		decoded = append(decoded, &frame)
+	}
+	last := decoded[len(decoded)-1]
+	start := len(decoded) - 1
+	for start > 0 && sameSchema(decoded[start-1], last) {
+		start--
+	}
+	merged := last.EmptyCopy()
+	merged.Meta = last.Meta
+	for _, f := range decoded[start:] {
+		for i := 0; i < f.Rows(); i++ {
+			merged.AppendRow(f.RowCopy(i)...)
+		}
+	}
+	return data.FrameToJSON(merged, data.IncludeAll)
+}
+
+func sameSchema(a, b *data.Frame) bool {
+	if a.Name != b.Name || len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	for i := range a.Fields {
+		if a.Fields[i].Name != b.Fields[i].Name || a.Fields[i].Type() != b.Fields[i].Type() {
+			return false
+		}
+	}
+	return true
+}
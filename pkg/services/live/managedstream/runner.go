@@ -172,6 +172,12 @@ func NewNamespaceStream(orgID int64, scope string, namespace string, publisher m
 // * Saves the entire frame to cache.
 // * If schema has been changed sends entire frame to channel, otherwise only data.
 func (s *NamespaceStream) Push(ctx context.Context, path string, frame *data.Frame) error {
+	return s.PushWithRetention(ctx, path, frame, Retention{})
+}
+
+// PushWithRetention is like Push, but keeps the recent frames of the channel according
+// to the retention, so that new subscribers receive them.
+func (s *NamespaceStream) PushWithRetention(ctx context.Context, path string, frame *data.Frame, retention Retention) error {
 	jsonFrameCache, err := data.FrameToJSONCache(frame)
 	if err != nil {
 		return err
@@ -180,7 +186,7 @@ func (s *NamespaceStream) Push(ctx context.Context, path string, frame *data.Fra
 	// The channel this will be posted into.
 	channel := live.Channel{Scope: s.scope, Namespace: s.namespace, Path: path}.String()
 
-	isUpdated, err := s.frameCache.Update(ctx, s.orgID, channel, jsonFrameCache)
+	isUpdated, err := s.frameCache.Update(ctx, s.orgID, channel, jsonFrameCache, retention)
 	if err != nil {
 		logger.Error("Error updating managed stream schema", "error", err)
 		return err
@@ -35,11 +35,13 @@ type ChannelRule struct {
 }
 
 type ConverterConfig struct {
-	Type                      string                     `json:"type" ts_type:"Omit<keyof ConverterConfig, 'type'>"`
-	AutoJsonConverterConfig   *AutoJsonConverterConfig   `json:"jsonAuto,omitempty"`
-	ExactJsonConverterConfig  *ExactJsonConverterConfig  `json:"jsonExact,omitempty"`
-	AutoInfluxConverterConfig *AutoInfluxConverterConfig `json:"influxAuto,omitempty"`
-	JsonFrameConverterConfig  *JsonFrameConverterConfig  `json:"jsonFrame,omitempty"`
+	Type                                 string                                `json:"type" ts_type:"Omit<keyof ConverterConfig, 'type'>"`
+	AutoJsonConverterConfig              *AutoJsonConverterConfig              `json:"jsonAuto,omitempty"`
+	ExactJsonConverterConfig             *ExactJsonConverterConfig             `json:"jsonExact,omitempty"`
+	AutoInfluxConverterConfig            *AutoInfluxConverterConfig            `json:"influxAuto,omitempty"`
+	JsonFrameConverterConfig             *JsonFrameConverterConfig             `json:"jsonFrame,omitempty"`
+	PrometheusRemoteWriteConverterConfig *PrometheusRemoteWriteConverterConfig `json:"prometheusRemoteWrite,omitempty"`
+	OTLPMetricsConverterConfig           *OTLPMetricsConverterConfig           `json:"otlpMetrics,omitempty"`
 }
 
 type DropFieldsFrameProcessorConfig struct {
@@ -50,11 +52,40 @@ type KeepFieldsFrameProcessorConfig struct {
 	FieldNames []string `json:"fieldNames"`
 }
 
+type WindowAggregateFrameProcessorConfig struct {
+	// WindowSeconds is the length of a window.
+	WindowSeconds int64 `json:"windowSeconds"`
+	// Reducer is one of avg, min, max or count.
+	Reducer string `json:"reducer"`
+	// FieldNames to aggregate, all numeric fields are aggregated when empty.
+	FieldNames []string `json:"fieldNames,omitempty"`
+}
+
+type RateLimitFrameProcessorConfig struct {
+	// FramesPerSecond is the maximum rate of frames in a channel.
+	FramesPerSecond float64 `json:"framesPerSecond"`
+	// Burst is the number of frames which can exceed the rate at once, 1 by default.
+	Burst int `json:"burst,omitempty"`
+}
+
+type ComputedFieldConfig struct {
+	Name string `json:"name"`
+	// Expression such as ($temperature - 32) / 1.8, in the syntax of math expressions.
+	Expression string `json:"expression"`
+}
+
+type ComputeFieldsFrameProcessorConfig struct {
+	Fields []ComputedFieldConfig `json:"fields"`
+}
+
 type FrameProcessorConfig struct {
-	Type                      string                          `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
-	DropFieldsProcessorConfig *DropFieldsFrameProcessorConfig `json:"dropFields,omitempty"`
-	KeepFieldsProcessorConfig *KeepFieldsFrameProcessorConfig `json:"keepFields,omitempty"`
-	MultipleProcessorConfig   *MultipleFrameProcessorConfig   `json:"multiple,omitempty"`
+	Type                           string                               `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
+	DropFieldsProcessorConfig      *DropFieldsFrameProcessorConfig      `json:"dropFields,omitempty"`
+	KeepFieldsProcessorConfig      *KeepFieldsFrameProcessorConfig      `json:"keepFields,omitempty"`
+	WindowAggregateProcessorConfig *WindowAggregateFrameProcessorConfig `json:"windowAggregate,omitempty"`
+	RateLimitProcessorConfig       *RateLimitFrameProcessorConfig       `json:"rateLimit,omitempty"`
+	ComputeFieldsProcessorConfig   *ComputeFieldsFrameProcessorConfig   `json:"computeFields,omitempty"`
+	MultipleProcessorConfig        *MultipleFrameProcessorConfig        `json:"multiple,omitempty"`
 }
 
 type MultipleFrameProcessorConfig struct {
@@ -152,4 +183,17 @@ type AutoInfluxConverterConfig struct {
 
 type JsonFrameConverterConfig struct{}
 
-type ManagedStreamOutputConfig struct{}
+type PrometheusRemoteWriteConverterConfig struct{}
+
+type OTLPMetricsConverterConfig struct {
+	// ContentType is the content type of the requests, application/x-protobuf (default) or application/json.
+	ContentType string `json:"contentType,omitempty"`
+}
+
+type ManagedStreamOutputConfig struct {
+	// ReplayMaxFrames is the number of recent frames of the channel sent to new subscribers.
+	// Only the last frame is sent by default.
+	ReplayMaxFrames int `json:"replayMaxFrames,omitempty"`
+	// ReplayMaxAgeMilliseconds limits the age of the frames sent to new subscribers.
+	ReplayMaxAgeMilliseconds int64 `json:"replayMaxAgeMilliseconds,omitempty"`
+}
@@ -0,0 +1,31 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/otlp"
+)
+
+// OTLPMetricsConverter decodes OTLP metrics export requests, in protobuf or JSON
+// according to the configured content type, and transforms them to several ChannelFrame objects where Channel is constructed
+// from original channel + / + <metric_name>.
+type OTLPMetricsConverter struct {
+	config    OTLPMetricsConverterConfig
+	converter telemetry.Converter
+}
+
+// NewOTLPMetricsConverter creates new OTLPMetricsConverter.
+func NewOTLPMetricsConverter(config OTLPMetricsConverterConfig) *OTLPMetricsConverter {
+	return &OTLPMetricsConverter{config: config, converter: otlp.NewConverter(config.ContentType)}
+}
+
+const ConverterTypeOTLPMetrics = "otlpMetrics"
+
+func (c *OTLPMetricsConverter) Type() string {
+	return ConverterTypeOTLPMetrics
+}
+
+func (c *OTLPMetricsConverter) Convert(_ context.Context, vars Vars, body []byte) ([]*ChannelFrame, error) {
+	return convertTelemetry(c.converter, vars, body)
+}
@@ -0,0 +1,48 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/promremotewrite"
+)
+
+// PrometheusRemoteWriteConverter decodes Prometheus remote write requests and
+// transforms them to several ChannelFrame objects where Channel is constructed
+// from original channel + / + <metric_name>.
+type PrometheusRemoteWriteConverter struct {
+	config    PrometheusRemoteWriteConverterConfig
+	converter telemetry.Converter
+}
+
+// NewPrometheusRemoteWriteConverter creates new PrometheusRemoteWriteConverter.
+func NewPrometheusRemoteWriteConverter(config PrometheusRemoteWriteConverterConfig) *PrometheusRemoteWriteConverter {
+	return &PrometheusRemoteWriteConverter{config: config, converter: promremotewrite.NewConverter()}
+}
+
+const ConverterTypePrometheusRemoteWrite = "prometheusRemoteWrite"
+
+func (c *PrometheusRemoteWriteConverter) Type() string {
+	return ConverterTypePrometheusRemoteWrite
+}
+
+func (c *PrometheusRemoteWriteConverter) Convert(_ context.Context, vars Vars, body []byte) ([]*ChannelFrame, error) {
+	return convertTelemetry(c.converter, vars, body)
+}
+
+// convertTelemetry converts the body with a telemetry converter, and outputs each
+// frame into the original channel + / + <metric_name>.
+func convertTelemetry(converter telemetry.Converter, vars Vars, body []byte) ([]*ChannelFrame, error) {
+	frameWrappers, err := converter.Convert(body)
+	if err != nil {
+		return nil, err
+	}
+	channelFrames := make([]*ChannelFrame, 0, len(frameWrappers))
+	for _, fw := range frameWrappers {
+		channelFrames = append(channelFrames, &ChannelFrame{
+			Channel: vars.Channel + "/" + fw.Key(),
+			Frame:   fw.Frame(),
+		})
+	}
+	return channelFrames, nil
+}
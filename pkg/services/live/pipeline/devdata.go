@@ -113,7 +113,7 @@ func (f *DevRuleBuilder) BuildRules(_ context.Context, _ int64) ([]*LiveChannelR
 			},
 			Converter: NewJsonFrameConverter(JsonFrameConverterConfig{}),
 			FrameOutputters: []FrameOutputter{
-				NewManagedStreamFrameOutput(f.ManagedStream),
+				NewManagedStreamFrameOutput(f.ManagedStream, ManagedStreamOutputConfig{}),
 				NewRemoteWriteFrameOutput(
 					os.Getenv("GF_LIVE_REMOTE_WRITE_ENDPOINT"),
 					&BasicAuth{
@@ -136,7 +136,7 @@ func (f *DevRuleBuilder) BuildRules(_ context.Context, _ int64) ([]*LiveChannelR
 				}),
 			},
 			FrameOutputters: []FrameOutputter{
-				NewManagedStreamFrameOutput(f.ManagedStream),
+				NewManagedStreamFrameOutput(f.ManagedStream, ManagedStreamOutputConfig{}),
 			},
 		},
 		{
@@ -150,7 +150,7 @@ func (f *DevRuleBuilder) BuildRules(_ context.Context, _ int64) ([]*LiveChannelR
 			OrgId:   1,
 			Pattern: "stream/influx/input/:rest",
 			FrameOutputters: []FrameOutputter{
-				NewManagedStreamFrameOutput(f.ManagedStream),
+				NewManagedStreamFrameOutput(f.ManagedStream, ManagedStreamOutputConfig{}),
 			},
 		},
 		{
@@ -166,7 +166,7 @@ func (f *DevRuleBuilder) BuildRules(_ context.Context, _ int64) ([]*LiveChannelR
 				}),
 			},
 			FrameOutputters: []FrameOutputter{
-				NewManagedStreamFrameOutput(f.ManagedStream),
+				NewManagedStreamFrameOutput(f.ManagedStream, ManagedStreamOutputConfig{}),
 				NewConditionalOutput(
 					NewFrameNumberCompareCondition("usage_user", "gte", 50),
 					NewRedirectFrameOutput(RedirectOutputConfig{
@@ -178,13 +178,13 @@ func (f *DevRuleBuilder) BuildRules(_ context.Context, _ int64) ([]*LiveChannelR
 		{
 			OrgId:           1,
 			Pattern:         "stream/influx/input/cpu/spikes",
-			FrameOutputters: []FrameOutputter{NewManagedStreamFrameOutput(f.ManagedStream)},
+			FrameOutputters: []FrameOutputter{NewManagedStreamFrameOutput(f.ManagedStream, ManagedStreamOutputConfig{})},
 		},
 		{
 			OrgId:           1,
 			Pattern:         "stream/json/auto",
 			Converter:       NewAutoJsonConverter(AutoJsonConverterConfig{}),
-			FrameOutputters: []FrameOutputter{NewManagedStreamFrameOutput(f.ManagedStream)},
+			FrameOutputters: []FrameOutputter{NewManagedStreamFrameOutput(f.ManagedStream, ManagedStreamOutputConfig{})},
 		},
 		{
 			OrgId:   1,
@@ -207,14 +207,14 @@ func (f *DevRuleBuilder) BuildRules(_ context.Context, _ int64) ([]*LiveChannelR
 				}),
 			},
 			FrameOutputters: []FrameOutputter{
-				NewManagedStreamFrameOutput(f.ManagedStream),
+				NewManagedStreamFrameOutput(f.ManagedStream, ManagedStreamOutputConfig{}),
 			},
 		},
 		{
 			OrgId:   1,
 			Pattern: "stream/json/exact/value3/changes",
 			FrameOutputters: []FrameOutputter{
-				NewManagedStreamFrameOutput(f.ManagedStream),
+				NewManagedStreamFrameOutput(f.ManagedStream, ManagedStreamOutputConfig{}),
 				NewRemoteWriteFrameOutput(
 					os.Getenv("GF_LIVE_REMOTE_WRITE_ENDPOINT"),
 					&BasicAuth{
@@ -229,21 +229,21 @@ func (f *DevRuleBuilder) BuildRules(_ context.Context, _ int64) ([]*LiveChannelR
 			OrgId:   1,
 			Pattern: "stream/json/exact/annotation/changes",
 			FrameOutputters: []FrameOutputter{
-				NewManagedStreamFrameOutput(f.ManagedStream),
+				NewManagedStreamFrameOutput(f.ManagedStream, ManagedStreamOutputConfig{}),
 			},
 		},
 		{
 			OrgId:   1,
 			Pattern: "stream/json/exact/condition",
 			FrameOutputters: []FrameOutputter{
-				NewManagedStreamFrameOutput(f.ManagedStream),
+				NewManagedStreamFrameOutput(f.ManagedStream, ManagedStreamOutputConfig{}),
 			},
 		},
 		{
 			OrgId:   1,
 			Pattern: "stream/json/exact/value4/state",
 			FrameOutputters: []FrameOutputter{
-				NewManagedStreamFrameOutput(f.ManagedStream),
+				NewManagedStreamFrameOutput(f.ManagedStream, ManagedStreamOutputConfig{}),
 			},
 		},
 	}, nil
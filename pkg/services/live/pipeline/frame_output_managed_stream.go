@@ -2,6 +2,7 @@ package pipeline
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
@@ -10,10 +11,17 @@ import (
 
 type ManagedStreamFrameOutput struct {
 	managedStream *managedstream.Runner
+	retention     managedstream.Retention
 }
 
-func NewManagedStreamFrameOutput(managedStream *managedstream.Runner) *ManagedStreamFrameOutput {
-	return &ManagedStreamFrameOutput{managedStream: managedStream}
+func NewManagedStreamFrameOutput(managedStream *managedstream.Runner, config ManagedStreamOutputConfig) *ManagedStreamFrameOutput {
+	return &ManagedStreamFrameOutput{
+		managedStream: managedStream,
+		retention: managedstream.Retention{
+			MaxFrames: config.ReplayMaxFrames,
+			MaxAge:    time.Duration(config.ReplayMaxAgeMilliseconds) * time.Millisecond,
+		},
+	}
 }
 
 const FrameOutputTypeManagedStream = "managedStream"
@@ -28,5 +36,5 @@ func (out *ManagedStreamFrameOutput) OutputFrame(ctx context.Context, vars Vars,
 		logger.Error("Error getting stream", "error", err)
 		return nil, err
 	}
-	return nil, stream.Push(ctx, vars.Path, frame)
+	return nil, stream.PushWithRetention(ctx, vars.Path, frame, out.retention)
 }
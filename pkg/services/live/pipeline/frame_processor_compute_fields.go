@@ -0,0 +1,233 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// ComputeFieldsFrameProcessor adds fields computed from other fields of a data.Frame.
+// Expressions use the syntax of math expressions, where fields are referenced as $name,
+// or ${name} when the name contains spaces, for example (${temperature C} - 32) / 1.8.
+// Fields are computed in order, so an expression can refer to fields computed before it.
+// A computed field replaces the field with the same name. The value is null when a
+// referenced field is missing or null.
+type ComputeFieldsFrameProcessor struct {
+	config      ComputeFieldsFrameProcessorConfig
+	expressions []*parse.Tree
+}
+
+func NewComputeFieldsFrameProcessor(config ComputeFieldsFrameProcessorConfig) (*ComputeFieldsFrameProcessor, error) {
+	expressions := make([]*parse.Tree, 0, len(config.Fields))
+	for _, f := range config.Fields {
+		if f.Name == "" {
+			return nil, fmt.Errorf("computed field name is empty")
+		}
+		e, err := parseComputeExpression(f.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing computed field %s: %w", f.Name, err)
+		}
+		expressions = append(expressions, e)
+	}
+	return &ComputeFieldsFrameProcessor{config: config, expressions: expressions}, nil
+}
+
+const FrameProcessorTypeComputeFields = "computeFields"
+
+func (p *ComputeFieldsFrameProcessor) Type() string {
+	return FrameProcessorTypeComputeFields
+}
+
+func (p *ComputeFieldsFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	rows := frame.Rows()
+	for i, e := range p.expressions {
+		fields := make(map[string]*data.Field, len(frame.Fields))
+		for _, field := range frame.Fields {
+			fields[field.Name] = field
+		}
+		values := make([]*float64, rows)
+		for row := 0; row < rows; row++ {
+			var err error
+			v, ok := evalComputeExpression(e.Root, func(name string) (float64, bool) {
+				field, ok := fields[name]
+				if !ok || err != nil {
+					return 0, false
+				}
+				var v *float64
+				v, err = field.NullableFloatAt(row)
+				if v == nil {
+					return 0, false
+				}
+				return *v, true
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error computing field %s: %w", p.config.Fields[i].Name, err)
+			}
+			if ok {
+				values[row] = &v
+			}
+		}
+		computed := data.NewField(p.config.Fields[i].Name, nil, values)
+		replaced := false
+		for j, field := range frame.Fields {
+			if field.Name == computed.Name {
+				frame.Fields[j] = computed
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			frame.Fields = append(frame.Fields, computed)
+		}
+	}
+	return frame, nil
+}
+
+// computeFunctions are the functions which can be used in the expressions of computed fields.
+var computeFunctions = map[string]parse.Func{
+	"abs":   computeFunction(math.Abs),
+	"ceil":  computeFunction(math.Ceil),
+	"floor": computeFunction(math.Floor),
+	"round": computeFunction(math.Round),
+	"sqrt":  computeFunction(math.Sqrt),
+	"log":   computeFunction(math.Log),
+	"pow":   computeFunction2(math.Pow),
+	"min":   computeFunction2(math.Min),
+	"max":   computeFunction2(math.Max),
+}
+
+func computeFunction(f func(float64) float64) parse.Func {
+	return parse.Func{Args: []parse.ReturnType{parse.TypeVariantSet}, Return: parse.TypeScalar, F: f}
+}
+
+func computeFunction2(f func(float64, float64) float64) parse.Func {
+	return parse.Func{Args: []parse.ReturnType{parse.TypeVariantSet, parse.TypeVariantSet}, Return: parse.TypeScalar, F: f}
+}
+
+// parseComputeExpression parses an expression of a computed field. Durations and strings,
+// which are only valid as arguments of the functions of math expressions, are rejected.
+func parseComputeExpression(s string) (*parse.Tree, error) {
+	tree, err := parse.Parse(s, computeFunctions)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkComputeExpression(tree.Root); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+func checkComputeExpression(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ScalarNode, *parse.VarNode:
+		return nil
+	case *parse.UnaryNode:
+		return checkComputeExpression(n.Arg)
+	case *parse.BinaryNode:
+		if err := checkComputeExpression(n.Args[0]); err != nil {
+			return err
+		}
+		return checkComputeExpression(n.Args[1])
+	case *parse.FuncNode:
+		for _, arg := range n.Args {
+			if err := checkComputeExpression(arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unexpected %s in expression", node)
+}
+
+// evalComputeExpression returns the value of a parsed expression, or false if a referenced
+// value is missing. Operators behave as in math expressions.
+func evalComputeExpression(node parse.Node, values func(name string) (float64, bool)) (float64, bool) {
+	switch n := node.(type) {
+	case *parse.ScalarNode:
+		return n.Float64, true
+	case *parse.VarNode:
+		return values(n.Name)
+	case *parse.UnaryNode:
+		v, ok := evalComputeExpression(n.Arg, values)
+		if !ok {
+			return 0, false
+		}
+		switch n.OpStr {
+		case "-":
+			return -v, true
+		case "!":
+			return computeBool(v == 0), true
+		}
+	case *parse.BinaryNode:
+		a, ok := evalComputeExpression(n.Args[0], values)
+		if !ok {
+			return 0, false
+		}
+		b, ok := evalComputeExpression(n.Args[1], values)
+		if !ok {
+			return 0, false
+		}
+		return computeBinaryOp(n.OpStr, a, b), true
+	case *parse.FuncNode:
+		args := make([]float64, 0, len(n.Args))
+		for _, arg := range n.Args {
+			v, ok := evalComputeExpression(arg, values)
+			if !ok {
+				return 0, false
+			}
+			args = append(args, v)
+		}
+		switch f := n.F.F.(type) {
+		case func(float64) float64:
+			return f(args[0]), true
+		case func(float64, float64) float64:
+			return f(args[0], args[1]), true
+		}
+	}
+	return 0, false
+}
+
+func computeBinaryOp(op string, a, b float64) float64 {
+	switch op {
+	case "+":
+		return a + b
+	case "-":
+		return a - b
+	case "*":
+		return a * b
+	case "/":
+		return a / b
+	case "%":
+		return math.Mod(a, b)
+	case "**":
+		return math.Pow(a, b)
+	case "==":
+		return computeBool(a == b)
+	case "!=":
+		return computeBool(a != b)
+	case ">":
+		return computeBool(a > b)
+	case ">=":
+		return computeBool(a >= b)
+	case "<":
+		return computeBool(a < b)
+	case "<=":
+		return computeBool(a <= b)
+	case "&&":
+		return computeBool(a != 0 && b != 0)
+	case "||":
+		return computeBool(a != 0 || b != 0)
+	}
+	return math.NaN()
+}
+
+func computeBool(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
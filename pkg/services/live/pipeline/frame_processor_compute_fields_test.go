@@ -0,0 +1,92 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseComputeExpression(t *testing.T) {
+	values := map[string]float64{"a": 3, "b": 4, "temperature C": 100}
+	lookup := func(name string) (float64, bool) {
+		v, ok := values[name]
+		return v, ok
+	}
+
+	testCases := []struct {
+		expression string
+		expected   float64
+	}{
+		{expression: "1 + 2 * 3", expected: 7},
+		{expression: "(1 + 2) * 3", expected: 9},
+		{expression: "10 - 4 - 3", expected: 3},
+		{expression: "-$a + $b", expected: 1},
+		{expression: "$b % $a", expected: 1},
+		{expression: "$a ** 2", expected: 9},
+		{expression: "1.5e1 / 3", expected: 5},
+		{expression: "sqrt(pow($a, 2) + pow($b, 2))", expected: 5},
+		{expression: "max($a, $b) - min($a, 1)", expected: 3},
+		{expression: "round(abs(-2.6))", expected: 3},
+		{expression: "($a > 2) && !($b < 2)", expected: 1},
+		{expression: "${temperature C} * 1.8 + 32", expected: 212},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			e, err := parseComputeExpression(tc.expression)
+			require.NoError(t, err)
+			v, ok := evalComputeExpression(e.Root, lookup)
+			require.True(t, ok)
+			require.InDelta(t, tc.expected, v, 1e-9)
+		})
+	}
+
+	t.Run("missing values", func(t *testing.T) {
+		e, err := parseComputeExpression("$a + $missing")
+		require.NoError(t, err)
+		_, ok := evalComputeExpression(e.Root, lookup)
+		require.False(t, ok)
+	})
+
+	invalid := []string{"", "1 +", "(1 + 2", "$a $b", "unknown(1)", "pow(1)", "abs()", "${a", "1 # 2", "5m", `abs("a")`}
+	for _, expression := range invalid {
+		t.Run("invalid "+expression, func(t *testing.T) {
+			_, err := parseComputeExpression(expression)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestComputeFieldsFrameProcessor(t *testing.T) {
+	p, err := NewComputeFieldsFrameProcessor(ComputeFieldsFrameProcessorConfig{
+		Fields: []ComputedFieldConfig{
+			{Name: "fahrenheit", Expression: "$celsius * 1.8 + 32"},
+			{Name: "celsius", Expression: "round($celsius)"},
+			{Name: "doubled", Expression: "$fahrenheit * 2"},
+		},
+	})
+	require.NoError(t, err)
+
+	celsius := 20.4
+	frame := data.NewFrame("test",
+		data.NewField("celsius", nil, []*float64{&celsius, nil}),
+	)
+	out, err := p.ProcessFrame(context.Background(), Vars{}, frame)
+	require.NoError(t, err)
+	require.Len(t, out.Fields, 3)
+
+	require.Equal(t, "celsius", out.Fields[0].Name)
+	require.Equal(t, 20.0, *out.Fields[0].At(0).(*float64))
+	require.Nil(t, out.Fields[0].At(1))
+	require.Equal(t, "fahrenheit", out.Fields[1].Name)
+	require.InDelta(t, 68.72, *out.Fields[1].At(0).(*float64), 1e-9)
+	require.Nil(t, out.Fields[1].At(1))
+	require.Equal(t, "doubled", out.Fields[2].Name)
+	require.InDelta(t, 137.44, *out.Fields[2].At(0).(*float64), 1e-9)
+
+	_, err = NewComputeFieldsFrameProcessor(ComputeFieldsFrameProcessorConfig{
+		Fields: []ComputedFieldConfig{{Name: "broken", Expression: "$celsius *"}},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,39 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"golang.org/x/time/rate"
+)
+
+// RateLimitFrameProcessor drops frames of a channel which exceed the configured rate.
+type RateLimitFrameProcessor struct {
+	storage *ProcessorStorage
+	id      string
+	config  RateLimitFrameProcessorConfig
+}
+
+// NewRateLimitFrameProcessor creates a RateLimitFrameProcessor. The id identifies the
+// processor in its channel rule, each processor has its own limit.
+func NewRateLimitFrameProcessor(storage *ProcessorStorage, id string, config RateLimitFrameProcessorConfig) *RateLimitFrameProcessor {
+	return &RateLimitFrameProcessor{storage: storage, id: id, config: config}
+}
+
+const FrameProcessorTypeRateLimit = "rateLimit"
+
+func (p *RateLimitFrameProcessor) Type() string {
+	return FrameProcessorTypeRateLimit
+}
+
+func (p *RateLimitFrameProcessor) ProcessFrame(_ context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	burst := p.config.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+	limiter := p.storage.limiter(vars.OrgID, vars.Channel, p.id, rate.Limit(p.config.FramesPerSecond), burst)
+	if !limiter.Allow() {
+		return nil, nil
+	}
+	return frame, nil
+}
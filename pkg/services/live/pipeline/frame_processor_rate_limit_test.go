@@ -0,0 +1,59 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimitFrameProcessor(t *testing.T) {
+	storage := NewProcessorStorage()
+	p := NewRateLimitFrameProcessor(storage, "0", RateLimitFrameProcessorConfig{FramesPerSecond: 0.001, Burst: 2})
+	frame := data.NewFrame("test", data.NewField("value", nil, []float64{1}))
+	vars := Vars{OrgID: 1, Channel: "stream/test/sensor"}
+
+	for i := 0; i < 2; i++ {
+		out, err := p.ProcessFrame(context.Background(), vars, frame)
+		require.NoError(t, err)
+		require.Equal(t, frame, out)
+	}
+	out, err := p.ProcessFrame(context.Background(), vars, frame)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	// Other channels have their own limit.
+	out, err = p.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/test/other"}, frame)
+	require.NoError(t, err)
+	require.Equal(t, frame, out)
+
+	// Other processors of the rule have their own limit.
+	out, err = NewRateLimitFrameProcessor(storage, "1", RateLimitFrameProcessorConfig{FramesPerSecond: 0.001, Burst: 2}).ProcessFrame(context.Background(), vars, frame)
+	require.NoError(t, err)
+	require.Equal(t, frame, out)
+
+	// The limit is kept when the rules are rebuilt.
+	p = NewRateLimitFrameProcessor(storage, "0", RateLimitFrameProcessorConfig{FramesPerSecond: 0.001, Burst: 2})
+	out, err = p.ProcessFrame(context.Background(), vars, frame)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestProcessorStorage_EvictIdleLimiters(t *testing.T) {
+	storage := NewProcessorStorage()
+	now := time.Now()
+	storage.now = func() time.Time { return now }
+
+	storage.limiter(1, "stream/test/full", "0", rate.Limit(100), 1)
+	storage.limiter(1, "stream/test/empty", "0", rate.Limit(0.0001), 1).Allow()
+	require.Len(t, storage.limiters, 2)
+
+	// Only the limiter which is full again is removed, a new limiter would allow more frames than the other one.
+	now = now.Add(processorStateIdleTimeout + time.Minute)
+	storage.limiter(1, "stream/test/other", "0", rate.Limit(100), 1)
+	require.Len(t, storage.limiters, 2)
+	require.Contains(t, storage.limiters, processorStateKey{orgID: 1, channel: "stream/test/empty", processorID: "0"})
+}
@@ -0,0 +1,217 @@
+package pipeline
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	WindowReducerAvg   = "avg"
+	WindowReducerMin   = "min"
+	WindowReducerMax   = "max"
+	WindowReducerCount = "count"
+)
+
+func isValidWindowReducer(reducer string) bool {
+	switch reducer {
+	case WindowReducerAvg, WindowReducerMin, WindowReducerMax, WindowReducerCount:
+		return true
+	}
+	return false
+}
+
+// WindowAggregateFrameProcessor aggregates numeric fields of a channel over tumbling
+// windows. Rows are held back until a row of a later window arrives, then the frame
+// with one row per completed window is passed further. The time of each row is the
+// start of its window, rows without a time field belong to the window of the current
+// time. Fields which are not aggregated are dropped.
+type WindowAggregateFrameProcessor struct {
+	storage *ProcessorStorage
+	id      string
+	config  WindowAggregateFrameProcessorConfig
+	now     func() time.Time
+}
+
+// NewWindowAggregateFrameProcessor creates a WindowAggregateFrameProcessor. The id identifies
+// the processor in its channel rule, the windows of each processor are kept separately.
+func NewWindowAggregateFrameProcessor(storage *ProcessorStorage, id string, config WindowAggregateFrameProcessorConfig) *WindowAggregateFrameProcessor {
+	return &WindowAggregateFrameProcessor{storage: storage, id: id, config: config, now: time.Now}
+}
+
+const FrameProcessorTypeWindowAggregate = "windowAggregate"
+
+func (p *WindowAggregateFrameProcessor) Type() string {
+	return FrameProcessorTypeWindowAggregate
+}
+
+func (p *WindowAggregateFrameProcessor) ProcessFrame(_ context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	size := time.Duration(p.config.WindowSeconds) * time.Second
+
+	timeField := -1
+	var valueFields []int
+	for i, field := range frame.Fields {
+		switch {
+		case field.Type().Time():
+			if timeField < 0 {
+				timeField = i
+			}
+		case field.Type().Numeric():
+			if len(p.config.FieldNames) == 0 || stringInSlice(field.Name, p.config.FieldNames) {
+				valueFields = append(valueFields, i)
+			}
+		}
+	}
+
+	w := p.storage.window(vars.OrgID, vars.Channel, p.id, size)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.size != size {
+		w.reset(size)
+	}
+
+	var completed []windowResult
+	for row := 0; row < frame.Rows(); row++ {
+		t := p.now()
+		if timeField >= 0 {
+			if v, ok := frame.Fields[timeField].ConcreteAt(row); ok {
+				t = v.(time.Time)
+			}
+		}
+		start := t.Truncate(size)
+		if w.start.IsZero() {
+			w.start = start
+		} else if start.After(w.start) {
+			completed = append(completed, w.complete(p.config.Reducer))
+			w.start = start
+		}
+		// Late rows are aggregated into the current window.
+		for _, i := range valueFields {
+			field := frame.Fields[i]
+			v, err := field.NullableFloatAt(row)
+			if err != nil {
+				return nil, err
+			}
+			w.add(field.Name, field.Labels, v)
+		}
+	}
+	if len(completed) == 0 {
+		return nil, nil
+	}
+	return windowResultsToFrame(frame.Name, completed), nil
+}
+
+// windowState is the current window of a channel.
+type windowState struct {
+	mu         sync.Mutex
+	size       time.Duration
+	start      time.Time
+	names      []string
+	aggregates map[string]*windowAggregate
+}
+
+type windowAggregate struct {
+	labels data.Labels
+	count  int
+	sum    float64
+	min    float64
+	max    float64
+}
+
+type windowResult struct {
+	start  time.Time
+	names  []string
+	labels map[string]data.Labels
+	values map[string]*float64
+}
+
+func (w *windowState) reset(size time.Duration) {
+	w.size = size
+	w.start = time.Time{}
+	w.names = nil
+	w.aggregates = map[string]*windowAggregate{}
+}
+
+func (w *windowState) add(name string, labels data.Labels, v *float64) {
+	a, ok := w.aggregates[name]
+	if !ok {
+		a = &windowAggregate{}
+		w.aggregates[name] = a
+		w.names = append(w.names, name)
+	}
+	a.labels = labels
+	if v == nil || math.IsNaN(*v) {
+		return
+	}
+	if a.count == 0 || *v < a.min {
+		a.min = *v
+	}
+	if a.count == 0 || *v > a.max {
+		a.max = *v
+	}
+	a.count++
+	a.sum += *v
+}
+
+// complete returns the result of the current window and clears it.
+func (w *windowState) complete(reducer string) windowResult {
+	result := windowResult{
+		start:  w.start,
+		names:  w.names,
+		labels: make(map[string]data.Labels, len(w.names)),
+		values: make(map[string]*float64, len(w.names)),
+	}
+	for _, name := range w.names {
+		a := w.aggregates[name]
+		result.labels[name] = a.labels
+		result.values[name] = a.reduce(reducer)
+	}
+	w.names = nil
+	w.aggregates = map[string]*windowAggregate{}
+	return result
+}
+
+func (a *windowAggregate) reduce(reducer string) *float64 {
+	var v float64
+	switch reducer {
+	case WindowReducerCount:
+		v = float64(a.count)
+		return &v
+	case WindowReducerMin:
+		v = a.min
+	case WindowReducerMax:
+		v = a.max
+	default:
+		v = a.sum / float64(a.count)
+	}
+	if a.count == 0 {
+		return nil
+	}
+	return &v
+}
+
+func windowResultsToFrame(name string, results []windowResult) *data.Frame {
+	times := make([]time.Time, len(results))
+	var names []string
+	labels := map[string]data.Labels{}
+	values := map[string][]*float64{}
+	for i, r := range results {
+		times[i] = r.start
+		for _, n := range r.names {
+			if _, ok := values[n]; !ok {
+				names = append(names, n)
+				values[n] = make([]*float64, len(results))
+			}
+			values[n][i] = r.values[n]
+			labels[n] = r.labels[n]
+		}
+	}
+	fields := []*data.Field{data.NewField("time", nil, times)}
+	for _, n := range names {
+		fields = append(fields, data.NewField(n, labels[n], values[n]))
+	}
+	return data.NewFrame(name, fields...)
+}
@@ -0,0 +1,140 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowAggregateFrameProcessor(t *testing.T) {
+	start := time.Unix(1700000000, 0).UTC()
+	frame := func(offsets []time.Duration, values []float64) *data.Frame {
+		times := make([]time.Time, len(offsets))
+		for i, o := range offsets {
+			times[i] = start.Add(o)
+		}
+		return data.NewFrame("test",
+			data.NewField("time", nil, times),
+			data.NewField("host", nil, make([]string, len(offsets))),
+			data.NewField("value", data.Labels{"sensor": "1"}, values),
+		)
+	}
+	vars := Vars{OrgID: 1, Channel: "stream/test/sensor"}
+
+	testCases := []struct {
+		reducer  string
+		expected []float64
+	}{
+		{reducer: WindowReducerAvg, expected: []float64{2, 10}},
+		{reducer: WindowReducerMin, expected: []float64{1, 10}},
+		{reducer: WindowReducerMax, expected: []float64{3, 10}},
+		{reducer: WindowReducerCount, expected: []float64{3, 1}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.reducer, func(t *testing.T) {
+			p := NewWindowAggregateFrameProcessor(NewProcessorStorage(), "0", WindowAggregateFrameProcessorConfig{
+				WindowSeconds: 10,
+				Reducer:       tc.reducer,
+			})
+
+			out, err := p.ProcessFrame(context.Background(), vars, frame([]time.Duration{0, time.Second}, []float64{1, 3}))
+			require.NoError(t, err)
+			require.Nil(t, out, "window is not completed yet")
+
+			out, err = p.ProcessFrame(context.Background(), vars, frame([]time.Duration{5 * time.Second, 10 * time.Second, 21 * time.Second}, []float64{2, 10, 7}))
+			require.NoError(t, err)
+			require.NotNil(t, out)
+			require.Len(t, out.Fields, 2)
+			require.Equal(t, 2, out.Rows())
+			require.Equal(t, start, out.Fields[0].At(0))
+			require.Equal(t, start.Add(10*time.Second), out.Fields[0].At(1))
+			require.Equal(t, "value", out.Fields[1].Name)
+			require.Equal(t, data.Labels{"sensor": "1"}, out.Fields[1].Labels)
+			for i, v := range tc.expected {
+				require.Equal(t, v, *out.Fields[1].At(i).(*float64))
+			}
+		})
+	}
+
+	t.Run("windows are kept per channel", func(t *testing.T) {
+		storage := NewProcessorStorage()
+		p := NewWindowAggregateFrameProcessor(storage, "0", WindowAggregateFrameProcessorConfig{WindowSeconds: 10, Reducer: WindowReducerAvg})
+		other := Vars{OrgID: 1, Channel: "stream/test/other"}
+
+		out, err := p.ProcessFrame(context.Background(), vars, frame([]time.Duration{0}, []float64{1}))
+		require.NoError(t, err)
+		require.Nil(t, out)
+		out, err = p.ProcessFrame(context.Background(), other, frame([]time.Duration{15 * time.Second}, []float64{5}))
+		require.NoError(t, err)
+		require.Nil(t, out)
+
+		// The state survives rebuilding the processor.
+		p = NewWindowAggregateFrameProcessor(storage, "0", WindowAggregateFrameProcessorConfig{WindowSeconds: 10, Reducer: WindowReducerAvg})
+		out, err = p.ProcessFrame(context.Background(), vars, frame([]time.Duration{12 * time.Second}, []float64{3}))
+		require.NoError(t, err)
+		require.NotNil(t, out)
+		require.Equal(t, 1, out.Rows())
+		require.Equal(t, 1.0, *out.Fields[1].At(0).(*float64))
+	})
+
+	t.Run("windows are kept per processor", func(t *testing.T) {
+		storage := NewProcessorStorage()
+		first := NewWindowAggregateFrameProcessor(storage, "0", WindowAggregateFrameProcessorConfig{WindowSeconds: 10, Reducer: WindowReducerAvg})
+		second := NewWindowAggregateFrameProcessor(storage, "1", WindowAggregateFrameProcessorConfig{WindowSeconds: 10, Reducer: WindowReducerMax})
+
+		out, err := first.ProcessFrame(context.Background(), vars, frame([]time.Duration{0, time.Second}, []float64{1, 3}))
+		require.NoError(t, err)
+		require.Nil(t, out)
+		out, err = second.ProcessFrame(context.Background(), vars, frame([]time.Duration{0}, []float64{5}))
+		require.NoError(t, err)
+		require.Nil(t, out)
+
+		out, err = first.ProcessFrame(context.Background(), vars, frame([]time.Duration{12 * time.Second}, []float64{0}))
+		require.NoError(t, err)
+		require.Equal(t, 2.0, *out.Fields[1].At(0).(*float64))
+		out, err = second.ProcessFrame(context.Background(), vars, frame([]time.Duration{12 * time.Second}, []float64{0}))
+		require.NoError(t, err)
+		require.Equal(t, 5.0, *out.Fields[1].At(0).(*float64))
+	})
+
+	t.Run("idle windows are removed", func(t *testing.T) {
+		storage := NewProcessorStorage()
+		now := start
+		storage.now = func() time.Time { return now }
+		p := NewWindowAggregateFrameProcessor(storage, "0", WindowAggregateFrameProcessorConfig{WindowSeconds: 10, Reducer: WindowReducerAvg})
+		_, err := p.ProcessFrame(context.Background(), vars, frame([]time.Duration{0}, []float64{1}))
+		require.NoError(t, err)
+		_, err = p.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/test/other"}, frame([]time.Duration{0}, []float64{1}))
+		require.NoError(t, err)
+		require.Len(t, storage.windows, 2)
+
+		now = now.Add(processorStateIdleTimeout / 2)
+		_, err = p.ProcessFrame(context.Background(), vars, frame([]time.Duration{time.Second}, []float64{1}))
+		require.NoError(t, err)
+		now = now.Add(processorStateIdleTimeout/2 + time.Minute)
+		_, err = p.ProcessFrame(context.Background(), vars, frame([]time.Duration{2 * time.Second}, []float64{1}))
+		require.NoError(t, err)
+		require.Len(t, storage.windows, 1)
+	})
+
+	t.Run("only configured fields are aggregated", func(t *testing.T) {
+		p := NewWindowAggregateFrameProcessor(NewProcessorStorage(), "0", WindowAggregateFrameProcessorConfig{
+			WindowSeconds: 1,
+			Reducer:       WindowReducerMax,
+			FieldNames:    []string{"b"},
+		})
+		in := data.NewFrame("test",
+			data.NewField("time", nil, []time.Time{start, start.Add(time.Second)}),
+			data.NewField("a", nil, []float64{1, 2}),
+			data.NewField("b", nil, []*float64{nil, nil}),
+		)
+		out, err := p.ProcessFrame(context.Background(), vars, in)
+		require.NoError(t, err)
+		require.Len(t, out.Fields, 2)
+		require.Equal(t, "b", out.Fields[1].Name)
+		require.Nil(t, out.Fields[1].At(0))
+	})
+}
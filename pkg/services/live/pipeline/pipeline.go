@@ -235,6 +235,24 @@ func (p *Pipeline) ProcessInput(ctx context.Context, orgID int64, channelID stri
 	return ok, err
 }
 
+// ProcessFrame processes a frame which was already converted, for example from a Prometheus remote write
+// or OTLP request, with the frame processors and outputters of the channel rule. It returns false if no
+// rule matches the channel.
+func (p *Pipeline) ProcessFrame(ctx context.Context, orgID int64, channelID string, frame *data.Frame) (bool, error) {
+	_, ok, err := p.ruleGetter.Get(orgID, channelID)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, nil
+	}
+	err = p.processChannelFrames(ctx, orgID, channelID, []*ChannelFrame{{Channel: channelID, Frame: frame}}, nil)
+	if err != nil {
+		return false, fmt.Errorf("error processing frame: %w", err)
+	}
+	return true, nil
+}
+
 func (p *Pipeline) processInput(ctx context.Context, orgID int64, channelID string, body []byte, visitedChannels map[string]struct{}) (bool, error) {
 	var span trace.Span
 	if p.tracer != nil {
@@ -405,17 +423,12 @@ func (p *Pipeline) processFrame(ctx context.Context, orgID int64, channelID stri
 		Path:      ch.Path,
 	}
 
-	if len(rule.FrameProcessors) > 0 {
-		for _, proc := range rule.FrameProcessors {
-			frame, err = p.execProcessor(ctx, proc, vars, frame)
-			if err != nil {
-				logger.Error("Error processing frame", "error", err)
-				return nil, err
-			}
-			if frame == nil {
-				return nil, nil
-			}
-		}
+	frame, err = p.execProcessors(ctx, rule.FrameProcessors, vars, frame)
+	if err != nil {
+		return nil, err
+	}
+	if frame == nil {
+		return nil, nil
 	}
 
 	if len(rule.FrameOutputters) > 0 {
@@ -434,6 +447,38 @@ func (p *Pipeline) processFrame(ctx context.Context, orgID int64, channelID stri
 	return nil, nil
 }
 
+// ApplyFrameProcessors applies the FrameProcessors of the channel rule to a frame without
+// outputting it, so that rules can be tried out. It returns nil if the frame was dropped.
+func (p *Pipeline) ApplyFrameProcessors(ctx context.Context, rule LiveChannelRule, orgID int64, channelID string, frame *data.Frame) (*data.Frame, error) {
+	ch, err := live.ParseChannel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	vars := Vars{
+		OrgID:     orgID,
+		Channel:   channelID,
+		Scope:     ch.Scope,
+		Namespace: ch.Namespace,
+		Path:      ch.Path,
+	}
+	return p.execProcessors(ctx, rule.FrameProcessors, vars, frame)
+}
+
+func (p *Pipeline) execProcessors(ctx context.Context, processors []FrameProcessor, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	for _, proc := range processors {
+		var err error
+		frame, err = p.execProcessor(ctx, proc, vars, frame)
+		if err != nil {
+			logger.Error("Error processing frame", "error", err)
+			return nil, err
+		}
+		if frame == nil {
+			return nil, nil
+		}
+	}
+	return frame, nil
+}
+
 func (p *Pipeline) execProcessor(ctx context.Context, proc FrameProcessor, vars Vars, frame *data.Frame) (*data.Frame, error) {
 	var span trace.Span
 	if p.tracer != nil {
@@ -100,6 +100,29 @@ func TestPipeline(t *testing.T) {
 	require.NotNil(t, outputter.frame)
 }
 
+func TestPipeline_ProcessFrame(t *testing.T) {
+	outputter := &testOutputter{}
+	p, err := New(&testRuleGetter{
+		rules: map[string]*LiveChannelRule{
+			"stream/test/xxx": {
+				FrameProcessors: []FrameProcessor{&testProcessor{}},
+				FrameOutputters: []FrameOutputter{outputter},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	frame := data.NewFrame("test")
+	ok, err := p.ProcessFrame(context.Background(), 1, "stream/test/xxx", frame)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, frame, outputter.frame)
+
+	ok, err = p.ProcessFrame(context.Background(), 1, "stream/test/yyy", frame)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
 func TestPipeline_OutputError(t *testing.T) {
 	boomErr := errors.New("boom")
 	outputter := &testOutputter{err: boomErr}
@@ -0,0 +1,116 @@
+package pipeline
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// processorStateIdleTimeout is the time after which the state of a processor in a channel
+	// which received no frames is removed.
+	processorStateIdleTimeout = 30 * time.Minute
+	// processorStateEvictInterval is the minimum time between two checks for idle state.
+	processorStateEvictInterval = time.Minute
+)
+
+// processorStateKey identifies the state of a processor in a channel. The processor ID is the
+// position of the processor in the channel rule, so that processors of the same type in a rule
+// have their own state.
+type processorStateKey struct {
+	orgID       int64
+	channel     string
+	processorID string
+}
+
+// ProcessorStorage keeps the state of stateful frame processors per channel in memory.
+// Channel rules are rebuilt periodically, so the state can't live in processors themselves.
+// The state of channels which receive no more frames is removed after processorStateIdleTimeout.
+// Not usable in HA setup.
+type ProcessorStorage struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	lastEvict time.Time
+	windows   map[processorStateKey]*windowEntry
+	limiters  map[processorStateKey]*limiterEntry
+}
+
+type windowEntry struct {
+	state    *windowState
+	lastUsed time.Time
+	// idleTimeout is at least the size of the window, so that windows are not removed before they complete.
+	idleTimeout time.Duration
+}
+
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+func NewProcessorStorage() *ProcessorStorage {
+	return &ProcessorStorage{
+		now:      time.Now,
+		windows:  map[processorStateKey]*windowEntry{},
+		limiters: map[processorStateKey]*limiterEntry{},
+	}
+}
+
+// window returns the aggregation window state of a processor in a channel.
+func (s *ProcessorStorage) window(orgID int64, channel, processorID string, size time.Duration) *windowState {
+	key := processorStateKey{orgID: orgID, channel: channel, processorID: processorID}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.evictIdle(now)
+	e, ok := s.windows[key]
+	if !ok {
+		e = &windowEntry{state: &windowState{}}
+		s.windows[key] = e
+	}
+	e.lastUsed = now
+	e.idleTimeout = max(processorStateIdleTimeout, 2*size)
+	return e.state
+}
+
+// limiter returns the rate limiter of a processor in a channel, updated to the given limit and burst.
+func (s *ProcessorStorage) limiter(orgID int64, channel, processorID string, limit rate.Limit, burst int) *rate.Limiter {
+	key := processorStateKey{orgID: orgID, channel: channel, processorID: processorID}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.evictIdle(now)
+	e, ok := s.limiters[key]
+	if !ok {
+		e = &limiterEntry{limiter: rate.NewLimiter(limit, burst)}
+		s.limiters[key] = e
+	}
+	e.lastUsed = now
+	if e.limiter.Limit() != limit {
+		e.limiter.SetLimit(limit)
+	}
+	if e.limiter.Burst() != burst {
+		e.limiter.SetBurst(burst)
+	}
+	return e.limiter
+}
+
+// evictIdle removes the state of processors which have not been used for their idle timeout.
+// Rate limiters are only removed once they are full again, as a new limiter would be.
+// Must be called with s.mu held.
+func (s *ProcessorStorage) evictIdle(now time.Time) {
+	if now.Sub(s.lastEvict) < processorStateEvictInterval {
+		return
+	}
+	s.lastEvict = now
+	for key, e := range s.windows {
+		if now.Sub(e.lastUsed) > e.idleTimeout {
+			delete(s.windows, key)
+		}
+	}
+	for key, e := range s.limiters {
+		if now.Sub(e.lastUsed) > processorStateIdleTimeout && e.limiter.TokensAt(now) >= float64(e.limiter.Burst()) {
+			delete(s.limiters, key)
+		}
+	}
+}
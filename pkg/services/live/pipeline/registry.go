@@ -20,7 +20,7 @@ var SubscribersRegistry = []EntityInfo{
 var FrameOutputsRegistry = []EntityInfo{
 	{
 		Type:        FrameOutputTypeManagedStream,
-		Description: "only send schema when structure changes (note this also requires a matching subscriber)",
+		Description: "only send schema when structure changes, optionally keeping recent frames for new subscribers (note this also requires a matching subscriber)",
 		Example:     ManagedStreamOutputConfig{},
 	},
 	{
@@ -66,6 +66,14 @@ var ConvertersRegistry = []EntityInfo{
 		Type:        ConverterTypeJsonFrame,
 		Description: "JSON-encoded Grafana data frame",
 	},
+	{
+		Type:        ConverterTypePrometheusRemoteWrite,
+		Description: "accept Prometheus remote write requests",
+	},
+	{
+		Type:        ConverterTypeOTLPMetrics,
+		Description: "accept OTLP metrics in protobuf or JSON",
+	},
 }
 
 var FrameProcessorsRegistry = []EntityInfo{
@@ -79,6 +87,28 @@ var FrameProcessorsRegistry = []EntityInfo{
 		Description: "list the fields that should be removed",
 		Example:     DropFieldsFrameProcessorConfig{},
 	},
+	{
+		Type:        FrameProcessorTypeWindowAggregate,
+		Description: "aggregate numeric fields over tumbling windows",
+		Example: WindowAggregateFrameProcessorConfig{
+			WindowSeconds: 10,
+			Reducer:       WindowReducerAvg,
+		},
+	},
+	{
+		Type:        FrameProcessorTypeRateLimit,
+		Description: "drop frames exceeding a rate per channel",
+		Example: RateLimitFrameProcessorConfig{
+			FramesPerSecond: 1,
+		},
+	},
+	{
+		Type:        FrameProcessorTypeComputeFields,
+		Description: "add fields computed from other fields",
+		Example: ComputeFieldsFrameProcessorConfig{
+			Fields: []ComputedFieldConfig{{Name: "fahrenheit", Expression: "$celsius * 1.8 + 32"}},
+		},
+	},
 }
 
 var DataOutputsRegistry = []EntityInfo{
@@ -3,6 +3,7 @@ package pipeline
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/centrifugal/centrifuge"
 
@@ -14,6 +15,7 @@ type StorageRuleBuilder struct {
 	Node                 *centrifuge.Node
 	ManagedStream        *managedstream.Runner
 	FrameStorage         *FrameStorage
+	ProcessorStorage     *ProcessorStorage
 	Storage              Storage
 	ChannelHandlerGetter ChannelHandlerGetter
 	SecretsService       secrets.Service
@@ -69,12 +71,24 @@ func (f *StorageRuleBuilder) extractConverter(config *ConverterConfig) (Converte
 			return nil, missingConfiguration
 		}
 		return NewAutoInfluxConverter(*config.AutoInfluxConverterConfig), nil
+	case ConverterTypePrometheusRemoteWrite:
+		if config.PrometheusRemoteWriteConverterConfig == nil {
+			config.PrometheusRemoteWriteConverterConfig = &PrometheusRemoteWriteConverterConfig{}
+		}
+		return NewPrometheusRemoteWriteConverter(*config.PrometheusRemoteWriteConverterConfig), nil
+	case ConverterTypeOTLPMetrics:
+		if config.OTLPMetricsConverterConfig == nil {
+			config.OTLPMetricsConverterConfig = &OTLPMetricsConverterConfig{}
+		}
+		return NewOTLPMetricsConverter(*config.OTLPMetricsConverterConfig), nil
 	default:
 		return nil, fmt.Errorf("unknown converter type: %s", config.Type)
 	}
 }
 
-func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig) (FrameProcessor, error) {
+// extractFrameProcessor builds a frame processor. The id is the position of the processor
+// in the channel rule, it identifies the state of stateful processors.
+func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig, id string) (FrameProcessor, error) {
 	if config == nil {
 		return nil, nil
 	}
@@ -90,14 +104,42 @@ func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig)
 			return nil, missingConfiguration
 		}
 		return NewKeepFieldsFrameProcessor(*config.KeepFieldsProcessorConfig), nil
+	case FrameProcessorTypeWindowAggregate:
+		if config.WindowAggregateProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		if config.WindowAggregateProcessorConfig.WindowSeconds <= 0 {
+			return nil, fmt.Errorf("window must be positive for %s", config.Type)
+		}
+		if !isValidWindowReducer(config.WindowAggregateProcessorConfig.Reducer) {
+			return nil, fmt.Errorf("unknown reducer for %s: %s", config.Type, config.WindowAggregateProcessorConfig.Reducer)
+		}
+		return NewWindowAggregateFrameProcessor(f.ProcessorStorage, id, *config.WindowAggregateProcessorConfig), nil
+	case FrameProcessorTypeRateLimit:
+		if config.RateLimitProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		if config.RateLimitProcessorConfig.FramesPerSecond <= 0 {
+			return nil, fmt.Errorf("rate must be positive for %s", config.Type)
+		}
+		return NewRateLimitFrameProcessor(f.ProcessorStorage, id, *config.RateLimitProcessorConfig), nil
+	case FrameProcessorTypeComputeFields:
+		if config.ComputeFieldsProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		proc, err := NewComputeFieldsFrameProcessor(*config.ComputeFieldsProcessorConfig)
+		if err != nil {
+			return nil, err
+		}
+		return proc, nil
 	case FrameProcessorTypeMultiple:
 		if config.MultipleProcessorConfig == nil {
 			return nil, missingConfiguration
 		}
 		var processors []FrameProcessor
-		for _, outConf := range config.MultipleProcessorConfig.Processors {
+		for i, outConf := range config.MultipleProcessorConfig.Processors {
 			out := outConf
-			proc, err := f.extractFrameProcessor(&out)
+			proc, err := f.extractFrameProcessor(&out, id+"."+strconv.Itoa(i))
 			if err != nil {
 				return nil, err
 			}
@@ -190,7 +232,13 @@ func (f *StorageRuleBuilder) extractFrameOutputter(config *FrameOutputterConfig,
 		}
 		return NewMultipleFrameOutput(outputters...), nil
 	case FrameOutputTypeManagedStream:
-		return NewManagedStreamFrameOutput(f.ManagedStream), nil
+		if config.ManagedStreamConfig == nil {
+			config.ManagedStreamConfig = &ManagedStreamOutputConfig{}
+		}
+		if config.ManagedStreamConfig.ReplayMaxFrames < 0 || config.ManagedStreamConfig.ReplayMaxAgeMilliseconds < 0 {
+			return nil, fmt.Errorf("replay retention of %s must not be negative", config.Type)
+		}
+		return NewManagedStreamFrameOutput(f.ManagedStream, *config.ManagedStreamConfig), nil
 	case FrameOutputTypeLocalSubscribers:
 		return NewLocalSubscribersFrameOutput(f.Node), nil
 	case FrameOutputTypeConditional:
@@ -334,8 +382,8 @@ func (f *StorageRuleBuilder) BuildRules(ctx context.Context, orgID int64) ([]*Li
 		}
 
 		var processors []FrameProcessor
-		for _, procConfig := range ruleConfig.Settings.FrameProcessors {
-			proc, err := f.extractFrameProcessor(procConfig)
+		for i, procConfig := range ruleConfig.Settings.FrameProcessors {
+			proc, err := f.extractFrameProcessor(procConfig, strconv.Itoa(i))
 			if err != nil {
 				return nil, fmt.Errorf("error building processor for %s: %w", rule.Pattern, err)
 			}
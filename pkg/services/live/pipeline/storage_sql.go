@@ -0,0 +1,339 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// SQLStorage keeps channel rules and write configs in the Grafana database, so that
+// they are shared by all Grafana instances. Secure settings of write configs are
+// encrypted with the secrets service.
+type SQLStorage struct {
+	SQLStore       db.DB
+	SecretsService secrets.Service
+	// OnChange is called after the channel rules or write configs of an organization
+	// were changed, to let the instances rebuild their rules.
+	OnChange func(orgID int64)
+}
+
+// liveChannelRule represents a record in live_channel_rule table
+type liveChannelRule struct {
+	ID       int64 `xorm:"pk autoincr 'id'"`
+	OrgID    int64 `xorm:"org_id"`
+	Pattern  string
+	Settings string
+	Created  time.Time
+	Updated  time.Time
+}
+
+func (r liveChannelRule) TableName() string {
+	return "live_channel_rule"
+}
+
+// liveWriteConfig represents a record in live_write_config table
+type liveWriteConfig struct {
+	ID             int64  `xorm:"pk autoincr 'id'"`
+	OrgID          int64  `xorm:"org_id"`
+	UID            string `xorm:"uid"`
+	Settings       string
+	SecureSettings string `xorm:"secure_settings"`
+	Created        time.Time
+	Updated        time.Time
+}
+
+func (c liveWriteConfig) TableName() string {
+	return "live_write_config"
+}
+
+func (s *SQLStorage) notifyChange(orgID int64) {
+	if s.OnChange != nil {
+		s.OnChange(orgID)
+	}
+}
+
+func (s *SQLStorage) ListWriteConfigs(ctx context.Context, orgID int64) ([]WriteConfig, error) {
+	var records []liveWriteConfig
+	err := s.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("uid").Find(&records)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't read write configs: %w", err)
+	}
+	configs := make([]WriteConfig, 0, len(records))
+	for _, r := range records {
+		c, err := writeConfigFromRecord(r)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, c)
+	}
+	return configs, nil
+}
+
+func (s *SQLStorage) GetWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigGetCmd) (WriteConfig, bool, error) {
+	var record liveWriteConfig
+	var found bool
+	err := s.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		found, err = sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Get(&record)
+		return err
+	})
+	if err != nil {
+		return WriteConfig{}, false, fmt.Errorf("can't read write config: %w", err)
+	}
+	if !found {
+		return WriteConfig{}, false, nil
+	}
+	c, err := writeConfigFromRecord(record)
+	if err != nil {
+		return WriteConfig{}, false, err
+	}
+	return c, true, nil
+}
+
+func (s *SQLStorage) CreateWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigCreateCmd) (WriteConfig, error) {
+	if cmd.UID == "" {
+		cmd.UID = util.GenerateShortUID()
+	}
+	backend, record, err := s.newWriteConfig(ctx, orgID, cmd.UID, cmd.Settings, cmd.SecureSettings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	err = s.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, backend.UID).Exist(&liveWriteConfig{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("backend already exists in org: %s", backend.UID)
+		}
+		record.Created = record.Updated
+		_, err = sess.Insert(&record)
+		return err
+	})
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	s.notifyChange(orgID)
+	return backend, nil
+}
+
+func (s *SQLStorage) UpdateWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigUpdateCmd) (WriteConfig, error) {
+	backend, record, err := s.newWriteConfig(ctx, orgID, cmd.UID, cmd.Settings, cmd.SecureSettings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	var updated int64
+	err = s.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		updated, err = sess.Where("org_id = ? AND uid = ?", orgID, backend.UID).
+			Cols("settings", "secure_settings", "updated").
+			Update(&record)
+		return err
+	})
+	if err != nil {
+		return WriteConfig{}, fmt.Errorf("can't update write config: %w", err)
+	}
+	if updated == 0 {
+		return s.CreateWriteConfig(ctx, orgID, WriteConfigCreateCmd(cmd))
+	}
+	s.notifyChange(orgID)
+	return backend, nil
+}
+
+func (s *SQLStorage) DeleteWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigDeleteCmd) error {
+	var deleted int64
+	err := s.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		deleted, err = sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Delete(&liveWriteConfig{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("can't delete write config: %w", err)
+	}
+	if deleted == 0 {
+		return errors.New("write config not found")
+	}
+	s.notifyChange(orgID)
+	return nil
+}
+
+// newWriteConfig encrypts the secure settings and validates the write config.
+func (s *SQLStorage) newWriteConfig(ctx context.Context, orgID int64, uid string, settings WriteSettings, secureSettings map[string]string) (WriteConfig, liveWriteConfig, error) {
+	encrypted, err := s.SecretsService.EncryptJsonData(ctx, secureSettings, secrets.WithoutScope())
+	if err != nil {
+		return WriteConfig{}, liveWriteConfig{}, fmt.Errorf("error encrypting data: %w", err)
+	}
+	backend := WriteConfig{
+		OrgId:          orgID,
+		UID:            uid,
+		Settings:       settings,
+		SecureSettings: encrypted,
+	}
+	ok, reason := backend.Valid()
+	if !ok {
+		return WriteConfig{}, liveWriteConfig{}, fmt.Errorf("invalid write config: %s", reason)
+	}
+	settingsJSON, err := json.Marshal(backend.Settings)
+	if err != nil {
+		return WriteConfig{}, liveWriteConfig{}, fmt.Errorf("can't marshal write config settings: %w", err)
+	}
+	secureSettingsJSON, err := json.Marshal(backend.SecureSettings)
+	if err != nil {
+		return WriteConfig{}, liveWriteConfig{}, fmt.Errorf("can't marshal write config secure settings: %w", err)
+	}
+	return backend, liveWriteConfig{
+		OrgID:          orgID,
+		UID:            uid,
+		Settings:       string(settingsJSON),
+		SecureSettings: string(secureSettingsJSON),
+		Updated:        time.Now(),
+	}, nil
+}
+
+func writeConfigFromRecord(r liveWriteConfig) (WriteConfig, error) {
+	c := WriteConfig{
+		OrgId: r.OrgID,
+		UID:   r.UID,
+	}
+	if err := json.Unmarshal([]byte(r.Settings), &c.Settings); err != nil {
+		return WriteConfig{}, fmt.Errorf("can't unmarshal settings of write config %s: %w", r.UID, err)
+	}
+	if r.SecureSettings != "" {
+		if err := json.Unmarshal([]byte(r.SecureSettings), &c.SecureSettings); err != nil {
+			return WriteConfig{}, fmt.Errorf("can't unmarshal secure settings of write config %s: %w", r.UID, err)
+		}
+	}
+	return c, nil
+}
+
+func (s *SQLStorage) ListChannelRules(ctx context.Context, orgID int64) ([]ChannelRule, error) {
+	var rules []ChannelRule
+	err := s.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		rules, err = listChannelRules(sess, orgID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't read channel rules: %w", err)
+	}
+	return rules, nil
+}
+
+func listChannelRules(sess *db.Session, orgID int64) ([]ChannelRule, error) {
+	var records []liveChannelRule
+	if err := sess.Where("org_id = ?", orgID).Asc("pattern").Find(&records); err != nil {
+		return nil, err
+	}
+	rules := make([]ChannelRule, 0, len(records))
+	for _, r := range records {
+		rule := ChannelRule{
+			OrgId:   r.OrgID,
+			Pattern: r.Pattern,
+		}
+		if err := json.Unmarshal([]byte(r.Settings), &rule.Settings); err != nil {
+			return nil, fmt.Errorf("can't unmarshal settings of channel rule %s: %w", r.Pattern, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (s *SQLStorage) CreateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleCreateCmd) (ChannelRule, error) {
+	rule, record, err := newChannelRule(orgID, cmd.Pattern, cmd.Settings)
+	if err != nil {
+		return rule, err
+	}
+	err = s.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		existing, err := listChannelRules(sess, orgID)
+		if err != nil {
+			return fmt.Errorf("can't read channel rules: %w", err)
+		}
+		for _, existingRule := range existing {
+			if existingRule.Pattern == rule.Pattern {
+				return fmt.Errorf("pattern already exists in org: %s", rule.Pattern)
+			}
+		}
+		ok, reason := checkRulesValid(orgID, append(existing, rule))
+		if !ok {
+			return errors.New(reason)
+		}
+		record.Created = record.Updated
+		_, err = sess.Insert(&record)
+		return err
+	})
+	if err != nil {
+		return rule, err
+	}
+	s.notifyChange(orgID)
+	return rule, nil
+}
+
+func (s *SQLStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleUpdateCmd) (ChannelRule, error) {
+	rule, record, err := newChannelRule(orgID, cmd.Pattern, cmd.Settings)
+	if err != nil {
+		return rule, err
+	}
+	var updated int64
+	err = s.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		updated, err = sess.Where("org_id = ? AND pattern = ?", orgID, rule.Pattern).
+			Cols("settings", "updated").
+			Update(&record)
+		return err
+	})
+	if err != nil {
+		return rule, fmt.Errorf("can't update channel rule: %w", err)
+	}
+	if updated == 0 {
+		return s.CreateChannelRule(ctx, orgID, ChannelRuleCreateCmd(cmd))
+	}
+	s.notifyChange(orgID)
+	return rule, nil
+}
+
+func (s *SQLStorage) DeleteChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleDeleteCmd) error {
+	var deleted int64
+	err := s.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		deleted, err = sess.Where("org_id = ? AND pattern = ?", orgID, cmd.Pattern).Delete(&liveChannelRule{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("can't delete channel rule: %w", err)
+	}
+	if deleted == 0 {
+		return errors.New("rule not found")
+	}
+	s.notifyChange(orgID)
+	return nil
+}
+
+func newChannelRule(orgID int64, pattern string, settings ChannelRuleSettings) (ChannelRule, liveChannelRule, error) {
+	rule := ChannelRule{
+		OrgId:    orgID,
+		Pattern:  pattern,
+		Settings: settings,
+	}
+	ok, reason := rule.Valid()
+	if !ok {
+		return rule, liveChannelRule{}, fmt.Errorf("invalid channel rule: %s", reason)
+	}
+	settingsJSON, err := json.Marshal(rule.Settings)
+	if err != nil {
+		return rule, liveChannelRule{}, fmt.Errorf("can't marshal channel rule settings: %w", err)
+	}
+	return rule, liveChannelRule{
+		OrgID:    orgID,
+		Pattern:  pattern,
+		Settings: string(settingsJSON),
+		Updated:  time.Now(),
+	}, nil
+}
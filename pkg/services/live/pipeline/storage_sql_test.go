@@ -0,0 +1,120 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationSQLStorage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	var changes []int64
+	storage := &SQLStorage{
+		SQLStore:       db.InitTestDB(t),
+		SecretsService: fakes.NewFakeSecretsService(),
+		OnChange: func(orgID int64) {
+			changes = append(changes, orgID)
+		},
+	}
+	ctx := context.Background()
+
+	t.Run("channel rules", func(t *testing.T) {
+		changes = nil
+		settings := ChannelRuleSettings{
+			Converter: &ConverterConfig{Type: ConverterTypeJsonAuto, AutoJsonConverterConfig: &AutoJsonConverterConfig{}},
+		}
+		_, err := storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/sensors/:sensor", Settings: settings})
+		require.NoError(t, err)
+		_, err = storage.CreateChannelRule(ctx, 2, ChannelRuleCreateCmd{Pattern: "stream/sensors/:sensor", Settings: settings})
+		require.NoError(t, err)
+
+		_, err = storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/sensors/:sensor", Settings: settings})
+		require.ErrorContains(t, err, "pattern already exists")
+		_, err = storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/sensors/:other", Settings: settings})
+		require.Error(t, err, "conflicting patterns must be rejected")
+		_, err = storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/sensors/temp", Settings: ChannelRuleSettings{
+			Converter: &ConverterConfig{Type: "unknown"},
+		}})
+		require.ErrorContains(t, err, "invalid channel rule")
+
+		settings.FrameProcessors = []*FrameProcessorConfig{{
+			Type:                      FrameProcessorTypeKeepFields,
+			KeepFieldsProcessorConfig: &KeepFieldsFrameProcessorConfig{FieldNames: []string{"value"}},
+		}}
+		_, err = storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/sensors/:sensor", Settings: settings})
+		require.NoError(t, err)
+		_, err = storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/metrics", Settings: settings})
+		require.NoError(t, err, "updating a missing rule must create it")
+
+		rules, err := storage.ListChannelRules(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		require.Equal(t, "stream/metrics", rules[0].Pattern)
+		require.Equal(t, "stream/sensors/:sensor", rules[1].Pattern)
+		require.Equal(t, settings, rules[1].Settings)
+
+		require.NoError(t, storage.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/metrics"}))
+		require.Error(t, storage.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/metrics"}))
+
+		rules, err = storage.ListChannelRules(ctx, 2)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Nil(t, rules[0].Settings.FrameProcessors, "other organizations must not be changed")
+
+		require.Equal(t, []int64{1, 2, 1, 1, 1}, changes)
+	})
+
+	t.Run("write configs", func(t *testing.T) {
+		changes = nil
+		created, err := storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{
+			Settings:       WriteSettings{Endpoint: "http://localhost:9090/api/v1/write", BasicAuth: &BasicAuth{User: "admin"}},
+			SecureSettings: map[string]string{"basicAuthPassword": "secret"},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, created.UID)
+
+		_, err = storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{UID: created.UID, Settings: created.Settings})
+		require.ErrorContains(t, err, "already exists")
+		_, err = storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{UID: "no-endpoint"})
+		require.ErrorContains(t, err, "endpoint required")
+
+		stored, ok, err := storage.GetWriteConfig(ctx, 1, WriteConfigGetCmd{UID: created.UID})
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, created, stored)
+		require.Equal(t, []byte("secret"), stored.SecureSettings["basicAuthPassword"])
+
+		_, ok, err = storage.GetWriteConfig(ctx, 2, WriteConfigGetCmd{UID: created.UID})
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		updated, err := storage.UpdateWriteConfig(ctx, 1, WriteConfigUpdateCmd{
+			UID:      created.UID,
+			Settings: WriteSettings{Endpoint: "http://localhost:9091/api/v1/write"},
+		})
+		require.NoError(t, err)
+		configs, err := storage.ListWriteConfigs(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, []WriteConfig{updated}, configs)
+		require.Empty(t, configs[0].SecureSettings)
+
+		require.NoError(t, storage.DeleteWriteConfig(ctx, 1, WriteConfigDeleteCmd{UID: created.UID}))
+		require.Error(t, storage.DeleteWriteConfig(ctx, 1, WriteConfigDeleteCmd{UID: created.UID}))
+		configs, err = storage.ListWriteConfigs(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, configs)
+
+		require.Equal(t, []int64{1, 1, 1}, changes)
+	})
+}
@@ -1,6 +1,7 @@
 package pushhttp
 
 import (
+	"compress/gzip"
 	"context"
 	"errors"
 	"io"
@@ -13,6 +14,10 @@ import (
 	"github.com/grafana/grafana/pkg/services/live"
 	"github.com/grafana/grafana/pkg/services/live/convert"
 	"github.com/grafana/grafana/pkg/services/live/pushurl"
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/otlp"
+	"github.com/grafana/grafana/pkg/services/live/telemetry/promremotewrite"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -21,12 +26,18 @@ var (
 	logger = log.New("live.push_http")
 )
 
+// maxBodySize is the maximum size in bytes of the body of a push request.
+const maxBodySize = 10 * 1024 * 1024 // 10MB
+
+var errBodyTooLarge = errors.New("request body too large")
+
 func ProvideService(cfg *setting.Cfg, live *live.GrafanaLive) *Gateway {
 	logger.Info("Live Push Gateway initialization")
 	g := &Gateway{
-		Cfg:         cfg,
-		GrafanaLive: live,
-		converter:   convert.NewConverter(),
+		Cfg:                  cfg,
+		GrafanaLive:          live,
+		converter:            convert.NewConverter(),
+		remoteWriteConverter: promremotewrite.NewConverter(),
 	}
 	return g
 }
@@ -36,7 +47,8 @@ type Gateway struct {
 	Cfg         *setting.Cfg
 	GrafanaLive *live.GrafanaLive
 
-	converter *convert.Converter
+	converter            *convert.Converter
+	remoteWriteConverter telemetry.Converter
 }
 
 // Run Gateway.
@@ -59,10 +71,14 @@ func (g *Gateway) Handle(ctx *contextmodel.ReqContext) {
 	urlValues := ctx.Req.URL.Query()
 	frameFormat := pushurl.FrameFormatFromValues(urlValues)
 
-	body, err := io.ReadAll(ctx.Req.Body)
+	body, err := readBody(ctx.Resp, ctx.Req)
 	if err != nil {
 		logger.Error("Error reading body", "error", err)
-		ctx.Resp.WriteHeader(http.StatusInternalServerError)
+		if errors.Is(err, errBodyTooLarge) {
+			ctx.Resp.WriteHeader(http.StatusRequestEntityTooLarge)
+		} else {
+			ctx.Resp.WriteHeader(http.StatusInternalServerError)
+		}
 		return
 	}
 	logger.Debug("Live Push request",
@@ -98,6 +114,121 @@ func (g *Gateway) Handle(ctx *contextmodel.ReqContext) {
 	ctx.Resp.WriteHeader(http.StatusOK)
 }
 
+// HandlePrometheusRemoteWrite receives Prometheus remote write requests and pushes
+// one frame per metric into the Live pipeline or the managed stream.
+func (g *Gateway) HandlePrometheusRemoteWrite(ctx *contextmodel.ReqContext) {
+	g.handleTelemetry(ctx, "prometheus_remote_write", g.remoteWriteConverter)
+}
+
+// HandleOTLPMetrics receives OTLP/HTTP metrics export requests and pushes one frame
+// per metric into the Live pipeline or the managed stream. The request is decoded according to its content type.
+func (g *Gateway) HandleOTLPMetrics(ctx *contextmodel.ReqContext) {
+	g.handleTelemetry(ctx, "otlp", otlp.NewConverter(ctx.Req.Header.Get("Content-Type")))
+}
+
+// handleTelemetry pushes the frame of each metric to the channel stream/:streamId/<metric name>. Frames of channels
+// that match a rule of the Live pipeline are processed by the pipeline, the others are pushed into the managed stream.
+func (g *Gateway) handleTelemetry(ctx *contextmodel.ReqContext, protocol string, converter telemetry.Converter) {
+	streamID := web.Params(ctx.Req)[":streamId"]
+
+	stream, err := g.GrafanaLive.ManagedStreamRunner.GetOrCreateStream(ctx.OrgID, liveDto.ScopeStream, streamID)
+	if err != nil {
+		logger.Error("Error getting stream", "error", err)
+		ctx.Resp.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	body, err := readBody(ctx.Resp, ctx.Req)
+	if err != nil {
+		logger.Error("Error reading body", "error", err)
+		if errors.Is(err, errBodyTooLarge) {
+			ctx.Resp.WriteHeader(http.StatusRequestEntityTooLarge)
+		} else {
+			ctx.Resp.WriteHeader(http.StatusBadRequest)
+		}
+		return
+	}
+	logger.Debug("Live Push request",
+		"protocol", protocol,
+		"streamId", streamID,
+		"bodyLength", len(body),
+	)
+
+	metricFrames, err := converter.Convert(body)
+	if err != nil {
+		logger.Error("Error converting metrics", "error", err, "protocol", protocol)
+		if errors.Is(err, otlp.ErrUnsupportedContentType) {
+			ctx.Resp.WriteHeader(http.StatusUnsupportedMediaType)
+		} else {
+			ctx.Resp.WriteHeader(http.StatusBadRequest)
+		}
+		return
+	}
+
+	// The channels of the frames processed by the pipeline. Permissions are checked before any frame is pushed.
+	pipelineChannels := make([]string, len(metricFrames))
+	for i, mf := range metricFrames {
+		channel := liveDto.Channel{Scope: liveDto.ScopeStream, Namespace: streamID, Path: mf.Key()}.String()
+		ok, status := g.canProcessInPipeline(ctx, channel)
+		if status != http.StatusOK {
+			ctx.Resp.WriteHeader(status)
+			return
+		}
+		if ok {
+			pipelineChannels[i] = channel
+		}
+	}
+
+	for i, mf := range metricFrames {
+		if channel := pipelineChannels[i]; channel != "" {
+			if _, err := g.GrafanaLive.Pipeline.ProcessFrame(ctx.Req.Context(), ctx.OrgID, channel, mf.Frame()); err != nil {
+				logger.Error("Pipeline frame processing error", "error", err, "protocol", protocol, "channel", channel)
+				ctx.Resp.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			continue
+		}
+		err := stream.Push(ctx.Req.Context(), mf.Key(), mf.Frame())
+		if err != nil {
+			logger.Error("Error pushing frame", "error", err, "protocol", protocol)
+			ctx.Resp.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	ctx.Resp.WriteHeader(http.StatusOK)
+}
+
+// canProcessInPipeline returns true if a rule of the Live pipeline matches the channel, and an HTTP status
+// other than 200 if the rule can't be read or the user is not allowed to publish to the channel. As for
+// publications, channels with a rule but no publish authorization are restricted to admins.
+func (g *Gateway) canProcessInPipeline(ctx *contextmodel.ReqContext, channel string) (bool, int) {
+	if g.GrafanaLive.Pipeline == nil {
+		return false, http.StatusOK
+	}
+	rule, ok, err := g.GrafanaLive.Pipeline.Get(ctx.OrgID, channel)
+	if err != nil {
+		logger.Error("Error getting channel rule", "error", err, "channel", channel)
+		return false, http.StatusInternalServerError
+	}
+	if !ok {
+		return false, http.StatusOK
+	}
+	if rule.PublishAuth != nil {
+		canPublish, err := rule.PublishAuth.CanPublish(ctx.Req.Context(), ctx.SignedInUser)
+		if err != nil {
+			logger.Error("Error checking publish permissions", "error", err, "channel", channel)
+			return false, http.StatusInternalServerError
+		}
+		if !canPublish {
+			return false, http.StatusForbidden
+		}
+	} else if !ctx.SignedInUser.HasRole(org.RoleAdmin) {
+		return false, http.StatusForbidden
+	}
+	return true, http.StatusOK
+}
+
 func (g *Gateway) HandlePipelinePush(ctx *contextmodel.ReqContext) {
 	channelID := web.Params(ctx.Req)["*"]
 
@@ -131,3 +262,29 @@ func (g *Gateway) HandlePipelinePush(ctx *contextmodel.ReqContext) {
 
 	ctx.Resp.WriteHeader(http.StatusOK)
 }
+
+// readBody reads the body of a request, which agents may compress with gzip. Bodies larger than maxBodySize,
+// before or after decompression, are rejected with errBodyTooLarge.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	var reader io.Reader = http.MaxBytesReader(w, r.Body, maxBodySize)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = gz.Close() }()
+		reader = gz
+	}
+	body, err := io.ReadAll(io.LimitReader(reader, maxBodySize+1))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, errBodyTooLarge
+		}
+		return nil, err
+	}
+	if len(body) > maxBodySize {
+		return nil, errBodyTooLarge
+	}
+	return body, nil
+}
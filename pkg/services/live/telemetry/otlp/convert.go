@@ -0,0 +1,151 @@
+package otlp
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
+)
+
+var (
+	logger = log.New("live.telemetry.otlp")
+)
+
+// Content types of the OTLP/HTTP encodings.
+const (
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeJSON     = "application/json"
+)
+
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+var _ telemetry.Converter = (*Converter)(nil)
+
+// Converter converts OTLP metrics export requests to Grafana frames.
+type Converter struct {
+	contentType string
+}
+
+// NewConverter creates new Converter from OTLP metrics to Grafana Data Frames, for requests
+// with the given content type. Requests without content type are decoded as protobuf.
+// This converter generates one frame for each metric name, with labels, time and value fields.
+// Histograms and summaries are converted to <name>_count and <name>_sum metrics.
+func NewConverter(contentType string) *Converter {
+	return &Converter{contentType: contentType}
+}
+
+// Convert an OTLP metrics export request, encoded in protobuf or JSON according to the content type.
+func (c *Converter) Convert(body []byte) ([]telemetry.FrameWrapper, error) {
+	mediaType := ContentTypeProtobuf
+	if c.contentType != "" {
+		var err error
+		mediaType, _, err = mime.ParseMediaType(c.contentType)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedContentType, c.contentType)
+		}
+	}
+	req := pmetricotlp.NewExportRequest()
+	var err error
+	switch mediaType {
+	case ContentTypeProtobuf:
+		err = req.UnmarshalProto(body)
+	case ContentTypeJSON:
+		err = req.UnmarshalJSON(body)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedContentType, c.contentType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing OTLP metrics: %w", err)
+	}
+
+	frames := telemetry.NewSampleFrames()
+	resourceMetrics := req.Metrics().ResourceMetrics()
+	for i := 0; i < resourceMetrics.Len(); i++ {
+		rm := resourceMetrics.At(i)
+		resourceLabels := attributesToLabels(rm.Resource().Attributes(), nil)
+		scopeMetrics := rm.ScopeMetrics()
+		for j := 0; j < scopeMetrics.Len(); j++ {
+			metrics := scopeMetrics.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				appendMetric(frames, metrics.At(k), resourceLabels)
+			}
+		}
+	}
+	return frames.FrameWrappers(), nil
+}
+
+func appendMetric(frames *telemetry.SampleFrames, m pmetric.Metric, resourceLabels data.Labels) {
+	name := m.Name()
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		appendNumberDataPoints(frames, name, m.Gauge().DataPoints(), resourceLabels)
+	case pmetric.MetricTypeSum:
+		appendNumberDataPoints(frames, name, m.Sum().DataPoints(), resourceLabels)
+	case pmetric.MetricTypeHistogram:
+		points := m.Histogram().DataPoints()
+		for i := 0; i < points.Len(); i++ {
+			p := points.At(i)
+			labels := attributesToLabels(p.Attributes(), resourceLabels)
+			frames.Append(name+"_count", labels, p.Timestamp().AsTime(), float64(p.Count()))
+			if p.HasSum() {
+				frames.Append(name+"_sum", labels, p.Timestamp().AsTime(), p.Sum())
+			}
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		points := m.ExponentialHistogram().DataPoints()
+		for i := 0; i < points.Len(); i++ {
+			p := points.At(i)
+			labels := attributesToLabels(p.Attributes(), resourceLabels)
+			frames.Append(name+"_count", labels, p.Timestamp().AsTime(), float64(p.Count()))
+			if p.HasSum() {
+				frames.Append(name+"_sum", labels, p.Timestamp().AsTime(), p.Sum())
+			}
+		}
+	case pmetric.MetricTypeSummary:
+		points := m.Summary().DataPoints()
+		for i := 0; i < points.Len(); i++ {
+			p := points.At(i)
+			labels := attributesToLabels(p.Attributes(), resourceLabels)
+			frames.Append(name+"_count", labels, p.Timestamp().AsTime(), float64(p.Count()))
+			frames.Append(name+"_sum", labels, p.Timestamp().AsTime(), p.Sum())
+		}
+	default:
+		logger.Debug("Skipping metric of unsupported type", "name", name, "type", m.Type().String())
+	}
+}
+
+func appendNumberDataPoints(frames *telemetry.SampleFrames, name string, points pmetric.NumberDataPointSlice, resourceLabels data.Labels) {
+	for i := 0; i < points.Len(); i++ {
+		p := points.At(i)
+		var value float64
+		switch p.ValueType() {
+		case pmetric.NumberDataPointValueTypeInt:
+			value = float64(p.IntValue())
+		case pmetric.NumberDataPointValueTypeDouble:
+			value = p.DoubleValue()
+		default:
+			continue
+		}
+		frames.Append(name, attributesToLabels(p.Attributes(), resourceLabels), p.Timestamp().AsTime(), value)
+	}
+}
+
+// attributesToLabels converts attributes to labels, on top of a copy of the base labels.
+func attributesToLabels(attributes pcommon.Map, base data.Labels) data.Labels {
+	labels := make(data.Labels, len(base)+attributes.Len())
+	for k, v := range base {
+		labels[k] = v
+	}
+	attributes.Range(func(k string, v pcommon.Value) bool {
+		labels[k] = v.AsString()
+		return true
+	})
+	return labels
+}
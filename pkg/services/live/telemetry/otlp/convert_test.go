@@ -0,0 +1,89 @@
+package otlp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+)
+
+func testMetrics() pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "api")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	gauge := metrics.AppendEmpty()
+	gauge.SetName("system.cpu.utilization")
+	p := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	p.SetTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(2000)))
+	p.SetDoubleValue(0.5)
+	p.Attributes().PutStr("cpu", "0")
+
+	sum := metrics.AppendEmpty()
+	sum.SetName("requests")
+	p = sum.SetEmptySum().DataPoints().AppendEmpty()
+	p.SetTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(1000)))
+	p.SetIntValue(42)
+
+	histogram := metrics.AppendEmpty()
+	histogram.SetName("latency")
+	hp := histogram.SetEmptyHistogram().DataPoints().AppendEmpty()
+	hp.SetTimestamp(pcommon.NewTimestampFromTime(time.UnixMilli(1000)))
+	hp.SetCount(3)
+	hp.SetSum(1.5)
+	return md
+}
+
+func TestConverter_Convert(t *testing.T) {
+	req := pmetricotlp.NewExportRequestFromMetrics(testMetrics())
+	protoBody, err := req.MarshalProto()
+	require.NoError(t, err)
+	jsonBody, err := req.MarshalJSON()
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		contentType string
+		body        []byte
+	}{
+		"protobuf":                {contentType: ContentTypeProtobuf, body: protoBody},
+		"protobuf by default":     {body: protoBody},
+		"json":                    {contentType: ContentTypeJSON, body: jsonBody},
+		"json with charset param": {contentType: "application/json; charset=utf-8", body: jsonBody},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			frameWrappers, err := NewConverter(tc.contentType).Convert(tc.body)
+			require.NoError(t, err)
+
+			keys := make([]string, 0, len(frameWrappers))
+			for _, fw := range frameWrappers {
+				keys = append(keys, fw.Key())
+			}
+			require.Equal(t, []string{"system.cpu.utilization", "requests", "latency_count", "latency_sum"}, keys)
+
+			frame := frameWrappers[0].Frame()
+			require.Equal(t, "system.cpu.utilization", frame.Name)
+			require.Equal(t, []any{"cpu=0, service.name=api", time.UnixMilli(2000).UTC(), 0.5}, frame.RowCopy(0))
+
+			require.Equal(t, []any{"service.name=api", time.UnixMilli(1000).UTC(), float64(42)}, frameWrappers[1].Frame().RowCopy(0))
+			require.Equal(t, []any{"service.name=api", time.UnixMilli(1000).UTC(), float64(3)}, frameWrappers[2].Frame().RowCopy(0))
+			require.Equal(t, []any{"service.name=api", time.UnixMilli(1000).UTC(), 1.5}, frameWrappers[3].Frame().RowCopy(0))
+		})
+	}
+}
+
+func TestConverter_Convert_Invalid(t *testing.T) {
+	_, err := NewConverter(ContentTypeJSON).Convert([]byte(`{"resourceMetrics": [`))
+	require.ErrorContains(t, err, "error parsing OTLP metrics")
+
+	// A JSON body is not decoded as JSON if the content type is protobuf.
+	_, err = NewConverter(ContentTypeProtobuf).Convert([]byte(`{"resourceMetrics": []}`))
+	require.ErrorContains(t, err, "error parsing OTLP metrics")
+
+	_, err = NewConverter("text/plain").Convert([]byte(`{"resourceMetrics": []}`))
+	require.ErrorIs(t, err, ErrUnsupportedContentType)
+}
@@ -0,0 +1,71 @@
+package promremotewrite
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/grafana/grafana/pkg/services/live/telemetry"
+)
+
+// maxDecodedSize is the maximum size in bytes of a decompressed remote write request.
+const maxDecodedSize = 32 * 1024 * 1024 // 32MB
+
+var _ telemetry.Converter = (*Converter)(nil)
+
+// Converter converts Prometheus remote write requests to Grafana frames.
+type Converter struct{}
+
+// NewConverter creates new Converter from Prometheus remote write format to Grafana Data Frames.
+// This converter generates one frame for each metric name, with labels, time and value fields.
+func NewConverter() *Converter {
+	return &Converter{}
+}
+
+// Convert a snappy compressed remote write request.
+func (c *Converter) Convert(body []byte) ([]telemetry.FrameWrapper, error) {
+	size, err := snappy.DecodedLen(body)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing remote write request: %w", err)
+	}
+	if size > maxDecodedSize {
+		return nil, fmt.Errorf("remote write request too large: %d bytes decompressed, maximum is %d", size, maxDecodedSize)
+	}
+	decoded, err := snappy.Decode(nil, body)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing remote write request: %w", err)
+	}
+	var req prompb.WriteRequest
+	if err := proto.Unmarshal(decoded, &req); err != nil {
+		return nil, fmt.Errorf("error parsing remote write request: %w", err)
+	}
+
+	frames := telemetry.NewSampleFrames()
+	for _, ts := range req.Timeseries {
+		var name string
+		labels := make(data.Labels, len(ts.Labels))
+		for _, l := range ts.Labels {
+			if l.Name == "__name__" {
+				name = l.Value
+				continue
+			}
+			labels[l.Name] = l.Value
+		}
+		if name == "" {
+			return nil, fmt.Errorf("time series without metric name: %s", labels)
+		}
+		for _, s := range ts.Samples {
+			// Stale markers are sent when a series disappears, they are not values.
+			if value.IsStaleNaN(s.Value) {
+				continue
+			}
+			frames.Append(name, labels, time.UnixMilli(s.Timestamp), s.Value)
+		}
+	}
+	return frames.FrameWrappers(), nil
+}
@@ -0,0 +1,65 @@
+package promremotewrite
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/remotewrite"
+)
+
+func TestConverter_Convert(t *testing.T) {
+	body, err := remotewrite.TimeSeriesToBytes([]prompb.TimeSeries{
+		{
+			Labels:  []prompb.Label{{Name: "__name__", Value: "cpu_usage"}, {Name: "host", Value: "a"}},
+			Samples: []prompb.Sample{{Timestamp: 2000, Value: 0.5}, {Timestamp: 3000, Value: math.Float64frombits(value.StaleNaN)}},
+		},
+		{
+			Labels:  []prompb.Label{{Name: "__name__", Value: "cpu_usage"}, {Name: "host", Value: "b"}},
+			Samples: []prompb.Sample{{Timestamp: 1000, Value: 0.25}},
+		},
+		{
+			Labels:  []prompb.Label{{Name: "__name__", Value: "job:up:sum"}},
+			Samples: []prompb.Sample{{Timestamp: 1000, Value: 1}},
+		},
+	})
+	require.NoError(t, err)
+
+	frameWrappers, err := NewConverter().Convert(body)
+	require.NoError(t, err)
+	require.Len(t, frameWrappers, 2)
+
+	require.Equal(t, "cpu_usage", frameWrappers[0].Key())
+	frame := frameWrappers[0].Frame()
+	require.Equal(t, "cpu_usage", frame.Name)
+	require.Equal(t, 2, frame.Rows())
+	require.Equal(t, []any{"host=b", time.UnixMilli(1000), 0.25}, frame.RowCopy(0))
+	require.Equal(t, []any{"host=a", time.UnixMilli(2000), 0.5}, frame.RowCopy(1))
+
+	// The key is used in channel paths, where colons are not allowed.
+	require.Equal(t, "job_up_sum", frameWrappers[1].Key())
+	require.Equal(t, "job:up:sum", frameWrappers[1].Frame().Name)
+	require.Equal(t, data.FieldTypeFloat64, frameWrappers[1].Frame().Fields[2].Type())
+}
+
+func TestConverter_Convert_Invalid(t *testing.T) {
+	_, err := NewConverter().Convert([]byte("not snappy"))
+	require.ErrorContains(t, err, "error decompressing remote write request")
+
+	// The decompressed size is in the header of the block, the block is not decompressed if it is too large.
+	_, err = NewConverter().Convert(binary.AppendUvarint(nil, maxDecodedSize+1))
+	require.ErrorContains(t, err, "remote write request too large")
+
+	body, err := remotewrite.TimeSeriesToBytes([]prompb.TimeSeries{
+		{Labels: []prompb.Label{{Name: "host", Value: "a"}}, Samples: []prompb.Sample{{Timestamp: 1000, Value: 1}}},
+	})
+	require.NoError(t, err)
+	_, err = NewConverter().Convert(body)
+	require.ErrorContains(t, err, "time series without metric name")
+}
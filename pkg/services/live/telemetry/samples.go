@@ -0,0 +1,92 @@
+package telemetry
+
+import (
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// SampleFrames groups samples of metrics into frames with labels, time and value
+// fields, one frame for each metric.
+type SampleFrames struct {
+	// maintain the order of frames as they appear in input.
+	keys   []string
+	frames map[string]*sampleFrame
+}
+
+// NewSampleFrames creates new SampleFrames.
+func NewSampleFrames() *SampleFrames {
+	return &SampleFrames{frames: map[string]*sampleFrame{}}
+}
+
+// Append adds a sample of a metric.
+func (s *SampleFrames) Append(metric string, labels data.Labels, t time.Time, value float64) {
+	frame, ok := s.frames[metric]
+	if !ok {
+		frame = &sampleFrame{name: metric, key: sanitizeKey(metric)}
+		s.frames[metric] = frame
+		s.keys = append(s.keys, metric)
+	}
+	frame.samples = append(frame.samples, sample{labels: labels.String(), time: t, value: value})
+}
+
+// FrameWrappers returns the frames of the metrics, with the samples ordered by time.
+func (s *SampleFrames) FrameWrappers() []FrameWrapper {
+	frameWrappers := make([]FrameWrapper, 0, len(s.keys))
+	for _, key := range s.keys {
+		frameWrappers = append(frameWrappers, s.frames[key])
+	}
+	return frameWrappers
+}
+
+type sample struct {
+	labels string
+	time   time.Time
+	value  float64
+}
+
+type sampleFrame struct {
+	name    string
+	key     string
+	samples []sample
+}
+
+// Key returns a key which describes Frame metrics.
+func (f *sampleFrame) Key() string {
+	return f.key
+}
+
+// Frame transforms sampleFrame to Grafana data.Frame.
+func (f *sampleFrame) Frame() *data.Frame {
+	sort.SliceStable(f.samples, func(i, j int) bool {
+		return f.samples[i].time.Before(f.samples[j].time)
+	})
+	labels := make([]string, 0, len(f.samples))
+	times := make([]time.Time, 0, len(f.samples))
+	values := make([]float64, 0, len(f.samples))
+	for _, s := range f.samples {
+		labels = append(labels, s.labels)
+		times = append(times, s.time)
+		values = append(values, s.value)
+	}
+	return data.NewFrame(f.name,
+		data.NewField("labels", nil, labels),
+		data.NewField("time", nil, times),
+		data.NewField("value", nil, values),
+	)
+}
+
+// sanitizeKey replaces the characters of a metric name that are not allowed in
+// the path of a Live channel.
+func sanitizeKey(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '-', c == '.', c == '=':
+		default:
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
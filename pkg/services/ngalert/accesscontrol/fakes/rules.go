@@ -19,6 +19,7 @@ type FakeRuleService struct {
 	HasAccessOrErrorFunc                      func(context.Context, identity.Requester, ac.Evaluator, func() string) error
 	AuthorizeDatasourceAccessForRuleFunc      func(context.Context, identity.Requester, *models.AlertRule) error
 	AuthorizeDatasourceAccessForRuleGroupFunc func(context.Context, identity.Requester, models.RulesGroup) error
+	AuthorizeDatasourceWriteFunc              func(context.Context, identity.Requester, string) error
 	HasAccessToRuleGroupFunc                  func(context.Context, identity.Requester, models.RulesGroup) (bool, error)
 	AuthorizeAccessToRuleGroupFunc            func(context.Context, identity.Requester, models.RulesGroup) error
 	HasAccessInFolderFunc                     func(context.Context, identity.Requester, models.Namespaced) (bool, error)
@@ -61,6 +62,14 @@ func (s *FakeRuleService) AuthorizeDatasourceAccessForRuleGroup(ctx context.Cont
 	return nil
 }
 
+func (s *FakeRuleService) AuthorizeDatasourceWrite(ctx context.Context, user identity.Requester, datasourceUID string) error {
+	s.Calls = append(s.Calls, Call{"AuthorizeDatasourceWrite", []interface{}{ctx, user, datasourceUID}})
+	if s.AuthorizeDatasourceWriteFunc != nil {
+		return s.AuthorizeDatasourceWriteFunc(ctx, user, datasourceUID)
+	}
+	return nil
+}
+
 func (s *FakeRuleService) HasAccessToRuleGroup(ctx context.Context, user identity.Requester, rules models.RulesGroup) (bool, error) {
 	s.Calls = append(s.Calls, Call{"HasAccessToRuleGroup", []interface{}{ctx, user, rules}})
 	if s.HasAccessToRuleGroupFunc != nil {
@@ -110,6 +110,14 @@ func (r *RuleService) AuthorizeDatasourceAccessForRuleGroup(ctx context.Context,
 	})
 }
 
+// AuthorizeDatasourceWrite checks that user can write to the data source, e.g. the target data source of a recording rule
+func (r *RuleService) AuthorizeDatasourceWrite(ctx context.Context, user identity.Requester, datasourceUID string) error {
+	ds := accesscontrol.EvalPermission(datasources.ActionWrite, datasources.ScopeProvider.GetResourceScopeUID(datasourceUID))
+	return r.HasAccessOrError(ctx, user, ds, func() string {
+		return fmt.Sprintf("write to the data source UID '%s'", datasourceUID)
+	})
+}
+
 // HasAccessToRuleGroup checks that the identity.Requester has permissions to all rules, which means that it has permissions to:
 // - ("folders:read") read folders which contain the rules
 // - ("alert.rules:read") read alert rules in the folders
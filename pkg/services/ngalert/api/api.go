@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
@@ -47,6 +48,7 @@ type RuleAccessControlService interface {
 	AuthorizeRuleChanges(ctx context.Context, user identity.Requester, change *store.GroupDelta) error
 	AuthorizeDatasourceAccessForRule(ctx context.Context, user identity.Requester, rule *models.AlertRule) error
 	AuthorizeDatasourceAccessForRuleGroup(ctx context.Context, user identity.Requester, rules models.RulesGroup) error
+	AuthorizeDatasourceWrite(ctx context.Context, user identity.Requester, datasourceUID string) error
 	AuthorizeAccessInFolder(ctx context.Context, user identity.Requester, namespaced models.Namespaced) error
 }
 
@@ -72,6 +74,8 @@ type API struct {
 	ContactPointService  *provisioning.ContactPointService
 	Templates            *provisioning.TemplateService
 	MuteTimings          *provisioning.MuteTimingService
+	RecurringSilences    *provisioning.RecurringSilenceService
+	AlertRuleTemplates   *provisioning.AlertRuleTemplateService
 	AlertRules           *provisioning.AlertRuleService
 	AlertsRouter         *sender.AlertsRouter
 	EvaluatorFactory     eval.EvaluatorFactory
@@ -81,6 +85,7 @@ type API struct {
 	Tracer               tracing.Tracer
 	AppUrl               *url.URL
 	UserService          user.Service
+	RecordingWriter      backtesting.BackfillWriter
 
 	// Hooks can be used to replace API handlers for specific paths.
 	Hooks *Hooks
@@ -113,7 +118,10 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 				api.RuleStore,
 				ruleAuthzService,
 			),
-			receiverAuthz: accesscontrol.NewReceiverAccess[ReceiverStatus](api.AccessControl, false),
+			receiverAuthz:      accesscontrol.NewReceiverAccess[ReceiverStatus](api.AccessControl, false),
+			ruleStore:          api.RuleStore,
+			ruleAuthz:          ruleAuthzService,
+			includeFolderLabel: !api.Cfg.UnifiedAlerting.ReservedLabels.IsReservedLabelDisabled(models.FolderTitleLabel),
 		},
 	), m)
 	// Register endpoints for proxying to Prometheus-compatible backends.
@@ -149,11 +157,13 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			authz:           ruleAuthzService,
 			evaluator:       api.EvaluatorFactory,
 			cfg:             &api.Cfg.UnifiedAlerting,
-			backtesting:     backtesting.NewEngine(api.AppUrl, api.EvaluatorFactory, api.Tracer),
+			backtesting:     backtesting.NewEngine(api.AppUrl, api.EvaluatorFactory, api.Tracer, api.Cfg.UnifiedAlerting.BaseInterval, schedule.JitterStrategyFrom(api.Cfg.UnifiedAlerting, api.FeatureManager)),
 			featureManager:  api.FeatureManager,
 			appUrl:          api.AppUrl,
 			tracer:          api.Tracer,
 			folderService:   api.RuleStore,
+			ruleStore:       api.RuleStore,
+			recordingWriter: api.RecordingWriter,
 		}), m)
 	api.RegisterConfigurationApiEndpoints(NewConfiguration(
 		&ConfigSrv{
@@ -171,6 +181,8 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		contactPointService: api.ContactPointService,
 		templates:           api.Templates,
 		muteTimings:         api.MuteTimings,
+		recurringSilences:   api.RecurringSilences,
+		alertRuleTemplates:  api.AlertRuleTemplates,
 		alertRules:          api.AlertRules,
 		// XXX: Used to flag recording rules, remove when FT is removed
 		featureManager: api.FeatureManager,
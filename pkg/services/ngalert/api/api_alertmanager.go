@@ -42,6 +42,10 @@ type AlertmanagerSrv struct {
 	silenceSvc     SilenceService
 	featureManager featuremgmt.FeatureToggles
 	receiverAuthz  receiversAuthz
+	ruleStore      RuleStore
+	ruleAuthz      RuleAccessControlService
+	// includeFolderLabel is true if the folder title label is added to the alerts of rules.
+	includeFolderLabel bool
 }
 
 type UnknownReceiverError struct {
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// RoutePostRoutingSimulation routes an alert through the notification policy tree of the Grafana Alertmanager and
+// returns the routes, mute timings, silences and receivers that apply to it, without sending any notification.
+func (srv AlertmanagerSrv) RoutePostRoutingSimulation(c *contextmodel.ReqContext, body apimodels.RoutingSimulationBody) response.Response {
+	ctx := c.Req.Context()
+
+	lset := make(model.LabelSet, len(body.Labels))
+	if body.RuleUID != "" {
+		rule, err := srv.ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{UID: body.RuleUID, OrgID: c.GetOrgID()})
+		if err != nil {
+			if errors.Is(err, models.ErrAlertRuleNotFound) {
+				return ErrResp(http.StatusNotFound, err, "")
+			}
+			return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule", err)
+		}
+		if err := srv.ruleAuthz.AuthorizeAccessInFolder(ctx, c.SignedInUser, rule); err != nil {
+			return errorToResponse(err)
+		}
+		folderTitle := ""
+		if srv.includeFolderLabel {
+			folder, err := srv.ruleStore.GetNamespaceByUID(ctx, rule.NamespaceUID, c.GetOrgID(), c.SignedInUser)
+			if err != nil {
+				return toNamespaceErrorResponse(err)
+			}
+			folderTitle = folder.Title
+		}
+		// The labels of the rule are added as they are defined, templates are not expanded.
+		for k, v := range rule.Labels {
+			lset[model.LabelName(k)] = model.LabelValue(v)
+		}
+		for k, v := range state.GetRuleExtraLabels(srv.log, rule, folderTitle, srv.includeFolderLabel) {
+			lset[model.LabelName(k)] = model.LabelValue(v)
+		}
+	}
+	// The labels of the body override the labels of the rule, so that other alerts of the rule can be simulated.
+	for k, v := range body.Labels {
+		lset[k] = v
+	}
+	if err := lset.Validate(); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid labels")
+	}
+
+	at := time.Now()
+	if body.At != nil {
+		at = *body.At
+	}
+
+	silences, err := srv.silenceSvc.ListSilences(ctx, c.SignedInUser, nil)
+	if err != nil {
+		// Users that cannot read silences get the result of the simulation without silences.
+		if !errors.Is(err, accesscontrol.ErrAuthorizationBase) {
+			return response.ErrOrFallback(http.StatusInternalServerError, "failed to list silences", err)
+		}
+		silences = nil
+	}
+	simulation, err := srv.mam.SimulateRouting(ctx, c.GetOrgID(), lset, at, silences)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to simulate routing", err)
+	}
+	return response.JSON(http.StatusOK, newRoutingSimulationResult(lset, at, simulation))
+}
+
+func newRoutingSimulationResult(lset model.LabelSet, at time.Time, simulation *notifier.RoutingSimulation) apimodels.RoutingSimulationResult {
+	result := apimodels.RoutingSimulationResult{
+		Labels:    lset,
+		At:        at,
+		Routes:    make([]apimodels.SimulatedRoute, 0, len(simulation.Routes)),
+		Silences:  SilencesToGettableGrafanaSilences(withEmptyMetadata(simulation.Silences...)),
+		Receivers: simulation.Receivers,
+	}
+	for _, r := range simulation.Routes {
+		route := apimodels.SimulatedRoute{
+			Path:                    make([]apimodels.SimulatedRouteStep, 0, len(r.Path)),
+			Receiver:                r.Receiver,
+			GroupBy:                 r.GroupBy,
+			GroupWait:               model.Duration(r.GroupWait),
+			GroupInterval:           model.Duration(r.GroupInterval),
+			RepeatInterval:          model.Duration(r.RepeatInterval),
+			MuteTimeIntervals:       r.MuteTimeIntervals,
+			ActiveTimeIntervals:     r.ActiveTimeIntervals,
+			ActiveMuteTimeIntervals: r.ActiveMuteTimeIntervals,
+			Muted:                   r.Muted,
+			Autogenerated:           r.Autogenerated,
+		}
+		for _, step := range r.Path {
+			route.Path = append(route.Path, apimodels.SimulatedRouteStep{
+				Receiver: step.Receiver,
+				Matchers: step.Matchers,
+				Continue: step.Continue,
+			})
+		}
+		result.Routes = append(result.Routes, route)
+	}
+	return result
+}
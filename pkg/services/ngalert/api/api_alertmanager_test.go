@@ -12,15 +12,21 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
@@ -554,6 +560,83 @@ func TestRoutePostTestTemplates(t *testing.T) {
 	})
 }
 
+func TestRoutePostRoutingSimulation(t *testing.T) {
+	sut := createSut(t)
+
+	t.Run("assert 200 and the matched routes", func(tt *testing.T) {
+		rc := createRequestCtxInOrg(1)
+
+		response := sut.RoutePostRoutingSimulation(rc, apimodels.RoutingSimulationBody{
+			Labels: model.LabelSet{"foo": "bar"},
+		})
+		require.Equal(tt, 200, response.Status())
+
+		var result apimodels.RoutingSimulationResult
+		require.NoError(tt, json.Unmarshal(response.Body(), &result))
+		require.Equal(tt, model.LabelSet{"foo": "bar"}, result.Labels)
+		require.Len(tt, result.Routes, 1)
+		require.Equal(tt, "grafana-default-email", result.Routes[0].Receiver)
+		require.Equal(tt, []string{"grafana-default-email"}, result.Receivers)
+		require.Empty(tt, result.Silences)
+	})
+
+	t.Run("assert 400 when labels are invalid", func(tt *testing.T) {
+		rc := createRequestCtxInOrg(1)
+
+		response := sut.RoutePostRoutingSimulation(rc, apimodels.RoutingSimulationBody{
+			Labels: model.LabelSet{"foo": "\xff"},
+		})
+		require.Equal(tt, 400, response.Status())
+	})
+
+	t.Run("assert 404 when the rule does not exist", func(tt *testing.T) {
+		rc := createRequestCtxInOrg(1)
+
+		response := sut.RoutePostRoutingSimulation(rc, apimodels.RoutingSimulationBody{
+			RuleUID: "unknown",
+		})
+		require.Equal(tt, 404, response.Status())
+	})
+
+	rule := ngmodels.RuleGen.With(
+		ngmodels.RuleGen.WithOrgID(1),
+		ngmodels.RuleGen.WithLabels(data.Labels{"team": "rule", "severity": "warning"}),
+		ngmodels.RuleGen.WithNoNotificationSettings(),
+	).GenerateRef()
+	sut.ruleStore.(*ngfakes.RuleStore).PutRule(context.Background(), rule)
+
+	t.Run("assert the labels of the body take precedence over the labels of the rule", func(tt *testing.T) {
+		rc := createRequestCtxInOrg(1)
+		rc.SignedInUser.Permissions = map[int64]map[string][]string{
+			1: {
+				ac.ActionAlertingRuleRead:    {dashboards.ScopeFoldersProvider.GetResourceScopeUID(rule.NamespaceUID)},
+				dashboards.ActionFoldersRead: {dashboards.ScopeFoldersProvider.GetResourceScopeUID(rule.NamespaceUID)},
+			},
+		}
+
+		response := sut.RoutePostRoutingSimulation(rc, apimodels.RoutingSimulationBody{
+			Labels:  model.LabelSet{"team": "body"},
+			RuleUID: rule.UID,
+		})
+		require.Equal(tt, 200, response.Status())
+
+		var result apimodels.RoutingSimulationResult
+		require.NoError(tt, json.Unmarshal(response.Body(), &result))
+		require.Equal(tt, model.LabelValue("body"), result.Labels["team"])
+		require.Equal(tt, model.LabelValue("warning"), result.Labels["severity"])
+		require.Equal(tt, model.LabelValue(rule.UID), result.Labels[model.LabelName(alertingModels.RuleUIDLabel)])
+	})
+
+	t.Run("assert 403 when the user cannot read the rules in the folder", func(tt *testing.T) {
+		rc := createRequestCtxInOrg(1)
+
+		response := sut.RoutePostRoutingSimulation(rc, apimodels.RoutingSimulationBody{
+			RuleUID: rule.UID,
+		})
+		require.Equal(tt, 403, response.Status())
+	})
+}
+
 func createSut(t *testing.T) AlertmanagerSrv {
 	t.Helper()
 
@@ -574,6 +657,8 @@ func createSut(t *testing.T) AlertmanagerSrv {
 		log:            log,
 		featureManager: featuremgmt.WithFeatures(),
 		silenceSvc:     notifier.NewSilenceService(accesscontrol.NewSilenceService(ac, ruleStore), ruleStore, log, mam, ruleStore, ruleAuthzService),
+		ruleStore:      ruleStore,
+		ruleAuthz:      ruleAuthzService,
 	}
 }
 
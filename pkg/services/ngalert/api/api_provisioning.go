@@ -20,6 +20,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/api/hcl"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/prom"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/util"
@@ -33,6 +34,8 @@ type ProvisioningSrv struct {
 	contactPointService ContactPointService
 	templates           TemplateService
 	muteTimings         MuteTimingService
+	recurringSilences   RecurringSilenceService
+	alertRuleTemplates  AlertRuleTemplateService
 	alertRules          AlertRuleService
 	folderSvc           folder.Service
 
@@ -68,6 +71,27 @@ type MuteTimingService interface {
 	DeleteMuteTiming(ctx context.Context, name string, orgID int64, provenance definitions.Provenance, version string) error
 }
 
+type RecurringSilenceService interface {
+	GetRecurringSilences(ctx context.Context, orgID int64) ([]alerting_models.RecurringSilence, error)
+	GetRecurringSilence(ctx context.Context, uid string, orgID int64) (alerting_models.RecurringSilence, error)
+	CreateRecurringSilence(ctx context.Context, s alerting_models.RecurringSilence, orgID int64) (alerting_models.RecurringSilence, error)
+	UpdateRecurringSilence(ctx context.Context, s alerting_models.RecurringSilence, orgID int64) (alerting_models.RecurringSilence, error)
+	DeleteRecurringSilence(ctx context.Context, uid string, orgID int64, provenance alerting_models.Provenance) error
+}
+
+type AlertRuleTemplateService interface {
+	GetAlertRuleTemplates(ctx context.Context, orgID int64) ([]alerting_models.AlertRuleTemplate, error)
+	GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (alerting_models.AlertRuleTemplate, error)
+	CreateAlertRuleTemplate(ctx context.Context, user identity.Requester, t alerting_models.AlertRuleTemplate) (alerting_models.AlertRuleTemplate, error)
+	UpdateAlertRuleTemplate(ctx context.Context, user identity.Requester, t alerting_models.AlertRuleTemplate) (alerting_models.AlertRuleTemplate, error)
+	DeleteAlertRuleTemplate(ctx context.Context, user identity.Requester, uid string, provenance alerting_models.Provenance) error
+	GetAlertRuleTemplateInstances(ctx context.Context, orgID int64, templateUID string) ([]alerting_models.AlertRuleTemplateInstance, error)
+	GetAlertRuleTemplateInstance(ctx context.Context, orgID int64, templateUID, uid string) (alerting_models.AlertRuleTemplateInstance, error)
+	CreateAlertRuleTemplateInstance(ctx context.Context, user identity.Requester, instance alerting_models.AlertRuleTemplateInstance) (alerting_models.AlertRuleTemplateInstance, error)
+	UpdateAlertRuleTemplateInstance(ctx context.Context, user identity.Requester, instance alerting_models.AlertRuleTemplateInstance) (alerting_models.AlertRuleTemplateInstance, error)
+	DeleteAlertRuleTemplateInstance(ctx context.Context, user identity.Requester, templateUID, uid string, provenance alerting_models.Provenance) error
+}
+
 type AlertRuleService interface {
 	GetAlertRules(ctx context.Context, user identity.Requester) ([]*alerting_models.AlertRule, map[string]alerting_models.Provenance, error)
 	GetAlertRule(ctx context.Context, user identity.Requester, ruleUID string) (alerting_models.AlertRule, alerting_models.Provenance, error)
@@ -318,6 +342,159 @@ func (srv *ProvisioningSrv) RouteDeleteMuteTiming(c *contextmodel.ReqContext, na
 	return response.JSON(http.StatusNoContent, nil)
 }
 
+func (srv *ProvisioningSrv) RouteGetRecurringSilences(c *contextmodel.ReqContext) response.Response {
+	silences, err := srv.recurringSilences.GetRecurringSilences(c.Req.Context(), c.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get recurring silences", err)
+	}
+	return response.JSON(http.StatusOK, ApiRecurringSilencesFromRecurringSilences(silences))
+}
+
+func (srv *ProvisioningSrv) RouteGetRecurringSilence(c *contextmodel.ReqContext, UID string) response.Response {
+	silence, err := srv.recurringSilences.GetRecurringSilence(c.Req.Context(), UID, c.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get recurring silence", err)
+	}
+	return response.JSON(http.StatusOK, ApiRecurringSilenceFromRecurringSilence(silence))
+}
+
+func (srv *ProvisioningSrv) RoutePostRecurringSilence(c *contextmodel.ReqContext, rs definitions.RecurringSilence) response.Response {
+	silence := RecurringSilenceFromApiRecurringSilence(rs)
+	silence.CreatedBy = c.GetLogin()
+	silence.Provenance = alerting_models.Provenance(determineProvenance(c))
+	created, err := srv.recurringSilences.CreateRecurringSilence(c.Req.Context(), silence, c.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to create recurring silence", err)
+	}
+	return response.JSON(http.StatusCreated, ApiRecurringSilenceFromRecurringSilence(created))
+}
+
+func (srv *ProvisioningSrv) RoutePutRecurringSilence(c *contextmodel.ReqContext, rs definitions.RecurringSilence, UID string) response.Response {
+	silence := RecurringSilenceFromApiRecurringSilence(rs)
+	silence.UID = UID
+	silence.Provenance = alerting_models.Provenance(determineProvenance(c))
+	updated, err := srv.recurringSilences.UpdateRecurringSilence(c.Req.Context(), silence, c.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to update recurring silence", err)
+	}
+	return response.JSON(http.StatusAccepted, ApiRecurringSilenceFromRecurringSilence(updated))
+}
+
+func (srv *ProvisioningSrv) RouteDeleteRecurringSilence(c *contextmodel.ReqContext, UID string) response.Response {
+	err := srv.recurringSilences.DeleteRecurringSilence(c.Req.Context(), UID, c.GetOrgID(), alerting_models.Provenance(determineProvenance(c)))
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete recurring silence", err)
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleTemplates(c *contextmodel.ReqContext) response.Response {
+	templates, err := srv.alertRuleTemplates.GetAlertRuleTemplates(c.Req.Context(), c.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get alert rule templates", err)
+	}
+	return response.JSON(http.StatusOK, ApiAlertRuleTemplatesFromAlertRuleTemplates(templates))
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleTemplate(c *contextmodel.ReqContext, UID string) response.Response {
+	t, err := srv.alertRuleTemplates.GetAlertRuleTemplate(c.Req.Context(), c.GetOrgID(), UID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get alert rule template", err)
+	}
+	return response.JSON(http.StatusOK, ApiAlertRuleTemplateFromAlertRuleTemplate(t))
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleTemplate(c *contextmodel.ReqContext, at definitions.AlertRuleTemplate) response.Response {
+	t := AlertRuleTemplateFromApiAlertRuleTemplate(at)
+	t.Provenance = alerting_models.Provenance(determineProvenance(c))
+	created, err := srv.alertRuleTemplates.CreateAlertRuleTemplate(c.Req.Context(), c.SignedInUser, t)
+	if err != nil {
+		return alertRuleTemplateErrResp(err, "failed to create alert rule template")
+	}
+	return response.JSON(http.StatusCreated, ApiAlertRuleTemplateFromAlertRuleTemplate(created))
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRuleTemplate(c *contextmodel.ReqContext, at definitions.AlertRuleTemplate, UID string) response.Response {
+	t := AlertRuleTemplateFromApiAlertRuleTemplate(at)
+	t.UID = UID
+	t.Provenance = alerting_models.Provenance(determineProvenance(c))
+	updated, err := srv.alertRuleTemplates.UpdateAlertRuleTemplate(c.Req.Context(), c.SignedInUser, t)
+	if err != nil {
+		return alertRuleTemplateErrResp(err, "failed to update alert rule template")
+	}
+	return response.JSON(http.StatusAccepted, ApiAlertRuleTemplateFromAlertRuleTemplate(updated))
+}
+
+func (srv *ProvisioningSrv) RouteDeleteAlertRuleTemplate(c *contextmodel.ReqContext, UID string) response.Response {
+	err := srv.alertRuleTemplates.DeleteAlertRuleTemplate(c.Req.Context(), c.SignedInUser, UID, alerting_models.Provenance(determineProvenance(c)))
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete alert rule template", err)
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleTemplateInstances(c *contextmodel.ReqContext, UID string) response.Response {
+	instances, err := srv.alertRuleTemplates.GetAlertRuleTemplateInstances(c.Req.Context(), c.GetOrgID(), UID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get alert rule template instances", err)
+	}
+	return response.JSON(http.StatusOK, ApiAlertRuleTemplateInstancesFromAlertRuleTemplateInstances(instances))
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleTemplateInstance(c *contextmodel.ReqContext, UID string, InstanceUID string) response.Response {
+	instance, err := srv.alertRuleTemplates.GetAlertRuleTemplateInstance(c.Req.Context(), c.GetOrgID(), UID, InstanceUID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get alert rule template instance", err)
+	}
+	return response.JSON(http.StatusOK, ApiAlertRuleTemplateInstanceFromAlertRuleTemplateInstance(instance))
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleTemplateInstance(c *contextmodel.ReqContext, ai definitions.AlertRuleTemplateInstance, UID string) response.Response {
+	instance := AlertRuleTemplateInstanceFromApiAlertRuleTemplateInstance(ai)
+	instance.TemplateUID = UID
+	instance.Provenance = alerting_models.Provenance(determineProvenance(c))
+	created, err := srv.alertRuleTemplates.CreateAlertRuleTemplateInstance(c.Req.Context(), c.SignedInUser, instance)
+	if err != nil {
+		return alertRuleTemplateErrResp(err, "failed to create alert rule template instance")
+	}
+	return response.JSON(http.StatusCreated, ApiAlertRuleTemplateInstanceFromAlertRuleTemplateInstance(created))
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRuleTemplateInstance(c *contextmodel.ReqContext, ai definitions.AlertRuleTemplateInstance, UID string, InstanceUID string) response.Response {
+	instance := AlertRuleTemplateInstanceFromApiAlertRuleTemplateInstance(ai)
+	instance.TemplateUID = UID
+	instance.UID = InstanceUID
+	instance.Provenance = alerting_models.Provenance(determineProvenance(c))
+	updated, err := srv.alertRuleTemplates.UpdateAlertRuleTemplateInstance(c.Req.Context(), c.SignedInUser, instance)
+	if err != nil {
+		return alertRuleTemplateErrResp(err, "failed to update alert rule template instance")
+	}
+	return response.JSON(http.StatusAccepted, ApiAlertRuleTemplateInstanceFromAlertRuleTemplateInstance(updated))
+}
+
+func (srv *ProvisioningSrv) RouteDeleteAlertRuleTemplateInstance(c *contextmodel.ReqContext, UID string, InstanceUID string) response.Response {
+	err := srv.alertRuleTemplates.DeleteAlertRuleTemplateInstance(c.Req.Context(), c.SignedInUser, UID, InstanceUID, alerting_models.Provenance(determineProvenance(c)))
+	if err != nil {
+		return alertRuleTemplateErrResp(err, "failed to delete alert rule template instance")
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+// alertRuleTemplateErrResp maps the errors of the changes to the rules of template instances like the errors of the
+// alert rule routes.
+func alertRuleTemplateErrResp(err error, message string) response.Response {
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, store.ErrOptimisticLock) {
+		return ErrResp(http.StatusConflict, err, "")
+	}
+	if errors.Is(err, alerting_models.ErrQuotaReached) {
+		return ErrResp(http.StatusForbidden, err, "")
+	}
+	return response.ErrOrFallback(http.StatusInternalServerError, message, err)
+}
+
 func (srv *ProvisioningSrv) RouteGetAlertRules(c *contextmodel.ReqContext) response.Response {
 	rules, provenances, err := srv.alertRules.GetAlertRules(c.Req.Context(), c.SignedInUser)
 	if err != nil {
@@ -398,6 +575,9 @@ func (srv *ProvisioningSrv) RoutePutAlertRule(c *contextmodel.ReqContext, ar def
 func (srv *ProvisioningSrv) RouteDeleteAlertRule(c *contextmodel.ReqContext, UID string) response.Response {
 	provenance := determineProvenance(c)
 	err := srv.alertRules.DeleteAlertRule(c.Req.Context(), c.SignedInUser, UID, alerting_models.Provenance(provenance))
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
 	if err != nil {
 		return response.ErrOrFallback(http.StatusInternalServerError, "", err)
 	}
@@ -440,6 +620,9 @@ func (srv *ProvisioningSrv) RouteGetAlertRulesExport(c *contextmodel.ReqContext)
 	if len(groupsWithFullpath) == 0 {
 		return response.Empty(http.StatusNotFound)
 	}
+	if isPrometheusExport(c) {
+		return prometheusExportResponse(c, groupsWithFullpath)
+	}
 
 	e, err := AlertingFileExportFromAlertRuleGroupWithFolderFullpath(groupsWithFullpath)
 	if err != nil {
@@ -455,6 +638,9 @@ func (srv *ProvisioningSrv) RouteGetAlertRuleGroupExport(c *contextmodel.ReqCont
 	if err != nil {
 		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get alert rule group", err)
 	}
+	if isPrometheusExport(c) {
+		return prometheusExportResponse(c, []alerting_models.AlertRuleGroupWithFolderFullpath{g})
+	}
 
 	e, err := AlertingFileExportFromAlertRuleGroupWithFolderFullpath([]alerting_models.AlertRuleGroupWithFolderFullpath{g})
 	if err != nil {
@@ -474,9 +660,12 @@ func (srv *ProvisioningSrv) RouteGetAlertRuleExport(c *contextmodel.ReqContext,
 		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get alert rules", err)
 	}
 
-	e, err := AlertingFileExportFromAlertRuleGroupWithFolderFullpath([]alerting_models.AlertRuleGroupWithFolderFullpath{
-		alerting_models.NewAlertRuleGroupWithFolderFullpathFromRulesGroup(rule.AlertRule.GetGroupKey(), alerting_models.RulesGroup{&rule.AlertRule}, rule.FolderFullpath),
-	})
+	group := alerting_models.NewAlertRuleGroupWithFolderFullpathFromRulesGroup(rule.AlertRule.GetGroupKey(), alerting_models.RulesGroup{&rule.AlertRule}, rule.FolderFullpath)
+	if isPrometheusExport(c) {
+		return prometheusExportResponse(c, []alerting_models.AlertRuleGroupWithFolderFullpath{group})
+	}
+
+	e, err := AlertingFileExportFromAlertRuleGroupWithFolderFullpath([]alerting_models.AlertRuleGroupWithFolderFullpath{group})
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
 	}
@@ -511,6 +700,9 @@ func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *contextmodel.ReqContext, a
 func (srv *ProvisioningSrv) RouteDeleteAlertRuleGroup(c *contextmodel.ReqContext, folderUID string, group string) response.Response {
 	provenance := determineProvenance(c)
 	err := srv.alertRules.DeleteRuleGroup(c.Req.Context(), c.SignedInUser, folderUID, group, alerting_models.Provenance(provenance))
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
 	if err != nil {
 		return response.ErrOrFallback(http.StatusInternalServerError, "", err)
 	}
@@ -571,6 +763,51 @@ func exportResponse(c *contextmodel.ReqContext, body definitions.AlertingFileExp
 	return r(http.StatusOK, body)
 }
 
+// isPrometheusExport returns true if the alert rules are requested in the format of Prometheus rule files.
+func isPrometheusExport(c *contextmodel.ReqContext) bool {
+	return c.Query("format") == "prometheus"
+}
+
+// prometheusExportResponse responds with the rule groups converted to Prometheus rule groups, mapped by the full path of
+// their folder. If some rules cannot be expressed as Prometheus rules, it responds with 400 and the reason for each of them.
+func prometheusExportResponse(c *contextmodel.ReqContext, groups []alerting_models.AlertRuleGroupWithFolderFullpath) response.Response {
+	result := make(map[string][]definitions.PrometheusRuleGroup, len(groups))
+	var errs []error
+	for _, group := range groups {
+		promGroup, err := prom.GrafanaRulesToPrometheus(group.Title, group.Rules)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rules := make([]definitions.PrometheusRule, 0, len(promGroup.Rules))
+		for _, r := range promGroup.Rules {
+			rules = append(rules, definitions.PrometheusRule{
+				Alert:         r.Alert,
+				Expr:          r.Expr,
+				For:           r.For,
+				KeepFiringFor: r.KeepFiringFor,
+				Labels:        r.Labels,
+				Annotations:   r.Annotations,
+				Record:        r.Record,
+			})
+		}
+		result[group.FolderFullpath] = append(result[group.FolderFullpath], definitions.PrometheusRuleGroup{
+			Name:        promGroup.Name,
+			Interval:    promGroup.Interval,
+			QueryOffset: promGroup.QueryOffset,
+			Rules:       rules,
+		})
+	}
+	if len(errs) > 0 {
+		return ErrResp(http.StatusBadRequest, errors.Join(errs...), "some rules cannot be exported in Prometheus format")
+	}
+
+	if c.QueryBoolWithDefault("download", false) {
+		return response.YAMLDownload(http.StatusOK, result, "rules.yaml")
+	}
+	return response.YAML(http.StatusOK, result)
+}
+
 // escape all strings except:
 // Alert rule annotations: groups[].rules[].annotations
 // Alert rule time range: groups[].rules[].relativeTimeRange
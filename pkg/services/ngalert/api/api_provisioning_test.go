@@ -265,6 +265,185 @@ func TestIntegrationProvisioningApi(t *testing.T) {
 		})
 	})
 
+	t.Run("recurring silences", func(t *testing.T) {
+		newRecurringSilence := func() definitions.RecurringSilence {
+			return definitions.RecurringSilence{
+				Name:     "Weekly maintenance",
+				Matchers: definitions.ObjectMatchers{{Type: labels.MatchEqual, Name: "env", Value: "prod"}},
+				Cron:     "0 2 * * 0",
+				Duration: model.Duration(2 * time.Hour),
+			}
+		}
+
+		t.Run("are invalid, POST returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rs := newRecurringSilence()
+			rs.Duration = 0
+
+			response := sut.RoutePostRecurringSilence(&rc, rs)
+
+			require.Equal(t, 400, response.Status())
+			require.Contains(t, string(response.Body()), "duration must be positive")
+		})
+
+		t.Run("are missing, PUT returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutRecurringSilence(&rc, newRecurringSilence(), "does-not-exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("can be created, updated and deleted", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostRecurringSilence(&rc, newRecurringSilence())
+			require.Equal(t, 201, response.Status())
+			var created definitions.RecurringSilence
+			require.NoError(t, json.Unmarshal(response.Body(), &created))
+			require.NotEmpty(t, created.UID)
+			require.EqualValues(t, 1, created.Version)
+			require.Equal(t, definitions.Provenance(models.ProvenanceAPI), created.Provenance)
+
+			response = sut.RoutePostRecurringSilence(&rc, newRecurringSilence())
+			require.Equal(t, 400, response.Status())
+
+			rs := newRecurringSilence()
+			rs.Comment = "updated"
+			rs.Version = created.Version
+			response = sut.RoutePutRecurringSilence(&rc, rs, created.UID)
+			require.Equal(t, 202, response.Status())
+
+			response = sut.RoutePutRecurringSilence(&rc, rs, created.UID)
+			require.Equal(t, 409, response.Status())
+
+			response = sut.RouteGetRecurringSilence(&rc, created.UID)
+			require.Equal(t, 200, response.Status())
+			var updated definitions.RecurringSilence
+			require.NoError(t, json.Unmarshal(response.Body(), &updated))
+			require.Equal(t, "updated", updated.Comment)
+			require.EqualValues(t, 2, updated.Version)
+			require.Len(t, updated.Matchers, 1)
+
+			response = sut.RouteDeleteRecurringSilence(&rc, created.UID)
+			require.Equal(t, 204, response.Status())
+
+			response = sut.RouteGetRecurringSilences(&rc)
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, "[]", string(response.Body()))
+		})
+	})
+
+	t.Run("alert rule templates", func(t *testing.T) {
+		newAlertRuleTemplate := func() definitions.AlertRuleTemplate {
+			rule := createTestAlertRule("rule", 1)
+			return definitions.AlertRuleTemplate{
+				UID:        "team-template",
+				Name:       "Team rule",
+				Parameters: []definitions.AlertRuleTemplateParameter{{Name: "team", Type: "string"}},
+				Rule: definitions.AlertRuleTemplateRule{
+					Title:        "Rule of ${team}",
+					Condition:    rule.Condition,
+					Data:         rule.Data,
+					NoDataState:  rule.NoDataState,
+					ExecErrState: rule.ExecErrState,
+					Labels:       map[string]string{"team": "${team}"},
+				},
+			}
+		}
+		newInstance := func(team string) definitions.AlertRuleTemplateInstance {
+			return definitions.AlertRuleTemplateInstance{
+				UID:       team,
+				FolderUID: "folder-uid",
+				RuleGroup: "my-cool-group",
+				Values:    map[string]string{"team": team},
+			}
+		}
+
+		t.Run("are invalid, POST returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			at := newAlertRuleTemplate()
+			at.Name = ""
+
+			response := sut.RoutePostAlertRuleTemplate(&rc, at)
+
+			require.Equal(t, 400, response.Status())
+			require.Contains(t, string(response.Body()), "name must not be empty")
+		})
+
+		t.Run("instance of missing template, POST returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostAlertRuleTemplateInstance(&rc, newInstance("sre"), "does-not-exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("instance when the rule quota is reached, POST returns 403", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			quotas := provisioning.MockQuotaChecker{}
+			quotas.EXPECT().LimitExceeded()
+			env.quotas = &quotas
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostAlertRuleTemplate(&rc, newAlertRuleTemplate())
+			require.Equal(t, 201, response.Status())
+
+			response = sut.RoutePostAlertRuleTemplateInstance(&rc, newInstance("sre"), "team-template")
+			require.Equal(t, 403, response.Status())
+		})
+
+		t.Run("instances create, update and delete their rules", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostAlertRuleTemplate(&rc, newAlertRuleTemplate())
+			require.Equal(t, 201, response.Status())
+
+			response = sut.RoutePostAlertRuleTemplateInstance(&rc, newInstance("sre"), "team-template")
+			require.Equal(t, 201, response.Status())
+			var created definitions.AlertRuleTemplateInstance
+			require.NoError(t, json.Unmarshal(response.Body(), &created))
+			require.Equal(t, "team-template", created.TemplateUID)
+			require.EqualValues(t, 60, created.Interval)
+
+			response = sut.RouteRouteGetAlertRule(&rc, "sre")
+			require.Equal(t, 200, response.Status())
+			var rule definitions.ProvisionedAlertRule
+			require.NoError(t, json.Unmarshal(response.Body(), &rule))
+			require.Equal(t, "Rule of sre", rule.Title)
+			require.Equal(t, definitions.Provenance(models.ProvenanceRuleTemplate), rule.Provenance)
+
+			at := newAlertRuleTemplate()
+			at.Rule.Title = "Team ${team}"
+			response = sut.RoutePutAlertRuleTemplate(&rc, at, "team-template")
+			require.Equal(t, 202, response.Status())
+
+			response = sut.RouteRouteGetAlertRule(&rc, "sre")
+			require.Equal(t, 200, response.Status())
+			require.NoError(t, json.Unmarshal(response.Body(), &rule))
+			require.Equal(t, "Team sre", rule.Title)
+
+			response = sut.RouteDeleteAlertRuleTemplate(&rc, "team-template")
+			require.Equal(t, 409, response.Status())
+
+			response = sut.RouteDeleteAlertRuleTemplateInstance(&rc, "team-template", "sre")
+			require.Equal(t, 204, response.Status())
+
+			response = sut.RouteRouteGetAlertRule(&rc, "sre")
+			require.Equal(t, 404, response.Status())
+
+			response = sut.RouteDeleteAlertRuleTemplate(&rc, "team-template")
+			require.Equal(t, 204, response.Status())
+		})
+	})
+
 	t.Run("alert rules", func(t *testing.T) {
 		t.Run("are invalid", func(t *testing.T) {
 			t.Run("POST returns 400 on wrong body params", func(t *testing.T) {
@@ -2158,13 +2337,16 @@ func createProvisioningSrvSutFromEnv(t *testing.T, env *testEnvironment) Provisi
 		ngalertfakes.NewFakeReceiverPermissionsService(),
 		tracer,
 	)
+	alertRules := provisioning.NewAlertRuleService(env.store, env.prov, env.folderService, env.quotas, env.xact, 60, 10, 100, env.log, &provisioning.NotificationSettingsValidatorProviderFake{}, env.rulesAuthz)
 	return ProvisioningSrv{
 		log:                 env.log,
 		policies:            newFakeNotificationPolicyService(),
 		contactPointService: provisioning.NewContactPointService(configStore, env.secrets, env.prov, env.xact, receiverSvc, env.log, env.store, ngalertfakes.NewFakeReceiverPermissionsService()),
 		templates:           provisioning.NewTemplateService(configStore, env.prov, env.xact, env.log),
 		muteTimings:         provisioning.NewMuteTimingService(configStore, env.prov, env.xact, env.log, env.store),
-		alertRules:          provisioning.NewAlertRuleService(env.store, env.prov, env.folderService, env.quotas, env.xact, 60, 10, 100, env.log, &provisioning.NotificationSettingsValidatorProviderFake{}, env.rulesAuthz),
+		recurringSilences:   provisioning.NewRecurringSilenceService(env.store, env.prov, env.xact, env.log),
+		alertRuleTemplates:  provisioning.NewAlertRuleTemplateService(env.store, env.prov, env.xact, alertRules, env.log),
+		alertRules:          alertRules,
 		folderSvc:           env.folderService,
 		featureManager:      env.features,
 	}
@@ -158,6 +158,9 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *contextmodel.ReqContext, namespaceU
 			rulesToDelete = append(rulesToDelete, uid...)
 		}
 		if len(rulesToDelete) > 0 {
+			if err := store.ValidateRulesDelete(ctx, srv.store, c.GetOrgID(), rulesToDelete...); err != nil {
+				return err
+			}
 			err := srv.store.DeleteAlertRulesByUID(ctx, c.GetOrgID(), ngmodels.NewUserUID(c.SignedInUser), permanently, rulesToDelete...)
 			if err != nil {
 				return err
@@ -178,7 +181,7 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *contextmodel.ReqContext, namespaceU
 		if errors.As(err, &errutil.Error{}) {
 			return response.Err(err)
 		}
-		if errors.Is(err, errProvisionedResource) {
+		if errors.Is(err, errProvisionedResource) || errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) {
 			return ErrResp(http.StatusBadRequest, err, "failed to delete rule group")
 		}
 		return ErrResp(http.StatusInternalServerError, err, "failed to delete rule group")
@@ -670,6 +673,7 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, provenanceRecords map[stri
 			Metadata:                    AlertRuleMetadataFromModelMetadata(r.Metadata),
 			GUID:                        r.GUID,
 			MissingSeriesEvalsToResolve: r.MissingSeriesEvalsToResolve,
+			DependsOn:                   r.DependsOn,
 		},
 	}
 	forDuration := model.Duration(r.For)
@@ -14,7 +14,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
@@ -439,3 +441,82 @@ func TestExportRules(t *testing.T) {
 		})
 	}
 }
+
+func TestExportRules_PrometheusFormat(t *testing.T) {
+	orgID := int64(1)
+	f1 := randFolder()
+	f1.Fullpath = f1.Title
+
+	ruleStore := fakes.NewRuleStore(t)
+	groupKey := ngmodels.AlertRuleGroupKey{
+		OrgID:        orgID,
+		NamespaceUID: f1.UID,
+		RuleGroup:    "test-group",
+	}
+
+	threshold, err := json.Marshal(map[string]any{
+		"refId":      "B",
+		"type":       "threshold",
+		"expression": "A",
+		"conditions": []map[string]any{{"evaluator": map[string]any{"type": "gt", "params": []float64{0.5}}}},
+	})
+	require.NoError(t, err)
+	promQuery := ngmodels.CreatePrometheusQuery("A", "avg(cpu_usage)", 1000, 43200, true, "prom-uid")
+
+	gen := ngmodels.RuleGen
+	gen = gen.With(gen.WithGroupKey(groupKey), gen.WithNoDataExecAs(ngmodels.OK), gen.WithErrorExecAs(ngmodels.OkErrState), gen.WithIsPaused(false), gen.WithIntervalSeconds(60))
+	convertible := gen.With(
+		gen.WithQuery(promQuery, ngmodels.AlertQuery{RefID: "B", DatasourceUID: expr.DatasourceUID, Model: threshold}),
+		gen.WithCondition("B"),
+		gen.WithTitle("HighCPU"),
+		gen.WithGroupIndex(1),
+	).GenerateRef()
+	classic := gen.With(
+		gen.WithQuery(promQuery, ngmodels.CreateClassicConditionExpression("B", "A", "last", "gt", 1)),
+		gen.WithCondition("B"),
+		gen.WithGroupIndex(2),
+	).GenerateRef()
+	ruleStore.PutRule(context.Background(), convertible, classic)
+	ruleStore.Folders[orgID] = []*folder2.Folder{f1}
+
+	srv := createService(ruleStore, nil)
+	createRequest := func(params url.Values) *contextmodel.ReqContext {
+		rc := createRequestContextWithPerms(orgID, map[int64]map[string][]string{
+			orgID: {
+				dashboards.ActionFoldersRead:         []string{dashboards.ScopeFoldersProvider.GetResourceScopeUID(f1.UID)},
+				accesscontrol.ActionAlertingRuleRead: []string{dashboards.ScopeFoldersProvider.GetResourceScopeUID(f1.UID)},
+				datasources.ActionQuery:              []string{datasources.ScopeProvider.GetResourceScopeUID("prom-uid")},
+			},
+		}, nil)
+		rc.Req.Form = params
+		return rc
+	}
+
+	t.Run("should return the rules as Prometheus rule groups", func(t *testing.T) {
+		resp := srv.ExportRules(createRequest(url.Values{
+			"format":  []string{"prometheus"},
+			"ruleUid": []string{convertible.UID},
+		}))
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		var result map[string][]apimodels.PrometheusRuleGroup
+		require.NoError(t, yaml.Unmarshal(resp.Body(), &result))
+		require.Len(t, result[f1.Fullpath], 1)
+		group := result[f1.Fullpath][0]
+		require.Equal(t, "test-group", group.Name)
+		require.Len(t, group.Rules, 1)
+		require.Equal(t, "HighCPU", group.Rules[0].Alert)
+		require.Equal(t, "(avg(cpu_usage)) > 0.5", group.Rules[0].Expr)
+	})
+
+	t.Run("should report the rules that cannot be converted", func(t *testing.T) {
+		resp := srv.ExportRules(createRequest(url.Values{
+			"format":    []string{"prometheus"},
+			"folderUid": []string{f1.UID},
+			"group":     []string{groupKey.RuleGroup},
+		}))
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+		require.Contains(t, string(resp.Body()), classic.UID)
+		require.NotContains(t, string(resp.Body()), convertible.UID)
+	})
+}
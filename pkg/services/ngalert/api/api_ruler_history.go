@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	amlabels "github.com/prometheus/alertmanager/pkg/labels"
+
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
@@ -39,6 +41,15 @@ func (srv *HistorySrv) RouteQueryStateHistory(c *contextmodel.ReqContext) respon
 		}
 	}
 
+	var matchers amlabels.Matchers
+	for _, s := range c.QueryStrings("matcher") {
+		m, err := amlabels.ParseMatcher(s)
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, err, "invalid matcher")
+		}
+		matchers = append(matchers, m)
+	}
+
 	query := models.HistoryQuery{
 		RuleUID:      ruleUID,
 		OrgID:        c.GetOrgID(),
@@ -49,10 +60,13 @@ func (srv *HistorySrv) RouteQueryStateHistory(c *contextmodel.ReqContext) respon
 		To:           time.Unix(to, 0),
 		Limit:        limit,
 		Labels:       labels,
+		Offset:       c.QueryInt("offset"),
+		States:       c.QueryStrings("state"),
+		Matchers:     matchers,
 	}
 	frame, err := srv.hist.Query(c.Req.Context(), query)
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return response.ErrOrFallback(http.StatusInternalServerError, err.Error(), err)
 	}
 	return response.JSON(http.StatusOK, frame)
 }
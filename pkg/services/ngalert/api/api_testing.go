@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
@@ -41,6 +43,12 @@ type folderService interface {
 	GetNamespaceByUID(ctx context.Context, uid string, orgID int64, user identity.Requester) (*folder.Folder, error)
 }
 
+// backtestingRuleStore provides the versions of the alert rules to backtest against.
+type backtestingRuleStore interface {
+	GetAlertRuleByUID(ctx context.Context, query *ngmodels.GetAlertRuleByUIDQuery) (*ngmodels.AlertRule, error)
+	GetAlertRuleVersions(ctx context.Context, orgID int64, guid string) ([]*ngmodels.AlertRule, error)
+}
+
 type TestingApiSrv struct {
 	*AlertingProxy
 	DatasourceCache datasources.CacheService
@@ -53,15 +61,41 @@ type TestingApiSrv struct {
 	appUrl          *url.URL
 	tracer          tracing.Tracer
 	folderService   folderService
+	ruleStore       backtestingRuleStore
+	recordingWriter backtesting.BackfillWriter
 }
 
 // RouteTestGrafanaRuleConfig returns a list of potential alerts for a given rule configuration. This is intended to be
 // as true as possible to what would be generated by the ruler except that the resulting alerts are not filtered to
 // only Resolved / Firing and ready to send.
 func (srv TestingApiSrv) RouteTestGrafanaRuleConfig(c *contextmodel.ReqContext, body apimodels.PostableExtendedRuleNodeExtended) response.Response {
+	alerts, errResp := srv.testGrafanaRule(c, body, nil)
+	if errResp != nil {
+		return errResp
+	}
+	return response.JSON(http.StatusOK, alerts)
+}
+
+// RouteExplainGrafanaRuleConfig does the same as RouteTestGrafanaRuleConfig, and also returns the trace of the execution
+// of the queries and expressions of the rule.
+func (srv TestingApiSrv) RouteExplainGrafanaRuleConfig(c *contextmodel.ReqContext, body apimodels.PostableExtendedRuleNodeExtended) response.Response {
+	explanation := &expr.PipelineExplanation{}
+	alerts, errResp := srv.testGrafanaRule(c, body, explanation)
+	if errResp != nil {
+		return errResp
+	}
+	return response.JSON(http.StatusOK, apimodels.TestGrafanaRuleExplanation{
+		Alerts:      alerts,
+		Explanation: apiPipelineExplanation(explanation),
+	})
+}
+
+// testGrafanaRule evaluates the rule and returns the alerts it would produce, or an error response.
+// If explanation is not nil, the execution of the queries and expressions is traced into it.
+func (srv TestingApiSrv) testGrafanaRule(c *contextmodel.ReqContext, body apimodels.PostableExtendedRuleNodeExtended, explanation *expr.PipelineExplanation) ([]*amv2.PostableAlert, response.Response) {
 	folder, err := srv.folderService.GetNamespaceByUID(c.Req.Context(), body.NamespaceUID, c.OrgID, c.SignedInUser)
 	if err != nil {
-		return toNamespaceErrorResponse(dashboards.ErrFolderAccessDenied)
+		return nil, toNamespaceErrorResponse(dashboards.ErrFolderAccessDenied)
 	}
 	rule, err := apivalidation.ValidateRuleNode(
 		&body.Rule,
@@ -72,28 +106,30 @@ func (srv TestingApiSrv) RouteTestGrafanaRuleConfig(c *contextmodel.ReqContext,
 		apivalidation.RuleLimitsFromConfig(srv.cfg, srv.featureManager),
 	)
 	if err != nil {
-		return ErrResp(http.StatusBadRequest, err, "")
+		return nil, ErrResp(http.StatusBadRequest, err, "")
 	}
 
 	if err := srv.authz.AuthorizeDatasourceAccessForRule(c.Req.Context(), c.SignedInUser, rule); err != nil {
-		return response.ErrOrFallback(http.StatusInternalServerError, "failed to authorize access to rule group", err)
+		return nil, response.ErrOrFallback(http.StatusInternalServerError, "failed to authorize access to rule group", err)
 	}
 
 	if srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingQueryOptimization) {
 		if _, err := store.OptimizeAlertQueries(rule.Data); err != nil {
-			return ErrResp(http.StatusInternalServerError, err, "Failed to optimize query")
+			return nil, ErrResp(http.StatusInternalServerError, err, "Failed to optimize query")
 		}
 	}
 
-	evaluator, err := srv.evaluator.Create(eval.NewContext(c.Req.Context(), c.SignedInUser), rule.GetEvalCondition().WithSource("preview"))
+	evalCtx := eval.NewContext(c.Req.Context(), c.SignedInUser)
+	evalCtx.Explanation = explanation
+	evaluator, err := srv.evaluator.Create(evalCtx, rule.GetEvalCondition().WithSource("preview"))
 	if err != nil {
-		return ErrResp(http.StatusBadRequest, err, "Failed to build evaluator for queries and expressions")
+		return nil, ErrResp(http.StatusBadRequest, err, "Failed to build evaluator for queries and expressions")
 	}
 
 	now := time.Now()
 	results, err := evaluator.Evaluate(c.Req.Context(), now)
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "Failed to evaluate queries")
+		return nil, ErrResp(http.StatusInternalServerError, err, "Failed to evaluate queries")
 	}
 
 	cfg := state.ManagerCfg{
@@ -121,8 +157,32 @@ func (srv TestingApiSrv) RouteTestGrafanaRuleConfig(c *contextmodel.ReqContext,
 	for _, alertState := range transitions {
 		alerts = append(alerts, state.StateToPostableAlert(alertState, srv.appUrl, srv.featureManager))
 	}
+	return alerts, nil
+}
 
-	return response.JSON(http.StatusOK, alerts)
+func apiPipelineExplanation(e *expr.PipelineExplanation) apimodels.PipelineExplanation {
+	result := apimodels.PipelineExplanation{Nodes: make([]apimodels.PipelineNodeExplanation, 0, len(e.Nodes))}
+	for _, n := range e.Nodes {
+		node := apimodels.PipelineNodeExplanation{
+			RefID:        n.RefID,
+			NodeType:     n.NodeType,
+			Command:      n.Command,
+			Inputs:       n.Inputs,
+			Duration:     n.Duration.String(),
+			InputSeries:  n.InputSeries,
+			OutputSeries: n.OutputSeries,
+			DroppedCount: n.DroppedCount,
+			Error:        n.Error,
+		}
+		for _, d := range n.Dropped {
+			node.Dropped = append(node.Dropped, apimodels.PipelineDroppedSeries{Labels: d.Labels, Reason: d.Reason})
+		}
+		for _, v := range n.Samples {
+			node.Samples = append(node.Samples, apimodels.PipelineValueSample{Labels: v.Labels, Type: v.Type, Length: v.Length, Values: v.Values})
+		}
+		result.Nodes = append(result.Nodes, node)
+	}
+	return result
 }
 
 func (srv TestingApiSrv) RouteTestRuleConfig(c *contextmodel.ReqContext, body apimodels.TestRulePayload, datasourceUID string) response.Response {
@@ -186,7 +246,9 @@ func (srv TestingApiSrv) RouteEvalQueries(c *contextmodel.ReqContext, cmd apimod
 		}
 	}
 
-	evaluator, err := srv.evaluator.Create(eval.NewContext(c.Req.Context(), c.SignedInUser), cond)
+	evalCtx := eval.NewContext(c.Req.Context(), c.SignedInUser)
+	evalCtx.SQLDryRun = cmd.DryRun
+	evaluator, err := srv.evaluator.Create(evalCtx, cond)
 
 	if err != nil {
 		return ErrResp(http.StatusBadRequest, err, "Failed to build evaluator for queries and expressions")
@@ -227,30 +289,6 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		return ErrResp(http.StatusNotFound, nil, "Backgtesting API is not enabled")
 	}
 
-	if cmd.From.After(cmd.To) {
-		return ErrResp(400, nil, "From cannot be greater than To")
-	}
-
-	noDataState, err := ngmodels.NoDataStateFromString(string(cmd.NoDataState))
-
-	if err != nil {
-		return ErrResp(400, err, "")
-	}
-	forInterval := time.Duration(cmd.For)
-	if forInterval < 0 {
-		return ErrResp(400, nil, "Bad For interval")
-	}
-
-	intervalSeconds, err := apivalidation.ValidateInterval(time.Duration(cmd.Interval), srv.cfg.BaseInterval)
-	if err != nil {
-		return ErrResp(400, err, "")
-	}
-
-	queries := AlertQueriesFromApiAlertQueries(cmd.Data)
-	if err := srv.authz.AuthorizeDatasourceAccessForRule(c.Req.Context(), c.SignedInUser, &ngmodels.AlertRule{Data: queries}); err != nil {
-		return errorToResponse(err)
-	}
-
 	rule := &ngmodels.AlertRule{
 		// ID:             0,
 		// Updated:        time.Time{},
@@ -261,17 +299,10 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		// RuleGroup:      "",
 		// RuleGroupIndex: 0,
 		// ExecErrState:   "",
-		Title: cmd.Title,
-		// prefix backtesting- is to distinguish between executions of regular rule and backtesting in logs (like expression engine, evaluator, state manager etc)
-		UID:             "backtesting-" + util.GenerateShortUID(),
-		OrgID:           c.GetOrgID(),
-		Condition:       cmd.Condition,
-		Data:            queries,
-		IntervalSeconds: intervalSeconds,
-		NoDataState:     noDataState,
-		For:             forInterval,
-		Annotations:     cmd.Annotations,
-		Labels:          cmd.Labels,
+		OrgID: c.GetOrgID(),
+	}
+	if errResp := srv.applyBacktestConfig(c, cmd, rule); errResp != nil {
+		return errResp
 	}
 
 	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
@@ -288,3 +319,180 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 	}
 	return response.JSON(http.StatusOK, body)
 }
+
+// BacktestCompareAlertRule backtests a proposed version of an alert rule against one of its versions.
+func (srv TestingApiSrv) BacktestCompareAlertRule(c *contextmodel.ReqContext, cmd apimodels.BacktestCompareConfig) response.Response {
+	if !srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingBacktesting) {
+		return ErrResp(http.StatusNotFound, nil, "Backgtesting API is not enabled")
+	}
+	ctx := c.Req.Context()
+
+	current, err := srv.ruleStore.GetAlertRuleByUID(ctx, &ngmodels.GetAlertRuleByUIDQuery{OrgID: c.GetOrgID(), UID: cmd.RuleUID})
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "Failed to get the alert rule")
+	}
+	if err := srv.authz.AuthorizeAccessInFolder(ctx, c.SignedInUser, current); err != nil {
+		return errorToResponse(err)
+	}
+	if current.Type() == ngmodels.RuleTypeRecording {
+		return ErrResp(http.StatusBadRequest, nil, "Recording rules cannot be backtested")
+	}
+
+	baseline := current
+	if cmd.BaselineVersion != 0 && cmd.BaselineVersion != current.Version {
+		versions, err := srv.ruleStore.GetAlertRuleVersions(ctx, current.OrgID, current.GUID)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "Failed to get the versions of the alert rule")
+		}
+		idx := slices.IndexFunc(versions, func(r *ngmodels.AlertRule) bool {
+			return r.Version == cmd.BaselineVersion
+		})
+		if idx < 0 {
+			return ErrResp(http.StatusNotFound, nil, "Version %d of the alert rule %s does not exist", cmd.BaselineVersion, cmd.RuleUID)
+		}
+		baseline = versions[idx]
+	}
+	if err := srv.authz.AuthorizeDatasourceAccessForRule(ctx, c.SignedInUser, baseline); err != nil {
+		return errorToResponse(err)
+	}
+
+	// The fields that cannot be set in the backtesting configuration, like the error state, are the ones of the baseline.
+	proposed := baseline.Copy()
+	if errResp := srv.applyBacktestConfig(c, cmd.BacktestConfig, proposed); errResp != nil {
+		return errResp
+	}
+	baseline = baseline.Copy()
+	baseline.UID = "backtesting-" + util.GenerateShortUID()
+
+	result, err := srv.backtesting.Compare(ctx, c.SignedInUser, baseline, proposed, cmd.From, cmd.To)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(400, err, "Failed to evaluate")
+		}
+		return ErrResp(500, err, "Failed to evaluate")
+	}
+
+	return response.JSON(http.StatusOK, apimodels.BacktestCompareResult{
+		Baseline:              result.Baseline,
+		Proposed:              result.Proposed,
+		Differences:           result.Differences,
+		BaselineNotifications: result.BaselineNotifications,
+		ProposedNotifications: result.ProposedNotifications,
+		FirstDifference:       result.FirstDifference,
+	})
+}
+
+// BackfillRecordingRule evaluates a recording rule over a past time range and writes the computed samples with their
+// original timestamps.
+func (srv TestingApiSrv) BackfillRecordingRule(c *contextmodel.ReqContext, cmd apimodels.BackfillConfig) response.Response {
+	if !srv.cfg.RecordingRules.Enabled {
+		return ErrResp(http.StatusBadRequest, nil, "Recording rules are not enabled")
+	}
+	ctx := c.Req.Context()
+
+	if cmd.RuleUID == "" {
+		return ErrResp(http.StatusBadRequest, nil, "The UID of the rule is required")
+	}
+	if !cmd.From.Before(cmd.To) {
+		return ErrResp(http.StatusBadRequest, nil, "From must be before To")
+	}
+	if now := time.Now(); cmd.To.After(now) {
+		cmd.To = now
+	}
+	if cmd.ChunkSize < 0 || cmd.MaxChunks < 0 {
+		return ErrResp(http.StatusBadRequest, nil, "The chunk size and the maximum number of chunks cannot be negative")
+	}
+
+	rule, err := srv.ruleStore.GetAlertRuleByUID(ctx, &ngmodels.GetAlertRuleByUIDQuery{OrgID: c.GetOrgID(), UID: cmd.RuleUID})
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "Failed to get the alert rule")
+	}
+	if err := srv.authz.AuthorizeAccessInFolder(ctx, c.SignedInUser, rule); err != nil {
+		return errorToResponse(err)
+	}
+	if rule.Type() != ngmodels.RuleTypeRecording {
+		return ErrResp(http.StatusBadRequest, nil, "Only recording rules can be backfilled")
+	}
+	if err := srv.authz.AuthorizeDatasourceAccessForRule(ctx, c.SignedInUser, rule); err != nil {
+		return errorToResponse(err)
+	}
+	targetUID := rule.Record.TargetDatasourceUID
+	if targetUID == "" {
+		targetUID = srv.cfg.RecordingRules.DefaultDatasourceUID
+	}
+	if targetUID == "" {
+		return ErrResp(http.StatusBadRequest, nil, "The rule has no target data source and no default target data source is configured")
+	}
+	if err := srv.authz.AuthorizeDatasourceWrite(ctx, c.SignedInUser, targetUID); err != nil {
+		return errorToResponse(err)
+	}
+
+	result, err := srv.backtesting.Backfill(ctx, c.SignedInUser, rule, cmd.From, cmd.To, srv.recordingWriter, backtesting.BackfillOptions{
+		ChunkSize: cmd.ChunkSize,
+		MaxChunks: cmd.MaxChunks,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			status = http.StatusBadRequest
+		}
+		if result != nil && result.Next != nil {
+			return ErrResp(status, err, "Failed to backfill the rule, resume the backfill from %s", result.Next.Format(time.RFC3339))
+		}
+		return ErrResp(status, err, "Failed to backfill the rule")
+	}
+
+	return response.JSON(http.StatusOK, apimodels.BackfillResult{
+		From:        result.From,
+		To:          result.To,
+		Evaluations: result.Evaluations,
+		Samples:     result.Samples,
+		Next:        result.Next,
+	})
+}
+
+// applyBacktestConfig validates the backtesting configuration and applies it to the rule. It returns an error response
+// if the configuration is not valid or the user cannot query its data sources.
+func (srv TestingApiSrv) applyBacktestConfig(c *contextmodel.ReqContext, cmd apimodels.BacktestConfig, rule *ngmodels.AlertRule) response.Response {
+	if cmd.From.After(cmd.To) {
+		return ErrResp(400, nil, "From cannot be greater than To")
+	}
+
+	noDataState, err := ngmodels.NoDataStateFromString(string(cmd.NoDataState))
+
+	if err != nil {
+		return ErrResp(400, err, "")
+	}
+	forInterval := time.Duration(cmd.For)
+	if forInterval < 0 {
+		return ErrResp(400, nil, "Bad For interval")
+	}
+
+	intervalSeconds, err := apivalidation.ValidateInterval(time.Duration(cmd.Interval), srv.cfg.BaseInterval)
+	if err != nil {
+		return ErrResp(400, err, "")
+	}
+
+	queries := AlertQueriesFromApiAlertQueries(cmd.Data)
+	if err := srv.authz.AuthorizeDatasourceAccessForRule(c.Req.Context(), c.SignedInUser, &ngmodels.AlertRule{Data: queries}); err != nil {
+		return errorToResponse(err)
+	}
+
+	rule.Title = cmd.Title
+	// prefix backtesting- is to distinguish between executions of regular rule and backtesting in logs (like expression engine, evaluator, state manager etc)
+	rule.UID = "backtesting-" + util.GenerateShortUID()
+	rule.Condition = cmd.Condition
+	rule.Data = queries
+	rule.IntervalSeconds = intervalSeconds
+	rule.NoDataState = noDataState
+	rule.For = forInterval
+	rule.Annotations = cmd.Annotations
+	rule.Labels = cmd.Labels
+	return nil
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
@@ -241,9 +243,74 @@ func TestRouteTestGrafanaRuleConfig(t *testing.T) {
 
 			evaluator.AssertCalled(t, "Evaluate", mock.Anything, mock.Anything)
 		})
+
+		t.Run("should return the alerts and the explanation of the evaluation when explained", func(t *testing.T) {
+			data1 := models.RuleGen.GenerateQuery()
+
+			ac := acMock.New().WithPermissions([]ac.Permission{
+				{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(data1.DatasourceUID)},
+			})
+			ds := &fakes.FakeCacheService{DataSources: []*datasources.DataSource{
+				{UID: data1.DatasourceUID},
+			}}
+
+			var result []eval.Result
+			evaluator := &eval_mocks.ConditionEvaluatorMock{}
+			evaluator.EXPECT().Evaluate(mock.Anything, mock.Anything).Return(result, nil)
+			evalFactory := explainingEvaluatorFactory{
+				EvaluatorFactory: eval_mocks.NewEvaluatorFactory(evaluator),
+				node: expr.NodeExplanation{
+					RefID:    data1.RefID,
+					NodeType: expr.TypeDatasourceNode.String(),
+					Duration: time.Second,
+					Dropped:  []expr.DroppedSeries{{Labels: data.Labels{"host": "a"}, Reason: "test"}},
+				},
+			}
+
+			f := randFolder()
+			ruleStore := fakes2.NewRuleStore(t)
+			ruleStore.Folders[rc.OrgID] = []*folder.Folder{f}
+
+			srv := createTestingApiSrv(t, ds, ac, evalFactory, featuremgmt.WithFeatures(), ruleStore)
+
+			rule := validRule()
+			rule.GrafanaManagedAlert.Data = ApiAlertQueriesFromAlertQueries([]models.AlertQuery{data1})
+			rule.GrafanaManagedAlert.Condition = data1.RefID
+			response := srv.RouteExplainGrafanaRuleConfig(rc, definitions.PostableExtendedRuleNodeExtended{
+				Rule:           rule,
+				NamespaceUID:   f.UID,
+				NamespaceTitle: f.Title,
+			})
+
+			require.Equal(t, http.StatusOK, response.Status())
+			var body definitions.TestGrafanaRuleExplanation
+			require.NoError(t, json.Unmarshal(response.Body(), &body))
+			require.Empty(t, body.Alerts)
+			require.Equal(t, definitions.PipelineExplanation{
+				Nodes: []definitions.PipelineNodeExplanation{{
+					RefID:    data1.RefID,
+					NodeType: "Datasource",
+					Duration: "1s",
+					Dropped:  []definitions.PipelineDroppedSeries{{Labels: map[string]string{"host": "a"}, Reason: "test"}},
+				}},
+			}, body.Explanation)
+		})
 	})
 }
 
+// explainingEvaluatorFactory adds a node to the explanation of the evaluation context, if any.
+type explainingEvaluatorFactory struct {
+	eval.EvaluatorFactory
+	node expr.NodeExplanation
+}
+
+func (f explainingEvaluatorFactory) Create(ctx eval.EvaluationContext, condition models.Condition) (eval.ConditionEvaluator, error) {
+	if ctx.Explanation != nil {
+		ctx.Explanation.Nodes = append(ctx.Explanation.Nodes, &f.node)
+	}
+	return f.EvaluatorFactory.Create(ctx, condition)
+}
+
 func TestRouteEvalQueries(t *testing.T) {
 	t.Run("when fine-grained access is enabled", func(t *testing.T) {
 		rc := &contextmodel.ReqContext{
@@ -392,6 +459,147 @@ func TestRouteEvalQueries(t *testing.T) {
 	})
 }
 
+func TestBacktestCompareAlertRule(t *testing.T) {
+	rc := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &user.SignedInUser{
+			OrgID: 1,
+		},
+	}
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(1), models.RuleGen.WithNamespaceUID("folder"))
+	rule := gen.GenerateRef()
+	recordingRule := gen.With(gen.WithAllRecordingRules()).GenerateRef()
+	targetRule := gen.With(gen.WithAllRecordingRules()).GenerateRef()
+	targetRule.Record.TargetDatasourceUID = "target"
+	ruleStore := fakes2.NewRuleStore(t)
+	ruleStore.PutRule(context.Background(), rule, recordingRule, targetRule)
+
+	scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID("folder")
+	permissions := []ac.Permission{
+		{Action: dashboards.ActionFoldersRead, Scope: scope},
+		{Action: ac.ActionAlertingRuleRead, Scope: scope},
+	}
+	features := featuremgmt.WithFeatures(featuremgmt.FlagAlertingBacktesting)
+
+	t.Run("should return 404 if backtesting is not enabled", func(t *testing.T) {
+		srv := createTestingApiSrv(t, nil, acMock.New().WithPermissions(permissions), nil, featuremgmt.WithFeatures(), ruleStore)
+		response := srv.BacktestCompareAlertRule(rc, definitions.BacktestCompareConfig{RuleUID: rule.UID})
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return 404 if the rule does not exist", func(t *testing.T) {
+		srv := createTestingApiSrv(t, nil, acMock.New().WithPermissions(permissions), nil, features, ruleStore)
+		response := srv.BacktestCompareAlertRule(rc, definitions.BacktestCompareConfig{RuleUID: "unknown"})
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return Forbidden if user cannot read the rule", func(t *testing.T) {
+		srv := createTestingApiSrv(t, nil, acMock.New(), nil, features, ruleStore)
+		response := srv.BacktestCompareAlertRule(rc, definitions.BacktestCompareConfig{RuleUID: rule.UID})
+		require.Equal(t, http.StatusForbidden, response.Status())
+	})
+
+	t.Run("should return 404 if the baseline version does not exist", func(t *testing.T) {
+		srv := createTestingApiSrv(t, nil, acMock.New().WithPermissions(permissions), nil, features, ruleStore)
+		response := srv.BacktestCompareAlertRule(rc, definitions.BacktestCompareConfig{RuleUID: rule.UID, BaselineVersion: rule.Version + 1})
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return 400 if the rule is a recording rule", func(t *testing.T) {
+		srv := createTestingApiSrv(t, nil, acMock.New().WithPermissions(permissions), nil, features, ruleStore)
+		response := srv.BacktestCompareAlertRule(rc, definitions.BacktestCompareConfig{RuleUID: recordingRule.UID})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+}
+
+func TestBackfillRecordingRule(t *testing.T) {
+	rc := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &user.SignedInUser{
+			OrgID: 1,
+		},
+	}
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(1), models.RuleGen.WithNamespaceUID("folder"))
+	rule := gen.GenerateRef()
+	recordingRule := gen.With(gen.WithAllRecordingRules()).GenerateRef()
+	targetRule := gen.With(gen.WithAllRecordingRules()).GenerateRef()
+	targetRule.Record.TargetDatasourceUID = "target"
+	ruleStore := fakes2.NewRuleStore(t)
+	ruleStore.PutRule(context.Background(), rule, recordingRule, targetRule)
+
+	scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID("folder")
+	permissions := []ac.Permission{
+		{Action: dashboards.ActionFoldersRead, Scope: scope},
+		{Action: ac.ActionAlertingRuleRead, Scope: scope},
+	}
+	to := time.Now().Add(-time.Hour)
+	from := to.Add(-time.Hour)
+	createSrv := func(ac *acMock.Mock) *TestingApiSrv {
+		srv := createTestingApiSrv(t, nil, ac, nil, featuremgmt.WithFeatures(), ruleStore)
+		srv.cfg.RecordingRules.Enabled = true
+		return srv
+	}
+
+	t.Run("should return 400 if recording rules are not enabled", func(t *testing.T) {
+		srv := createTestingApiSrv(t, nil, acMock.New().WithPermissions(permissions), nil, featuremgmt.WithFeatures(), ruleStore)
+		response := srv.BackfillRecordingRule(rc, definitions.BackfillConfig{RuleUID: recordingRule.UID, From: from, To: to})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return 400 if the time range is not valid", func(t *testing.T) {
+		srv := createSrv(acMock.New().WithPermissions(permissions))
+		response := srv.BackfillRecordingRule(rc, definitions.BackfillConfig{RuleUID: recordingRule.UID, From: to, To: from})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return 404 if the rule does not exist", func(t *testing.T) {
+		srv := createSrv(acMock.New().WithPermissions(permissions))
+		response := srv.BackfillRecordingRule(rc, definitions.BackfillConfig{RuleUID: "unknown", From: from, To: to})
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return Forbidden if user cannot read the rule", func(t *testing.T) {
+		srv := createSrv(acMock.New())
+		response := srv.BackfillRecordingRule(rc, definitions.BackfillConfig{RuleUID: recordingRule.UID, From: from, To: to})
+		require.Equal(t, http.StatusForbidden, response.Status())
+	})
+
+	t.Run("should return 400 if the rule is not a recording rule", func(t *testing.T) {
+		srv := createSrv(acMock.New().WithPermissions(permissions))
+		response := srv.BackfillRecordingRule(rc, definitions.BackfillConfig{RuleUID: rule.UID, From: from, To: to})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return Forbidden if user cannot query the data sources of the rule", func(t *testing.T) {
+		srv := createSrv(acMock.New().WithPermissions(permissions))
+		response := srv.BackfillRecordingRule(rc, definitions.BackfillConfig{RuleUID: recordingRule.UID, From: from, To: to})
+		require.Equal(t, http.StatusForbidden, response.Status())
+	})
+
+	queryPermissions := append([]ac.Permission{{Action: datasources.ActionQuery, Scope: datasources.ScopeAll}}, permissions...)
+
+	t.Run("should return 400 if the rule has no target data source", func(t *testing.T) {
+		srv := createSrv(acMock.New().WithPermissions(queryPermissions))
+		response := srv.BackfillRecordingRule(rc, definitions.BackfillConfig{RuleUID: recordingRule.UID, From: from, To: to})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return Forbidden if user cannot write to the target data source", func(t *testing.T) {
+		srv := createSrv(acMock.New().WithPermissions(queryPermissions))
+		response := srv.BackfillRecordingRule(rc, definitions.BackfillConfig{RuleUID: targetRule.UID, From: from, To: to})
+		require.Equal(t, http.StatusForbidden, response.Status())
+
+		srv = createSrv(acMock.New().WithPermissions(queryPermissions))
+		srv.cfg.RecordingRules.DefaultDatasourceUID = "target"
+		response = srv.BackfillRecordingRule(rc, definitions.BackfillConfig{RuleUID: recordingRule.UID, From: from, To: to})
+		require.Equal(t, http.StatusForbidden, response.Status())
+	})
+}
+
 func createTestingApiSrv(t *testing.T, ds *fakes.FakeCacheService, ac *acMock.Mock, evaluator eval.EvaluatorFactory, featureManager featuremgmt.FeatureToggles, ruleStore RuleStore) *TestingApiSrv {
 	if ac == nil {
 		ac = acMock.New()
@@ -405,5 +613,6 @@ func createTestingApiSrv(t *testing.T, ds *fakes.FakeCacheService, ac *acMock.Mo
 		tracer:          tracing.InitializeTracerForTest(),
 		featureManager:  featureManager,
 		folderService:   ruleStore,
+		ruleStore:       ruleStore,
 	}
 }
@@ -77,13 +77,20 @@ func (api *API) authorize(method, path string) web.Handler {
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 
 	// Grafana Rules Testing Paths
-	case http.MethodPost + "/api/v1/rule/test/grafana":
+	case http.MethodPost + "/api/v1/rule/test/grafana",
+		http.MethodPost + "/api/v1/rule/test/grafana/explain":
 		// additional authorization is done in the request handler
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 	// Grafana Rules Testing Paths
 	case http.MethodPost + "/api/v1/rule/backtest":
 		// additional authorization is done in the request handler
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/v1/rule/backtest/compare":
+		// additional authorization is done in the request handler
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/v1/rule/backfill":
+		// additional authorization is done in the request handler
+		eval = ac.EvalPermission(ac.ActionAlertingRuleUpdate)
 	case http.MethodPost + "/api/v1/eval":
 		// additional authorization is done in the request handler
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
@@ -253,6 +260,11 @@ func (api *API) authorize(method, path string) web.Handler {
 			ac.EvalPermission(ac.ActionAlertingNotificationsWrite),
 			ac.EvalPermission(ac.ActionAlertingNotificationsTemplatesRead),
 		)
+	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/routing/simulate":
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingNotificationsRead),
+			ac.EvalPermission(ac.ActionAlertingRoutesRead),
+		)
 
 	// External Alertmanager Paths
 	case http.MethodDelete + "/api/alertmanager/{DatasourceUID}/config/api/v1/alerts":
@@ -341,12 +353,24 @@ func (api *API) authorize(method, path string) web.Handler {
 			),
 		)
 
+	case http.MethodGet + "/api/v1/provisioning/alert-rule-templates",
+		http.MethodGet + "/api/v1/provisioning/alert-rule-templates/{UID}",
+		http.MethodGet + "/api/v1/provisioning/alert-rule-templates/{UID}/instances",
+		http.MethodGet + "/api/v1/provisioning/alert-rule-templates/{UID}/instances/{InstanceUID}":
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingProvisioningRead),
+			ac.EvalPermission(ac.ActionAlertingRulesProvisioningRead),
+			ac.EvalPermission(ac.ActionAlertingProvisioningReadSecrets),
+		)
+
 	case http.MethodGet + "/api/v1/provisioning/policies",
 		http.MethodGet + "/api/v1/provisioning/contact-points",
 		http.MethodGet + "/api/v1/provisioning/templates",
 		http.MethodGet + "/api/v1/provisioning/templates/{name}",
 		http.MethodGet + "/api/v1/provisioning/mute-timings",
-		http.MethodGet + "/api/v1/provisioning/mute-timings/{name}":
+		http.MethodGet + "/api/v1/provisioning/mute-timings/{name}",
+		http.MethodGet + "/api/v1/provisioning/recurring-silences",
+		http.MethodGet + "/api/v1/provisioning/recurring-silences/{UID}":
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingProvisioningRead),
 			ac.EvalPermission(ac.ActionAlertingNotificationsProvisioningRead), // organization scope
@@ -410,6 +434,28 @@ func (api *API) authorize(method, path string) web.Handler {
 				),
 			),
 		)
+	case http.MethodPost + "/api/v1/provisioning/alert-rule-templates",
+		http.MethodPut + "/api/v1/provisioning/alert-rule-templates/{UID}",
+		http.MethodDelete + "/api/v1/provisioning/alert-rule-templates/{UID}":
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingProvisioningWrite),
+			ac.EvalPermission(ac.ActionAlertingRulesProvisioningWrite),
+		)
+	case http.MethodPost + "/api/v1/provisioning/alert-rule-templates/{UID}/instances",
+		http.MethodPut + "/api/v1/provisioning/alert-rule-templates/{UID}/instances/{InstanceUID}",
+		http.MethodDelete + "/api/v1/provisioning/alert-rule-templates/{UID}/instances/{InstanceUID}":
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingProvisioningWrite),
+			ac.EvalPermission(ac.ActionAlertingRulesProvisioningWrite),
+			ac.EvalAll(
+				ac.EvalPermission(ac.ActionAlertingProvisioningSetStatus),
+				ac.EvalAny( // the exact permissions are checked by the service for the changes to the rule of the instance
+					ac.EvalPermission(ac.ActionAlertingRuleUpdate),
+					ac.EvalPermission(ac.ActionAlertingRuleCreate),
+					ac.EvalPermission(ac.ActionAlertingRuleDelete),
+				),
+			),
+		)
 
 	case http.MethodPut + "/api/v1/provisioning/policies",
 		http.MethodDelete + "/api/v1/provisioning/policies",
@@ -420,7 +466,10 @@ func (api *API) authorize(method, path string) web.Handler {
 		http.MethodDelete + "/api/v1/provisioning/templates/{name}",
 		http.MethodPost + "/api/v1/provisioning/mute-timings",
 		http.MethodPut + "/api/v1/provisioning/mute-timings/{name}",
-		http.MethodDelete + "/api/v1/provisioning/mute-timings/{name}":
+		http.MethodDelete + "/api/v1/provisioning/mute-timings/{name}",
+		http.MethodPost + "/api/v1/provisioning/recurring-silences",
+		http.MethodPut + "/api/v1/provisioning/recurring-silences/{UID}",
+		http.MethodDelete + "/api/v1/provisioning/recurring-silences/{UID}":
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingProvisioningWrite),              // organization scope,
 			ac.EvalPermission(ac.ActionAlertingNotificationsProvisioningWrite), // organization scope
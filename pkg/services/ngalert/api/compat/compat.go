@@ -7,6 +7,7 @@ import (
 
 	jsoniter "github.com/json-iterator/go"
 	amConfig "github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
@@ -36,6 +37,7 @@ func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (mode
 		NotificationSettings:        NotificationSettingsFromAlertRuleNotificationSettings(a.NotificationSettings),
 		Record:                      ModelRecordFromApiRecord(a.Record),
 		MissingSeriesEvalsToResolve: a.MissingSeriesEvalsToResolve,
+		DependsOn:                   a.DependsOn,
 	}
 
 	if rule.Type() == models.RuleTypeRecording {
@@ -68,6 +70,7 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		NotificationSettings:        AlertRuleNotificationSettingsFromNotificationSettings(rule.NotificationSettings),
 		Record:                      ApiRecordFromModelRecord(rule.Record),
 		MissingSeriesEvalsToResolve: rule.MissingSeriesEvalsToResolve,
+		DependsOn:                   rule.DependsOn,
 	}
 }
 
@@ -220,6 +223,7 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: AlertRuleNotificationSettingsExportFromNotificationSettings(rule.NotificationSettings),
 		Record:               AlertRuleRecordExportFromRecord(rule.Record),
+		DependsOn:            rule.DependsOn,
 	}
 	if rule.For.Seconds() > 0 {
 		result.ForString = util.Pointer(model.Duration(rule.For).String())
@@ -422,6 +426,161 @@ func MuteTimeIntervalExportFromMuteTiming(orgID int64, m definitions.MuteTimeInt
 	}
 }
 
+// RecurringSilenceFromApiRecurringSilence converts definitions.RecurringSilence to models.RecurringSilence
+func RecurringSilenceFromApiRecurringSilence(s definitions.RecurringSilence) models.RecurringSilence {
+	return models.RecurringSilence{
+		UID:           s.UID,
+		Name:          s.Name,
+		Matchers:      labels.Matchers(s.Matchers),
+		Cron:          s.Cron,
+		Duration:      time.Duration(s.Duration),
+		TimeIntervals: s.TimeIntervals,
+		Comment:       s.Comment,
+		CreatedBy:     s.CreatedBy,
+		Version:       s.Version,
+		Provenance:    models.Provenance(s.Provenance),
+	}
+}
+
+// ApiRecurringSilenceFromRecurringSilence converts models.RecurringSilence to definitions.RecurringSilence
+func ApiRecurringSilenceFromRecurringSilence(s models.RecurringSilence) definitions.RecurringSilence {
+	return definitions.RecurringSilence{
+		UID:           s.UID,
+		Name:          s.Name,
+		Matchers:      definitions.ObjectMatchers(s.Matchers),
+		Cron:          s.Cron,
+		Duration:      model.Duration(s.Duration),
+		TimeIntervals: s.TimeIntervals,
+		Comment:       s.Comment,
+		CreatedBy:     s.CreatedBy,
+		Version:       s.Version,
+		Provenance:    definitions.Provenance(s.Provenance),
+	}
+}
+
+// ApiRecurringSilencesFromRecurringSilences converts a slice of models.RecurringSilence to definitions.RecurringSilences
+func ApiRecurringSilencesFromRecurringSilences(silences []models.RecurringSilence) definitions.RecurringSilences {
+	result := make(definitions.RecurringSilences, 0, len(silences))
+	for _, s := range silences {
+		result = append(result, ApiRecurringSilenceFromRecurringSilence(s))
+	}
+	return result
+}
+
+// AlertRuleTemplateFromApiAlertRuleTemplate converts definitions.AlertRuleTemplate to models.AlertRuleTemplate
+func AlertRuleTemplateFromApiAlertRuleTemplate(t definitions.AlertRuleTemplate) models.AlertRuleTemplate {
+	result := models.AlertRuleTemplate{
+		UID:  t.UID,
+		Name: t.Name,
+		Rule: models.AlertRuleTemplateRule{
+			Title:                       t.Rule.Title,
+			Condition:                   t.Rule.Condition,
+			Data:                        AlertQueriesFromApiAlertQueries(t.Rule.Data),
+			NoDataState:                 models.NoDataState(t.Rule.NoDataState),
+			ExecErrState:                models.ExecutionErrorState(t.Rule.ExecErrState),
+			For:                         time.Duration(t.Rule.For),
+			KeepFiringFor:               time.Duration(t.Rule.KeepFiringFor),
+			Annotations:                 t.Rule.Annotations,
+			Labels:                      t.Rule.Labels,
+			IsPaused:                    t.Rule.IsPaused,
+			NotificationSettings:        NotificationSettingsFromAlertRuleNotificationSettings(t.Rule.NotificationSettings),
+			Record:                      ModelRecordFromApiRecord(t.Rule.Record),
+			MissingSeriesEvalsToResolve: t.Rule.MissingSeriesEvalsToResolve,
+		},
+		Version:    t.Version,
+		Provenance: models.Provenance(t.Provenance),
+	}
+	for _, p := range t.Parameters {
+		result.Parameters = append(result.Parameters, models.AlertRuleTemplateParameter{
+			Name:        p.Name,
+			Type:        models.AlertRuleTemplateParameterType(p.Type),
+			Description: p.Description,
+		})
+	}
+	return result
+}
+
+// ApiAlertRuleTemplateFromAlertRuleTemplate converts models.AlertRuleTemplate to definitions.AlertRuleTemplate
+func ApiAlertRuleTemplateFromAlertRuleTemplate(t models.AlertRuleTemplate) definitions.AlertRuleTemplate {
+	result := definitions.AlertRuleTemplate{
+		UID:  t.UID,
+		Name: t.Name,
+		Rule: definitions.AlertRuleTemplateRule{
+			Title:                       t.Rule.Title,
+			Condition:                   t.Rule.Condition,
+			Data:                        ApiAlertQueriesFromAlertQueries(t.Rule.Data),
+			NoDataState:                 definitions.NoDataState(t.Rule.NoDataState),
+			ExecErrState:                definitions.ExecutionErrorState(t.Rule.ExecErrState),
+			For:                         model.Duration(t.Rule.For),
+			KeepFiringFor:               model.Duration(t.Rule.KeepFiringFor),
+			Annotations:                 t.Rule.Annotations,
+			Labels:                      t.Rule.Labels,
+			IsPaused:                    t.Rule.IsPaused,
+			NotificationSettings:        AlertRuleNotificationSettingsFromNotificationSettings(t.Rule.NotificationSettings),
+			Record:                      ApiRecordFromModelRecord(t.Rule.Record),
+			MissingSeriesEvalsToResolve: t.Rule.MissingSeriesEvalsToResolve,
+		},
+		Version:    t.Version,
+		Updated:    t.Updated,
+		Provenance: definitions.Provenance(t.Provenance),
+	}
+	for _, p := range t.Parameters {
+		result.Parameters = append(result.Parameters, definitions.AlertRuleTemplateParameter{
+			Name:        p.Name,
+			Type:        string(p.Type),
+			Description: p.Description,
+		})
+	}
+	return result
+}
+
+// ApiAlertRuleTemplatesFromAlertRuleTemplates converts a slice of models.AlertRuleTemplate to definitions.AlertRuleTemplates
+func ApiAlertRuleTemplatesFromAlertRuleTemplates(templates []models.AlertRuleTemplate) definitions.AlertRuleTemplates {
+	result := make(definitions.AlertRuleTemplates, 0, len(templates))
+	for _, t := range templates {
+		result = append(result, ApiAlertRuleTemplateFromAlertRuleTemplate(t))
+	}
+	return result
+}
+
+// AlertRuleTemplateInstanceFromApiAlertRuleTemplateInstance converts definitions.AlertRuleTemplateInstance to models.AlertRuleTemplateInstance
+func AlertRuleTemplateInstanceFromApiAlertRuleTemplateInstance(i definitions.AlertRuleTemplateInstance) models.AlertRuleTemplateInstance {
+	return models.AlertRuleTemplateInstance{
+		UID:             i.UID,
+		TemplateUID:     i.TemplateUID,
+		NamespaceUID:    i.FolderUID,
+		RuleGroup:       i.RuleGroup,
+		IntervalSeconds: i.Interval,
+		Values:          i.Values,
+		Version:         i.Version,
+		Provenance:      models.Provenance(i.Provenance),
+	}
+}
+
+// ApiAlertRuleTemplateInstanceFromAlertRuleTemplateInstance converts models.AlertRuleTemplateInstance to definitions.AlertRuleTemplateInstance
+func ApiAlertRuleTemplateInstanceFromAlertRuleTemplateInstance(i models.AlertRuleTemplateInstance) definitions.AlertRuleTemplateInstance {
+	return definitions.AlertRuleTemplateInstance{
+		UID:         i.UID,
+		TemplateUID: i.TemplateUID,
+		FolderUID:   i.NamespaceUID,
+		RuleGroup:   i.RuleGroup,
+		Interval:    i.IntervalSeconds,
+		Values:      i.Values,
+		Version:     i.Version,
+		Updated:     i.Updated,
+		Provenance:  definitions.Provenance(i.Provenance),
+	}
+}
+
+// ApiAlertRuleTemplateInstancesFromAlertRuleTemplateInstances converts a slice of models.AlertRuleTemplateInstance to definitions.AlertRuleTemplateInstances
+func ApiAlertRuleTemplateInstancesFromAlertRuleTemplateInstances(instances []models.AlertRuleTemplateInstance) definitions.AlertRuleTemplateInstances {
+	result := make(definitions.AlertRuleTemplateInstances, 0, len(instances))
+	for _, i := range instances {
+		result = append(result, ApiAlertRuleTemplateInstanceFromAlertRuleTemplateInstance(i))
+	}
+	return result
+}
+
 // Converts definitions.MuteTimeIntervalExport to definitions.MuteTimeIntervalExportHcl using JSON marshalling. Returns error if structure could not be marshalled\unmarshalled
 func MuteTimingIntervalToMuteTimeIntervalHclExport(m definitions.MuteTimeIntervalExport) (definitions.MuteTimeIntervalExportHcl, error) {
 	result := definitions.MuteTimeIntervalExportHcl{}
@@ -186,3 +186,7 @@ func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaReceivers(ctx *contex
 func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaTemplates(ctx *contextmodel.ReqContext, conf apimodels.TestTemplatesConfigBodyParams) response.Response {
 	return f.GrafanaSvc.RoutePostTestTemplates(ctx, conf)
 }
+
+func (f *AlertmanagerApiHandler) handleRoutePostGrafanaRoutingSimulation(ctx *contextmodel.ReqContext, conf apimodels.RoutingSimulationBody) response.Response {
+	return f.GrafanaSvc.RoutePostRoutingSimulation(ctx, conf)
+}
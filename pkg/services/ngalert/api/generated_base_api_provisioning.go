@@ -22,8 +22,11 @@ import (
 type ProvisioningApi interface {
 	RouteDeleteAlertRule(*contextmodel.ReqContext) response.Response
 	RouteDeleteAlertRuleGroup(*contextmodel.ReqContext) response.Response
+	RouteDeleteAlertRuleTemplate(*contextmodel.ReqContext) response.Response
+	RouteDeleteAlertRuleTemplateInstance(*contextmodel.ReqContext) response.Response
 	RouteDeleteContactpoints(*contextmodel.ReqContext) response.Response
 	RouteDeleteMuteTiming(*contextmodel.ReqContext) response.Response
+	RouteDeleteRecurringSilence(*contextmodel.ReqContext) response.Response
 	RouteDeleteTemplate(*contextmodel.ReqContext) response.Response
 	RouteExportMuteTiming(*contextmodel.ReqContext) response.Response
 	RouteExportMuteTimings(*contextmodel.ReqContext) response.Response
@@ -31,6 +34,10 @@ type ProvisioningApi interface {
 	RouteGetAlertRuleExport(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRuleGroup(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRuleGroupExport(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleTemplate(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleTemplateInstance(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleTemplateInstances(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleTemplates(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRules(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRulesExport(*contextmodel.ReqContext) response.Response
 	RouteGetContactpoints(*contextmodel.ReqContext) response.Response
@@ -39,16 +46,24 @@ type ProvisioningApi interface {
 	RouteGetMuteTimings(*contextmodel.ReqContext) response.Response
 	RouteGetPolicyTree(*contextmodel.ReqContext) response.Response
 	RouteGetPolicyTreeExport(*contextmodel.ReqContext) response.Response
+	RouteGetRecurringSilence(*contextmodel.ReqContext) response.Response
+	RouteGetRecurringSilences(*contextmodel.ReqContext) response.Response
 	RouteGetTemplate(*contextmodel.ReqContext) response.Response
 	RouteGetTemplates(*contextmodel.ReqContext) response.Response
 	RoutePostAlertRule(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleTemplate(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleTemplateInstance(*contextmodel.ReqContext) response.Response
 	RoutePostContactpoints(*contextmodel.ReqContext) response.Response
 	RoutePostMuteTiming(*contextmodel.ReqContext) response.Response
+	RoutePostRecurringSilence(*contextmodel.ReqContext) response.Response
 	RoutePutAlertRule(*contextmodel.ReqContext) response.Response
 	RoutePutAlertRuleGroup(*contextmodel.ReqContext) response.Response
+	RoutePutAlertRuleTemplate(*contextmodel.ReqContext) response.Response
+	RoutePutAlertRuleTemplateInstance(*contextmodel.ReqContext) response.Response
 	RoutePutContactpoint(*contextmodel.ReqContext) response.Response
 	RoutePutMuteTiming(*contextmodel.ReqContext) response.Response
 	RoutePutPolicyTree(*contextmodel.ReqContext) response.Response
+	RoutePutRecurringSilence(*contextmodel.ReqContext) response.Response
 	RoutePutTemplate(*contextmodel.ReqContext) response.Response
 	RouteResetPolicyTree(*contextmodel.ReqContext) response.Response
 }
@@ -64,6 +79,17 @@ func (f *ProvisioningApiHandler) RouteDeleteAlertRuleGroup(ctx *contextmodel.Req
 	groupParam := web.Params(ctx.Req)[":Group"]
 	return f.handleRouteDeleteAlertRuleGroup(ctx, folderUIDParam, groupParam)
 }
+func (f *ProvisioningApiHandler) RouteDeleteAlertRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteDeleteAlertRuleTemplate(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteDeleteAlertRuleTemplateInstance(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	instanceUIDParam := web.Params(ctx.Req)[":InstanceUID"]
+	return f.handleRouteDeleteAlertRuleTemplateInstance(ctx, uIDParam, instanceUIDParam)
+}
 func (f *ProvisioningApiHandler) RouteDeleteContactpoints(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	uIDParam := web.Params(ctx.Req)[":UID"]
@@ -74,6 +100,11 @@ func (f *ProvisioningApiHandler) RouteDeleteMuteTiming(ctx *contextmodel.ReqCont
 	nameParam := web.Params(ctx.Req)[":name"]
 	return f.handleRouteDeleteMuteTiming(ctx, nameParam)
 }
+func (f *ProvisioningApiHandler) RouteDeleteRecurringSilence(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteDeleteRecurringSilence(ctx, uIDParam)
+}
 func (f *ProvisioningApiHandler) RouteDeleteTemplate(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -109,6 +140,25 @@ func (f *ProvisioningApiHandler) RouteGetAlertRuleGroupExport(ctx *contextmodel.
 	groupParam := web.Params(ctx.Req)[":Group"]
 	return f.handleRouteGetAlertRuleGroupExport(ctx, folderUIDParam, groupParam)
 }
+func (f *ProvisioningApiHandler) RouteGetAlertRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetAlertRuleTemplate(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertRuleTemplateInstance(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	instanceUIDParam := web.Params(ctx.Req)[":InstanceUID"]
+	return f.handleRouteGetAlertRuleTemplateInstance(ctx, uIDParam, instanceUIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertRuleTemplateInstances(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetAlertRuleTemplateInstances(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertRuleTemplates(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetAlertRuleTemplates(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetAlertRules(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetAlertRules(ctx)
 }
@@ -135,6 +185,14 @@ func (f *ProvisioningApiHandler) RouteGetPolicyTree(ctx *contextmodel.ReqContext
 func (f *ProvisioningApiHandler) RouteGetPolicyTreeExport(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetPolicyTreeExport(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetRecurringSilence(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetRecurringSilence(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetRecurringSilences(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetRecurringSilences(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetTemplate(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -151,6 +209,24 @@ func (f *ProvisioningApiHandler) RoutePostAlertRule(ctx *contextmodel.ReqContext
 	}
 	return f.handleRoutePostAlertRule(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePostAlertRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.AlertRuleTemplate{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertRuleTemplate(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertRuleTemplateInstance(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.AlertRuleTemplateInstance{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertRuleTemplateInstance(ctx, conf, uIDParam)
+}
 func (f *ProvisioningApiHandler) RoutePostContactpoints(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.EmbeddedContactPoint{}
@@ -167,6 +243,14 @@ func (f *ProvisioningApiHandler) RoutePostMuteTiming(ctx *contextmodel.ReqContex
 	}
 	return f.handleRoutePostMuteTiming(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePostRecurringSilence(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.RecurringSilence{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostRecurringSilence(ctx, conf)
+}
 func (f *ProvisioningApiHandler) RoutePutAlertRule(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	uIDParam := web.Params(ctx.Req)[":UID"]
@@ -188,6 +272,27 @@ func (f *ProvisioningApiHandler) RoutePutAlertRuleGroup(ctx *contextmodel.ReqCon
 	}
 	return f.handleRoutePutAlertRuleGroup(ctx, conf, folderUIDParam, groupParam)
 }
+func (f *ProvisioningApiHandler) RoutePutAlertRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.AlertRuleTemplate{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutAlertRuleTemplate(ctx, conf, uIDParam)
+}
+func (f *ProvisioningApiHandler) RoutePutAlertRuleTemplateInstance(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	instanceUIDParam := web.Params(ctx.Req)[":InstanceUID"]
+	// Parse Request Body
+	conf := apimodels.AlertRuleTemplateInstance{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutAlertRuleTemplateInstance(ctx, conf, uIDParam, instanceUIDParam)
+}
 func (f *ProvisioningApiHandler) RoutePutContactpoint(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	uIDParam := web.Params(ctx.Req)[":UID"]
@@ -216,6 +321,16 @@ func (f *ProvisioningApiHandler) RoutePutPolicyTree(ctx *contextmodel.ReqContext
 	}
 	return f.handleRoutePutPolicyTree(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePutRecurringSilence(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.RecurringSilence{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutRecurringSilence(ctx, conf, uIDParam)
+}
 func (f *ProvisioningApiHandler) RoutePutTemplate(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -256,6 +371,30 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/alert-rule-templates/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/alert-rule-templates/{UID}",
+				api.Hooks.Wrap(srv.RouteDeleteAlertRuleTemplate),
+				m,
+			),
+		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}/instances/{InstanceUID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/alert-rule-templates/{UID}/instances/{InstanceUID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/alert-rule-templates/{UID}/instances/{InstanceUID}",
+				api.Hooks.Wrap(srv.RouteDeleteAlertRuleTemplateInstance),
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/v1/provisioning/contact-points/{UID}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -280,6 +419,18 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/recurring-silences/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/recurring-silences/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/recurring-silences/{UID}",
+				api.Hooks.Wrap(srv.RouteDeleteRecurringSilence),
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/v1/provisioning/templates/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -364,6 +515,54 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rule-templates/{UID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rule-templates/{UID}",
+				api.Hooks.Wrap(srv.RouteGetAlertRuleTemplate),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}/instances/{InstanceUID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rule-templates/{UID}/instances/{InstanceUID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rule-templates/{UID}/instances/{InstanceUID}",
+				api.Hooks.Wrap(srv.RouteGetAlertRuleTemplateInstance),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}/instances"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rule-templates/{UID}/instances"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rule-templates/{UID}/instances",
+				api.Hooks.Wrap(srv.RouteGetAlertRuleTemplateInstances),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rule-templates"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rule-templates",
+				api.Hooks.Wrap(srv.RouteGetAlertRuleTemplates),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/alert-rules"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -460,6 +659,30 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/recurring-silences/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/recurring-silences/{UID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/recurring-silences/{UID}",
+				api.Hooks.Wrap(srv.RouteGetRecurringSilence),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/recurring-silences"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/recurring-silences"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/recurring-silences",
+				api.Hooks.Wrap(srv.RouteGetRecurringSilences),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/templates/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -496,6 +719,30 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rule-templates"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rule-templates",
+				api.Hooks.Wrap(srv.RoutePostAlertRuleTemplate),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}/instances"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rule-templates/{UID}/instances"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rule-templates/{UID}/instances",
+				api.Hooks.Wrap(srv.RoutePostAlertRuleTemplateInstance),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/provisioning/contact-points"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -520,6 +767,18 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/recurring-silences"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/recurring-silences"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/recurring-silences",
+				api.Hooks.Wrap(srv.RoutePostRecurringSilence),
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/alert-rules/{UID}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -544,6 +803,30 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/alert-rule-templates/{UID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/alert-rule-templates/{UID}",
+				api.Hooks.Wrap(srv.RoutePutAlertRuleTemplate),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}/instances/{InstanceUID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/alert-rule-templates/{UID}/instances/{InstanceUID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/alert-rule-templates/{UID}/instances/{InstanceUID}",
+				api.Hooks.Wrap(srv.RoutePutAlertRuleTemplateInstance),
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/contact-points/{UID}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -580,6 +863,18 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/recurring-silences/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/recurring-silences/{UID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/recurring-silences/{UID}",
+				api.Hooks.Wrap(srv.RoutePutRecurringSilence),
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/templates/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -20,12 +20,31 @@ import (
 )
 
 type TestingApi interface {
+	BackfillRecordingRule(*contextmodel.ReqContext) response.Response
+	BacktestCompareConfig(*contextmodel.ReqContext) response.Response
 	BacktestConfig(*contextmodel.ReqContext) response.Response
 	RouteEvalQueries(*contextmodel.ReqContext) response.Response
+	RouteExplainRuleGrafanaConfig(*contextmodel.ReqContext) response.Response
 	RouteTestRuleConfig(*contextmodel.ReqContext) response.Response
 	RouteTestRuleGrafanaConfig(*contextmodel.ReqContext) response.Response
 }
 
+func (f *TestingApiHandler) BackfillRecordingRule(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.BackfillConfig{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleBackfillRecordingRule(ctx, conf)
+}
+func (f *TestingApiHandler) BacktestCompareConfig(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.BacktestCompareConfig{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleBacktestCompareConfig(ctx, conf)
+}
 func (f *TestingApiHandler) BacktestConfig(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.BacktestConfig{}
@@ -42,6 +61,14 @@ func (f *TestingApiHandler) RouteEvalQueries(ctx *contextmodel.ReqContext) respo
 	}
 	return f.handleRouteEvalQueries(ctx, conf)
 }
+func (f *TestingApiHandler) RouteExplainRuleGrafanaConfig(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.PostableExtendedRuleNodeExtended{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRouteExplainRuleGrafanaConfig(ctx, conf)
+}
 func (f *TestingApiHandler) RouteTestRuleConfig(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	datasourceUIDParam := web.Params(ctx.Req)[":DatasourceUID"]
@@ -63,6 +90,30 @@ func (f *TestingApiHandler) RouteTestRuleGrafanaConfig(ctx *contextmodel.ReqCont
 
 func (api *API) RegisterTestingApiEndpoints(srv TestingApi, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Post(
+			toMacaronPath("/api/v1/rule/backfill"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/rule/backfill"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/backfill",
+				api.Hooks.Wrap(srv.BackfillRecordingRule),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/rule/backtest/compare"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/rule/backtest/compare"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/backtest/compare",
+				api.Hooks.Wrap(srv.BacktestCompareConfig),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/rule/backtest"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -87,6 +138,18 @@ func (api *API) RegisterTestingApiEndpoints(srv TestingApi, m *metrics.API) {
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/rule/test/grafana/explain"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/rule/test/grafana/explain"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/test/grafana/explain",
+				api.Hooks.Wrap(srv.RouteExplainRuleGrafanaConfig),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/rule/test/{DatasourceUID}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
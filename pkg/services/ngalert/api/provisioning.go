@@ -84,6 +84,66 @@ func (f *ProvisioningApiHandler) handleRouteDeleteMuteTiming(ctx *contextmodel.R
 	return f.svc.RouteDeleteMuteTiming(ctx, name)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetRecurringSilences(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetRecurringSilences(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetRecurringSilence(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteGetRecurringSilence(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostRecurringSilence(ctx *contextmodel.ReqContext, rs apimodels.RecurringSilence) response.Response {
+	return f.svc.RoutePostRecurringSilence(ctx, rs)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutRecurringSilence(ctx *contextmodel.ReqContext, rs apimodels.RecurringSilence, uid string) response.Response {
+	return f.svc.RoutePutRecurringSilence(ctx, rs, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteRecurringSilence(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteDeleteRecurringSilence(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleTemplates(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetAlertRuleTemplates(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleTemplate(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteGetAlertRuleTemplate(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleTemplate(ctx *contextmodel.ReqContext, t apimodels.AlertRuleTemplate) response.Response {
+	return f.svc.RoutePostAlertRuleTemplate(ctx, t)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutAlertRuleTemplate(ctx *contextmodel.ReqContext, t apimodels.AlertRuleTemplate, uid string) response.Response {
+	return f.svc.RoutePutAlertRuleTemplate(ctx, t, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteAlertRuleTemplate(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteDeleteAlertRuleTemplate(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleTemplateInstances(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteGetAlertRuleTemplateInstances(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleTemplateInstance(ctx *contextmodel.ReqContext, uid string, instanceUID string) response.Response {
+	return f.svc.RouteGetAlertRuleTemplateInstance(ctx, uid, instanceUID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleTemplateInstance(ctx *contextmodel.ReqContext, i apimodels.AlertRuleTemplateInstance, uid string) response.Response {
+	return f.svc.RoutePostAlertRuleTemplateInstance(ctx, i, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutAlertRuleTemplateInstance(ctx *contextmodel.ReqContext, i apimodels.AlertRuleTemplateInstance, uid string, instanceUID string) response.Response {
+	return f.svc.RoutePutAlertRuleTemplateInstance(ctx, i, uid, instanceUID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteAlertRuleTemplateInstance(ctx *contextmodel.ReqContext, uid string, instanceUID string) response.Response {
+	return f.svc.RouteDeleteAlertRuleTemplateInstance(ctx, uid, instanceUID)
+}
+
 func (f *ProvisioningApiHandler) handleRouteGetAlertRules(ctx *contextmodel.ReqContext) response.Response {
 	return f.svc.RouteGetAlertRules(ctx)
 }
@@ -187,6 +187,20 @@ import (
 //       403: PermissionDenied
 //       409: AlertManagerNotReady
 
+// swagger:route POST /alertmanager/grafana/config/api/v1/routing/simulate alertmanager RoutePostGrafanaRoutingSimulation
+//
+// Simulate the routing of an alert through the Grafana managed notification policy tree without sending notifications.
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//
+//       200: RoutingSimulationResult
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+//       409: AlertManagerNotReady
+
 // swagger:route GET /alertmanager/grafana/api/v2/silences alertmanager RouteGetGrafanaSilences
 //
 // get silences
@@ -430,6 +444,71 @@ const (
 	AlertScope  TemplateScope = ".Alert"
 )
 
+// swagger:parameters RoutePostGrafanaRoutingSimulation
+type RoutingSimulationParams struct {
+	// in:body
+	Body RoutingSimulationBody
+}
+
+type RoutingSimulationBody struct {
+	// Labels of the alert to route.
+	Labels model.LabelSet `json:"labels"`
+
+	// UID of an alert rule. If set, the labels of the rule and the labels added to its alerts, including the labels of
+	// its notification settings, are added to the labels of the alert. Labels set in the body take precedence over them.
+	RuleUID string `json:"ruleUid,omitempty"`
+
+	// Time at which the mute timings and silences are evaluated. Defaults to the current time.
+	At *time.Time `json:"at,omitempty"`
+}
+
+// swagger:model
+type RoutingSimulationResult struct {
+	// Labels of the alert that was routed.
+	Labels model.LabelSet `json:"labels"`
+
+	// Time at which the mute timings and silences were evaluated.
+	At time.Time `json:"at"`
+
+	// Routes matched by the alert, in the order they are matched.
+	Routes []SimulatedRoute `json:"routes"`
+
+	// Silences that are active at the given time and match the alert.
+	Silences GettableGrafanaSilences `json:"silences"`
+
+	// Receivers of the matched routes that are not muted at the given time. Silences are not taken into account.
+	Receivers []string `json:"receivers"`
+}
+
+type SimulatedRoute struct {
+	// Path is the list of routes from the root of the notification policy tree to the matched route.
+	Path []SimulatedRouteStep `json:"path"`
+
+	// Receiver and the settings of the notifications of the route, including the settings inherited from its parents.
+	Receiver            string         `json:"receiver"`
+	GroupBy             []string       `json:"group_by"`
+	GroupWait           model.Duration `json:"group_wait"`
+	GroupInterval       model.Duration `json:"group_interval"`
+	RepeatInterval      model.Duration `json:"repeat_interval"`
+	MuteTimeIntervals   []string       `json:"mute_time_intervals,omitempty"`
+	ActiveTimeIntervals []string       `json:"active_time_intervals,omitempty"`
+
+	// Mute time intervals of the route that are active at the given time.
+	ActiveMuteTimeIntervals []string `json:"active_mute_time_intervals,omitempty"`
+
+	// Muted is true if the route is muted by a mute time interval, or is outside all of its active time intervals.
+	Muted bool `json:"muted"`
+
+	// Autogenerated is true if the route is generated from the notification settings of alert rules.
+	Autogenerated bool `json:"autogenerated"`
+}
+
+type SimulatedRouteStep struct {
+	Receiver string   `json:"receiver"`
+	Matchers []string `json:"matchers,omitempty"`
+	Continue bool     `json:"continue"`
+}
+
 // swagger:parameters RouteCreateSilence RouteCreateGrafanaSilence
 type CreateSilenceParams struct {
 	// in:body
@@ -594,6 +594,11 @@ type PostableGrafanaRule struct {
 	// required: false
 	// example: 3
 	MissingSeriesEvalsToResolve *int `json:"missing_series_evals_to_resolve,omitempty" yaml:"missing_series_evals_to_resolve,omitempty"`
+	// UIDs of the alert rules this rule depends on. The alerts of this rule are not sent
+	// while any of these rules is firing.
+	// required: false
+	// example: ["upstream-rule-uid"]
+	DependsOn []string `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
 }
 
 // swagger:model
@@ -617,6 +622,7 @@ type GettableGrafanaRule struct {
 	Metadata                    *AlertRuleMetadata             `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 	GUID                        string                         `json:"guid" yaml:"guid"`
 	MissingSeriesEvalsToResolve *int                           `json:"missing_series_evals_to_resolve,omitempty" yaml:"missing_series_evals_to_resolve,omitempty"`
+	DependsOn                   []string                       `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
 }
 
 // UserInfo represents user-related information, including a unique identifier and a name.
@@ -18,11 +18,11 @@ type ExportQueryParams struct {
 	// default: false
 	Download bool `json:"download"`
 
-	// Format of the downloaded file. Supported yaml, json or hcl. Accept header can also be used, but the query parameter will take precedence.
+	// Format of the downloaded file. Supported yaml, json or hcl. Accept header can also be used, but the query parameter will take precedence. Alert rules can also be exported as Prometheus rule groups with prometheus.
 	// in: query
 	// required: false
 	// default: yaml
-	// enum: yaml,json,hcl
+	// enum: yaml,json,hcl,prometheus
 	Format string `json:"format"`
 }
 
@@ -0,0 +1,222 @@
+package definitions
+
+import (
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route GET /v1/provisioning/alert-rule-templates provisioning stable RouteGetAlertRuleTemplates
+//
+// Get all the alert rule templates.
+//
+//     Responses:
+//       200: AlertRuleTemplates
+
+// swagger:route GET /v1/provisioning/alert-rule-templates/{UID} provisioning stable RouteGetAlertRuleTemplate
+//
+// Get an alert rule template.
+//
+//     Responses:
+//       200: AlertRuleTemplate
+//       404: description: Not found.
+
+// swagger:route POST /v1/provisioning/alert-rule-templates provisioning stable RoutePostAlertRuleTemplate
+//
+// Create a new alert rule template.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: AlertRuleTemplate
+//       400: ValidationError
+
+// swagger:route PUT /v1/provisioning/alert-rule-templates/{UID} provisioning stable RoutePutAlertRuleTemplate
+//
+// Replace an existing alert rule template. The rules of all its instances are updated.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: AlertRuleTemplate
+//       400: ValidationError
+//       404: description: Not found.
+//       409: PublicError
+
+// swagger:route DELETE /v1/provisioning/alert-rule-templates/{UID} provisioning stable RouteDeleteAlertRuleTemplate
+//
+// Delete an alert rule template. A template cannot be deleted while it has instances.
+//
+//     Responses:
+//       204: description: The alert rule template was deleted successfully.
+//       409: PublicError
+
+// swagger:route GET /v1/provisioning/alert-rule-templates/{UID}/instances provisioning stable RouteGetAlertRuleTemplateInstances
+//
+// Get all the instances of an alert rule template.
+//
+//     Responses:
+//       200: AlertRuleTemplateInstances
+//       404: description: Not found.
+
+// swagger:route GET /v1/provisioning/alert-rule-templates/{UID}/instances/{InstanceUID} provisioning stable RouteGetAlertRuleTemplateInstance
+//
+// Get an instance of an alert rule template.
+//
+//     Responses:
+//       200: AlertRuleTemplateInstance
+//       404: description: Not found.
+
+// swagger:route POST /v1/provisioning/alert-rule-templates/{UID}/instances provisioning stable RoutePostAlertRuleTemplateInstance
+//
+// Create a new instance of an alert rule template, together with its alert rule.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: AlertRuleTemplateInstance
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route PUT /v1/provisioning/alert-rule-templates/{UID}/instances/{InstanceUID} provisioning stable RoutePutAlertRuleTemplateInstance
+//
+// Replace an existing instance of an alert rule template, and update its alert rule.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: AlertRuleTemplateInstance
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+//       409: PublicError
+
+// swagger:route DELETE /v1/provisioning/alert-rule-templates/{UID}/instances/{InstanceUID} provisioning stable RouteDeleteAlertRuleTemplateInstance
+//
+// Delete an instance of an alert rule template, together with its alert rule.
+//
+//     Responses:
+//       204: description: The alert rule template instance was deleted successfully.
+//       403: ForbiddenError
+
+// swagger:model
+type AlertRuleTemplates []AlertRuleTemplate
+
+// swagger:model
+type AlertRuleTemplateInstances []AlertRuleTemplateInstance
+
+// swagger:parameters RouteGetAlertRuleTemplate RoutePutAlertRuleTemplate RouteDeleteAlertRuleTemplate RouteGetAlertRuleTemplateInstances RouteGetAlertRuleTemplateInstance RoutePostAlertRuleTemplateInstance RoutePutAlertRuleTemplateInstance RouteDeleteAlertRuleTemplateInstance
+type AlertRuleTemplateUIDParam struct {
+	// Alert rule template UID
+	// in:path
+	UID string
+}
+
+// swagger:parameters RouteGetAlertRuleTemplateInstance RoutePutAlertRuleTemplateInstance RouteDeleteAlertRuleTemplateInstance
+type AlertRuleTemplateInstanceUIDParam struct {
+	// Alert rule template instance UID
+	// in:path
+	InstanceUID string
+}
+
+// swagger:parameters RoutePostAlertRuleTemplate RoutePutAlertRuleTemplate
+type AlertRuleTemplatePayload struct {
+	// in:body
+	Body AlertRuleTemplate
+}
+
+// swagger:parameters RoutePostAlertRuleTemplateInstance RoutePutAlertRuleTemplateInstance
+type AlertRuleTemplateInstancePayload struct {
+	// in:body
+	Body AlertRuleTemplateInstance
+}
+
+// swagger:parameters RoutePostAlertRuleTemplate RoutePutAlertRuleTemplate RouteDeleteAlertRuleTemplate RoutePostAlertRuleTemplateInstance RoutePutAlertRuleTemplateInstance RouteDeleteAlertRuleTemplateInstance
+type AlertRuleTemplateHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// AlertRuleTemplate is an alert rule with parameters. The parameters are referenced as ${name} in the title, labels,
+// annotations, data sources and query models, and the recording rule settings of the rule.
+// swagger:model
+type AlertRuleTemplate struct {
+	UID string `json:"uid,omitempty"`
+	// required: true
+	// example: High CPU usage
+	Name       string                       `json:"name"`
+	Parameters []AlertRuleTemplateParameter `json:"parameters,omitempty"`
+	// required: true
+	Rule    AlertRuleTemplateRule `json:"rule"`
+	Version int64                 `json:"version,omitempty"`
+	// readonly: true
+	Updated    time.Time  `json:"updated,omitempty"`
+	Provenance Provenance `json:"provenance,omitempty"`
+}
+
+// AlertRuleTemplateParameter is a parameter of an alert rule template.
+type AlertRuleTemplateParameter struct {
+	// required: true
+	// example: datasource
+	Name string `json:"name"`
+	// required: true
+	// enum: string,number
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
+// AlertRuleTemplateRule is the definition of the rules of an alert rule template.
+type AlertRuleTemplateRule struct {
+	// required: true
+	// example: High CPU usage on ${instance}
+	Title string `json:"title"`
+	// required: true
+	// example: A
+	Condition string `json:"condition"`
+	// required: true
+	Data []AlertQuery `json:"data"`
+	// required: true
+	NoDataState NoDataState `json:"noDataState"`
+	// required: true
+	ExecErrState ExecutionErrorState `json:"execErrState"`
+	// swagger:strfmt duration
+	For model.Duration `json:"for"`
+	// swagger:strfmt duration
+	KeepFiringFor               model.Duration                 `json:"keep_firing_for"`
+	Annotations                 map[string]string              `json:"annotations,omitempty"`
+	Labels                      map[string]string              `json:"labels,omitempty"`
+	IsPaused                    bool                           `json:"isPaused"`
+	NotificationSettings        *AlertRuleNotificationSettings `json:"notification_settings"`
+	Record                      *Record                        `json:"record"`
+	MissingSeriesEvalsToResolve *int                           `json:"missingSeriesEvalsToResolve,omitempty"`
+}
+
+// AlertRuleTemplateInstance binds values to the parameters of an alert rule template. Its alert rule has the UID of
+// the instance, and is evaluated at the interval of its group if the group exists.
+// swagger:model
+type AlertRuleTemplateInstance struct {
+	// pattern: ^[a-zA-Z0-9-_]+$
+	UID string `json:"uid,omitempty"`
+	// readonly: true
+	TemplateUID string `json:"templateUID,omitempty"`
+	// required: true
+	// example: project_x
+	FolderUID string `json:"folderUID"`
+	// required: true
+	// example: eval_group_1
+	RuleGroup string `json:"ruleGroup"`
+	// Interval of the rule group in seconds, used if the group does not exist.
+	// example: 60
+	Interval int64 `json:"interval,omitempty"`
+	// example: {"instance": "web-1"}
+	Values  map[string]string `json:"values,omitempty"`
+	Version int64             `json:"version,omitempty"`
+	// readonly: true
+	Updated    time.Time  `json:"updated,omitempty"`
+	Provenance Provenance `json:"provenance,omitempty"`
+}
@@ -175,6 +175,8 @@ type ProvisionedAlertRule struct {
 	Record *Record `json:"record"`
 	// example: 2
 	MissingSeriesEvalsToResolve *int `json:"missingSeriesEvalsToResolve,omitempty"`
+	// example: ["upstream-rule-uid"]
+	DependsOn []string `json:"dependsOn,omitempty"`
 }
 
 // swagger:route GET /v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
@@ -285,6 +287,7 @@ type AlertRuleExport struct {
 	NotificationSettings        *AlertRuleNotificationSettingsExport `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty" hcl:"notification_settings,block"`
 	Record                      *AlertRuleRecordExport               `json:"record,omitempty" yaml:"record,omitempty" hcl:"record,block"`
 	MissingSeriesEvalsToResolve *int                                 `json:"missing_series_evals_to_resolve,omitempty" yaml:"missing_series_evals_to_resolve,omitempty" hcl:"missing_series_evals_to_resolve"`
+	DependsOn                   []string                             `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
@@ -0,0 +1,96 @@
+package definitions
+
+import (
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route GET /v1/provisioning/recurring-silences provisioning stable RouteGetRecurringSilences
+//
+// Get all the recurring silences.
+//
+//     Responses:
+//       200: RecurringSilences
+
+// swagger:route GET /v1/provisioning/recurring-silences/{UID} provisioning stable RouteGetRecurringSilence
+//
+// Get a recurring silence.
+//
+//     Responses:
+//       200: RecurringSilence
+//       404: description: Not found.
+
+// swagger:route POST /v1/provisioning/recurring-silences provisioning stable RoutePostRecurringSilence
+//
+// Create a new recurring silence.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: RecurringSilence
+//       400: ValidationError
+
+// swagger:route PUT /v1/provisioning/recurring-silences/{UID} provisioning stable RoutePutRecurringSilence
+//
+// Replace an existing recurring silence.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: RecurringSilence
+//       400: ValidationError
+//       404: description: Not found.
+//       409: PublicError
+
+// swagger:route DELETE /v1/provisioning/recurring-silences/{UID} provisioning stable RouteDeleteRecurringSilence
+//
+// Delete a recurring silence. The silences that were created for its occurrences are expired.
+//
+//     Responses:
+//       204: description: The recurring silence was deleted successfully.
+
+// swagger:model
+type RecurringSilences []RecurringSilence
+
+// swagger:parameters RouteGetRecurringSilence RoutePutRecurringSilence RouteDeleteRecurringSilence
+type RecurringSilenceUIDParam struct {
+	// Recurring silence UID
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePostRecurringSilence RoutePutRecurringSilence
+type RecurringSilencePayload struct {
+	// in:body
+	Body RecurringSilence
+}
+
+// swagger:parameters RoutePostRecurringSilence RoutePutRecurringSilence RouteDeleteRecurringSilence
+type RecurringSilenceHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// RecurringSilence is a silence that repeats on a schedule. The schedule is either a cron expression for the start of
+// each occurrence together with its duration, or time intervals in the format of mute timings.
+// swagger:model
+type RecurringSilence struct {
+	UID string `json:"uid,omitempty"`
+	// required: true
+	Name string `json:"name"`
+	// required: true
+	Matchers ObjectMatchers `json:"matchers"`
+	// Cron expression for the start of each occurrence. It can be prefixed with CRON_TZ=<location>, otherwise UTC is used.
+	// example: 0 2 * * 0
+	Cron string `json:"cron,omitempty"`
+	// Duration of each occurrence. Required with cron.
+	// example: 2h
+	Duration      model.Duration              `json:"duration,omitempty"`
+	TimeIntervals []timeinterval.TimeInterval `json:"time_intervals,omitempty"`
+	Comment       string                      `json:"comment,omitempty"`
+	CreatedBy     string                      `json:"createdBy,omitempty"`
+	Version       int64                       `json:"version,omitempty"`
+	Provenance    Provenance                  `json:"provenance,omitempty"`
+}
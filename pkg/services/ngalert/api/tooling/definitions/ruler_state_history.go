@@ -7,6 +7,8 @@ import "github.com/grafana/grafana-plugin-sdk-go/data"
 // Query state history.
 //
 // Allows to query alerting state history.
+// With the prometheus backend, the history is rebuilt from the series that only record the state of the alert instances:
+// the values and the reason of the states are empty, and the times are only as precise as the step of the query.
 // In addition to defined query parameters it accepts filter by labels. The query parameter name must start with 'labels_'
 //   Example: /v1/rules/history?labels_myKey1=myValue1&labels_myKey2=myValue2
 //
@@ -50,4 +52,16 @@ type StateHistoryParams struct {
 	DashboardUID string
 	// Filter by dashboard's panel ID. Requires Dashboard UID to be specified.
 	PanelID int64
+	// The number of the most recent records to skip, to paginate the results. Only supported by the sql backend.
+	// in:query
+	// required: false
+	Offset int `json:"offset"`
+	// Filter by the state the alert instances transitioned to, for example Alerting. Only supported by the sql backend.
+	// in:query
+	// required: false
+	State []string `json:"state"`
+	// Filter by label matchers, for example severity=~"critical|warning". Only supported by the sql backend.
+	// in:query
+	// required: false
+	Matcher []string `json:"matcher"`
 }
@@ -28,6 +28,21 @@ import (
 //       400: ValidationError
 //       404: NotFound
 
+// swagger:route Post /v1/rule/test/grafana/explain testing RouteExplainRuleGrafanaConfig
+//
+// Test a rule against Grafana ruler and trace the execution of its queries and expressions
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: TestGrafanaRuleExplanationResponse
+//       400: ValidationError
+//       404: NotFound
+
 // swagger:route Post /v1/rule/test/{DatasourceUID} testing RouteTestRuleConfig
 //
 // Test a rule against external data source ruler
@@ -68,6 +83,35 @@ import (
 //     Responses:
 //       200: BacktestResult
 
+// swagger:route Post /v1/rule/backtest/compare testing BacktestCompareConfig
+//
+// Compare the backtesting of two versions of a rule
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BacktestCompareResult
+//       404: NotFound
+
+// swagger:route Post /v1/rule/backfill testing BackfillRecordingRule
+//
+// Backfill a recording rule over a past time range
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BackfillResult
+//       400: ValidationError
+//       404: NotFound
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -94,7 +138,13 @@ type TestGrafanaRuleResponse struct {
 	Body []amv2.PostableAlert
 }
 
-// swagger:parameters RouteTestRuleGrafanaConfig
+// swagger:response TestGrafanaRuleExplanationResponse
+type TestGrafanaRuleExplanationResponse struct {
+	// in:body
+	Body TestGrafanaRuleExplanation
+}
+
+// swagger:parameters RouteTestRuleGrafanaConfig RouteExplainRuleGrafanaConfig
 type TestGrafanaRuleRequest struct {
 	// in:body
 	Body PostableExtendedRuleNodeExtended
@@ -112,6 +162,59 @@ type PostableExtendedRuleNodeExtended struct {
 	RuleGroup string `json:"ruleGroup"`
 }
 
+// swagger:model
+type TestGrafanaRuleExplanation struct {
+	// Alerts are the alerts that the rule would produce, as returned by RouteTestRuleGrafanaConfig.
+	Alerts []*amv2.PostableAlert `json:"alerts"`
+	// Explanation is the trace of the execution of the queries and expressions of the rule.
+	Explanation PipelineExplanation `json:"explanation"`
+}
+
+// swagger:model
+type PipelineExplanation struct {
+	// Nodes are the queries and expressions in the order they were executed.
+	Nodes []PipelineNodeExplanation `json:"nodes"`
+}
+
+// swagger:model
+type PipelineNodeExplanation struct {
+	// example: A
+	RefID string `json:"refId"`
+	// example: Expression
+	NodeType string `json:"nodeType"`
+	// example: math
+	Command string   `json:"command,omitempty"`
+	Inputs  []string `json:"inputs,omitempty"`
+	// example: 1.5ms
+	Duration     string `json:"duration"`
+	InputSeries  int    `json:"inputSeries"`
+	OutputSeries int    `json:"outputSeries"`
+	// Dropped are the input values that did not make it to the output, such as the values that did not match in a union.
+	Dropped []PipelineDroppedSeries `json:"dropped,omitempty"`
+	// DroppedCount is the total number of dropped values, which can be more than the number of listed values.
+	DroppedCount int `json:"droppedCount,omitempty"`
+	// Samples are the first few output values of the node.
+	Samples []PipelineValueSample `json:"samples,omitempty"`
+	Error   string                `json:"error,omitempty"`
+}
+
+// swagger:model
+type PipelineDroppedSeries struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	Reason string            `json:"reason"`
+}
+
+// swagger:model
+type PipelineValueSample struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	// example: numberSet
+	Type string `json:"type"`
+	// Length is the number of points of a series.
+	Length int `json:"length,omitempty"`
+	// Values are the value of a number, or the last values of a series, formatted as strings.
+	Values []string `json:"values,omitempty"`
+}
+
 func (n *PostableExtendedRuleNodeExtended) UnmarshalJSON(b []byte) error {
 	type plain PostableExtendedRuleNodeExtended
 	if err := json.Unmarshal(b, (*plain)(n)); err != nil {
@@ -131,6 +234,9 @@ type EvalQueriesPayload struct {
 	Condition string       `json:"condition"`
 	Data      []AlertQuery `json:"data"`
 	Now       time.Time    `json:"now"`
+	// DryRun, if true, explains the SQL expressions instead of running them. The response of each SQL expression
+	// is an empty frame with the plan and the estimated output size in its metadata, and the expected problems as notices.
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 func (p *TestRulePayload) UnmarshalJSON(b []byte) error {
@@ -234,3 +340,75 @@ type BacktestConfig struct {
 
 // swagger:model
 type BacktestResult data.Frame
+
+// swagger:parameters BacktestCompareConfig
+type BacktestCompareConfigRequest struct {
+	// in:body
+	Body BacktestCompareConfig
+}
+
+// BacktestCompareConfig describes a proposed version of an existing alert rule to backtest against one of its versions.
+// swagger:model
+type BacktestCompareConfig struct {
+	// The proposed version of the rule. The fields that cannot be set here are copied from the baseline version.
+	BacktestConfig
+
+	// UID of the alert rule to use as the baseline.
+	// required: true
+	RuleUID string `json:"rule_uid"`
+	// Version of the alert rule to use as the baseline. The current version is used if it is not set.
+	BaselineVersion int64 `json:"baseline_version,omitempty"`
+}
+
+// swagger:model
+type BacktestCompareResult struct {
+	// The result of the backtesting of the baseline version, in the same format as BacktestResult.
+	Baseline *data.Frame `json:"baseline"`
+	// The result of the backtesting of the proposed version, in the same format as BacktestResult.
+	Proposed *data.Frame `json:"proposed"`
+	// A row for each evaluation time and alert instance at which the versions have a different state.
+	Differences *data.Frame `json:"differences"`
+	// The number of notifications the baseline version would have sent.
+	BaselineNotifications int `json:"baseline_notifications"`
+	// The number of notifications the proposed version would have sent.
+	ProposedNotifications int `json:"proposed_notifications"`
+	// The first evaluation time at which the versions have a different state.
+	FirstDifference *time.Time `json:"first_difference,omitempty"`
+}
+
+// swagger:parameters BackfillRecordingRule
+type BackfillRecordingRuleRequest struct {
+	// in:body
+	Body BackfillConfig
+}
+
+// BackfillConfig describes the time range over which a recording rule is backfilled.
+// swagger:model
+type BackfillConfig struct {
+	// UID of the recording rule to backfill.
+	// required: true
+	RuleUID string `json:"rule_uid"`
+	// required: true
+	From time.Time `json:"from"`
+	// required: true
+	To time.Time `json:"to"`
+	// The number of evaluations whose samples are written together.
+	ChunkSize int `json:"chunk_size,omitempty"`
+	// The maximum number of chunks backfilled by the request. If the range is not completely backfilled, the response
+	// has the time from which the backfill must be resumed. All chunks are backfilled if it is not set.
+	MaxChunks int `json:"max_chunks,omitempty"`
+}
+
+// swagger:model
+type BackfillResult struct {
+	// The time of the first evaluation of the backfill.
+	From time.Time `json:"from"`
+	// The time of the last evaluation of the backfill.
+	To time.Time `json:"to"`
+	// The number of evaluations whose samples were written.
+	Evaluations int `json:"evaluations"`
+	// The number of evaluations that returned data.
+	Samples int `json:"samples"`
+	// The time from which the backfill must be resumed. It is not set if the whole range was backfilled.
+	Next *time.Time `json:"next,omitempty"`
+}
@@ -73,6 +73,7 @@ func ValidateRuleNode(
 		NamespaceUID:                namespaceUID,
 		RuleGroup:                   groupName,
 		MissingSeriesEvalsToResolve: ruleNode.GrafanaManagedAlert.MissingSeriesEvalsToResolve,
+		DependsOn:                   ruleNode.GrafanaManagedAlert.DependsOn,
 	}
 
 	if isRecordingRule {
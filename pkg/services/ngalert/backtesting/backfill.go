@@ -0,0 +1,146 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
+)
+
+// DefaultBackfillChunkSize is the number of evaluations that are evaluated and written together when no chunk size is specified.
+const DefaultBackfillChunkSize = 60
+
+// maxBackfillEvaluations is the maximum number of evaluations done by a single backfill. Longer ranges are backfilled
+// by resuming the backfill from BackfillResult.Next.
+var maxBackfillEvaluations = 1440
+
+// BackfillWriter writes the samples of a recording rule. It is implemented by the recording rule writers of the writer package.
+type BackfillWriter interface {
+	WriteDatasourceSamples(ctx context.Context, dsUID string, name string, samples []writer.Sample, orgID int64, extraLabels map[string]string) error
+}
+
+// BackfillOptions controls how a recording rule is backfilled.
+type BackfillOptions struct {
+	// ChunkSize is the number of evaluations that are evaluated before their samples are written.
+	ChunkSize int
+	// MaxChunks is the maximum number of chunks that are backfilled. If the range is not completely backfilled,
+	// the backfill can be resumed from BackfillResult.Next. Zero means no limit.
+	MaxChunks int
+}
+
+// BackfillResult is the progress of the backfill of a recording rule.
+type BackfillResult struct {
+	// From and To are the evaluation times of the first and the last evaluation of the backfill.
+	From time.Time
+	To   time.Time
+	// Evaluations is the number of evaluations that were done and written.
+	Evaluations int
+	// Samples is the number of evaluations that returned data and whose samples were written.
+	Samples int
+	// Next is the evaluation time from which the backfill must be resumed. It is nil if the whole range was backfilled.
+	Next *time.Time
+}
+
+// Backfill evaluates the recording rule over the time range [from, to) and writes the computed samples with the time of
+// the evaluation that computed them. The evaluations are aligned to the ticks on which the scheduler evaluates the rule,
+// so that the backfilled samples line up with the samples written by the scheduler.
+//
+// The range is processed in chunks: the samples of a chunk are written in a single request once all of its evaluations
+// succeeded.
+// At most maxBackfillEvaluations are done by a call. If the backfill fails, stops after MaxChunks or reaches that
+// limit, the returned result has the time from which it must be resumed.
+func (e *Engine) Backfill(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time, w BackfillWriter, opts BackfillOptions) (*BackfillResult, error) {
+	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
+	logger := logger.FromContext(ctx)
+
+	if rule.Type() != models.RuleTypeRecording {
+		return nil, fmt.Errorf("%w: only recording rules can be backfilled", ErrInvalidInputData)
+	}
+	if rule.IntervalSeconds <= 0 {
+		return nil, fmt.Errorf("%w: invalid evaluation interval [%ds]", ErrInvalidInputData, rule.IntervalSeconds)
+	}
+	interval := time.Duration(rule.IntervalSeconds) * time.Second
+	if e.baseInterval <= 0 || interval%e.baseInterval != 0 {
+		return nil, fmt.Errorf("%w: evaluation interval [%ds] is not a multiple of the scheduler interval [%s]", ErrInvalidInputData, rule.IntervalSeconds, e.baseInterval)
+	}
+	from = schedule.NextEvaluationTime(rule, e.baseInterval, e.jitterStrategy, from)
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: invalid interval of the backfill [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
+	}
+	evaluations := int((to.Sub(from) + interval - 1) / interval)
+	limit := min(evaluations, maxBackfillEvaluations)
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultBackfillChunkSize
+	}
+
+	evaluator, err := backtestingEvaluatorFactory(ruleCtx, e.evalFactory, user, rule.GetEvalCondition().WithSource("backfill"), &schedule.AlertingResultsFromRuleState{
+		Manager: e.createStateManager(),
+		Rule:    rule,
+	})
+	if err != nil {
+		return nil, errors.Join(ErrInvalidInputData, err)
+	}
+
+	logger.Info("Start backfilling recording rule", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluations", limit, "chunkSize", chunkSize)
+	start := time.Now()
+
+	result := &BackfillResult{From: from}
+	chunkStart := from
+	for chunk := 0; result.Evaluations < limit; chunk++ {
+		if opts.MaxChunks > 0 && chunk == opts.MaxChunks {
+			next := chunkStart
+			result.Next = &next
+			logger.Info("Backfill stopped after the maximum number of chunks", "chunks", chunk, "next", next)
+			return result, nil
+		}
+		if err := ctx.Err(); err != nil {
+			next := chunkStart
+			result.Next = &next
+			return result, err
+		}
+
+		size := min(chunkSize, limit-result.Evaluations)
+		samples := make([]writer.Sample, 0, size)
+		err := evaluator.EvalFrames(ruleCtx, chunkStart, interval, size, func(_ int, now time.Time, frames data.Frames) error {
+			if len(frames) > 0 {
+				samples = append(samples, writer.Sample{T: now, Frames: frames})
+			}
+			return nil
+		})
+		if err == nil && len(samples) > 0 {
+			if err = w.WriteDatasourceSamples(ruleCtx, rule.Record.TargetDatasourceUID, rule.Record.Metric, samples, rule.OrgID, rule.Labels); err != nil {
+				err = fmt.Errorf("failed to write the samples from %s: %w", chunkStart.Format(time.RFC3339), err)
+			}
+		}
+		if err != nil {
+			next := chunkStart
+			result.Next = &next
+			logger.Error("Backfill of recording rule failed", "error", err, "next", next)
+			return result, err
+		}
+
+		result.Evaluations += size
+		result.Samples += len(samples)
+		result.To = chunkStart.Add(time.Duration(size-1) * interval)
+		chunkStart = chunkStart.Add(time.Duration(size) * interval)
+	}
+
+	if limit < evaluations {
+		next := chunkStart
+		result.Next = &next
+		logger.Info("Backfill stopped after the maximum number of evaluations", "evaluations", limit, "next", next)
+		return result, nil
+	}
+
+	logger.Info("Recording rule backfill finished successfully", "duration", time.Since(start), "evaluations", result.Evaluations, "samples", result.Samples)
+	return result, nil
+}
@@ -0,0 +1,163 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
+)
+
+type fakeBackfillWriter struct {
+	written  []time.Time
+	requests int
+	failAt   time.Time
+}
+
+func (f *fakeBackfillWriter) WriteDatasourceSamples(_ context.Context, _ string, _ string, samples []writer.Sample, _ int64, _ map[string]string) error {
+	for _, s := range samples {
+		if s.T.Equal(f.failAt) {
+			f.failAt = time.Time{}
+			return errors.New("write failed")
+		}
+	}
+	f.requests++
+	for _, s := range samples {
+		f.written = append(f.written, s.T)
+	}
+	return nil
+}
+
+func TestEngineBackfill(t *testing.T) {
+	from := time.Unix(600, 0)
+	at := func(evaluation int) time.Time {
+		return from.Add(time.Duration(evaluation) * time.Minute)
+	}
+
+	evaluator := &fakeBacktestingEvaluator{
+		framesCallback: func(now time.Time) (data.Frames, error) {
+			// The third evaluation has no data.
+			if now.Equal(at(2)) {
+				return nil, nil
+			}
+			n := mathexp.NewNumber("A", data.Labels{"instance": "a"})
+			v := float64(now.Unix())
+			n.SetValue(&v)
+			return data.Frames{n.AsDataFrame()}, nil
+		},
+	}
+	backtestingEvaluatorFactory = func(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, r eval.AlertingResultsReader) (backtestingEvaluator, error) {
+		return evaluator, nil
+	}
+	t.Cleanup(func() {
+		backtestingEvaluatorFactory = newBacktestingEvaluator
+	})
+
+	engine := &Engine{
+		createStateManager: func() stateManager {
+			return &fakeStateManager{}
+		},
+		baseInterval: 10 * time.Second,
+	}
+	gen := models.RuleGen
+	rule := gen.With(gen.WithAllRecordingRules(), gen.WithInterval(time.Minute)).GenerateRef()
+
+	t.Run("should fail if the rule is not a recording rule", func(t *testing.T) {
+		alertRule := gen.With(gen.WithInterval(time.Minute)).GenerateRef()
+		_, err := engine.Backfill(context.Background(), nil, alertRule, from, at(5), &fakeBackfillWriter{}, BackfillOptions{})
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+
+	t.Run("should fail if the range is empty", func(t *testing.T) {
+		_, err := engine.Backfill(context.Background(), nil, rule, from.Add(time.Second), at(1), &fakeBackfillWriter{}, BackfillOptions{})
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+
+	t.Run("should write the samples at the time of the evaluations", func(t *testing.T) {
+		writer := &fakeBackfillWriter{}
+		result, err := engine.Backfill(context.Background(), nil, rule, from.Add(-30*time.Second), at(5), writer, BackfillOptions{ChunkSize: 2})
+		require.NoError(t, err)
+
+		require.Equal(t, []time.Time{at(0), at(1), at(3), at(4)}, writer.written)
+		// The samples of a chunk are written in a single request.
+		require.Equal(t, 3, writer.requests)
+		require.Equal(t, at(0), result.From)
+		require.Equal(t, at(4), result.To)
+		require.Equal(t, 5, result.Evaluations)
+		require.Equal(t, 4, result.Samples)
+		require.Nil(t, result.Next)
+	})
+
+	t.Run("should align the evaluations to the ticks of the scheduler", func(t *testing.T) {
+		jittered := &Engine{
+			createStateManager: engine.createStateManager,
+			baseInterval:       engine.baseInterval,
+			jitterStrategy:     schedule.JitterByRule,
+		}
+		writer := &fakeBackfillWriter{}
+		result, err := jittered.Backfill(context.Background(), nil, rule, from, at(5), writer, BackfillOptions{})
+		require.NoError(t, err)
+
+		expected := schedule.NextEvaluationTime(rule, engine.baseInterval, schedule.JitterByRule, from)
+		require.Equal(t, expected, result.From)
+		require.Equal(t, expected, writer.written[0])
+	})
+
+	t.Run("should fail if the interval is not a multiple of the scheduler interval", func(t *testing.T) {
+		invalid := gen.With(gen.WithAllRecordingRules(), gen.WithInterval(15*time.Second)).GenerateRef()
+		_, err := engine.Backfill(context.Background(), nil, invalid, from, at(5), &fakeBackfillWriter{}, BackfillOptions{})
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+
+	t.Run("should stop after the maximum number of evaluations", func(t *testing.T) {
+		maxBackfillEvaluations = 3
+		t.Cleanup(func() {
+			maxBackfillEvaluations = 1440
+		})
+		writer := &fakeBackfillWriter{}
+		result, err := engine.Backfill(context.Background(), nil, rule, from, at(5), writer, BackfillOptions{ChunkSize: 2})
+		require.NoError(t, err)
+		require.Equal(t, 3, result.Evaluations)
+		require.NotNil(t, result.Next)
+		require.Equal(t, at(3), *result.Next)
+		require.Equal(t, []time.Time{at(0), at(1)}, writer.written)
+	})
+
+	t.Run("should stop after the maximum number of chunks", func(t *testing.T) {
+		writer := &fakeBackfillWriter{}
+		result, err := engine.Backfill(context.Background(), nil, rule, from, at(5), writer, BackfillOptions{ChunkSize: 2, MaxChunks: 2})
+		require.NoError(t, err)
+		require.Equal(t, 4, result.Evaluations)
+		require.NotNil(t, result.Next)
+		require.Equal(t, at(4), *result.Next)
+
+		result, err = engine.Backfill(context.Background(), nil, rule, *result.Next, at(5), writer, BackfillOptions{ChunkSize: 2, MaxChunks: 2})
+		require.NoError(t, err)
+		require.Nil(t, result.Next)
+		require.Equal(t, []time.Time{at(0), at(1), at(3), at(4)}, writer.written)
+	})
+
+	t.Run("should be resumable from the chunk that failed", func(t *testing.T) {
+		writer := &fakeBackfillWriter{failAt: at(3)}
+		result, err := engine.Backfill(context.Background(), nil, rule, from, at(5), writer, BackfillOptions{ChunkSize: 2})
+		require.Error(t, err)
+		require.Equal(t, 2, result.Evaluations)
+		require.NotNil(t, result.Next)
+		require.Equal(t, at(2), *result.Next)
+
+		result, err = engine.Backfill(context.Background(), nil, rule, *result.Next, at(5), writer, BackfillOptions{ChunkSize: 2})
+		require.NoError(t, err)
+		require.Nil(t, result.Next)
+		require.Equal(t, []time.Time{at(0), at(1), at(3), at(4)}, writer.written)
+	})
+}
@@ -0,0 +1,197 @@
+package backtesting
+
+import (
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// Comparison is the result of backtesting two versions of an alert rule over the same time range.
+type Comparison struct {
+	// Baseline and Proposed are the results of the backtesting of each version, in the same format as the one returned by Engine.Test.
+	Baseline *data.Frame
+	Proposed *data.Frame
+	// Differences has a row for each evaluation time and alert instance at which the versions have a different state.
+	// The state of an alert instance that does not exist in a version is null.
+	Differences *data.Frame
+	// BaselineNotifications and ProposedNotifications are the number of notifications each version would have sent,
+	// that is the number of alert instances that started firing or got resolved.
+	BaselineNotifications int
+	ProposedNotifications int
+	// FirstDifference is the first evaluation time at which the versions have a different state. It is nil if the
+	// state timelines of the versions are the same.
+	FirstDifference *time.Time
+}
+
+// instanceState is the state of an alert instance after an evaluation.
+type instanceState struct {
+	state eval.State
+	value string
+}
+
+type timelineEvaluation struct {
+	idx    int
+	time   time.Time
+	states map[data.Fingerprint]instanceState
+}
+
+// stateTimeline collects the states of the alert instances of a rule at each evaluation of the backtesting.
+type stateTimeline struct {
+	length        int
+	evaluations   []timelineEvaluation
+	labels        map[data.Fingerprint]data.Labels
+	notifications int
+}
+
+func newStateTimeline(length int) *stateTimeline {
+	return &stateTimeline{
+		length:      length,
+		evaluations: make([]timelineEvaluation, 0, length),
+		labels:      make(map[data.Fingerprint]data.Labels),
+	}
+}
+
+func (t *stateTimeline) add(idx int, now time.Time, transitions state.StateTransitions) {
+	states := make(map[data.Fingerprint]instanceState, len(transitions))
+	for _, s := range transitions {
+		if _, ok := t.labels[s.CacheID]; !ok {
+			t.labels[s.CacheID] = s.Labels
+		}
+		value := s.State.State.String()
+		if s.StateReason != "" {
+			value += " (" + s.StateReason + ")"
+		}
+		states[s.CacheID] = instanceState{state: s.State.State, value: value}
+		if sendsNotification(s) {
+			t.notifications++
+		}
+	}
+	t.evaluations = append(t.evaluations, timelineEvaluation{idx: idx, time: now, states: states})
+}
+
+// frame returns a frame with the time of each evaluation and a field per alert instance with its state at that time.
+// The state is null if the alert instance does not exist or has no data.
+func (t *stateTimeline) frame() *data.Frame {
+	tsField := data.NewField("Time", nil, make([]time.Time, t.length))
+	for _, e := range t.evaluations {
+		tsField.Set(e.idx, e.time)
+	}
+
+	fields := make([]*data.Field, 0, len(t.labels)+1)
+	fields = append(fields, tsField)
+	for fp, labels := range t.labels {
+		field := data.NewField("", labels, make([]*string, t.length))
+		for _, e := range t.evaluations {
+			s, ok := e.states[fp]
+			if !ok || s.state == eval.NoData {
+				continue
+			}
+			value := s.value
+			field.Set(e.idx, &value)
+		}
+		fields = append(fields, field)
+	}
+	return data.NewFrame("Testing results", fields...)
+}
+
+// sendsNotification returns true if the transition makes the Alertmanager send a notification, that is when the alert
+// instance starts firing or gets resolved.
+func sendsNotification(t state.StateTransition) bool {
+	if t.State.State == eval.Normal {
+		return t.State.ShouldBeResolved(t.PreviousState)
+	}
+	return isFiring(t.State.State) && !isFiring(t.PreviousState)
+}
+
+func isFiring(s eval.State) bool {
+	return s == eval.Alerting || s == eval.Error || s == eval.NoData || s == eval.Recovering
+}
+
+// compareTimelines compares the states of the alert instances of two versions of a rule. When the versions are
+// evaluated at different times, the state of a version between two evaluations is the one of the last evaluation.
+func compareTimelines(baseline, proposed *stateTimeline) *Comparison {
+	result := &Comparison{
+		Baseline:              baseline.frame(),
+		Proposed:              proposed.frame(),
+		BaselineNotifications: baseline.notifications,
+		ProposedNotifications: proposed.notifications,
+	}
+
+	var (
+		times          []time.Time
+		labels         []string
+		baselineValues []*string
+		proposedValues []*string
+	)
+	var baselineStates, proposedStates map[data.Fingerprint]instanceState
+	i, j := 0, 0
+	for i < len(baseline.evaluations) || j < len(proposed.evaluations) {
+		var now time.Time
+		switch {
+		case j == len(proposed.evaluations) || (i < len(baseline.evaluations) && baseline.evaluations[i].time.Before(proposed.evaluations[j].time)):
+			now = baseline.evaluations[i].time
+			baselineStates = baseline.evaluations[i].states
+			i++
+		case i == len(baseline.evaluations) || proposed.evaluations[j].time.Before(baseline.evaluations[i].time):
+			now = proposed.evaluations[j].time
+			proposedStates = proposed.evaluations[j].states
+			j++
+		default:
+			now = baseline.evaluations[i].time
+			baselineStates = baseline.evaluations[i].states
+			proposedStates = proposed.evaluations[j].states
+			i++
+			j++
+		}
+
+		type difference struct {
+			labels             string
+			baseline, proposed *string
+		}
+		var differences []difference
+		for fp, s := range baselineStates {
+			p, ok := proposedStates[fp]
+			if ok && p.value == s.value {
+				continue
+			}
+			d := difference{labels: baseline.labels[fp].String(), baseline: &s.value}
+			if ok {
+				d.proposed = &p.value
+			}
+			differences = append(differences, d)
+		}
+		for fp, p := range proposedStates {
+			if _, ok := baselineStates[fp]; ok {
+				continue
+			}
+			differences = append(differences, difference{labels: proposed.labels[fp].String(), proposed: &p.value})
+		}
+		if len(differences) == 0 {
+			continue
+		}
+		if result.FirstDifference == nil {
+			result.FirstDifference = &now
+		}
+		sort.Slice(differences, func(a, b int) bool {
+			return differences[a].labels < differences[b].labels
+		})
+		for _, d := range differences {
+			times = append(times, now)
+			labels = append(labels, d.labels)
+			baselineValues = append(baselineValues, d.baseline)
+			proposedValues = append(proposedValues, d.proposed)
+		}
+	}
+
+	result.Differences = data.NewFrame("Differences",
+		data.NewField("Time", nil, times),
+		data.NewField("Labels", nil, labels),
+		data.NewField("Baseline", nil, baselineValues),
+		data.NewField("Proposed", nil, proposedValues),
+	)
+	return result
+}
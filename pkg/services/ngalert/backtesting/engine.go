@@ -30,8 +30,12 @@ var (
 
 type callbackFunc = func(evaluationIndex int, now time.Time, results eval.Results) error
 
+// framesCallbackFunc receives the frames of the condition of an evaluation. The frames are nil if the condition has no data.
+type framesCallbackFunc = func(evaluationIndex int, now time.Time, frames data.Frames) error
+
 type backtestingEvaluator interface {
 	Eval(ctx context.Context, from time.Time, interval time.Duration, evaluations int, callback callbackFunc) error
+	EvalFrames(ctx context.Context, from time.Time, interval time.Duration, evaluations int, callback framesCallbackFunc) error
 }
 
 type stateManager interface {
@@ -42,11 +46,17 @@ type stateManager interface {
 type Engine struct {
 	evalFactory        eval.EvaluatorFactory
 	createStateManager func() stateManager
+	// baseInterval and jitterStrategy are the settings of the scheduler, used to align backfilled evaluations with the
+	// evaluations of the scheduler.
+	baseInterval   time.Duration
+	jitterStrategy schedule.JitterStrategy
 }
 
-func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracing.Tracer) *Engine {
+func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracing.Tracer, baseInterval time.Duration, jitterStrategy schedule.JitterStrategy) *Engine {
 	return &Engine{
-		evalFactory: evalFactory,
+		evalFactory:    evalFactory,
+		baseInterval:   baseInterval,
+		jitterStrategy: jitterStrategy,
 		createStateManager: func() stateManager {
 			cfg := state.ManagerCfg{
 				Metrics:       nil,
@@ -64,6 +74,28 @@ func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracin
 }
 
 func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time) (*data.Frame, error) {
+	timeline, err := e.run(ctx, user, rule, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return timeline.frame(), nil
+}
+
+// Compare backtests two versions of the alert rule over the same time range, and compares their state timelines.
+func (e *Engine) Compare(ctx context.Context, user identity.Requester, baseline, proposed *models.AlertRule, from, to time.Time) (*Comparison, error) {
+	baselineTimeline, err := e.run(ctx, user, baseline, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to test the baseline version of the rule: %w", err)
+	}
+	proposedTimeline, err := e.run(ctx, user, proposed, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to test the proposed version of the rule: %w", err)
+	}
+	return compareTimelines(baselineTimeline, proposedTimeline), nil
+}
+
+// run evaluates the rule over the time range, and returns the states of its alert instances at each evaluation.
+func (e *Engine) run(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time) (*stateTimeline, error) {
 	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
 	logger := logger.FromContext(ctx)
 
@@ -89,45 +121,21 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 
 	start := time.Now()
 
-	tsField := data.NewField("Time", nil, make([]time.Time, length))
-	valueFields := make(map[data.Fingerprint]*data.Field)
-
+	timeline := newStateTimeline(length)
 	err = evaluator.Eval(ruleCtx, from, time.Duration(rule.IntervalSeconds)*time.Second, length, func(idx int, currentTime time.Time, results eval.Results) error {
 		if idx >= length {
 			logger.Info("Unexpected evaluation. Skipping", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluationTime", currentTime, "evaluationIndex", idx, "expectedEvaluations", length)
 			return nil
 		}
 		states := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, nil, nil)
-		tsField.Set(idx, currentTime)
-		for _, s := range states {
-			field, ok := valueFields[s.CacheID]
-			if !ok {
-				field = data.NewField("", s.Labels, make([]*string, length))
-				valueFields[s.CacheID] = field
-			}
-			if s.State.State != eval.NoData { // set nil if NoData
-				value := s.State.State.String()
-				if s.StateReason != "" {
-					value += " (" + s.StateReason + ")"
-				}
-				field.Set(idx, &value)
-				continue
-			}
-		}
+		timeline.add(idx, currentTime, states)
 		return nil
 	})
-	fields := make([]*data.Field, 0, len(valueFields)+1)
-	fields = append(fields, tsField)
-	for _, f := range valueFields {
-		fields = append(fields, f)
-	}
-	result := data.NewFrame("Testing results", fields...)
-
 	if err != nil {
 		return nil, err
 	}
 	logger.Info("Rule testing finished successfully", "duration", time.Since(start))
-	return result, nil
+	return timeline, nil
 }
 
 func newBacktestingEvaluator(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, reader eval.AlertingResultsReader) (backtestingEvaluator, error) {
@@ -163,7 +171,8 @@ func newBacktestingEvaluator(ctx context.Context, evalFactory eval.EvaluatorFact
 	}
 
 	return &queryEvaluator{
-		eval: evaluator,
+		eval:  evaluator,
+		refID: condition.Condition,
 	}, nil
 }
 
@@ -382,6 +382,102 @@ func TestEvaluatorTest(t *testing.T) {
 	})
 }
 
+func TestEngineCompare(t *testing.T) {
+	evaluator := &fakeBacktestingEvaluator{
+		evalCallback: func(now time.Time) (eval.Results, error) {
+			return eval.Results{}, nil
+		},
+	}
+	backtestingEvaluatorFactory = func(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, r eval.AlertingResultsReader) (backtestingEvaluator, error) {
+		return evaluator, nil
+	}
+	t.Cleanup(func() {
+		backtestingEvaluatorFactory = newBacktestingEvaluator
+	})
+
+	from := time.Unix(0, 0)
+	at := func(evaluation int) time.Time {
+		return from.Add(time.Duration(evaluation) * time.Second)
+	}
+	transition := func(labels data.Labels, previous, current eval.State) state.StateTransition {
+		return state.StateTransition{
+			State: &state.State{
+				CacheID: labels.Fingerprint(),
+				Labels:  labels,
+				State:   current,
+			},
+			PreviousState: previous,
+		}
+	}
+	a := data.Labels{"instance": "a"}
+	b := data.Labels{"instance": "b"}
+
+	// The baseline fires at the second evaluation and resolves at the fourth one.
+	baseline := &fakeStateManager{stateCallback: func(now time.Time) []state.StateTransition {
+		return map[time.Time][]state.StateTransition{
+			at(0): {transition(a, eval.Normal, eval.Normal)},
+			at(1): {transition(a, eval.Normal, eval.Alerting)},
+			at(2): {transition(a, eval.Alerting, eval.Alerting)},
+			at(3): {transition(a, eval.Alerting, eval.Normal)},
+			at(4): {transition(a, eval.Normal, eval.Normal)},
+		}[now]
+	}}
+	// The proposed version is pending first, and keeps firing until the end.
+	proposed := &fakeStateManager{stateCallback: func(now time.Time) []state.StateTransition {
+		return map[time.Time][]state.StateTransition{
+			at(0): {transition(a, eval.Normal, eval.Normal)},
+			at(1): {transition(a, eval.Normal, eval.Pending)},
+			at(2): {transition(a, eval.Pending, eval.Alerting)},
+			at(3): {transition(a, eval.Alerting, eval.Alerting)},
+			at(4): {transition(a, eval.Alerting, eval.Alerting), transition(b, eval.Normal, eval.Alerting)},
+		}[now]
+	}}
+	managers := []stateManager{baseline, proposed}
+	engine := &Engine{
+		createStateManager: func() stateManager {
+			m := managers[0]
+			managers = managers[1:]
+			return m
+		},
+	}
+
+	rule := models.RuleGen.With(models.RuleGen.WithInterval(time.Second)).GenerateRef()
+	result, err := engine.Compare(context.Background(), nil, rule, rule.Copy(), from, at(5))
+	require.NoError(t, err)
+
+	require.Equal(t, 2, result.BaselineNotifications)
+	require.Equal(t, 2, result.ProposedNotifications)
+	require.NotNil(t, result.FirstDifference)
+	require.Equal(t, at(1), *result.FirstDifference)
+	require.Equal(t, 5, result.Baseline.Rows())
+	require.Equal(t, 5, result.Proposed.Rows())
+
+	str := func(s string) *string { return &s }
+	diff := result.Differences
+	require.Equal(t, 4, diff.Rows())
+	expected := []struct {
+		time               time.Time
+		labels             string
+		baseline, proposed *string
+	}{
+		{at(1), a.String(), str("Alerting"), str("Pending")},
+		{at(3), a.String(), str("Normal"), str("Alerting")},
+		{at(4), a.String(), str("Normal"), str("Alerting")},
+		{at(4), b.String(), nil, str("Alerting")},
+	}
+	for i, e := range expected {
+		require.Equal(t, e.time, diff.Fields[0].At(i))
+		require.Equal(t, e.labels, diff.Fields[1].At(i))
+		require.Equal(t, e.baseline, diff.Fields[2].At(i))
+		require.Equal(t, e.proposed, diff.Fields[3].At(i))
+	}
+
+	t.Run("should fail if a version cannot be tested", func(t *testing.T) {
+		_, err := engine.Compare(context.Background(), nil, rule, rule.Copy(), from, from)
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+}
+
 type fakeStateManager struct {
 	stateCallback func(now time.Time) []state.StateTransition
 }
@@ -395,7 +491,8 @@ func (f *fakeStateManager) GetStatesForRuleUID(orgID int64, alertRuleUID string)
 }
 
 type fakeBacktestingEvaluator struct {
-	evalCallback func(now time.Time) (eval.Results, error)
+	evalCallback   func(now time.Time) (eval.Results, error)
+	framesCallback func(now time.Time) (data.Frames, error)
 }
 
 func (f *fakeBacktestingEvaluator) Eval(_ context.Context, from time.Time, interval time.Duration, evaluations int, callback callbackFunc) error {
@@ -411,3 +508,17 @@ func (f *fakeBacktestingEvaluator) Eval(_ context.Context, from time.Time, inter
 	}
 	return nil
 }
+
+func (f *fakeBacktestingEvaluator) EvalFrames(_ context.Context, from time.Time, interval time.Duration, evaluations int, callback framesCallbackFunc) error {
+	for idx, now := 0, from; idx < evaluations; idx, now = idx+1, now.Add(interval) {
+		frames, err := f.framesCallback(now)
+		if err != nil {
+			return err
+		}
+		err = callback(idx, now, frames)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
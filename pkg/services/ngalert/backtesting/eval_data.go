@@ -37,17 +37,26 @@ func newDataEvaluator(refID string, frame *data.Frame) (*dataEvaluator, error) {
 	}, nil
 }
 
-func (d *dataEvaluator) Eval(_ context.Context, from time.Time, interval time.Duration, evaluations int, callback callbackFunc) error {
+// resample aligns the input data with the evaluations in the range [from, from+evaluations*interval).
+func (d *dataEvaluator) resample(from time.Time, interval time.Duration, evaluations int) ([]mathexp.Series, error) {
 	var resampled = make([]mathexp.Series, 0, len(d.data))
 	to := from.Add(time.Duration(evaluations) * interval)
 	for _, s := range d.data {
 		// making sure the input data frame is aligned with the interval
-		r, err := s.Resample(d.refID, interval, d.downsampleFunction, d.upsampleFunction, from, to.Add(-interval)) // we want to query [from,to)
+		r, err := s.Resample(d.refID, interval, d.downsampleFunction, d.upsampleFunction, 0, from, to.Add(-interval)) // we want to query [from,to)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		resampled = append(resampled, r)
 	}
+	return resampled, nil
+}
+
+func (d *dataEvaluator) Eval(_ context.Context, from time.Time, interval time.Duration, evaluations int, callback callbackFunc) error {
+	resampled, err := d.resample(from, interval, evaluations)
+	if err != nil {
+		return err
+	}
 
 	for i := 0; i < evaluations; i++ {
 		result := make([]eval.Result, 0, len(resampled))
@@ -92,3 +101,29 @@ func (d *dataEvaluator) Eval(_ context.Context, from time.Time, interval time.Du
 	}
 	return nil
 }
+
+func (d *dataEvaluator) EvalFrames(_ context.Context, from time.Time, interval time.Duration, evaluations int, callback framesCallbackFunc) error {
+	resampled, err := d.resample(from, interval, evaluations)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < evaluations; i++ {
+		var frames data.Frames
+		now := from.Add(time.Duration(i) * interval)
+		for _, series := range resampled {
+			value := series.GetValue(i)
+			if value == nil {
+				continue
+			}
+			n := mathexp.NewNumber(d.refID, series.GetLabels())
+			n.SetValue(value)
+			frames = append(frames, n.AsDataFrame())
+		}
+		err := callback(i, now, frames)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
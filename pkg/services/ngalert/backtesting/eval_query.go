@@ -4,12 +4,15 @@ import (
 	"context"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 )
 
 // QueryEvaluator is evaluator of regular alert rule queries
 type queryEvaluator struct {
-	eval eval.ConditionEvaluator
+	eval  eval.ConditionEvaluator
+	refID string
 }
 
 func (d *queryEvaluator) Eval(ctx context.Context, from time.Time, interval time.Duration, evaluations int, callback callbackFunc) error {
@@ -25,3 +28,24 @@ func (d *queryEvaluator) Eval(ctx context.Context, from time.Time, interval time
 	}
 	return nil
 }
+
+func (d *queryEvaluator) EvalFrames(ctx context.Context, from time.Time, interval time.Duration, evaluations int, callback framesCallbackFunc) error {
+	for idx, now := 0, from; idx < evaluations; idx, now = idx+1, now.Add(interval) {
+		resp, err := d.eval.EvaluateRaw(ctx, now)
+		if err != nil {
+			return err
+		}
+		if err := eval.FindConditionError(resp, d.refID); err != nil {
+			return err
+		}
+		var frames data.Frames
+		if res, ok := resp.Responses[d.refID]; ok && !eval.IsNoData(res) {
+			frames = res.Frames
+		}
+		err = callback(idx, now, frames)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
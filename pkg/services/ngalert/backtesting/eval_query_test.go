@@ -11,6 +11,9 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 )
@@ -92,3 +95,48 @@ func TestQueryEvaluator_Eval(t *testing.T) {
 		})
 	})
 }
+
+func TestQueryEvaluator_EvalFrames(t *testing.T) {
+	ctx := context.Background()
+	from := time.Unix(0, 0)
+
+	t.Run("should pass the frames of the condition", func(t *testing.T) {
+		frame := data.NewFrame("", data.NewField("A", nil, []float64{1}))
+		m := &eval_mocks.ConditionEvaluatorMock{}
+		m.EXPECT().EvaluateRaw(mock.Anything, from).Return(&backend.QueryDataResponse{
+			Responses: backend.Responses{"A": {Frames: data.Frames{frame}}},
+		}, nil).Once()
+		m.EXPECT().EvaluateRaw(mock.Anything, from.Add(time.Second)).Return(&backend.QueryDataResponse{
+			Responses: backend.Responses{"A": {Frames: data.Frames{data.NewFrame("")}}},
+		}, nil).Once()
+		evaluator := queryEvaluator{
+			eval:  m,
+			refID: "A",
+		}
+
+		var results []data.Frames
+		err := evaluator.EvalFrames(ctx, from, time.Second, 2, func(idx int, now time.Time, frames data.Frames) error {
+			results = append(results, frames)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []data.Frames{{frame}, nil}, results)
+	})
+
+	t.Run("should fail if the condition has an error", func(t *testing.T) {
+		expectedError := errors.New("test")
+		m := &eval_mocks.ConditionEvaluatorMock{}
+		m.EXPECT().EvaluateRaw(mock.Anything, mock.Anything).Return(&backend.QueryDataResponse{
+			Responses: backend.Responses{"A": {Error: expectedError}},
+		}, nil)
+		evaluator := queryEvaluator{
+			eval:  m,
+			refID: "A",
+		}
+
+		err := evaluator.EvalFrames(ctx, from, time.Second, 2, func(idx int, now time.Time, frames data.Frames) error {
+			return nil
+		})
+		require.ErrorIs(t, err, expectedError)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/expr"
 )
 
 // AlertingResultsReader provides fingerprints of results that are in alerting state.
@@ -14,11 +15,22 @@ type AlertingResultsReader interface {
 	Read() map[data.Fingerprint]struct{}
 }
 
+// AlertingSeveritiesReader provides the severities of results that are in alerting state.
+// It is used during the evaluation of multi-level thresholds, and is implemented by some AlertingResultsReader.
+type AlertingSeveritiesReader interface {
+	ReadSeverities() map[data.Fingerprint]string
+}
+
 // EvaluationContext represents the context in which a condition is evaluated.
 type EvaluationContext struct {
 	Ctx                   context.Context
 	User                  identity.Requester
 	AlertingResultsReader AlertingResultsReader
+	// Explanation, if not nil, enables the explain mode of the expression pipeline:
+	// every evaluation traces the execution of each query and expression into it.
+	Explanation *expr.PipelineExplanation
+	// SQLDryRun, if true, explains the SQL expressions instead of running them, see expr.Service.DryRunPipeline.
+	SQLDryRun bool
 }
 
 func NewContext(ctx context.Context, user identity.Requester) EvaluationContext {
@@ -40,7 +40,8 @@ type ConditionEvaluator interface {
 }
 
 type expressionExecutor interface {
-	ExecutePipeline(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (*backend.QueryDataResponse, error)
+	ExecutePipeline(ctx context.Context, now time.Time, pipeline expr.DataPipeline, opts ...expr.PipelineOption) (*backend.QueryDataResponse, error)
+	DryRunPipeline(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (map[string]expr.SQLDryRun, error)
 }
 
 type expressionBuilder interface {
@@ -54,6 +55,10 @@ type conditionEvaluator struct {
 	condition         models.Condition
 	evalTimeout       time.Duration
 	evalResultLimit   int
+	// explanation, if not nil, receives the trace of the execution of the pipeline.
+	explanation *expr.PipelineExplanation
+	// sqlDryRun makes EvaluateRaw explain the SQL expressions instead of running the pipeline.
+	sqlDryRun bool
 }
 
 func (r *conditionEvaluator) EvaluateRaw(ctx context.Context, now time.Time) (resp *backend.QueryDataResponse, err error) {
@@ -76,7 +81,14 @@ func (r *conditionEvaluator) EvaluateRaw(ctx context.Context, now time.Time) (re
 		execCtx = timeoutCtx
 	}
 	logger.FromContext(ctx).Debug("Executing pipeline", "commands", strings.Join(r.pipeline.GetCommandTypes(), ","), "datasources", strings.Join(r.pipeline.GetDatasourceTypes(), ","))
-	result, err := r.expressionService.ExecutePipeline(execCtx, now, r.pipeline)
+	if r.sqlDryRun {
+		return r.dryRun(execCtx, now)
+	}
+	var opts []expr.PipelineOption
+	if r.explanation != nil {
+		opts = append(opts, expr.WithExplain(r.explanation))
+	}
+	result, err := r.expressionService.ExecutePipeline(execCtx, now, r.pipeline, opts...)
 
 	// Check if the result of the condition evaluation is too large
 	if err == nil && result != nil && r.evalResultLimit > 0 {
@@ -93,6 +105,31 @@ func (r *conditionEvaluator) EvaluateRaw(ctx context.Context, now time.Time) (re
 	return result, err
 }
 
+// dryRun explains the SQL expressions of the pipeline. The explanation of each SQL expression is returned
+// as the custom metadata of an empty frame, with its warnings as notices, so it can be shown before the rule is saved.
+func (r *conditionEvaluator) dryRun(ctx context.Context, now time.Time) (*backend.QueryDataResponse, error) {
+	dryRuns, err := r.expressionService.DryRunPipeline(ctx, now, r.pipeline)
+	if err != nil {
+		return nil, err
+	}
+	result := backend.NewQueryDataResponse()
+	for refID, dryRun := range dryRuns {
+		if dryRun.Error != nil {
+			result.Responses[refID] = backend.DataResponse{Error: dryRun.Error}
+			continue
+		}
+		meta := &data.FrameMeta{
+			ExecutedQueryString: strings.Join(dryRun.Explanation.Plan, "\n"),
+			Custom:              dryRun.Explanation,
+		}
+		for _, warning := range dryRun.Explanation.Warnings {
+			meta.Notices = append(meta.Notices, data.Notice{Severity: data.NoticeSeverityWarning, Text: warning})
+		}
+		result.Responses[refID] = backend.DataResponse{Frames: data.Frames{data.NewFrame(refID).SetMeta(meta)}}
+	}
+	return result, nil
+}
+
 // Evaluate evaluates the condition and converts the response to Results
 func (r *conditionEvaluator) Evaluate(ctx context.Context, now time.Time) (Results, error) {
 	response, err := r.EvaluateRaw(ctx, now)
@@ -254,6 +291,9 @@ type Result struct {
 	// indexed by their Ref ID and the index of the condition. For example, B0, B1, etc.
 	Values map[string]NumberValueCapture
 
+	// Severity is the severity of the highest level breached by a multi-level threshold condition, if any.
+	Severity string
+
 	EvaluatedAt        time.Time
 	EvaluationDuration time.Duration
 	// EvaluationString is a string representation of evaluation data such
@@ -404,6 +444,25 @@ func getExprRequest(ctx EvaluationContext, condition models.Condition, dsCacheSe
 					}
 				}
 			}
+
+			isMultiLevel, err := q.IsMultiLevelThresholdExpression()
+			if err != nil {
+				return nil, fmt.Errorf("failed to build query '%s': %w", q.RefID, err)
+			}
+			if isMultiLevel {
+				// the severity of the results and the hysteresis of the levels only apply to the alert condition.
+				if q.RefID != condition.Condition {
+					return nil, fmt.Errorf("multi-level threshold '%s' is only allowed to be the alert condition", q.RefID)
+				}
+				if severitiesReader, ok := reader.(AlertingSeveritiesReader); ok {
+					severities := severitiesReader.ReadSeverities()
+					logger.FromContext(ctx.Ctx).Debug("Detected multi-level threshold command. Populating with the severities of the results", "items", len(severities))
+					err = q.PatchMultiLevelThresholdExpression(severities)
+					if err != nil {
+						return nil, fmt.Errorf("failed to amend multi-level threshold command '%s': %w", q.RefID, err)
+					}
+				}
+			}
 		}
 
 		model, err := q.GetModel()
@@ -785,6 +844,12 @@ func buildResult(f *data.Frame, val *float64, ts time.Time) Result {
 		EvaluationString:   extractEvalString(f),
 		Values:             extractValues(f),
 	}
+	// the severity of multi-level thresholds is not part of the identity of the result
+	if severity, ok := r.Instance[expr.ThresholdSeverityLabel]; ok {
+		r.Instance = r.Instance.Copy()
+		delete(r.Instance, expr.ThresholdSeverityLabel)
+		r.Severity = severity
+	}
 	switch {
 	case val == nil:
 		r.State = NoData
@@ -849,10 +914,10 @@ func (e *evaluatorImpl) Create(ctx EvaluationContext, condition models.Condition
 	if err != nil {
 		return nil, err
 	}
-	return e.create(condition, req)
+	return e.create(condition, req, ctx.Explanation, ctx.SQLDryRun)
 }
 
-func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request) (ConditionEvaluator, error) {
+func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request, explanation *expr.PipelineExplanation, sqlDryRun bool) (ConditionEvaluator, error) {
 	pipeline, err := e.expressionService.BuildPipeline(req)
 	if err != nil {
 		return nil, err
@@ -866,6 +931,8 @@ func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request) (C
 				condition:         condition,
 				evalTimeout:       e.evaluationTimeout,
 				evalResultLimit:   e.evaluationResultLimit,
+				explanation:       explanation,
+				sqlDryRun:         sqlDryRun,
 			}, nil
 		}
 		conditions = append(conditions, node.RefID())
@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/expr/sql"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -270,6 +271,24 @@ func TestEvaluateExecutionResult(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "severity of multi-level thresholds is removed from the instance",
+			execResults: ExecutionResults{
+				Condition: []*data.Frame{
+					data.NewFrame("",
+						data.NewField("", data.Labels{"a": "b", expr.ThresholdSeverityLabel: "critical"}, []*float64{util.Pointer(2.0)}),
+					),
+				},
+			},
+			expectResultLength: 1,
+			expectResults: Results{
+				{
+					State:    Alerting,
+					Instance: data.Labels{"a": "b"},
+					Severity: "critical",
+				},
+			},
+		},
 		{
 			desc: "certain errors will produce multiple mixed Error and other state results",
 			execResults: ExecutionResults{
@@ -305,6 +324,7 @@ func TestEvaluateExecutionResult(t *testing.T) {
 			for i, r := range res {
 				require.Equal(t, tc.expectResults[i].State, r.State)
 				require.Equal(t, tc.expectResults[i].Instance, r.Instance)
+				require.Equal(t, tc.expectResults[i].Severity, r.Severity)
 				if tc.expectResults[i].State == Error {
 					require.EqualError(t, tc.expectResults[i].Error, r.Error.Error())
 				}
@@ -592,7 +612,7 @@ func TestValidate(t *testing.T) {
 				pluginsStore: store,
 			})
 
-			expressions := expr.ProvideService(
+			expressions, err := expr.ProvideService(
 				&setting.Cfg{ExpressionsEnabled: true},
 				nil,
 				nil,
@@ -601,10 +621,11 @@ func TestValidate(t *testing.T) {
 				tracing.InitializeTracerForTest(),
 				mtdsclient.NewNullMTDatasourceClientBuilder(),
 			)
+			require.NoError(t, err)
 			validator := NewConditionValidator(cacheService, expressions, store)
 			evalCtx := NewContext(context.Background(), u)
 
-			err := validator.Validate(evalCtx, condition)
+			err = validator.Validate(evalCtx, condition)
 			if testCase.error {
 				require.Error(t, err)
 			} else {
@@ -719,18 +740,20 @@ func TestCreate_HysteresisCommand(t *testing.T) {
 				cache:        cacheService,
 				pluginsStore: store,
 			})
+			expressions, err := expr.ProvideService(
+				&setting.Cfg{ExpressionsEnabled: true},
+				nil,
+				nil,
+				featuremgmt.WithFeatures(),
+				nil,
+				tracing.InitializeTracerForTest(),
+				mtdsclient.NewNullMTDatasourceClientBuilder(),
+			)
+			require.NoError(t, err)
 			evaluator := NewEvaluatorFactory(
 				setting.UnifiedAlertingSettings{},
 				cacheService,
-				expr.ProvideService(
-					&setting.Cfg{ExpressionsEnabled: true},
-					nil,
-					nil,
-					featuremgmt.WithFeatures(),
-					nil,
-					tracing.InitializeTracerForTest(),
-					mtdsclient.NewNullMTDatasourceClientBuilder(),
-				),
+				expressions,
 			)
 			evalCtx := NewContextWithPreviousResults(context.Background(), u, testCase.reader)
 
@@ -753,6 +776,72 @@ func TestCreate_HysteresisCommand(t *testing.T) {
 	}
 }
 
+func TestCreate_MultiLevelThresholdCommand(t *testing.T) {
+	newCondition := func(t *testing.T, cache *fakes.FakeCacheService, store *pluginstore.FakePluginStore, condition string) models.Condition {
+		dsQuery := models.GenerateAlertQuery()
+		ds := &datasources.DataSource{
+			UID:  dsQuery.DatasourceUID,
+			Type: util.GenerateShortUID(),
+		}
+		cache.DataSources = append(cache.DataSources, ds)
+		store.PluginList = append(store.PluginList, pluginstore.Plugin{
+			JSONData: plugins.JSONData{
+				ID:      ds.Type,
+				Backend: true,
+			},
+		})
+		return models.Condition{
+			Condition: condition,
+			Data: []models.AlertQuery{
+				dsQuery,
+				models.CreateMultiLevelThresholdExpression(t, "B", dsQuery.RefID, 50, 90, 80),
+				models.CreateClassicConditionExpression("C", "B", "last", "gt", rand.Int()),
+			},
+		}
+	}
+	newEvaluator := func(cache *fakes.FakeCacheService) EvaluatorFactory {
+		expressions, err := expr.ProvideService(
+			&setting.Cfg{ExpressionsEnabled: true},
+			nil,
+			nil,
+			featuremgmt.WithFeatures(),
+			nil,
+			tracing.InitializeTracerForTest(),
+			mtdsclient.NewNullMTDatasourceClientBuilder(),
+		)
+		require.NoError(t, err)
+		return NewEvaluatorFactory(
+			setting.UnifiedAlertingSettings{},
+			cache,
+			expressions,
+		)
+	}
+
+	t.Run("fail if multi-level threshold is not the condition", func(t *testing.T) {
+		cache, store := &fakes.FakeCacheService{}, &pluginstore.FakePluginStore{}
+		condition := newCondition(t, cache, store, "C")
+		_, err := newEvaluator(cache).Create(NewContext(context.Background(), &user.SignedInUser{}), condition)
+		require.ErrorContains(t, err, "multi-level threshold 'B' is only allowed to be the alert condition")
+	})
+
+	t.Run("populate the levels with hysteresis with the severities", func(t *testing.T) {
+		cache, store := &fakes.FakeCacheService{}, &pluginstore.FakePluginStore{}
+		condition := newCondition(t, cache, store, "B")
+		reader := FakeLoadedSeveritiesReader{severities: map[data.Fingerprint]string{1: "warning", 2: "critical", 3: "unknown"}}
+		eval, err := newEvaluator(cache).Create(NewContextWithPreviousResults(context.Background(), &user.SignedInUser{}, reader), condition)
+		require.NoError(t, err)
+		require.IsType(t, &conditionEvaluator{}, eval)
+
+		cmds := expr.GetCommandsFromPipeline[*expr.MultiLevelThresholdCommand](eval.(*conditionEvaluator).pipeline)
+		require.Len(t, cmds, 1)
+		require.Len(t, cmds[0].Levels, 2)
+		require.Nil(t, cmds[0].Levels[0].Unloading)
+		require.Empty(t, cmds[0].Levels[0].LoadedDimensions)
+		require.NotNil(t, cmds[0].Levels[1].Unloading)
+		require.EqualValues(t, expr.Fingerprints{2: {}}, cmds[0].Levels[1].LoadedDimensions)
+	})
+}
+
 func TestQueryDataResponseToExecutionResults(t *testing.T) {
 	t.Run("should set datasource type for captured values", func(t *testing.T) {
 		c := models.Condition{
@@ -1293,6 +1382,38 @@ func TestEvaluateRaw(t *testing.T) {
 		_, err := e.EvaluateRaw(context.Background(), time.Now())
 		require.ErrorIs(t, err, context.DeadlineExceeded)
 	})
+
+	t.Run("should explain the SQL expressions instead of running the pipeline in dry run", func(t *testing.T) {
+		explanation := &sql.Explanation{
+			Plan:     []string{"Project", " └─ Table A"},
+			Warnings: []string{"estimated output of 20 cells exceeds the limit of 10 cells, the result will be truncated"},
+		}
+		e := conditionEvaluator{
+			expressionService: &fakeExpressionService{
+				hook: func(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (*backend.QueryDataResponse, error) {
+					return nil, errors.New("the pipeline should not run")
+				},
+				dryRunHook: func(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (map[string]expr.SQLDryRun, error) {
+					return map[string]expr.SQLDryRun{
+						"B": {Explanation: explanation},
+						"C": {Error: errors.New("column not found")},
+					}, nil
+				},
+			},
+			condition:   models.Condition{Condition: "B"},
+			evalTimeout: time.Second,
+			sqlDryRun:   true,
+		}
+
+		result, err := e.EvaluateRaw(context.Background(), time.Now())
+		require.NoError(t, err)
+		require.Len(t, result.Responses["B"].Frames, 1)
+		meta := result.Responses["B"].Frames[0].Meta
+		require.Equal(t, explanation, meta.Custom)
+		require.Equal(t, "Project\n └─ Table A", meta.ExecutedQueryString)
+		require.Equal(t, []data.Notice{{Severity: data.NoticeSeverityWarning, Text: explanation.Warnings[0]}}, meta.Notices)
+		require.ErrorContains(t, result.Responses["C"].Error, "column not found")
+	})
 }
 
 func TestEvaluateRawLimit(t *testing.T) {
@@ -1561,14 +1682,19 @@ func TestCreate(t *testing.T) {
 }
 
 type fakeExpressionService struct {
-	hook      func(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (*backend.QueryDataResponse, error)
-	buildHook func(req *expr.Request) (expr.DataPipeline, error)
+	hook       func(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (*backend.QueryDataResponse, error)
+	buildHook  func(req *expr.Request) (expr.DataPipeline, error)
+	dryRunHook func(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (map[string]expr.SQLDryRun, error)
 }
 
-func (f fakeExpressionService) ExecutePipeline(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (*backend.QueryDataResponse, error) {
+func (f fakeExpressionService) ExecutePipeline(ctx context.Context, now time.Time, pipeline expr.DataPipeline, _ ...expr.PipelineOption) (*backend.QueryDataResponse, error) {
 	return f.hook(ctx, now, pipeline)
 }
 
+func (f fakeExpressionService) DryRunPipeline(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (map[string]expr.SQLDryRun, error) {
+	return f.dryRunHook(ctx, now, pipeline)
+}
+
 func (f fakeExpressionService) BuildPipeline(req *expr.Request) (expr.DataPipeline, error) {
 	return f.buildHook(req)
 }
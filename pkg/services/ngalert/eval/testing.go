@@ -93,3 +93,19 @@ type FakeLoadedMetricsReader struct {
 func (f FakeLoadedMetricsReader) Read() map[data.Fingerprint]struct{} {
 	return f.fingerprints
 }
+
+type FakeLoadedSeveritiesReader struct {
+	severities map[data.Fingerprint]string
+}
+
+func (f FakeLoadedSeveritiesReader) Read() map[data.Fingerprint]struct{} {
+	result := make(map[data.Fingerprint]struct{}, len(f.severities))
+	for fp := range f.severities {
+		result[fp] = struct{}{}
+	}
+	return result
+}
+
+func (f FakeLoadedSeveritiesReader) ReadSeverities() map[data.Fingerprint]string {
+	return f.severities
+}
@@ -146,6 +146,28 @@ func (aq *AlertQuery) PatchHysteresisExpression(loadedMetrics map[data.Fingerpri
 	return expr.SetLoadedDimensionsToHysteresisCommand(aq.modelProps, loadedMetrics)
 }
 
+// IsMultiLevelThresholdExpression returns true if the model describes a multi-level threshold command expression. Returns error if the Model is not a valid JSON
+func (aq *AlertQuery) IsMultiLevelThresholdExpression() (bool, error) {
+	if aq.modelProps == nil {
+		err := aq.setModelProps()
+		if err != nil {
+			return false, err
+		}
+	}
+	return expr.IsMultiLevelThresholdExpression(aq.modelProps), nil
+}
+
+// PatchMultiLevelThresholdExpression updates the AlertQuery to include the severities of the results into the levels that have a hysteresis
+func (aq *AlertQuery) PatchMultiLevelThresholdExpression(severities map[data.Fingerprint]string) error {
+	if aq.modelProps == nil {
+		err := aq.setModelProps()
+		if err != nil {
+			return err
+		}
+	}
+	return expr.SetLoadedSeveritiesToMultiLevelThresholdCommand(aq.modelProps, severities)
+}
+
 // setMaxDatapoints sets the model maxDataPoints if it's missing or invalid
 func (aq *AlertQuery) setMaxDatapoints() error {
 	if aq.modelProps == nil {
@@ -138,6 +138,10 @@ const (
 	// StateReasonAnnotation is the name of the annotation that explains the difference between evaluation state and alert state (i.e. changing state when NoData or Error).
 	StateReasonAnnotation = GrafanaReservedLabelPrefix + "state_reason"
 
+	// SeverityLabel is the label that will contain the severity of the highest level breached by a multi-level threshold condition.
+	// It is not part of the fingerprint of the alert instance, so that a change of severity does not change its identity.
+	SeverityLabel = GrafanaReservedLabelPrefix + "severity"
+
 	// MigratedLabelPrefix is a label prefix for all labels created during legacy migration.
 	MigratedLabelPrefix = "__legacy_"
 	// MigratedUseLegacyChannelsLabel is created during legacy migration to route to separate nested policies for migrated channels.
@@ -304,11 +308,16 @@ type AlertRule struct {
 	// If nil, alerts resolve after 2 missing evaluation intervals
 	// (i.e., resolution occurs during the second evaluation where data is absent).
 	MissingSeriesEvalsToResolve *int
+	// DependsOn contains the UIDs of the alert rules of the same organization this rule depends on.
+	// The scheduler evaluates them before this rule, and this rule's alerts are not sent while any of them is firing.
+	DependsOn []string
 }
 
 type AlertRuleMetadata struct {
 	EditorSettings      EditorSettings       `json:"editor_settings"`
 	PrometheusStyleRule *PrometheusStyleRule `json:"prometheus_style_rule,omitempty"`
+	// RuleTemplate references the template of the rules of alert rule template instances.
+	RuleTemplate *AlertRuleTemplateReference `json:"rule_template,omitempty"`
 }
 
 type EditorSettings struct {
@@ -694,6 +703,20 @@ func validateAlertRuleFields(rule *AlertRule) error {
 		return errors.New("field `missing_series_evals_to_resolve` must be greater than 0")
 	}
 
+	seen := make(map[string]struct{}, len(rule.DependsOn))
+	for _, uid := range rule.DependsOn {
+		if uid == "" {
+			return errors.New("field `depends_on` cannot contain an empty rule UID")
+		}
+		if uid == rule.UID {
+			return errors.New("rule cannot depend on itself")
+		}
+		if _, ok := seen[uid]; ok {
+			return fmt.Errorf("field `depends_on` contains rule UID %s more than once", uid)
+		}
+		seen[uid] = struct{}{}
+	}
+
 	return nil
 }
 
@@ -766,6 +789,11 @@ func (alertRule *AlertRule) Copy() *AlertRule {
 		MissingSeriesEvalsToResolve: alertRule.MissingSeriesEvalsToResolve,
 	}
 
+	if alertRule.DependsOn != nil {
+		result.DependsOn = make([]string, len(alertRule.DependsOn))
+		copy(result.DependsOn, alertRule.DependsOn)
+	}
+
 	if alertRule.DashboardUID != nil {
 		dash := *alertRule.DashboardUID
 		result.DashboardUID = &dash
@@ -813,6 +841,11 @@ func (alertRule *AlertRule) Copy() *AlertRule {
 		result.Metadata.PrometheusStyleRule = &prometheusStyleRule
 	}
 
+	if alertRule.Metadata.RuleTemplate != nil {
+		ruleTemplate := *alertRule.Metadata.RuleTemplate
+		result.Metadata.RuleTemplate = &ruleTemplate
+	}
+
 	for _, s := range alertRule.NotificationSettings {
 		result.NotificationSettings = append(result.NotificationSettings, CopyNotificationSettings(s))
 	}
@@ -828,6 +861,7 @@ func ClearRecordingRuleIgnoredFields(rule *AlertRule) {
 	rule.KeepFiringFor = 0
 	rule.NotificationSettings = nil
 	rule.MissingSeriesEvalsToResolve = nil
+	rule.DependsOn = nil
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
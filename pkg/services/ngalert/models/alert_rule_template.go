@@ -0,0 +1,271 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrAlertRuleTemplateNotFound         = errors.New("alert rule template not found")
+	ErrAlertRuleTemplateExists           = errors.New("alert rule template with this name already exists")
+	ErrAlertRuleTemplateInUse            = errors.New("alert rule template is used by instances")
+	ErrAlertRuleTemplateInstanceNotFound = errors.New("alert rule template instance not found")
+	ErrAlertRuleTemplateInstanceExists   = errors.New("alert rule template instance with this UID already exists")
+)
+
+// templateStringValueForbiddenChars are the characters that the values of string parameters must not contain. Values
+// are inserted as they are in query expressions and templates of annotations, so these characters would let a value
+// close a quoted string, add lines to a query, or reference other variables and templates.
+const templateStringValueForbiddenChars = "${}\"'`\n\r"
+
+// templateParameterReference matches the references to the parameters of a template, written ${name}.
+var templateParameterReference = regexp.MustCompile(`\$\{([a-zA-Z_][a-zA-Z0-9_]*)\}`)
+
+type AlertRuleTemplateParameterType string
+
+const (
+	AlertRuleTemplateParameterString AlertRuleTemplateParameterType = "string"
+	AlertRuleTemplateParameterNumber AlertRuleTemplateParameterType = "number"
+)
+
+// AlertRuleTemplateParameter is a parameter of an alert rule template.
+type AlertRuleTemplateParameter struct {
+	Name        string
+	Type        AlertRuleTemplateParameterType
+	Description string
+}
+
+// AlertRuleTemplate is an alert rule with parameters. It is instantiated as one alert rule per
+// AlertRuleTemplateInstance, in which the references to the parameters are replaced with the values of the instance.
+type AlertRuleTemplate struct {
+	ID         int64
+	UID        string
+	OrgID      int64
+	Name       string
+	Parameters []AlertRuleTemplateParameter
+	// Rule is the definition of the rules. The parameters can be referenced as ${name} in the title, labels and
+	// annotations, the data source and model of the queries, and the recording rule settings. In query models,
+	// a string that is only a reference to a number parameter is replaced with the number.
+	Rule       AlertRuleTemplateRule
+	Version    int64
+	Updated    time.Time
+	Provenance Provenance
+}
+
+func (t *AlertRuleTemplate) ResourceType() string {
+	return "alertRuleTemplate"
+}
+
+func (t *AlertRuleTemplate) ResourceID() string {
+	return t.UID
+}
+
+// AlertRuleTemplateRule is the definition of the rules of an alert rule template.
+type AlertRuleTemplateRule struct {
+	Title                       string
+	Condition                   string
+	Data                        []AlertQuery
+	NoDataState                 NoDataState
+	ExecErrState                ExecutionErrorState
+	For                         time.Duration
+	KeepFiringFor               time.Duration
+	Annotations                 map[string]string
+	Labels                      map[string]string
+	IsPaused                    bool
+	NotificationSettings        []NotificationSettings
+	Record                      *Record
+	MissingSeriesEvalsToResolve *int
+}
+
+// AlertRuleTemplateInstance binds values to the parameters of an alert rule template. The rule of the instance is
+// stored in the folder and group of the instance, with the same UID as the instance.
+type AlertRuleTemplateInstance struct {
+	ID              int64
+	UID             string
+	OrgID           int64
+	TemplateUID     string
+	NamespaceUID    string
+	RuleGroup       string
+	IntervalSeconds int64
+	Values          map[string]string
+	Version         int64
+	Updated         time.Time
+	Provenance      Provenance
+}
+
+func (i *AlertRuleTemplateInstance) ResourceType() string {
+	return "alertRuleTemplateInstance"
+}
+
+func (i *AlertRuleTemplateInstance) ResourceID() string {
+	return i.UID
+}
+
+// AlertRuleTemplateReference is stored in the metadata of the rules of template instances.
+type AlertRuleTemplateReference struct {
+	TemplateUID     string `json:"template_uid"`
+	TemplateVersion int64  `json:"template_version"`
+}
+
+// Validate checks that the template has a name, a rule and parameters with unique names and known types.
+func (t *AlertRuleTemplate) Validate() error {
+	if t.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	if t.Rule.Title == "" {
+		return errors.New("rule title must not be empty")
+	}
+	if len(t.Rule.Data) == 0 {
+		return errors.New("rule must have at least one query")
+	}
+	names := make(map[string]struct{}, len(t.Parameters))
+	for _, p := range t.Parameters {
+		if !templateParameterReference.MatchString("${" + p.Name + "}") {
+			return fmt.Errorf("invalid parameter name %q", p.Name)
+		}
+		if _, ok := names[p.Name]; ok {
+			return fmt.Errorf("duplicate parameter %q", p.Name)
+		}
+		names[p.Name] = struct{}{}
+		if p.Type != AlertRuleTemplateParameterString && p.Type != AlertRuleTemplateParameterNumber {
+			return fmt.Errorf("parameter %q has invalid type %q", p.Name, p.Type)
+		}
+	}
+	return nil
+}
+
+// Instantiate returns the rule of the instance. The values of the instance must match the parameters of the template,
+// and the values of string parameters must not contain any of templateStringValueForbiddenChars. References to unknown parameters are kept as they are, so that the rules can use variables such as ${__interval}.
+func (t *AlertRuleTemplate) Instantiate(instance AlertRuleTemplateInstance) (AlertRule, error) {
+	numbers := make(map[string]float64)
+	for _, p := range t.Parameters {
+		v, ok := instance.Values[p.Name]
+		if !ok {
+			return AlertRule{}, fmt.Errorf("missing value for parameter %q", p.Name)
+		}
+		if p.Type == AlertRuleTemplateParameterNumber {
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return AlertRule{}, fmt.Errorf("value of parameter %q must be a number: %w", p.Name, err)
+			}
+			numbers[p.Name] = n
+			continue
+		}
+		if i := strings.IndexAny(v, templateStringValueForbiddenChars); i >= 0 {
+			return AlertRule{}, fmt.Errorf("value of parameter %q must not contain %q", p.Name, v[i:i+1])
+		}
+	}
+	for name := range instance.Values {
+		if !t.hasParameter(name) {
+			return AlertRule{}, fmt.Errorf("unknown parameter %q", name)
+		}
+	}
+
+	replace := func(s string) string {
+		return templateParameterReference.ReplaceAllStringFunc(s, func(ref string) string {
+			if v, ok := instance.Values[ref[2:len(ref)-1]]; ok {
+				return v
+			}
+			return ref
+		})
+	}
+	replaceMap := func(m map[string]string) map[string]string {
+		if m == nil {
+			return nil
+		}
+		result := make(map[string]string, len(m))
+		for k, v := range m {
+			result[k] = replace(v)
+		}
+		return result
+	}
+
+	def := t.Rule
+	rule := AlertRule{
+		OrgID:                       instance.OrgID,
+		UID:                         instance.UID,
+		NamespaceUID:                instance.NamespaceUID,
+		RuleGroup:                   instance.RuleGroup,
+		IntervalSeconds:             instance.IntervalSeconds,
+		Title:                       replace(def.Title),
+		Condition:                   def.Condition,
+		Data:                        make([]AlertQuery, 0, len(def.Data)),
+		NoDataState:                 def.NoDataState,
+		ExecErrState:                def.ExecErrState,
+		For:                         def.For,
+		KeepFiringFor:               def.KeepFiringFor,
+		Annotations:                 replaceMap(def.Annotations),
+		Labels:                      replaceMap(def.Labels),
+		IsPaused:                    def.IsPaused,
+		MissingSeriesEvalsToResolve: def.MissingSeriesEvalsToResolve,
+		Metadata: AlertRuleMetadata{
+			RuleTemplate: &AlertRuleTemplateReference{TemplateUID: t.UID, TemplateVersion: t.Version},
+		},
+	}
+	for _, ns := range def.NotificationSettings {
+		rule.NotificationSettings = append(rule.NotificationSettings, CopyNotificationSettings(ns))
+	}
+	if def.Record != nil {
+		rule.Record = &Record{
+			Metric:              replace(def.Record.Metric),
+			From:                def.Record.From,
+			TargetDatasourceUID: replace(def.Record.TargetDatasourceUID),
+		}
+	}
+	for _, q := range def.Data {
+		var model any
+		if err := json.Unmarshal(q.Model, &model); err != nil {
+			return AlertRule{}, fmt.Errorf("failed to unmarshal model of query %s: %w", q.RefID, err)
+		}
+		b, err := json.Marshal(replaceInModel(model, replace, numbers))
+		if err != nil {
+			return AlertRule{}, fmt.Errorf("failed to marshal model of query %s: %w", q.RefID, err)
+		}
+		q.Model = b
+		q.DatasourceUID = replace(q.DatasourceUID)
+		rule.Data = append(rule.Data, q)
+	}
+	return rule, nil
+}
+
+func (t *AlertRuleTemplate) hasParameter(name string) bool {
+	for _, p := range t.Parameters {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceInModel replaces the references to parameters in the strings of a query model.
+func replaceInModel(v any, replace func(string) string, numbers map[string]float64) any {
+	switch v := v.(type) {
+	case string:
+		if m := templateParameterReference.FindStringSubmatch(v); m != nil && m[0] == v {
+			if n, ok := numbers[m[1]]; ok {
+				return n
+			}
+		}
+		return replace(v)
+	case map[string]any:
+		result := maps.Clone(v)
+		for k, e := range v {
+			result[k] = replaceInModel(e, replace, numbers)
+		}
+		return result
+	case []any:
+		result := make([]any, 0, len(v))
+		for _, e := range v {
+			result = append(result, replaceInModel(e, replace, numbers))
+		}
+		return result
+	default:
+		return v
+	}
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertRuleTemplateInstantiate(t *testing.T) {
+	template := AlertRuleTemplate{
+		UID:     "template-uid",
+		Version: 3,
+		Parameters: []AlertRuleTemplateParameter{
+			{Name: "datasource", Type: AlertRuleTemplateParameterString},
+			{Name: "job", Type: AlertRuleTemplateParameterString},
+			{Name: "threshold", Type: AlertRuleTemplateParameterNumber},
+		},
+		Rule: AlertRuleTemplateRule{
+			Title: "Errors of ${job}",
+			Data: []AlertQuery{
+				{
+					RefID:         "A",
+					DatasourceUID: "${datasource}",
+					Model:         json.RawMessage(`{"refId":"A","expr":"rate(errors{job=\"${job}\"}[${__interval}])","datasource":{"uid":"${datasource}"}}`),
+				},
+				{
+					RefID:         "B",
+					DatasourceUID: "__expr__",
+					Model:         json.RawMessage(`{"refId":"B","type":"threshold","expression":"A","conditions":[{"evaluator":{"type":"gt","params":["${threshold}"]}}]}`),
+				},
+			},
+			Condition:    "B",
+			NoDataState:  NoData,
+			ExecErrState: ErrorErrState,
+			Labels:       map[string]string{"job": "${job}", "team": "sre"},
+			Annotations:  map[string]string{"summary": "${job} has more than ${threshold} errors per second"},
+		},
+	}
+	instance := AlertRuleTemplateInstance{
+		UID:             "instance-uid",
+		OrgID:           1,
+		TemplateUID:     "template-uid",
+		NamespaceUID:    "folder",
+		RuleGroup:       "group",
+		IntervalSeconds: 60,
+		Values:          map[string]string{"datasource": "prom-eu", "job": "api", "threshold": "0.5"},
+	}
+
+	t.Run("should replace references to parameters", func(t *testing.T) {
+		rule, err := template.Instantiate(instance)
+		require.NoError(t, err)
+
+		require.Equal(t, "instance-uid", rule.UID)
+		require.Equal(t, "folder", rule.NamespaceUID)
+		require.Equal(t, "group", rule.RuleGroup)
+		require.EqualValues(t, 60, rule.IntervalSeconds)
+		require.Equal(t, "Errors of api", rule.Title)
+		require.Equal(t, map[string]string{"job": "api", "team": "sre"}, rule.Labels)
+		require.Equal(t, map[string]string{"summary": "api has more than 0.5 errors per second"}, rule.Annotations)
+		require.Equal(t, "prom-eu", rule.Data[0].DatasourceUID)
+		require.JSONEq(t, `{"refId":"A","expr":"rate(errors{job=\"api\"}[${__interval}])","datasource":{"uid":"prom-eu"}}`, string(rule.Data[0].Model))
+		require.JSONEq(t, `{"refId":"B","type":"threshold","expression":"A","conditions":[{"evaluator":{"type":"gt","params":[0.5]}}]}`, string(rule.Data[1].Model))
+		require.Equal(t, &AlertRuleTemplateReference{TemplateUID: "template-uid", TemplateVersion: 3}, rule.Metadata.RuleTemplate)
+
+		// The template must not be modified.
+		require.Equal(t, "${datasource}", template.Rule.Data[0].DatasourceUID)
+	})
+
+	t.Run("should fail if a value is missing", func(t *testing.T) {
+		i := instance
+		i.Values = map[string]string{"datasource": "prom-eu", "threshold": "1"}
+		_, err := template.Instantiate(i)
+		require.ErrorContains(t, err, `missing value for parameter "job"`)
+	})
+
+	t.Run("should fail if a value is unknown", func(t *testing.T) {
+		i := instance
+		i.Values = map[string]string{"datasource": "prom-eu", "job": "api", "threshold": "1", "env": "prod"}
+		_, err := template.Instantiate(i)
+		require.ErrorContains(t, err, `unknown parameter "env"`)
+	})
+
+	t.Run("should fail if a number is invalid", func(t *testing.T) {
+		i := instance
+		i.Values = map[string]string{"datasource": "prom-eu", "job": "api", "threshold": "high"}
+		_, err := template.Instantiate(i)
+		require.ErrorContains(t, err, `value of parameter "threshold" must be a number`)
+	})
+
+	t.Run("should fail if a string value can escape its reference", func(t *testing.T) {
+		for _, value := range []string{
+			`api"} or vector(1) or up{job="api`,
+			"api'",
+			"api`",
+			"api\nsum(up)",
+			"api\r",
+			"${datasource}",
+			"{{ $labels.instance }}",
+		} {
+			i := instance
+			i.Values = map[string]string{"datasource": "prom-eu", "job": value, "threshold": "1"}
+			_, err := template.Instantiate(i)
+			require.ErrorContainsf(t, err, `value of parameter "job" must not contain`, "value %q", value)
+		}
+	})
+
+	t.Run("should accept other characters in string values", func(t *testing.T) {
+		i := instance
+		i.Values = map[string]string{"datasource": "prom-eu", "job": "api-v2.eu_west (primary)/1", "threshold": "1"}
+		rule, err := template.Instantiate(i)
+		require.NoError(t, err)
+		require.Equal(t, "Errors of api-v2.eu_west (primary)/1", rule.Title)
+	})
+}
+
+func TestAlertRuleTemplateValidate(t *testing.T) {
+	valid := func() AlertRuleTemplate {
+		return AlertRuleTemplate{
+			Name:       "template",
+			Parameters: []AlertRuleTemplateParameter{{Name: "job", Type: AlertRuleTemplateParameterString}},
+			Rule:       AlertRuleTemplateRule{Title: "rule", Data: []AlertQuery{{RefID: "A"}}},
+		}
+	}
+	require.NoError(t, valid().Validate())
+
+	testCases := []struct {
+		name        string
+		mutate      func(*AlertRuleTemplate)
+		expectedErr string
+	}{
+		{name: "empty name", mutate: func(t *AlertRuleTemplate) { t.Name = "" }, expectedErr: "name must not be empty"},
+		{name: "no queries", mutate: func(t *AlertRuleTemplate) { t.Rule.Data = nil }, expectedErr: "rule must have at least one query"},
+		{name: "invalid parameter name", mutate: func(t *AlertRuleTemplate) { t.Parameters[0].Name = "job-name" }, expectedErr: `invalid parameter name "job-name"`},
+		{name: "invalid parameter type", mutate: func(t *AlertRuleTemplate) { t.Parameters[0].Type = "bool" }, expectedErr: `parameter "job" has invalid type "bool"`},
+		{
+			name:        "duplicate parameter",
+			mutate:      func(t *AlertRuleTemplate) { t.Parameters = append(t.Parameters, t.Parameters[0]) },
+			expectedErr: `duplicate parameter "job"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			template := valid()
+			tc.mutate(&template)
+			require.EqualError(t, template.Validate(), tc.expectedErr)
+		})
+	}
+}
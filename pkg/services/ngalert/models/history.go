@@ -3,6 +3,8 @@ package models
 import (
 	"time"
 
+	"github.com/prometheus/alertmanager/pkg/labels"
+
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 )
 
@@ -17,4 +19,10 @@ type HistoryQuery struct {
 	To           time.Time
 	Limit        int
 	SignedInUser identity.Requester
+	// Offset is the number of the most recent transitions to skip.
+	Offset int
+	// States filters the transitions by the state the alert instances transitioned to.
+	States []string
+	// Matchers filters the transitions by the labels of the alert instances.
+	Matchers labels.Matchers
 }
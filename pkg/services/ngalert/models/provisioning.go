@@ -10,10 +10,12 @@ const (
 	ProvenanceFile Provenance = "file"
 	// ProvenanceConvertedPrometheus is used for objects converted from Prometheus definitions.
 	ProvenanceConvertedPrometheus Provenance = "converted_prometheus"
+	// ProvenanceRuleTemplate is used for the alert rules of alert rule template instances.
+	ProvenanceRuleTemplate Provenance = "rule_template"
 )
 
 var (
-	KnownProvenances = []Provenance{ProvenanceNone, ProvenanceAPI, ProvenanceFile, ProvenanceConvertedPrometheus}
+	KnownProvenances = []Provenance{ProvenanceNone, ProvenanceAPI, ProvenanceFile, ProvenanceConvertedPrometheus, ProvenanceRuleTemplate}
 )
 
 // Provisionable represents a resource that can be created through a provisioning mechanism, such as Terraform or config file.
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	ErrRecurringSilenceNotFound = errors.New("recurring silence not found")
+	ErrRecurringSilenceExists   = errors.New("recurring silence with this name already exists")
+)
+
+const (
+	// maxRecurringSilenceOccurrences limits the number of occurrences of a recurring silence returned by Occurrences.
+	maxRecurringSilenceOccurrences = 1000
+	// maxRecurringSilenceWindow is how far before and after a time range the occurrences of time intervals are looked up
+	// to find their actual start and end. Longer occurrences are cut.
+	maxRecurringSilenceWindow = 7 * 24 * time.Hour
+)
+
+// RecurringSilence is a silence that repeats on a schedule, for example every Sunday from 02:00 to 04:00.
+// The schedule is either a cron expression with a duration, or time intervals in the same format as mute timings.
+// Each occurrence is materialized into a regular silence ahead of time.
+type RecurringSilence struct {
+	ID       int64
+	UID      string
+	OrgID    int64
+	Name     string
+	Matchers labels.Matchers
+	// Cron is a cron expression for the start of each occurrence, which lasts Duration. It can be prefixed with
+	// CRON_TZ=<location> to use a time zone other than UTC.
+	Cron     string
+	Duration time.Duration
+	// TimeIntervals are the time intervals during which the silence is active.
+	TimeIntervals []timeinterval.TimeInterval
+	Comment       string
+	CreatedBy     string
+	Version       int64
+	Updated       time.Time
+	Provenance    Provenance
+}
+
+// SilenceWindow is a single occurrence of a recurring silence.
+type SilenceWindow struct {
+	StartsAt time.Time
+	EndsAt   time.Time
+}
+
+// ListRecurringSilencesQuery is the query for listing recurring silences. If OrgID is 0, the recurring silences of all
+// organizations are listed.
+type ListRecurringSilencesQuery struct {
+	OrgID int64
+}
+
+func (s *RecurringSilence) ResourceType() string {
+	return "recurringSilence"
+}
+
+func (s *RecurringSilence) ResourceID() string {
+	return s.UID
+}
+
+// Validate checks that the recurring silence has a name, matchers and exactly one valid schedule.
+func (s *RecurringSilence) Validate() error {
+	if s.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	if len(s.Matchers) == 0 {
+		return errors.New("at least one matcher is required")
+	}
+	switch {
+	case s.Cron != "" && len(s.TimeIntervals) > 0:
+		return errors.New("cron and time intervals cannot be used together")
+	case s.Cron != "":
+		if _, err := cron.ParseStandard(s.Cron); err != nil {
+			return fmt.Errorf("invalid cron expression: %w", err)
+		}
+		if s.Duration <= 0 {
+			return errors.New("duration must be positive when cron is used")
+		}
+	case len(s.TimeIntervals) > 0:
+		if s.Duration != 0 {
+			return errors.New("duration cannot be used with time intervals")
+		}
+	default:
+		return errors.New("either cron or time intervals are required")
+	}
+	return nil
+}
+
+// Occurrences returns the occurrences of the recurring silence that overlap with the time range [from, to).
+// An occurrence in progress at from is returned with its actual start time.
+func (s *RecurringSilence) Occurrences(from, to time.Time) ([]SilenceWindow, error) {
+	if s.Cron != "" {
+		return s.cronOccurrences(from, to)
+	}
+	return s.timeIntervalOccurrences(from, to), nil
+}
+
+func (s *RecurringSilence) cronOccurrences(from, to time.Time) ([]SilenceWindow, error) {
+	schedule, err := cron.ParseStandard(s.Cron)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression: %w", err)
+	}
+	var result []SilenceWindow
+	// Next returns the first activation strictly after the given time, so this finds the occurrences that end after from.
+	// Expressions without CRON_TZ use the location of the given time, which must be UTC.
+	for start := schedule.Next(from.UTC().Add(-s.Duration)); !start.IsZero() && start.Before(to); start = schedule.Next(start) {
+		result = append(result, SilenceWindow{StartsAt: start, EndsAt: start.Add(s.Duration)})
+		if len(result) == maxRecurringSilenceOccurrences {
+			break
+		}
+	}
+	return result, nil
+}
+
+// timeIntervalOccurrences scans the time range minute by minute, the resolution of time intervals.
+func (s *RecurringSilence) timeIntervalOccurrences(from, to time.Time) []SilenceWindow {
+	contains := func(t time.Time) bool {
+		for _, ti := range s.TimeIntervals {
+			if ti.ContainsTime(t.UTC()) {
+				return true
+			}
+		}
+		return false
+	}
+
+	t := from.Truncate(time.Minute)
+	// Find the actual start of an occurrence in progress.
+	if contains(t) {
+		for limit := t.Add(-maxRecurringSilenceWindow); t.After(limit) && contains(t.Add(-time.Minute)); {
+			t = t.Add(-time.Minute)
+		}
+	}
+
+	var result []SilenceWindow
+	var start time.Time
+	for ; t.Before(to) || (!start.IsZero() && t.Before(start.Add(maxRecurringSilenceWindow))); t = t.Add(time.Minute) {
+		active := contains(t)
+		if active && start.IsZero() {
+			start = t
+		} else if !active && !start.IsZero() {
+			result = append(result, SilenceWindow{StartsAt: start, EndsAt: t})
+			if len(result) == maxRecurringSilenceOccurrences {
+				return result
+			}
+			start = time.Time{}
+		}
+	}
+	if !start.IsZero() {
+		result = append(result, SilenceWindow{StartsAt: start, EndsAt: t})
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurringSilence_Validate(t *testing.T) {
+	matchers := labels.Matchers{labels.MustNewMatcher(labels.MatchEqual, "env", "prod")}
+	intervals := []timeinterval.TimeInterval{{Weekdays: []timeinterval.WeekdayRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: 0, End: 0}}}}}
+
+	testCases := []struct {
+		name    string
+		silence RecurringSilence
+		err     string
+	}{
+		{name: "cron", silence: RecurringSilence{Name: "a", Matchers: matchers, Cron: "0 2 * * 0", Duration: time.Hour}},
+		{name: "cron with time zone", silence: RecurringSilence{Name: "a", Matchers: matchers, Cron: "CRON_TZ=Europe/Paris 0 2 * * 0", Duration: time.Hour}},
+		{name: "time intervals", silence: RecurringSilence{Name: "a", Matchers: matchers, TimeIntervals: intervals}},
+		{name: "without name", silence: RecurringSilence{Matchers: matchers, Cron: "0 2 * * 0", Duration: time.Hour}, err: "name must not be empty"},
+		{name: "without matchers", silence: RecurringSilence{Name: "a", Cron: "0 2 * * 0", Duration: time.Hour}, err: "at least one matcher is required"},
+		{name: "without schedule", silence: RecurringSilence{Name: "a", Matchers: matchers}, err: "either cron or time intervals are required"},
+		{name: "with both schedules", silence: RecurringSilence{Name: "a", Matchers: matchers, Cron: "0 2 * * 0", Duration: time.Hour, TimeIntervals: intervals}, err: "cannot be used together"},
+		{name: "invalid cron", silence: RecurringSilence{Name: "a", Matchers: matchers, Cron: "every sunday", Duration: time.Hour}, err: "invalid cron expression"},
+		{name: "cron without duration", silence: RecurringSilence{Name: "a", Matchers: matchers, Cron: "0 2 * * 0"}, err: "duration must be positive"},
+		{name: "time intervals with duration", silence: RecurringSilence{Name: "a", Matchers: matchers, TimeIntervals: intervals, Duration: time.Hour}, err: "duration cannot be used"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.silence.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestRecurringSilence_Occurrences(t *testing.T) {
+	// Sunday
+	sunday := time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)
+	at := func(days, hours int) time.Time {
+		return sunday.Add(time.Duration(days)*24*time.Hour + time.Duration(hours)*time.Hour)
+	}
+
+	t.Run("cron", func(t *testing.T) {
+		s := RecurringSilence{Cron: "0 2 * * 0", Duration: 2 * time.Hour}
+
+		windows, err := s.Occurrences(at(-1, 0), at(14, 0))
+		require.NoError(t, err)
+		require.Equal(t, []SilenceWindow{
+			{StartsAt: at(0, 2), EndsAt: at(0, 4)},
+			{StartsAt: at(7, 2), EndsAt: at(7, 4)},
+		}, windows)
+
+		// An occurrence in progress is returned with its start.
+		windows, err = s.Occurrences(at(0, 3), at(1, 0))
+		require.NoError(t, err)
+		require.Equal(t, []SilenceWindow{{StartsAt: at(0, 2), EndsAt: at(0, 4)}}, windows)
+
+		windows, err = s.Occurrences(at(0, 4), at(1, 0))
+		require.NoError(t, err)
+		require.Empty(t, windows)
+	})
+
+	t.Run("cron with time zone", func(t *testing.T) {
+		s := RecurringSilence{Cron: "CRON_TZ=Europe/Paris 0 2 * * 0", Duration: 2 * time.Hour}
+
+		windows, err := s.Occurrences(at(-1, 0), at(1, 0))
+		require.NoError(t, err)
+		require.Len(t, windows, 1)
+		require.True(t, at(0, 1).Equal(windows[0].StartsAt))
+	})
+
+	t.Run("time intervals", func(t *testing.T) {
+		s := RecurringSilence{TimeIntervals: []timeinterval.TimeInterval{{
+			Weekdays: []timeinterval.WeekdayRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: 0, End: 0}}},
+			Times:    []timeinterval.TimeRange{{StartMinute: 2 * 60, EndMinute: 4 * 60}},
+		}}}
+
+		require.Equal(t, []SilenceWindow{
+			{StartsAt: at(0, 2), EndsAt: at(0, 4)},
+			{StartsAt: at(7, 2), EndsAt: at(7, 4)},
+		}, mustOccurrences(t, s, at(-1, 0), at(14, 0)))
+		require.Equal(t, []SilenceWindow{{StartsAt: at(0, 2), EndsAt: at(0, 4)}}, mustOccurrences(t, s, at(0, 3), at(1, 0)))
+		require.Empty(t, mustOccurrences(t, s, at(0, 4), at(1, 0)))
+	})
+}
+
+func mustOccurrences(t *testing.T, s RecurringSilence, from, to time.Time) []SilenceWindow {
+	t.Helper()
+	windows, err := s.Occurrences(from, to)
+	require.NoError(t, err)
+	return windows
+}
@@ -523,6 +523,12 @@ func (a *AlertRuleMutators) WithMissingSeriesEvalsToResolve(timesOfInterval int)
 	}
 }
 
+func (a *AlertRuleMutators) WithDependsOn(uids ...string) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.DependsOn = uids
+	}
+}
+
 func (a *AlertRuleMutators) WithNotificationSettingsGen(ns func() NotificationSettings) AlertRuleMutator {
 	return func(rule *AlertRule) {
 		rule.NotificationSettings = []NotificationSettings{ns()}
@@ -843,6 +849,57 @@ func CreateHysteresisExpression(t *testing.T, refID string, inputRefID string, t
 	return q
 }
 
+// CreateMultiLevelThresholdExpression creates a threshold expression with a "warning" level and a "critical" level,
+// where the critical level recovers below the recovery threshold.
+func CreateMultiLevelThresholdExpression(t *testing.T, refID string, inputRefID string, warning int, critical int, recoveryThreshold int) AlertQuery {
+	t.Helper()
+	q := AlertQuery{
+		RefID:         refID,
+		QueryType:     expr.DatasourceType,
+		DatasourceUID: expr.DatasourceUID,
+		Model: json.RawMessage(fmt.Sprintf(`
+		{
+			"refId": "%[1]s",
+			"type": "threshold",
+			"datasource": {
+				"uid": "%[6]s",
+				"type": "%[7]s"
+			},
+			"expression": "%[2]s",
+			"conditions": [
+				{
+					"severity": "warning",
+					"evaluator": {
+						"params": [
+							%[3]d
+						],
+						"type": "gt"
+					}
+				},
+				{
+					"severity": "critical",
+					"evaluator": {
+						"params": [
+							%[4]d
+						],
+						"type": "gt"
+					},
+					"unloadEvaluator": {
+						"params": [
+							%[5]d
+						],
+						"type": "lt"
+					}
+				}
+			]
+		}`, refID, inputRefID, warning, critical, recoveryThreshold, expr.DatasourceUID, expr.DatasourceType)),
+	}
+	m, err := q.IsMultiLevelThresholdExpression()
+	require.NoError(t, err)
+	require.Truef(t, m, "test model is expected to be a multi-level threshold expression")
+	return q
+}
+
 func GenerateMetadata() AlertRuleMetadata {
 	return AlertRuleMetadata{
 		EditorSettings: EditorSettings{
@@ -1380,6 +1437,7 @@ func ConvertToRecordingRule(rule *AlertRule) {
 	rule.For = 0
 	rule.NotificationSettings = nil
 	rule.MissingSeriesEvalsToResolve = nil
+	rule.DependsOn = nil
 }
 
 func nameToUid(name string) string { // Avoid legacy_storage.NameToUid import cycle.
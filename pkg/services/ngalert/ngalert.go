@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/httpclient"
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/annotations"
@@ -149,6 +150,7 @@ type AlertNG struct {
 	RecordingWriter       schedule.RecordingWriter
 	schedule              schedule.ScheduleService
 	stateManager          *state.Manager
+	historian             Historian
 	folderService         folder.Service
 	dashboardService      dashboards.DashboardService
 	Api                   *api.API
@@ -161,6 +163,8 @@ type AlertNG struct {
 	// Alerting notification services
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
 	AlertsRouter         *sender.AlertsRouter
+	// recurringSilences creates the silences of recurring silences in the Alertmanagers.
+	recurringSilences    *notifier.RecurringSilenceSyncer
 	accesscontrol        accesscontrol.AccessControl
 	AccesscontrolService accesscontrol.Service
 	ResourcePermissions  accesscontrol.ReceiverPermissionsService
@@ -320,6 +324,7 @@ func (ng *AlertNG) init() error {
 		return err
 	}
 	ng.MultiOrgAlertmanager = moa
+	ng.recurringSilences = notifier.NewRecurringSilenceSyncer(ng.store, ng.store, moa, ng.Cfg.UnifiedAlerting.RecurringSilencesLookahead, clock.New(), log.New("ngalert.recurring-silences"))
 
 	imageService, err := image.NewScreenshotImageServiceFromCfg(ng.Cfg, ng.store, ng.dashboardService, ng.renderService, ng.Metrics.Registerer)
 	if err != nil {
@@ -378,6 +383,19 @@ func (ng *AlertNG) init() error {
 		FeatureToggles:       ng.FeatureToggles,
 	}
 
+	if ng.Cfg.UnifiedAlerting.HAEvaluationSharding {
+		// The periodic persister replaces all alert instances in the database with the ones of this instance,
+		// which would remove the state of the rules evaluated by the other instances.
+		if ng.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingSaveStatePeriodic) && !ng.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingSaveStateCompressed) {
+			return fmt.Errorf("'ha_evaluation_sharding' cannot be used together with the %s feature flag", featuremgmt.FlagAlertingSaveStatePeriodic)
+		}
+		if members := ng.MultiOrgAlertmanager.ClusterMembers(); members != nil {
+			schedCfg.ClusterMembers = members
+		} else {
+			ng.Log.Warn("Evaluation sharding is enabled but Grafana does not run in high availability mode, all alert rules are evaluated by this instance")
+		}
+	}
+
 	history, err := configureHistorianBackend(
 		initCtx,
 		ng.Cfg.UnifiedAlerting.StateHistory,
@@ -389,6 +407,8 @@ func (ng *AlertNG) init() error {
 		ng.tracer,
 		ac.NewRuleService(ng.accesscontrol),
 		ng.DataSourceService,
+		ng.ExpressionService,
+		ng.SQLStore,
 		ng.httpClientProvider,
 		ng.pluginContextProvider,
 		clk,
@@ -397,6 +417,7 @@ func (ng *AlertNG) init() error {
 	if err != nil {
 		return err
 	}
+	ng.historian = history
 
 	ng.InstanceStore, ng.StartupInstanceReader = initInstanceStore(ng.store.SQLStore, ng.Log, ng.FeatureToggles)
 
@@ -456,11 +477,13 @@ func (ng *AlertNG) init() error {
 	contactPointService := provisioning.NewContactPointService(configStore, ng.SecretsService, ng.store, ng.store, provisioningReceiverService, ng.Log, ng.store, ng.ResourcePermissions)
 	templateService := provisioning.NewTemplateService(configStore, ng.store, ng.store, ng.Log)
 	muteTimingService := provisioning.NewMuteTimingService(configStore, ng.store, ng.store, ng.Log, ng.store)
+	recurringSilenceService := provisioning.NewRecurringSilenceService(ng.store, ng.store, ng.store, ng.Log)
 	alertRuleService := provisioning.NewAlertRuleService(ng.store, ng.store, ng.folderService, ng.QuotaService, ng.store,
 		int64(ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
 		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()),
 		ng.Cfg.UnifiedAlerting.RulesPerRuleGroupLimit, ng.Log, notifier.NewNotificationSettingsValidationService(ng.store),
 		ac.NewRuleService(ng.accesscontrol))
+	alertRuleTemplateService := provisioning.NewAlertRuleTemplateService(ng.store, ng.store, ng.store, alertRuleService, ng.Log)
 
 	ng.Api = &api.API{
 		Cfg:                  ng.Cfg,
@@ -483,6 +506,8 @@ func (ng *AlertNG) init() error {
 		ContactPointService:  contactPointService,
 		Templates:            templateService,
 		MuteTimings:          muteTimingService,
+		RecurringSilences:    recurringSilenceService,
+		AlertRuleTemplates:   alertRuleTemplateService,
 		AlertRules:           alertRuleService,
 		AlertsRouter:         alertsRouter,
 		EvaluatorFactory:     evalFactory,
@@ -493,6 +518,7 @@ func (ng *AlertNG) init() error {
 		Hooks:                api.NewHooks(ng.Log),
 		Tracer:               ng.tracer,
 		UserService:          ng.userService,
+		RecordingWriter:      ng.RecordingWriter,
 	}
 	ng.Api.RegisterAPIEndpoints(ng.Metrics.GetAPIMetrics())
 
@@ -594,6 +620,14 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	children.Go(func() error {
 		return ng.AlertsRouter.Run(subCtx)
 	})
+	children.Go(func() error {
+		return ng.recurringSilences.Run(subCtx)
+	})
+	if bg, ok := ng.historian.(historian.BackgroundService); ok {
+		children.Go(func() error {
+			return bg.Run(subCtx)
+		})
+	}
 
 	if ng.Cfg.UnifiedAlerting.ExecuteAlerts {
 		// Only Warm() the state manager if we are actually executing alerts.
@@ -648,6 +682,8 @@ func configureHistorianBackend(
 	tracer tracing.Tracer,
 	ac historian.AccessControl,
 	datasourceService datasources.DataSourceService,
+	expressionService historian.ExpressionService,
+	sqlStore db.DB,
 	httpClientProvider httpclient.Provider,
 	pluginContextProvider *plugincontext.Provider,
 	clock clock.Clock,
@@ -667,7 +703,7 @@ func configureHistorianBackend(
 	if backend == historian.BackendTypeMultiple {
 		primaryCfg := cfg
 		primaryCfg.Backend = cfg.MultiPrimary
-		primary, err := configureHistorianBackend(ctx, primaryCfg, ar, ds, rs, met, l, tracer, ac, datasourceService, httpClientProvider, pluginContextProvider, clock, mw)
+		primary, err := configureHistorianBackend(ctx, primaryCfg, ar, ds, rs, met, l, tracer, ac, datasourceService, expressionService, sqlStore, httpClientProvider, pluginContextProvider, clock, mw)
 		if err != nil {
 			return nil, fmt.Errorf("multi-backend target \"%s\" was misconfigured: %w", cfg.MultiPrimary, err)
 		}
@@ -676,7 +712,7 @@ func configureHistorianBackend(
 		for _, b := range cfg.MultiSecondaries {
 			secCfg := cfg
 			secCfg.Backend = b
-			sec, err := configureHistorianBackend(ctx, secCfg, ar, ds, rs, met, l, tracer, ac, datasourceService, httpClientProvider, pluginContextProvider, clock, mw)
+			sec, err := configureHistorianBackend(ctx, secCfg, ar, ds, rs, met, l, tracer, ac, datasourceService, expressionService, sqlStore, httpClientProvider, pluginContextProvider, clock, mw)
 			if err != nil {
 				return nil, fmt.Errorf("multi-backend target \"%s\" was miconfigured: %w", b, err)
 			}
@@ -724,11 +760,18 @@ func configureHistorianBackend(
 		if w == nil {
 			return nil, fmt.Errorf("failed to create alert state metrics writer")
 		}
-		backend := historian.NewRemotePrometheusBackend(pcfg, w, prometheusBackendLogger, met)
+		q := historian.NewDatasourceQuerier(pcfg.DatasourceUID, datasourceService, expressionService)
+		backend := historian.NewRemotePrometheusBackend(pcfg, w, q, prometheusBackendLogger, met, rs, ac)
 
 		return backend, nil
 	}
 
+	if backend == historian.BackendTypeSQL {
+		logCtx := log.WithContextualAttributes(ctx, []any{"backend", "sql"})
+		sqlBackendLogger := log.New("ngalert.state.historian").FromContext(logCtx)
+		return historian.NewSQLBackend(historian.NewSQLConfig(cfg), sqlStore, rs, ac, serverlock.ProvideService(sqlStore, tracer), clock, met, sqlBackendLogger), nil
+	}
+
 	return nil, fmt.Errorf("unrecognized state history backend: %s", backend)
 }
 
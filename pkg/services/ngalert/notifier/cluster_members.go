@@ -0,0 +1,34 @@
+package notifier
+
+import (
+	"slices"
+
+	alertingCluster "github.com/grafana/alerting/cluster"
+)
+
+// ClusterMembers provides the live members of the cluster of Grafana instances running in high availability mode.
+type ClusterMembers interface {
+	// Name returns the name of this instance in the cluster.
+	Name() string
+	// Members returns the names of all live members of the cluster, including this instance.
+	Members() []string
+}
+
+// gossipMembers provides the members of a cluster that uses the gossip protocol.
+type gossipMembers struct {
+	peer *alertingCluster.Peer
+}
+
+func (g gossipMembers) Name() string {
+	return g.peer.Name()
+}
+
+func (g gossipMembers) Members() []string {
+	nodes := g.peer.Peers()
+	members := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		members = append(members, n.Name)
+	}
+	slices.Sort(members)
+	return members
+}
@@ -424,6 +424,19 @@ func (moa *MultiOrgAlertmanager) StopAndWait() {
 	}
 }
 
+// ClusterMembers returns the members of the cluster the Alertmanagers of this instance are part of.
+// It returns nil if Grafana does not run in high availability mode.
+func (moa *MultiOrgAlertmanager) ClusterMembers() ClusterMembers {
+	switch p := moa.peer.(type) {
+	case *alertingCluster.Peer:
+		return gossipMembers{peer: p}
+	case *redisPeer:
+		return p
+	default:
+		return nil
+	}
+}
+
 // AlertmanagerFor returns the Alertmanager instance for the organization provided.
 // When the organization does not have an active Alertmanager, it returns a ErrNoAlertmanagerForOrg.
 // When the Alertmanager of the organization is not ready, it returns a ErrAlertmanagerNotReady.
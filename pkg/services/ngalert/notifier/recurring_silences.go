@@ -0,0 +1,214 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// RecurringSilenceCreatedByPrefix is the prefix of the creator of the silences materialized from a recurring silence.
+	// It is followed by the UID of the recurring silence.
+	RecurringSilenceCreatedByPrefix = "recurring-silence/"
+
+	recurringSilenceSyncInterval = time.Minute
+)
+
+// RecurringSilenceStore is the store of the definitions of recurring silences.
+type RecurringSilenceStore interface {
+	ListRecurringSilences(ctx context.Context, query *models.ListRecurringSilencesQuery) ([]*models.RecurringSilence, error)
+}
+
+// RecurringSilenceSyncer materializes the occurrences of recurring silences into alertmanager silences. Every minute,
+// it creates the silences of the occurrences that start within the lookahead window and expires the silences whose
+// occurrence no longer exists, for example because the recurring silence was updated or deleted.
+//
+// Each replica of Grafana runs the syncer. Silences created concurrently by several replicas are deduplicated once the
+// alertmanager state is replicated.
+type RecurringSilenceSyncer struct {
+	store     RecurringSilenceStore
+	orgStore  store.OrgStore
+	silences  SilenceStore
+	lookahead time.Duration
+	clock     clock.Clock
+	log       log.Logger
+}
+
+func NewRecurringSilenceSyncer(store RecurringSilenceStore, orgStore store.OrgStore, silences SilenceStore, lookahead time.Duration, clock clock.Clock, log log.Logger) *RecurringSilenceSyncer {
+	return &RecurringSilenceSyncer{
+		store:     store,
+		orgStore:  orgStore,
+		silences:  silences,
+		lookahead: lookahead,
+		clock:     clock,
+		log:       log,
+	}
+}
+
+// Run synchronizes the silences every minute until the context is cancelled.
+func (s *RecurringSilenceSyncer) Run(ctx context.Context) error {
+	ticker := s.clock.Ticker(recurringSilenceSyncInterval)
+	defer ticker.Stop()
+	s.Sync(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.Sync(ctx)
+		}
+	}
+}
+
+// Sync synchronizes the silences of the recurring silences of all organizations.
+func (s *RecurringSilenceSyncer) Sync(ctx context.Context) {
+	definitions, err := s.store.ListRecurringSilences(ctx, &models.ListRecurringSilencesQuery{})
+	if err != nil {
+		s.log.Error("Failed to list recurring silences", "error", err)
+		return
+	}
+	byOrg := make(map[int64][]*models.RecurringSilence)
+	for _, d := range definitions {
+		byOrg[d.OrgID] = append(byOrg[d.OrgID], d)
+	}
+
+	// Organizations without recurring silences may still have silences of deleted recurring silences.
+	orgIDs, err := s.orgStore.FetchOrgIds(ctx)
+	if err != nil {
+		s.log.Error("Failed to fetch organizations", "error", err)
+		return
+	}
+	now := s.clock.Now()
+	for _, orgID := range orgIDs {
+		if err := s.syncOrg(ctx, orgID, byOrg[orgID], now); err != nil {
+			s.log.Warn("Failed to sync recurring silences", "org", orgID, "error", err)
+		}
+	}
+}
+
+func (s *RecurringSilenceSyncer) syncOrg(ctx context.Context, orgID int64, definitions []*models.RecurringSilence, now time.Time) error {
+	logger := s.log.New("org", orgID)
+
+	desired := make(map[string]models.Silence)
+	for _, d := range definitions {
+		windows, err := d.Occurrences(now, now.Add(s.lookahead))
+		if err != nil {
+			logger.Error("Failed to compute the occurrences of recurring silence", "uid", d.UID, "error", err)
+			continue
+		}
+		for _, w := range windows {
+			silence := recurringSilenceToSilence(d, w)
+			desired[materializedSilenceKey(silence)] = silence
+		}
+	}
+
+	existing, err := s.silences.ListSilences(ctx, orgID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list silences: %w", err)
+	}
+	// Keep the silence with the lowest ID of each occurrence, so that all replicas agree on the duplicates to expire.
+	slices.SortFunc(existing, func(a, b *models.Silence) int {
+		return strings.Compare(stringOrEmpty(a.ID), stringOrEmpty(b.ID))
+	})
+	materialized := make(map[string]struct{})
+	for _, silence := range existing {
+		if !isMaterializedSilence(silence) {
+			continue
+		}
+		key := materializedSilenceKey(*silence)
+		_, isDesired := desired[key]
+		_, isDuplicate := materialized[key]
+		if isDesired && !isDuplicate {
+			materialized[key] = struct{}{}
+			continue
+		}
+		if err := s.silences.DeleteSilence(ctx, orgID, *silence.ID); err != nil {
+			logger.Warn("Failed to expire silence of recurring silence", "silence", *silence.ID, "createdBy", *silence.CreatedBy, "error", err)
+			continue
+		}
+		logger.Debug("Expired silence of recurring silence", "silence", *silence.ID, "createdBy", *silence.CreatedBy, "duplicate", isDuplicate)
+	}
+
+	for key, silence := range desired {
+		if _, ok := materialized[key]; ok {
+			continue
+		}
+		id, err := s.silences.CreateSilence(ctx, orgID, silence)
+		if err != nil {
+			logger.Warn("Failed to create silence of recurring silence", "createdBy", *silence.CreatedBy, "startsAt", silence.StartsAt, "endsAt", silence.EndsAt, "error", err)
+			continue
+		}
+		logger.Debug("Created silence of recurring silence", "silence", id, "createdBy", *silence.CreatedBy, "startsAt", silence.StartsAt, "endsAt", silence.EndsAt)
+	}
+	return nil
+}
+
+func recurringSilenceToSilence(d *models.RecurringSilence, w models.SilenceWindow) models.Silence {
+	matchers := make(amv2.Matchers, 0, len(d.Matchers))
+	for _, m := range d.Matchers {
+		matchers = append(matchers, &amv2.Matcher{
+			Name:    util.Pointer(m.Name),
+			Value:   util.Pointer(m.Value),
+			IsRegex: util.Pointer(m.Type == labels.MatchRegexp || m.Type == labels.MatchNotRegexp),
+			IsEqual: util.Pointer(m.Type == labels.MatchRegexp || m.Type == labels.MatchEqual),
+		})
+	}
+	comment := d.Comment
+	if comment == "" {
+		comment = d.Name
+	}
+	return models.Silence{
+		Silence: amv2.Silence{
+			Comment:   util.Pointer(comment),
+			CreatedBy: util.Pointer(RecurringSilenceCreatedByPrefix + d.UID),
+			StartsAt:  util.Pointer(strfmt.DateTime(w.StartsAt)),
+			EndsAt:    util.Pointer(strfmt.DateTime(w.EndsAt)),
+			Matchers:  matchers,
+		},
+	}
+}
+
+func isMaterializedSilence(s *models.Silence) bool {
+	if s.ID == nil || s.CreatedBy == nil || !strings.HasPrefix(*s.CreatedBy, RecurringSilenceCreatedByPrefix) {
+		return false
+	}
+	return s.Status == nil || s.Status.State == nil || *s.Status.State != amv2.SilenceStatusStateExpired
+}
+
+// materializedSilenceKey identifies the occurrence of a recurring silence that a silence was created for, and the content
+// of the silence so that changed recurring silences replace their silences. The start of the silence is not part of the
+// key because the alertmanager moves the start of silences created in the past to the time they are created.
+func materializedSilenceKey(s models.Silence) string {
+	matchers := make([]string, 0, len(s.Matchers))
+	for _, m := range s.Matchers {
+		if m == nil || m.Name == nil || m.Value == nil {
+			continue
+		}
+		matchers = append(matchers, fmt.Sprintf("%s|%t|%t|%s", *m.Name, m.IsEqual == nil || *m.IsEqual, m.IsRegex != nil && *m.IsRegex, *m.Value))
+	}
+	slices.Sort(matchers)
+	var endsAt int64
+	if s.EndsAt != nil {
+		endsAt = time.Time(*s.EndsAt).Unix()
+	}
+	return fmt.Sprintf("%s|%d|%s|%s", stringOrEmpty(s.CreatedBy), endsAt, strings.Join(matchers, ","), stringOrEmpty(s.Comment))
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
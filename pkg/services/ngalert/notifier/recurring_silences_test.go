@@ -0,0 +1,116 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/go-openapi/strfmt"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	ngfakes "github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type fakeRecurringSilenceStore struct {
+	silences []*models.RecurringSilence
+}
+
+func (f *fakeRecurringSilenceStore) ListRecurringSilences(_ context.Context, _ *models.ListRecurringSilencesQuery) ([]*models.RecurringSilence, error) {
+	return f.silences, nil
+}
+
+func TestRecurringSilenceSyncer(t *testing.T) {
+	// Saturday
+	now := time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC)
+	sunday := time.Date(2024, 1, 7, 2, 0, 0, 0, time.UTC)
+	maintenance := &models.RecurringSilence{
+		UID:      "maintenance",
+		OrgID:    1,
+		Name:     "Weekly maintenance",
+		Matchers: labels.Matchers{labels.MustNewMatcher(labels.MatchEqual, "env", "prod")},
+		Cron:     "0 2 * * 0",
+		Duration: 2 * time.Hour,
+	}
+
+	newSyncer := func(t *testing.T, definitions ...*models.RecurringSilence) (*RecurringSilenceSyncer, *ngfakes.FakeSilenceStore) {
+		silences := &ngfakes.FakeSilenceStore{Silences: map[string]*models.Silence{}}
+		clk := clock.NewMock()
+		clk.Set(now)
+		return NewRecurringSilenceSyncer(&fakeRecurringSilenceStore{silences: definitions}, NewFakeOrgStore(t, []int64{1}), silences, 24*time.Hour, clk, log.NewNopLogger()), silences
+	}
+	materialized := func(store *ngfakes.FakeSilenceStore) []*models.Silence {
+		var result []*models.Silence
+		for _, s := range store.Silences {
+			if isMaterializedSilence(s) {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+
+	t.Run("should create the silences of the occurrences within the lookahead", func(t *testing.T) {
+		syncer, silences := newSyncer(t, maintenance)
+		syncer.Sync(context.Background())
+
+		result := materialized(silences)
+		require.Len(t, result, 1)
+		require.Equal(t, RecurringSilenceCreatedByPrefix+"maintenance", *result[0].CreatedBy)
+		require.Equal(t, "Weekly maintenance", *result[0].Comment)
+		require.Equal(t, sunday, time.Time(*result[0].StartsAt))
+		require.Equal(t, sunday.Add(2*time.Hour), time.Time(*result[0].EndsAt))
+		require.Len(t, result[0].Matchers, 1)
+		require.Equal(t, "env", *result[0].Matchers[0].Name)
+
+		syncer.Sync(context.Background())
+		require.Len(t, materialized(silences), 1)
+	})
+
+	t.Run("should replace the silences of updated recurring silences", func(t *testing.T) {
+		syncer, silences := newSyncer(t, maintenance)
+		syncer.Sync(context.Background())
+
+		updated := *maintenance
+		updated.Duration = time.Hour
+		syncer.store = &fakeRecurringSilenceStore{silences: []*models.RecurringSilence{&updated}}
+		syncer.Sync(context.Background())
+
+		result := materialized(silences)
+		require.Len(t, result, 1)
+		require.Equal(t, sunday.Add(time.Hour), time.Time(*result[0].EndsAt))
+	})
+
+	t.Run("should expire the silences of deleted recurring silences", func(t *testing.T) {
+		syncer, silences := newSyncer(t, maintenance)
+		syncer.Sync(context.Background())
+		require.Len(t, materialized(silences), 1)
+
+		syncer.store = &fakeRecurringSilenceStore{}
+		syncer.Sync(context.Background())
+		require.Empty(t, materialized(silences))
+	})
+
+	t.Run("should expire duplicated silences and keep other silences", func(t *testing.T) {
+		syncer, silences := newSyncer(t, maintenance)
+		duplicate := recurringSilenceToSilence(maintenance, models.SilenceWindow{StartsAt: sunday, EndsAt: sunday.Add(2 * time.Hour)})
+		for _, id := range []string{"a", "b"} {
+			s := duplicate
+			s.ID = util.Pointer(id)
+			silences.Silences[id] = &s
+		}
+		manual := models.SilenceGen(models.SilenceMuts.WithMatcher("env", "prod", labels.MatchEqual))()
+		manual.EndsAt = util.Pointer(strfmt.DateTime(now.Add(time.Hour)))
+		silences.Silences[*manual.ID] = &manual
+
+		syncer.Sync(context.Background())
+
+		result := materialized(silences)
+		require.Len(t, result, 1)
+		require.Equal(t, "a", *result[0].ID)
+		require.Contains(t, silences.Silences, *manual.ID)
+	})
+}
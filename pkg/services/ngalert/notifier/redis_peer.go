@@ -405,6 +405,11 @@ func (p *redisPeer) GetHealthScore() int {
 	return 0
 }
 
+// Name returns the name of this peer in the cluster, as it appears in Members.
+func (p *redisPeer) Name() string {
+	return p.withPrefix(p.name)
+}
+
 // Members returns a list of active cluster Members.
 func (p *redisPeer) Members() []string {
 	p.membersMtx.Lock()
@@ -0,0 +1,223 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// RoutingSimulation is the result of routing an alert through the notification policy tree of an organization.
+type RoutingSimulation struct {
+	// Routes are the routes matched by the alert, in the order they are matched.
+	Routes []SimulatedRoute
+	// Silences are the silences that are active at the time of the simulation and match the alert.
+	Silences []*models.Silence
+	// Receivers are the receivers of the matched routes that are not muted at the time of the simulation.
+	// Silences are not taken into account.
+	Receivers []string
+}
+
+// SimulatedRoute is a route matched by an alert with the settings that apply to its notifications, which are inherited
+// from the parent routes if the route does not override them.
+type SimulatedRoute struct {
+	// Path is the list of routes from the root of the tree to the matched route.
+	Path                []SimulatedRouteStep
+	Receiver            string
+	GroupBy             []string
+	GroupWait           time.Duration
+	GroupInterval       time.Duration
+	RepeatInterval      time.Duration
+	MuteTimeIntervals   []string
+	ActiveTimeIntervals []string
+	// ActiveMuteTimeIntervals are the mute time intervals of the route that are active at the time of the simulation.
+	ActiveMuteTimeIntervals []string
+	// Muted is true if the route is muted by a mute time interval, or is outside all of its active time intervals.
+	Muted bool
+	// Autogenerated is true if the route is one of the routes generated for the notification settings of alert rules.
+	Autogenerated bool
+}
+
+// SimulatedRouteStep is a route of the notification policy tree on the path to a matched route.
+type SimulatedRouteStep struct {
+	Receiver string
+	Matchers []string
+	Continue bool
+}
+
+// SimulateRouting routes an alert with the given labels through the notification policy tree of the organization,
+// including the autogenerated routes of alert rules with notification settings. The silences that match the alert are
+// looked up in the given silences, so that callers can restrict them to the silences visible to the user.
+// No notification is sent.
+func (moa *MultiOrgAlertmanager) SimulateRouting(ctx context.Context, orgID int64, lset model.LabelSet, at time.Time, silences []*models.Silence) (*RoutingSimulation, error) {
+	cfg, err := moa.GetAlertmanagerConfiguration(ctx, orgID, true)
+	if err != nil {
+		return nil, err
+	}
+	return simulateRouting(&cfg.AlertmanagerConfig, silences, lset, at)
+}
+
+func simulateRouting(cfg *definitions.GettableApiAlertingConfig, silences []*models.Silence, lset model.LabelSet, at time.Time) (*RoutingSimulation, error) {
+	if cfg.Route == nil {
+		return nil, errors.New("the configuration has no notification policy tree")
+	}
+	intervals := make(map[string][]timeinterval.TimeInterval, len(cfg.MuteTimeIntervals)+len(cfg.TimeIntervals))
+	for _, mt := range cfg.MuteTimeIntervals {
+		intervals[mt.Name] = mt.TimeIntervals
+	}
+	for _, ti := range cfg.TimeIntervals {
+		intervals[ti.Name] = ti.TimeIntervals
+	}
+
+	result := &RoutingSimulation{
+		Routes:    []SimulatedRoute{},
+		Silences:  []*models.Silence{},
+		Receivers: []string{},
+	}
+	root := dispatch.NewRoute(cfg.Route.AsAMRoute(), nil)
+	for _, path := range matchRoutePaths(root, lset, nil) {
+		route := newSimulatedRoute(path, intervals, at)
+		result.Routes = append(result.Routes, route)
+		if !route.Muted && !slices.Contains(result.Receivers, route.Receiver) {
+			result.Receivers = append(result.Receivers, route.Receiver)
+		}
+	}
+
+	for _, s := range silences {
+		matches, err := silenceMatches(s, lset, at)
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			result.Silences = append(result.Silences, s)
+		}
+	}
+	return result, nil
+}
+
+// matchRoutePaths returns the paths from the given route to the routes matched by the label set. It follows the same
+// rules as dispatch.Route.Match, which only returns the matched routes.
+func matchRoutePaths(r *dispatch.Route, lset model.LabelSet, parents []*dispatch.Route) [][]*dispatch.Route {
+	if !r.Matchers.Matches(lset) {
+		return nil
+	}
+	path := append(slices.Clone(parents), r)
+
+	var matches [][]*dispatch.Route
+	for _, child := range r.Routes {
+		childMatches := matchRoutePaths(child, lset, path)
+		matches = append(matches, childMatches...)
+		if len(childMatches) > 0 && !child.Continue {
+			break
+		}
+	}
+	// If no child route matches, the route itself is the match.
+	if len(matches) == 0 {
+		matches = append(matches, path)
+	}
+	return matches
+}
+
+func newSimulatedRoute(path []*dispatch.Route, intervals map[string][]timeinterval.TimeInterval, at time.Time) SimulatedRoute {
+	leaf := path[len(path)-1]
+	opts := leaf.RouteOpts
+
+	route := SimulatedRoute{
+		Path:                make([]SimulatedRouteStep, 0, len(path)),
+		Receiver:            opts.Receiver,
+		GroupBy:             []string{},
+		GroupWait:           opts.GroupWait,
+		GroupInterval:       opts.GroupInterval,
+		RepeatInterval:      opts.RepeatInterval,
+		MuteTimeIntervals:   opts.MuteTimeIntervals,
+		ActiveTimeIntervals: opts.ActiveTimeIntervals,
+	}
+	if opts.GroupByAll {
+		route.GroupBy = append(route.GroupBy, models.GroupByAll)
+	} else {
+		for l := range opts.GroupBy {
+			route.GroupBy = append(route.GroupBy, string(l))
+		}
+		slices.Sort(route.GroupBy)
+	}
+
+	for _, r := range path {
+		matchers := make([]string, 0, len(r.Matchers))
+		for _, m := range r.Matchers {
+			matchers = append(matchers, m.String())
+			if m.Name == models.AutogeneratedRouteLabel {
+				route.Autogenerated = true
+			}
+		}
+		route.Path = append(route.Path, SimulatedRouteStep{
+			Receiver: r.RouteOpts.Receiver,
+			Matchers: matchers,
+			Continue: r.Continue,
+		})
+	}
+
+	for _, name := range opts.MuteTimeIntervals {
+		if timeIntervalsContain(intervals[name], at) {
+			route.ActiveMuteTimeIntervals = append(route.ActiveMuteTimeIntervals, name)
+		}
+	}
+	route.Muted = len(route.ActiveMuteTimeIntervals) > 0
+	if len(opts.ActiveTimeIntervals) > 0 && !slices.ContainsFunc(opts.ActiveTimeIntervals, func(name string) bool {
+		return timeIntervalsContain(intervals[name], at)
+	}) {
+		route.Muted = true
+	}
+	return route
+}
+
+func timeIntervalsContain(intervals []timeinterval.TimeInterval, t time.Time) bool {
+	for _, ti := range intervals {
+		if ti.ContainsTime(t.UTC()) {
+			return true
+		}
+	}
+	return false
+}
+
+// silenceMatches returns true if the silence is active at the given time and all of its matchers match the label set.
+func silenceMatches(s *models.Silence, lset model.LabelSet, at time.Time) (bool, error) {
+	if s.StartsAt == nil || s.EndsAt == nil {
+		return false, nil
+	}
+	if at.Before(time.Time(*s.StartsAt)) || !at.Before(time.Time(*s.EndsAt)) {
+		return false, nil
+	}
+	for _, m := range s.Matchers {
+		if m == nil || m.Name == nil || m.Value == nil {
+			continue
+		}
+		isEqual := m.IsEqual == nil || *m.IsEqual
+		isRegex := m.IsRegex != nil && *m.IsRegex
+		matchType := labels.MatchEqual
+		switch {
+		case isEqual && isRegex:
+			matchType = labels.MatchRegexp
+		case !isEqual && isRegex:
+			matchType = labels.MatchNotRegexp
+		case !isEqual:
+			matchType = labels.MatchNotEqual
+		}
+		matcher, err := labels.NewMatcher(matchType, *m.Name, *m.Value)
+		if err != nil {
+			return false, fmt.Errorf("invalid matcher in silence %s: %w", stringOrEmpty(s.ID), err)
+		}
+		if !matcher.Matches(string(lset[model.LabelName(*m.Name)])) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
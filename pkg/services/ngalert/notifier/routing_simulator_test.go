@@ -0,0 +1,154 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestSimulateRouting(t *testing.T) {
+	noon := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	night := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+
+	matcher := func(key, val string) definitions.ObjectMatchers {
+		m, err := labels.NewMatcher(labels.MatchEqual, key, val)
+		require.NoError(t, err)
+		return definitions.ObjectMatchers{m}
+	}
+	newConfig := func() *definitions.GettableApiAlertingConfig {
+		cfg := &definitions.GettableApiAlertingConfig{}
+		cfg.Route = &definitions.Route{
+			Receiver:   "default",
+			GroupByStr: []string{"alertname"},
+			GroupBy:    []model.LabelName{"alertname"},
+			Routes: []*definitions.Route{
+				{
+					Receiver:       "team-a",
+					ObjectMatchers: matcher("team", "a"),
+					GroupWait:      util.Pointer(model.Duration(time.Minute)),
+					Continue:       true,
+				},
+				{
+					Receiver:          "pager",
+					ObjectMatchers:    matcher("severity", "critical"),
+					MuteTimeIntervals: []string{"night"},
+				},
+				{
+					Receiver:            "team-b",
+					ObjectMatchers:      matcher("team", "b"),
+					ActiveTimeIntervals: []string{"night"},
+				},
+			},
+		}
+		cfg.MuteTimeIntervals = []config.MuteTimeInterval{
+			{
+				Name:          "night",
+				TimeIntervals: []timeinterval.TimeInterval{{Times: []timeinterval.TimeRange{{StartMinute: 0, EndMinute: 6 * 60}}}},
+			},
+		}
+		return cfg
+	}
+	receiversOf := func(routes []SimulatedRoute) []string {
+		result := make([]string, 0, len(routes))
+		for _, r := range routes {
+			result = append(result, r.Receiver)
+		}
+		return result
+	}
+
+	t.Run("should return all routes matched by the alert", func(t *testing.T) {
+		result, err := simulateRouting(newConfig(), nil, model.LabelSet{"team": "a", "severity": "critical"}, noon)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"team-a", "pager"}, receiversOf(result.Routes))
+		require.Equal(t, []string{"team-a", "pager"}, result.Receivers)
+
+		teamA := result.Routes[0]
+		require.Len(t, teamA.Path, 2)
+		require.Equal(t, "default", teamA.Path[0].Receiver)
+		require.Equal(t, []string{`team="a"`}, teamA.Path[1].Matchers)
+		require.True(t, teamA.Path[1].Continue)
+		require.Equal(t, time.Minute, teamA.GroupWait)
+		require.Equal(t, []string{"alertname"}, teamA.GroupBy)
+		require.False(t, teamA.Muted)
+		require.False(t, teamA.Autogenerated)
+	})
+
+	t.Run("should use the root route if no other route matches", func(t *testing.T) {
+		result, err := simulateRouting(newConfig(), nil, model.LabelSet{"team": "c"}, noon)
+		require.NoError(t, err)
+		require.Equal(t, []string{"default"}, receiversOf(result.Routes))
+		require.Len(t, result.Routes[0].Path, 1)
+	})
+
+	t.Run("should report the mute timings active at the given time", func(t *testing.T) {
+		result, err := simulateRouting(newConfig(), nil, model.LabelSet{"team": "a", "severity": "critical"}, night)
+		require.NoError(t, err)
+		require.True(t, result.Routes[1].Muted)
+		require.Equal(t, []string{"night"}, result.Routes[1].ActiveMuteTimeIntervals)
+		require.Equal(t, []string{"team-a"}, result.Receivers)
+	})
+
+	t.Run("should mute routes outside of their active time intervals", func(t *testing.T) {
+		result, err := simulateRouting(newConfig(), nil, model.LabelSet{"team": "b"}, noon)
+		require.NoError(t, err)
+		require.True(t, result.Routes[0].Muted)
+		require.Empty(t, result.Routes[0].ActiveMuteTimeIntervals)
+		require.Empty(t, result.Receivers)
+
+		result, err = simulateRouting(newConfig(), nil, model.LabelSet{"team": "b"}, night)
+		require.NoError(t, err)
+		require.False(t, result.Routes[0].Muted)
+		require.Equal(t, []string{"team-b"}, result.Receivers)
+	})
+
+	t.Run("should include autogenerated routes", func(t *testing.T) {
+		cfg := newConfig()
+		settings := models.NotificationSettings{Receiver: "team-b", MuteTimeIntervals: []string{"night"}}
+		autogen, err := generateRouteFromSettings(cfg.Route.Receiver, map[data.Fingerprint]models.NotificationSettings{settings.Fingerprint(): settings})
+		require.NoError(t, err)
+		require.NoError(t, autogen.addToRoute(cfg.Route))
+
+		lset := model.LabelSet{"team": "a"}
+		for k, v := range settings.ToLabels() {
+			lset[model.LabelName(k)] = model.LabelValue(v)
+		}
+		result, err := simulateRouting(cfg, nil, lset, night)
+		require.NoError(t, err)
+
+		require.Len(t, result.Routes, 1)
+		require.True(t, result.Routes[0].Autogenerated)
+		require.Equal(t, "team-b", result.Routes[0].Receiver)
+		require.Len(t, result.Routes[0].Path, 4)
+		require.True(t, result.Routes[0].Muted)
+	})
+
+	t.Run("should return the active silences that match the alert", func(t *testing.T) {
+		now := time.Now()
+		withMatchers := func(ms ...*labels.Matcher) models.Mutator[models.Silence] {
+			return func(s *models.Silence) {
+				s.Matchers = nil
+				for _, m := range ms {
+					models.SilenceMuts.WithMatcher(m.Name, m.Value, m.Type)(s)
+				}
+			}
+		}
+		matching := models.SilenceGen(withMatchers(&labels.Matcher{Name: "team", Value: "a|b", Type: labels.MatchRegexp}))()
+		notMatching := models.SilenceGen(withMatchers(&labels.Matcher{Name: "team", Value: "a", Type: labels.MatchNotEqual}))()
+		expired := models.SilenceGen(withMatchers(&labels.Matcher{Name: "team", Value: "a", Type: labels.MatchEqual}), models.SilenceMuts.Expired())()
+
+		result, err := simulateRouting(newConfig(), []*models.Silence{&matching, &notMatching, &expired}, model.LabelSet{"team": "a"}, now)
+		require.NoError(t, err)
+		require.Equal(t, []*models.Silence{&matching}, result.Silences)
+	})
+}
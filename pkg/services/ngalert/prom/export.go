@@ -0,0 +1,360 @@
+package prom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// RuleConversionError is returned for a Grafana rule that cannot be expressed as a Prometheus rule.
+type RuleConversionError struct {
+	UID    string
+	Title  string
+	Reason string
+}
+
+func (e *RuleConversionError) Error() string {
+	return fmt.Sprintf("rule '%s' (UID %s) cannot be converted to a Prometheus rule: %s", e.Title, e.UID, e.Reason)
+}
+
+// singleValueReducers are the reducers that return the value itself when they reduce a single value,
+// which is the case for every series of an instant query.
+var singleValueReducers = []mathexp.ReducerID{
+	mathexp.ReducerSum,
+	mathexp.ReducerMean,
+	mathexp.ReducerMin,
+	mathexp.ReducerMax,
+	mathexp.ReducerLast,
+	mathexp.ReducerFirst,
+	mathexp.ReducerMedian,
+	mathexp.ReducerPercentile,
+}
+
+// thresholdOperators are the PromQL comparison operators of the threshold functions that compare with a single value.
+var thresholdOperators = map[expr.ThresholdType]string{
+	expr.ThresholdIsAbove:            ">",
+	expr.ThresholdIsBelow:            "<",
+	expr.ThresholdIsEqual:            "==",
+	expr.ThresholdIsNotEqual:         "!=",
+	expr.ThresholdIsGreaterThanEqual: ">=",
+	expr.ThresholdIsLessThanEqual:    "<=",
+}
+
+// supportedNoDataStates are the no data states that behave like Prometheus, which resolves the alerts of the series
+// that are missing from the result of the query.
+var supportedNoDataStates = []models.NoDataState{
+	models.OK,
+}
+
+// supportedExecErrStates are the error states that have a Prometheus equivalent. Prometheus keeps the alerts of a rule
+// whose query fails, and the rules imported from Prometheus resolve them.
+var supportedExecErrStates = []models.ExecutionErrorState{
+	models.KeepLastErrState,
+	models.OkErrState,
+}
+
+// GrafanaRulesToPrometheus converts a group of Grafana-managed rules into a Prometheus rule group.
+//
+// Rules imported from Prometheus are converted back to their original definition. Other rules can be converted
+// if they run a single instant query in a Prometheus or Loki data source, optionally followed by a Reduce expression
+// and a Threshold expression. If some of the rules cannot be expressed as Prometheus rules, the returned error joins
+// a RuleConversionError for each of them.
+func GrafanaRulesToPrometheus(group string, rules []models.AlertRule) (PrometheusRuleGroup, error) {
+	result := PrometheusRuleGroup{
+		Name:  group,
+		Rules: make([]PrometheusRule, 0, len(rules)),
+	}
+	if len(rules) > 0 {
+		result.Interval = prommodel.Duration(time.Duration(rules[0].IntervalSeconds) * time.Second)
+	}
+
+	var errs []error
+	var queryOffset *time.Duration
+	for _, rule := range rules {
+		promRule, offset, err := grafanaRuleToPrometheus(rule)
+		// Prometheus only supports the query offset on the group level.
+		if err == nil && queryOffset != nil && offset != *queryOffset {
+			err = fmt.Errorf("the query offset %s is different from the query offset %s of the other rules in the group", offset, *queryOffset)
+		}
+		if err != nil {
+			errs = append(errs, &RuleConversionError{UID: rule.UID, Title: rule.Title, Reason: err.Error()})
+			continue
+		}
+		queryOffset = &offset
+		result.Rules = append(result.Rules, promRule)
+	}
+	if len(errs) > 0 {
+		return PrometheusRuleGroup{}, errors.Join(errs...)
+	}
+
+	if queryOffset != nil && *queryOffset > 0 {
+		offset := prommodel.Duration(*queryOffset)
+		result.QueryOffset = &offset
+	}
+	return result, nil
+}
+
+// grafanaRuleToPrometheus converts a Grafana rule into a Prometheus rule and returns the offset of its query.
+func grafanaRuleToPrometheus(rule models.AlertRule) (PrometheusRule, time.Duration, error) {
+	if definition, err := rule.PrometheusRuleDefinition(); err == nil {
+		var promRule PrometheusRule
+		if err := yaml.Unmarshal([]byte(definition), &promRule); err != nil {
+			return PrometheusRule{}, 0, fmt.Errorf("failed to unmarshal the original Prometheus rule definition: %w", err)
+		}
+		for _, q := range rule.Data {
+			if expr.NodeTypeFromDatasourceUID(q.DatasourceUID) == expr.TypeDatasourceNode {
+				return promRule, time.Duration(q.RelativeTimeRange.To), nil
+			}
+		}
+		return promRule, 0, nil
+	}
+
+	if rule.IsPaused {
+		return PrometheusRule{}, 0, errors.New("paused rules are not supported")
+	}
+
+	graph := newQueryGraph(rule.Data)
+	promRule := PrometheusRule{
+		Labels:      make(map[string]string, len(rule.Labels)),
+		Annotations: rule.Annotations,
+	}
+	for k, v := range rule.Labels {
+		if k == models.ConvertedPrometheusRuleLabel {
+			continue
+		}
+		promRule.Labels[k] = v
+	}
+	if len(promRule.Labels) == 0 {
+		promRule.Labels = nil
+	}
+
+	if rule.Type() == models.RuleTypeRecording {
+		e, err := graph.expression(rule.Record.From)
+		if err != nil {
+			return PrometheusRule{}, 0, err
+		}
+		if graph.thresholds > 0 {
+			return PrometheusRule{}, 0, errors.New("recording rules with a threshold expression are not supported")
+		}
+		promRule.Record = rule.Record.Metric
+		promRule.Expr = e
+		return promRule, graph.queryOffset, nil
+	}
+
+	if !slices.Contains(supportedNoDataStates, rule.NoDataState) {
+		return PrometheusRule{}, 0, fmt.Errorf("the no data state %s is not supported, only %s is supported", rule.NoDataState, models.OK)
+	}
+	if !slices.Contains(supportedExecErrStates, rule.ExecErrState) {
+		return PrometheusRule{}, 0, fmt.Errorf("the error state %s is not supported, only %s and %s are supported", rule.ExecErrState, models.KeepLastErrState, models.OkErrState)
+	}
+	e, err := graph.expression(rule.Condition)
+	if err != nil {
+		return PrometheusRule{}, 0, err
+	}
+	// Without a threshold, the rule fires for every series with a value other than zero.
+	if graph.thresholds == 0 {
+		e = fmt.Sprintf("(%s) != 0", e)
+	}
+	promRule.Alert = rule.Title
+	promRule.Expr = e
+	if rule.For > 0 {
+		forDuration := prommodel.Duration(rule.For)
+		promRule.For = &forDuration
+	}
+	if rule.KeepFiringFor > 0 {
+		keepFiringFor := prommodel.Duration(rule.KeepFiringFor)
+		promRule.KeepFiringFor = &keepFiringFor
+	}
+	return promRule, graph.queryOffset, nil
+}
+
+// queryGraph converts the queries and expressions of a rule into a Prometheus expression, starting from the node
+// that the condition or the recording refers to.
+type queryGraph struct {
+	nodes map[string]models.AlertQuery
+	// queryOffset is the offset of the data source query.
+	queryOffset time.Duration
+	// thresholds is the number of threshold expressions in the Prometheus expression.
+	thresholds int
+}
+
+type queryModel struct {
+	Datasource struct {
+		Type string `json:"type"`
+	} `json:"datasource"`
+	Expr      string `json:"expr"`
+	Instant   bool   `json:"instant"`
+	Range     bool   `json:"range"`
+	QueryType string `json:"queryType"`
+}
+
+type expressionModel struct {
+	Type       expr.QueryType `json:"type"`
+	Expression string         `json:"expression"`
+	Reducer    string         `json:"reducer"`
+	Settings   struct {
+		Mode string `json:"mode"`
+	} `json:"settings"`
+	Conditions []expr.ThresholdConditionJSON `json:"conditions"`
+}
+
+func newQueryGraph(queries []models.AlertQuery) *queryGraph {
+	nodes := make(map[string]models.AlertQuery, len(queries))
+	for _, q := range queries {
+		nodes[q.RefID] = q
+	}
+	return &queryGraph{nodes: nodes}
+}
+
+func (g *queryGraph) expression(refID string) (string, error) {
+	q, ok := g.nodes[refID]
+	if !ok {
+		return "", fmt.Errorf("query %s does not exist", refID)
+	}
+	switch expr.NodeTypeFromDatasourceUID(q.DatasourceUID) {
+	case expr.TypeDatasourceNode:
+		return g.queryExpression(q)
+	case expr.TypeMLNode:
+		return "", fmt.Errorf("machine learning query %s is not supported", refID)
+	}
+
+	m, err := g.expressionModel(refID)
+	if err != nil {
+		return "", err
+	}
+	switch m.Type {
+	case expr.QueryTypeReduce:
+		return g.reduceExpression(refID, m)
+	case expr.QueryTypeThreshold:
+		return g.thresholdExpression(refID, m)
+	default:
+		return "", fmt.Errorf("expression %s of type %s is not supported", refID, m.Type)
+	}
+}
+
+// expressionModel returns the model of the expression with the given refID, or an empty model if the node
+// is a data source query.
+func (g *queryGraph) expressionModel(refID string) (expressionModel, error) {
+	var m expressionModel
+	q, ok := g.nodes[refID]
+	if !ok || expr.NodeTypeFromDatasourceUID(q.DatasourceUID) != expr.TypeCMDNode {
+		return m, nil
+	}
+	if err := json.Unmarshal(q.Model, &m); err != nil {
+		return m, fmt.Errorf("failed to unmarshal expression %s: %w", refID, err)
+	}
+	return m, nil
+}
+
+func (g *queryGraph) queryExpression(q models.AlertQuery) (string, error) {
+	var m queryModel
+	if err := json.Unmarshal(q.Model, &m); err != nil {
+		return "", fmt.Errorf("failed to unmarshal query %s: %w", q.RefID, err)
+	}
+	if m.Datasource.Type != "" && m.Datasource.Type != datasources.DS_PROMETHEUS && m.Datasource.Type != datasources.DS_LOKI {
+		return "", fmt.Errorf("query %s uses a data source of type %s, only prometheus and loki are supported", q.RefID, m.Datasource.Type)
+	}
+	if m.Expr == "" {
+		return "", fmt.Errorf("query %s has no expression", q.RefID)
+	}
+	if m.QueryType != "instant" && (!m.Instant || m.Range) {
+		return "", fmt.Errorf("query %s is a range query, only instant queries are supported", q.RefID)
+	}
+	g.queryOffset = time.Duration(q.RelativeTimeRange.To)
+	return m.Expr, nil
+}
+
+// reduceExpression converts a Reduce expression of an instant query. Each series of an instant query has a single
+// value, so that the reducers that return this value do not change the result of the query.
+func (g *queryGraph) reduceExpression(refID string, m expressionModel) (string, error) {
+	input := strings.TrimPrefix(m.Expression, "$")
+	if q, ok := g.nodes[input]; !ok || expr.NodeTypeFromDatasourceUID(q.DatasourceUID) != expr.TypeDatasourceNode {
+		return "", fmt.Errorf("reduce expression %s must reduce the data source query", refID)
+	}
+	if !slices.Contains(singleValueReducers, mathexp.ReducerID(strings.ToLower(m.Reducer))) {
+		return "", fmt.Errorf("reduce expression %s uses the reducer %s, which is not supported", refID, m.Reducer)
+	}
+	if m.Settings.Mode != "" && m.Settings.Mode != "dropNN" {
+		return "", fmt.Errorf("reduce expression %s uses the mode %s, which is not supported", refID, m.Settings.Mode)
+	}
+	return g.expression(input)
+}
+
+func (g *queryGraph) thresholdExpression(refID string, m expressionModel) (string, error) {
+	if len(m.Conditions) != 1 {
+		return "", fmt.Errorf("threshold expression %s must have exactly one condition", refID)
+	}
+	condition := m.Conditions[0]
+	if condition.UnloadEvaluator != nil {
+		return "", fmt.Errorf("threshold expression %s has a recovery threshold, which is not supported", refID)
+	}
+	input := strings.TrimPrefix(m.Expression, "$")
+	inputModel, err := g.expressionModel(input)
+	if err != nil {
+		return "", err
+	}
+	g.thresholds++
+
+	// The rules imported from Prometheus fire for any result of the query. They are converted back to the query.
+	if inputModel.Type == expr.QueryTypeMath {
+		query, ok := g.anyResultQuery(inputModel)
+		if !ok || condition.Evaluator.Type != expr.ThresholdIsAbove || len(condition.Evaluator.Params) != 1 || condition.Evaluator.Params[0] != 0 {
+			return "", fmt.Errorf("threshold expression %s is not supported on the math expression %s", refID, input)
+		}
+		return g.expression(query)
+	}
+	// Thresholds return 0 or 1 in Grafana, while comparisons filter the series in Prometheus.
+	if inputModel.Type != "" && inputModel.Type != expr.QueryTypeReduce {
+		return "", fmt.Errorf("threshold expression %s is not supported on the expression %s of type %s", refID, input, inputModel.Type)
+	}
+
+	e, err := g.expression(input)
+	if err != nil {
+		return "", err
+	}
+	params := make([]string, 0, len(condition.Evaluator.Params))
+	for _, p := range condition.Evaluator.Params {
+		params = append(params, strconv.FormatFloat(p, 'f', -1, 64))
+	}
+	if op, ok := thresholdOperators[condition.Evaluator.Type]; ok && len(params) > 0 {
+		return fmt.Sprintf("(%s) %s %s", e, op, params[0]), nil
+	}
+	if len(params) < 2 {
+		return "", fmt.Errorf("threshold expression %s uses the function %s, which is not supported", refID, condition.Evaluator.Type)
+	}
+	switch condition.Evaluator.Type {
+	case expr.ThresholdIsWithinRange:
+		return fmt.Sprintf("(%s) > %s < %s", e, params[0], params[1]), nil
+	case expr.ThresholdIsWithinRangeIncluded:
+		return fmt.Sprintf("(%s) >= %s <= %s", e, params[0], params[1]), nil
+	case expr.ThresholdIsOutsideRange:
+		return fmt.Sprintf("(%[1]s) < %[2]s or (%[1]s) > %[3]s", e, params[0], params[1]), nil
+	case expr.ThresholdIsOutsideRangeIncluded:
+		return fmt.Sprintf("(%[1]s) <= %[2]s or (%[1]s) >= %[3]s", e, params[0], params[1]), nil
+	default:
+		return "", fmt.Errorf("threshold expression %s uses the function %s, which is not supported", refID, condition.Evaluator.Type)
+	}
+}
+
+// anyResultQuery returns the query of a math expression that is true for any value of the query,
+// like the one created by the converter of Prometheus rules.
+func (g *queryGraph) anyResultQuery(m expressionModel) (string, bool) {
+	for refID := range g.nodes {
+		if m.Expression == fmt.Sprintf("is_number($%[1]s) || is_nan($%[1]s) || is_inf($%[1]s)", refID) {
+			return refID, true
+		}
+	}
+	return "", false
+}
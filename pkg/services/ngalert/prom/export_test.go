@@ -0,0 +1,276 @@
+package prom
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestGrafanaRulesToPrometheus(t *testing.T) {
+	thresholdExpression := func(refID, input string, thresholdType expr.ThresholdType, params ...float64) models.AlertQuery {
+		model, err := json.Marshal(map[string]any{
+			"refId":      refID,
+			"type":       expr.QueryTypeThreshold,
+			"expression": input,
+			"conditions": []expr.ThresholdConditionJSON{{Evaluator: expr.ConditionEvalJSON{Type: thresholdType, Params: params}}},
+		})
+		require.NoError(t, err)
+		return models.AlertQuery{RefID: refID, DatasourceUID: expr.DatasourceUID, Model: model}
+	}
+	alertRule := func(queries ...models.AlertQuery) models.AlertRule {
+		return models.AlertRule{
+			UID:             "rule-uid",
+			Title:           "HighCPU",
+			IntervalSeconds: 60,
+			Data:            queries,
+			Condition:       queries[len(queries)-1].RefID,
+			NoDataState:     models.OK,
+			ExecErrState:    models.OkErrState,
+			For:             5 * time.Minute,
+			Labels:          map[string]string{"severity": "critical"},
+			Annotations:     map[string]string{"summary": "CPU usage is high"},
+		}
+	}
+	promQuery := models.CreatePrometheusQuery("A", "avg(cpu_usage)", 1000, 43200, true, "prom-uid")
+
+	testCases := []struct {
+		name         string
+		rule         models.AlertRule
+		expectedExpr string
+		expectedErr  string
+	}{
+		{
+			name:         "query with threshold",
+			rule:         alertRule(promQuery, thresholdExpression("B", "A", expr.ThresholdIsAbove, 80)),
+			expectedExpr: "(avg(cpu_usage)) > 80",
+		},
+		{
+			name:         "query with reduce and threshold",
+			rule:         alertRule(promQuery, models.CreateReduceExpression("B", "A", "last"), thresholdExpression("C", "B", expr.ThresholdIsBelow, 0.5)),
+			expectedExpr: "(avg(cpu_usage)) < 0.5",
+		},
+		{
+			name:         "threshold within range",
+			rule:         alertRule(promQuery, thresholdExpression("B", "$A", expr.ThresholdIsWithinRange, 10, 20)),
+			expectedExpr: "(avg(cpu_usage)) > 10 < 20",
+		},
+		{
+			name:         "threshold outside range",
+			rule:         alertRule(promQuery, thresholdExpression("B", "A", expr.ThresholdIsOutsideRangeIncluded, 10, 20)),
+			expectedExpr: "(avg(cpu_usage)) <= 10 or (avg(cpu_usage)) >= 20",
+		},
+		{
+			name:         "query without threshold",
+			rule:         alertRule(promQuery),
+			expectedExpr: "(avg(cpu_usage)) != 0",
+		},
+		{
+			name:         "loki instant query",
+			rule:         alertRule(models.CreateLokiQuery("A", "sum(rate({app=\\\"foo\\\"}[5m]))", 1000, 43200, "instant", "loki-uid"), thresholdExpression("B", "A", expr.ThresholdIsAbove, 1)),
+			expectedExpr: `(sum(rate({app="foo"}[5m]))) > 1`,
+		},
+		{
+			name:        "range query",
+			rule:        alertRule(models.CreatePrometheusQuery("A", "avg(cpu_usage)", 1000, 43200, false, "prom-uid"), thresholdExpression("B", "A", expr.ThresholdIsAbove, 80)),
+			expectedErr: "query A is a range query, only instant queries are supported",
+		},
+		{
+			name:        "reducer that changes the value",
+			rule:        alertRule(promQuery, models.CreateReduceExpression("B", "A", "count"), thresholdExpression("C", "B", expr.ThresholdIsAbove, 1)),
+			expectedErr: "reduce expression B uses the reducer count, which is not supported",
+		},
+		{
+			name:        "classic condition",
+			rule:        alertRule(promQuery, models.CreateClassicConditionExpression("B", "A", "last", "gt", 80)),
+			expectedErr: "expression B of type classic_conditions is not supported",
+		},
+		{
+			name:        "recovery threshold",
+			rule:        alertRule(promQuery, models.CreateHysteresisExpression(t, "B", "A", 80, 70)),
+			expectedErr: "threshold expression B has a recovery threshold, which is not supported",
+		},
+		{
+			name: "unsupported data source",
+			rule: alertRule(models.AlertQuery{
+				RefID:         "A",
+				DatasourceUID: "influx-uid",
+				Model:         json.RawMessage(`{"refId": "A", "expr": "foo", "instant": true, "datasource": {"type": "influxdb", "uid": "influx-uid"}}`),
+			}),
+			expectedErr: "query A uses a data source of type influxdb, only prometheus and loki are supported",
+		},
+		{
+			name: "no data state alerting",
+			rule: func() models.AlertRule {
+				r := alertRule(promQuery, thresholdExpression("B", "A", expr.ThresholdIsAbove, 80))
+				r.NoDataState = models.Alerting
+				return r
+			}(),
+			expectedErr: "the no data state Alerting is not supported, only OK is supported",
+		},
+		{
+			name: "no data state NoData",
+			rule: func() models.AlertRule {
+				r := alertRule(promQuery, thresholdExpression("B", "A", expr.ThresholdIsAbove, 80))
+				r.NoDataState = models.NoData
+				return r
+			}(),
+			expectedErr: "the no data state NoData is not supported, only OK is supported",
+		},
+		{
+			name: "no data state KeepLast",
+			rule: func() models.AlertRule {
+				r := alertRule(promQuery, thresholdExpression("B", "A", expr.ThresholdIsAbove, 80))
+				r.NoDataState = models.KeepLast
+				return r
+			}(),
+			expectedErr: "the no data state KeepLast is not supported, only OK is supported",
+		},
+		{
+			name: "error state Error",
+			rule: func() models.AlertRule {
+				r := alertRule(promQuery, thresholdExpression("B", "A", expr.ThresholdIsAbove, 80))
+				r.ExecErrState = models.ErrorErrState
+				return r
+			}(),
+			expectedErr: "the error state Error is not supported, only KeepLast and OK are supported",
+		},
+		{
+			name: "error state KeepLast",
+			rule: func() models.AlertRule {
+				r := alertRule(promQuery, thresholdExpression("B", "A", expr.ThresholdIsAbove, 80))
+				r.ExecErrState = models.KeepLastErrState
+				return r
+			}(),
+			expectedExpr: "(avg(cpu_usage)) > 80",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			group, err := GrafanaRulesToPrometheus("test-group", []models.AlertRule{tc.rule})
+			if tc.expectedErr != "" {
+				var conversionErr *RuleConversionError
+				require.ErrorAs(t, err, &conversionErr)
+				require.Equal(t, "rule-uid", conversionErr.UID)
+				require.Equal(t, tc.expectedErr, conversionErr.Reason)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, "test-group", group.Name)
+			require.Equal(t, prommodel.Duration(time.Minute), group.Interval)
+			require.Nil(t, group.QueryOffset)
+			require.Len(t, group.Rules, 1)
+			require.Equal(t, PrometheusRule{
+				Alert:       "HighCPU",
+				Expr:        tc.expectedExpr,
+				For:         util.Pointer(prommodel.Duration(5 * time.Minute)),
+				Labels:      map[string]string{"severity": "critical"},
+				Annotations: map[string]string{"summary": "CPU usage is high"},
+			}, group.Rules[0])
+		})
+	}
+
+	t.Run("recording rule", func(t *testing.T) {
+		rule := alertRule(promQuery)
+		rule.Condition = ""
+		rule.Record = &models.Record{From: "A", Metric: "cpu:avg", TargetDatasourceUID: "prom-uid"}
+
+		group, err := GrafanaRulesToPrometheus("test-group", []models.AlertRule{rule})
+		require.NoError(t, err)
+		require.Equal(t, PrometheusRule{
+			Record:      "cpu:avg",
+			Expr:        "avg(cpu_usage)",
+			Labels:      map[string]string{"severity": "critical"},
+			Annotations: map[string]string{"summary": "CPU usage is high"},
+		}, group.Rules[0])
+	})
+
+	t.Run("should report all rules that cannot be converted", func(t *testing.T) {
+		valid := alertRule(promQuery, thresholdExpression("B", "A", expr.ThresholdIsAbove, 80))
+		paused := alertRule(promQuery, thresholdExpression("B", "A", expr.ThresholdIsAbove, 80))
+		paused.UID = "paused"
+		paused.IsPaused = true
+		classic := alertRule(promQuery, models.CreateClassicConditionExpression("B", "A", "last", "gt", 80))
+		classic.UID = "classic"
+
+		_, err := GrafanaRulesToPrometheus("test-group", []models.AlertRule{valid, paused, classic})
+		require.Error(t, err)
+		var uids []string
+		for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+			var conversionErr *RuleConversionError
+			require.True(t, errors.As(e, &conversionErr))
+			uids = append(uids, conversionErr.UID)
+		}
+		require.Equal(t, []string{"paused", "classic"}, uids)
+	})
+
+	t.Run("should set the query offset of the group", func(t *testing.T) {
+		query := promQuery
+		query.RelativeTimeRange = models.RelativeTimeRange{From: models.Duration(11 * time.Minute), To: models.Duration(time.Minute)}
+		rule := alertRule(query, thresholdExpression("B", "A", expr.ThresholdIsAbove, 80))
+
+		group, err := GrafanaRulesToPrometheus("test-group", []models.AlertRule{rule, rule})
+		require.NoError(t, err)
+		require.Equal(t, util.Pointer(prommodel.Duration(time.Minute)), group.QueryOffset)
+
+		other := alertRule(promQuery, thresholdExpression("B", "A", expr.ThresholdIsAbove, 80))
+		_, err = GrafanaRulesToPrometheus("test-group", []models.AlertRule{rule, other})
+		require.ErrorContains(t, err, "the query offset 0s is different from the query offset 1m0s of the other rules in the group")
+	})
+}
+
+func TestGrafanaRulesToPrometheus_RoundTrip(t *testing.T) {
+	promGroup := PrometheusRuleGroup{
+		Name:        "test-group",
+		Interval:    prommodel.Duration(2 * time.Minute),
+		QueryOffset: util.Pointer(prommodel.Duration(30 * time.Second)),
+		Rules: []PrometheusRule{
+			{
+				Alert:       "HighCPU",
+				Expr:        "avg(cpu_usage) > 80",
+				For:         util.Pointer(prommodel.Duration(5 * time.Minute)),
+				Labels:      map[string]string{"severity": "critical"},
+				Annotations: map[string]string{"summary": "CPU usage is high"},
+			},
+			{
+				Record: "cpu:avg",
+				Expr:   "avg(cpu_usage)",
+			},
+		},
+	}
+
+	for _, keepOriginal := range []bool{true, false} {
+		converter, err := NewConverter(Config{
+			DatasourceUID:              "prom-uid",
+			DatasourceType:             datasources.DS_PROMETHEUS,
+			DefaultInterval:            time.Minute,
+			KeepOriginalRuleDefinition: util.Pointer(keepOriginal),
+		})
+		require.NoError(t, err)
+		grafanaGroup, err := converter.PrometheusRulesToGrafana(1, "namespace", promGroup)
+		require.NoError(t, err)
+
+		result, err := GrafanaRulesToPrometheus(grafanaGroup.Title, grafanaGroup.Rules)
+		require.NoError(t, err)
+		require.Equal(t, promGroup.Name, result.Name)
+		require.Equal(t, promGroup.Interval, result.Interval)
+		require.Equal(t, promGroup.QueryOffset, result.QueryOffset)
+		require.Len(t, result.Rules, 2)
+		require.Equal(t, "cpu:avg", result.Rules[1].Record)
+		require.Equal(t, "avg(cpu_usage)", result.Rules[1].Expr)
+		require.Equal(t, "HighCPU", result.Rules[0].Alert)
+		require.Equal(t, "avg(cpu_usage) > 80", result.Rules[0].Expr)
+		require.Equal(t, promGroup.Rules[0].For, result.Rules[0].For)
+		require.Equal(t, promGroup.Rules[0].Labels, result.Rules[0].Labels)
+	}
+}
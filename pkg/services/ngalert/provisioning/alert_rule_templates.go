@@ -0,0 +1,594 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning/validation"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// AlertRuleTemplateService manages alert rule templates and their instances. The rule of an instance is written
+// through the same path as the rule groups of the provisioning API, with the provenance models.ProvenanceRuleTemplate.
+type AlertRuleTemplateService struct {
+	store           AlertRuleTemplateStore
+	provenanceStore ProvisioningStore
+	xact            TransactionManager
+	rules           *AlertRuleService
+	log             log.Logger
+	validator       validation.ProvenanceStatusTransitionValidator
+}
+
+func NewAlertRuleTemplateService(store AlertRuleTemplateStore, prov ProvisioningStore, xact TransactionManager, rules *AlertRuleService, log log.Logger) *AlertRuleTemplateService {
+	return &AlertRuleTemplateService{
+		store:           store,
+		provenanceStore: prov,
+		xact:            xact,
+		rules:           rules,
+		log:             log,
+		validator:       validation.ValidateProvenanceRelaxed,
+	}
+}
+
+// GetAlertRuleTemplates returns all alert rule templates within the specified org.
+func (svc *AlertRuleTemplateService) GetAlertRuleTemplates(ctx context.Context, orgID int64) ([]models.AlertRuleTemplate, error) {
+	templates, err := svc.store.ListAlertRuleTemplates(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return []models.AlertRuleTemplate{}, nil
+	}
+
+	provenances, err := svc.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRuleTemplate{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.AlertRuleTemplate, 0, len(templates))
+	for _, t := range templates {
+		if prov, ok := provenances[t.ResourceID()]; ok {
+			t.Provenance = prov
+		}
+		result = append(result, *t)
+	}
+	return result, nil
+}
+
+// GetAlertRuleTemplate returns an alert rule template by UID.
+func (svc *AlertRuleTemplateService) GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (models.AlertRuleTemplate, error) {
+	t, err := svc.getTemplate(ctx, orgID, uid)
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	prov, err := svc.provenanceStore.GetProvenance(ctx, t, orgID)
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	t.Provenance = prov
+	return *t, nil
+}
+
+// CreateAlertRuleTemplate adds a new alert rule template within the org of the user. The created template is returned.
+func (svc *AlertRuleTemplateService) CreateAlertRuleTemplate(ctx context.Context, user identity.Requester, t models.AlertRuleTemplate) (models.AlertRuleTemplate, error) {
+	if err := t.Validate(); err != nil {
+		return models.AlertRuleTemplate{}, MakeErrAlertRuleTemplateInvalid(err)
+	}
+	if t.UID != "" {
+		if err := util.ValidateUID(t.UID); err != nil {
+			return models.AlertRuleTemplate{}, MakeErrAlertRuleTemplateInvalid(err)
+		}
+	}
+	t.OrgID = user.GetOrgID()
+
+	var created *models.AlertRuleTemplate
+	err := svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = svc.store.InsertAlertRuleTemplate(ctx, t)
+		if err != nil {
+			if errors.Is(err, models.ErrAlertRuleTemplateExists) {
+				return ErrAlertRuleTemplateExists.Errorf("")
+			}
+			return err
+		}
+		return svc.provenanceStore.SetProvenance(ctx, created, t.OrgID, t.Provenance)
+	})
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	created.Provenance = t.Provenance
+	return *created, nil
+}
+
+// UpdateAlertRuleTemplate replaces an existing alert rule template within the org of the user, and updates the rules
+// of all its instances in the same transaction. The template is looked up by UID or, if the UID is empty, by name.
+// If the version is not zero, it must match the current version. Nothing is updated if the template cannot be
+// instantiated with the values of one of the instances, or if the changes to the rules are rejected.
+func (svc *AlertRuleTemplateService) UpdateAlertRuleTemplate(ctx context.Context, user identity.Requester, t models.AlertRuleTemplate) (models.AlertRuleTemplate, error) {
+	if err := t.Validate(); err != nil {
+		return models.AlertRuleTemplate{}, MakeErrAlertRuleTemplateInvalid(err)
+	}
+	t.OrgID = user.GetOrgID()
+
+	existing, err := svc.findTemplate(ctx, t.OrgID, t.UID, t.Name)
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	if existing == nil {
+		return models.AlertRuleTemplate{}, ErrAlertRuleTemplateNotFound.Errorf("")
+	}
+
+	// check optimistic concurrency
+	if t.Version == 0 {
+		if t.Provenance != models.ProvenanceFile {
+			svc.log.FromContext(ctx).Debug("Ignoring optimistic concurrency check because version was not provided", "template", existing.UID, "operation", "update")
+		}
+		t.Version = existing.Version
+	} else if t.Version != existing.Version {
+		return models.AlertRuleTemplate{}, ErrVersionConflict.Errorf("provided version %d of alert rule template %s does not match current version %d", t.Version, existing.UID, existing.Version)
+	}
+
+	// check that provenance is not changed in an invalid way
+	storedProvenance, err := svc.provenanceStore.GetProvenance(ctx, existing, t.OrgID)
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	if err := svc.validator(storedProvenance, t.Provenance); err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+
+	t.UID = existing.UID
+	var updated *models.AlertRuleTemplate
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = svc.store.UpdateAlertRuleTemplate(ctx, t)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrAlertRuleTemplateNotFound):
+				return ErrAlertRuleTemplateNotFound.Errorf("")
+			case errors.Is(err, models.ErrAlertRuleTemplateExists):
+				return ErrAlertRuleTemplateExists.Errorf("")
+			case errors.Is(err, store.ErrOptimisticLock):
+				return ErrVersionConflict.Errorf("alert rule template %s was updated concurrently", t.UID)
+			}
+			return err
+		}
+
+		instances, err := svc.store.ListAlertRuleTemplateInstances(ctx, t.OrgID, t.UID)
+		if err != nil {
+			return err
+		}
+		// The rules are saved group by group, in the order of the instances.
+		var keys []models.AlertRuleGroupKey
+		rulesByGroup := make(map[models.AlertRuleGroupKey][]models.AlertRule)
+		for _, instance := range instances {
+			rule, err := updated.Instantiate(*instance)
+			if err != nil {
+				return MakeErrAlertRuleTemplateInvalid(fmt.Errorf("cannot instantiate the template for instance %s: %w", instance.UID, err))
+			}
+			key := rule.GetGroupKey()
+			if _, ok := rulesByGroup[key]; !ok {
+				keys = append(keys, key)
+			}
+			rulesByGroup[key] = append(rulesByGroup[key], rule)
+		}
+		for _, key := range keys {
+			if _, err := svc.saveInstanceRules(ctx, user, key, svc.rules.defaultIntervalSeconds, rulesByGroup[key]); err != nil {
+				return err
+			}
+		}
+		return svc.provenanceStore.SetProvenance(ctx, updated, t.OrgID, t.Provenance)
+	})
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	updated.Provenance = t.Provenance
+	return *updated, nil
+}
+
+// DeleteAlertRuleTemplate deletes the alert rule template with the given UID in the org of the user. A template cannot
+// be deleted while it has instances. If the template does not exist, no error is returned.
+func (svc *AlertRuleTemplateService) DeleteAlertRuleTemplate(ctx context.Context, user identity.Requester, uid string, provenance models.Provenance) error {
+	orgID := user.GetOrgID()
+	existing, err := svc.store.GetAlertRuleTemplate(ctx, orgID, uid)
+	if err != nil {
+		if errors.Is(err, models.ErrAlertRuleTemplateNotFound) {
+			svc.log.FromContext(ctx).Debug("Alert rule template was not found. Skip deleting", "uid", uid)
+			return nil
+		}
+		return err
+	}
+
+	// check that provenance is not changed in an invalid way
+	storedProvenance, err := svc.provenanceStore.GetProvenance(ctx, existing, orgID)
+	if err != nil {
+		return err
+	}
+	if err := svc.validator(storedProvenance, provenance); err != nil {
+		return err
+	}
+
+	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.DeleteAlertRuleTemplate(ctx, orgID, uid); err != nil {
+			if errors.Is(err, models.ErrAlertRuleTemplateInUse) {
+				return ErrAlertRuleTemplateInUse.Errorf("")
+			}
+			return err
+		}
+		return svc.provenanceStore.DeleteProvenance(ctx, existing, orgID)
+	})
+}
+
+// GetAlertRuleTemplateInstances returns the instances of an alert rule template.
+func (svc *AlertRuleTemplateService) GetAlertRuleTemplateInstances(ctx context.Context, orgID int64, templateUID string) ([]models.AlertRuleTemplateInstance, error) {
+	if _, err := svc.getTemplate(ctx, orgID, templateUID); err != nil {
+		return nil, err
+	}
+	instances, err := svc.store.ListAlertRuleTemplateInstances(ctx, orgID, templateUID)
+	if err != nil {
+		return nil, err
+	}
+	if len(instances) == 0 {
+		return []models.AlertRuleTemplateInstance{}, nil
+	}
+
+	provenances, err := svc.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRuleTemplateInstance{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.AlertRuleTemplateInstance, 0, len(instances))
+	for _, instance := range instances {
+		if prov, ok := provenances[instance.ResourceID()]; ok {
+			instance.Provenance = prov
+		}
+		result = append(result, *instance)
+	}
+	return result, nil
+}
+
+// GetAlertRuleTemplateInstance returns an instance of an alert rule template by UID.
+func (svc *AlertRuleTemplateService) GetAlertRuleTemplateInstance(ctx context.Context, orgID int64, templateUID, uid string) (models.AlertRuleTemplateInstance, error) {
+	instance, err := svc.getInstance(ctx, orgID, templateUID, uid)
+	if err != nil {
+		return models.AlertRuleTemplateInstance{}, err
+	}
+	prov, err := svc.provenanceStore.GetProvenance(ctx, instance, orgID)
+	if err != nil {
+		return models.AlertRuleTemplateInstance{}, err
+	}
+	instance.Provenance = prov
+	return *instance, nil
+}
+
+// CreateAlertRuleTemplateInstance adds a new instance of an alert rule template within the org of the user, together
+// with its rule. The rule has the UID of the instance and is evaluated at the interval of its group if the group exists.
+// The created instance is returned.
+func (svc *AlertRuleTemplateService) CreateAlertRuleTemplateInstance(ctx context.Context, user identity.Requester, instance models.AlertRuleTemplateInstance) (models.AlertRuleTemplateInstance, error) {
+	if instance.UID == "" {
+		instance.UID = util.GenerateShortUID()
+	} else if err := util.ValidateUID(instance.UID); err != nil {
+		return models.AlertRuleTemplateInstance{}, MakeErrAlertRuleTemplateInstanceInvalid(err)
+	}
+	if instance.RuleGroup == "" {
+		return models.AlertRuleTemplateInstance{}, MakeErrAlertRuleTemplateInstanceInvalid(errors.New("rule group must not be empty"))
+	}
+	instance.OrgID = user.GetOrgID()
+
+	var created *models.AlertRuleTemplateInstance
+	err := svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		t, err := svc.getTemplate(ctx, instance.OrgID, instance.TemplateUID)
+		if err != nil {
+			return err
+		}
+		// The rule of the instance must not replace an existing rule.
+		_, err = svc.rules.ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{OrgID: instance.OrgID, UID: instance.UID})
+		if err == nil {
+			return ErrAlertRuleTemplateInstanceExists.Errorf("")
+		}
+		if !errors.Is(err, models.ErrAlertRuleNotFound) {
+			return err
+		}
+
+		rule, err := t.Instantiate(instance)
+		if err != nil {
+			return MakeErrAlertRuleTemplateInstanceInvalid(err)
+		}
+		instance.IntervalSeconds, err = svc.saveInstanceRules(ctx, user, rule.GetGroupKey(), svc.instanceInterval(instance), []models.AlertRule{rule})
+		if err != nil {
+			return err
+		}
+
+		created, err = svc.store.InsertAlertRuleTemplateInstance(ctx, instance)
+		if err != nil {
+			if errors.Is(err, models.ErrAlertRuleTemplateInstanceExists) {
+				return ErrAlertRuleTemplateInstanceExists.Errorf("")
+			}
+			return err
+		}
+		return svc.provenanceStore.SetProvenance(ctx, created, instance.OrgID, instance.Provenance)
+	})
+	if err != nil {
+		return models.AlertRuleTemplateInstance{}, err
+	}
+	created.Provenance = instance.Provenance
+	return *created, nil
+}
+
+// UpdateAlertRuleTemplateInstance replaces an existing instance of an alert rule template within the org of the user,
+// and updates its rule. The rule is moved if the folder or the group of the instance changes. If the version is not
+// zero, it must match the current version. The updated instance is returned.
+func (svc *AlertRuleTemplateService) UpdateAlertRuleTemplateInstance(ctx context.Context, user identity.Requester, instance models.AlertRuleTemplateInstance) (models.AlertRuleTemplateInstance, error) {
+	if instance.RuleGroup == "" {
+		return models.AlertRuleTemplateInstance{}, MakeErrAlertRuleTemplateInstanceInvalid(errors.New("rule group must not be empty"))
+	}
+	instance.OrgID = user.GetOrgID()
+
+	existing, err := svc.getInstance(ctx, instance.OrgID, instance.TemplateUID, instance.UID)
+	if err != nil {
+		return models.AlertRuleTemplateInstance{}, err
+	}
+
+	// check optimistic concurrency
+	if instance.Version == 0 {
+		if instance.Provenance != models.ProvenanceFile {
+			svc.log.FromContext(ctx).Debug("Ignoring optimistic concurrency check because version was not provided", "instance", existing.UID, "operation", "update")
+		}
+		instance.Version = existing.Version
+	} else if instance.Version != existing.Version {
+		return models.AlertRuleTemplateInstance{}, ErrVersionConflict.Errorf("provided version %d of alert rule template instance %s does not match current version %d", instance.Version, existing.UID, existing.Version)
+	}
+
+	// check that provenance is not changed in an invalid way
+	storedProvenance, err := svc.provenanceStore.GetProvenance(ctx, existing, instance.OrgID)
+	if err != nil {
+		return models.AlertRuleTemplateInstance{}, err
+	}
+	if err := svc.validator(storedProvenance, instance.Provenance); err != nil {
+		return models.AlertRuleTemplateInstance{}, err
+	}
+
+	var updated *models.AlertRuleTemplateInstance
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		t, err := svc.getTemplate(ctx, instance.OrgID, instance.TemplateUID)
+		if err != nil {
+			return err
+		}
+		rule, err := t.Instantiate(instance)
+		if err != nil {
+			return MakeErrAlertRuleTemplateInstanceInvalid(err)
+		}
+		instance.IntervalSeconds, err = svc.saveInstanceRules(ctx, user, rule.GetGroupKey(), svc.instanceInterval(instance), []models.AlertRule{rule})
+		if err != nil {
+			return err
+		}
+
+		updated, err = svc.store.UpdateAlertRuleTemplateInstance(ctx, instance)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrAlertRuleTemplateInstanceNotFound):
+				return ErrAlertRuleTemplateInstanceNotFound.Errorf("")
+			case errors.Is(err, store.ErrOptimisticLock):
+				return ErrVersionConflict.Errorf("alert rule template instance %s was updated concurrently", instance.UID)
+			}
+			return err
+		}
+		return svc.provenanceStore.SetProvenance(ctx, updated, instance.OrgID, instance.Provenance)
+	})
+	if err != nil {
+		return models.AlertRuleTemplateInstance{}, err
+	}
+	updated.Provenance = instance.Provenance
+	return *updated, nil
+}
+
+// DeleteAlertRuleTemplateInstance deletes the instance with the given UID of an alert rule template in the org of the
+// user, together with its rule. If the instance does not exist, no error is returned.
+func (svc *AlertRuleTemplateService) DeleteAlertRuleTemplateInstance(ctx context.Context, user identity.Requester, templateUID, uid string, provenance models.Provenance) error {
+	orgID := user.GetOrgID()
+	existing, err := svc.getInstance(ctx, orgID, templateUID, uid)
+	if err != nil {
+		if errors.Is(err, ErrAlertRuleTemplateInstanceNotFound) || errors.Is(err, ErrAlertRuleTemplateNotFound) {
+			svc.log.FromContext(ctx).Debug("Alert rule template instance was not found. Skip deleting", "uid", uid)
+			return nil
+		}
+		return err
+	}
+
+	// check that provenance is not changed in an invalid way
+	storedProvenance, err := svc.provenanceStore.GetProvenance(ctx, existing, orgID)
+	if err != nil {
+		return err
+	}
+	if err := svc.validator(storedProvenance, provenance); err != nil {
+		return err
+	}
+
+	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		delta, err := store.CalculateRuleDelete(ctx, svc.rules.ruleStore, models.AlertRuleKey{OrgID: orgID, UID: uid})
+		if err != nil && !errors.Is(err, models.ErrAlertRuleNotFound) {
+			return err
+		}
+		if delta != nil {
+			if err := svc.authorizeRuleChanges(ctx, user, delta); err != nil {
+				return err
+			}
+			if err := svc.rules.persistDeltaOfRules(ctx, user, delta, models.ProvenanceRuleTemplate, map[string]struct{}{uid: {}}); err != nil {
+				return err
+			}
+		}
+		if err := svc.store.DeleteAlertRuleTemplateInstance(ctx, orgID, uid); err != nil {
+			return err
+		}
+		return svc.provenanceStore.DeleteProvenance(ctx, existing, orgID)
+	})
+}
+
+// saveInstanceRules adds the rules of template instances to a rule group, or replaces them, and returns the interval
+// of the group. The changes are calculated, authorized and persisted like the changes to rule groups made with the
+// provisioning API, which validates the dependencies between rules, the interval and the alert rule quota. The other
+// rules of the group, and the interval of the group if it exists, do not change.
+func (svc *AlertRuleTemplateService) saveInstanceRules(ctx context.Context, user identity.Requester, key models.AlertRuleGroupKey, intervalSeconds int64, rules []models.AlertRule) (int64, error) {
+	if err := svc.rules.ensureNamespace(ctx, user, key.OrgID, key.NamespaceUID); err != nil {
+		return 0, err
+	}
+	existing, err := svc.rules.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+		OrgID:         key.OrgID,
+		NamespaceUIDs: []string{key.NamespaceUID},
+		RuleGroups:    []string{key.RuleGroup},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	if len(existing) > 0 {
+		intervalSeconds = existing[0].IntervalSeconds
+	}
+	if err := models.ValidateRuleGroupInterval(intervalSeconds, svc.rules.baseIntervalSeconds); err != nil {
+		return 0, err
+	}
+
+	instanceRules := make(map[string]*models.AlertRule, len(rules))
+	owned := make(map[string]struct{}, len(rules))
+	for i := range rules {
+		rules[i].IntervalSeconds = intervalSeconds
+		if err := rules[i].SetDashboardAndPanelFromAnnotations(); err != nil {
+			return 0, err
+		}
+		instanceRules[rules[i].UID] = &rules[i]
+		owned[rules[i].UID] = struct{}{}
+	}
+
+	// Submit the whole group, in which the rules of the instances keep their position and new rules are added at the end.
+	group := models.AlertRuleGroup{Title: key.RuleGroup, FolderUID: key.NamespaceUID, Interval: intervalSeconds}
+	submitted := make([]*models.AlertRuleWithOptionals, 0, len(existing)+len(rules))
+	maxIndex := 0
+	for _, r := range existing {
+		maxIndex = max(maxIndex, r.RuleGroupIndex)
+		if rule, ok := instanceRules[r.UID]; ok {
+			rule.RuleGroupIndex = r.RuleGroupIndex
+			delete(instanceRules, r.UID)
+			submitted = append(submitted, &models.AlertRuleWithOptionals{AlertRule: *rule, HasPause: true})
+			continue
+		}
+		submitted = append(submitted, &models.AlertRuleWithOptionals{AlertRule: *r, HasPause: true, HasEditorSettings: true})
+	}
+	for _, r := range rules {
+		if _, ok := instanceRules[r.UID]; !ok {
+			continue
+		}
+		maxIndex++
+		r.RuleGroupIndex = maxIndex
+		submitted = append(submitted, &models.AlertRuleWithOptionals{AlertRule: r, HasPause: true})
+	}
+	for _, r := range submitted {
+		group.Rules = append(group.Rules, r.AlertRule)
+	}
+	if err := svc.rules.checkGroupLimits(group); err != nil {
+		return 0, fmt.Errorf("write rejected due to exceeded limits: %w", err)
+	}
+
+	delta, err := store.CalculateChanges(ctx, svc.rules.ruleStore, key, submitted)
+	if err != nil {
+		return 0, fmt.Errorf("failed to calculate diff for alert rules: %w", err)
+	}
+	if delta.IsEmpty() {
+		return intervalSeconds, nil
+	}
+	delta = store.UpdateCalculatedRuleFields(delta)
+
+	if err := svc.authorizeRuleChanges(ctx, user, delta); err != nil {
+		return 0, err
+	}
+
+	newOrUpdatedNotificationSettings := delta.NewOrUpdatedNotificationSettings()
+	if len(newOrUpdatedNotificationSettings) > 0 {
+		validator, err := svc.rules.nsValidatorProvider.Validator(ctx, key.OrgID)
+		if err != nil {
+			return 0, err
+		}
+		for _, s := range newOrUpdatedNotificationSettings {
+			if err := validator.Validate(s); err != nil {
+				return 0, errors.Join(models.ErrAlertRuleFailedValidation, err)
+			}
+		}
+	}
+
+	if err := svc.rules.persistDeltaOfRules(ctx, user, delta, models.ProvenanceRuleTemplate, owned); err != nil {
+		return 0, err
+	}
+	return intervalSeconds, nil
+}
+
+// authorizeRuleChanges checks that the user can make the changes to the rules, unless the user can write all rules
+// with the provisioning API.
+func (svc *AlertRuleTemplateService) authorizeRuleChanges(ctx context.Context, user identity.Requester, delta *store.GroupDelta) error {
+	can, err := svc.rules.authz.CanWriteAllRules(ctx, user)
+	if err != nil {
+		return err
+	}
+	if can {
+		return nil
+	}
+	return svc.rules.authz.AuthorizeRuleGroupWrite(ctx, user, delta)
+}
+
+// instanceInterval returns the interval of the rule of the instance if its group does not exist yet.
+func (svc *AlertRuleTemplateService) instanceInterval(instance models.AlertRuleTemplateInstance) int64 {
+	if instance.IntervalSeconds > 0 {
+		return instance.IntervalSeconds
+	}
+	return svc.rules.defaultIntervalSeconds
+}
+
+func (svc *AlertRuleTemplateService) getTemplate(ctx context.Context, orgID int64, uid string) (*models.AlertRuleTemplate, error) {
+	t, err := svc.store.GetAlertRuleTemplate(ctx, orgID, uid)
+	if err != nil {
+		if errors.Is(err, models.ErrAlertRuleTemplateNotFound) {
+			return nil, ErrAlertRuleTemplateNotFound.Errorf("")
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// getInstance returns the instance with the given UID, if it is an instance of the template with the given UID.
+func (svc *AlertRuleTemplateService) getInstance(ctx context.Context, orgID int64, templateUID, uid string) (*models.AlertRuleTemplateInstance, error) {
+	instance, err := svc.store.GetAlertRuleTemplateInstance(ctx, orgID, uid)
+	if err != nil {
+		if errors.Is(err, models.ErrAlertRuleTemplateInstanceNotFound) {
+			return nil, ErrAlertRuleTemplateInstanceNotFound.Errorf("")
+		}
+		return nil, err
+	}
+	if instance.TemplateUID != templateUID {
+		return nil, ErrAlertRuleTemplateInstanceNotFound.Errorf("")
+	}
+	return instance, nil
+}
+
+// findTemplate returns the alert rule template with the given UID or, if the UID is empty, with the given name.
+// It returns nil if it does not exist.
+func (svc *AlertRuleTemplateService) findTemplate(ctx context.Context, orgID int64, uid, name string) (*models.AlertRuleTemplate, error) {
+	if uid != "" {
+		t, err := svc.store.GetAlertRuleTemplate(ctx, orgID, uid)
+		if errors.Is(err, models.ErrAlertRuleTemplateNotFound) {
+			return nil, nil
+		}
+		return t, err
+	}
+	templates, err := svc.store.ListAlertRuleTemplates(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range templates {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
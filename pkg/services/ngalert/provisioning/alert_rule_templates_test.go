@@ -0,0 +1,335 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type fakeAlertRuleTemplateStore struct {
+	templates map[string]models.AlertRuleTemplate
+	instances map[string]models.AlertRuleTemplateInstance
+}
+
+func (f *fakeAlertRuleTemplateStore) ListAlertRuleTemplates(_ context.Context, orgID int64) ([]*models.AlertRuleTemplate, error) {
+	var result []*models.AlertRuleTemplate
+	for _, t := range f.templates {
+		if t.OrgID == orgID {
+			result = append(result, &t)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeAlertRuleTemplateStore) GetAlertRuleTemplate(_ context.Context, orgID int64, uid string) (*models.AlertRuleTemplate, error) {
+	t, ok := f.templates[uid]
+	if !ok || t.OrgID != orgID {
+		return nil, models.ErrAlertRuleTemplateNotFound
+	}
+	return &t, nil
+}
+
+func (f *fakeAlertRuleTemplateStore) InsertAlertRuleTemplate(_ context.Context, t models.AlertRuleTemplate) (*models.AlertRuleTemplate, error) {
+	for _, existing := range f.templates {
+		if existing.OrgID == t.OrgID && (existing.UID == t.UID || existing.Name == t.Name) {
+			return nil, models.ErrAlertRuleTemplateExists
+		}
+	}
+	if t.UID == "" {
+		t.UID = t.Name
+	}
+	t.Version = 1
+	f.templates[t.UID] = t
+	return &t, nil
+}
+
+func (f *fakeAlertRuleTemplateStore) UpdateAlertRuleTemplate(_ context.Context, t models.AlertRuleTemplate) (*models.AlertRuleTemplate, error) {
+	existing, ok := f.templates[t.UID]
+	if !ok {
+		return nil, models.ErrAlertRuleTemplateNotFound
+	}
+	if existing.Version != t.Version {
+		return nil, store.ErrOptimisticLock
+	}
+	t.Version++
+	f.templates[t.UID] = t
+	return &t, nil
+}
+
+func (f *fakeAlertRuleTemplateStore) DeleteAlertRuleTemplate(_ context.Context, _ int64, uid string) error {
+	for _, instance := range f.instances {
+		if instance.TemplateUID == uid {
+			return models.ErrAlertRuleTemplateInUse
+		}
+	}
+	delete(f.templates, uid)
+	return nil
+}
+
+func (f *fakeAlertRuleTemplateStore) ListAlertRuleTemplateInstances(_ context.Context, orgID int64, templateUID string) ([]*models.AlertRuleTemplateInstance, error) {
+	var result []*models.AlertRuleTemplateInstance
+	for _, instance := range f.instances {
+		if instance.OrgID == orgID && instance.TemplateUID == templateUID {
+			result = append(result, &instance)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeAlertRuleTemplateStore) GetAlertRuleTemplateInstance(_ context.Context, orgID int64, uid string) (*models.AlertRuleTemplateInstance, error) {
+	instance, ok := f.instances[uid]
+	if !ok || instance.OrgID != orgID {
+		return nil, models.ErrAlertRuleTemplateInstanceNotFound
+	}
+	return &instance, nil
+}
+
+func (f *fakeAlertRuleTemplateStore) InsertAlertRuleTemplateInstance(_ context.Context, instance models.AlertRuleTemplateInstance) (*models.AlertRuleTemplateInstance, error) {
+	if _, ok := f.instances[instance.UID]; ok {
+		return nil, models.ErrAlertRuleTemplateInstanceExists
+	}
+	instance.Version = 1
+	f.instances[instance.UID] = instance
+	return &instance, nil
+}
+
+func (f *fakeAlertRuleTemplateStore) UpdateAlertRuleTemplateInstance(_ context.Context, instance models.AlertRuleTemplateInstance) (*models.AlertRuleTemplateInstance, error) {
+	existing, ok := f.instances[instance.UID]
+	if !ok {
+		return nil, models.ErrAlertRuleTemplateInstanceNotFound
+	}
+	if existing.Version != instance.Version {
+		return nil, store.ErrOptimisticLock
+	}
+	instance.TemplateUID = existing.TemplateUID
+	instance.Version++
+	f.instances[instance.UID] = instance
+	return &instance, nil
+}
+
+func (f *fakeAlertRuleTemplateStore) DeleteAlertRuleTemplateInstance(_ context.Context, _ int64, uid string) error {
+	delete(f.instances, uid)
+	return nil
+}
+
+func TestAlertRuleTemplateService(t *testing.T) {
+	orgID := int64(1)
+	u := &user.SignedInUser{OrgID: orgID}
+	template := models.AlertRuleTemplate{
+		UID:     "high-cpu",
+		OrgID:   orgID,
+		Name:    "High CPU",
+		Version: 1,
+		Parameters: []models.AlertRuleTemplateParameter{
+			{Name: "datasource", Type: models.AlertRuleTemplateParameterString},
+		},
+		Rule: models.AlertRuleTemplateRule{
+			Title: "High CPU on ${datasource}",
+			Data: []models.AlertQuery{
+				models.CreatePrometheusQuery("A", "avg(cpu_usage)", 1000, 43200, true, "${datasource}"),
+				models.CreateClassicConditionExpression("B", "A", "last", "gt", 80),
+			},
+			Condition:    "B",
+			NoDataState:  models.OK,
+			ExecErrState: models.OkErrState,
+		},
+	}
+	newInstance := func(uid, datasource string) models.AlertRuleTemplateInstance {
+		return models.AlertRuleTemplateInstance{
+			UID:          uid,
+			TemplateUID:  template.UID,
+			NamespaceUID: "my-namespace",
+			RuleGroup:    "my-group",
+			Values:       map[string]string{"datasource": datasource},
+		}
+	}
+
+	t.Run("create instance should insert its rule with the rule template provenance", func(t *testing.T) {
+		sut, st, ruleStore, prov, ac := createAlertRuleTemplateSvcSut(t, template)
+
+		created, err := sut.CreateAlertRuleTemplateInstance(context.Background(), u, newInstance("instance-a", "prom-a"))
+		require.NoError(t, err)
+		require.EqualValues(t, 60, created.IntervalSeconds)
+		require.Contains(t, st.instances, "instance-a")
+
+		rule, err := ruleStore.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: orgID, UID: "instance-a"})
+		require.NoError(t, err)
+		require.Equal(t, "High CPU on prom-a", rule.Title)
+		require.Equal(t, "prom-a", rule.Data[0].DatasourceUID)
+		require.EqualValues(t, 60, rule.IntervalSeconds)
+
+		provenance, err := prov.GetProvenance(context.Background(), rule, orgID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceRuleTemplate, provenance)
+		require.NotEmpty(t, ac.Calls)
+		require.Equal(t, "AuthorizeRuleGroupWrite", ac.Calls[len(ac.Calls)-1].Method)
+	})
+
+	t.Run("create instance should use the interval of the existing group and keep the other rules", func(t *testing.T) {
+		sut, _, ruleStore, prov, _ := createAlertRuleTemplateSvcSut(t, template)
+		other := createTestRule("other", "my-group", orgID, "my-namespace")
+		other.UID = "other"
+		other.IntervalSeconds = 120
+		other.RuleGroupIndex = 1
+		ruleStore.PutRule(context.Background(), &other)
+		require.NoError(t, prov.SetProvenance(context.Background(), &other, orgID, models.ProvenanceAPI))
+
+		created, err := sut.CreateAlertRuleTemplateInstance(context.Background(), u, newInstance("instance-a", "prom-a"))
+		require.NoError(t, err)
+		require.EqualValues(t, 120, created.IntervalSeconds)
+
+		rule, err := ruleStore.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: orgID, UID: "instance-a"})
+		require.NoError(t, err)
+		require.EqualValues(t, 120, rule.IntervalSeconds)
+		require.Equal(t, 2, rule.RuleGroupIndex)
+
+		provenance, err := prov.GetProvenance(context.Background(), &other, orgID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+	})
+
+	t.Run("create instance should fail if the user cannot write the rule", func(t *testing.T) {
+		sut, st, ruleStore, _, ac := createAlertRuleTemplateSvcSut(t, template)
+		expectedErr := errors.New("no access to the data source")
+		ac.AuthorizeRuleChangesFunc = func(ctx context.Context, user identity.Requester, change *store.GroupDelta) error {
+			return expectedErr
+		}
+
+		_, err := sut.CreateAlertRuleTemplateInstance(context.Background(), u, newInstance("instance-a", "prom-a"))
+		require.ErrorIs(t, err, expectedErr)
+		require.Empty(t, st.instances)
+		require.Empty(t, ruleStore.Rules[orgID])
+	})
+
+	t.Run("create instance should validate the interval of a new group", func(t *testing.T) {
+		sut, st, _, _, _ := createAlertRuleTemplateSvcSut(t, template)
+		instance := newInstance("instance-a", "prom-a")
+		instance.IntervalSeconds = 15
+
+		_, err := sut.CreateAlertRuleTemplateInstance(context.Background(), u, instance)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.Empty(t, st.instances)
+	})
+
+	t.Run("create instance should not replace an existing rule", func(t *testing.T) {
+		sut, st, ruleStore, _, _ := createAlertRuleTemplateSvcSut(t, template)
+		other := createTestRule("other", "other-group", orgID, "my-namespace")
+		other.UID = "instance-a"
+		ruleStore.PutRule(context.Background(), &other)
+
+		_, err := sut.CreateAlertRuleTemplateInstance(context.Background(), u, newInstance("instance-a", "prom-a"))
+		require.ErrorIs(t, err, ErrAlertRuleTemplateInstanceExists)
+		require.Empty(t, st.instances)
+	})
+
+	t.Run("create instance should fail if a value is missing", func(t *testing.T) {
+		sut, _, _, _, _ := createAlertRuleTemplateSvcSut(t, template)
+		instance := newInstance("instance-a", "prom-a")
+		instance.Values = nil
+
+		_, err := sut.CreateAlertRuleTemplateInstance(context.Background(), u, instance)
+		require.ErrorIs(t, err, ErrAlertRuleTemplateInstanceInvalid)
+	})
+
+	t.Run("update template should update the rules of all instances", func(t *testing.T) {
+		sut, st, ruleStore, _, _ := createAlertRuleTemplateSvcSut(t, template)
+		for _, ds := range []string{"prom-a", "prom-b"} {
+			_, err := sut.CreateAlertRuleTemplateInstance(context.Background(), u, newInstance("instance-"+ds, ds))
+			require.NoError(t, err)
+		}
+		ruleStore.RecordedOps = nil
+
+		update := template
+		update.Rule.Title = "CPU usage on ${datasource}"
+		updated, err := sut.UpdateAlertRuleTemplate(context.Background(), u, update)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, updated.Version)
+		require.EqualValues(t, 2, st.templates[template.UID].Version)
+
+		var titles []string
+		for _, op := range ruleStore.RecordedOps {
+			if updates, ok := op.([]models.UpdateRule); ok {
+				for _, upd := range updates {
+					require.EqualValues(t, 2, upd.New.Metadata.RuleTemplate.TemplateVersion)
+					titles = append(titles, upd.New.Title)
+				}
+			}
+		}
+		require.ElementsMatch(t, []string{"CPU usage on prom-a", "CPU usage on prom-b"}, titles)
+	})
+
+	t.Run("update template should fail if an instance cannot be instantiated", func(t *testing.T) {
+		sut, _, _, _, _ := createAlertRuleTemplateSvcSut(t, template)
+		_, err := sut.CreateAlertRuleTemplateInstance(context.Background(), u, newInstance("instance-a", "prom-a"))
+		require.NoError(t, err)
+
+		update := template
+		update.Parameters = append(update.Parameters, models.AlertRuleTemplateParameter{Name: "team", Type: models.AlertRuleTemplateParameterString})
+		_, err = sut.UpdateAlertRuleTemplate(context.Background(), u, update)
+		require.ErrorIs(t, err, ErrAlertRuleTemplateInvalid)
+	})
+
+	t.Run("update template should fail if the version does not match", func(t *testing.T) {
+		sut, _, _, _, _ := createAlertRuleTemplateSvcSut(t, template)
+		update := template
+		update.Version = 3
+
+		_, err := sut.UpdateAlertRuleTemplate(context.Background(), u, update)
+		require.ErrorIs(t, err, ErrVersionConflict)
+	})
+
+	t.Run("delete template should fail if it has instances", func(t *testing.T) {
+		sut, _, _, _, _ := createAlertRuleTemplateSvcSut(t, template)
+		_, err := sut.CreateAlertRuleTemplateInstance(context.Background(), u, newInstance("instance-a", "prom-a"))
+		require.NoError(t, err)
+
+		err = sut.DeleteAlertRuleTemplate(context.Background(), u, template.UID, models.ProvenanceNone)
+		require.ErrorIs(t, err, ErrAlertRuleTemplateInUse)
+	})
+
+	t.Run("delete instance should delete its rule", func(t *testing.T) {
+		sut, st, ruleStore, prov, _ := createAlertRuleTemplateSvcSut(t, template)
+		_, err := sut.CreateAlertRuleTemplateInstance(context.Background(), u, newInstance("instance-a", "prom-a"))
+		require.NoError(t, err)
+
+		require.NoError(t, sut.DeleteAlertRuleTemplateInstance(context.Background(), u, template.UID, "instance-a", models.ProvenanceNone))
+		require.Empty(t, st.instances)
+		require.Empty(t, ruleStore.Rules[orgID])
+
+		provenance, err := prov.GetProvenance(context.Background(), &models.AlertRule{UID: "instance-a"}, orgID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceNone, provenance)
+
+		require.NoError(t, sut.DeleteAlertRuleTemplateInstance(context.Background(), u, template.UID, "instance-a", models.ProvenanceNone))
+	})
+}
+
+func createAlertRuleTemplateSvcSut(t *testing.T, templates ...models.AlertRuleTemplate) (*AlertRuleTemplateService, *fakeAlertRuleTemplateStore, *fakes.RuleStore, *fakes.FakeProvisioningStore, *fakeRuleAccessControlService) {
+	ruleService, ruleStore, prov, ac := initService(t)
+	st := &fakeAlertRuleTemplateStore{
+		templates: map[string]models.AlertRuleTemplate{},
+		instances: map[string]models.AlertRuleTemplateInstance{},
+	}
+	for _, template := range templates {
+		st.templates[template.UID] = template
+	}
+	return &AlertRuleTemplateService{
+		store:           st,
+		provenanceStore: prov,
+		xact:            newNopTransactionManager(),
+		rules:           ruleService,
+		log:             log.NewNopLogger(),
+		validator: func(from, to models.Provenance) error {
+			return nil
+		},
+	}, st, ruleStore, prov, ac
+}
@@ -551,10 +551,27 @@ func (service *AlertRuleService) calcDelta(ctx context.Context, user identity.Re
 }
 
 func (service *AlertRuleService) persistDelta(ctx context.Context, user identity.Requester, delta *store.GroupDelta, provenance models.Provenance) error {
+	return service.persistDeltaOfRules(ctx, user, delta, provenance, nil)
+}
+
+// persistDeltaOfRules persists the changes of the delta. The provenance is checked and set only for the rules with
+// the given UIDs, or for all the rules of the delta if owned is nil. The other rules of the delta keep their
+// provenance, so that the rules of alert rule template instances can share a group with other rules.
+func (service *AlertRuleService) persistDeltaOfRules(ctx context.Context, user identity.Requester, delta *store.GroupDelta, provenance models.Provenance, owned map[string]struct{}) error {
+	isOwned := func(uid string) bool {
+		if owned == nil {
+			return true
+		}
+		_, ok := owned[uid]
+		return ok
+	}
 	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
 		// Delete first as this could prevent future unique constraint violations.
 		if len(delta.Delete) > 0 {
 			for _, del := range delta.Delete {
+				if !isOwned(del.UID) {
+					continue
+				}
 				// check that provenance is not changed in an invalid way
 				storedProvenance, err := service.provenanceStore.GetProvenance(ctx, del, user.GetOrgID())
 				if err != nil {
@@ -578,19 +595,21 @@ func (service *AlertRuleService) persistDelta(ctx context.Context, user identity
 		if len(delta.Update) > 0 {
 			updates := make([]models.UpdateRule, 0, len(delta.Update))
 			for _, update := range delta.Update {
-				// check that provenance is not changed in an invalid way
-				storedProvenance, err := service.provenanceStore.GetProvenance(ctx, update.New, user.GetOrgID())
-				if err != nil {
-					return err
-				}
-				if canUpdate := validation.CanUpdateProvenanceInRuleGroup(storedProvenance, provenance); !canUpdate {
-					return errProvenanceMismatch.Build(errutil.TemplateData{
-						Public: map[string]interface{}{
-							"ProvidedProvenance": provenance,
-							"StoredProvenance":   storedProvenance,
-							"Operation":          "update",
-						},
-					})
+				if isOwned(update.New.UID) {
+					// check that provenance is not changed in an invalid way
+					storedProvenance, err := service.provenanceStore.GetProvenance(ctx, update.New, user.GetOrgID())
+					if err != nil {
+						return err
+					}
+					if canUpdate := validation.CanUpdateProvenanceInRuleGroup(storedProvenance, provenance); !canUpdate {
+						return errProvenanceMismatch.Build(errutil.TemplateData{
+							Public: map[string]interface{}{
+								"ProvidedProvenance": provenance,
+								"StoredProvenance":   storedProvenance,
+								"Operation":          "update",
+							},
+						})
+					}
 				}
 				updates = append(updates, models.UpdateRule{
 					Existing: update.Existing,
@@ -601,6 +620,9 @@ func (service *AlertRuleService) persistDelta(ctx context.Context, user identity
 				return fmt.Errorf("failed to update alert rules: %w", err)
 			}
 			for _, update := range delta.Update {
+				if !isOwned(update.New.UID) {
+					continue
+				}
 				if err := service.provenanceStore.SetProvenance(ctx, update.New, user.GetOrgID(), provenance); err != nil {
 					return err
 				}
@@ -613,6 +635,9 @@ func (service *AlertRuleService) persistDelta(ctx context.Context, user identity
 				return fmt.Errorf("failed to insert alert rules: %w", err)
 			}
 			for _, key := range uids {
+				if !isOwned(key.UID) {
+					continue
+				}
 				if err := service.provenanceStore.SetProvenance(ctx, &models.AlertRule{UID: key.UID}, user.GetOrgID(), provenance); err != nil {
 					return err
 				}
@@ -757,6 +782,8 @@ func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, user ident
 		if err = service.authz.AuthorizeRuleGroupWrite(ctx, user, delta); err != nil {
 			return err
 		}
+	} else if err := store.ValidateRulesDelete(ctx, service.ruleStore, rule.OrgID, rule.UID); err != nil {
+		return err
 	}
 
 	// The single delete is idempotent, and doesn't error when deleting a group that already doesn't exist.
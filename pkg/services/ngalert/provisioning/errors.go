@@ -22,6 +22,18 @@ var (
 		errutil.WithPublic(`Time interval cannot be renamed because it is used by provisioned {{ if .Public.UsedByRules }}alert rules{{ end }}{{ if .Public.UsedByRoutes }}{{ if .Public.UsedByRules }} and {{ end }}notification policies{{ end }}. You must update those resources first using the original provision method.`),
 	)
 
+	ErrRecurringSilenceNotFound = errutil.NotFound("alerting.notifications.recurring-silences.notFound")
+	ErrRecurringSilenceExists   = errutil.BadRequest("alerting.notifications.recurring-silences.nameExists", errutil.WithPublicMessage("Recurring silence with this name already exists. Use a different name or update existing one."))
+	ErrRecurringSilenceInvalid  = errutil.BadRequest("alerting.notifications.recurring-silences.invalidFormat").MustTemplate("Invalid format of the submitted recurring silence", errutil.WithPublic("Recurring silence is in invalid format: {{.Public.Error}}. Correct the payload and try again."))
+
+	ErrAlertRuleTemplateNotFound         = errutil.NotFound("alerting.rule-templates.notFound")
+	ErrAlertRuleTemplateExists           = errutil.BadRequest("alerting.rule-templates.nameExists", errutil.WithPublicMessage("Alert rule template with this name already exists. Use a different name or update existing one."))
+	ErrAlertRuleTemplateInvalid          = errutil.BadRequest("alerting.rule-templates.invalidFormat").MustTemplate("Invalid format of the submitted alert rule template", errutil.WithPublic("Alert rule template is in invalid format: {{.Public.Error}}. Correct the payload and try again."))
+	ErrAlertRuleTemplateInUse            = errutil.Conflict("alerting.rule-templates.used", errutil.WithPublicMessage("Alert rule template is used by instances. Delete the instances first."))
+	ErrAlertRuleTemplateInstanceNotFound = errutil.NotFound("alerting.rule-templates.instances.notFound")
+	ErrAlertRuleTemplateInstanceExists   = errutil.BadRequest("alerting.rule-templates.instances.uidExists", errutil.WithPublicMessage("Alert rule template instance or alert rule with this UID already exists. Use a different UID or update existing one."))
+	ErrAlertRuleTemplateInstanceInvalid  = errutil.BadRequest("alerting.rule-templates.instances.invalidFormat").MustTemplate("Invalid format of the submitted alert rule template instance", errutil.WithPublic("Alert rule template instance is invalid: {{.Public.Error}}. Correct the payload and try again."))
+
 	ErrTemplateNotFound = errutil.NotFound("alerting.notifications.templates.notFound")
 	ErrTemplateInvalid  = errutil.BadRequest("alerting.notifications.templates.invalidFormat").MustTemplate("Invalid format of the submitted template", errutil.WithPublic("Template is in invalid format. Correct the payload and try again."))
 	ErrTemplateExists   = errutil.BadRequest("alerting.notifications.templates.nameExists", errutil.WithPublicMessage("Template file with this name already exists. Use a different name or update existing one."))
@@ -74,6 +86,34 @@ func MakeErrTimeIntervalInUse(usedByRoutes bool, rules []models.AlertRuleKey) er
 	})
 }
 
+// MakeErrRecurringSilenceInvalid creates an error with the ErrRecurringSilenceInvalid template
+func MakeErrRecurringSilenceInvalid(err error) error {
+	return ErrRecurringSilenceInvalid.Build(errutil.TemplateData{
+		Public: map[string]any{
+			"Error": err.Error(),
+		},
+		Error: err,
+	})
+}
+
+func MakeErrAlertRuleTemplateInvalid(err error) error {
+	return ErrAlertRuleTemplateInvalid.Build(errutil.TemplateData{
+		Public: map[string]any{
+			"Error": err.Error(),
+		},
+		Error: err,
+	})
+}
+
+func MakeErrAlertRuleTemplateInstanceInvalid(err error) error {
+	return ErrAlertRuleTemplateInstanceInvalid.Build(errutil.TemplateData{
+		Public: map[string]any{
+			"Error": err.Error(),
+		},
+		Error: err,
+	})
+}
+
 // MakeErrTimeIntervalInvalid creates an error with the ErrTimeIntervalInvalid template
 func MakeErrTemplateInvalid(err error) error {
 	data := errutil.TemplateData{
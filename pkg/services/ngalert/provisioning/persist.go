@@ -39,6 +39,29 @@ type RuleStore interface {
 	GetAlertRulesGroupByRuleUID(ctx context.Context, query *models.GetAlertRulesGroupByRuleUIDQuery) ([]*models.AlertRule, error)
 }
 
+// RecurringSilenceStore represents the ability to persist and query recurring silences.
+type RecurringSilenceStore interface {
+	ListRecurringSilences(ctx context.Context, query *models.ListRecurringSilencesQuery) ([]*models.RecurringSilence, error)
+	GetRecurringSilence(ctx context.Context, orgID int64, uid string) (*models.RecurringSilence, error)
+	InsertRecurringSilence(ctx context.Context, s models.RecurringSilence) (*models.RecurringSilence, error)
+	UpdateRecurringSilence(ctx context.Context, s models.RecurringSilence) (*models.RecurringSilence, error)
+	DeleteRecurringSilence(ctx context.Context, orgID int64, uid string) error
+}
+
+// AlertRuleTemplateStore represents the ability to persist and query alert rule templates and their instances.
+type AlertRuleTemplateStore interface {
+	ListAlertRuleTemplates(ctx context.Context, orgID int64) ([]*models.AlertRuleTemplate, error)
+	GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (*models.AlertRuleTemplate, error)
+	InsertAlertRuleTemplate(ctx context.Context, t models.AlertRuleTemplate) (*models.AlertRuleTemplate, error)
+	UpdateAlertRuleTemplate(ctx context.Context, t models.AlertRuleTemplate) (*models.AlertRuleTemplate, error)
+	DeleteAlertRuleTemplate(ctx context.Context, orgID int64, uid string) error
+	ListAlertRuleTemplateInstances(ctx context.Context, orgID int64, templateUID string) ([]*models.AlertRuleTemplateInstance, error)
+	GetAlertRuleTemplateInstance(ctx context.Context, orgID int64, uid string) (*models.AlertRuleTemplateInstance, error)
+	InsertAlertRuleTemplateInstance(ctx context.Context, instance models.AlertRuleTemplateInstance) (*models.AlertRuleTemplateInstance, error)
+	UpdateAlertRuleTemplateInstance(ctx context.Context, instance models.AlertRuleTemplateInstance) (*models.AlertRuleTemplateInstance, error)
+	DeleteAlertRuleTemplateInstance(ctx context.Context, orgID int64, uid string) error
+}
+
 // QuotaChecker represents the ability to evaluate whether quotas are met.
 //
 //go:generate mockery --name QuotaChecker --structname MockQuotaChecker --inpackage --filename quota_checker_mock.go --with-expecter
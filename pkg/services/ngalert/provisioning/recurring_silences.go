@@ -0,0 +1,211 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning/validation"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+type RecurringSilenceService struct {
+	store           RecurringSilenceStore
+	provenanceStore ProvisioningStore
+	xact            TransactionManager
+	log             log.Logger
+	validator       validation.ProvenanceStatusTransitionValidator
+}
+
+func NewRecurringSilenceService(store RecurringSilenceStore, prov ProvisioningStore, xact TransactionManager, log log.Logger) *RecurringSilenceService {
+	return &RecurringSilenceService{
+		store:           store,
+		provenanceStore: prov,
+		xact:            xact,
+		log:             log,
+		validator:       validation.ValidateProvenanceRelaxed,
+	}
+}
+
+// GetRecurringSilences returns all recurring silences within the specified org.
+func (svc *RecurringSilenceService) GetRecurringSilences(ctx context.Context, orgID int64) ([]models.RecurringSilence, error) {
+	silences, err := svc.store.ListRecurringSilences(ctx, &models.ListRecurringSilencesQuery{OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	if len(silences) == 0 {
+		return []models.RecurringSilence{}, nil
+	}
+
+	provenances, err := svc.provenanceStore.GetProvenances(ctx, orgID, (&models.RecurringSilence{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.RecurringSilence, 0, len(silences))
+	for _, s := range silences {
+		if prov, ok := provenances[s.ResourceID()]; ok {
+			s.Provenance = prov
+		}
+		result = append(result, *s)
+	}
+	return result, nil
+}
+
+// GetRecurringSilence returns a recurring silence by UID.
+func (svc *RecurringSilenceService) GetRecurringSilence(ctx context.Context, uid string, orgID int64) (models.RecurringSilence, error) {
+	s, err := svc.store.GetRecurringSilence(ctx, orgID, uid)
+	if err != nil {
+		if errors.Is(err, models.ErrRecurringSilenceNotFound) {
+			return models.RecurringSilence{}, ErrRecurringSilenceNotFound.Errorf("")
+		}
+		return models.RecurringSilence{}, err
+	}
+
+	prov, err := svc.provenanceStore.GetProvenance(ctx, s, orgID)
+	if err != nil {
+		return models.RecurringSilence{}, err
+	}
+	s.Provenance = prov
+	return *s, nil
+}
+
+// CreateRecurringSilence adds a new recurring silence within the specified org. The created recurring silence is returned.
+func (svc *RecurringSilenceService) CreateRecurringSilence(ctx context.Context, s models.RecurringSilence, orgID int64) (models.RecurringSilence, error) {
+	if err := s.Validate(); err != nil {
+		return models.RecurringSilence{}, MakeErrRecurringSilenceInvalid(err)
+	}
+	s.OrgID = orgID
+
+	var created *models.RecurringSilence
+	err := svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = svc.store.InsertRecurringSilence(ctx, s)
+		if err != nil {
+			if errors.Is(err, models.ErrRecurringSilenceExists) {
+				return ErrRecurringSilenceExists.Errorf("")
+			}
+			return err
+		}
+		return svc.provenanceStore.SetProvenance(ctx, created, orgID, s.Provenance)
+	})
+	if err != nil {
+		return models.RecurringSilence{}, err
+	}
+	created.Provenance = s.Provenance
+	return *created, nil
+}
+
+// UpdateRecurringSilence replaces an existing recurring silence within the specified org. The recurring silence is
+// looked up by UID or, if the UID is empty, by name. If the version is not zero, it must match the current version.
+// The updated recurring silence is returned.
+func (svc *RecurringSilenceService) UpdateRecurringSilence(ctx context.Context, s models.RecurringSilence, orgID int64) (models.RecurringSilence, error) {
+	if err := s.Validate(); err != nil {
+		return models.RecurringSilence{}, MakeErrRecurringSilenceInvalid(err)
+	}
+	s.OrgID = orgID
+
+	existing, err := svc.find(ctx, orgID, s.UID, s.Name)
+	if err != nil {
+		return models.RecurringSilence{}, err
+	}
+	if existing == nil {
+		return models.RecurringSilence{}, ErrRecurringSilenceNotFound.Errorf("")
+	}
+
+	// check optimistic concurrency
+	if s.Version == 0 {
+		if s.Provenance != models.ProvenanceFile {
+			svc.log.FromContext(ctx).Debug("Ignoring optimistic concurrency check because version was not provided", "recurringSilence", existing.UID, "operation", "update")
+		}
+		s.Version = existing.Version
+	} else if s.Version != existing.Version {
+		return models.RecurringSilence{}, ErrVersionConflict.Errorf("provided version %d of recurring silence %s does not match current version %d", s.Version, existing.UID, existing.Version)
+	}
+
+	// check that provenance is not changed in an invalid way
+	storedProvenance, err := svc.provenanceStore.GetProvenance(ctx, existing, orgID)
+	if err != nil {
+		return models.RecurringSilence{}, err
+	}
+	if err := svc.validator(storedProvenance, s.Provenance); err != nil {
+		return models.RecurringSilence{}, err
+	}
+
+	s.UID = existing.UID
+	s.CreatedBy = existing.CreatedBy
+	var updated *models.RecurringSilence
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = svc.store.UpdateRecurringSilence(ctx, s)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrRecurringSilenceNotFound):
+				return ErrRecurringSilenceNotFound.Errorf("")
+			case errors.Is(err, models.ErrRecurringSilenceExists):
+				return ErrRecurringSilenceExists.Errorf("")
+			case errors.Is(err, store.ErrOptimisticLock):
+				return ErrVersionConflict.Errorf("recurring silence %s was updated concurrently", s.UID)
+			}
+			return err
+		}
+		return svc.provenanceStore.SetProvenance(ctx, updated, orgID, s.Provenance)
+	})
+	if err != nil {
+		return models.RecurringSilence{}, err
+	}
+	updated.Provenance = s.Provenance
+	return *updated, nil
+}
+
+// DeleteRecurringSilence deletes the recurring silence with the given UID in the given org. The silences created from it
+// expire once the change is picked up by the alertmanagers. If the recurring silence does not exist, no error is returned.
+func (svc *RecurringSilenceService) DeleteRecurringSilence(ctx context.Context, uid string, orgID int64, provenance models.Provenance) error {
+	existing, err := svc.store.GetRecurringSilence(ctx, orgID, uid)
+	if err != nil {
+		if errors.Is(err, models.ErrRecurringSilenceNotFound) {
+			svc.log.FromContext(ctx).Debug("Recurring silence was not found. Skip deleting", "uid", uid)
+			return nil
+		}
+		return err
+	}
+
+	// check that provenance is not changed in an invalid way
+	storedProvenance, err := svc.provenanceStore.GetProvenance(ctx, existing, orgID)
+	if err != nil {
+		return err
+	}
+	if err := svc.validator(storedProvenance, provenance); err != nil {
+		return err
+	}
+
+	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.DeleteRecurringSilence(ctx, orgID, uid); err != nil {
+			return err
+		}
+		return svc.provenanceStore.DeleteProvenance(ctx, existing, orgID)
+	})
+}
+
+// find returns the recurring silence with the given UID or, if the UID is empty, with the given name. It returns nil if
+// it does not exist.
+func (svc *RecurringSilenceService) find(ctx context.Context, orgID int64, uid, name string) (*models.RecurringSilence, error) {
+	if uid != "" {
+		s, err := svc.store.GetRecurringSilence(ctx, orgID, uid)
+		if errors.Is(err, models.ErrRecurringSilenceNotFound) {
+			return nil, nil
+		}
+		return s, err
+	}
+	silences, err := svc.store.ListRecurringSilences(ctx, &models.ListRecurringSilencesQuery{OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range silences {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,176 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+type fakeRecurringSilenceStore struct {
+	silences map[string]models.RecurringSilence
+}
+
+func (f *fakeRecurringSilenceStore) ListRecurringSilences(_ context.Context, query *models.ListRecurringSilencesQuery) ([]*models.RecurringSilence, error) {
+	var result []*models.RecurringSilence
+	for _, s := range f.silences {
+		if query.OrgID == 0 || s.OrgID == query.OrgID {
+			result = append(result, &s)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeRecurringSilenceStore) GetRecurringSilence(_ context.Context, orgID int64, uid string) (*models.RecurringSilence, error) {
+	s, ok := f.silences[uid]
+	if !ok || s.OrgID != orgID {
+		return nil, models.ErrRecurringSilenceNotFound
+	}
+	return &s, nil
+}
+
+func (f *fakeRecurringSilenceStore) InsertRecurringSilence(_ context.Context, s models.RecurringSilence) (*models.RecurringSilence, error) {
+	for _, existing := range f.silences {
+		if existing.OrgID == s.OrgID && (existing.UID == s.UID || existing.Name == s.Name) {
+			return nil, models.ErrRecurringSilenceExists
+		}
+	}
+	if s.UID == "" {
+		s.UID = s.Name
+	}
+	s.Version = 1
+	f.silences[s.UID] = s
+	return &s, nil
+}
+
+func (f *fakeRecurringSilenceStore) UpdateRecurringSilence(_ context.Context, s models.RecurringSilence) (*models.RecurringSilence, error) {
+	existing, ok := f.silences[s.UID]
+	if !ok {
+		return nil, models.ErrRecurringSilenceNotFound
+	}
+	if existing.Version != s.Version {
+		return nil, store.ErrOptimisticLock
+	}
+	s.Version++
+	f.silences[s.UID] = s
+	return &s, nil
+}
+
+func (f *fakeRecurringSilenceStore) DeleteRecurringSilence(_ context.Context, _ int64, uid string) error {
+	delete(f.silences, uid)
+	return nil
+}
+
+func TestRecurringSilenceService(t *testing.T) {
+	orgID := int64(1)
+	newSilence := func(name string) models.RecurringSilence {
+		return models.RecurringSilence{
+			Name:     name,
+			Matchers: labels.Matchers{labels.MustNewMatcher(labels.MatchEqual, "env", "prod")},
+			Cron:     "0 2 * * 0",
+			Duration: 2 * time.Hour,
+		}
+	}
+
+	t.Run("create should validate the recurring silence", func(t *testing.T) {
+		sut, _, _ := createRecurringSilenceSvcSut()
+		s := newSilence("maintenance")
+		s.Cron = "not a cron"
+		_, err := sut.CreateRecurringSilence(context.Background(), s, orgID)
+		require.ErrorIs(t, err, ErrRecurringSilenceInvalid)
+	})
+
+	t.Run("create should save the recurring silence and its provenance", func(t *testing.T) {
+		sut, st, prov := createRecurringSilenceSvcSut()
+		prov.EXPECT().SetProvenance(mock.Anything, mock.Anything, orgID, models.ProvenanceFile).Return(nil)
+		s := newSilence("maintenance")
+		s.Provenance = models.ProvenanceFile
+
+		created, err := sut.CreateRecurringSilence(context.Background(), s, orgID)
+		require.NoError(t, err)
+		require.Equal(t, orgID, created.OrgID)
+		require.Equal(t, models.ProvenanceFile, created.Provenance)
+		require.Contains(t, st.silences, created.UID)
+
+		_, err = sut.CreateRecurringSilence(context.Background(), s, orgID)
+		require.ErrorIs(t, err, ErrRecurringSilenceExists)
+	})
+
+	t.Run("update should find the recurring silence by name if the UID is empty", func(t *testing.T) {
+		sut, st, prov := createRecurringSilenceSvcSut()
+		st.silences["uid"] = models.RecurringSilence{UID: "uid", OrgID: orgID, Name: "maintenance", Version: 3}
+		prov.EXPECT().GetProvenance(mock.Anything, mock.Anything, orgID).Return(models.ProvenanceFile, nil)
+		prov.EXPECT().SetProvenance(mock.Anything, mock.Anything, orgID, models.ProvenanceFile).Return(nil)
+		s := newSilence("maintenance")
+		s.Comment = "updated"
+		s.Provenance = models.ProvenanceFile
+
+		updated, err := sut.UpdateRecurringSilence(context.Background(), s, orgID)
+		require.NoError(t, err)
+		require.Equal(t, "uid", updated.UID)
+		require.EqualValues(t, 4, updated.Version)
+		require.Equal(t, "updated", st.silences["uid"].Comment)
+	})
+
+	t.Run("update should fail if the version does not match", func(t *testing.T) {
+		sut, st, _ := createRecurringSilenceSvcSut()
+		st.silences["uid"] = models.RecurringSilence{UID: "uid", OrgID: orgID, Name: "maintenance", Version: 3}
+		s := newSilence("maintenance")
+		s.UID = "uid"
+		s.Version = 2
+
+		_, err := sut.UpdateRecurringSilence(context.Background(), s, orgID)
+		require.ErrorIs(t, err, ErrVersionConflict)
+	})
+
+	t.Run("update should fail if the recurring silence does not exist", func(t *testing.T) {
+		sut, _, _ := createRecurringSilenceSvcSut()
+		s := newSilence("maintenance")
+		s.UID = "uid"
+
+		_, err := sut.UpdateRecurringSilence(context.Background(), s, orgID)
+		require.ErrorIs(t, err, ErrRecurringSilenceNotFound)
+	})
+
+	t.Run("delete should validate the provenance", func(t *testing.T) {
+		sut, st, prov := createRecurringSilenceSvcSut()
+		sut.validator = func(from, to models.Provenance) error {
+			if from != to {
+				return ErrVersionConflict.Errorf("")
+			}
+			return nil
+		}
+		st.silences["uid"] = models.RecurringSilence{UID: "uid", OrgID: orgID, Name: "maintenance", Version: 1}
+		prov.EXPECT().GetProvenance(mock.Anything, mock.Anything, orgID).Return(models.ProvenanceFile, nil)
+		prov.EXPECT().DeleteProvenance(mock.Anything, mock.Anything, orgID).Return(nil)
+
+		require.Error(t, sut.DeleteRecurringSilence(context.Background(), "uid", orgID, models.ProvenanceAPI))
+		require.Contains(t, st.silences, "uid")
+
+		require.NoError(t, sut.DeleteRecurringSilence(context.Background(), "uid", orgID, models.ProvenanceFile))
+		require.NotContains(t, st.silences, "uid")
+
+		require.NoError(t, sut.DeleteRecurringSilence(context.Background(), "uid", orgID, models.ProvenanceFile))
+	})
+}
+
+func createRecurringSilenceSvcSut() (*RecurringSilenceService, *fakeRecurringSilenceStore, *MockProvisioningStore) {
+	st := &fakeRecurringSilenceStore{silences: map[string]models.RecurringSilence{}}
+	prov := &MockProvisioningStore{}
+	return &RecurringSilenceService{
+		store:           st,
+		provenanceStore: prov,
+		xact:            newNopTransactionManager(),
+		log:             log.NewNopLogger(),
+		validator: func(from, to models.Provenance) error {
+			return nil
+		},
+	}, st, prov
+}
@@ -67,10 +67,15 @@ func newRuleFactory(
 	tracer tracing.Tracer,
 	featureToggles featuremgmt.FeatureToggles,
 	recordingWriter RecordingWriter,
+	handoverGracePeriod func(rule *ngmodels.AlertRule) time.Duration,
 	evalAppliedHook evalAppliedFunc,
 	stopAppliedHook stopAppliedFunc,
 ) ruleFactoryFunc {
 	return func(ctx context.Context, rule *ngmodels.AlertRule) Rule {
+		var gracePeriod time.Duration
+		if handoverGracePeriod != nil {
+			gracePeriod = handoverGracePeriod(rule)
+		}
 		if rule.Type() == ngmodels.RuleTypeRecording {
 			return newRecordingRule(
 				ctx,
@@ -101,6 +106,7 @@ func newRuleFactory(
 			logger,
 			tracer,
 			featureToggles,
+			gracePeriod,
 			evalAppliedHook,
 			stopAppliedHook,
 		)
@@ -135,6 +141,11 @@ type alertRule struct {
 	logger         log.Logger
 	tracer         tracing.Tracer
 	featureToggles featuremgmt.FeatureToggles
+
+	// handoverGracePeriod, if not zero, makes the routine wait for this long after it starts before it evaluates
+	// the rule, and then load the state of the rule from the instance store. The rule could have been evaluated
+	// by another instance of Grafana until now, which needs some time to notice the handover and stop.
+	handoverGracePeriod time.Duration
 }
 
 func newAlertRule(
@@ -151,6 +162,7 @@ func newAlertRule(
 	logger log.Logger,
 	tracer tracing.Tracer,
 	featureToggles featuremgmt.FeatureToggles,
+	handoverGracePeriod time.Duration,
 	evalAppliedHook func(ngmodels.AlertRuleKey, time.Time),
 	stopAppliedHook func(ngmodels.AlertRuleKey),
 ) *alertRule {
@@ -174,6 +186,7 @@ func newAlertRule(
 		logger:               logger.FromContext(ctx),
 		tracer:               tracer,
 		featureToggles:       featureToggles,
+		handoverGracePeriod:  handoverGracePeriod,
 	}
 }
 
@@ -246,6 +259,7 @@ func (a *alertRule) Run() error {
 	a.logger.Debug("Alert rule routine started")
 
 	var currentFingerprint fingerprint
+	startedAt := a.clock.Now()
 	defer a.stopApplied()
 	for {
 		select {
@@ -269,6 +283,14 @@ func (a *alertRule) Run() error {
 			}
 			f := ctx.Fingerprint()
 			logger := a.logger.New("version", ctx.rule.Version, "fingerprint", f, "now", ctx.scheduledAt)
+			if until := startedAt.Add(a.handoverGracePeriod); currentFingerprint == 0 && a.clock.Now().Before(until) {
+				logger.Debug("Skip rule evaluation until the instance that evaluated the rule before stops", "until", until)
+				a.evalApplied(ctx.scheduledAt)
+				if ctx.afterEval != nil {
+					ctx.afterEval()
+				}
+				continue
+			}
 			logger.Debug("Processing tick")
 
 			func() {
@@ -297,6 +319,15 @@ func (a *alertRule) Run() error {
 					needReset = needReset || (currentFingerprint == 0 && isPaused)
 					if needReset {
 						a.resetState(grafanaCtx, ctx.rule, isPaused)
+					} else if currentFingerprint == 0 && a.handoverGracePeriod > 0 {
+						// The evaluation of the rule could have been handed over by another instance, so continue
+						// from the state it persisted instead of the one loaded at startup.
+						loaded, err := a.stateManager.LoadStateByRuleUID(grafanaCtx, ctx.rule)
+						if err != nil {
+							logger.Error("Failed to load the state of the rule", "error", err)
+						} else {
+							logger.Debug("Loaded the state of the rule", "states", loaded)
+						}
 					}
 					currentFingerprint = f
 					if isPaused {
@@ -461,6 +492,7 @@ func (a *alertRule) evaluate(ctx context.Context, e *Evaluation, span trace.Span
 		state.GetRuleExtraLabels(logger, e.rule, e.folderTitle, !a.disableGrafanaFolder),
 		func(ctx context.Context, statesToSend state.StateTransitions) {
 			start := a.clock.Now()
+			statesToSend = a.dropInhibited(logger, e.rule, statesToSend)
 			alerts := a.send(ctx, logger, statesToSend)
 			span.AddEvent("results sent", trace.WithAttributes(
 				attribute.Int64("alerts_sent", int64(len(alerts.PostableAlerts))),
@@ -473,6 +505,47 @@ func (a *alertRule) evaluate(ctx context.Context, e *Evaluation, span trace.Span
 	return nil
 }
 
+// dropInhibited removes the alerts that started firing while a rule the rule depends on is firing,
+// so they are not sent until all these rules are resolved. Alerts that were already firing are kept
+// so that they are not resolved by the Alertmanager.
+func (a *alertRule) dropInhibited(logger log.Logger, rule *ngmodels.AlertRule, states state.StateTransitions) state.StateTransitions {
+	if len(rule.DependsOn) == 0 || len(states) == 0 {
+		return states
+	}
+	since, inhibited := a.inhibitedSince(rule)
+	if !inhibited {
+		return states
+	}
+	result := make(state.StateTransitions, 0, len(states))
+	for _, s := range states {
+		if s.State.State != eval.Normal && !s.State.StartsAt.Before(since) {
+			continue
+		}
+		result = append(result, s)
+	}
+	if dropped := len(states) - len(result); dropped > 0 {
+		logger.Debug("Alerts are inhibited because a rule the rule depends on is firing", "inhibited", dropped, "since", since)
+	}
+	return result
+}
+
+// inhibitedSince returns the time the earliest firing alert of the rules the rule depends on started firing,
+// and false if none of them is firing.
+func (a *alertRule) inhibitedSince(rule *ngmodels.AlertRule) (time.Time, bool) {
+	var since time.Time
+	for _, uid := range rule.DependsOn {
+		for _, s := range a.stateManager.GetStatesForRuleUID(rule.OrgID, uid) {
+			if s.State != eval.Alerting && s.State != eval.Recovering {
+				continue
+			}
+			if since.IsZero() || s.StartsAt.Before(since) {
+				since = s.StartsAt
+			}
+		}
+	}
+	return since, !since.IsZero()
+}
+
 // send sends alerts for the given state transitions.
 func (a *alertRule) send(ctx context.Context, logger log.Logger, states state.StateTransitions) definitions.PostableAlerts {
 	alerts := definitions.PostableAlerts{PostableAlerts: make([]models.PostableAlert, 0, len(states))}
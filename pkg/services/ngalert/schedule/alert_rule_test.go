@@ -502,7 +502,43 @@ func blankRuleForTests(ctx context.Context, key models.AlertRuleKeyWithGroup) *a
 		Log:       log.NewNopLogger(),
 	}
 	st := state.NewManager(managerCfg, state.NewNoopPersister())
-	return newAlertRule(ctx, key, nil, false, 0, nil, st, nil, nil, nil, log.NewNopLogger(), nil, featuremgmt.WithFeatures(), nil, nil)
+	return newAlertRule(ctx, key, nil, false, 0, nil, st, nil, nil, nil, log.NewNopLogger(), nil, featuremgmt.WithFeatures(), 0, nil, nil)
+}
+
+func TestAlertRuleDropInhibited(t *testing.T) {
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(1))
+	upstream := gen.GenerateRef()
+	rule := gen.With(models.RuleGen.WithDependsOn(upstream.UID)).GenerateRef()
+	r := blankRuleForTests(context.Background(), rule.GetKeyWithGroup())
+
+	now := time.Now()
+	transition := func(startsAt time.Time, s eval.State) state.StateTransition {
+		st := stateForRule(rule, startsAt, s)
+		st.Labels["instance"] = fmt.Sprintf("%s-%d", s, startsAt.Unix())
+		return state.StateTransition{State: st}
+	}
+	transitions := state.StateTransitions{
+		transition(now.Add(-time.Hour), eval.Alerting),
+		transition(now, eval.Alerting),
+		transition(now, eval.Normal),
+	}
+
+	t.Run("sends all alerts when the upstream rule is not firing", func(t *testing.T) {
+		r.stateManager.Put([]*state.State{stateForRule(upstream, now.Add(-time.Minute), eval.Pending)})
+		require.Equal(t, transitions, r.dropInhibited(log.NewNopLogger(), rule, transitions))
+	})
+
+	t.Run("drops the alerts that started firing after the upstream rule", func(t *testing.T) {
+		r.stateManager.Put([]*state.State{stateForRule(upstream, now.Add(-time.Minute), eval.Alerting)})
+		result := r.dropInhibited(log.NewNopLogger(), rule, transitions)
+		require.Equal(t, state.StateTransitions{transitions[0], transitions[2]}, result)
+	})
+
+	t.Run("sends all alerts of rules without dependencies", func(t *testing.T) {
+		noDeps := models.CopyRule(rule)
+		noDeps.DependsOn = nil
+		require.Equal(t, transitions, r.dropInhibited(log.NewNopLogger(), noDeps, transitions))
+	})
 }
 
 func TestRuleRoutine(t *testing.T) {
@@ -1126,7 +1162,7 @@ func TestRuleRoutine(t *testing.T) {
 }
 
 func ruleFactoryFromScheduler(sch *schedule) ruleFactory {
-	return newRuleFactory(sch.appURL, sch.disableGrafanaFolder, sch.maxAttempts, sch.alertsSender, sch.stateManager, sch.evaluatorFactory, sch.clock, sch.rrCfg, sch.metrics, sch.log, sch.tracer, sch.featureToggles, sch.recordingWriter, sch.evalAppliedFunc, sch.stopAppliedFunc)
+	return newRuleFactory(sch.appURL, sch.disableGrafanaFolder, sch.maxAttempts, sch.alertsSender, sch.stateManager, sch.evaluatorFactory, sch.clock, sch.rrCfg, sch.metrics, sch.log, sch.tracer, sch.featureToggles, sch.recordingWriter, nil, sch.evalAppliedFunc, sch.stopAppliedFunc)
 }
 
 func stateForRule(rule *models.AlertRule, ts time.Time, evalState eval.State) *state.State {
@@ -68,3 +68,18 @@ func jitterHash(r *ngmodels.AlertRule, strategy JitterStrategy) uint64 {
 	}
 	return uint64(ls.Fingerprint())
 }
+
+// NextEvaluationTime returns the first time at or after t at which the scheduler evaluates the rule.
+// The scheduler ticks at multiples of the base interval since the Unix epoch, and evaluates the rule on the ticks that
+// match its interval shifted by its jitter offset. We assume the rule interval is a multiple of baseInterval.
+func NextEvaluationTime(r *ngmodels.AlertRule, baseInterval time.Duration, strategy JitterStrategy, t time.Time) time.Time {
+	interval := (time.Duration(r.IntervalSeconds) * time.Second).Nanoseconds()
+	offset := (time.Duration(jitterOffsetInTicks(r, baseInterval, strategy)) * baseInterval).Nanoseconds()
+
+	nano := t.UnixNano() - offset
+	next := time.Unix(0, nano-((nano%interval)+interval)%interval+offset)
+	if next.Before(t) {
+		next = next.Add(time.Duration(interval))
+	}
+	return next
+}
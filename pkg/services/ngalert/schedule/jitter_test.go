@@ -92,4 +92,23 @@ func TestJitter(t *testing.T) {
 			}
 		})
 	})
+	t.Run("next evaluation time matches the ticks on which the scheduler runs the rule", func(t *testing.T) {
+		baseInterval := time.Second
+		rules := genWithInterval10to600.GenerateManyRef(100)
+		now := time.Unix(1700000000, 123456789)
+
+		for _, strategy := range []JitterStrategy{JitterNever, JitterByGroup, JitterByRule} {
+			for _, r := range rules {
+				next := NextEvaluationTime(r, baseInterval, strategy, now)
+				require.False(t, next.Before(now), "next evaluation cannot be before the given time")
+				require.Less(t, next.Sub(now), time.Duration(r.IntervalSeconds)*time.Second)
+
+				require.Zero(t, next.UnixNano()%baseInterval.Nanoseconds(), "next evaluation must be on a tick")
+				tickNum := next.Unix() / int64(baseInterval.Seconds())
+				require.Equal(t, jitterOffsetInTicks(r, baseInterval, strategy), tickNum%r.IntervalSeconds)
+
+				require.Equal(t, next, NextEvaluationTime(r, baseInterval, strategy, next), "a time on an evaluation tick must be kept")
+			}
+		}
+	})
 }
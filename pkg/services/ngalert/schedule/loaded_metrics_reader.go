@@ -9,6 +9,7 @@ import (
 )
 
 var _ eval.AlertingResultsReader = AlertingResultsFromRuleState{}
+var _ eval.AlertingSeveritiesReader = AlertingResultsFromRuleState{}
 
 func (a *alertRule) newLoadedMetricsReader(rule *ngmodels.AlertRule) eval.AlertingResultsReader {
 	return &AlertingResultsFromRuleState{
@@ -42,3 +43,22 @@ func (n AlertingResultsFromRuleState) Read() map[data.Fingerprint]struct{} {
 	}
 	return active
 }
+
+// ReadSeverities returns the severity of the results whose states are Alerting or Pending and have empty StateReason.
+func (n AlertingResultsFromRuleState) ReadSeverities() map[data.Fingerprint]string {
+	states := n.Manager.GetStatesForRuleUID(n.Rule.OrgID, n.Rule.UID)
+
+	severities := map[data.Fingerprint]string{}
+	for _, st := range states {
+		if st.StateReason != "" {
+			continue
+		}
+		if st.State != eval.Alerting && st.State != eval.Pending {
+			continue
+		}
+		if severity, ok := st.Labels[ngmodels.SeverityLabel]; ok {
+			severities[st.ResultFingerprint] = severity
+		}
+	}
+	return severities
+}
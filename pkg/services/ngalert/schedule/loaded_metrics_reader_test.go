@@ -53,6 +53,28 @@ func TestLoadedResultsFromRuleState(t *testing.T) {
 	})
 }
 
+func TestLoadedSeveritiesFromRuleState(t *testing.T) {
+	rule := ngmodels.RuleGen.GenerateRef()
+	p := &FakeRuleStateProvider{
+		map[ngmodels.AlertRuleKey][]*state.State{
+			rule.GetKey(): {
+				{State: eval.Alerting, ResultFingerprint: data.Fingerprint(1), Labels: data.Labels{ngmodels.SeverityLabel: "critical"}},
+				{State: eval.Pending, ResultFingerprint: data.Fingerprint(2), Labels: data.Labels{ngmodels.SeverityLabel: "warning"}},
+				{State: eval.Alerting, ResultFingerprint: data.Fingerprint(3)},
+				{State: eval.Normal, ResultFingerprint: data.Fingerprint(4), Labels: data.Labels{ngmodels.SeverityLabel: "warning"}},
+				{State: eval.Alerting, ResultFingerprint: data.Fingerprint(5), Labels: data.Labels{ngmodels.SeverityLabel: "warning"}, StateReason: ngmodels.StateReasonMissingSeries},
+			},
+		},
+	}
+
+	reader := AlertingResultsFromRuleState{
+		Manager: p,
+		Rule:    rule,
+	}
+
+	require.Equal(t, map[data.Fingerprint]string{1: "critical", 2: "warning"}, reader.ReadSeverities())
+}
+
 type FakeRuleStateProvider struct {
 	states map[ngmodels.AlertRuleKey][]*state.State
 }
@@ -16,8 +16,9 @@ import (
 )
 
 var (
-	errRuleDeleted   = errors.New("rule deleted")
-	errRuleRestarted = errors.New("rule restarted")
+	errRuleDeleted    = errors.New("rule deleted")
+	errRuleRestarted  = errors.New("rule restarted")
+	errRuleHandedOver = errors.New("rule handed over to another instance")
 )
 
 type ruleFactory interface {
@@ -324,6 +325,9 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 		binary.LittleEndian.PutUint64(tmp, uint64(rule.Record.Fingerprint()))
 		writeBytes(tmp)
 	}
+	for _, uid := range rule.DependsOn {
+		writeString(uid)
+	}
 
 	return fingerprint(sum.Sum64())
 }
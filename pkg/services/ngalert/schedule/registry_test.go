@@ -215,6 +215,7 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 				},
 			},
 			MissingSeriesEvalsToResolve: util.Pointer(2),
+			DependsOn:                   []string{"upstream"},
 		}
 		r2 := &models.AlertRule{
 			ID:        2,
@@ -261,6 +262,7 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 				},
 			},
 			MissingSeriesEvalsToResolve: util.Pointer(1),
+			DependsOn:                   []string{"upstream-2"},
 		}
 
 		excludedFields := map[string]struct{}{
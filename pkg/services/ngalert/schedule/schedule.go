@@ -19,6 +19,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util/ticker"
 )
@@ -49,6 +50,8 @@ type RulesStore interface {
 
 type RecordingWriter interface {
 	WriteDatasource(ctx context.Context, dsUID string, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error
+	// WriteDatasourceSamples writes the samples of several evaluations in a single request. It is used to backfill recording rules.
+	WriteDatasourceSamples(ctx context.Context, dsUID string, name string, samples []writer.Sample, orgID int64, extraLabels map[string]string) error
 }
 
 // AlertRuleStopReasonProvider is an interface for determining the reason why an alert rule was stopped.
@@ -110,6 +113,9 @@ type schedule struct {
 	tracer          tracing.Tracer
 	featureToggles  featuremgmt.FeatureToggles
 	recordingWriter RecordingWriter
+
+	// sharding decides which rules are evaluated by this instance. It is nil when all rules are evaluated.
+	sharding *evaluationSharding
 }
 
 // SchedulerCfg is the scheduler configuration.
@@ -131,6 +137,8 @@ type SchedulerCfg struct {
 	RecordingWriter        RecordingWriter
 	RuleStopReasonProvider AlertRuleStopReasonProvider
 	FeatureToggles         featuremgmt.FeatureToggles
+	// ClusterMembers, if not nil, shards the evaluation of rule groups between the members of the cluster.
+	ClusterMembers ClusterMembers
 }
 
 // NewScheduler returns a new scheduler.
@@ -163,6 +171,9 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		ruleStopReasonProvider: cfg.RuleStopReasonProvider,
 		featureToggles:         cfg.FeatureToggles,
 	}
+	if cfg.ClusterMembers != nil {
+		sch.sharding = newEvaluationSharding(cfg.ClusterMembers, cfg.BaseInterval, cfg.Log)
+	}
 
 	return &sch
 }
@@ -292,9 +303,13 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 
 	sch.updateRulesMetrics(alertRules)
 
+	ringChanged := sch.sharding.update()
+	ringKeys := sch.sharding.ringKeys(alertRules)
+
 	readyToRun := make([]readyToRunItem, 0)
 	updatedRules := make([]ngmodels.AlertRuleKeyWithVersion, 0, len(updated)) // this is needed for tests only
 	restartedRules := make([]Rule, 0)
+	handedOverRules := make([]Rule, 0)
 	missingFolder := make(map[string][]string)
 	ruleFactory := newRuleFactory(
 		sch.appURL,
@@ -310,14 +325,31 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		sch.tracer,
 		sch.featureToggles,
 		sch.recordingWriter,
+		func(rule *ngmodels.AlertRule) time.Duration {
+			return sch.sharding.handoverGracePeriod(ringKeys[rule.GetGroupKey()])
+		},
 		sch.evalAppliedFunc,
 		sch.stopAppliedFunc,
 	)
 	for _, item := range alertRules {
-		ruleRoutine, newRoutine := sch.registry.getOrCreate(ctx, item, ruleFactory)
 		key := item.GetKey()
 		logger := sch.log.FromContext(ctx).New(key.LogContext()...)
 
+		if !sch.sharding.owns(ringKeys[item.GetGroupKey()]) {
+			// The rule is evaluated by another instance. Its state is kept in the database for that instance to load.
+			if ruleRoutine, ok := sch.registry.del(key); ok {
+				logger.Info("Rule is now evaluated by another instance")
+				handedOverRules = append(handedOverRules, ruleRoutine)
+			} else if ringChanged {
+				// Drop the state that could have been loaded at startup, or by an earlier evaluation.
+				sch.stateManager.ForgetStateByRuleUID(ctx, item.GetKeyWithGroup())
+			}
+			delete(registeredDefinitions, key)
+			continue
+		}
+
+		ruleRoutine, newRoutine := sch.registry.getOrCreate(ctx, item, ruleFactory)
+
 		// enforce minimum evaluation interval
 		if item.IntervalSeconds < int64(sch.minRuleInterval.Seconds()) {
 			logger.Debug("Interval adjusted", "originalInterval", item.IntervalSeconds, "adjustedInterval", sch.minRuleInterval.Seconds())
@@ -406,6 +438,11 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		oldRoutine.Stop(errRuleRestarted)
 	}
 
+	// Stop routines for rules that are now evaluated by another instance.
+	for _, oldRoutine := range handedOverRules {
+		oldRoutine.Stop(errRuleHandedOver)
+	}
+
 	// unregister and stop routines of the deleted alert rules
 	toDelete := make([]ngmodels.AlertRuleKey, 0, len(registeredDefinitions))
 	for key := range registeredDefinitions {
@@ -424,16 +461,29 @@ func (sch *schedule) runJobFn(next readyToRunItem, prev ...readyToRunItem) func(
 		}
 		key := next.rule.GetKey()
 		success, dropped := next.ruleRoutine.Eval(&next.Evaluation)
-		if !success {
-			sch.log.Debug("Scheduled evaluation was canceled because evaluation routine was stopped", append(key.LogContext(), "time", next.scheduledAt)...)
-			return
-		}
 		if dropped != nil {
 			sch.log.Warn("Tick dropped because alert rule evaluation is too slow", append(key.LogContext(), "time", next.scheduledAt, "droppedTick", dropped.scheduledAt)...)
 			orgID := fmt.Sprint(key.OrgID)
 			sch.metrics.EvaluationMissed.WithLabelValues(orgID, next.rule.Title).Inc()
+			// The rules chained after the dropped evaluation, such as the rules that depend on this rule,
+			// are still evaluated, so that they do not miss their tick as well.
+			sch.runAfterEval(key, dropped)
 		}
+		if !success {
+			sch.log.Debug("Scheduled evaluation was canceled because evaluation routine was stopped", append(key.LogContext(), "time", next.scheduledAt)...)
+			sch.runAfterEval(key, &next.Evaluation)
+			return
+		}
+	}
+}
+
+// runAfterEval calls the afterEval callback of an evaluation that the evaluation routine of the rule will not process.
+func (sch *schedule) runAfterEval(key ngmodels.AlertRuleKey, e *Evaluation) {
+	if e.afterEval == nil {
+		return
 	}
+	sch.log.Debug("Triggering the rules chained after an evaluation that was not processed", append(key.LogContext(), "time", e.scheduledAt)...)
+	e.afterEval()
 }
 
 func (sch *schedule) runSequences(sequences []sequence, step int64) {
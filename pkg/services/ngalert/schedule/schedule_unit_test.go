@@ -67,18 +67,20 @@ func TestProcessTicks(t *testing.T) {
 	}
 
 	cacheServ := &datasources.FakeCacheService{}
+	exprService, err := expr.ProvideService(
+		&setting.Cfg{ExpressionsEnabled: true},
+		nil,
+		nil,
+		featuremgmt.WithFeatures(),
+		nil,
+		tracing.InitializeTracerForTest(),
+		mtdsclient.NewNullMTDatasourceClientBuilder(),
+	)
+	require.NoError(t, err)
 	evaluator := eval.NewEvaluatorFactory(
 		setting.UnifiedAlertingSettings{},
 		cacheServ,
-		expr.ProvideService(
-			&setting.Cfg{ExpressionsEnabled: true},
-			nil,
-			nil,
-			featuremgmt.WithFeatures(),
-			nil,
-			tracing.InitializeTracerForTest(),
-			mtdsclient.NewNullMTDatasourceClientBuilder(),
-		),
+		exprService,
 	)
 	rrSet := setting.RecordingRuleSettings{
 		Enabled: true,
@@ -1205,18 +1207,20 @@ func setupScheduler(t *testing.T, rs *fakeRulesStore, is *state.FakeInstanceStor
 
 	var evaluator = evalMock
 	if evalMock == nil {
+		exprService, err := expr.ProvideService(
+			&setting.Cfg{ExpressionsEnabled: true},
+			nil,
+			nil,
+			featuremgmt.WithFeatures(),
+			nil,
+			tracing.InitializeTracerForTest(),
+			mtdsclient.NewNullMTDatasourceClientBuilder(),
+		)
+		require.NoError(t, err)
 		evaluator = eval.NewEvaluatorFactory(
 			setting.UnifiedAlertingSettings{},
 			&datasources.FakeCacheService{},
-			expr.ProvideService(
-				&setting.Cfg{ExpressionsEnabled: true},
-				nil,
-				nil,
-				featuremgmt.WithFeatures(),
-				nil,
-				tracing.InitializeTracerForTest(),
-				mtdsclient.NewNullMTDatasourceClientBuilder(),
-			),
+			exprService,
 		)
 	}
 
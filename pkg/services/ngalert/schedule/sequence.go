@@ -4,6 +4,7 @@ import (
 	"cmp"
 	"slices"
 	"strings"
+	"sync/atomic"
 
 	models "github.com/grafana/grafana/pkg/services/ngalert/models"
 )
@@ -32,7 +33,12 @@ type groupKey struct {
 // that should be evaluated in order.
 //
 // NOTE: This currently only chains rules in imported groups.
+//
+// Rules that depend on other rules that are ready to run are not part of the returned sequences.
+// They are evaluated after all of these rules are evaluated, see chainDependencies.
 func (sch *schedule) buildSequences(items []readyToRunItem, runJobFn func(next readyToRunItem, prev ...readyToRunItem) func()) []sequence {
+	items = sch.chainDependencies(items, runJobFn)
+
 	// Step 1: Group rules by their folder and group name
 	groups := map[groupKey][]readyToRunItem{}
 	var keys []groupKey
@@ -92,7 +98,7 @@ func (sch *schedule) buildSequence(groupKey groupKey, groupItems []readyToRunIte
 
 	// iterate over the group items backwards to set the afterEval callback
 	for i := len(groupItems) - 2; i >= 0; i-- {
-		groupItems[i].afterEval = runJobFn(groupItems[i+1], groupItems[i])
+		groupItems[i].afterEval = chainCallbacks(groupItems[i].afterEval, runJobFn(groupItems[i+1], groupItems[i]))
 	}
 
 	uids := make([]string, 0, len(groupItems))
@@ -125,3 +131,98 @@ func (sch *schedule) shouldEvaluateSequentially(groupItems []readyToRunItem) boo
 	// default to false
 	return false
 }
+
+// chainDependencies makes sure that rules are evaluated after the rules they depend on in the same tick.
+// A rule that depends on rules that are ready to run is evaluated by the afterEval callback of the last of them to finish.
+// It returns the items that do not wait for other rules. Dependencies that form a cycle are ignored.
+func (sch *schedule) chainDependencies(items []readyToRunItem, runJobFn func(next readyToRunItem, prev ...readyToRunItem) func()) []readyToRunItem {
+	items = slices.Clone(items)
+	nodes := make(map[models.AlertRuleKey]*readyToRunItem, len(items))
+	for i := range items {
+		nodes[items[i].rule.GetKey()] = &items[i]
+	}
+
+	// upstreams contains the keys of the rules that are ready to run and that a rule depends on.
+	upstreams := make(map[models.AlertRuleKey][]models.AlertRuleKey)
+	downstreams := make(map[models.AlertRuleKey][]models.AlertRuleKey)
+	for _, item := range items {
+		key := item.rule.GetKey()
+		for _, uid := range item.rule.DependsOn {
+			upKey := models.AlertRuleKey{OrgID: key.OrgID, UID: uid}
+			if _, ok := nodes[upKey]; !ok || upKey == key || slices.Contains(upstreams[key], upKey) {
+				continue
+			}
+			upstreams[key] = append(upstreams[key], upKey)
+			downstreams[upKey] = append(downstreams[upKey], key)
+		}
+	}
+	if len(upstreams) == 0 {
+		return items
+	}
+
+	// Rules in or after a cycle are never ready with Kahn's algorithm. Cycles are rejected when rules are saved,
+	// so this only happens if the rules are changed concurrently.
+	pending := make(map[models.AlertRuleKey]int, len(items))
+	queue := make([]models.AlertRuleKey, 0, len(items))
+	for _, item := range items {
+		key := item.rule.GetKey()
+		pending[key] = len(upstreams[key])
+		if pending[key] == 0 {
+			queue = append(queue, key)
+		}
+	}
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		for _, down := range downstreams[key] {
+			pending[down]--
+			if pending[down] == 0 {
+				queue = append(queue, down)
+			}
+		}
+	}
+	for key, count := range pending {
+		if count > 0 {
+			sch.log.Warn("Ignoring the dependencies of the rule because of a dependency cycle", key.LogContext()...)
+			delete(upstreams, key)
+		}
+	}
+
+	// Set the callbacks through pointers to the items so that the callbacks of rules that depend on other rules
+	// are set before they are copied.
+	for key, ups := range upstreams {
+		next := nodes[key]
+		remaining := &atomic.Int32{}
+		remaining.Store(int32(len(ups)))
+		for _, upKey := range ups {
+			prev := nodes[upKey]
+			prev.afterEval = chainCallbacks(prev.afterEval, func() {
+				if remaining.Add(-1) == 0 {
+					runJobFn(*next, *prev)()
+				}
+			})
+		}
+	}
+
+	result := make([]readyToRunItem, 0, len(items)-len(upstreams))
+	for _, item := range items {
+		if _, ok := upstreams[item.rule.GetKey()]; !ok {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// chainCallbacks returns a callback that calls the given callbacks in order, skipping the nil ones.
+func chainCallbacks(first, second func()) func() {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func() {
+		first()
+		second()
+	}
+}
@@ -155,3 +155,83 @@ func TestSequence(t *testing.T) {
 		require.Equal(t, []string{"3", "4"}, prevByGroup["rg2"])
 	})
 }
+
+func TestSequenceDependencies(t *testing.T) {
+	ruleStore := newFakeRulesStore()
+	reg := prometheus.NewPedanticRegistry()
+	sch := setupScheduler(t, ruleStore, nil, reg, nil, nil, nil)
+	gen := models.RuleGen.With(models.RuleGen.WithNamespaceUID("ns1"), models.RuleGen.WithOrgID(1))
+
+	item := func(uid string, dependsOn ...string) readyToRunItem {
+		return readyToRunItem{
+			ruleRoutine: &fakeSequenceRule{UID: uid, Group: "rg-" + uid},
+			Evaluation: Evaluation{
+				rule: gen.With(
+					models.RuleGen.WithUID(uid),
+					models.RuleGen.WithGroupName("rg-"+uid),
+					models.RuleGen.WithDependsOn(dependsOn...),
+				).GenerateRef(),
+				folderTitle: "folder1",
+			},
+		}
+	}
+	var evaluated []string
+	callback := func(next readyToRunItem, prev ...readyToRunItem) func() {
+		return func() {
+			evaluated = append(evaluated, next.rule.UID)
+			next.ruleRoutine.Eval(&next.Evaluation)
+		}
+	}
+
+	items := []readyToRunItem{
+		item("c", "a", "b"),
+		item("b", "a"),
+		item("a"),
+		// "not-ready" is not evaluated in this tick, so "d" does not wait for it.
+		item("d", "not-ready"),
+		// "e" and "f" form a cycle, their dependencies are ignored.
+		item("e", "f"),
+		item("f", "e"),
+	}
+	sequences := sch.buildSequences(items, callback)
+	uids := make([]string, 0, len(sequences))
+	for _, s := range sequences {
+		uids = append(uids, s.rule.UID)
+	}
+	require.Equal(t, []string{"a", "d", "e", "f"}, uids)
+
+	for _, s := range sequences {
+		callback(readyToRunItem(s))()
+	}
+	require.Equal(t, []string{"a", "b", "c", "d", "e", "f"}, evaluated)
+}
+
+// fakeDroppingRule is a rule whose evaluation routine replaces a pending evaluation, or is stopped.
+type fakeDroppingRule struct {
+	fakeSequenceRule
+	pending *Evaluation
+	stopped bool
+}
+
+func (r *fakeDroppingRule) Eval(e *Evaluation) (bool, *Evaluation) {
+	return !r.stopped, r.pending
+}
+
+func TestRunJobFnCallsAfterEvalOfUnprocessedEvaluations(t *testing.T) {
+	sch := setupScheduler(t, newFakeRulesStore(), nil, prometheus.NewPedanticRegistry(), nil, nil, nil)
+	rule := models.RuleGen.GenerateRef()
+
+	t.Run("dropped evaluation", func(t *testing.T) {
+		called := false
+		routine := &fakeDroppingRule{pending: &Evaluation{rule: rule, afterEval: func() { called = true }}}
+		sch.runJobFn(readyToRunItem{ruleRoutine: routine, Evaluation: Evaluation{rule: rule}})()
+		require.True(t, called)
+	})
+
+	t.Run("stopped evaluation routine", func(t *testing.T) {
+		called := false
+		routine := &fakeDroppingRule{stopped: true}
+		sch.runJobFn(readyToRunItem{ruleRoutine: routine, Evaluation: Evaluation{rule: rule, afterEval: func() { called = true }}})()
+		require.True(t, called)
+	})
+}
@@ -0,0 +1,203 @@
+package schedule
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ringTokensPerMember is the number of virtual nodes each member gets on the ring.
+// More tokens spread the rule groups more evenly between the members.
+const ringTokensPerMember = 128
+
+// ClusterMembers provides the live members of the cluster of Grafana instances that share the evaluation of alert rules.
+type ClusterMembers interface {
+	// Name returns the name of this instance in the cluster.
+	Name() string
+	// Members returns the names of all live members of the cluster, including this instance.
+	Members() []string
+}
+
+type ringToken struct {
+	hash   uint64
+	member string
+}
+
+// hashRing assigns keys to members with consistent hashing, so that a change of the members only moves
+// the keys of the members that joined or left the ring.
+type hashRing struct {
+	members []string
+	tokens  []ringToken
+}
+
+func newHashRing(members []string) *hashRing {
+	members = slices.Clone(members)
+	slices.Sort(members)
+	members = slices.Compact(members)
+
+	tokens := make([]ringToken, 0, len(members)*ringTokensPerMember)
+	for _, m := range members {
+		for i := 0; i < ringTokensPerMember; i++ {
+			tokens = append(tokens, ringToken{hash: ringHash(m + "-" + strconv.Itoa(i)), member: m})
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].hash == tokens[j].hash {
+			return tokens[i].member < tokens[j].member
+		}
+		return tokens[i].hash < tokens[j].hash
+	})
+	return &hashRing{members: members, tokens: tokens}
+}
+
+// owner returns the member that owns the key, that is the member of the first token after the hash of the key.
+// It returns an empty string if the ring has no members.
+func (r *hashRing) owner(key string) string {
+	if len(r.tokens) == 0 {
+		return ""
+	}
+	h := ringHash(key)
+	idx := sort.Search(len(r.tokens), func(i int) bool {
+		return r.tokens[i].hash >= h
+	})
+	if idx == len(r.tokens) {
+		idx = 0
+	}
+	return r.tokens[idx].member
+}
+
+func ringHash(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// evaluationSharding decides which rule groups are evaluated by this instance when the evaluation
+// of alert rules is sharded between the instances of a highly available setup.
+// Rules of the same group are always evaluated by the same instance, so they can be evaluated in sequence,
+// and so are the groups of rules that depend on each other, so that the rules a rule depends on are evaluated
+// before it and their state is available to it.
+// A nil *evaluationSharding evaluates all rules.
+type evaluationSharding struct {
+	members ClusterMembers
+	ring    *hashRing
+	// previous is the ring before the last update, which is the same as ring if the members did not change.
+	previous    *hashRing
+	gracePeriod time.Duration
+	logger      log.Logger
+}
+
+// newEvaluationSharding returns the sharding between the members of the cluster. A rule handed over to this instance
+// is evaluated after two base intervals, which gives the instance that evaluated it until now the time to update
+// its ring at its next tick and stop evaluating it.
+func newEvaluationSharding(members ClusterMembers, baseInterval time.Duration, logger log.Logger) *evaluationSharding {
+	return &evaluationSharding{
+		members:     members,
+		ring:        newHashRing(nil),
+		previous:    newHashRing(nil),
+		gracePeriod: 2 * baseInterval,
+		logger:      logger,
+	}
+}
+
+// handoverGracePeriod returns how long the routine of a rule of the group with the given ring key waits before its
+// first evaluation. It is zero unless the last update handed the group over from another member that is still
+// in the ring, and so can still be evaluating it. New rules and rules loaded at startup are evaluated right away.
+func (s *evaluationSharding) handoverGracePeriod(key string) time.Duration {
+	if s == nil {
+		return 0
+	}
+	previousOwner := s.previous.owner(key)
+	if previousOwner == "" || previousOwner == s.members.Name() || previousOwner == s.ring.owner(key) {
+		return 0
+	}
+	if _, live := slices.BinarySearch(s.ring.members, previousOwner); !live {
+		return 0
+	}
+	return s.gracePeriod
+}
+
+// update rebuilds the ring from the current members of the cluster.
+// It returns true if the members changed since the last update.
+func (s *evaluationSharding) update() bool {
+	if s == nil {
+		return false
+	}
+	ring := newHashRing(s.members.Members())
+	if slices.Equal(ring.members, s.ring.members) {
+		s.previous = s.ring
+		return false
+	}
+	s.logger.Info("Members of the evaluation ring changed", "self", s.members.Name(), "previous", s.ring.members, "current", ring.members)
+	s.previous, s.ring = s.ring, ring
+	return true
+}
+
+// owns returns true if this instance evaluates the rules of the group with the given ring key, see ringKeys.
+// When this instance is not part of the ring yet, for example because it has not joined the cluster, it owns all
+// groups: evaluating a rule twice is preferred over not evaluating it, as the Alertmanagers deduplicate notifications.
+func (s *evaluationSharding) owns(key string) bool {
+	if s == nil {
+		return true
+	}
+	self := s.members.Name()
+	if _, ok := slices.BinarySearch(s.ring.members, self); !ok {
+		return true
+	}
+	return s.ring.owner(key) == self
+}
+
+// ringKeys returns the key on the ring of the group of each rule. The groups that are linked by the dependencies
+// of their rules share the same key, the smallest key of these groups, so that they are evaluated by the same instance.
+func (s *evaluationSharding) ringKeys(rules []*ngmodels.AlertRule) map[ngmodels.AlertRuleGroupKey]string {
+	if s == nil {
+		return nil
+	}
+	groupByRule := make(map[ngmodels.AlertRuleKey]ngmodels.AlertRuleGroupKey, len(rules))
+	parent := make(map[ngmodels.AlertRuleGroupKey]ngmodels.AlertRuleGroupKey)
+	for _, rule := range rules {
+		groupByRule[rule.GetKey()] = rule.GetGroupKey()
+		parent[rule.GetGroupKey()] = rule.GetGroupKey()
+	}
+
+	var find func(ngmodels.AlertRuleGroupKey) ngmodels.AlertRuleGroupKey
+	find = func(g ngmodels.AlertRuleGroupKey) ngmodels.AlertRuleGroupKey {
+		if parent[g] != g {
+			parent[g] = find(parent[g])
+		}
+		return parent[g]
+	}
+	for _, rule := range rules {
+		for _, uid := range rule.DependsOn {
+			upstream, ok := groupByRule[ngmodels.AlertRuleKey{OrgID: rule.OrgID, UID: uid}]
+			if !ok {
+				continue
+			}
+			a, b := find(rule.GetGroupKey()), find(upstream)
+			if a == b {
+				continue
+			}
+			// Keep the group with the smallest key as the root, so that the key does not depend on the order of the rules.
+			if ringKey(b) < ringKey(a) {
+				a, b = b, a
+			}
+			parent[b] = a
+		}
+	}
+
+	keys := make(map[ngmodels.AlertRuleGroupKey]string, len(parent))
+	for g := range parent {
+		keys[g] = ringKey(find(g))
+	}
+	return keys
+}
+
+func ringKey(key ngmodels.AlertRuleGroupKey) string {
+	return fmt.Sprintf("%d/%s/%s", key.OrgID, key.NamespaceUID, key.RuleGroup)
+}
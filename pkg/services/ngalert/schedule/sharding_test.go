@@ -0,0 +1,215 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeClusterMembers struct {
+	mtx     sync.Mutex
+	name    string
+	members []string
+}
+
+func (f *fakeClusterMembers) Name() string {
+	return f.name
+}
+
+func (f *fakeClusterMembers) Members() []string {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.members
+}
+
+func (f *fakeClusterMembers) setMembers(members ...string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.members = members
+}
+
+func TestHashRing(t *testing.T) {
+	keys := make([]string, 0, 3000)
+	for i := 0; i < cap(keys); i++ {
+		keys = append(keys, fmt.Sprintf("1/folder-%d/group-%d", i%10, i))
+	}
+
+	t.Run("empty ring has no owner", func(t *testing.T) {
+		require.Empty(t, newHashRing(nil).owner("key"))
+	})
+
+	t.Run("keys are spread between the members", func(t *testing.T) {
+		ring := newHashRing([]string{"c", "a", "b", "a"})
+		require.Equal(t, []string{"a", "b", "c"}, ring.members)
+
+		owned := map[string]int{}
+		for _, k := range keys {
+			owned[ring.owner(k)]++
+		}
+		require.Len(t, owned, 3)
+		for m, count := range owned {
+			assert.Greaterf(t, count, len(keys)/5, "member %s owns too few keys", m)
+		}
+	})
+
+	t.Run("only keys of the new member move when a member joins", func(t *testing.T) {
+		before := newHashRing([]string{"a", "b", "c"})
+		after := newHashRing([]string{"a", "b", "c", "d"})
+
+		moved := 0
+		for _, k := range keys {
+			if prev, cur := before.owner(k), after.owner(k); prev != cur {
+				require.Equal(t, "d", cur)
+				moved++
+			}
+		}
+		require.Positive(t, moved)
+	})
+}
+
+func TestEvaluationSharding(t *testing.T) {
+	groupKey := func(i int) models.AlertRuleGroupKey {
+		return models.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "folder", RuleGroup: fmt.Sprintf("group-%d", i)}
+	}
+
+	t.Run("nil sharding owns all groups", func(t *testing.T) {
+		var s *evaluationSharding
+		require.False(t, s.update())
+		require.True(t, s.owns(ringKey(groupKey(1))))
+	})
+
+	t.Run("owns all groups when not in the ring", func(t *testing.T) {
+		members := &fakeClusterMembers{name: "a"}
+		s := newEvaluationSharding(members, time.Second, log.NewNopLogger())
+		require.False(t, s.update())
+		require.True(t, s.owns(ringKey(groupKey(1))))
+
+		members.setMembers("b", "c")
+		require.True(t, s.update())
+		require.True(t, s.owns(ringKey(groupKey(1))))
+	})
+
+	t.Run("each group is owned by exactly one member", func(t *testing.T) {
+		a := newEvaluationSharding(&fakeClusterMembers{name: "a", members: []string{"a", "b"}}, time.Second, log.NewNopLogger())
+		b := newEvaluationSharding(&fakeClusterMembers{name: "b", members: []string{"b", "a"}}, time.Second, log.NewNopLogger())
+		require.True(t, a.update())
+		require.True(t, b.update())
+		require.False(t, a.update())
+
+		ownedByA := 0
+		for i := 0; i < 100; i++ {
+			require.NotEqual(t, a.owns(ringKey(groupKey(i))), b.owns(ringKey(groupKey(i))))
+			if a.owns(ringKey(groupKey(i))) {
+				ownedByA++
+			}
+		}
+		require.Positive(t, ownedByA)
+		require.Less(t, ownedByA, 100)
+	})
+
+	t.Run("waits only for the groups handed over by a live member", func(t *testing.T) {
+		members := &fakeClusterMembers{name: "a", members: []string{"a", "b", "c"}}
+		s := newEvaluationSharding(members, time.Second, log.NewNopLogger())
+		require.True(t, s.update())
+		for i := 0; i < 100; i++ {
+			require.Zero(t, s.handoverGracePeriod(ringKey(groupKey(i))), "rules loaded at startup are evaluated right away")
+		}
+
+		// c leaves the ring, and its groups are taken over without waiting.
+		members.setMembers("a", "b")
+		require.True(t, s.update())
+		for i := 0; i < 100; i++ {
+			require.Zero(t, s.handoverGracePeriod(ringKey(groupKey(i))))
+		}
+
+		// a leaves and joins the ring again, and takes over groups that b still evaluates.
+		members.setMembers("b")
+		require.True(t, s.update())
+		members.setMembers("a", "b")
+		require.True(t, s.update())
+		handedOver := 0
+		for i := 0; i < 100; i++ {
+			key := ringKey(groupKey(i))
+			if s.owns(key) {
+				require.Equal(t, 2*time.Second, s.handoverGracePeriod(key))
+				handedOver++
+			} else {
+				require.Zero(t, s.handoverGracePeriod(key))
+			}
+		}
+		require.Positive(t, handedOver)
+
+		// Rules created after the handover are evaluated right away.
+		require.False(t, s.update())
+		for i := 0; i < 100; i++ {
+			require.Zero(t, s.handoverGracePeriod(ringKey(groupKey(i))))
+		}
+	})
+
+	t.Run("groups linked by dependencies share the same ring key", func(t *testing.T) {
+		gen := models.RuleGen.With(models.RuleGen.WithOrgID(1))
+		upstream := gen.With(gen.WithGroupKey(groupKey(3))).GenerateRef()
+		middle := gen.With(gen.WithGroupKey(groupKey(2)), gen.WithDependsOn(upstream.UID)).GenerateRef()
+		downstream := gen.With(gen.WithGroupKey(groupKey(1)), gen.WithDependsOn(middle.UID, "missing")).GenerateRef()
+		other := gen.With(gen.WithGroupKey(groupKey(4))).GenerateRef()
+
+		s := newEvaluationSharding(&fakeClusterMembers{name: "a"}, time.Second, log.NewNopLogger())
+		keys := s.ringKeys([]*models.AlertRule{downstream, other, upstream, middle})
+		require.Equal(t, map[models.AlertRuleGroupKey]string{
+			groupKey(1): ringKey(groupKey(1)),
+			groupKey(2): ringKey(groupKey(1)),
+			groupKey(3): ringKey(groupKey(1)),
+			groupKey(4): ringKey(groupKey(4)),
+		}, keys)
+	})
+}
+
+func TestProcessTickSharding(t *testing.T) {
+	ctx := context.Background()
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	ruleStore := newFakeRulesStore()
+	sch := setupScheduler(t, ruleStore, nil, nil, nil, nil, nil)
+	members := &fakeClusterMembers{name: "a", members: []string{"a"}}
+	sch.sharding = newEvaluationSharding(members, time.Second, log.NewNopLogger())
+
+	gen := models.RuleGen
+	rules := gen.With(gen.WithOrgID(1), gen.WithInterval(time.Second), gen.WithIsPaused(false)).GenerateManyRef(20)
+	ruleStore.PutRule(ctx, rules...)
+
+	tick := time.Time{}.Add(time.Second)
+	scheduled, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+	require.Len(t, scheduled, len(rules))
+	require.Empty(t, stopped)
+
+	// Another instance joins the ring and takes over some of the groups.
+	members.setMembers("a", "b")
+	other := newEvaluationSharding(&fakeClusterMembers{name: "b", members: []string{"a", "b"}}, time.Second, log.NewNopLogger())
+	other.update()
+
+	tick = tick.Add(time.Second)
+	scheduled, stopped, _ = sch.processTick(ctx, dispatcherGroup, tick)
+	require.Empty(t, stopped, "rules evaluated by another instance must not be deleted")
+
+	scheduledKeys := make(map[models.AlertRuleKey]struct{}, len(scheduled))
+	for _, item := range scheduled {
+		scheduledKeys[item.rule.GetKey()] = struct{}{}
+	}
+	for _, rule := range rules {
+		_, isScheduled := scheduledKeys[rule.GetKey()]
+		require.Equal(t, !other.owns(ringKey(rule.GetGroupKey())), isScheduled)
+		require.Equal(t, isScheduled, sch.registry.exists(rule.GetKey()))
+	}
+
+	// The rules evaluated by another instance are still known to the scheduler.
+	known, _ := sch.schedulableAlertRules.all()
+	require.Len(t, known, len(rules))
+}
@@ -157,6 +157,10 @@ func expandAnnotationsAndLabels(ctx context.Context, log log.Logger, alertRule *
 	if len(dupes) > 0 {
 		log.Debug("Evaluation result contains either reserved labels or labels declared in the rules. Those labels from the result will be ignored", "labels", dupes)
 	}
+	// the severity of the result takes precedence over a severity label of the rule or the result.
+	if result.Severity != "" {
+		lbs[ngModels.SeverityLabel] = result.Severity
+	}
 	return lbs, annotations
 }
 
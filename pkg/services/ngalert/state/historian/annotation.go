@@ -44,6 +44,7 @@ type AnnotationBackend struct {
 type RuleStore interface {
 	GetAlertRuleByUID(ctx context.Context, query *ngmodels.GetAlertRuleByUIDQuery) (*ngmodels.AlertRule, error)
 	GetUserVisibleNamespaces(ctx context.Context, orgID int64, user identity.Requester) (map[string]*folder.Folder, error)
+	ListAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) (ngmodels.RulesGroup, error)
 }
 
 type AnnotationStore interface {
@@ -114,6 +115,9 @@ func (h *AnnotationBackend) Query(ctx context.Context, query ngmodels.HistoryQue
 	if query.RuleUID == "" {
 		return nil, fmt.Errorf("ruleUID is required to query annotations")
 	}
+	if err := checkQuerySupported(BackendTypeAnnotations, query); err != nil {
+		return nil, err
+	}
 
 	if query.Labels != nil {
 		logger.Warn("Annotation state history backend does not support label queries, ignoring that filter")
@@ -3,6 +3,9 @@ package historian
 import (
 	"fmt"
 	"strings"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
 // BackendType identifies different kinds of state history backends.
@@ -18,9 +21,40 @@ const (
 	BackendTypeLoki        BackendType = "loki"
 	BackendTypeMultiple    BackendType = "multiple"
 	BackendTypePrometheus  BackendType = "prometheus"
+	BackendTypeSQL         BackendType = "sql"
 	BackendTypeNoop        BackendType = "noop"
 )
 
+// ErrUnsupportedQuery is returned by the backends that cannot apply some of the filters of the query.
+var ErrUnsupportedQuery = errutil.BadRequest("alerting.state-history.unsupportedQuery").MustTemplate(
+	"State history backend {{.Public.Backend}} does not support filtering by {{.Public.Fields}}",
+	errutil.WithPublic("The {{.Public.Backend}} state history backend does not support filtering by {{.Public.Fields}}."),
+)
+
+// checkQuerySupported returns ErrUnsupportedQuery if the query uses the offset, state or matcher filters,
+// which only some backends implement, so that they are never silently ignored.
+func checkQuerySupported(backend BackendType, query models.HistoryQuery) error {
+	var fields []string
+	if query.Offset > 0 {
+		fields = append(fields, "offset")
+	}
+	if len(query.States) > 0 {
+		fields = append(fields, "state")
+	}
+	if len(query.Matchers) > 0 {
+		fields = append(fields, "matcher")
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return ErrUnsupportedQuery.Build(errutil.TemplateData{
+		Public: map[string]any{
+			"Backend": backend,
+			"Fields":  strings.Join(fields, ", "),
+		},
+	})
+}
+
 func ParseBackendType(s string) (BackendType, error) {
 	norm := strings.ToLower(strings.TrimSpace(s))
 
@@ -29,6 +63,7 @@ func ParseBackendType(s string) (BackendType, error) {
 		BackendTypeLoki:        {},
 		BackendTypeMultiple:    {},
 		BackendTypePrometheus:  {},
+		BackendTypeSQL:         {},
 		BackendTypeNoop:        {},
 	}
 	p := BackendType(norm)
@@ -143,6 +143,9 @@ func (h *RemoteLokiBackend) Record(ctx context.Context, rule history_model.RuleM
 
 // Query retrieves state history entries from an external Loki instance and formats the results into a dataframe.
 func (h *RemoteLokiBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	if err := checkQuerySupported(BackendTypeLoki, query); err != nil {
+		return nil, err
+	}
 	uids, err := h.getFolderUIDsForFilter(ctx, query)
 	if err != nil {
 		return nil, err
@@ -495,7 +498,13 @@ func queryHasLogFilters(query models.HistoryQuery) bool {
 }
 
 func (h *RemoteLokiBackend) getFolderUIDsForFilter(ctx context.Context, query models.HistoryQuery) ([]string, error) {
-	bypass, err := h.ac.CanReadAllRules(ctx, query.SignedInUser)
+	return getFolderUIDsForFilter(ctx, query, h.ac, h.ruleStore)
+}
+
+// getFolderUIDsForFilter returns the UIDs of the folders in which the user can read the state history,
+// or nil if the user can read the history of all rules or of the rule requested by the query.
+func getFolderUIDsForFilter(ctx context.Context, query models.HistoryQuery, ac AccessControl, ruleStore RuleStore) ([]string, error) {
+	bypass, err := ac.CanReadAllRules(ctx, query.SignedInUser)
 	if err != nil {
 		return nil, err
 	}
@@ -504,7 +513,7 @@ func (h *RemoteLokiBackend) getFolderUIDsForFilter(ctx context.Context, query mo
 	}
 	// if there is a filter by rule UID, find that rule UID and make sure that user has access to it.
 	if query.RuleUID != "" {
-		rule, err := h.ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{
+		rule, err := ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{
 			UID:   query.RuleUID,
 			OrgID: query.OrgID,
 		})
@@ -514,17 +523,17 @@ func (h *RemoteLokiBackend) getFolderUIDsForFilter(ctx context.Context, query mo
 		if rule == nil {
 			return nil, models.ErrAlertRuleNotFound
 		}
-		return nil, h.ac.AuthorizeAccessInFolder(ctx, query.SignedInUser, rule)
+		return nil, ac.AuthorizeAccessInFolder(ctx, query.SignedInUser, rule)
 	}
 	// if no filter, then we need to get all namespaces user has access to
-	folders, err := h.ruleStore.GetUserVisibleNamespaces(ctx, query.OrgID, query.SignedInUser)
+	folders, err := ruleStore.GetUserVisibleNamespaces(ctx, query.OrgID, query.SignedInUser)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch folders that user can access: %w", err)
 	}
 	uids := make([]string, 0, len(folders))
 	// now keep only UIDs of folder in which user can read rules.
 	for _, f := range folders {
-		hasAccess, err := h.ac.HasAccessInFolder(ctx, query.SignedInUser, models.Namespace(*f.ToFolderReference()))
+		hasAccess, err := ac.HasAccessInFolder(ctx, query.SignedInUser, models.Namespace(*f.ToFolderReference()))
 		if err != nil {
 			return nil, err
 		}
@@ -5,6 +5,8 @@ import (
 	"errors"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"golang.org/x/sync/errgroup"
+
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
@@ -15,6 +17,11 @@ type Backend interface {
 	Query(ctx context.Context, query ngmodels.HistoryQuery) (*data.Frame, error)
 }
 
+// BackgroundService is implemented by the backends that run jobs in the background, such as deleting expired history.
+type BackgroundService interface {
+	Run(ctx context.Context) error
+}
+
 // MultipleBackend is a state.Historian that records history to multiple backends at once.
 // Only one backend is used for reads. The backend selected for read traffic is called the primary and all others are called secondaries.
 type MultipleBackend struct {
@@ -53,3 +60,16 @@ func (h *MultipleBackend) Record(ctx context.Context, rule history_model.RuleMet
 func (h *MultipleBackend) Query(ctx context.Context, query ngmodels.HistoryQuery) (*data.Frame, error) {
 	return h.primary.Query(ctx, query)
 }
+
+// Run runs the background jobs of the backends, until the context is cancelled.
+func (h *MultipleBackend) Run(ctx context.Context) error {
+	g, ctx := errgroup.WithContext(ctx)
+	for _, b := range append([]Backend{h.primary}, h.secondaries...) {
+		if bg, ok := b.(BackgroundService); ok {
+			g.Go(func() error {
+				return bg.Run(ctx)
+			})
+		}
+	}
+	return g.Wait()
+}
@@ -16,7 +16,6 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
-	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
 	"github.com/grafana/grafana/pkg/setting"
@@ -89,25 +88,27 @@ func NewPrometheusConfig(cfg setting.UnifiedAlertingStateHistorySettings) (Prome
 type RemotePrometheusBackend struct {
 	cfg        PrometheusConfig
 	promWriter seriesWriter
+	querier    seriesQuerier
 	logger     log.Logger
 	metrics    *metrics.Historian
+	ruleStore  RuleStore
+	ac         AccessControl
 }
 
-func NewRemotePrometheusBackend(cfg PrometheusConfig, promWriter seriesWriter, logger log.Logger, metrics *metrics.Historian) *RemotePrometheusBackend {
+func NewRemotePrometheusBackend(cfg PrometheusConfig, promWriter seriesWriter, querier seriesQuerier, logger log.Logger, metrics *metrics.Historian, ruleStore RuleStore, ac AccessControl) *RemotePrometheusBackend {
 	logger.Info("Initializing remote Prometheus backend", "datasourceUID", cfg.DatasourceUID)
 
 	return &RemotePrometheusBackend{
 		cfg:        cfg,
 		promWriter: promWriter,
+		querier:    querier,
 		logger:     logger,
 		metrics:    metrics,
+		ruleStore:  ruleStore,
+		ac:         ac,
 	}
 }
 
-func (b *RemotePrometheusBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
-	return nil, fmt.Errorf("prometheus historian backend does not support querying")
-}
-
 func (b *RemotePrometheusBackend) Record(ctx context.Context, rule history_model.RuleMeta, transitions []state.StateTransition) <-chan error {
 	errCh := make(chan error, 1)
 
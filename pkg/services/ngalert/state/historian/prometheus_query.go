@@ -0,0 +1,459 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/util/strutil"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// prometheusQueryRefID is the refID of the range query sent to the data source.
+	prometheusQueryRefID = "A"
+	// minPrometheusQueryStep is the smallest resolution at which the state history is read back.
+	minPrometheusQueryStep = 10 * time.Second
+	// maxPrometheusQueryPoints is the maximum number of points per series allowed by Prometheus.
+	maxPrometheusQueryPoints = 11000
+	// prometheusQueryRuleUIDBatchSize is the maximum number of rule UIDs matched by a single range query,
+	// to keep the regular expression of the selector short.
+	prometheusQueryRuleUIDBatchSize = 100
+)
+
+// seriesQuerier runs range queries against the data source the state history is written to.
+type seriesQuerier interface {
+	QueryRange(ctx context.Context, orgID int64, query string, from, to time.Time, step time.Duration) (data.Frames, error)
+}
+
+type ExpressionService interface {
+	TransformData(ctx context.Context, now time.Time, req *expr.Request) (*backend.QueryDataResponse, error)
+}
+
+type dataSourceGetter interface {
+	GetDataSource(ctx context.Context, query *datasources.GetDataSourceQuery) (*datasources.DataSource, error)
+}
+
+// DatasourceQuerier runs PromQL range queries against a Prometheus data source through the expression service.
+// The queries run as the service identity: the data source is the one configured for the state history,
+// and the access to the history is checked against the alert rules before querying.
+type DatasourceQuerier struct {
+	datasourceUID string
+	datasources   dataSourceGetter
+	expressions   ExpressionService
+}
+
+func NewDatasourceQuerier(datasourceUID string, datasources dataSourceGetter, expressions ExpressionService) *DatasourceQuerier {
+	return &DatasourceQuerier{
+		datasourceUID: datasourceUID,
+		datasources:   datasources,
+		expressions:   expressions,
+	}
+}
+
+func (q *DatasourceQuerier) QueryRange(ctx context.Context, orgID int64, query string, from, to time.Time, step time.Duration) (data.Frames, error) {
+	ctx, user := identity.WithServiceIdentity(ctx, orgID)
+	ds, err := q.datasources.GetDataSource(ctx, &datasources.GetDataSourceQuery{UID: q.datasourceUID, OrgID: orgID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get datasource %s: %w", q.datasourceUID, err)
+	}
+
+	model, err := json.Marshal(map[string]any{
+		"refId":         prometheusQueryRefID,
+		"expr":          query,
+		"range":         true,
+		"instant":       false,
+		"interval":      fmt.Sprintf("%ds", int64(step.Seconds())),
+		"intervalMs":    step.Milliseconds(),
+		"maxDataPoints": maxPrometheusQueryPoints,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := q.expressions.TransformData(ctx, time.Now(), &expr.Request{
+		OrgId: orgID,
+		User:  user,
+		Queries: []expr.Query{{
+			RefID:         prometheusQueryRefID,
+			TimeRange:     expr.AbsoluteTimeRange{From: from, To: to},
+			DataSource:    ds,
+			JSON:          model,
+			Interval:      step,
+			MaxDataPoints: maxPrometheusQueryPoints,
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query datasource %s: %w", q.datasourceUID, err)
+	}
+	res, ok := resp.Responses[prometheusQueryRefID]
+	if !ok {
+		return nil, nil
+	}
+	if res.Error != nil {
+		return nil, fmt.Errorf("failed to query datasource %s: %w", q.datasourceUID, res.Error)
+	}
+	return res.Frames, nil
+}
+
+// Query reads the state history back from the series written by Record and rebuilds the state transitions
+// into the same frame as the one returned by the Loki backend.
+//
+// The series only tell in which state an alert instance was at every step of the query, so the transitions are
+// only as precise as the step, the reason of the state and the values of the query are not available,
+// and the labels of the instances are the sanitized labels of the series.
+func (b *RemotePrometheusBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	if b.querier == nil {
+		return nil, fmt.Errorf("prometheus historian backend is not configured for querying")
+	}
+	if err := checkQuerySupported(BackendTypePrometheus, query); err != nil {
+		return nil, err
+	}
+
+	uids, err := getFolderUIDsForFilter(ctx, query, b.ac, b.ruleStore)
+	if err != nil {
+		return nil, err
+	}
+
+	rulesQuery := &models.ListAlertRulesQuery{
+		OrgID:         query.OrgID,
+		NamespaceUIDs: uids,
+		DashboardUID:  query.DashboardUID,
+		PanelID:       query.PanelID,
+	}
+	if query.RuleUID != "" {
+		rulesQuery.RuleUIDs = []string{query.RuleUID}
+	}
+	rules, err := b.ruleStore.ListAlertRules(ctx, rulesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch alert rules: %w", err)
+	}
+	rulesByUID := make(map[string]*models.AlertRule, len(rules))
+	for _, rule := range rules {
+		rulesByUID[rule.UID] = rule
+	}
+
+	// The history of rules that no longer exist can only be read by users that can read all rules.
+	filterByRule := query.RuleUID == "" && (len(uids) > 0 || query.DashboardUID != "")
+	if filterByRule && len(rulesByUID) == 0 {
+		return newStateHistoryFrame([]time.Time{}, []json.RawMessage{}, []json.RawMessage{}), nil
+	}
+
+	now := time.Now().UTC()
+	if query.To.IsZero() {
+		query.To = now
+	}
+	if query.From.IsZero() {
+		query.From = now.Add(-defaultQueryRange)
+	}
+	step := prometheusQueryStep(query.From, query.To)
+
+	var frames data.Frames
+	for _, batch := range ruleUIDBatches(rulesByUID, filterByRule) {
+		promQL := BuildPromQLQuery(b.cfg.MetricName, query, batch)
+		res, err := b.querier.QueryRange(ctx, query.OrgID, promQL, query.From, query.To, step)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, res...)
+	}
+
+	entries, err := rebuildTransitions(frames, query.From, query.To, step)
+	if err != nil {
+		return nil, err
+	}
+	if filterByRule {
+		filtered := entries[:0]
+		for _, e := range entries {
+			if _, ok := rulesByUID[e.ruleUID]; ok {
+				filtered = append(filtered, e)
+			}
+		}
+		entries = filtered
+	}
+	// Keep the most recent transitions, like the Loki backend does.
+	if query.Limit > 0 && len(entries) > query.Limit {
+		entries = entries[len(entries)-query.Limit:]
+	}
+
+	return b.buildStateHistoryFrame(query.OrgID, entries, rulesByUID)
+}
+
+// ruleUIDBatches splits the UIDs of the rules into the batches of UIDs matched by each range query.
+// It returns a single empty batch if the query does not need to match the rules.
+func ruleUIDBatches(rules map[string]*models.AlertRule, filterByRule bool) [][]string {
+	if !filterByRule {
+		return [][]string{nil}
+	}
+	uids := make([]string, 0, len(rules))
+	for uid := range rules {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	batches := make([][]string, 0, len(uids)/prometheusQueryRuleUIDBatchSize+1)
+	for len(uids) > prometheusQueryRuleUIDBatchSize {
+		batches = append(batches, uids[:prometheusQueryRuleUIDBatchSize])
+		uids = uids[prometheusQueryRuleUIDBatchSize:]
+	}
+	return append(batches, uids)
+}
+
+// BuildPromQLQuery returns the PromQL selector of the series of the state history matching the query.
+// If the query is not for a single rule, the series are limited to the rules with the given UIDs, if any.
+func BuildPromQLQuery(metricName string, query models.HistoryQuery, ruleUIDs []string) string {
+	matchers := make([]string, 0, len(query.Labels)+1)
+	switch {
+	case query.RuleUID != "":
+		matchers = append(matchers, fmt.Sprintf("%s=%s", alertRuleUIDLabel, strconv.Quote(query.RuleUID)))
+	case len(ruleUIDs) == 1:
+		matchers = append(matchers, fmt.Sprintf("%s=%s", alertRuleUIDLabel, strconv.Quote(ruleUIDs[0])))
+	case len(ruleUIDs) > 1:
+		quoted := make([]string, 0, len(ruleUIDs))
+		for _, uid := range ruleUIDs {
+			quoted = append(quoted, regexp.QuoteMeta(uid))
+		}
+		matchers = append(matchers, fmt.Sprintf("%s=~%s", alertRuleUIDLabel, strconv.Quote(strings.Join(quoted, "|"))))
+	}
+
+	keys := make([]string, 0, len(query.Labels))
+	for k := range query.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		matchers = append(matchers, fmt.Sprintf("%s=%s", strutil.SanitizeFullLabelName(k), strconv.Quote(query.Labels[k])))
+	}
+
+	return fmt.Sprintf("%s{%s}", metricName, strings.Join(matchers, ","))
+}
+
+// prometheusQueryStep returns the step of the range query, as small as possible within the limits of Prometheus.
+func prometheusQueryStep(from, to time.Time) time.Duration {
+	step := to.Sub(from) / maxPrometheusQueryPoints
+	if step < minPrometheusQueryStep {
+		return minPrometheusQueryStep
+	}
+	return step.Truncate(time.Second) + time.Second
+}
+
+// historyEntry is a state transition of an alert instance rebuilt from the series.
+type historyEntry struct {
+	time     time.Time
+	ruleUID  string
+	labels   data.Labels
+	previous eval.State
+	current  eval.State
+}
+
+// stateInterval is a period during which an alert instance was in the same state.
+type stateInterval struct {
+	state      eval.State
+	start, end time.Time
+}
+
+type instanceHistory struct {
+	ruleUID   string
+	labels    data.Labels
+	intervals []stateInterval
+}
+
+// rebuildTransitions rebuilds the state transitions of the alert instances from the series returned by the range query,
+// sorted by time. Normal is not written, therefore the absence of all series of an instance means the instance was Normal.
+func rebuildTransitions(frames data.Frames, from, to time.Time, step time.Duration) ([]historyEntry, error) {
+	type series struct {
+		state  eval.State
+		labels data.Labels
+		times  []time.Time
+	}
+	var all []series
+	minSpacing := time.Duration(0)
+	for _, frame := range frames {
+		var timeField, valueField *data.Field
+		for _, f := range frame.Fields {
+			switch {
+			case f.Type().Time():
+				timeField = f
+			case f.Type().Numeric():
+				valueField = f
+			}
+		}
+		if timeField == nil || valueField == nil {
+			continue
+		}
+
+		st, err := eval.ParseStateString(valueField.Labels[grafanaAlertStateLabel])
+		if err != nil {
+			return nil, fmt.Errorf("series with invalid %s label: %w", grafanaAlertStateLabel, err)
+		}
+
+		s := series{state: st, labels: valueField.Labels}
+		for i := 0; i < frame.Rows(); i++ {
+			t, ok := timeField.ConcreteAt(i)
+			if !ok {
+				continue
+			}
+			v, err := valueField.NullableFloatAt(i)
+			if err != nil {
+				return nil, err
+			}
+			if v == nil || math.IsNaN(*v) {
+				continue
+			}
+			ts := t.(time.Time)
+			if n := len(s.times); n > 0 {
+				if d := ts.Sub(s.times[n-1]); d > 0 && (minSpacing == 0 || d < minSpacing) {
+					minSpacing = d
+				}
+			}
+			s.times = append(s.times, ts)
+		}
+		if len(s.times) > 0 {
+			all = append(all, s)
+		}
+	}
+
+	// The data source can use a larger step than the requested one.
+	if minSpacing > step {
+		step = minSpacing
+	}
+	maxGap := step + step/2
+
+	instances := map[string]*instanceHistory{}
+	keys := make([]string, 0)
+	for _, s := range all {
+		lbls := make(data.Labels, len(s.labels))
+		for k, v := range s.labels {
+			switch k {
+			case "__name__", alertStateLabel, grafanaAlertStateLabel, alertRuleUIDLabel:
+				continue
+			}
+			lbls[k] = v
+		}
+		ruleUID := s.labels[alertRuleUIDLabel]
+		key := ruleUID + "/" + labelFingerprint(lbls)
+		inst, ok := instances[key]
+		if !ok {
+			inst = &instanceHistory{ruleUID: ruleUID, labels: lbls}
+			instances[key] = inst
+			keys = append(keys, key)
+		}
+		start := s.times[0]
+		for i := 1; i < len(s.times); i++ {
+			if s.times[i].Sub(s.times[i-1]) > maxGap {
+				inst.intervals = append(inst.intervals, stateInterval{state: s.state, start: start, end: s.times[i-1]})
+				start = s.times[i]
+			}
+		}
+		inst.intervals = append(inst.intervals, stateInterval{state: s.state, start: start, end: s.times[len(s.times)-1]})
+	}
+
+	var entries []historyEntry
+	for _, key := range keys {
+		inst := instances[key]
+		sort.Slice(inst.intervals, func(i, j int) bool {
+			return inst.intervals[i].start.Before(inst.intervals[j].start)
+		})
+		add := func(t time.Time, previous, current eval.State) {
+			entries = append(entries, historyEntry{time: t, ruleUID: inst.ruleUID, labels: inst.labels, previous: previous, current: current})
+		}
+
+		previous := eval.Normal
+		var lastEnd time.Time
+		for i, iv := range inst.intervals {
+			if i > 0 && iv.start.Sub(lastEnd) > maxGap {
+				add(lastEnd.Add(step), previous, eval.Normal)
+				previous = eval.Normal
+			}
+			// An instance that is already in the state at the beginning of the range did not transition within it.
+			if i > 0 || iv.start.Sub(from) >= step {
+				add(iv.start, previous, iv.state)
+			}
+			previous = iv.state
+			if iv.end.After(lastEnd) {
+				lastEnd = iv.end
+			}
+		}
+		if len(inst.intervals) > 0 && to.Sub(lastEnd) > maxGap {
+			add(lastEnd.Add(step), previous, eval.Normal)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].time.Before(entries[j].time)
+	})
+	return entries, nil
+}
+
+// buildStateHistoryFrame builds the frame returned by the Loki backend from the rebuilt transitions.
+func (b *RemotePrometheusBackend) buildStateHistoryFrame(orgID int64, entries []historyEntry, rules map[string]*models.AlertRule) (*data.Frame, error) {
+	times := make([]time.Time, 0, len(entries))
+	lines := make([]json.RawMessage, 0, len(entries))
+	labels := make([]json.RawMessage, 0, len(entries))
+	for _, e := range entries {
+		streamLbls := map[string]string{
+			StateHistoryLabelKey: StateHistoryLabelValue,
+			OrgIDLabel:           fmt.Sprint(orgID),
+		}
+		entry := LokiEntry{
+			SchemaVersion:  1,
+			Previous:       e.previous.String(),
+			Current:        e.current.String(),
+			Values:         simplejson.New(), // the values of the query are not written to the series.
+			Fingerprint:    labelFingerprint(e.labels),
+			RuleTitle:      e.labels[alertNameLabel],
+			RuleUID:        e.ruleUID,
+			InstanceLabels: e.labels,
+		}
+		if rule, ok := rules[e.ruleUID]; ok {
+			entry.Condition = rule.Condition
+			entry.RuleTitle = rule.Title
+			entry.RuleID = rule.ID
+			if rule.DashboardUID != nil {
+				entry.DashboardUID = *rule.DashboardUID
+			}
+			if rule.PanelID != nil {
+				entry.PanelID = *rule.PanelID
+			}
+			streamLbls[GroupLabel] = rule.RuleGroup
+			streamLbls[FolderUIDLabel] = rule.NamespaceUID
+		}
+
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize state history entry: %w", err)
+		}
+		lblsJson, err := json.Marshal(streamLbls)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize stream labels: %w", err)
+		}
+		times = append(times, e.time)
+		lines = append(lines, line)
+		labels = append(labels, lblsJson)
+	}
+
+	return newStateHistoryFrame(times, lines, labels), nil
+}
+
+// newStateHistoryFrame returns the frame with the same fields as the one returned by the Loki backend.
+func newStateHistoryFrame(times []time.Time, lines, labels []json.RawMessage) *data.Frame {
+	lbls := data.Labels(map[string]string{})
+	frame := data.NewFrame("states")
+	frame.Fields = append(frame.Fields, data.NewField(dfTime, lbls, times))
+	frame.Fields = append(frame.Fields, data.NewField(dfLine, lbls, lines))
+	frame.Fields = append(frame.Fields, data.NewField(dfLabels, lbls, labels))
+	return frame
+}
@@ -3,7 +3,9 @@ package historian
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math"
 	"testing"
 	"time"
@@ -16,12 +18,18 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/folder"
+	acfakes "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol/fakes"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -59,7 +67,7 @@ func TestNewRemotePrometheusBackend(t *testing.T) {
 	logger := log.NewNopLogger()
 	met := metrics.NewHistorianMetrics(prometheus.NewRegistry(), "test")
 
-	backend := NewRemotePrometheusBackend(cfg, fakeWriter, logger, met)
+	backend := NewRemotePrometheusBackend(cfg, fakeWriter, nil, logger, met, nil, nil)
 
 	require.NotNil(t, backend)
 	require.Equal(t, cfg.DatasourceUID, backend.cfg.DatasourceUID)
@@ -287,7 +295,7 @@ func TestPrometheusBackend_Record(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			fakeWriter := new(fakeRemoteWriter)
 			met := metrics.NewHistorianMetrics(prometheus.NewRegistry(), "test")
-			backend := NewRemotePrometheusBackend(cfg, fakeWriter, logger, met)
+			backend := NewRemotePrometheusBackend(cfg, fakeWriter, nil, logger, met, nil, nil)
 
 			if tc.expectedFrames != nil {
 				var extraLabels map[string]string
@@ -319,18 +327,147 @@ func TestPrometheusBackend_Record(t *testing.T) {
 	}
 }
 
+type fakeSeriesQuerier struct {
+	frames  data.Frames
+	queries []string
+}
+
+func (f *fakeSeriesQuerier) QueryRange(_ context.Context, _ int64, query string, _, _ time.Time, _ time.Duration) (data.Frames, error) {
+	f.queries = append(f.queries, query)
+	return f.frames, nil
+}
+
 func TestPrometheusBackend_Query(t *testing.T) {
 	cfg := PrometheusConfig{DatasourceUID: "test-ds-uid", MetricName: testMetricName}
 	logger := log.NewNopLogger()
-	fakeWriter := new(fakeRemoteWriter)
 	met := metrics.NewHistorianMetrics(prometheus.NewRegistry(), "test")
+	usr := accesscontrol.BackgroundUser("test", 1, org.RoleNone, nil)
+
+	rule := ngmodels.RuleGen.With(ngmodels.RuleMuts.WithOrgID(1), ngmodels.RuleMuts.WithNamespaceUID("folder-1")).GenerateRef()
+	rules := fakes.NewRuleStore(t)
+	rules.Rules = map[int64][]*ngmodels.AlertRule{1: {rule}}
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(10 * time.Minute)
+	series := func(host, grafanaState string, start, end time.Duration) *data.Frame {
+		var times []time.Time
+		var values []float64
+		for d := start; d <= end; d += 10 * time.Second {
+			times = append(times, from.Add(d))
+			values = append(values, 1)
+		}
+		return data.NewFrame("",
+			data.NewField("Time", nil, times),
+			data.NewField("Value", data.Labels{
+				"__name__":             testMetricName,
+				alertRuleUIDLabel:      rule.UID,
+				alertNameLabel:         rule.Title,
+				"host":                 host,
+				alertStateLabel:        getPrometheusState(eval.Alerting),
+				grafanaAlertStateLabel: grafanaState,
+			}, values),
+		)
+	}
 
-	backend := NewRemotePrometheusBackend(cfg, fakeWriter, logger, met)
+	t.Run("rebuilds transitions from the series", func(t *testing.T) {
+		querier := &fakeSeriesQuerier{frames: data.Frames{
+			series("a", "pending", time.Minute, 2*time.Minute),
+			series("a", "alerting", 2*time.Minute+10*time.Second, 4*time.Minute),
+			// Instance b was alerting during the whole range, it did not transition.
+			series("b", "alerting", 0, 10*time.Minute),
+		}}
+		ac := &acfakes.FakeRuleService{}
+		ac.CanReadAllRulesFunc = func(ctx context.Context, requester identity.Requester) (bool, error) {
+			return true, nil
+		}
+		backend := NewRemotePrometheusBackend(cfg, new(fakeRemoteWriter), querier, logger, met, rules, ac)
+
+		frame, err := backend.Query(context.Background(), ngmodels.HistoryQuery{
+			OrgID:        1,
+			RuleUID:      rule.UID,
+			Labels:       map[string]string{"host": "a"},
+			From:         from,
+			To:           to,
+			SignedInUser: usr,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{testMetricName + `{grafana_rule_uid="` + rule.UID + `",host="a"}`}, querier.queries)
+
+		require.Equal(t, "states", frame.Name)
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, 3, frame.Rows())
+		expected := []struct {
+			time     time.Time
+			previous string
+			current  string
+		}{
+			{from.Add(time.Minute), "Normal", "Pending"},
+			{from.Add(2*time.Minute + 10*time.Second), "Pending", "Alerting"},
+			{from.Add(4*time.Minute + 10*time.Second), "Alerting", "Normal"},
+		}
+		for i, exp := range expected {
+			require.Equal(t, exp.time, frame.Fields[0].At(i))
+
+			var entry LokiEntry
+			require.NoError(t, json.Unmarshal(frame.Fields[1].At(i).(json.RawMessage), &entry))
+			require.Equal(t, exp.previous, entry.Previous)
+			require.Equal(t, exp.current, entry.Current)
+			require.Equal(t, rule.UID, entry.RuleUID)
+			require.Equal(t, rule.Title, entry.RuleTitle)
+			require.Equal(t, rule.Condition, entry.Condition)
+			require.Equal(t, map[string]string{alertNameLabel: rule.Title, "host": "a"}, entry.InstanceLabels)
+
+			var streamLabels map[string]string
+			require.NoError(t, json.Unmarshal(frame.Fields[2].At(i).(json.RawMessage), &streamLabels))
+			require.Equal(t, "folder-1", streamLabels[FolderUIDLabel])
+		}
 
-	frame, err := backend.Query(context.Background(), ngmodels.HistoryQuery{})
-	require.Error(t, err)
-	require.Nil(t, frame)
-	require.Contains(t, err.Error(), "prometheus historian backend does not support querying")
+		t.Run("keeps the most recent transitions", func(t *testing.T) {
+			frame, err := backend.Query(context.Background(), ngmodels.HistoryQuery{OrgID: 1, RuleUID: rule.UID, From: from, To: to, Limit: 1, SignedInUser: usr})
+			require.NoError(t, err)
+			require.Equal(t, 1, frame.Rows())
+			require.Equal(t, from.Add(4*time.Minute+10*time.Second), frame.Fields[0].At(0))
+		})
+	})
+
+	t.Run("queries the rules of the folders the user can read in batches", func(t *testing.T) {
+		visible := ngmodels.RuleGen.With(ngmodels.RuleMuts.WithOrgID(1), ngmodels.RuleMuts.WithNamespaceUID("folder-2")).GenerateManyRef(2*prometheusQueryRuleUIDBatchSize + 1)
+		store := fakes.NewRuleStore(t)
+		store.Rules = map[int64][]*ngmodels.AlertRule{1: append([]*ngmodels.AlertRule{rule}, visible...)}
+		store.Folders = map[int64][]*folder.Folder{1: {{UID: "folder-1", OrgID: 1}, {UID: "folder-2", OrgID: 1}}}
+		ac := &acfakes.FakeRuleService{}
+		ac.HasAccessInFolderFunc = func(ctx context.Context, requester identity.Requester, namespaced ngmodels.Namespaced) (bool, error) {
+			return namespaced.GetNamespaceUID() == "folder-2", nil
+		}
+		querier := &fakeSeriesQuerier{}
+		backend := NewRemotePrometheusBackend(cfg, new(fakeRemoteWriter), querier, logger, met, store, ac)
+
+		_, err := backend.Query(context.Background(), ngmodels.HistoryQuery{OrgID: 1, From: from, To: to, SignedInUser: usr})
+		require.NoError(t, err)
+		require.Len(t, querier.queries, 3)
+		for _, q := range querier.queries {
+			require.NotContains(t, q, rule.UID)
+		}
+		require.Contains(t, querier.queries[2], fmt.Sprintf(`{%s="`, alertRuleUIDLabel))
+	})
+
+	t.Run("returns an error for the filters it does not support", func(t *testing.T) {
+		querier := &fakeSeriesQuerier{}
+		backend := NewRemotePrometheusBackend(cfg, new(fakeRemoteWriter), querier, logger, met, rules, &acfakes.FakeRuleService{})
+
+		_, err := backend.Query(context.Background(), ngmodels.HistoryQuery{OrgID: 1, RuleUID: rule.UID, States: []string{"Alerting"}, SignedInUser: usr})
+		require.ErrorIs(t, err, ErrUnsupportedQuery)
+		require.Empty(t, querier.queries)
+	})
+
+	t.Run("returns an error if the user cannot read rules in any folder", func(t *testing.T) {
+		querier := &fakeSeriesQuerier{}
+		backend := NewRemotePrometheusBackend(cfg, new(fakeRemoteWriter), querier, logger, met, rules, &acfakes.FakeRuleService{})
+
+		_, err := backend.Query(context.Background(), ngmodels.HistoryQuery{OrgID: 1, SignedInUser: usr})
+		require.Error(t, err)
+		require.Empty(t, querier.queries)
+	})
 }
 
 func TestPrometheusBackend_Record_Metrics(t *testing.T) {
@@ -347,7 +484,7 @@ func TestPrometheusBackend_Record_Metrics(t *testing.T) {
 
 		registry := prometheus.NewRegistry()
 		met := metrics.NewHistorianMetrics(registry, "test")
-		backend := NewRemotePrometheusBackend(cfg, fakeWriter, logger, met)
+		backend := NewRemotePrometheusBackend(cfg, fakeWriter, nil, logger, met, nil, nil)
 
 		states := []state.StateTransition{
 			{State: &state.State{AlertRuleUID: "rule-uid", OrgID: orgID, Labels: data.Labels{}, State: eval.Alerting, LastEvaluationTime: now}},
@@ -384,7 +521,7 @@ func TestPrometheusBackend_Record_Metrics(t *testing.T) {
 
 		registry := prometheus.NewRegistry()
 		met := metrics.NewHistorianMetrics(registry, "test")
-		backend := NewRemotePrometheusBackend(cfg, fakeWriter, logger, met)
+		backend := NewRemotePrometheusBackend(cfg, fakeWriter, nil, logger, met, nil, nil)
 
 		states := []state.StateTransition{
 			{State: &state.State{AlertRuleUID: "rule-uid", OrgID: orgID, Labels: data.Labels{}, State: eval.Alerting, LastEvaluationTime: now}},
@@ -434,7 +571,7 @@ func TestPrometheusBackend_Record_PanicRecovery(t *testing.T) {
 	panicWriter.On("WriteDatasource", ctx, cfg.DatasourceUID, testMetricName, now, mock.Anything, orgID, mock.Anything).Once()
 
 	met := metrics.NewHistorianMetrics(prometheus.NewRegistry(), "test")
-	backend := NewRemotePrometheusBackend(cfg, panicWriter, logger, met)
+	backend := NewRemotePrometheusBackend(cfg, panicWriter, nil, logger, met, nil, nil)
 
 	states := []state.StateTransition{
 		{State: &state.State{
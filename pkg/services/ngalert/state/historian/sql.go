@@ -0,0 +1,461 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	amlabels "github.com/prometheus/alertmanager/pkg/labels"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	stateHistoryTable = "alert_state_history"
+	// defaultSQLQueryLimit is the number of transitions returned when the query has no limit.
+	defaultSQLQueryLimit = 1000
+	// sqlCleanupBatchSize is the number of transitions deleted at once by the retention sweeper.
+	// It is kept below the SQLite limit of 999 parameters per statement.
+	sqlCleanupBatchSize = 500
+	// sqlCleanupLockName is the name of the server lock that makes only one Grafana instance delete the expired history.
+	sqlCleanupLockName = "delete expired alert state history"
+	// sqlMatcherScanLimit is the maximum number of transitions read to apply label matchers,
+	// which the database can't apply as the labels are stored as JSON.
+	sqlMatcherScanLimit = 10000
+)
+
+var errStopIteration = errors.New("stop iteration")
+
+// ErrQueryTooBroad is returned when the label matchers of a query need to read too many transitions.
+var ErrQueryTooBroad = errutil.BadRequest("alerting.state-history.queryTooBroad", errutil.WithPublicMessage(
+	"Too many state changes to match the labels. Narrow the time range or filter by rule, dashboard or state.",
+))
+
+type SQLConfig struct {
+	// Retention is how long transitions are kept. Zero keeps them forever.
+	Retention       time.Duration
+	CleanupInterval time.Duration
+}
+
+func NewSQLConfig(cfg setting.UnifiedAlertingStateHistorySettings) SQLConfig {
+	return SQLConfig{
+		Retention:       cfg.SQLRetention,
+		CleanupInterval: cfg.SQLCleanupInterval,
+	}
+}
+
+// stateHistoryEntry is a row of the alert_state_history table.
+type stateHistoryEntry struct {
+	ID             int64   `xorm:"pk autoincr 'id'"`
+	OrgID          int64   `xorm:"org_id"`
+	RuleUID        string  `xorm:"rule_uid"`
+	RuleID         int64   `xorm:"rule_id"`
+	RuleTitle      string  `xorm:"rule_title"`
+	NamespaceUID   string  `xorm:"namespace_uid"`
+	RuleGroup      string  `xorm:"rule_group"`
+	DashboardUID   *string `xorm:"dashboard_uid"`
+	PanelID        *int64  `xorm:"panel_id"`
+	Condition      string  `xorm:"rule_condition"`
+	Fingerprint    string  `xorm:"fingerprint"`
+	Labels         string  `xorm:"labels"`
+	PreviousState  string  `xorm:"previous_state"`
+	PreviousReason string  `xorm:"previous_reason"`
+	State          string  `xorm:"state"`
+	Reason         string  `xorm:"reason"`
+	Values         *string `xorm:"state_values"`
+	Error          *string `xorm:"state_error"`
+	// Epoch is the time of the transition in milliseconds.
+	Epoch int64 `xorm:"epoch"`
+}
+
+// serverLocker runs a function on only one of the Grafana instances sharing the database.
+type serverLocker interface {
+	LockAndExecute(ctx context.Context, actionName string, maxInterval time.Duration, fn func(ctx context.Context)) error
+}
+
+// SQLBackend is an implementation of state.Historian that stores state history in a table of the Grafana database.
+type SQLBackend struct {
+	cfg       SQLConfig
+	db        db.DB
+	ruleStore RuleStore
+	ac        AccessControl
+	locker    serverLocker
+	clock     clock.Clock
+	metrics   *metrics.Historian
+	log       log.Logger
+	// matcherScanLimit is the maximum number of transitions read to apply the label matchers of a query.
+	matcherScanLimit int
+}
+
+func NewSQLBackend(cfg SQLConfig, store db.DB, ruleStore RuleStore, ac AccessControl, locker serverLocker, clock clock.Clock, metrics *metrics.Historian, logger log.Logger) *SQLBackend {
+	return &SQLBackend{
+		cfg:       cfg,
+		db:        store,
+		ruleStore: ruleStore,
+		ac:        ac,
+		locker:    locker,
+		clock:     clock,
+		metrics:   metrics,
+		log:       logger,
+
+		matcherScanLimit: sqlMatcherScanLimit,
+	}
+}
+
+// Record writes a number of state transitions for a given rule to the alert_state_history table.
+func (h *SQLBackend) Record(ctx context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	logger := h.log.FromContext(ctx)
+	// Build the rows before starting goroutine, to make sure all data is copied and won't mutate underneath us.
+	entries := buildStateHistoryEntries(rule, states, logger)
+
+	errCh := make(chan error, 1)
+	if len(entries) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	// This is a new background job, so let's create a brand new context for it.
+	// We want it to be isolated, i.e. we don't want grafana shutdowns to interrupt this work
+	// immediately but rather try to flush writes.
+	writeCtx := context.Background()
+	writeCtx, cancel := context.WithTimeout(writeCtx, StateHistoryWriteTimeout)
+	writeCtx = history_model.WithRuleData(writeCtx, rule)
+	writeCtx = trace.ContextWithSpan(writeCtx, trace.SpanFromContext(ctx))
+
+	go func(ctx context.Context) {
+		defer cancel()
+		defer close(errCh)
+		logger := h.log.FromContext(ctx)
+		logger.Debug("Saving state history batch", "samples", len(entries))
+		org := fmt.Sprint(rule.OrgID)
+		h.metrics.WritesTotal.WithLabelValues(org, "sql").Inc()
+		h.metrics.TransitionsTotal.WithLabelValues(org).Add(float64(len(entries)))
+
+		err := h.db.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.BulkInsert(stateHistoryTable, entries, sqlstore.NativeSettingsForDialect(h.db.GetDialect()))
+			return err
+		})
+		if err != nil {
+			logger.Error("Failed to save alert state history batch", "error", err)
+			h.metrics.WritesFailed.WithLabelValues(org, "sql").Inc()
+			h.metrics.TransitionsFailed.WithLabelValues(org).Add(float64(len(entries)))
+			errCh <- fmt.Errorf("failed to save alert state history batch: %w", err)
+			return
+		}
+		logger.Debug("Done saving alert state history batch", "samples", len(entries))
+	}(writeCtx)
+	return errCh
+}
+
+func buildStateHistoryEntries(rule history_model.RuleMeta, states []state.StateTransition, logger log.Logger) []stateHistoryEntry {
+	entries := make([]stateHistoryEntry, 0, len(states))
+	for _, st := range states {
+		if !shouldRecord(st) {
+			continue
+		}
+
+		sanitizedLabels := removePrivateLabels(st.Labels)
+		lbls, err := json.Marshal(sanitizedLabels)
+		if err != nil {
+			logger.Error("Failed to serialize labels of state, skipping", "error", err)
+			continue
+		}
+		entry := stateHistoryEntry{
+			OrgID:          rule.OrgID,
+			RuleUID:        rule.UID,
+			RuleID:         rule.ID,
+			RuleTitle:      rule.Title,
+			NamespaceUID:   rule.NamespaceUID,
+			RuleGroup:      rule.Group,
+			Condition:      rule.Condition,
+			Fingerprint:    labelFingerprint(sanitizedLabels),
+			Labels:         string(lbls),
+			PreviousState:  st.PreviousState.String(),
+			PreviousReason: st.PreviousStateReason,
+			State:          st.State.State.String(),
+			Reason:         st.StateReason,
+			Epoch:          st.LastEvaluationTime.UnixMilli(),
+		}
+		if rule.DashboardUID != "" {
+			entry.DashboardUID = &rule.DashboardUID
+			entry.PanelID = &rule.PanelID
+		}
+		if values := valuesAsDataBlob(st.State); values != nil {
+			if b, err := values.Encode(); err == nil {
+				v := string(b)
+				entry.Values = &v
+			}
+		}
+		if st.State.State == eval.Error && st.Error != nil {
+			e := st.Error.Error()
+			entry.Error = &e
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Query returns the most recent state transitions matching the query, paginated with its limit and offset,
+// in the same frame as the one returned by the Loki backend.
+func (h *SQLBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	uids, err := getFolderUIDsForFilter(ctx, query, h.ac, h.ruleStore)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]string, 0, len(query.States))
+	for _, s := range query.States {
+		st, err := eval.ParseStateString(s)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, st.String())
+	}
+
+	matchers := make(amlabels.Matchers, 0, len(query.Labels)+len(query.Matchers))
+	for k, v := range query.Labels {
+		m, err := amlabels.NewMatcher(amlabels.MatchEqual, k, v)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	matchers = append(matchers, query.Matchers...)
+
+	now := h.clock.Now().UTC()
+	if query.To.IsZero() {
+		query.To = now
+	}
+	if query.From.IsZero() {
+		query.From = now.Add(-defaultQueryRange)
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultSQLQueryLimit
+	}
+	offset := max(query.Offset, 0)
+
+	entries := make([]*stateHistoryEntry, 0)
+	err = h.db.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table(stateHistoryTable).
+			Where("org_id = ?", query.OrgID).
+			And("epoch >= ?", query.From.UnixMilli()).
+			And("epoch <= ?", query.To.UnixMilli())
+		if query.RuleUID != "" {
+			q = q.And("rule_uid = ?", query.RuleUID)
+		}
+		if len(uids) > 0 {
+			q = q.In("namespace_uid", uids)
+		}
+		if query.DashboardUID != "" {
+			q = q.And("dashboard_uid = ?", query.DashboardUID)
+			if query.PanelID != 0 {
+				q = q.And("panel_id = ?", query.PanelID)
+			}
+		}
+		if len(states) > 0 {
+			q = q.In("state", states)
+		}
+		q = q.Desc("epoch", "id")
+
+		// Labels are stored as JSON, so label matchers are applied while reading the rows,
+		// up to a limit so that a matcher that matches few rows doesn't read the whole table.
+		if len(matchers) == 0 {
+			return q.Limit(limit, offset).Find(&entries)
+		}
+		skipped, scanned := 0, 0
+		err := q.Iterate(new(stateHistoryEntry), func(_ int, bean any) error {
+			scanned++
+			if scanned > h.matcherScanLimit {
+				return ErrQueryTooBroad.Errorf("label matchers scanned more than %d state history entries", h.matcherScanLimit)
+			}
+			entry := bean.(*stateHistoryEntry)
+			lbls := map[string]string{}
+			if err := json.Unmarshal([]byte(entry.Labels), &lbls); err != nil {
+				return fmt.Errorf("failed to parse labels of state history entry %d: %w", entry.ID, err)
+			}
+			if !matchLabels(matchers, lbls) {
+				return nil
+			}
+			if skipped < offset {
+				skipped++
+				return nil
+			}
+			entries = append(entries, entry)
+			if len(entries) == limit {
+				return errStopIteration
+			}
+			return nil
+		})
+		if errors.Is(err, errStopIteration) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query state history: %w", err)
+	}
+
+	// Results are sorted by time like the ones of the other backends.
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Epoch < entries[j].Epoch
+	})
+	return stateHistoryEntriesToFrame(entries)
+}
+
+func matchLabels(matchers amlabels.Matchers, lbls map[string]string) bool {
+	for _, m := range matchers {
+		if !m.Matches(lbls[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
+
+func stateHistoryEntriesToFrame(entries []*stateHistoryEntry) (*data.Frame, error) {
+	times := make([]time.Time, 0, len(entries))
+	lines := make([]json.RawMessage, 0, len(entries))
+	labels := make([]json.RawMessage, 0, len(entries))
+	for _, e := range entries {
+		instanceLabels := map[string]string{}
+		if err := json.Unmarshal([]byte(e.Labels), &instanceLabels); err != nil {
+			return nil, fmt.Errorf("failed to parse labels of state history entry %d: %w", e.ID, err)
+		}
+		values := simplejson.New()
+		if e.Values != nil {
+			v, err := simplejson.NewJson([]byte(*e.Values))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse values of state history entry %d: %w", e.ID, err)
+			}
+			values = v
+		}
+		previous, err := eval.ParseStateString(e.PreviousState)
+		if err != nil {
+			return nil, err
+		}
+		current, err := eval.ParseStateString(e.State)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := LokiEntry{
+			SchemaVersion:  1,
+			Previous:       state.FormatStateAndReason(previous, e.PreviousReason),
+			Current:        state.FormatStateAndReason(current, e.Reason),
+			Values:         values,
+			Condition:      e.Condition,
+			Fingerprint:    e.Fingerprint,
+			RuleTitle:      e.RuleTitle,
+			RuleID:         e.RuleID,
+			RuleUID:        e.RuleUID,
+			InstanceLabels: instanceLabels,
+		}
+		if e.DashboardUID != nil {
+			entry.DashboardUID = *e.DashboardUID
+		}
+		if e.PanelID != nil {
+			entry.PanelID = *e.PanelID
+		}
+		if e.Error != nil {
+			entry.Error = *e.Error
+		}
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize state history entry: %w", err)
+		}
+		lblsJson, err := json.Marshal(map[string]string{
+			StateHistoryLabelKey: StateHistoryLabelValue,
+			OrgIDLabel:           fmt.Sprint(e.OrgID),
+			GroupLabel:           e.RuleGroup,
+			FolderUIDLabel:       e.NamespaceUID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize stream labels: %w", err)
+		}
+
+		times = append(times, time.UnixMilli(e.Epoch))
+		lines = append(lines, line)
+		labels = append(labels, lblsJson)
+	}
+	return newStateHistoryFrame(times, lines, labels), nil
+}
+
+// Run deletes the transitions older than the retention every cleanup interval, until the context is cancelled.
+// In high availability mode, the server lock makes only one of the instances delete them in each interval.
+func (h *SQLBackend) Run(ctx context.Context) error {
+	if h.cfg.Retention <= 0 {
+		return nil
+	}
+	ticker := h.clock.Ticker(h.cfg.CleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			err := h.locker.LockAndExecute(ctx, sqlCleanupLockName, h.cfg.CleanupInterval, func(ctx context.Context) {
+				deleted, err := h.DeleteExpired(ctx)
+				if err != nil {
+					h.log.Error("Failed to delete expired state history", "error", err)
+					return
+				}
+				h.log.Debug("Deleted expired state history", "count", deleted)
+			})
+			if err != nil {
+				h.log.Error("Failed to acquire the lock to delete expired state history", "error", err)
+			}
+		}
+	}
+}
+
+// DeleteExpired deletes the transitions older than the retention, in batches, and returns how many were deleted.
+func (h *SQLBackend) DeleteExpired(ctx context.Context) (int64, error) {
+	if h.cfg.Retention <= 0 {
+		return 0, nil
+	}
+	cutoff := h.clock.Now().Add(-h.cfg.Retention).UnixMilli()
+
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		var deleted int64
+		// Loading the IDs first avoids deadlocks with concurrent inserts on MySQL, like the annotation cleanup does.
+		err := h.db.WithDbSession(ctx, func(sess *db.Session) error {
+			ids := make([]int64, 0, sqlCleanupBatchSize)
+			if err := sess.Table(stateHistoryTable).Where("epoch < ?", cutoff).Asc("id").Limit(sqlCleanupBatchSize).Cols("id").Find(&ids); err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+			n, err := sess.Table(stateHistoryTable).In("id", ids).Delete(&stateHistoryEntry{})
+			deleted = n
+			return err
+		})
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+		if deleted == 0 {
+			return total, nil
+		}
+	}
+}
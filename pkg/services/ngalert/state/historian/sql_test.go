@@ -0,0 +1,167 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	amlabels "github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	acfakes "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol/fakes"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationSQLBackend(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	usr := accesscontrol.BackgroundUser("test", 1, org.RoleNone, nil)
+	ac := &acfakes.FakeRuleService{}
+	ac.CanReadAllRulesFunc = func(ctx context.Context, requester identity.Requester) (bool, error) {
+		return true, nil
+	}
+	clk := clock.NewMock()
+	clk.Set(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	met := metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem)
+	sqlStore := db.InitTestDB(t)
+	backend := NewSQLBackend(SQLConfig{Retention: time.Hour, CleanupInterval: time.Minute}, sqlStore, fakes.NewRuleStore(t), ac, serverlock.ProvideService(sqlStore, tracing.InitializeTracerForTest()), clk, met, log.NewNopLogger())
+
+	rule := createTestRule()
+	transition := func(host string, previous, current eval.State, ago time.Duration) state.StateTransition {
+		st := &state.State{
+			State:              current,
+			Labels:             data.Labels{"host": host, "__private__": "x"},
+			Values:             map[string]float64{"A": 1},
+			LastEvaluationTime: clk.Now().Add(-ago),
+		}
+		if current == eval.Error {
+			st.Error = fmt.Errorf("oh no")
+		}
+		return state.StateTransition{PreviousState: previous, State: st}
+	}
+	states := []state.StateTransition{
+		transition("a", eval.Normal, eval.Pending, 50*time.Minute),
+		transition("a", eval.Pending, eval.Alerting, 40*time.Minute),
+		transition("b", eval.Normal, eval.Error, 30*time.Minute),
+		transition("a", eval.Alerting, eval.Normal, 20*time.Minute),
+		// Not a transition, it is not recorded.
+		transition("c", eval.Normal, eval.Normal, 10*time.Minute),
+		// Older than the retention.
+		transition("a", eval.Normal, eval.Alerting, 2*time.Hour),
+	}
+	require.NoError(t, <-backend.Record(ctx, rule, states))
+
+	query := func(t *testing.T, q models.HistoryQuery) []LokiEntry {
+		t.Helper()
+		q.OrgID = rule.OrgID
+		q.SignedInUser = usr
+		q.From = clk.Now().Add(-3 * time.Hour)
+		q.To = clk.Now()
+		frame, err := backend.Query(ctx, q)
+		require.NoError(t, err)
+		require.Equal(t, "states", frame.Name)
+		entries := make([]LokiEntry, 0, frame.Rows())
+		for i := 0; i < frame.Rows(); i++ {
+			var entry LokiEntry
+			require.NoError(t, json.Unmarshal(frame.Fields[1].At(i).(json.RawMessage), &entry))
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+	current := func(entries []LokiEntry) []string {
+		res := make([]string, 0, len(entries))
+		for _, e := range entries {
+			res = append(res, e.InstanceLabels["host"]+":"+e.Current)
+		}
+		return res
+	}
+
+	t.Run("returns the transitions sorted by time", func(t *testing.T) {
+		entries := query(t, models.HistoryQuery{RuleUID: rule.UID})
+		require.Equal(t, []string{"a:Alerting", "a:Pending", "a:Alerting", "b:Error", "a:Normal"}, current(entries))
+
+		e := entries[1]
+		require.Equal(t, "Normal", e.Previous)
+		require.Equal(t, rule.Title, e.RuleTitle)
+		require.Equal(t, rule.DashboardUID, e.DashboardUID)
+		require.Equal(t, map[string]string{"host": "a"}, e.InstanceLabels)
+		require.Equal(t, labelFingerprint(data.Labels{"host": "a"}), e.Fingerprint)
+		require.Equal(t, 1.0, e.Values.Get("A").MustFloat64())
+		require.Equal(t, "oh no", entries[3].Error)
+	})
+
+	t.Run("filters by state", func(t *testing.T) {
+		entries := query(t, models.HistoryQuery{RuleUID: rule.UID, States: []string{"alerting", "Error"}})
+		require.Equal(t, []string{"a:Alerting", "a:Alerting", "b:Error"}, current(entries))
+	})
+
+	t.Run("filters by labels", func(t *testing.T) {
+		entries := query(t, models.HistoryQuery{Labels: map[string]string{"host": "b"}})
+		require.Equal(t, []string{"b:Error"}, current(entries))
+
+		entries = query(t, models.HistoryQuery{Matchers: amlabels.Matchers{amlabels.MustNewMatcher(amlabels.MatchNotEqual, "host", "b")}})
+		require.Equal(t, []string{"a:Alerting", "a:Pending", "a:Alerting", "a:Normal"}, current(entries))
+	})
+
+	t.Run("returns an error when label matchers scan too many transitions", func(t *testing.T) {
+		backend.matcherScanLimit = 3
+		t.Cleanup(func() { backend.matcherScanLimit = sqlMatcherScanLimit })
+
+		q := models.HistoryQuery{OrgID: rule.OrgID, SignedInUser: usr, From: clk.Now().Add(-3 * time.Hour), To: clk.Now(), Labels: map[string]string{"host": "b"}}
+		_, err := backend.Query(ctx, q)
+		require.ErrorIs(t, err, ErrQueryTooBroad)
+
+		// The filters applied by the database reduce the number of scanned transitions.
+		entries := query(t, models.HistoryQuery{Labels: map[string]string{"host": "b"}, States: []string{"Error"}})
+		require.Equal(t, []string{"b:Error"}, current(entries))
+	})
+
+	t.Run("paginates from the most recent transitions", func(t *testing.T) {
+		entries := query(t, models.HistoryQuery{Limit: 2})
+		require.Equal(t, []string{"b:Error", "a:Normal"}, current(entries))
+
+		entries = query(t, models.HistoryQuery{Limit: 2, Offset: 2})
+		require.Equal(t, []string{"a:Pending", "a:Alerting"}, current(entries))
+
+		entries = query(t, models.HistoryQuery{Limit: 2, Offset: 1, Labels: map[string]string{"host": "a"}})
+		require.Equal(t, []string{"a:Pending", "a:Alerting"}, current(entries))
+	})
+
+	t.Run("returns an error for an invalid state", func(t *testing.T) {
+		_, err := backend.Query(ctx, models.HistoryQuery{OrgID: rule.OrgID, SignedInUser: usr, States: []string{"foo"}})
+		require.Error(t, err)
+	})
+
+	t.Run("deletes the transitions older than the retention", func(t *testing.T) {
+		deleted, err := backend.DeleteExpired(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+
+		entries := query(t, models.HistoryQuery{})
+		require.Equal(t, []string{"a:Pending", "a:Alerting", "b:Error", "a:Normal"}, current(entries))
+	})
+}
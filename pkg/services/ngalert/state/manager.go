@@ -180,40 +180,7 @@ func (st *Manager) Warm(ctx context.Context, orgReader OrgReader, rulesReader Ru
 				continue
 			}
 
-			// nil safety.
-			annotations := ruleForEntry.Annotations
-			if annotations == nil {
-				annotations = make(map[string]string)
-			}
-
-			lbs := map[string]string(entry.Labels)
-			cacheID := entry.Labels.Fingerprint()
-			var resultFp data.Fingerprint
-			if entry.ResultFingerprint != "" {
-				fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
-				if err != nil {
-					logger.Error("Failed to parse result fingerprint of alert instance", "error", err, "rule_uid", entry.RuleUID)
-				}
-				resultFp = data.Fingerprint(fp)
-			}
-			state := &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheID:              cacheID,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				StateReason:          entry.CurrentReason,
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				FiredAt:              entry.FiredAt,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          annotations,
-				ResultFingerprint:    resultFp,
-				ResolvedAt:           entry.ResolvedAt,
-				LastSentAt:           entry.LastSentAt,
-			}
-			st.cache.set(state)
+			st.cache.set(stateFromInstance(ruleForEntry, entry, logger))
 			statesCount++
 		}
 	}
@@ -221,6 +188,67 @@ func (st *Manager) Warm(ctx context.Context, orgReader OrgReader, rulesReader Ru
 	logger.Info("State cache has been initialized", "states", statesCount, "duration", time.Since(startTime))
 }
 
+// LoadStateByRuleUID replaces the states of the rule in the cache with the ones persisted in the instance store.
+// It is used when the rule was evaluated by another instance of Grafana until now, so its evaluation continues from
+// the state that instance left behind instead of starting from scratch. It returns the number of loaded states.
+func (st *Manager) LoadStateByRuleUID(ctx context.Context, rule *ngModels.AlertRule) (int, error) {
+	st.ForgetStateByRuleUID(ctx, rule.GetKeyWithGroup())
+	if st.instanceStore == nil {
+		return 0, nil
+	}
+
+	alertInstances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{
+		RuleOrgID: rule.OrgID,
+		RuleUID:   rule.UID,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	logger := st.log.FromContext(ctx)
+	for _, entry := range alertInstances {
+		st.cache.set(stateFromInstance(rule, entry, logger))
+	}
+	return len(alertInstances), nil
+}
+
+// stateFromInstance converts an alert instance persisted in the instance store to a state of the rule.
+func stateFromInstance(rule *ngModels.AlertRule, entry *ngModels.AlertInstance, logger log.Logger) *State {
+	// nil safety.
+	annotations := rule.Annotations
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	lbs := map[string]string(entry.Labels)
+	cacheID := instanceFingerprint(data.Labels(entry.Labels))
+	var resultFp data.Fingerprint
+	if entry.ResultFingerprint != "" {
+		fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
+		if err != nil {
+			logger.Error("Failed to parse result fingerprint of alert instance", "error", err, "rule_uid", entry.RuleUID)
+		}
+		resultFp = data.Fingerprint(fp)
+	}
+	return &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheID:              cacheID,
+		Labels:               lbs,
+		State:                translateInstanceState(entry.CurrentState),
+		StateReason:          entry.CurrentReason,
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		FiredAt:              entry.FiredAt,
+		LastEvaluationTime:   entry.LastEvalTime,
+		Annotations:          annotations,
+		ResultFingerprint:    resultFp,
+		ResolvedAt:           entry.ResolvedAt,
+		LastSentAt:           entry.LastSentAt,
+	}
+}
+
 func (st *Manager) Get(orgID int64, alertRuleUID string, stateId data.Fingerprint) *State {
 	return st.cache.get(orgID, alertRuleUID, stateId)
 }
@@ -1883,6 +1883,77 @@ func TestIntegrationDeleteStateByRuleUID(t *testing.T) {
 	}
 }
 
+func TestIntegrationLoadStateByRuleUID(t *testing.T) {
+	interval := time.Minute
+	ctx := context.Background()
+	ng, dbstore := tests.SetupTestEnv(t, 1)
+
+	orgService, err := alertTestUtil.SetupOrgService(t, dbstore.SQLStore, setting.NewCfg())
+	require.NoError(t, err)
+	mainOrg, err := orgService.CreateWithMember(ctx, &org.CreateOrgCommand{})
+	require.NoError(t, err)
+
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, int64(interval.Seconds()), mainOrg.ID)
+	other := tests.CreateTestAlertRule(t, ctx, dbstore, int64(interval.Seconds()), mainOrg.ID)
+
+	cfg := state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore: ng.InstanceStore,
+		Images:        &state.NoopImageService{},
+		Clock:         clock.NewMock(),
+		Historian:     &state.FakeHistorian{},
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+
+	// A state that is only in the cache, as if it was left over by a previous evaluation.
+	st.Put([]*state.State{setCacheID(&state.State{
+		AlertRuleUID: rule.UID,
+		OrgID:        rule.OrgID,
+		Labels:       data.Labels{"stale": "true"},
+		State:        eval.Alerting,
+	})})
+	otherState := setCacheID(&state.State{
+		AlertRuleUID: other.UID,
+		OrgID:        other.OrgID,
+		Labels:       data.Labels{"other": "true"},
+		State:        eval.Alerting,
+	})
+	st.Put([]*state.State{otherState})
+
+	// The states saved by the instance that evaluated the rule so far.
+	labels := models.InstanceLabels{"test1": "testValue1"}
+	_, hash, _ := labels.StringAndHash()
+	startsAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, ng.InstanceStore.SaveAlertInstance(ctx, models.AlertInstance{
+		AlertInstanceKey: models.AlertInstanceKey{
+			RuleOrgID:  rule.OrgID,
+			RuleUID:    rule.UID,
+			LabelsHash: hash,
+		},
+		CurrentState:      models.InstanceStateFiring,
+		CurrentStateSince: startsAt,
+		CurrentStateEnd:   startsAt.Add(4 * interval),
+		LastEvalTime:      startsAt.Add(interval),
+		Labels:            labels,
+	}))
+
+	loaded, err := st.LoadStateByRuleUID(ctx, rule)
+	require.NoError(t, err)
+	require.Equal(t, 1, loaded)
+
+	states := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
+	require.Len(t, states, 1)
+	assert.Equal(t, data.Labels{"test1": "testValue1"}, states[0].Labels)
+	assert.Equal(t, eval.Alerting, states[0].State)
+	assert.Equal(t, startsAt, states[0].StartsAt.UTC())
+	assert.Equal(t, rule.Annotations, states[0].Annotations)
+
+	// The states of the other rules are left untouched.
+	assert.Equal(t, []*state.State{otherState}, st.GetStatesForRuleUID(other.OrgID, other.UID))
+}
+
 func TestIntegrationResetStateByRuleUID(t *testing.T) {
 	interval := time.Minute
 	ctx := context.Background()
@@ -83,7 +83,7 @@ type State struct {
 func newState(ctx context.Context, log log.Logger, alertRule *models.AlertRule, result eval.Result, extraLabels data.Labels, externalURL *url.URL) *State {
 	lbs, annotations := expandAnnotationsAndLabels(ctx, log, alertRule, result, extraLabels, externalURL)
 
-	cacheID := lbs.Fingerprint()
+	cacheID := instanceFingerprint(lbs)
 	// For new states, we set StartsAt & EndsAt to EvaluatedAt as this is the
 	// expected value for a Normal state during state transition.
 	return &State{
@@ -148,8 +148,24 @@ func (a *State) GetRuleKey() models.AlertRuleKey {
 	}
 }
 
+// instanceFingerprint returns the fingerprint of the labels of an alert instance, without the severity label
+// so that the instance keeps its identity when the severity changes.
+func instanceFingerprint(lbs data.Labels) data.Fingerprint {
+	if _, ok := lbs[models.SeverityLabel]; !ok {
+		return lbs.Fingerprint()
+	}
+	withoutSeverity := lbs.Copy()
+	delete(withoutSeverity, models.SeverityLabel)
+	return withoutSeverity.Fingerprint()
+}
+
 func (a *State) GetAlertInstanceKey() (models.AlertInstanceKey, error) {
 	instanceLabels := models.InstanceLabels(a.Labels)
+	if _, ok := instanceLabels[models.SeverityLabel]; ok {
+		// the key of the instance is stable when the severity changes.
+		instanceLabels = models.InstanceLabels(a.Labels.Copy())
+		delete(instanceLabels, models.SeverityLabel)
+	}
 	_, labelsHash, err := instanceLabels.StringAndHash()
 	if err != nil {
 		return models.AlertInstanceKey{}, err
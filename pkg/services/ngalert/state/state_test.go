@@ -858,6 +858,31 @@ func TestNewState(t *testing.T) {
 			require.Equal(t, expected, state.Labels[key])
 		}
 	})
+	t.Run("should add the severity of the result as a label", func(t *testing.T) {
+		rule := generateRule()
+		rule.Labels = map[string]string{ngmodels.SeverityLabel: "rule-severity"}
+
+		result := eval.Result{
+			Instance: ngmodels.GenerateAlertLabels(5, "result-"),
+			Severity: "critical",
+		}
+		state := newState(context.Background(), l, rule, result, nil, url)
+		require.Equal(t, "critical", state.Labels[ngmodels.SeverityLabel])
+		require.NotContains(t, state.Annotations, ngmodels.SeverityLabel)
+
+		other := newState(context.Background(), l, rule, eval.Result{Instance: result.Instance, Severity: "warning"}, nil, url)
+		require.Equal(t, "warning", other.Labels[ngmodels.SeverityLabel])
+		require.Equal(t, state.CacheID, other.CacheID, "a change of severity should not change the identity of the alert instance")
+
+		key, err := state.GetAlertInstanceKey()
+		require.NoError(t, err)
+		otherKey, err := other.GetAlertInstanceKey()
+		require.NoError(t, err)
+		require.Equal(t, key, otherKey, "a change of severity should not change the key of the alert instance")
+
+		restored := stateFromInstance(rule, &ngmodels.AlertInstance{Labels: ngmodels.InstanceLabels(other.Labels)}, l)
+		require.Equal(t, state.CacheID, restored.CacheID)
+	})
 	t.Run("rule labels should take precedence over result labels", func(t *testing.T) {
 		rule := generateRule()
 
@@ -0,0 +1,462 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// alertRuleTemplate represents a record in alert_rule_template table
+type alertRuleTemplate struct {
+	ID             int64  `xorm:"pk autoincr 'id'"`
+	OrgID          int64  `xorm:"org_id"`
+	UID            string `xorm:"uid"`
+	Name           string
+	Parameters     string
+	RuleDefinition string `xorm:"rule_definition"`
+	Version        int64
+	Updated        time.Time
+}
+
+func (t alertRuleTemplate) TableName() string {
+	return "alert_rule_template"
+}
+
+// alertRuleTemplateInstance represents a record in alert_rule_template_instance table
+type alertRuleTemplateInstance struct {
+	ID              int64  `xorm:"pk autoincr 'id'"`
+	OrgID           int64  `xorm:"org_id"`
+	UID             string `xorm:"uid"`
+	TemplateUID     string `xorm:"template_uid"`
+	NamespaceUID    string `xorm:"namespace_uid"`
+	RuleGroup       string `xorm:"rule_group"`
+	IntervalSeconds int64  `xorm:"interval_seconds"`
+	ParameterValues string `xorm:"parameter_values"`
+	Version         int64
+	Updated         time.Time
+}
+
+func (i alertRuleTemplateInstance) TableName() string {
+	return "alert_rule_template_instance"
+}
+
+type alertRuleTemplateParameter struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
+type alertRuleTemplateRecord struct {
+	Metric              string `json:"metric"`
+	From                string `json:"from"`
+	TargetDatasourceUID string `json:"target_datasource_uid,omitempty"`
+}
+
+type alertRuleTemplateDefinition struct {
+	Title                       string                        `json:"title"`
+	Condition                   string                        `json:"condition,omitempty"`
+	Data                        []models.AlertQuery           `json:"data"`
+	NoDataState                 string                        `json:"no_data_state,omitempty"`
+	ExecErrState                string                        `json:"exec_err_state,omitempty"`
+	For                         time.Duration                 `json:"for,omitempty"`
+	KeepFiringFor               time.Duration                 `json:"keep_firing_for,omitempty"`
+	Annotations                 map[string]string             `json:"annotations,omitempty"`
+	Labels                      map[string]string             `json:"labels,omitempty"`
+	IsPaused                    bool                          `json:"is_paused,omitempty"`
+	NotificationSettings        []models.NotificationSettings `json:"notification_settings,omitempty"`
+	Record                      *alertRuleTemplateRecord      `json:"record,omitempty"`
+	MissingSeriesEvalsToResolve *int                          `json:"missing_series_evals_to_resolve,omitempty"`
+}
+
+// ListAlertRuleTemplates returns the alert rule templates of an organization.
+func (st DBstore) ListAlertRuleTemplates(ctx context.Context, orgID int64) ([]*models.AlertRuleTemplate, error) {
+	var result []*models.AlertRuleTemplate
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var rows []alertRuleTemplate
+		if err := sess.Where("org_id = ?", orgID).Asc("name").Find(&rows); err != nil {
+			return err
+		}
+		result = make([]*models.AlertRuleTemplate, 0, len(rows))
+		for _, row := range rows {
+			t, err := alertRuleTemplateToModel(row)
+			if err != nil {
+				st.Logger.Error("Invalid alert rule template found in DB store, ignoring it", "org_id", row.OrgID, "uid", row.UID, "error", err)
+				continue
+			}
+			result = append(result, t)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// GetAlertRuleTemplate returns the alert rule template with the given UID, or models.ErrAlertRuleTemplateNotFound.
+func (st DBstore) GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (*models.AlertRuleTemplate, error) {
+	var result *models.AlertRuleTemplate
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var row alertRuleTemplate
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&row)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return models.ErrAlertRuleTemplateNotFound
+		}
+		result, err = alertRuleTemplateToModel(row)
+		return err
+	})
+	return result, err
+}
+
+// InsertAlertRuleTemplate inserts a new alert rule template and returns it. A UID is generated if it is empty.
+// It returns models.ErrAlertRuleTemplateExists if a template with the same name or UID already exists.
+func (st DBstore) InsertAlertRuleTemplate(ctx context.Context, t models.AlertRuleTemplate) (*models.AlertRuleTemplate, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	if t.UID == "" {
+		t.UID = util.GenerateShortUID()
+	}
+	t.Version = 1
+	t.Updated = TimeNow().UTC()
+	row, err := alertRuleTemplateFromModel(t)
+	if err != nil {
+		return nil, err
+	}
+	err = st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Table(alertRuleTemplate{}).Where("org_id = ? AND (uid = ? OR name = ?)", t.OrgID, t.UID, t.Name).Exist()
+		if err != nil {
+			return err
+		}
+		if exists {
+			return models.ErrAlertRuleTemplateExists
+		}
+		if _, err := sess.Insert(&row); err != nil {
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return models.ErrAlertRuleTemplateExists
+			}
+			return fmt.Errorf("failed to insert alert rule template: %w", err)
+		}
+		t.ID = row.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// UpdateAlertRuleTemplate updates the alert rule template with the UID of t, if its current version is the version of t.
+// It returns the updated template with an incremented version, models.ErrAlertRuleTemplateNotFound, or
+// ErrOptimisticLock if the template was updated concurrently. The rules of the instances are not updated.
+func (st DBstore) UpdateAlertRuleTemplate(ctx context.Context, t models.AlertRuleTemplate) (*models.AlertRuleTemplate, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	t.Updated = TimeNow().UTC()
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing alertRuleTemplate
+		found, err := sess.Where("org_id = ? AND uid = ?", t.OrgID, t.UID).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return models.ErrAlertRuleTemplateNotFound
+		}
+		if existing.Version != t.Version {
+			return ErrOptimisticLock
+		}
+		t.ID = existing.ID
+		t.Version = existing.Version + 1
+		row, err := alertRuleTemplateFromModel(t)
+		if err != nil {
+			return err
+		}
+		updated, err := sess.ID(existing.ID).Where("version = ?", existing.Version).AllCols().Update(&row)
+		if err != nil {
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return models.ErrAlertRuleTemplateExists
+			}
+			return fmt.Errorf("failed to update alert rule template: %w", err)
+		}
+		if updated == 0 {
+			return ErrOptimisticLock
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// DeleteAlertRuleTemplate deletes the alert rule template with the given UID. It does nothing if it does not exist,
+// and returns models.ErrAlertRuleTemplateInUse if the template has instances.
+func (st DBstore) DeleteAlertRuleTemplate(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		inUse, err := sess.Table(alertRuleTemplateInstance{}).Where("org_id = ? AND template_uid = ?", orgID, uid).Exist()
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return models.ErrAlertRuleTemplateInUse
+		}
+		_, err = sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&alertRuleTemplate{})
+		return err
+	})
+}
+
+// ListAlertRuleTemplateInstances returns the instances of an alert rule template.
+func (st DBstore) ListAlertRuleTemplateInstances(ctx context.Context, orgID int64, templateUID string) ([]*models.AlertRuleTemplateInstance, error) {
+	var result []*models.AlertRuleTemplateInstance
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var rows []alertRuleTemplateInstance
+		if err := sess.Where("org_id = ? AND template_uid = ?", orgID, templateUID).Asc("id").Find(&rows); err != nil {
+			return err
+		}
+		result = make([]*models.AlertRuleTemplateInstance, 0, len(rows))
+		for _, row := range rows {
+			instance, err := alertRuleTemplateInstanceToModel(row)
+			if err != nil {
+				return fmt.Errorf("invalid alert rule template instance %s: %w", row.UID, err)
+			}
+			result = append(result, instance)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// GetAlertRuleTemplateInstance returns the instance with the given UID, or models.ErrAlertRuleTemplateInstanceNotFound.
+func (st DBstore) GetAlertRuleTemplateInstance(ctx context.Context, orgID int64, uid string) (*models.AlertRuleTemplateInstance, error) {
+	var result *models.AlertRuleTemplateInstance
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var row alertRuleTemplateInstance
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&row)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return models.ErrAlertRuleTemplateInstanceNotFound
+		}
+		result, err = alertRuleTemplateInstanceToModel(row)
+		return err
+	})
+	return result, err
+}
+
+// InsertAlertRuleTemplateInstance inserts a new instance of an alert rule template and returns it. A UID is generated
+// if it is empty. It returns models.ErrAlertRuleTemplateInstanceExists if an instance with the same UID already exists.
+// The rule of the instance is not inserted.
+func (st DBstore) InsertAlertRuleTemplateInstance(ctx context.Context, instance models.AlertRuleTemplateInstance) (*models.AlertRuleTemplateInstance, error) {
+	if instance.UID == "" {
+		instance.UID = util.GenerateShortUID()
+	}
+	instance.Version = 1
+	instance.Updated = TimeNow().UTC()
+	row, err := alertRuleTemplateInstanceFromModel(instance)
+	if err != nil {
+		return nil, err
+	}
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(&row); err != nil {
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return models.ErrAlertRuleTemplateInstanceExists
+			}
+			return fmt.Errorf("failed to insert alert rule template instance: %w", err)
+		}
+		instance.ID = row.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &instance, nil
+}
+
+// UpdateAlertRuleTemplateInstance updates the instance with the UID of instance, if its current version is the version
+// of instance. The template of an instance cannot be changed. It returns the updated instance with an incremented
+// version, models.ErrAlertRuleTemplateInstanceNotFound, or ErrOptimisticLock if the instance was updated concurrently.
+// The rule of the instance is not updated.
+func (st DBstore) UpdateAlertRuleTemplateInstance(ctx context.Context, instance models.AlertRuleTemplateInstance) (*models.AlertRuleTemplateInstance, error) {
+	instance.Updated = TimeNow().UTC()
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing alertRuleTemplateInstance
+		found, err := sess.Where("org_id = ? AND uid = ?", instance.OrgID, instance.UID).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return models.ErrAlertRuleTemplateInstanceNotFound
+		}
+		if existing.Version != instance.Version {
+			return ErrOptimisticLock
+		}
+		instance.ID = existing.ID
+		instance.TemplateUID = existing.TemplateUID
+		instance.Version = existing.Version + 1
+		row, err := alertRuleTemplateInstanceFromModel(instance)
+		if err != nil {
+			return err
+		}
+		updated, err := sess.ID(existing.ID).Where("version = ?", existing.Version).AllCols().Update(&row)
+		if err != nil {
+			return fmt.Errorf("failed to update alert rule template instance: %w", err)
+		}
+		if updated == 0 {
+			return ErrOptimisticLock
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &instance, nil
+}
+
+// DeleteAlertRuleTemplateInstance deletes the instance with the given UID. It does nothing if it does not exist.
+// The rule of the instance is not deleted.
+func (st DBstore) DeleteAlertRuleTemplateInstance(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&alertRuleTemplateInstance{})
+		return err
+	})
+}
+
+func alertRuleTemplateFromModel(t models.AlertRuleTemplate) (alertRuleTemplate, error) {
+	parameters := make([]alertRuleTemplateParameter, 0, len(t.Parameters))
+	for _, p := range t.Parameters {
+		parameters = append(parameters, alertRuleTemplateParameter{Name: p.Name, Type: string(p.Type), Description: p.Description})
+	}
+	p, err := json.Marshal(parameters)
+	if err != nil {
+		return alertRuleTemplate{}, fmt.Errorf("failed to marshal parameters: %w", err)
+	}
+	def := alertRuleTemplateDefinition{
+		Title:                       t.Rule.Title,
+		Condition:                   t.Rule.Condition,
+		Data:                        t.Rule.Data,
+		NoDataState:                 string(t.Rule.NoDataState),
+		ExecErrState:                string(t.Rule.ExecErrState),
+		For:                         t.Rule.For,
+		KeepFiringFor:               t.Rule.KeepFiringFor,
+		Annotations:                 t.Rule.Annotations,
+		Labels:                      t.Rule.Labels,
+		IsPaused:                    t.Rule.IsPaused,
+		NotificationSettings:        t.Rule.NotificationSettings,
+		MissingSeriesEvalsToResolve: t.Rule.MissingSeriesEvalsToResolve,
+	}
+	if t.Rule.Record != nil {
+		def.Record = &alertRuleTemplateRecord{
+			Metric:              t.Rule.Record.Metric,
+			From:                t.Rule.Record.From,
+			TargetDatasourceUID: t.Rule.Record.TargetDatasourceUID,
+		}
+	}
+	d, err := json.Marshal(def)
+	if err != nil {
+		return alertRuleTemplate{}, fmt.Errorf("failed to marshal rule definition: %w", err)
+	}
+	return alertRuleTemplate{
+		ID:             t.ID,
+		OrgID:          t.OrgID,
+		UID:            t.UID,
+		Name:           t.Name,
+		Parameters:     string(p),
+		RuleDefinition: string(d),
+		Version:        t.Version,
+		Updated:        t.Updated,
+	}, nil
+}
+
+func alertRuleTemplateToModel(row alertRuleTemplate) (*models.AlertRuleTemplate, error) {
+	var parameters []alertRuleTemplateParameter
+	if err := json.Unmarshal([]byte(row.Parameters), &parameters); err != nil {
+		return nil, fmt.Errorf("failed to parse parameters: %w", err)
+	}
+	var def alertRuleTemplateDefinition
+	if err := json.Unmarshal([]byte(row.RuleDefinition), &def); err != nil {
+		return nil, fmt.Errorf("failed to parse rule definition: %w", err)
+	}
+	t := &models.AlertRuleTemplate{
+		ID:         row.ID,
+		UID:        row.UID,
+		OrgID:      row.OrgID,
+		Name:       row.Name,
+		Parameters: make([]models.AlertRuleTemplateParameter, 0, len(parameters)),
+		Rule: models.AlertRuleTemplateRule{
+			Title:                       def.Title,
+			Condition:                   def.Condition,
+			Data:                        def.Data,
+			NoDataState:                 models.NoDataState(def.NoDataState),
+			ExecErrState:                models.ExecutionErrorState(def.ExecErrState),
+			For:                         def.For,
+			KeepFiringFor:               def.KeepFiringFor,
+			Annotations:                 def.Annotations,
+			Labels:                      def.Labels,
+			IsPaused:                    def.IsPaused,
+			NotificationSettings:        def.NotificationSettings,
+			MissingSeriesEvalsToResolve: def.MissingSeriesEvalsToResolve,
+		},
+		Version: row.Version,
+		Updated: row.Updated,
+	}
+	for _, p := range parameters {
+		t.Parameters = append(t.Parameters, models.AlertRuleTemplateParameter{
+			Name:        p.Name,
+			Type:        models.AlertRuleTemplateParameterType(p.Type),
+			Description: p.Description,
+		})
+	}
+	if def.Record != nil {
+		t.Rule.Record = &models.Record{
+			Metric:              def.Record.Metric,
+			From:                def.Record.From,
+			TargetDatasourceUID: def.Record.TargetDatasourceUID,
+		}
+	}
+	return t, nil
+}
+
+func alertRuleTemplateInstanceFromModel(instance models.AlertRuleTemplateInstance) (alertRuleTemplateInstance, error) {
+	values, err := json.Marshal(instance.Values)
+	if err != nil {
+		return alertRuleTemplateInstance{}, fmt.Errorf("failed to marshal parameter values: %w", err)
+	}
+	return alertRuleTemplateInstance{
+		ID:              instance.ID,
+		OrgID:           instance.OrgID,
+		UID:             instance.UID,
+		TemplateUID:     instance.TemplateUID,
+		NamespaceUID:    instance.NamespaceUID,
+		RuleGroup:       instance.RuleGroup,
+		IntervalSeconds: instance.IntervalSeconds,
+		ParameterValues: string(values),
+		Version:         instance.Version,
+		Updated:         instance.Updated,
+	}, nil
+}
+
+func alertRuleTemplateInstanceToModel(row alertRuleTemplateInstance) (*models.AlertRuleTemplateInstance, error) {
+	var values map[string]string
+	if err := json.Unmarshal([]byte(row.ParameterValues), &values); err != nil {
+		return nil, fmt.Errorf("failed to parse parameter values: %w", err)
+	}
+	return &models.AlertRuleTemplateInstance{
+		ID:              row.ID,
+		UID:             row.UID,
+		OrgID:           row.OrgID,
+		TemplateUID:     row.TemplateUID,
+		NamespaceUID:    row.NamespaceUID,
+		RuleGroup:       row.RuleGroup,
+		IntervalSeconds: row.IntervalSeconds,
+		Values:          values,
+		Version:         row.Version,
+		Updated:         row.Updated,
+	}, nil
+}
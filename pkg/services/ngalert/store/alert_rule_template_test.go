@@ -0,0 +1,123 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationAlertRuleTemplates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting = setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second, DefaultRuleEvaluationInterval: time.Minute}
+	sqlStore := db.InitTestDB(t)
+	folderService := setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures())
+	store := createTestStore(sqlStore, folderService, &logtest.Fake{}, cfg.UnifiedAlerting, &fakeBus{})
+	ctx := context.Background()
+	const orgID = 1
+
+	template := models.AlertRuleTemplate{
+		OrgID: orgID,
+		Name:  "High CPU",
+		Parameters: []models.AlertRuleTemplateParameter{
+			{Name: "datasource", Type: models.AlertRuleTemplateParameterString},
+			{Name: "threshold", Type: models.AlertRuleTemplateParameterNumber},
+		},
+		Rule: models.AlertRuleTemplateRule{
+			Title: "High CPU on ${datasource}",
+			Data: []models.AlertQuery{
+				models.CreatePrometheusQuery("A", "avg(cpu_usage)", 1000, 43200, true, "${datasource}"),
+				models.CreateClassicConditionExpression("B", "A", "last", "gt", 80),
+			},
+			Condition:    "B",
+			NoDataState:  models.NoData,
+			ExecErrState: models.ErrorErrState,
+			Labels:       map[string]string{"datasource": "${datasource}"},
+		},
+	}
+
+	created, err := store.InsertAlertRuleTemplate(ctx, template)
+	require.NoError(t, err)
+	require.NotEmpty(t, created.UID)
+	require.EqualValues(t, 1, created.Version)
+
+	_, err = store.InsertAlertRuleTemplate(ctx, template)
+	require.ErrorIs(t, err, models.ErrAlertRuleTemplateExists)
+
+	instances := make([]*models.AlertRuleTemplateInstance, 0, 2)
+	for _, ds := range []string{"prom-a", "prom-b"} {
+		instance, err := store.InsertAlertRuleTemplateInstance(ctx, models.AlertRuleTemplateInstance{
+			OrgID:           orgID,
+			TemplateUID:     created.UID,
+			NamespaceUID:    "folder",
+			RuleGroup:       "group",
+			IntervalSeconds: 60,
+			Values:          map[string]string{"datasource": ds, "threshold": "90"},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, instance.UID)
+		require.EqualValues(t, 1, instance.Version)
+		instances = append(instances, instance)
+	}
+
+	_, err = store.InsertAlertRuleTemplateInstance(ctx, *instances[0])
+	require.ErrorIs(t, err, models.ErrAlertRuleTemplateInstanceExists)
+
+	listed, err := store.ListAlertRuleTemplateInstances(ctx, orgID, created.UID)
+	require.NoError(t, err)
+	require.Len(t, listed, 2)
+	require.Equal(t, map[string]string{"datasource": "prom-b", "threshold": "90"}, listed[1].Values)
+
+	t.Run("updating the template should increment its version", func(t *testing.T) {
+		update := *created
+		update.Rule.Title = "CPU above ${threshold}% on ${datasource}"
+		updated, err := store.UpdateAlertRuleTemplate(ctx, update)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, updated.Version)
+
+		stored, err := store.GetAlertRuleTemplate(ctx, orgID, created.UID)
+		require.NoError(t, err)
+		require.Equal(t, "CPU above ${threshold}% on ${datasource}", stored.Rule.Title)
+
+		_, err = store.UpdateAlertRuleTemplate(ctx, update)
+		require.ErrorIs(t, err, ErrOptimisticLock)
+	})
+
+	t.Run("updating an instance should keep its template", func(t *testing.T) {
+		update := *instances[0]
+		update.TemplateUID = "other"
+		update.Values = map[string]string{"datasource": "prom-c", "threshold": "95"}
+		updated, err := store.UpdateAlertRuleTemplateInstance(ctx, update)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, updated.Version)
+		require.Equal(t, created.UID, updated.TemplateUID)
+
+		_, err = store.UpdateAlertRuleTemplateInstance(ctx, update)
+		require.ErrorIs(t, err, ErrOptimisticLock)
+	})
+
+	t.Run("should not delete a template with instances", func(t *testing.T) {
+		require.ErrorIs(t, store.DeleteAlertRuleTemplate(ctx, orgID, created.UID), models.ErrAlertRuleTemplateInUse)
+	})
+
+	t.Run("should delete the template once its instances are deleted", func(t *testing.T) {
+		for _, instance := range instances {
+			require.NoError(t, store.DeleteAlertRuleTemplateInstance(ctx, orgID, instance.UID))
+			_, err := store.GetAlertRuleTemplateInstance(ctx, orgID, instance.UID)
+			require.ErrorIs(t, err, models.ErrAlertRuleTemplateInstanceNotFound)
+		}
+		require.NoError(t, store.DeleteAlertRuleTemplate(ctx, orgID, created.UID))
+		_, err := store.GetAlertRuleTemplate(ctx, orgID, created.UID)
+		require.ErrorIs(t, err, models.ErrAlertRuleTemplateNotFound)
+	})
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -158,13 +159,155 @@ func calculateChanges(ctx context.Context, ruleReader RuleReader, groupKey model
 		toDelete = append(toDelete, rule)
 	}
 
-	return &GroupDelta{
+	delta := &GroupDelta{
 		GroupKey:       groupKey,
 		AffectedGroups: affectedGroups,
 		New:            toAdd,
 		Delete:         toDelete,
 		Update:         toUpdate,
-	}, nil
+	}
+	if err := validateDependencies(ctx, ruleReader, delta); err != nil {
+		return nil, err
+	}
+	return delta, nil
+}
+
+// ValidateRulesDelete checks that none of the remaining alert rules of the organization depend on the rules
+// with the given UIDs, for the operations that delete rules without calculating a GroupDelta.
+func ValidateRulesDelete(ctx context.Context, ruleReader RuleReader, orgID int64, uids ...string) error {
+	if len(uids) == 0 {
+		return nil
+	}
+	toDelete := make([]*models.AlertRule, 0, len(uids))
+	for _, uid := range uids {
+		toDelete = append(toDelete, &models.AlertRule{OrgID: orgID, UID: uid})
+	}
+	return validateDependencies(ctx, ruleReader, &GroupDelta{
+		GroupKey: models.AlertRuleGroupKey{OrgID: orgID},
+		Delete:   toDelete,
+	})
+}
+
+// validateDependencies checks that the new or updated dependencies of the rules in the delta refer to existing alert rules
+// of the same organization, and that they do not create a cycle. It also checks that the rules that other rules depend on
+// are not deleted without the rules that depend on them. Rules can depend on rules of other groups, so they can be moved freely.
+func validateDependencies(ctx context.Context, ruleReader RuleReader, delta *GroupDelta) error {
+	changed := make([]*models.AlertRule, 0)
+	for _, rule := range delta.New {
+		if len(rule.DependsOn) > 0 {
+			changed = append(changed, rule)
+		}
+	}
+	for _, upd := range delta.Update {
+		if len(upd.New.DependsOn) > 0 && len(upd.Diff.GetDiffsForField("DependsOn")) > 0 {
+			changed = append(changed, upd.New)
+		}
+	}
+	deleted := make(map[string]struct{}, len(delta.Delete))
+	for _, rule := range delta.Delete {
+		deleted[rule.UID] = struct{}{}
+	}
+	if len(changed) == 0 && len(deleted) == 0 {
+		return nil
+	}
+
+	rules, err := ruleReader.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: delta.GroupKey.OrgID})
+	if err != nil {
+		return fmt.Errorf("failed to query database for rules of the organization: %w", err)
+	}
+	// dependencies contains the dependencies of all rules of the organization after the changes are applied.
+	// titles also contains the titles of the deleted rules.
+	dependencies := make(map[string][]string, len(rules)+len(delta.New))
+	titles := make(map[string]string, len(rules)+len(delta.New))
+	set := func(rule *models.AlertRule) {
+		dependencies[rule.UID] = rule.DependsOn
+		titles[rule.UID] = rule.Title
+	}
+	for _, rule := range rules {
+		set(rule)
+	}
+	for _, rule := range delta.Delete {
+		delete(dependencies, rule.UID)
+	}
+	for _, upd := range delta.Update {
+		set(upd.New)
+	}
+	for _, rule := range delta.New {
+		if rule.UID != "" {
+			set(rule)
+		}
+	}
+
+	uids := make([]string, 0, len(dependencies))
+	for uid := range dependencies {
+		uids = append(uids, uid)
+	}
+	slices.Sort(uids)
+	for _, uid := range uids {
+		for _, up := range dependencies[uid] {
+			if _, ok := deleted[up]; ok {
+				return fmt.Errorf("%w: rule '%s' cannot be deleted because rule '%s' depends on it", models.ErrAlertRuleFailedValidation, titles[up], titles[uid])
+			}
+		}
+	}
+
+	for _, rule := range changed {
+		for _, uid := range rule.DependsOn {
+			if _, ok := dependencies[uid]; !ok {
+				return fmt.Errorf("%w: rule '%s' depends on rule %s that does not exist", models.ErrAlertRuleFailedValidation, rule.Title, uid)
+			}
+		}
+	}
+
+	if cycle := findDependencyCycle(dependencies); len(cycle) > 0 {
+		return fmt.Errorf("%w: rule dependencies form a cycle: %s", models.ErrAlertRuleFailedValidation, strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// findDependencyCycle returns the UIDs of the rules that form a cycle in the dependency graph, starting and ending
+// with the same rule, or nil if there is no cycle.
+func findDependencyCycle(dependencies map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	status := make(map[string]int, len(dependencies))
+	var path []string
+	var visit func(uid string) []string
+	visit = func(uid string) []string {
+		switch status[uid] {
+		case visited:
+			return nil
+		case visiting:
+			idx := slices.Index(path, uid)
+			return append(slices.Clone(path[idx:]), uid)
+		}
+		status[uid] = visiting
+		path = append(path, uid)
+		for _, dep := range dependencies[uid] {
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		status[uid] = visited
+		return nil
+	}
+
+	// visit the rules in a stable order, so the same cycle is reported every time.
+	uids := make([]string, 0, len(dependencies))
+	for uid := range dependencies {
+		uids = append(uids, uid)
+	}
+	slices.Sort(uids)
+	for _, uid := range uids {
+		if cycle := visit(uid); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
 }
 
 // UpdateCalculatedRuleFields refreshes the calculated fields in a set of alert rule changes.
@@ -269,6 +412,14 @@ func CalculateRuleGroupsDelete(ctx context.Context, ruleReader RuleReader, orgID
 		deltas = append(deltas, delta)
 	}
 
+	uids := make([]string, 0, len(ruleList))
+	for _, rule := range ruleList {
+		uids = append(uids, rule.UID)
+	}
+	if err := ValidateRulesDelete(ctx, ruleReader, orgID, uids...); err != nil {
+		return nil, err
+	}
+
 	return deltas, nil
 }
 
@@ -317,6 +468,9 @@ func CalculateRuleDelete(ctx context.Context, ruleReader RuleReader, ruleKey mod
 			groupKey: group,
 		},
 	}
+	if err := validateDependencies(ctx, ruleReader, delta); err != nil {
+		return nil, err
+	}
 	return delta, nil
 }
 
@@ -343,5 +497,8 @@ func CalculateRuleCreate(ctx context.Context, ruleReader RuleReader, rule *model
 	if len(group) > 0 {
 		delta.AffectedGroups[rule.GetGroupKey()] = group
 	}
+	if err := validateDependencies(ctx, ruleReader, delta); err != nil {
+		return nil, err
+	}
 	return delta, nil
 }
@@ -300,6 +300,72 @@ func TestCalculateChanges(t *testing.T) {
 	})
 }
 
+func TestCalculateChangesDependencies(t *testing.T) {
+	orgId := int64(rand.Int32())
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(orgId))
+
+	upstream := gen.GenerateRef()
+	downstream := gen.With(gen.WithDependsOn(upstream.UID)).GenerateRef()
+	fakeStore := fakes.NewRuleStore(t)
+	fakeStore.PutRule(context.Background(), upstream, downstream)
+
+	t.Run("accepts dependencies on existing rules", func(t *testing.T) {
+		submitted := gen.With(simulateSubmitted, gen.WithDependsOn(upstream.UID, downstream.UID)).Generate()
+		delta, err := CalculateChanges(context.Background(), fakeStore, models.GenerateGroupKey(orgId), []*models.AlertRuleWithOptionals{{AlertRule: submitted}})
+		require.NoError(t, err)
+		require.Len(t, delta.New, 1)
+	})
+
+	t.Run("rejects dependencies on rules that do not exist", func(t *testing.T) {
+		submitted := gen.With(simulateSubmitted, gen.WithDependsOn("unknown")).Generate()
+		_, err := CalculateChanges(context.Background(), fakeStore, models.GenerateGroupKey(orgId), []*models.AlertRuleWithOptionals{{AlertRule: submitted}})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("rejects dependencies that form a cycle", func(t *testing.T) {
+		submitted := models.CopyRule(upstream)
+		submitted.DependsOn = []string{downstream.UID}
+		_, err := CalculateChanges(context.Background(), fakeStore, upstream.GetGroupKey(), []*models.AlertRuleWithOptionals{{AlertRule: *submitted}})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "rule dependencies form a cycle")
+	})
+
+	t.Run("accepts rules without changes to dependencies", func(t *testing.T) {
+		submitted := models.CopyRule(downstream)
+		submitted.Title = "updated"
+		delta, err := CalculateChanges(context.Background(), fakeStore, downstream.GetGroupKey(), []*models.AlertRuleWithOptionals{{AlertRule: *submitted}})
+		require.NoError(t, err)
+		require.Len(t, delta.Update, 1)
+	})
+
+	t.Run("rejects deleting a rule other rules depend on", func(t *testing.T) {
+		_, err := CalculateRuleDelete(context.Background(), fakeStore, upstream.GetKey())
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "cannot be deleted")
+
+		_, err = CalculateChanges(context.Background(), fakeStore, upstream.GetGroupKey(), nil)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("accepts deleting a rule together with the rules that depend on it", func(t *testing.T) {
+		require.NoError(t, ValidateRulesDelete(context.Background(), fakeStore, orgId, upstream.UID, downstream.UID))
+	})
+
+	t.Run("accepts moving a rule other rules depend on to another group", func(t *testing.T) {
+		moved := models.CopyRule(upstream)
+		moved.RuleGroup = "moved-" + moved.RuleGroup
+		delta, err := CalculateChanges(context.Background(), fakeStore, moved.GetGroupKey(), []*models.AlertRuleWithOptionals{{AlertRule: *moved}})
+		require.NoError(t, err)
+		require.Len(t, delta.Update, 1)
+	})
+}
+
+func TestFindDependencyCycle(t *testing.T) {
+	require.Nil(t, findDependencyCycle(map[string][]string{"a": {"b", "c"}, "b": {"c"}, "c": nil, "d": {"missing"}}))
+	require.Equal(t, []string{"a", "b", "c", "a"}, findDependencyCycle(map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a"}}))
+	require.Equal(t, []string{"b", "b"}, findDependencyCycle(map[string][]string{"a": {"b"}, "b": {"b"}}))
+}
+
 func TestCalculateAutomaticChanges(t *testing.T) {
 	orgID := rand.Int64()
 	gen := models.RuleGen
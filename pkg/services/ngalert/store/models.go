@@ -31,6 +31,7 @@ type alertRule struct {
 	NotificationSettings        string `xorm:"notification_settings"`
 	Metadata                    string `xorm:"metadata"`
 	MissingSeriesEvalsToResolve *int   `xorm:"missing_series_evals_to_resolve"`
+	DependsOn                   string `xorm:"depends_on"`
 }
 
 func (a alertRule) TableName() string {
@@ -69,6 +70,7 @@ type alertRuleVersion struct {
 	NotificationSettings        string `xorm:"notification_settings"`
 	Metadata                    string `xorm:"metadata"`
 	MissingSeriesEvalsToResolve *int   `xorm:"missing_series_evals_to_resolve"`
+	DependsOn                   string `xorm:"depends_on"`
 }
 
 // EqualSpec compares two alertRuleVersion objects for equality based on their specifications and returns true if they match.
@@ -93,7 +95,8 @@ func (a alertRuleVersion) EqualSpec(b alertRuleVersion) bool {
 		a.IsPaused == b.IsPaused &&
 		a.NotificationSettings == b.NotificationSettings &&
 		a.Metadata == b.Metadata &&
-		a.MissingSeriesEvalsToResolve == b.MissingSeriesEvalsToResolve
+		a.MissingSeriesEvalsToResolve == b.MissingSeriesEvalsToResolve &&
+		a.DependsOn == b.DependsOn
 }
 
 func (a alertRuleVersion) TableName() string {
@@ -0,0 +1,229 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// recurringSilence represents a record in alert_recurring_silence table
+type recurringSilence struct {
+	ID            int64  `xorm:"pk autoincr 'id'"`
+	OrgID         int64  `xorm:"org_id"`
+	UID           string `xorm:"uid"`
+	Name          string
+	Matchers      string
+	Cron          string
+	Duration      int64
+	TimeIntervals string `xorm:"time_intervals"`
+	Comment       string
+	CreatedBy     string `xorm:"created_by"`
+	Version       int64
+	Updated       time.Time
+}
+
+func (s recurringSilence) TableName() string {
+	return "alert_recurring_silence"
+}
+
+// ListRecurringSilences returns the recurring silences of an organization, or of all organizations if the OrgID of the query is 0.
+func (st DBstore) ListRecurringSilences(ctx context.Context, query *models.ListRecurringSilencesQuery) ([]*models.RecurringSilence, error) {
+	var result []*models.RecurringSilence
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table(recurringSilence{})
+		if query.OrgID > 0 {
+			q = q.Where("org_id = ?", query.OrgID)
+		}
+		var rows []recurringSilence
+		if err := q.Asc("org_id", "name").Find(&rows); err != nil {
+			return err
+		}
+		result = make([]*models.RecurringSilence, 0, len(rows))
+		for _, row := range rows {
+			s, err := recurringSilenceToModel(row)
+			if err != nil {
+				st.Logger.Error("Invalid recurring silence found in DB store, ignoring it", "org_id", row.OrgID, "uid", row.UID, "error", err)
+				continue
+			}
+			result = append(result, s)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// GetRecurringSilence returns the recurring silence with the given UID, or models.ErrRecurringSilenceNotFound.
+func (st DBstore) GetRecurringSilence(ctx context.Context, orgID int64, uid string) (*models.RecurringSilence, error) {
+	var result *models.RecurringSilence
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var row recurringSilence
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&row)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return models.ErrRecurringSilenceNotFound
+		}
+		result, err = recurringSilenceToModel(row)
+		return err
+	})
+	return result, err
+}
+
+// InsertRecurringSilence inserts a new recurring silence and returns it. A UID is generated if it is empty.
+// It returns models.ErrRecurringSilenceExists if a recurring silence with the same name or UID already exists.
+func (st DBstore) InsertRecurringSilence(ctx context.Context, s models.RecurringSilence) (*models.RecurringSilence, error) {
+	if s.UID == "" {
+		s.UID = util.GenerateShortUID()
+	}
+	s.Version = 1
+	s.Updated = TimeNow().UTC()
+	row, err := recurringSilenceFromModel(s)
+	if err != nil {
+		return nil, err
+	}
+	err = st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Table(recurringSilence{}).Where("org_id = ? AND (uid = ? OR name = ?)", s.OrgID, s.UID, s.Name).Exist()
+		if err != nil {
+			return err
+		}
+		if exists {
+			return models.ErrRecurringSilenceExists
+		}
+		if _, err := sess.Insert(&row); err != nil {
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return models.ErrRecurringSilenceExists
+			}
+			return fmt.Errorf("failed to insert recurring silence: %w", err)
+		}
+		s.ID = row.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// UpdateRecurringSilence updates the recurring silence with the UID of s, if its current version is the version of s.
+// It returns the updated recurring silence with an incremented version, models.ErrRecurringSilenceNotFound, or
+// ErrOptimisticLock if the recurring silence was updated concurrently.
+func (st DBstore) UpdateRecurringSilence(ctx context.Context, s models.RecurringSilence) (*models.RecurringSilence, error) {
+	s.Updated = TimeNow().UTC()
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing recurringSilence
+		found, err := sess.Where("org_id = ? AND uid = ?", s.OrgID, s.UID).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return models.ErrRecurringSilenceNotFound
+		}
+		if existing.Version != s.Version {
+			return ErrOptimisticLock
+		}
+		s.ID = existing.ID
+		s.Version = existing.Version + 1
+		row, err := recurringSilenceFromModel(s)
+		if err != nil {
+			return err
+		}
+		updated, err := sess.ID(existing.ID).Where("version = ?", existing.Version).AllCols().Update(&row)
+		if err != nil {
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return models.ErrRecurringSilenceExists
+			}
+			return fmt.Errorf("failed to update recurring silence: %w", err)
+		}
+		if updated == 0 {
+			return ErrOptimisticLock
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// DeleteRecurringSilence deletes the recurring silence with the given UID. It does nothing if it does not exist.
+func (st DBstore) DeleteRecurringSilence(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&recurringSilence{})
+		return err
+	})
+}
+
+func recurringSilenceFromModel(s models.RecurringSilence) (recurringSilence, error) {
+	matchers := make([]string, 0, len(s.Matchers))
+	for _, m := range s.Matchers {
+		matchers = append(matchers, m.String())
+	}
+	m, err := json.Marshal(matchers)
+	if err != nil {
+		return recurringSilence{}, fmt.Errorf("failed to marshal matchers: %w", err)
+	}
+	var intervals []byte
+	if len(s.TimeIntervals) > 0 {
+		if intervals, err = json.Marshal(s.TimeIntervals); err != nil {
+			return recurringSilence{}, fmt.Errorf("failed to marshal time intervals: %w", err)
+		}
+	}
+	return recurringSilence{
+		ID:            s.ID,
+		OrgID:         s.OrgID,
+		UID:           s.UID,
+		Name:          s.Name,
+		Matchers:      string(m),
+		Cron:          s.Cron,
+		Duration:      int64(s.Duration),
+		TimeIntervals: string(intervals),
+		Comment:       s.Comment,
+		CreatedBy:     s.CreatedBy,
+		Version:       s.Version,
+		Updated:       s.Updated,
+	}, nil
+}
+
+func recurringSilenceToModel(row recurringSilence) (*models.RecurringSilence, error) {
+	var matchers []string
+	if err := json.Unmarshal([]byte(row.Matchers), &matchers); err != nil {
+		return nil, fmt.Errorf("failed to parse matchers: %w", err)
+	}
+	s := &models.RecurringSilence{
+		ID:        row.ID,
+		UID:       row.UID,
+		OrgID:     row.OrgID,
+		Name:      row.Name,
+		Matchers:  make(labels.Matchers, 0, len(matchers)),
+		Cron:      row.Cron,
+		Duration:  time.Duration(row.Duration),
+		Comment:   row.Comment,
+		CreatedBy: row.CreatedBy,
+		Version:   row.Version,
+		Updated:   row.Updated,
+	}
+	for _, matcher := range matchers {
+		m, err := labels.ParseMatcher(matcher)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse matcher %q: %w", matcher, err)
+		}
+		s.Matchers = append(s.Matchers, m)
+	}
+	if row.TimeIntervals != "" {
+		var intervals []timeinterval.TimeInterval
+		if err := json.Unmarshal([]byte(row.TimeIntervals), &intervals); err != nil {
+			return nil, fmt.Errorf("failed to parse time intervals: %w", err)
+		}
+		s.TimeIntervals = intervals
+	}
+	return s, nil
+}
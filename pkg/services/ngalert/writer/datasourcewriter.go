@@ -240,9 +240,29 @@ func uidKey(orgID int64, uid string) string {
 }
 
 func (w *DatasourceWriter) WriteDatasource(ctx context.Context, dsUID string, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	writer, err := w.writer(ctx, orgID, dsUID)
+	if err != nil {
+		return err
+	}
+
+	return writer.Write(ctx, name, t, frames, orgID, extraLabels)
+}
+
+// WriteDatasourceSamples writes the samples of several evaluations to the data source in a single request.
+func (w *DatasourceWriter) WriteDatasourceSamples(ctx context.Context, dsUID string, name string, samples []Sample, orgID int64, extraLabels map[string]string) error {
+	writer, err := w.writer(ctx, orgID, dsUID)
+	if err != nil {
+		return err
+	}
+
+	return writer.WriteSamples(ctx, name, samples, orgID, extraLabels)
+}
+
+// writer returns the cached writer of the data source, or the default data source if dsUID is empty.
+func (w *DatasourceWriter) writer(ctx context.Context, orgID int64, dsUID string) (*PrometheusWriter, error) {
 	if dsUID == "" {
 		if w.cfg.DefaultDatasourceUID == "" {
-			return errors.New("data source uid not specified and no default set")
+			return nil, errors.New("data source uid not specified and no default set")
 		}
 		dsUID = w.cfg.DefaultDatasourceUID
 		w.l.Debug("Using default data source for remote write",
@@ -258,7 +278,7 @@ func (w *DatasourceWriter) WriteDatasource(ctx context.Context, dsUID string, na
 		var ok bool
 		writer, ok = val.(*PrometheusWriter)
 		if !ok {
-			return errors.New("type in cache not a Writer")
+			return nil, errors.New("type in cache not a Writer")
 		}
 	} else {
 		var err error
@@ -266,11 +286,11 @@ func (w *DatasourceWriter) WriteDatasource(ctx context.Context, dsUID string, na
 		if err != nil {
 			w.l.Error("Failed to create writer for data source",
 				"org_id", orgID, "datasource_uid", dsUID)
-			return err
+			return nil, err
 		}
 
 		w.writers.Set(key, writer, 0)
 	}
 
-	return writer.Write(ctx, name, t, frames, orgID, extraLabels)
+	return writer, nil
 }
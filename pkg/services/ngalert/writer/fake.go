@@ -23,3 +23,12 @@ func (w FakeWriter) WriteDatasource(ctx context.Context, dsUID string, name stri
 
 	return w.WriteFunc(ctx, name, t, frames, orgID, extraLabels)
 }
+
+func (w FakeWriter) WriteDatasourceSamples(ctx context.Context, dsUID string, name string, samples []Sample, orgID int64, extraLabels map[string]string) error {
+	for _, s := range samples {
+		if err := w.WriteDatasource(ctx, dsUID, name, s.T, s.Frames, orgID, extraLabels); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -16,3 +16,7 @@ func (w NoopWriter) Write(ctx context.Context, name string, t time.Time, frames
 func (w NoopWriter) WriteDatasource(ctx context.Context, dsUID string, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
 	return nil
 }
+
+func (w NoopWriter) WriteDatasourceSamples(ctx context.Context, dsUID string, name string, samples []Sample, orgID int64, extraLabels map[string]string) error {
+	return nil
+}
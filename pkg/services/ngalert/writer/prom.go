@@ -176,6 +176,12 @@ type Metric struct {
 	V float64
 }
 
+// Sample is the result of an evaluation of a recording rule at time T.
+type Sample struct {
+	T      time.Time
+	Frames data.Frames
+}
+
 // Point is a logical representation of a single point in time for a Prometheus time series.
 type Point struct {
 	Name   string
@@ -292,28 +298,47 @@ func (w PrometheusWriter) WriteDatasource(ctx context.Context, dsUID string, nam
 	return w.Write(ctx, name, t, frames, orgID, extraLabels)
 }
 
+// WriteDatasourceSamples writes the given samples to the Prometheus remote write endpoint in a single request.
+func (w PrometheusWriter) WriteDatasourceSamples(ctx context.Context, dsUID string, name string, samples []Sample, orgID int64, extraLabels map[string]string) error {
+	l := w.logger.FromContext(ctx)
+
+	if dsUID != "" {
+		l.Error("Writing to specific data sources is not enabled", "org_id", orgID, "datasource_uid", dsUID)
+		return errors.New("writing to specific data sources is not enabled")
+	}
+
+	return w.WriteSamples(ctx, name, samples, orgID, extraLabels)
+}
+
 // Write writes the given frames to the Prometheus remote write endpoint.
 func (w PrometheusWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	return w.WriteSamples(ctx, name, []Sample{{T: t, Frames: frames}}, orgID, extraLabels)
+}
+
+// WriteSamples writes the given samples to the Prometheus remote write endpoint in a single request.
+func (w PrometheusWriter) WriteSamples(ctx context.Context, name string, samples []Sample, orgID int64, extraLabels map[string]string) error {
 	l := w.logger.FromContext(ctx)
 	lvs := []string{fmt.Sprint(orgID), backendType}
 
-	points, err := PointsFromFrames(name, t, frames, extraLabels)
-	if err != nil {
-		return errors.Join(ErrBadFrame, err)
-	}
+	var series []promremote.TimeSeries
+	for _, s := range samples {
+		points, err := PointsFromFrames(name, s.T, s.Frames, extraLabels)
+		if err != nil {
+			return errors.Join(ErrBadFrame, err)
+		}
 
-	series := make([]promremote.TimeSeries, 0, len(points))
-	for _, p := range points {
-		series = append(series, promremote.TimeSeries{
-			Labels: promremoteLabelsFromPoint(p),
-			Datapoint: promremote.Datapoint{
-				Timestamp: p.Metric.T,
-				Value:     p.Metric.V,
-			},
-		})
+		for _, p := range points {
+			series = append(series, promremote.TimeSeries{
+				Labels: promremoteLabelsFromPoint(p),
+				Datapoint: promremote.Datapoint{
+					Timestamp: p.Metric.T,
+					Value:     p.Metric.V,
+				},
+			})
+		}
 	}
 
-	l.Debug("Writing metric", "name", name)
+	l.Debug("Writing metric", "name", name, "samples", len(samples))
 	writeStart := w.clock.Now()
 	res, writeErr := w.client.WriteTimeSeries(ctx, series, promremote.WriteOptions{})
 	w.metrics.WriteDuration.WithLabelValues(lvs...).Observe(w.clock.Now().Sub(writeStart).Seconds())
@@ -151,6 +151,27 @@ func TestPrometheusWriter_Write(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("writes the points of all samples in a single request", func(t *testing.T) {
+		later := now.Add(time.Minute)
+		requests := 0
+		client.writeSeriesFunc = func(ctx context.Context, tslist promremote.TSList, opts promremote.WriteOptions) (promremote.WriteResult, promremote.WriteError) {
+			requests++
+			require.Len(t, tslist, 2*len(series))
+			for i, ts := range tslist {
+				expected := now
+				if i >= len(series) {
+					expected = later
+				}
+				require.Equal(t, expected, ts.Datapoint.Timestamp)
+			}
+			return promremote.WriteResult{}, nil
+		}
+
+		err := writer.WriteSamples(ctx, "test", []Sample{{T: now, Frames: frames}, {T: later, Frames: frames}}, 1, map[string]string{})
+		require.NoError(t, err)
+		require.Equal(t, 1, requests)
+	})
+
 	t.Run("ignores client error when status code is 400 and message contains duplicate timestamp error", func(t *testing.T) {
 		for _, msg := range IgnoredErrors {
 			t.Run(msg, func(t *testing.T) {
@@ -0,0 +1,170 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+type AlertRuleTemplatesProvisioner interface {
+	Provision(ctx context.Context, files []*AlertingFile) error
+	Unprovision(ctx context.Context, files []*AlertingFile) error
+}
+
+type defaultAlertRuleTemplatesProvisioner struct {
+	logger                   log.Logger
+	alertRuleTemplateService provisioning.AlertRuleTemplateService
+}
+
+func NewAlertRuleTemplatesProvisioner(logger log.Logger,
+	alertRuleTemplateService provisioning.AlertRuleTemplateService) AlertRuleTemplatesProvisioner {
+	return &defaultAlertRuleTemplatesProvisioner{
+		logger:                   logger,
+		alertRuleTemplateService: alertRuleTemplateService,
+	}
+}
+
+// Provision creates or updates the alert rule templates of the files, and then their instances, so that instances
+// can use templates of any file.
+func (c *defaultAlertRuleTemplatesProvisioner) Provision(ctx context.Context,
+	files []*AlertingFile) error {
+	cache := map[int64][]models.AlertRuleTemplate{}
+	for _, file := range files {
+		for _, template := range file.AlertRuleTemplates {
+			existing, err := c.getAlertRuleTemplates(ctx, cache, template.OrgID)
+			if err != nil {
+				return err
+			}
+			ctx, user := identity.WithServiceIdentity(ctx, template.OrgID)
+			template.AlertRuleTemplate.Provenance = models.ProvenanceFile
+			if uid, exists := findAlertRuleTemplate(existing, template.AlertRuleTemplate.UID, template.AlertRuleTemplate.Name); exists {
+				template.AlertRuleTemplate.UID = uid
+				_, err := c.alertRuleTemplateService.UpdateAlertRuleTemplate(ctx, user, template.AlertRuleTemplate)
+				if err != nil {
+					return fmt.Errorf("%s: %w", template.AlertRuleTemplate.Name, err)
+				}
+				continue
+			}
+			created, err := c.alertRuleTemplateService.CreateAlertRuleTemplate(ctx, user, template.AlertRuleTemplate)
+			if err != nil {
+				return fmt.Errorf("%s: %w", template.AlertRuleTemplate.Name, err)
+			}
+			cache[template.OrgID] = append(cache[template.OrgID], created)
+		}
+	}
+	for _, file := range files {
+		for _, instance := range file.AlertRuleTemplateInstances {
+			existing, err := c.getAlertRuleTemplates(ctx, cache, instance.OrgID)
+			if err != nil {
+				return err
+			}
+			uid, exists := findAlertRuleTemplate(existing, instance.Template, "")
+			if !exists {
+				uid, exists = findAlertRuleTemplate(existing, "", instance.Template)
+			}
+			if !exists {
+				return fmt.Errorf("alert rule template '%s' of instance '%s' was not found", instance.Template, instance.AlertRuleTemplateInstance.UID)
+			}
+			ctx, user := identity.WithServiceIdentity(ctx, instance.OrgID)
+			instance.AlertRuleTemplateInstance.TemplateUID = uid
+			instance.AlertRuleTemplateInstance.Provenance = models.ProvenanceFile
+			_, err = c.alertRuleTemplateService.GetAlertRuleTemplateInstance(ctx, instance.OrgID, uid, instance.AlertRuleTemplateInstance.UID)
+			if err == nil {
+				_, err = c.alertRuleTemplateService.UpdateAlertRuleTemplateInstance(ctx, user, instance.AlertRuleTemplateInstance)
+			} else if errors.Is(err, provisioning.ErrAlertRuleTemplateInstanceNotFound) {
+				_, err = c.alertRuleTemplateService.CreateAlertRuleTemplateInstance(ctx, user, instance.AlertRuleTemplateInstance)
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", instance.AlertRuleTemplateInstance.UID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Unprovision deletes the instances of the files, and then the alert rule templates.
+func (c *defaultAlertRuleTemplatesProvisioner) Unprovision(ctx context.Context,
+	files []*AlertingFile) error {
+	cache := map[int64][]models.AlertRuleTemplate{}
+	for _, file := range files {
+		for _, deleteInstance := range file.DeleteAlertRuleTemplateInstances {
+			templateUID, exists, err := c.findAlertRuleTemplateOfInstance(ctx, cache, deleteInstance.OrgID, deleteInstance.UID)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				c.logger.Debug("Alert rule template instance was not found. Skip deleting", "uid", deleteInstance.UID, "org", deleteInstance.OrgID)
+				continue
+			}
+			ctx, user := identity.WithServiceIdentity(ctx, deleteInstance.OrgID)
+			err = c.alertRuleTemplateService.DeleteAlertRuleTemplateInstance(ctx, user, templateUID, deleteInstance.UID, models.ProvenanceFile)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, file := range files {
+		for _, deleteTemplate := range file.DeleteAlertRuleTemplates {
+			existing, err := c.getAlertRuleTemplates(ctx, cache, deleteTemplate.OrgID)
+			if err != nil {
+				return err
+			}
+			uid, exists := findAlertRuleTemplate(existing, "", deleteTemplate.Name)
+			if !exists {
+				c.logger.Debug("Alert rule template was not found. Skip deleting", "name", deleteTemplate.Name, "org", deleteTemplate.OrgID)
+				continue
+			}
+			ctx, user := identity.WithServiceIdentity(ctx, deleteTemplate.OrgID)
+			err = c.alertRuleTemplateService.DeleteAlertRuleTemplate(ctx, user, uid, models.ProvenanceFile)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *defaultAlertRuleTemplatesProvisioner) getAlertRuleTemplates(ctx context.Context, cache map[int64][]models.AlertRuleTemplate, orgID int64) ([]models.AlertRuleTemplate, error) {
+	if templates, ok := cache[orgID]; ok {
+		return templates, nil
+	}
+	templates, err := c.alertRuleTemplateService.GetAlertRuleTemplates(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	cache[orgID] = templates
+	return templates, nil
+}
+
+// findAlertRuleTemplateOfInstance returns the UID of the alert rule template that has an instance with the given UID.
+func (c *defaultAlertRuleTemplatesProvisioner) findAlertRuleTemplateOfInstance(ctx context.Context, cache map[int64][]models.AlertRuleTemplate, orgID int64, uid string) (string, bool, error) {
+	templates, err := c.getAlertRuleTemplates(ctx, cache, orgID)
+	if err != nil {
+		return "", false, err
+	}
+	for _, t := range templates {
+		_, err := c.alertRuleTemplateService.GetAlertRuleTemplateInstance(ctx, orgID, t.UID, uid)
+		if err == nil {
+			return t.UID, true, nil
+		}
+		if !errors.Is(err, provisioning.ErrAlertRuleTemplateInstanceNotFound) {
+			return "", false, err
+		}
+	}
+	return "", false, nil
+}
+
+// findAlertRuleTemplate returns the UID of the alert rule template with the given UID or, if the UID is empty, with the given name.
+func findAlertRuleTemplate(templates []models.AlertRuleTemplate, uid, name string) (string, bool) {
+	for _, t := range templates {
+		if (uid != "" && t.UID == uid) || (uid == "" && t.Name == name) {
+			return t.UID, true
+		}
+	}
+	return "", false
+}
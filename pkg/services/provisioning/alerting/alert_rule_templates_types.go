@@ -0,0 +1,250 @@
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+type AlertRuleTemplateV1 struct {
+	OrgID      values.Int64Value              `json:"orgId" yaml:"orgId"`
+	UID        values.StringValue             `json:"uid" yaml:"uid"`
+	Name       values.StringValue             `json:"name" yaml:"name"`
+	Parameters []AlertRuleTemplateParameterV1 `json:"parameters" yaml:"parameters"`
+	Rule       AlertRuleTemplateRuleV1        `json:"rule" yaml:"rule"`
+}
+
+type AlertRuleTemplateParameterV1 struct {
+	Name        values.StringValue `json:"name" yaml:"name"`
+	Type        values.StringValue `json:"type" yaml:"type"`
+	Description values.StringValue `json:"description" yaml:"description"`
+}
+
+// AlertRuleTemplateRuleV1 is the rule of an alert rule template. The fields that can reference the parameters of the
+// template, written ${name}, are not interpolated with environment variables.
+type AlertRuleTemplateRuleV1 struct {
+	Title                values.StringValue      `json:"title" yaml:"title"`
+	Condition            values.StringValue      `json:"condition" yaml:"condition"`
+	Data                 []QueryV1               `json:"data" yaml:"data"`
+	NoDataState          values.StringValue      `json:"noDataState" yaml:"noDataState"`
+	ExecErrState         values.StringValue      `json:"execErrState" yaml:"execErrState"`
+	For                  values.StringValue      `json:"for" yaml:"for"`
+	Annotations          values.StringMapValue   `json:"annotations" yaml:"annotations"`
+	Labels               values.StringMapValue   `json:"labels" yaml:"labels"`
+	IsPaused             values.BoolValue        `json:"isPaused" yaml:"isPaused"`
+	NotificationSettings *NotificationSettingsV1 `json:"notification_settings" yaml:"notification_settings"`
+	Record               *RecordV1               `json:"record" yaml:"record"`
+}
+
+func (v1 *AlertRuleTemplateV1) mapToModel() (AlertRuleTemplate, error) {
+	name := strings.TrimSpace(v1.Name.Value())
+	if name == "" {
+		return AlertRuleTemplate{}, errors.New("alert rule template missing name")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	template := models.AlertRuleTemplate{
+		UID:  strings.TrimSpace(v1.UID.Value()),
+		Name: name,
+	}
+	for _, p := range v1.Parameters {
+		template.Parameters = append(template.Parameters, models.AlertRuleTemplateParameter{
+			Name:        strings.TrimSpace(p.Name.Value()),
+			Type:        models.AlertRuleTemplateParameterType(strings.TrimSpace(p.Type.Value())),
+			Description: p.Description.Value(),
+		})
+	}
+	rule, err := v1.Rule.mapToModel()
+	if err != nil {
+		return AlertRuleTemplate{}, fmt.Errorf("alert rule template '%s' failed to parse: %w", name, err)
+	}
+	template.Rule = rule
+	return AlertRuleTemplate{
+		OrgID:             orgID,
+		AlertRuleTemplate: template,
+	}, nil
+}
+
+func (rule *AlertRuleTemplateRuleV1) mapToModel() (models.AlertRuleTemplateRule, error) {
+	result := models.AlertRuleTemplateRule{
+		Title:       rule.Title.Raw,
+		Condition:   rule.Condition.Value(),
+		Annotations: rule.Annotations.Raw,
+		Labels:      rule.Labels.Raw,
+		IsPaused:    rule.IsPaused.Value(),
+	}
+	if result.Title == "" {
+		return models.AlertRuleTemplateRule{}, errors.New("rule has no title set")
+	}
+	if result.Condition == "" {
+		return models.AlertRuleTemplateRule{}, errors.New("rule has no condition set")
+	}
+	if rule.For.Value() != "" {
+		duration, err := model.ParseDuration(rule.For.Value())
+		if err != nil {
+			return models.AlertRuleTemplateRule{}, fmt.Errorf("failed to parse 'for' field: %w", err)
+		}
+		result.For = time.Duration(duration)
+	}
+	execErrStateValue := strings.TrimSpace(rule.ExecErrState.Value())
+	result.ExecErrState = models.AlertingErrState
+	if execErrStateValue != "" {
+		execErrState, err := models.ErrStateFromString(execErrStateValue)
+		if err != nil {
+			return models.AlertRuleTemplateRule{}, err
+		}
+		result.ExecErrState = execErrState
+	}
+	noDataStateValue := strings.TrimSpace(rule.NoDataState.Value())
+	result.NoDataState = models.NoData
+	if noDataStateValue != "" {
+		noDataState, err := models.NoDataStateFromString(noDataStateValue)
+		if err != nil {
+			return models.AlertRuleTemplateRule{}, err
+		}
+		result.NoDataState = noDataState
+	}
+	for _, queryV1 := range rule.Data {
+		query, err := queryV1.mapToModel()
+		if err != nil {
+			return models.AlertRuleTemplateRule{}, err
+		}
+		// The data source can be a parameter of the template.
+		query.DatasourceUID = queryV1.DatasourceUID.Raw
+		result.Data = append(result.Data, query)
+	}
+	if len(result.Data) == 0 {
+		return models.AlertRuleTemplateRule{}, errors.New("rule has no data set")
+	}
+	if rule.NotificationSettings != nil {
+		ns, err := rule.NotificationSettings.mapToModel()
+		if err != nil {
+			return models.AlertRuleTemplateRule{}, err
+		}
+		result.NotificationSettings = append(result.NotificationSettings, ns)
+	}
+	if rule.Record != nil {
+		record, err := rule.Record.mapToModel()
+		if err != nil {
+			return models.AlertRuleTemplateRule{}, err
+		}
+		record.Metric = rule.Record.Metric.Raw
+		result.Record = &record
+	}
+	return result, nil
+}
+
+type AlertRuleTemplate struct {
+	OrgID             int64
+	AlertRuleTemplate models.AlertRuleTemplate
+}
+
+type DeleteAlertRuleTemplateV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name  values.StringValue `json:"name" yaml:"name"`
+}
+
+func (v1 *DeleteAlertRuleTemplateV1) mapToModel() (DeleteAlertRuleTemplate, error) {
+	name := strings.TrimSpace(v1.Name.Value())
+	if name == "" {
+		return DeleteAlertRuleTemplate{}, errors.New("delete alert rule template missing name")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	return DeleteAlertRuleTemplate{
+		OrgID: orgID,
+		Name:  name,
+	}, nil
+}
+
+type DeleteAlertRuleTemplate struct {
+	OrgID int64
+	Name  string
+}
+
+type AlertRuleTemplateInstanceV1 struct {
+	OrgID    values.Int64Value     `json:"orgId" yaml:"orgId"`
+	UID      values.StringValue    `json:"uid" yaml:"uid"`
+	Template values.StringValue    `json:"template" yaml:"template"`
+	Folder   values.StringValue    `json:"folderUid" yaml:"folderUid"`
+	Group    values.StringValue    `json:"ruleGroup" yaml:"ruleGroup"`
+	Interval values.StringValue    `json:"interval" yaml:"interval"`
+	Values   values.StringMapValue `json:"values" yaml:"values"`
+}
+
+func (v1 *AlertRuleTemplateInstanceV1) mapToModel() (AlertRuleTemplateInstance, error) {
+	uid := strings.TrimSpace(v1.UID.Value())
+	if uid == "" {
+		return AlertRuleTemplateInstance{}, errors.New("alert rule template instance missing uid")
+	}
+	template := strings.TrimSpace(v1.Template.Value())
+	if template == "" {
+		return AlertRuleTemplateInstance{}, fmt.Errorf("alert rule template instance '%s' has no template set", uid)
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	var interval int64
+	if v1.Interval.Value() != "" {
+		d, err := model.ParseDuration(v1.Interval.Value())
+		if err != nil {
+			return AlertRuleTemplateInstance{}, fmt.Errorf("alert rule template instance '%s' failed to parse 'interval' field: %w", uid, err)
+		}
+		interval = int64(time.Duration(d).Seconds())
+	}
+	return AlertRuleTemplateInstance{
+		OrgID:    orgID,
+		Template: template,
+		AlertRuleTemplateInstance: models.AlertRuleTemplateInstance{
+			UID:             uid,
+			NamespaceUID:    strings.TrimSpace(v1.Folder.Value()),
+			RuleGroup:       strings.TrimSpace(v1.Group.Value()),
+			IntervalSeconds: interval,
+			Values:          v1.Values.Value(),
+		},
+	}, nil
+}
+
+// AlertRuleTemplateInstance is an instance of the alert rule template with the UID or, if there is none, the name
+// in Template.
+type AlertRuleTemplateInstance struct {
+	OrgID                     int64
+	Template                  string
+	AlertRuleTemplateInstance models.AlertRuleTemplateInstance
+}
+
+type DeleteAlertRuleTemplateInstanceV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+}
+
+func (v1 *DeleteAlertRuleTemplateInstanceV1) mapToModel() (DeleteAlertRuleTemplateInstance, error) {
+	uid := strings.TrimSpace(v1.UID.Value())
+	if uid == "" {
+		return DeleteAlertRuleTemplateInstance{}, errors.New("delete alert rule template instance missing uid")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	return DeleteAlertRuleTemplateInstance{
+		OrgID: orgID,
+		UID:   uid,
+	}, nil
+}
+
+type DeleteAlertRuleTemplateInstance struct {
+	OrgID int64
+	UID   string
+}
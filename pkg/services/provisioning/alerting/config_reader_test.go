@@ -3,6 +3,7 @@ package alerting
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -34,6 +35,8 @@ const (
 	testFileCorrectProperties_t         = "./testdata/templates/correct-properties"
 	testFileCorrectPropertiesWithOrg_t  = "./testdata/templates/correct-properties-with-org"
 	testFileMultipleTs                  = "./testdata/templates/multiple-templates"
+	testFileCorrectProperties_rs        = "./testdata/recurring_silences/correct-properties"
+	testFileCorrectProperties_art       = "./testdata/alert_rule_templates/correct-properties"
 )
 
 func TestConfigReader(t *testing.T) {
@@ -160,6 +163,54 @@ func TestConfigReader(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, file[0].Templates, 2)
 	})
+	t.Run("a recurring silences file with correct properties should not error", func(t *testing.T) {
+		file, err := configReader.readConfig(ctx, testFileCorrectProperties_rs)
+		require.NoError(t, err)
+		require.Len(t, file[0].RecurringSilences, 2)
+		t.Run("when no organization is present it should be set to 1", func(t *testing.T) {
+			require.Equal(t, int64(1), file[0].RecurringSilences[0].OrgID)
+			require.Equal(t, int64(1337), file[0].RecurringSilences[1].OrgID)
+		})
+		t.Run("the schedule should be parsed", func(t *testing.T) {
+			weekly := file[0].RecurringSilences[0].RecurringSilence
+			require.Equal(t, "weekly-maintenance", weekly.Name)
+			require.Equal(t, "0 2 * * 0", weekly.Cron)
+			require.Equal(t, 2*time.Hour, weekly.Duration)
+			require.Len(t, weekly.Matchers, 1)
+			require.NoError(t, weekly.Validate())
+
+			businessHours := file[0].RecurringSilences[1].RecurringSilence
+			require.Len(t, businessHours.TimeIntervals, 1)
+			require.NoError(t, businessHours.Validate())
+		})
+		require.Equal(t, "old-maintenance", file[0].DeleteRecurringSilences[0].Name)
+	})
+	t.Run("an alert rule templates file with correct properties should not error", func(t *testing.T) {
+		file, err := configReader.readConfig(ctx, testFileCorrectProperties_art)
+		require.NoError(t, err)
+		require.Len(t, file[0].AlertRuleTemplates, 1)
+		t.Run("the references to parameters should not be interpolated", func(t *testing.T) {
+			template := file[0].AlertRuleTemplates[0].AlertRuleTemplate
+			require.Equal(t, int64(1), file[0].AlertRuleTemplates[0].OrgID)
+			require.Equal(t, "high-cpu", template.UID)
+			require.Len(t, template.Parameters, 3)
+			require.Equal(t, "High CPU usage on ${instance}", template.Rule.Title)
+			require.Equal(t, "${datasource}", template.Rule.Data[0].DatasourceUID)
+			require.Equal(t, map[string]string{"instance": "${instance}"}, template.Rule.Labels)
+			require.Equal(t, 5*time.Minute, template.Rule.For)
+			require.NoError(t, template.Validate())
+		})
+		t.Run("the instance should be parsed", func(t *testing.T) {
+			require.Len(t, file[0].AlertRuleTemplateInstances, 1)
+			instance := file[0].AlertRuleTemplateInstances[0]
+			require.Equal(t, "high-cpu", instance.Template)
+			require.Equal(t, "high-cpu-web-1", instance.AlertRuleTemplateInstance.UID)
+			require.Equal(t, int64(60), instance.AlertRuleTemplateInstance.IntervalSeconds)
+			require.Equal(t, "web-1", instance.AlertRuleTemplateInstance.Values["instance"])
+		})
+		require.Equal(t, "Old template", file[0].DeleteAlertRuleTemplates[0].Name)
+		require.Equal(t, "high-cpu-web-0", file[0].DeleteAlertRuleTemplateInstances[0].UID)
+	})
 	t.Run("a rule file with dasboard typo", func(t *testing.T) {
 		ruleFiles, err := configReader.readConfig(ctx, testFileDasboardTypoSupport)
 		require.NoError(t, err)
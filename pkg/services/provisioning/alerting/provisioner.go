@@ -18,7 +18,9 @@ type ProvisionerConfig struct {
 	ContactPointService        provisioning.ContactPointService
 	NotificiationPolicyService provisioning.NotificationPolicyService
 	MuteTimingService          provisioning.MuteTimingService
+	RecurringSilenceService    provisioning.RecurringSilenceService
 	TemplateService            provisioning.TemplateService
+	AlertRuleTemplateService   provisioning.AlertRuleTemplateService
 }
 
 func Provision(ctx context.Context, cfg ProvisionerConfig) error {
@@ -40,6 +42,11 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return fmt.Errorf("mute times: %w", err)
 	}
+	rsProvisioner := NewRecurringSilencesProvisioner(logger, cfg.RecurringSilenceService)
+	err = rsProvisioner.Provision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("recurring silences: %w", err)
+	}
 	ttProvsioner := NewTextTemplateProvisioner(logger, cfg.TemplateService)
 	err = ttProvsioner.Provision(ctx, files)
 	if err != nil {
@@ -58,6 +65,10 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return fmt.Errorf("mute times: %w", err)
 	}
+	err = rsProvisioner.Unprovision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("recurring silences: %w", err)
+	}
 	err = ttProvsioner.Unprovision(ctx, files)
 	if err != nil {
 		return fmt.Errorf("text templates: %w", err)
@@ -71,6 +82,15 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return fmt.Errorf("alert rules: %w", err)
 	}
+	artProvisioner := NewAlertRuleTemplatesProvisioner(logger, cfg.AlertRuleTemplateService)
+	err = artProvisioner.Provision(ctx, files) // Provision templates after rules, as instances add rules to their groups
+	if err != nil {
+		return fmt.Errorf("alert rule templates: %w", err)
+	}
+	err = artProvisioner.Unprovision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("alert rule templates: %w", err)
+	}
 	err = cpProvisioner.Unprovision(ctx, files) // Unprovision contact points after rules to make sure all references in rules are updated
 	if err != nil {
 		return fmt.Errorf("contact points: %w", err)
@@ -0,0 +1,100 @@
+package alerting
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+type RecurringSilencesProvisioner interface {
+	Provision(ctx context.Context, files []*AlertingFile) error
+	Unprovision(ctx context.Context, files []*AlertingFile) error
+}
+
+type defaultRecurringSilencesProvisioner struct {
+	logger                  log.Logger
+	recurringSilenceService provisioning.RecurringSilenceService
+}
+
+func NewRecurringSilencesProvisioner(logger log.Logger,
+	recurringSilenceService provisioning.RecurringSilenceService) RecurringSilencesProvisioner {
+	return &defaultRecurringSilencesProvisioner{
+		logger:                  logger,
+		recurringSilenceService: recurringSilenceService,
+	}
+}
+
+func (c *defaultRecurringSilencesProvisioner) Provision(ctx context.Context,
+	files []*AlertingFile) error {
+	cache := map[int64][]models.RecurringSilence{}
+	for _, file := range files {
+		for _, silence := range file.RecurringSilences {
+			existing, err := c.getRecurringSilences(ctx, cache, silence.OrgID)
+			if err != nil {
+				return err
+			}
+			silence.RecurringSilence.Provenance = models.ProvenanceFile
+			if uid, exists := findRecurringSilence(existing, silence.RecurringSilence.UID, silence.RecurringSilence.Name); exists {
+				silence.RecurringSilence.UID = uid
+				_, err := c.recurringSilenceService.UpdateRecurringSilence(ctx, silence.RecurringSilence, silence.OrgID)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			created, err := c.recurringSilenceService.CreateRecurringSilence(ctx, silence.RecurringSilence, silence.OrgID)
+			if err != nil {
+				return err
+			}
+			cache[silence.OrgID] = append(cache[silence.OrgID], created)
+		}
+	}
+	return nil
+}
+
+func (c *defaultRecurringSilencesProvisioner) Unprovision(ctx context.Context,
+	files []*AlertingFile) error {
+	cache := map[int64][]models.RecurringSilence{}
+	for _, file := range files {
+		for _, deleteSilence := range file.DeleteRecurringSilences {
+			existing, err := c.getRecurringSilences(ctx, cache, deleteSilence.OrgID)
+			if err != nil {
+				return err
+			}
+			uid, exists := findRecurringSilence(existing, "", deleteSilence.Name)
+			if !exists {
+				c.logger.Debug("Recurring silence was not found. Skip deleting", "name", deleteSilence.Name, "org", deleteSilence.OrgID)
+				continue
+			}
+			err = c.recurringSilenceService.DeleteRecurringSilence(ctx, uid, deleteSilence.OrgID, models.ProvenanceFile)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *defaultRecurringSilencesProvisioner) getRecurringSilences(ctx context.Context, cache map[int64][]models.RecurringSilence, orgID int64) ([]models.RecurringSilence, error) {
+	if silences, ok := cache[orgID]; ok {
+		return silences, nil
+	}
+	silences, err := c.recurringSilenceService.GetRecurringSilences(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	cache[orgID] = silences
+	return silences, nil
+}
+
+// findRecurringSilence returns the UID of the recurring silence with the given UID or, if the UID is empty, with the given name.
+func findRecurringSilence(silences []models.RecurringSilence, uid, name string) (string, bool) {
+	for _, s := range silences {
+		if (uid != "" && s.UID == uid) || (uid == "" && s.Name == name) {
+			return s.UID, true
+		}
+	}
+	return "", false
+}
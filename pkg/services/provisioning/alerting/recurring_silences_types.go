@@ -0,0 +1,79 @@
+package alerting
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+type RecurringSilenceV1 struct {
+	OrgID         values.Int64Value           `json:"orgId" yaml:"orgId"`
+	UID           values.StringValue          `json:"uid" yaml:"uid"`
+	Name          values.StringValue          `json:"name" yaml:"name"`
+	Matchers      definitions.ObjectMatchers  `json:"matchers" yaml:"matchers"`
+	Cron          values.StringValue          `json:"cron" yaml:"cron"`
+	Duration      model.Duration              `json:"duration" yaml:"duration"`
+	TimeIntervals []timeinterval.TimeInterval `json:"time_intervals" yaml:"time_intervals"`
+	Comment       values.StringValue          `json:"comment" yaml:"comment"`
+}
+
+func (v1 *RecurringSilenceV1) mapToModel() (RecurringSilence, error) {
+	name := strings.TrimSpace(v1.Name.Value())
+	if name == "" {
+		return RecurringSilence{}, errors.New("recurring silence missing name")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	return RecurringSilence{
+		OrgID: orgID,
+		RecurringSilence: models.RecurringSilence{
+			UID:           strings.TrimSpace(v1.UID.Value()),
+			Name:          name,
+			Matchers:      labels.Matchers(v1.Matchers),
+			Cron:          strings.TrimSpace(v1.Cron.Value()),
+			Duration:      time.Duration(v1.Duration),
+			TimeIntervals: v1.TimeIntervals,
+			Comment:       v1.Comment.Value(),
+		},
+	}, nil
+}
+
+type RecurringSilence struct {
+	OrgID            int64
+	RecurringSilence models.RecurringSilence
+}
+
+type DeleteRecurringSilenceV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name  values.StringValue `json:"name" yaml:"name"`
+}
+
+func (v1 *DeleteRecurringSilenceV1) mapToModel() (DeleteRecurringSilence, error) {
+	name := strings.TrimSpace(v1.Name.Value())
+	if name == "" {
+		return DeleteRecurringSilence{}, errors.New("delete recurring silence missing name")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	return DeleteRecurringSilence{
+		OrgID: orgID,
+		Name:  name,
+	}, nil
+}
+
+type DeleteRecurringSilence struct {
+	OrgID int64
+	Name  string
+}
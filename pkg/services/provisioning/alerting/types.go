@@ -15,32 +15,44 @@ type OrgID int64
 
 type AlertingFile struct {
 	configVersion
-	Filename            string
-	Groups              []models.AlertRuleGroupWithFolderFullpath
-	DeleteRules         []RuleDelete
-	ContactPoints       []ContactPoint
-	DeleteContactPoints []DeleteContactPoint
-	Policies            []NotificiationPolicy
-	ResetPolicies       []OrgID
-	MuteTimes           []MuteTime
-	DeleteMuteTimes     []DeleteMuteTime
-	Templates           []Template
-	DeleteTemplates     []DeleteTemplate
+	Filename                         string
+	Groups                           []models.AlertRuleGroupWithFolderFullpath
+	DeleteRules                      []RuleDelete
+	ContactPoints                    []ContactPoint
+	DeleteContactPoints              []DeleteContactPoint
+	Policies                         []NotificiationPolicy
+	ResetPolicies                    []OrgID
+	MuteTimes                        []MuteTime
+	DeleteMuteTimes                  []DeleteMuteTime
+	RecurringSilences                []RecurringSilence
+	DeleteRecurringSilences          []DeleteRecurringSilence
+	Templates                        []Template
+	DeleteTemplates                  []DeleteTemplate
+	AlertRuleTemplates               []AlertRuleTemplate
+	DeleteAlertRuleTemplates         []DeleteAlertRuleTemplate
+	AlertRuleTemplateInstances       []AlertRuleTemplateInstance
+	DeleteAlertRuleTemplateInstances []DeleteAlertRuleTemplateInstance
 }
 
 type AlertingFileV1 struct {
 	configVersion
-	Filename            string
-	Groups              []AlertRuleGroupV1      `json:"groups" yaml:"groups"`
-	DeleteRules         []RuleDeleteV1          `json:"deleteRules" yaml:"deleteRules"`
-	ContactPoints       []ContactPointV1        `json:"contactPoints" yaml:"contactPoints"`
-	DeleteContactPoints []DeleteContactPointV1  `json:"deleteContactPoints" yaml:"deleteContactPoints"`
-	Policies            []NotificiationPolicyV1 `json:"policies" yaml:"policies"`
-	ResetPolicies       []values.Int64Value     `json:"resetPolicies" yaml:"resetPolicies"`
-	MuteTimes           []MuteTimeV1            `json:"muteTimes" yaml:"muteTimes"`
-	DeleteMuteTimes     []DeleteMuteTimeV1      `json:"deleteMuteTimes" yaml:"deleteMuteTimes"`
-	Templates           []TemplateV1            `json:"templates" yaml:"templates"`
-	DeleteTemplates     []DeleteTemplateV1      `json:"deleteTemplates" yaml:"deleteTemplates"`
+	Filename                         string
+	Groups                           []AlertRuleGroupV1                  `json:"groups" yaml:"groups"`
+	DeleteRules                      []RuleDeleteV1                      `json:"deleteRules" yaml:"deleteRules"`
+	ContactPoints                    []ContactPointV1                    `json:"contactPoints" yaml:"contactPoints"`
+	DeleteContactPoints              []DeleteContactPointV1              `json:"deleteContactPoints" yaml:"deleteContactPoints"`
+	Policies                         []NotificiationPolicyV1             `json:"policies" yaml:"policies"`
+	ResetPolicies                    []values.Int64Value                 `json:"resetPolicies" yaml:"resetPolicies"`
+	MuteTimes                        []MuteTimeV1                        `json:"muteTimes" yaml:"muteTimes"`
+	DeleteMuteTimes                  []DeleteMuteTimeV1                  `json:"deleteMuteTimes" yaml:"deleteMuteTimes"`
+	RecurringSilences                []RecurringSilenceV1                `json:"recurringSilences" yaml:"recurringSilences"`
+	DeleteRecurringSilences          []DeleteRecurringSilenceV1          `json:"deleteRecurringSilences" yaml:"deleteRecurringSilences"`
+	Templates                        []TemplateV1                        `json:"templates" yaml:"templates"`
+	DeleteTemplates                  []DeleteTemplateV1                  `json:"deleteTemplates" yaml:"deleteTemplates"`
+	AlertRuleTemplates               []AlertRuleTemplateV1               `json:"alertRuleTemplates" yaml:"alertRuleTemplates"`
+	DeleteAlertRuleTemplates         []DeleteAlertRuleTemplateV1         `json:"deleteAlertRuleTemplates" yaml:"deleteAlertRuleTemplates"`
+	AlertRuleTemplateInstances       []AlertRuleTemplateInstanceV1       `json:"alertRuleTemplateInstances" yaml:"alertRuleTemplateInstances"`
+	DeleteAlertRuleTemplateInstances []DeleteAlertRuleTemplateInstanceV1 `json:"deleteAlertRuleTemplateInstances" yaml:"deleteAlertRuleTemplateInstances"`
 }
 
 func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
@@ -61,6 +73,12 @@ func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
 	if err := fileV1.mapTemplates(&alertingFile); err != nil {
 		return AlertingFile{}, fmt.Errorf("failure parsing templates: %w", err)
 	}
+	if err := fileV1.mapRecurringSilences(&alertingFile); err != nil {
+		return AlertingFile{}, fmt.Errorf("failure parsing recurring silences: %w", err)
+	}
+	if err := fileV1.mapAlertRuleTemplates(&alertingFile); err != nil {
+		return AlertingFile{}, fmt.Errorf("failure parsing alert rule templates: %w", err)
+	}
 	return alertingFile, nil
 }
 
@@ -92,6 +110,56 @@ func (fileV1 *AlertingFileV1) mapMuteTimes(alertingFile *AlertingFile) error {
 	return nil
 }
 
+func (fileV1 *AlertingFileV1) mapRecurringSilences(alertingFile *AlertingFile) error {
+	for _, rsV1 := range fileV1.RecurringSilences {
+		rs, err := rsV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.RecurringSilences = append(alertingFile.RecurringSilences, rs)
+	}
+	for _, deleteV1 := range fileV1.DeleteRecurringSilences {
+		delReq, err := deleteV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.DeleteRecurringSilences = append(alertingFile.DeleteRecurringSilences, delReq)
+	}
+	return nil
+}
+
+func (fileV1 *AlertingFileV1) mapAlertRuleTemplates(alertingFile *AlertingFile) error {
+	for _, artV1 := range fileV1.AlertRuleTemplates {
+		art, err := artV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.AlertRuleTemplates = append(alertingFile.AlertRuleTemplates, art)
+	}
+	for _, deleteV1 := range fileV1.DeleteAlertRuleTemplates {
+		delReq, err := deleteV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.DeleteAlertRuleTemplates = append(alertingFile.DeleteAlertRuleTemplates, delReq)
+	}
+	for _, instanceV1 := range fileV1.AlertRuleTemplateInstances {
+		instance, err := instanceV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.AlertRuleTemplateInstances = append(alertingFile.AlertRuleTemplateInstances, instance)
+	}
+	for _, deleteV1 := range fileV1.DeleteAlertRuleTemplateInstances {
+		delReq, err := deleteV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.DeleteAlertRuleTemplateInstances = append(alertingFile.DeleteAlertRuleTemplateInstances, delReq)
+	}
+	return nil
+}
+
 func (fileV1 *AlertingFileV1) mapPolicies(alertingFile *AlertingFile) error {
 	for _, npV1 := range fileV1.Policies {
 		np, err := npV1.mapToModel()
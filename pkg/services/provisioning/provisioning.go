@@ -322,6 +322,8 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		ps.alertingStore, ps.SQLStore, ps.Cfg.UnifiedAlerting, ps.log)
 	mutetimingsService := provisioning.NewMuteTimingService(configStore, ps.alertingStore, ps.alertingStore, ps.log, ps.alertingStore)
 	templateService := provisioning.NewTemplateService(configStore, ps.alertingStore, ps.alertingStore, ps.log)
+	recurringSilenceService := provisioning.NewRecurringSilenceService(ps.alertingStore, ps.alertingStore, ps.alertingStore, ps.log)
+	alertRuleTemplateService := provisioning.NewAlertRuleTemplateService(ps.alertingStore, ps.alertingStore, ps.alertingStore, ruleService, ps.log)
 	cfg := prov_alerting.ProvisionerConfig{
 		Path:                       alertingPath,
 		RuleService:                *ruleService,
@@ -330,7 +332,9 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		ContactPointService:        *contactPointService,
 		NotificiationPolicyService: *notificationPolicyService,
 		MuteTimingService:          *mutetimingsService,
+		RecurringSilenceService:    *recurringSilenceService,
 		TemplateService:            *templateService,
+		AlertRuleTemplateService:   *alertRuleTemplateService,
 	}
 	return ps.provisionAlerting(ctx, cfg)
 }
@@ -555,7 +555,7 @@ func setup(t *testing.T, isMultiTenant bool, mockClient clientapi.QueryDataClien
 		mtdsClientBuilder = mtdsclient.NewTestMTDSClientBuilder(false, nil)
 	}
 
-	exprService := expr.ProvideService(
+	exprService, err := expr.ProvideService(
 		&setting.Cfg{ExpressionsEnabled: true},
 		pc,
 		pCtxProvider,
@@ -564,6 +564,7 @@ func setup(t *testing.T, isMultiTenant bool, mockClient clientapi.QueryDataClien
 		tracing.InitializeTracerForTest(),
 		mtdsClientBuilder,
 	)
+	require.NoError(t, err)
 
 	queryService := ProvideService(
 		setting.NewCfg(),
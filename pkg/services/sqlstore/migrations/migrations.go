@@ -155,4 +155,14 @@ func (oss *OSSMigrations) AddMigration(mg *Migrator) {
 	ualert.DropTitleUniqueIndexMigration(mg)
 
 	ualert.AddStateFiredAtColumn(mg)
+
+	ualert.AddAlertStateHistoryTable(mg)
+
+	ualert.AddAlertRuleDependsOn(mg)
+
+	ualert.AddAlertRecurringSilenceTable(mg)
+
+	ualert.AddAlertRuleTemplateTables(mg)
+
+	addLivePipelineMigrations(mg)
 }
@@ -0,0 +1,17 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddAlertRuleDependsOn adds depends_on column to alert_rule and alert_rule_version tables.
+func AddAlertRuleDependsOn(mg *migrator.Migrator) {
+	column := &migrator.Column{Name: "depends_on", Type: migrator.DB_Text, Nullable: true}
+
+	mg.AddMigration(
+		"add depends_on column to alert_rule",
+		migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, column),
+	)
+	mg.AddMigration(
+		"add depends_on column to alert_rule_version",
+		migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, column),
+	)
+}
@@ -0,0 +1,70 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddAlertRuleTemplateTables adds the tables that store alert rule templates and their instances.
+func AddAlertRuleTemplateTables(mg *migrator.Migrator) {
+	templateTable := migrator.Table{
+		Name: "alert_rule_template",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "name", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "parameters", Type: migrator.DB_Text, Nullable: false},
+			{Name: "rule_definition", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "version", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "name"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration(
+		"add alert_rule_template table",
+		migrator.NewAddTableMigration(templateTable),
+	)
+	mg.AddMigration(
+		"add unique index to alert_rule_template on org_id and uid columns",
+		migrator.NewAddIndexMigration(templateTable, templateTable.Indices[0]),
+	)
+	mg.AddMigration(
+		"add unique index to alert_rule_template on org_id and name columns",
+		migrator.NewAddIndexMigration(templateTable, templateTable.Indices[1]),
+	)
+
+	instanceTable := migrator.Table{
+		Name: "alert_rule_template_instance",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "template_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "namespace_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "rule_group", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "interval_seconds", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "parameter_values", Type: migrator.DB_Text, Nullable: false},
+			{Name: "version", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "template_uid"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration(
+		"add alert_rule_template_instance table",
+		migrator.NewAddTableMigration(instanceTable),
+	)
+	mg.AddMigration(
+		"add unique index to alert_rule_template_instance on org_id and uid columns",
+		migrator.NewAddIndexMigration(instanceTable, instanceTable.Indices[0]),
+	)
+	mg.AddMigration(
+		"add index to alert_rule_template_instance on org_id and template_uid columns",
+		migrator.NewAddIndexMigration(instanceTable, instanceTable.Indices[1]),
+	)
+}
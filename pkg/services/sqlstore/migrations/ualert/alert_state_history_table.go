@@ -0,0 +1,53 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddAlertStateHistoryTable adds the table used by the SQL state history backend.
+func AddAlertStateHistoryTable(mg *migrator.Migrator) {
+	stateHistoryTable := migrator.Table{
+		Name: "alert_state_history",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "rule_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_title", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "namespace_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "rule_group", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "dashboard_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: true},
+			{Name: "panel_id", Type: migrator.DB_BigInt, Nullable: true},
+			{Name: "rule_condition", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "fingerprint", Type: migrator.DB_NVarchar, Length: 16, Nullable: false},
+			{Name: "labels", Type: migrator.DB_Text, Nullable: false},
+			{Name: "previous_state", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "previous_reason", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "state", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "reason", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "state_values", Type: migrator.DB_Text, Nullable: true},
+			{Name: "state_error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "epoch", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "epoch"}, Type: migrator.IndexType},
+			{Cols: []string{"org_id", "rule_uid", "epoch"}, Type: migrator.IndexType},
+			{Cols: []string{"epoch"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration(
+		"add alert_state_history table",
+		migrator.NewAddTableMigration(stateHistoryTable),
+	)
+	mg.AddMigration(
+		"add index to alert_state_history on org_id and epoch columns",
+		migrator.NewAddIndexMigration(stateHistoryTable, stateHistoryTable.Indices[0]),
+	)
+	mg.AddMigration(
+		"add index to alert_state_history on org_id, rule_uid and epoch columns",
+		migrator.NewAddIndexMigration(stateHistoryTable, stateHistoryTable.Indices[1]),
+	)
+	mg.AddMigration(
+		"add index to alert_state_history on epoch column",
+		migrator.NewAddIndexMigration(stateHistoryTable, stateHistoryTable.Indices[2]),
+	)
+}
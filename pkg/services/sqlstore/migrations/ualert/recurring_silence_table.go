@@ -0,0 +1,41 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddAlertRecurringSilenceTable adds the table that stores the definitions of recurring silences.
+func AddAlertRecurringSilenceTable(mg *migrator.Migrator) {
+	recurringSilenceTable := migrator.Table{
+		Name: "alert_recurring_silence",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "name", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "matchers", Type: migrator.DB_Text, Nullable: false},
+			{Name: "cron", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "duration", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "time_intervals", Type: migrator.DB_Text, Nullable: true},
+			{Name: "comment", Type: migrator.DB_Text, Nullable: true},
+			{Name: "created_by", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "version", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "name"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration(
+		"add alert_recurring_silence table",
+		migrator.NewAddTableMigration(recurringSilenceTable),
+	)
+	mg.AddMigration(
+		"add unique index to alert_recurring_silence on org_id and uid columns",
+		migrator.NewAddIndexMigration(recurringSilenceTable, recurringSilenceTable.Indices[0]),
+	)
+	mg.AddMigration(
+		"add unique index to alert_recurring_silence on org_id and name columns",
+		migrator.NewAddIndexMigration(recurringSilenceTable, recurringSilenceTable.Indices[1]),
+	)
+}
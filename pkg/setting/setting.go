@@ -438,6 +438,9 @@ type Cfg struct {
 	// SQLExpressionTimeoutSeconds is the duration a SQL expression will run before timing out
 	SQLExpressionTimeout time.Duration
 
+	// SQLExpressionMacros maps the signature of an admin-defined scalar macro, such as "ratio(num, den)", to the SQL expression it expands to.
+	SQLExpressionMacros map[string]string
+
 	ImageUploadProvider string
 
 	// LiveMaxConnections is a maximum number of WebSocket connections to
@@ -458,6 +461,9 @@ type Cfg struct {
 	// LiveMessageSizeLimit is the maximum size in bytes of Websocket messages
 	// from clients. Defaults to 64KB.
 	LiveMessageSizeLimit int
+	// LivePipelineEnabled enables processing of channel data with the Live
+	// pipeline rules stored in the database.
+	LivePipelineEnabled bool
 
 	// Grafana.com URL, used for OAuth redirect.
 	GrafanaComURL string
@@ -824,6 +830,11 @@ func (cfg *Cfg) readExpressionsSettings() {
 	cfg.SQLExpressionCellLimit = expressions.Key("sql_expression_cell_limit").MustInt64(100000)
 	cfg.SQLExpressionOutputCellLimit = expressions.Key("sql_expression_output_cell_limit").MustInt64(100000)
 	cfg.SQLExpressionTimeout = expressions.Key("sql_expression_timeout").MustDuration(time.Second * 10)
+
+	cfg.SQLExpressionMacros = map[string]string{}
+	for _, key := range cfg.Raw.Section("expressions.sql_macros").Keys() {
+		cfg.SQLExpressionMacros[key.Name()] = key.Value()
+	}
 }
 
 type AnnotationCleanupSettings struct {
@@ -2039,6 +2050,7 @@ func (cfg *Cfg) readLiveSettings(iniFile *ini.File) error {
 	cfg.LiveHAPrefix = section.Key("ha_prefix").MustString("")
 	cfg.LiveHAEngineAddress = section.Key("ha_engine_address").MustString("127.0.0.1:6379")
 	cfg.LiveHAEnginePassword = section.Key("ha_engine_password").MustString("")
+	cfg.LivePipelineEnabled = section.Key("pipeline_enabled").MustBool(false)
 
 	allowedOrigins := section.Key("allowed_origins").MustString("")
 	origins := strings.Split(allowedOrigins, ",")
@@ -70,6 +70,8 @@ const (
 	lokiDefaultMaxQuerySize                = 65536 // 64kb
 	defaultHistorianPrometheusWriteTimeout = 10 * time.Second
 	defaultHistorianPrometheusMetricName   = "GRAFANA_ALERTS"
+	defaultHistorianSQLRetention           = 30 * 24 * time.Hour
+	defaultHistorianSQLCleanupInterval     = time.Hour
 )
 
 var (
@@ -89,6 +91,7 @@ type UnifiedAlertingSettings struct {
 	HAGossipInterval                time.Duration
 	HAReconnectTimeout              time.Duration
 	HAPushPullInterval              time.Duration
+	HAEvaluationSharding            bool
 	HALabel                         string
 	HARedisClusterModeEnabled       bool
 	HARedisSentinelModeEnabled      bool
@@ -140,6 +143,9 @@ type UnifiedAlertingSettings struct {
 	// Duration for which a resolved alert state transition will continue to be sent to the Alertmanager.
 	ResolvedAlertRetention time.Duration
 
+	// RecurringSilencesLookahead is how far in advance the occurrences of recurring silences are created as silences.
+	RecurringSilencesLookahead time.Duration
+
 	// RuleVersionRecordLimit defines the limit of how many alert rule versions
 	// should be stored in the database for each alert_rule in an organization including the current one.
 	// 0 value means no limit
@@ -204,6 +210,8 @@ type UnifiedAlertingStateHistorySettings struct {
 	PrometheusMetricName          string
 	PrometheusTargetDatasourceUID string
 	PrometheusWriteTimeout        time.Duration
+	SQLRetention                  time.Duration
+	SQLCleanupInterval            time.Duration
 	MultiPrimary                  string
 	MultiSecondaries              []string
 	ExternalLabels                map[string]string
@@ -301,6 +309,7 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	uaCfg.HAListenAddr = ua.Key("ha_listen_address").MustString(alertmanagerDefaultClusterAddr)
 	uaCfg.HAAdvertiseAddr = ua.Key("ha_advertise_address").MustString("")
 	uaCfg.HALabel = ua.Key("ha_label").MustString("")
+	uaCfg.HAEvaluationSharding = ua.Key("ha_evaluation_sharding").MustBool(false)
 	uaCfg.HARedisClusterModeEnabled = ua.Key("ha_redis_cluster_mode_enabled").MustBool(false)
 	uaCfg.HARedisSentinelModeEnabled = ua.Key("ha_redis_sentinel_mode_enabled").MustBool(false)
 	if uaCfg.HARedisClusterModeEnabled && uaCfg.HARedisSentinelModeEnabled {
@@ -484,6 +493,17 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		PrometheusWriteTimeout:        stateHistory.Key("prometheus_write_timeout").MustDuration(defaultHistorianPrometheusWriteTimeout),
 		ExternalLabels:                stateHistoryLabels.KeysHash(),
 	}
+	uaCfgStateHistory.SQLRetention, err = gtime.ParseDuration(valueAsString(stateHistory, "sql_retention", (defaultHistorianSQLRetention).String()))
+	if err != nil {
+		return err
+	}
+	uaCfgStateHistory.SQLCleanupInterval, err = gtime.ParseDuration(valueAsString(stateHistory, "sql_cleanup_interval", (defaultHistorianSQLCleanupInterval).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfgStateHistory.SQLCleanupInterval <= 0 {
+		return fmt.Errorf("value of setting 'sql_cleanup_interval' should be greater than 0")
+	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
 	notificationHistory := iniFile.Section("unified_alerting.notification_history")
@@ -544,6 +564,14 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		return err
 	}
 
+	uaCfg.RecurringSilencesLookahead, err = gtime.ParseDuration(valueAsString(ua, "recurring_silences_lookahead", (24 * time.Hour).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.RecurringSilencesLookahead <= 0 {
+		return fmt.Errorf("setting 'recurring_silences_lookahead' is invalid, only a positive duration is allowed")
+	}
+
 	uaCfg.RuleVersionRecordLimit = ua.Key("rule_version_record_limit").MustInt(0)
 	if uaCfg.RuleVersionRecordLimit < 0 {
 		return fmt.Errorf("setting 'rule_version_record_limit' is invalid, only 0 or a positive integer are allowed")
@@ -0,0 +1,7 @@
+SELECT
+    {{ .Ident "value" | .Into .Response.Value }}
+  FROM {{ .Ident "resource_kv" }}
+  WHERE 1 = 1
+    AND {{ .Ident "section" }} = {{ .Arg .Section }}
+    AND {{ .Ident "key" }}     = {{ .Arg .Key }}
+;
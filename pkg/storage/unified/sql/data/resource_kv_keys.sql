@@ -0,0 +1,21 @@
+SELECT
+    {{ .Ident "key" | .Into .Response.Key }}
+  FROM {{ .Ident "resource_kv" }}
+  WHERE 1 = 1
+    AND {{ .Ident "section" }} = {{ .Arg .Section }}
+    {{ if .StartKey }}
+    AND {{ .Ident "key" }} >= {{ .Arg .StartKey }}
+    {{ end }}
+    {{ if .AfterKey }}
+    AND {{ .Ident "key" }} > {{ .Arg .AfterKey }}
+    {{ end }}
+    {{ if .EndKey }}
+    AND {{ .Ident "key" }} < {{ .Arg .EndKey }}
+    {{ end }}
+{{ if .SortDesc }}
+  ORDER BY {{ .Ident "key" }} DESC
+{{ else }}
+  ORDER BY {{ .Ident "key" }} ASC
+{{ end }}
+  LIMIT {{ .Arg .Limit }}
+;
@@ -0,0 +1 @@
+SELECT {{ .CurrentEpoch | .Into .Response.CurrentEpoch }};
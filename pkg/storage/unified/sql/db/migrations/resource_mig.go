@@ -167,5 +167,21 @@ func initResourceTables(mg *migrator.Migrator) string {
 		Name: "IDX_resource_history_namespace_group_resource_name_generation",
 	}))
 
+	// Key/value store used by the KV storage backend. Keys must be compared byte by
+	// byte to keep range scans consistent across databases: latin1_bin on MySQL,
+	// the "C" collation on Postgres and the default BINARY collation on SQLite.
+	resource_kv_table := migrator.Table{
+		Name: "resource_kv",
+		Columns: []*migrator.Column{
+			{Name: "section", Type: migrator.DB_NVarchar, Length: 190, Nullable: false, IsLatin: true},
+			{Name: "key", Type: migrator.DB_NVarchar, Length: 2048, Nullable: false, IsLatin: true},
+			{Name: "value", Type: migrator.DB_LongBlob, Nullable: false},
+		},
+		PrimaryKeys: []string{"section", "key"},
+	}
+	mg.AddMigration("create table resource_kv", migrator.NewAddTableMigration(resource_kv_table))
+	mg.AddMigration("Use binary collation for resource_kv keys", migrator.NewRawSQLMigration("").
+		Postgres(`ALTER TABLE resource_kv ALTER COLUMN section TYPE VARCHAR(190) COLLATE "C", ALTER COLUMN "key" TYPE VARCHAR(2048) COLLATE "C";`))
+
 	return marker
 }
@@ -0,0 +1,215 @@
+package sql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/db"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/dbutil"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/sqltemplate"
+)
+
+// kvKeysPageSize is the number of keys read from the database at once when
+// listing keys, so that no connection is held while the caller iterates.
+const kvKeysPageSize = 1000
+
+var _ resource.KV = (*sqlKV)(nil)
+
+// sqlKV implements resource.KV on top of the resource_kv table, so that the KV
+// storage backend can use the SQL database of Grafana. Keys are compared byte
+// by byte on all the supported databases, and the timestamps come from the
+// database server so that all the instances agree on the current time.
+type sqlKV struct {
+	db      db.DB
+	dialect sqltemplate.Dialect
+}
+
+// NewKV creates a resource.KV that stores its values in the given database.
+func NewKV(dbConn db.DB) (*sqlKV, error) {
+	if dbConn == nil {
+		return nil, errors.New("db is required")
+	}
+	dialect := sqltemplate.DialectForDriver(dbConn.DriverName())
+	if dialect == nil {
+		return nil, fmt.Errorf("no dialect for driver %q", dbConn.DriverName())
+	}
+	return &sqlKV{
+		db:      dbConn,
+		dialect: dialect,
+	}, nil
+}
+
+func (k *sqlKV) Get(ctx context.Context, section string, key string) (io.ReadCloser, error) {
+	if section == "" {
+		return nil, fmt.Errorf("section is required")
+	}
+
+	value, err := dbutil.QueryRow(ctx, k.db, sqlResourceKVGet, sqlKVGetRequest{
+		SQLTemplate: sqltemplate.New(k.dialect),
+		Section:     section,
+		Key:         key,
+		Response:    &kvResponse{},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, resource.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(bytes.NewReader(value)), nil
+}
+
+// sqlKVWriteCloser buffers the value and stores it when closed.
+type sqlKVWriteCloser struct {
+	ctx     context.Context
+	kv      *sqlKV
+	section string
+	key     string
+	buf     bytes.Buffer
+	closed  bool
+}
+
+// Write implements io.Writer
+func (w *sqlKVWriteCloser) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, fmt.Errorf("write to closed writer")
+	}
+	return w.buf.Write(p)
+}
+
+// Close implements io.Closer - stores the buffered data in the database
+func (w *sqlKVWriteCloser) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	value := w.buf.Bytes()
+	if value == nil {
+		// The value column is not nullable, empty values are stored as such.
+		value = []byte{}
+	}
+
+	return w.kv.db.WithTx(w.ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
+		_, err := dbutil.Exec(ctx, tx, sqlResourceKVSave, sqlKVSaveRequest{
+			SQLTemplate: sqltemplate.New(w.kv.dialect),
+			Section:     w.section,
+			Key:         w.key,
+			Value:       value,
+		})
+		return err
+	})
+}
+
+func (k *sqlKV) Save(ctx context.Context, section string, key string) (io.WriteCloser, error) {
+	if section == "" {
+		return nil, fmt.Errorf("section is required")
+	}
+
+	if key == "" {
+		return nil, fmt.Errorf("key is required")
+	}
+
+	return &sqlKVWriteCloser{
+		ctx:     ctx,
+		kv:      k,
+		section: section,
+		key:     key,
+	}, nil
+}
+
+func (k *sqlKV) Delete(ctx context.Context, section string, key string) error {
+	if section == "" {
+		return fmt.Errorf("section is required")
+	}
+
+	var deleted int64
+	err := k.db.WithTx(ctx, ReadCommitted, func(ctx context.Context, tx db.Tx) error {
+		res, err := dbutil.Exec(ctx, tx, sqlResourceKVDelete, sqlKVDeleteRequest{
+			SQLTemplate: sqltemplate.New(k.dialect),
+			Section:     section,
+			Key:         key,
+		})
+		if err != nil {
+			return err
+		}
+		deleted, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return resource.ErrNotFound
+	}
+	return nil
+}
+
+func (k *sqlKV) Keys(ctx context.Context, section string, opt resource.ListOptions) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		if section == "" {
+			yield("", fmt.Errorf("section is required"))
+			return
+		}
+
+		req := sqlKVKeysRequest{
+			Section:  section,
+			StartKey: opt.StartKey,
+			EndKey:   opt.EndKey,
+			SortDesc: opt.Sort == resource.SortOrderDesc,
+		}
+		remaining := opt.Limit
+		for {
+			limit := int64(kvKeysPageSize)
+			if opt.Limit > 0 && remaining < limit {
+				limit = remaining
+			}
+			req.SQLTemplate = sqltemplate.New(k.dialect)
+			req.Limit = limit
+			req.Response = &kvResponse{}
+
+			keys, err := dbutil.Query(ctx, k.db, sqlResourceKVKeys, req)
+			if err != nil {
+				yield("", err)
+				return
+			}
+			for _, key := range keys {
+				if !yield(key, nil) {
+					return
+				}
+			}
+
+			remaining -= int64(len(keys))
+			if int64(len(keys)) < limit || (opt.Limit > 0 && remaining <= 0) {
+				return
+			}
+
+			// Continue after the last key of the page.
+			last := keys[len(keys)-1]
+			if req.SortDesc {
+				req.EndKey = last
+			} else {
+				req.AfterKey = last
+			}
+		}
+	}
+}
+
+func (k *sqlKV) UnixTimestamp(ctx context.Context) (int64, error) {
+	epoch, err := dbutil.QueryRow(ctx, k.db, sqlResourceKVTimestamp, sqlKVTimestampRequest{
+		SQLTemplate: sqltemplate.New(k.dialect),
+		Response:    &kvResponse{},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("get current time from database: %w", err)
+	}
+	// The database epoch is in microseconds.
+	return epoch / 1_000_000, nil
+}
@@ -57,6 +57,12 @@ var (
 
 	sqlResourceBlobInsert = mustTemplate("resource_blob_insert.sql")
 	sqlResourceBlobQuery  = mustTemplate("resource_blob_query.sql")
+
+	sqlResourceKVGet       = mustTemplate("resource_kv_get.sql")
+	sqlResourceKVKeys      = mustTemplate("resource_kv_keys.sql")
+	sqlResourceKVSave      = mustTemplate("resource_kv_save.sql")
+	sqlResourceKVDelete    = mustTemplate("resource_kv_delete.sql")
+	sqlResourceKVTimestamp = mustTemplate("resource_kv_timestamp.sql")
 )
 
 // TxOptions.
@@ -428,3 +434,102 @@ func (r *sqlResourceVersionListRequest) Results() (*groupResourceVersion, error)
 	x := *r.groupResourceVersion
 	return &x, nil
 }
+
+// resource_kv table requests.
+type kvResponse struct {
+	Key          string
+	Value        []byte
+	CurrentEpoch int64
+}
+
+type sqlKVGetRequest struct {
+	sqltemplate.SQLTemplate
+	Section, Key string
+	Response     *kvResponse
+}
+
+func (r sqlKVGetRequest) Validate() error {
+	if r.Section == "" {
+		return fmt.Errorf("missing section")
+	}
+	if r.Key == "" {
+		return fmt.Errorf("missing key")
+	}
+	return nil
+}
+
+func (r sqlKVGetRequest) Results() ([]byte, error) {
+	return r.Response.Value, nil
+}
+
+type sqlKVKeysRequest struct {
+	sqltemplate.SQLTemplate
+	Section  string
+	StartKey string // inclusive lower bound
+	AfterKey string // exclusive lower bound, used to fetch the next page in ascending order
+	EndKey   string // exclusive upper bound
+	SortDesc bool
+	Limit    int64
+	Response *kvResponse
+}
+
+func (r sqlKVKeysRequest) Validate() error {
+	if r.Section == "" {
+		return fmt.Errorf("missing section")
+	}
+	if r.Limit <= 0 {
+		return fmt.Errorf("limit must be greater than zero")
+	}
+	return nil
+}
+
+func (r sqlKVKeysRequest) Results() (string, error) {
+	return r.Response.Key, nil
+}
+
+type sqlKVSaveRequest struct {
+	sqltemplate.SQLTemplate
+	Section, Key string
+	Value        []byte
+}
+
+func (r sqlKVSaveRequest) Validate() error {
+	if r.Section == "" {
+		return fmt.Errorf("missing section")
+	}
+	if r.Key == "" {
+		return fmt.Errorf("missing key")
+	}
+	if r.Value == nil {
+		return fmt.Errorf("missing value")
+	}
+	return nil
+}
+
+type sqlKVDeleteRequest struct {
+	sqltemplate.SQLTemplate
+	Section, Key string
+}
+
+func (r sqlKVDeleteRequest) Validate() error {
+	if r.Section == "" {
+		return fmt.Errorf("missing section")
+	}
+	if r.Key == "" {
+		return fmt.Errorf("missing key")
+	}
+	return nil
+}
+
+type sqlKVTimestampRequest struct {
+	sqltemplate.SQLTemplate
+	Response *kvResponse
+}
+
+func (r sqlKVTimestampRequest) Validate() error {
+	return nil
+}
+
+func (r sqlKVTimestampRequest) Results() (int64, error) {
+	return r.Response.CurrentEpoch, nil
+}
@@ -466,5 +466,81 @@ func TestUnifiedStorageQueries(t *testing.T) {
 					},
 				},
 			},
+			sqlResourceKVGet: {
+				{
+					Name: "simple",
+					Data: &sqlKVGetRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Section:     "unified/data",
+						Key:         "default/dashboard.grafana.app/dashboards/name",
+						Response:    &kvResponse{},
+					},
+				},
+			},
+			sqlResourceKVKeys: {
+				{
+					Name: "section",
+					Data: &sqlKVKeysRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Section:     "unified/data",
+						Limit:       1000,
+						Response:    &kvResponse{},
+					},
+				},
+				{
+					Name: "range",
+					Data: &sqlKVKeysRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Section:     "unified/data",
+						StartKey:    "default/",
+						EndKey:      "default0",
+						AfterKey:    "default/dashboard.grafana.app",
+						Limit:       10,
+						Response:    &kvResponse{},
+					},
+				},
+				{
+					Name: "descending",
+					Data: &sqlKVKeysRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Section:     "unified/data",
+						StartKey:    "default/",
+						EndKey:      "default0",
+						SortDesc:    true,
+						Limit:       10,
+						Response:    &kvResponse{},
+					},
+				},
+			},
+			sqlResourceKVSave: {
+				{
+					Name: "simple",
+					Data: &sqlKVSaveRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Section:     "unified/data",
+						Key:         "default/dashboard.grafana.app/dashboards/name",
+						Value:       []byte("{}"),
+					},
+				},
+			},
+			sqlResourceKVDelete: {
+				{
+					Name: "simple",
+					Data: &sqlKVDeleteRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Section:     "unified/data",
+						Key:         "default/dashboard.grafana.app/dashboards/name",
+					},
+				},
+			},
+			sqlResourceKVTimestamp: {
+				{
+					Name: "simple",
+					Data: &sqlKVTimestampRequest{
+						SQLTemplate: mocks.NewTestingSQLTemplate(),
+						Response:    &kvResponse{},
+					},
+				},
+			},
 		}})
 }
@@ -95,6 +95,39 @@ func TestIntegrationSQLStorageBackend(t *testing.T) {
 	})
 }
 
+func TestIntegrationSQLKV(t *testing.T) {
+	unitest.RunKVTest(t, func(ctx context.Context) resource.KV {
+		return newTestSQLKV(t)
+	}, &unitest.KVTestOptions{
+		NSPrefix: "sql-kv-test",
+	})
+}
+
+func TestIntegrationSQLKVStorageBackend(t *testing.T) {
+	unitest.RunStorageBackendTest(t, func(ctx context.Context) resource.StorageBackend {
+		return resource.NewKvStorageBackend(newTestSQLKV(t))
+	}, &unitest.TestOptions{
+		NSPrefix: "sql-kvstorage-test",
+		SkipTests: map[string]bool{
+			unitest.TestBlobSupport: true,
+		},
+	})
+}
+
+func newTestSQLKV(t *testing.T) resource.KV {
+	dbstore := db.InitTestDB(t)
+	eDB, err := dbimpl.ProvideResourceDB(dbstore, setting.NewCfg(), nil)
+	require.NoError(t, err)
+	require.NotNil(t, eDB)
+
+	dbConn, err := eDB.Init(testutil.NewDefaultTestContext(t))
+	require.NoError(t, err)
+
+	kv, err := sql.NewKV(dbConn)
+	require.NoError(t, err)
+	return kv
+}
+
 func TestIntegrationSearchAndStorage(t *testing.T) {
 	tests.SkipIntegrationTestInShortMode(t)
 
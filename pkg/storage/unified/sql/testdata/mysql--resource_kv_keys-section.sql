@@ -0,0 +1,8 @@
+SELECT
+    `key`
+  FROM `resource_kv`
+  WHERE 1 = 1
+    AND `section` = 'unified/data'
+  ORDER BY `key` ASC
+  LIMIT 1000
+;
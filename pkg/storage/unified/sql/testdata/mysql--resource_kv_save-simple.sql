@@ -0,0 +1,14 @@
+INSERT INTO `resource_kv`
+    (
+        `section`,
+        `key`,
+        `value`
+    )
+    VALUES (
+        'unified/data',
+        'default/dashboard.grafana.app/dashboards/name',
+        '[123 125]'
+    )
+    ON DUPLICATE KEY UPDATE
+        `value` = VALUES(`value`)
+;
@@ -0,0 +1,11 @@
+SELECT
+    "key"
+  FROM "resource_kv"
+  WHERE 1 = 1
+    AND "section" = 'unified/data'
+    AND "key" >= 'default/'
+    AND "key" > 'default/dashboard.grafana.app'
+    AND "key" < 'default0'
+  ORDER BY "key" ASC
+  LIMIT 10
+;
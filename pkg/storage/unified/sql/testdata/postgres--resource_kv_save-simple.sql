@@ -0,0 +1,14 @@
+INSERT INTO "resource_kv"
+    (
+        "section",
+        "key",
+        "value"
+    )
+    VALUES (
+        'unified/data',
+        'default/dashboard.grafana.app/dashboards/name',
+        '[123 125]'
+    )
+    ON CONFLICT ("section", "key") DO UPDATE SET
+        "value" = excluded."value"
+;
@@ -0,0 +1 @@
+SELECT (EXTRACT(EPOCH FROM statement_timestamp()) * 1000000)::BIGINT;
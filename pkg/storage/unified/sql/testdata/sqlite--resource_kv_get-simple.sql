@@ -0,0 +1,7 @@
+SELECT
+    "value"
+  FROM "resource_kv"
+  WHERE 1 = 1
+    AND "section" = 'unified/data'
+    AND "key"     = 'default/dashboard.grafana.app/dashboards/name'
+;
@@ -13,14 +13,18 @@ interface HistoryProps {
 }
 
 const History = ({ rule }: HistoryProps) => {
-  // can be "loki", "multiple" or "annotations"
+  // can be "loki", "prometheus", "sql", "multiple" or "annotations"
   const stateHistoryBackend = config.unifiedAlerting.alertStateHistoryBackend;
-  // can be "loki" or "annotations"
+  // can be "loki", "prometheus", "sql" or "annotations"
   const stateHistoryPrimary = config.unifiedAlerting.alertStateHistoryPrimary;
 
-  // if "loki" is either the backend or the primary, show the new state history implementation
+  // if "loki", "prometheus" or "sql" is either the backend or the primary, show the new state history implementation,
+  // they all return the same state history frame
   const usingNewAlertStateHistory = [stateHistoryBackend, stateHistoryPrimary].some(
-    (implementation) => implementation === StateHistoryImplementation.Loki
+    (implementation) =>
+      implementation === StateHistoryImplementation.Loki ||
+      implementation === StateHistoryImplementation.Prometheus ||
+      implementation === StateHistoryImplementation.SQL
   );
   const implementation = usingNewAlertStateHistory
     ? StateHistoryImplementation.Loki
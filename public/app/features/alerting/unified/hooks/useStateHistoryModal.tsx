@@ -12,6 +12,8 @@ const LokiStateHistory = lazy(() => import('../components/rules/state-history/Lo
 
 export enum StateHistoryImplementation {
   Loki = 'loki',
+  Prometheus = 'prometheus',
+  SQL = 'sql',
   Annotations = 'annotations',
 }
 
@@ -21,14 +23,18 @@ function useStateHistoryModal() {
 
   const styles = useStyles2(getStyles);
 
-  // can be "loki", "multiple" or "annotations"
+  // can be "loki", "prometheus", "sql", "multiple" or "annotations"
   const stateHistoryBackend = config.unifiedAlerting.alertStateHistoryBackend;
-  // can be "loki" or "annotations"
+  // can be "loki", "prometheus", "sql" or "annotations"
   const stateHistoryPrimary = config.unifiedAlerting.alertStateHistoryPrimary;
 
-  // if "loki" is either the backend or the primary, show the new state history implementation
+  // if "loki", "prometheus" or "sql" is either the backend or the primary, show the new state history implementation,
+  // they all return the same state history frame
   const usingNewAlertStateHistory = [stateHistoryBackend, stateHistoryPrimary].some(
-    (implementation) => implementation === StateHistoryImplementation.Loki
+    (implementation) =>
+      implementation === StateHistoryImplementation.Loki ||
+      implementation === StateHistoryImplementation.Prometheus ||
+      implementation === StateHistoryImplementation.SQL
   );
   const implementation = usingNewAlertStateHistory
     ? StateHistoryImplementation.Loki
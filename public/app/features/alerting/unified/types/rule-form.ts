@@ -65,6 +65,7 @@ export interface RuleFormValues {
   keepFiringForTimeUnit?: string;
   expression: string;
   missingSeriesEvalsToResolve?: number;
+  dependsOn?: string[];
 }
 
 export type Folder = { title: string; uid: string };
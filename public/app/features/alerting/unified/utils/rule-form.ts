@@ -154,6 +154,7 @@ export function formValuesToRulerGrafanaRuleDTO(values: RuleFormValues): Postabl
     metric,
     targetDatasourceUid,
     missingSeriesEvalsToResolve,
+    dependsOn,
   } = values;
   if (!condition) {
     throw new Error('You cannot create an alert rule without specifying the alert condition');
@@ -187,6 +188,7 @@ export function formValuesToRulerGrafanaRuleDTO(values: RuleFormValues): Postabl
           ? Number(missingSeriesEvalsToResolve)
           : // API uses 0 value to reset, as `missing_series_evals_to_resolve` cannot be 0
             0,
+        depends_on: dependsOn,
       },
       annotations,
       labels,
@@ -328,6 +330,7 @@ export function rulerRuleToFormValues(ruleWithLocation: RuleWithLocation): RuleF
           editorSettings: getEditorSettingsFromDTO(ga),
 
           missingSeriesEvalsToResolve: ga.missing_series_evals_to_resolve,
+          dependsOn: ga.depends_on,
         };
       } else {
         throw new Error('Unexpected type of rule for grafana rules source');
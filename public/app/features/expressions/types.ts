@@ -121,6 +121,8 @@ export const upsamplingTypes: Array<SelectableValue<string>> = [
   { value: 'pad', label: 'pad', description: 'fill with the last known value' },
   { value: 'backfilling', label: 'backfilling', description: 'fill with the next known value' },
   { value: 'fillna', label: 'fillna', description: 'Fill with NaNs' },
+  { value: 'linear', label: 'linear', description: 'interpolate between the last and the next known values' },
+  { value: 'nearest', label: 'nearest', description: 'fill with the closest known value' },
 ];
 
 export const thresholdFunctions: Array<SelectableValue<EvalFunction>> = [
@@ -173,6 +175,7 @@ export interface ClassicCondition {
     params: number[];
     type: EvalFunction;
   };
+  severity?: string;
   operator?: {
     type: string;
   };